@@ -223,6 +223,163 @@ func (x *GetBalanceResponse) GetError() string {
 	return ""
 }
 
+// 批量获取余额，单次最多100个地址
+type GetBalancesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Addresses     []string               `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalancesRequest) Reset() {
+	*x = GetBalancesRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalancesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesRequest) ProtoMessage() {}
+
+func (x *GetBalancesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesRequest.ProtoReflect.Descriptor instead.
+func (*GetBalancesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *GetBalancesRequest) GetAddresses() []string {
+	if x != nil {
+		return x.Addresses
+	}
+	return nil
+}
+
+type AccountBalance struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Balance       string                 `protobuf:"bytes,2,opt,name=balance,proto3" json:"balance,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AccountBalance) Reset() {
+	*x = AccountBalance{}
+	mi := &file_proto_chain_service_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AccountBalance) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AccountBalance) ProtoMessage() {}
+
+func (x *AccountBalance) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AccountBalance.ProtoReflect.Descriptor instead.
+func (*AccountBalance) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AccountBalance) GetAddress() string {
+	if x != nil {
+		return x.Address
+	}
+	return ""
+}
+
+func (x *AccountBalance) GetBalance() string {
+	if x != nil {
+		return x.Balance
+	}
+	return ""
+}
+
+type GetBalancesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Balances      []*AccountBalance      `protobuf:"bytes,1,rep,name=balances,proto3" json:"balances,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetBalancesResponse) Reset() {
+	*x = GetBalancesResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetBalancesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetBalancesResponse) ProtoMessage() {}
+
+func (x *GetBalancesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetBalancesResponse.ProtoReflect.Descriptor instead.
+func (*GetBalancesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *GetBalancesResponse) GetBalances() []*AccountBalance {
+	if x != nil {
+		return x.Balances
+	}
+	return nil
+}
+
+func (x *GetBalancesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetBalancesResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 // 转账
 type TransferRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -234,7 +391,7 @@ type TransferRequest struct {
 
 func (x *TransferRequest) Reset() {
 	*x = TransferRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[4]
+	mi := &file_proto_chain_service_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -246,7 +403,7 @@ func (x *TransferRequest) String() string {
 func (*TransferRequest) ProtoMessage() {}
 
 func (x *TransferRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[4]
+	mi := &file_proto_chain_service_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -259,7 +416,7 @@ func (x *TransferRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransferRequest.ProtoReflect.Descriptor instead.
 func (*TransferRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{4}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{7}
 }
 
 func (x *TransferRequest) GetTo() string {
@@ -287,7 +444,7 @@ type TransferResponse struct {
 
 func (x *TransferResponse) Reset() {
 	*x = TransferResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[5]
+	mi := &file_proto_chain_service_proto_msgTypes[8]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -299,7 +456,7 @@ func (x *TransferResponse) String() string {
 func (*TransferResponse) ProtoMessage() {}
 
 func (x *TransferResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[5]
+	mi := &file_proto_chain_service_proto_msgTypes[8]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -312,7 +469,7 @@ func (x *TransferResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TransferResponse.ProtoReflect.Descriptor instead.
 func (*TransferResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{5}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{8}
 }
 
 func (x *TransferResponse) GetTransactionHash() string {
@@ -346,7 +503,7 @@ type GetTransactionRequest struct {
 
 func (x *GetTransactionRequest) Reset() {
 	*x = GetTransactionRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[6]
+	mi := &file_proto_chain_service_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -358,7 +515,7 @@ func (x *GetTransactionRequest) String() string {
 func (*GetTransactionRequest) ProtoMessage() {}
 
 func (x *GetTransactionRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[6]
+	mi := &file_proto_chain_service_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -371,7 +528,7 @@ func (x *GetTransactionRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTransactionRequest.ProtoReflect.Descriptor instead.
 func (*GetTransactionRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{6}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{9}
 }
 
 func (x *GetTransactionRequest) GetHash() string {
@@ -399,7 +556,7 @@ type GetTransactionResponse struct {
 
 func (x *GetTransactionResponse) Reset() {
 	*x = GetTransactionResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[7]
+	mi := &file_proto_chain_service_proto_msgTypes[10]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -411,7 +568,7 @@ func (x *GetTransactionResponse) String() string {
 func (*GetTransactionResponse) ProtoMessage() {}
 
 func (x *GetTransactionResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[7]
+	mi := &file_proto_chain_service_proto_msgTypes[10]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -424,7 +581,7 @@ func (x *GetTransactionResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTransactionResponse.ProtoReflect.Descriptor instead.
 func (*GetTransactionResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{7}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{10}
 }
 
 func (x *GetTransactionResponse) GetHash() string {
@@ -509,7 +666,7 @@ type CallContractRequest struct {
 
 func (x *CallContractRequest) Reset() {
 	*x = CallContractRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[8]
+	mi := &file_proto_chain_service_proto_msgTypes[11]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -521,7 +678,7 @@ func (x *CallContractRequest) String() string {
 func (*CallContractRequest) ProtoMessage() {}
 
 func (x *CallContractRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[8]
+	mi := &file_proto_chain_service_proto_msgTypes[11]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -534,7 +691,7 @@ func (x *CallContractRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CallContractRequest.ProtoReflect.Descriptor instead.
 func (*CallContractRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{8}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{11}
 }
 
 func (x *CallContractRequest) GetContractAddress() string {
@@ -569,7 +726,7 @@ type CallContractResponse struct {
 
 func (x *CallContractResponse) Reset() {
 	*x = CallContractResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[9]
+	mi := &file_proto_chain_service_proto_msgTypes[12]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -581,7 +738,7 @@ func (x *CallContractResponse) String() string {
 func (*CallContractResponse) ProtoMessage() {}
 
 func (x *CallContractResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[9]
+	mi := &file_proto_chain_service_proto_msgTypes[12]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -594,7 +751,7 @@ func (x *CallContractResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use CallContractResponse.ProtoReflect.Descriptor instead.
 func (*CallContractResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{9}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{12}
 }
 
 func (x *CallContractResponse) GetResult() string {
@@ -629,7 +786,7 @@ type DeployContractRequest struct {
 
 func (x *DeployContractRequest) Reset() {
 	*x = DeployContractRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[10]
+	mi := &file_proto_chain_service_proto_msgTypes[13]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -641,7 +798,7 @@ func (x *DeployContractRequest) String() string {
 func (*DeployContractRequest) ProtoMessage() {}
 
 func (x *DeployContractRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[10]
+	mi := &file_proto_chain_service_proto_msgTypes[13]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -654,7 +811,7 @@ func (x *DeployContractRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeployContractRequest.ProtoReflect.Descriptor instead.
 func (*DeployContractRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{10}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{13}
 }
 
 func (x *DeployContractRequest) GetBytecode() string {
@@ -683,7 +840,7 @@ type DeployContractResponse struct {
 
 func (x *DeployContractResponse) Reset() {
 	*x = DeployContractResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[11]
+	mi := &file_proto_chain_service_proto_msgTypes[14]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -695,7 +852,7 @@ func (x *DeployContractResponse) String() string {
 func (*DeployContractResponse) ProtoMessage() {}
 
 func (x *DeployContractResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[11]
+	mi := &file_proto_chain_service_proto_msgTypes[14]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -708,7 +865,7 @@ func (x *DeployContractResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use DeployContractResponse.ProtoReflect.Descriptor instead.
 func (*DeployContractResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{11}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{14}
 }
 
 func (x *DeployContractResponse) GetContractAddress() string {
@@ -749,7 +906,7 @@ type GetTokenInfoRequest struct {
 
 func (x *GetTokenInfoRequest) Reset() {
 	*x = GetTokenInfoRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[12]
+	mi := &file_proto_chain_service_proto_msgTypes[15]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -761,7 +918,7 @@ func (x *GetTokenInfoRequest) String() string {
 func (*GetTokenInfoRequest) ProtoMessage() {}
 
 func (x *GetTokenInfoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[12]
+	mi := &file_proto_chain_service_proto_msgTypes[15]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -774,7 +931,7 @@ func (x *GetTokenInfoRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTokenInfoRequest.ProtoReflect.Descriptor instead.
 func (*GetTokenInfoRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{12}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{15}
 }
 
 func (x *GetTokenInfoRequest) GetAddress() string {
@@ -785,19 +942,24 @@ func (x *GetTokenInfoRequest) GetAddress() string {
 }
 
 type TokenInfo struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Symbol        string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Decimals      uint32                 `protobuf:"varint,4,opt,name=decimals,proto3" json:"decimals,omitempty"`
-	TotalSupply   string                 `protobuf:"bytes,5,opt,name=total_supply,json=totalSupply,proto3" json:"total_supply,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Address        string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Name           string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Symbol         string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Decimals       uint32                 `protobuf:"varint,4,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	TotalSupply    string                 `protobuf:"bytes,5,opt,name=total_supply,json=totalSupply,proto3" json:"total_supply,omitempty"`
+	LogoUri        string                 `protobuf:"bytes,6,opt,name=logo_uri,json=logoUri,proto3" json:"logo_uri,omitempty"`
+	Verified       bool                   `protobuf:"varint,7,opt,name=verified,proto3" json:"verified,omitempty"`                                    // 来自代币列表的代币为已验证
+	TotalSupplyRaw string                 `protobuf:"bytes,8,opt,name=total_supply_raw,json=totalSupplyRaw,proto3" json:"total_supply_raw,omitempty"` // 最小单位的总量
+	IsProxy        bool                   `protobuf:"varint,9,opt,name=is_proxy,json=isProxy,proto3" json:"is_proxy,omitempty"`                       // 是否为EIP-1967代理合约
+	Implementation string                 `protobuf:"bytes,10,opt,name=implementation,proto3" json:"implementation,omitempty"`                        // 代理合约的实现合约地址
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *TokenInfo) Reset() {
 	*x = TokenInfo{}
-	mi := &file_proto_chain_service_proto_msgTypes[13]
+	mi := &file_proto_chain_service_proto_msgTypes[16]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -809,7 +971,7 @@ func (x *TokenInfo) String() string {
 func (*TokenInfo) ProtoMessage() {}
 
 func (x *TokenInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[13]
+	mi := &file_proto_chain_service_proto_msgTypes[16]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -822,7 +984,7 @@ func (x *TokenInfo) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TokenInfo.ProtoReflect.Descriptor instead.
 func (*TokenInfo) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{13}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{16}
 }
 
 func (x *TokenInfo) GetAddress() string {
@@ -860,6 +1022,41 @@ func (x *TokenInfo) GetTotalSupply() string {
 	return ""
 }
 
+func (x *TokenInfo) GetLogoUri() string {
+	if x != nil {
+		return x.LogoUri
+	}
+	return ""
+}
+
+func (x *TokenInfo) GetVerified() bool {
+	if x != nil {
+		return x.Verified
+	}
+	return false
+}
+
+func (x *TokenInfo) GetTotalSupplyRaw() string {
+	if x != nil {
+		return x.TotalSupplyRaw
+	}
+	return ""
+}
+
+func (x *TokenInfo) GetIsProxy() bool {
+	if x != nil {
+		return x.IsProxy
+	}
+	return false
+}
+
+func (x *TokenInfo) GetImplementation() string {
+	if x != nil {
+		return x.Implementation
+	}
+	return ""
+}
+
 type GetTokenInfoResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Token         *TokenInfo             `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
@@ -871,7 +1068,7 @@ type GetTokenInfoResponse struct {
 
 func (x *GetTokenInfoResponse) Reset() {
 	*x = GetTokenInfoResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[14]
+	mi := &file_proto_chain_service_proto_msgTypes[17]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -883,7 +1080,7 @@ func (x *GetTokenInfoResponse) String() string {
 func (*GetTokenInfoResponse) ProtoMessage() {}
 
 func (x *GetTokenInfoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[14]
+	mi := &file_proto_chain_service_proto_msgTypes[17]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -896,7 +1093,7 @@ func (x *GetTokenInfoResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTokenInfoResponse.ProtoReflect.Descriptor instead.
 func (*GetTokenInfoResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{14}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{17}
 }
 
 func (x *GetTokenInfoResponse) GetToken() *TokenInfo {
@@ -923,14 +1120,17 @@ func (x *GetTokenInfoResponse) GetError() string {
 // 搜索代币
 type SearchTokenRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`                                      // 名称或符号
+	Prefix        bool                   `protobuf:"varint,2,opt,name=prefix,proto3" json:"prefix,omitempty"`                                 // 只匹配前缀，否则匹配任意位置并模糊匹配拼写相近的名称或符号
+	VerifiedOnly  bool                   `protobuf:"varint,3,opt,name=verified_only,json=verifiedOnly,proto3" json:"verified_only,omitempty"` // 只返回已验证的代币
+	Limit         uint32                 `protobuf:"varint,4,opt,name=limit,proto3" json:"limit,omitempty"`                                   // 默认20，最大100
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *SearchTokenRequest) Reset() {
 	*x = SearchTokenRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[15]
+	mi := &file_proto_chain_service_proto_msgTypes[18]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -942,7 +1142,7 @@ func (x *SearchTokenRequest) String() string {
 func (*SearchTokenRequest) ProtoMessage() {}
 
 func (x *SearchTokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[15]
+	mi := &file_proto_chain_service_proto_msgTypes[18]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -955,7 +1155,7 @@ func (x *SearchTokenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchTokenRequest.ProtoReflect.Descriptor instead.
 func (*SearchTokenRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{15}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{18}
 }
 
 func (x *SearchTokenRequest) GetName() string {
@@ -965,6 +1165,27 @@ func (x *SearchTokenRequest) GetName() string {
 	return ""
 }
 
+func (x *SearchTokenRequest) GetPrefix() bool {
+	if x != nil {
+		return x.Prefix
+	}
+	return false
+}
+
+func (x *SearchTokenRequest) GetVerifiedOnly() bool {
+	if x != nil {
+		return x.VerifiedOnly
+	}
+	return false
+}
+
+func (x *SearchTokenRequest) GetLimit() uint32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
 type SearchTokenResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Tokens        []*TokenInfo           `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`
@@ -976,7 +1197,7 @@ type SearchTokenResponse struct {
 
 func (x *SearchTokenResponse) Reset() {
 	*x = SearchTokenResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[16]
+	mi := &file_proto_chain_service_proto_msgTypes[19]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -988,7 +1209,7 @@ func (x *SearchTokenResponse) String() string {
 func (*SearchTokenResponse) ProtoMessage() {}
 
 func (x *SearchTokenResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[16]
+	mi := &file_proto_chain_service_proto_msgTypes[19]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1001,7 +1222,7 @@ func (x *SearchTokenResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use SearchTokenResponse.ProtoReflect.Descriptor instead.
 func (*SearchTokenResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{16}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{19}
 }
 
 func (x *SearchTokenResponse) GetTokens() []*TokenInfo {
@@ -1025,18 +1246,139 @@ func (x *SearchTokenResponse) GetError() string {
 	return ""
 }
 
-// 获取代币价格
-type GetTokenPriceRequest struct {
+// 导入Uniswap格式的代币列表
+type ImportTokenListRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	TokenName     string                 `protobuf:"bytes,2,opt,name=token_name,json=tokenName,proto3" json:"token_name,omitempty"`
+	Url           string                 `protobuf:"bytes,1,opt,name=url,proto3" json:"url,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTokenPriceRequest) Reset() {
-	*x = GetTokenPriceRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[17]
+func (x *ImportTokenListRequest) Reset() {
+	*x = ImportTokenListRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportTokenListRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportTokenListRequest) ProtoMessage() {}
+
+func (x *ImportTokenListRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportTokenListRequest.ProtoReflect.Descriptor instead.
+func (*ImportTokenListRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *ImportTokenListRequest) GetUrl() string {
+	if x != nil {
+		return x.Url
+	}
+	return ""
+}
+
+type ImportTokenListResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Imported      uint32                 `protobuf:"varint,2,opt,name=imported,proto3" json:"imported,omitempty"`
+	Skipped       uint32                 `protobuf:"varint,3,opt,name=skipped,proto3" json:"skipped,omitempty"`
+	Success       bool                   `protobuf:"varint,4,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ImportTokenListResponse) Reset() {
+	*x = ImportTokenListResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ImportTokenListResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ImportTokenListResponse) ProtoMessage() {}
+
+func (x *ImportTokenListResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ImportTokenListResponse.ProtoReflect.Descriptor instead.
+func (*ImportTokenListResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *ImportTokenListResponse) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ImportTokenListResponse) GetImported() uint32 {
+	if x != nil {
+		return x.Imported
+	}
+	return 0
+}
+
+func (x *ImportTokenListResponse) GetSkipped() uint32 {
+	if x != nil {
+		return x.Skipped
+	}
+	return 0
+}
+
+func (x *ImportTokenListResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *ImportTokenListResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// 获取代币价格
+type GetTokenPriceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	TokenName     string                 `protobuf:"bytes,2,opt,name=token_name,json=tokenName,proto3" json:"token_name,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTokenPriceRequest) Reset() {
+	*x = GetTokenPriceRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[22]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1048,7 +1390,7 @@ func (x *GetTokenPriceRequest) String() string {
 func (*GetTokenPriceRequest) ProtoMessage() {}
 
 func (x *GetTokenPriceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[17]
+	mi := &file_proto_chain_service_proto_msgTypes[22]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1061,7 +1403,7 @@ func (x *GetTokenPriceRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTokenPriceRequest.ProtoReflect.Descriptor instead.
 func (*GetTokenPriceRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{17}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{22}
 }
 
 func (x *GetTokenPriceRequest) GetAddress() string {
@@ -1079,19 +1421,29 @@ func (x *GetTokenPriceRequest) GetTokenName() string {
 }
 
 type TokenPrice struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Address       string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
-	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	Symbol        string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	PriceUsd      string                 `protobuf:"bytes,4,opt,name=price_usd,json=priceUsd,proto3" json:"price_usd,omitempty"`
-	PriceBnb      string                 `protobuf:"bytes,5,opt,name=price_bnb,json=priceBnb,proto3" json:"price_bnb,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Address         string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Symbol          string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	PriceUsd        string                 `protobuf:"bytes,4,opt,name=price_usd,json=priceUsd,proto3" json:"price_usd,omitempty"`
+	PriceBnb        string                 `protobuf:"bytes,5,opt,name=price_bnb,json=priceBnb,proto3" json:"price_bnb,omitempty"`
+	Decimals        uint32                 `protobuf:"varint,6,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	PriceUsdRaw     string                 `protobuf:"bytes,7,opt,name=price_usd_raw,json=priceUsdRaw,proto3" json:"price_usd_raw,omitempty"`           // 1个完整代币可兑换的USDT最小单位数量
+	PriceBnbRaw     string                 `protobuf:"bytes,8,opt,name=price_bnb_raw,json=priceBnbRaw,proto3" json:"price_bnb_raw,omitempty"`           // 1个完整代币可兑换的WBNB最小单位数量
+	Route           []string               `protobuf:"bytes,9,rep,name=route,proto3" json:"route,omitempty"`                                            // 计算价格使用的兑换路径
+	RoutePools      []string               `protobuf:"bytes,10,rep,name=route_pools,json=routePools,proto3" json:"route_pools,omitempty"`               // 路径每一跳使用的池子
+	Volume_24H      string                 `protobuf:"bytes,11,opt,name=volume_24h,json=volume24h,proto3" json:"volume_24h,omitempty"`                  // 24小时成交额（USD）
+	PriceChange_24H string                 `protobuf:"bytes,12,opt,name=price_change_24h,json=priceChange24h,proto3" json:"price_change_24h,omitempty"` // 24小时涨跌幅（百分比）
+	OraclePriceUsd  string                 `protobuf:"bytes,13,opt,name=oracle_price_usd,json=oraclePriceUsd,proto3" json:"oracle_price_usd,omitempty"` // 由Chainlink价格源得到的USD价格，价格源不可用时为空
+	PriceDeviation  string                 `protobuf:"bytes,14,opt,name=price_deviation,json=priceDeviation,proto3" json:"price_deviation,omitempty"`   // DEX价格相对预言机价格的偏差（百分比）
+	PriceDeviated   bool                   `protobuf:"varint,15,opt,name=price_deviated,json=priceDeviated,proto3" json:"price_deviated,omitempty"`     // 偏差是否超过阈值
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
 }
 
 func (x *TokenPrice) Reset() {
 	*x = TokenPrice{}
-	mi := &file_proto_chain_service_proto_msgTypes[18]
+	mi := &file_proto_chain_service_proto_msgTypes[23]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1103,7 +1455,7 @@ func (x *TokenPrice) String() string {
 func (*TokenPrice) ProtoMessage() {}
 
 func (x *TokenPrice) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[18]
+	mi := &file_proto_chain_service_proto_msgTypes[23]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1116,7 +1468,7 @@ func (x *TokenPrice) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TokenPrice.ProtoReflect.Descriptor instead.
 func (*TokenPrice) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{18}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{23}
 }
 
 func (x *TokenPrice) GetAddress() string {
@@ -1154,6 +1506,76 @@ func (x *TokenPrice) GetPriceBnb() string {
 	return ""
 }
 
+func (x *TokenPrice) GetDecimals() uint32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *TokenPrice) GetPriceUsdRaw() string {
+	if x != nil {
+		return x.PriceUsdRaw
+	}
+	return ""
+}
+
+func (x *TokenPrice) GetPriceBnbRaw() string {
+	if x != nil {
+		return x.PriceBnbRaw
+	}
+	return ""
+}
+
+func (x *TokenPrice) GetRoute() []string {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+func (x *TokenPrice) GetRoutePools() []string {
+	if x != nil {
+		return x.RoutePools
+	}
+	return nil
+}
+
+func (x *TokenPrice) GetVolume_24H() string {
+	if x != nil {
+		return x.Volume_24H
+	}
+	return ""
+}
+
+func (x *TokenPrice) GetPriceChange_24H() string {
+	if x != nil {
+		return x.PriceChange_24H
+	}
+	return ""
+}
+
+func (x *TokenPrice) GetOraclePriceUsd() string {
+	if x != nil {
+		return x.OraclePriceUsd
+	}
+	return ""
+}
+
+func (x *TokenPrice) GetPriceDeviation() string {
+	if x != nil {
+		return x.PriceDeviation
+	}
+	return ""
+}
+
+func (x *TokenPrice) GetPriceDeviated() bool {
+	if x != nil {
+		return x.PriceDeviated
+	}
+	return false
+}
+
 type GetTokenPriceResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	Price         *TokenPrice            `protobuf:"bytes,1,opt,name=price,proto3" json:"price,omitempty"`
@@ -1165,7 +1587,7 @@ type GetTokenPriceResponse struct {
 
 func (x *GetTokenPriceResponse) Reset() {
 	*x = GetTokenPriceResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[19]
+	mi := &file_proto_chain_service_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1177,7 +1599,7 @@ func (x *GetTokenPriceResponse) String() string {
 func (*GetTokenPriceResponse) ProtoMessage() {}
 
 func (x *GetTokenPriceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[19]
+	mi := &file_proto_chain_service_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1190,7 +1612,7 @@ func (x *GetTokenPriceResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetTokenPriceResponse.ProtoReflect.Descriptor instead.
 func (*GetTokenPriceResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{19}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{24}
 }
 
 func (x *GetTokenPriceResponse) GetPrice() *TokenPrice {
@@ -1225,7 +1647,7 @@ type TokenRequest struct {
 
 func (x *TokenRequest) Reset() {
 	*x = TokenRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[20]
+	mi := &file_proto_chain_service_proto_msgTypes[25]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1237,7 +1659,7 @@ func (x *TokenRequest) String() string {
 func (*TokenRequest) ProtoMessage() {}
 
 func (x *TokenRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[20]
+	mi := &file_proto_chain_service_proto_msgTypes[25]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1250,7 +1672,7 @@ func (x *TokenRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use TokenRequest.ProtoReflect.Descriptor instead.
 func (*TokenRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{20}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{25}
 }
 
 func (x *TokenRequest) GetAddress() string {
@@ -1276,7 +1698,7 @@ type GetMultipleTokenPricesRequest struct {
 
 func (x *GetMultipleTokenPricesRequest) Reset() {
 	*x = GetMultipleTokenPricesRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[21]
+	mi := &file_proto_chain_service_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1288,7 +1710,7 @@ func (x *GetMultipleTokenPricesRequest) String() string {
 func (*GetMultipleTokenPricesRequest) ProtoMessage() {}
 
 func (x *GetMultipleTokenPricesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[21]
+	mi := &file_proto_chain_service_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1301,7 +1723,7 @@ func (x *GetMultipleTokenPricesRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMultipleTokenPricesRequest.ProtoReflect.Descriptor instead.
 func (*GetMultipleTokenPricesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{21}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{26}
 }
 
 func (x *GetMultipleTokenPricesRequest) GetTokens() []*TokenRequest {
@@ -1322,7 +1744,7 @@ type GetMultipleTokenPricesResponse struct {
 
 func (x *GetMultipleTokenPricesResponse) Reset() {
 	*x = GetMultipleTokenPricesResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[22]
+	mi := &file_proto_chain_service_proto_msgTypes[27]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1334,7 +1756,7 @@ func (x *GetMultipleTokenPricesResponse) String() string {
 func (*GetMultipleTokenPricesResponse) ProtoMessage() {}
 
 func (x *GetMultipleTokenPricesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[22]
+	mi := &file_proto_chain_service_proto_msgTypes[27]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1347,7 +1769,7 @@ func (x *GetMultipleTokenPricesResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetMultipleTokenPricesResponse.ProtoReflect.Descriptor instead.
 func (*GetMultipleTokenPricesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{22}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{27}
 }
 
 func (x *GetMultipleTokenPricesResponse) GetPrices() []*TokenPrice {
@@ -1382,7 +1804,7 @@ type GetLiquidityPoolRequest struct {
 
 func (x *GetLiquidityPoolRequest) Reset() {
 	*x = GetLiquidityPoolRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[23]
+	mi := &file_proto_chain_service_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1394,7 +1816,7 @@ func (x *GetLiquidityPoolRequest) String() string {
 func (*GetLiquidityPoolRequest) ProtoMessage() {}
 
 func (x *GetLiquidityPoolRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[23]
+	mi := &file_proto_chain_service_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1407,7 +1829,7 @@ func (x *GetLiquidityPoolRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use GetLiquidityPoolRequest.ProtoReflect.Descriptor instead.
 func (*GetLiquidityPoolRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{23}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{28}
 }
 
 func (x *GetLiquidityPoolRequest) GetToken0() string {
@@ -1425,18 +1847,25 @@ func (x *GetLiquidityPoolRequest) GetToken1() string {
 }
 
 type LiquidityPool struct {
-	state          protoimpl.MessageState `protogen:"open.v1"`
-	PairAddress    string                 `protobuf:"bytes,1,opt,name=pair_address,json=pairAddress,proto3" json:"pair_address,omitempty"`
-	Token0         string                 `protobuf:"bytes,2,opt,name=token0,proto3" json:"token0,omitempty"`
-	Token1         string                 `protobuf:"bytes,3,opt,name=token1,proto3" json:"token1,omitempty"`
-	TotalLiquidity string                 `protobuf:"bytes,4,opt,name=total_liquidity,json=totalLiquidity,proto3" json:"total_liquidity,omitempty"`
-	unknownFields  protoimpl.UnknownFields
-	sizeCache      protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	PairAddress      string                 `protobuf:"bytes,1,opt,name=pair_address,json=pairAddress,proto3" json:"pair_address,omitempty"`
+	Token0           string                 `protobuf:"bytes,2,opt,name=token0,proto3" json:"token0,omitempty"`                                       // 交易对排序后的token0，与reserve0对应
+	Token1           string                 `protobuf:"bytes,3,opt,name=token1,proto3" json:"token1,omitempty"`                                       // 交易对排序后的token1，与reserve1对应
+	TotalLiquidity   string                 `protobuf:"bytes,4,opt,name=total_liquidity,json=totalLiquidity,proto3" json:"total_liquidity,omitempty"` // V2交易对和所有V3池合计的锁仓总价值（USD）
+	V3Pools          []*V3Pool              `protobuf:"bytes,5,rep,name=v3_pools,json=v3Pools,proto3" json:"v3_pools,omitempty"`
+	Reserve0         *PoolReserve           `protobuf:"bytes,6,opt,name=reserve0,proto3" json:"reserve0,omitempty"`
+	Reserve1         *PoolReserve           `protobuf:"bytes,7,opt,name=reserve1,proto3" json:"reserve1,omitempty"`
+	TvlUsd           string                 `protobuf:"bytes,8,opt,name=tvl_usd,json=tvlUsd,proto3" json:"tvl_usd,omitempty"` // V2交易对锁仓总价值（USD）
+	LpTotalSupply    string                 `protobuf:"bytes,9,opt,name=lp_total_supply,json=lpTotalSupply,proto3" json:"lp_total_supply,omitempty"`
+	LpTotalSupplyRaw string                 `protobuf:"bytes,10,opt,name=lp_total_supply_raw,json=lpTotalSupplyRaw,proto3" json:"lp_total_supply_raw,omitempty"`
+	LpTokenPriceUsd  string                 `protobuf:"bytes,11,opt,name=lp_token_price_usd,json=lpTokenPriceUsd,proto3" json:"lp_token_price_usd,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *LiquidityPool) Reset() {
 	*x = LiquidityPool{}
-	mi := &file_proto_chain_service_proto_msgTypes[24]
+	mi := &file_proto_chain_service_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -1448,7 +1877,7 @@ func (x *LiquidityPool) String() string {
 func (*LiquidityPool) ProtoMessage() {}
 
 func (x *LiquidityPool) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[24]
+	mi := &file_proto_chain_service_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1461,7 +1890,7 @@ func (x *LiquidityPool) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use LiquidityPool.ProtoReflect.Descriptor instead.
 func (*LiquidityPool) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{24}
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *LiquidityPool) GetPairAddress() string {
@@ -1492,129 +1921,3860 @@ func (x *LiquidityPool) GetTotalLiquidity() string {
 	return ""
 }
 
-// 价格服务消息
-type CryptoPriceInfo struct {
-	state                  protoimpl.MessageState `protogen:"open.v1"`
-	Symbol                 string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Name                   string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
-	CurrentPrice           float64                `protobuf:"fixed64,3,opt,name=current_price,json=currentPrice,proto3" json:"current_price,omitempty"`
-	MarketCap              float64                `protobuf:"fixed64,4,opt,name=market_cap,json=marketCap,proto3" json:"market_cap,omitempty"`
-	Volume_24H             float64                `protobuf:"fixed64,5,opt,name=volume_24h,json=volume24h,proto3" json:"volume_24h,omitempty"`
-	PriceChange_24H        float64                `protobuf:"fixed64,6,opt,name=price_change_24h,json=priceChange24h,proto3" json:"price_change_24h,omitempty"`
-	PriceChangePercent_24H float64                `protobuf:"fixed64,7,opt,name=price_change_percent_24h,json=priceChangePercent24h,proto3" json:"price_change_percent_24h,omitempty"`
-	LastUpdated            string                 `protobuf:"bytes,8,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
-	unknownFields          protoimpl.UnknownFields
-	sizeCache              protoimpl.SizeCache
-}
-
-func (x *CryptoPriceInfo) Reset() {
-	*x = CryptoPriceInfo{}
-	mi := &file_proto_chain_service_proto_msgTypes[25]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *LiquidityPool) GetV3Pools() []*V3Pool {
+	if x != nil {
+		return x.V3Pools
+	}
+	return nil
 }
 
-func (x *CryptoPriceInfo) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *LiquidityPool) GetReserve0() *PoolReserve {
+	if x != nil {
+		return x.Reserve0
+	}
+	return nil
 }
 
-func (*CryptoPriceInfo) ProtoMessage() {}
-
-func (x *CryptoPriceInfo) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[25]
+func (x *LiquidityPool) GetReserve1() *PoolReserve {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Reserve1
 	}
-	return mi.MessageOf(x)
+	return nil
 }
 
-// Deprecated: Use CryptoPriceInfo.ProtoReflect.Descriptor instead.
-func (*CryptoPriceInfo) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{25}
+func (x *LiquidityPool) GetTvlUsd() string {
+	if x != nil {
+		return x.TvlUsd
+	}
+	return ""
 }
 
-func (x *CryptoPriceInfo) GetSymbol() string {
+func (x *LiquidityPool) GetLpTotalSupply() string {
 	if x != nil {
-		return x.Symbol
+		return x.LpTotalSupply
 	}
 	return ""
 }
 
-func (x *CryptoPriceInfo) GetName() string {
+func (x *LiquidityPool) GetLpTotalSupplyRaw() string {
 	if x != nil {
-		return x.Name
+		return x.LpTotalSupplyRaw
+	}
+	return ""
+}
+
+func (x *LiquidityPool) GetLpTokenPriceUsd() string {
+	if x != nil {
+		return x.LpTokenPriceUsd
+	}
+	return ""
+}
+
+// 池子中单个代币的储备量
+type PoolReserve struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Symbol        string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Decimals      uint32                 `protobuf:"varint,3,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	Amount        string                 `protobuf:"bytes,4,opt,name=amount,proto3" json:"amount,omitempty"`
+	AmountRaw     string                 `protobuf:"bytes,5,opt,name=amount_raw,json=amountRaw,proto3" json:"amount_raw,omitempty"`
+	PriceUsd      string                 `protobuf:"bytes,6,opt,name=price_usd,json=priceUsd,proto3" json:"price_usd,omitempty"`
+	ValueUsd      string                 `protobuf:"bytes,7,opt,name=value_usd,json=valueUsd,proto3" json:"value_usd,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PoolReserve) Reset() {
+	*x = PoolReserve{}
+	mi := &file_proto_chain_service_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PoolReserve) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PoolReserve) ProtoMessage() {}
+
+func (x *PoolReserve) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PoolReserve.ProtoReflect.Descriptor instead.
+func (*PoolReserve) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *PoolReserve) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *PoolReserve) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *PoolReserve) GetDecimals() uint32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
+}
+
+func (x *PoolReserve) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *PoolReserve) GetAmountRaw() string {
+	if x != nil {
+		return x.AmountRaw
+	}
+	return ""
+}
+
+func (x *PoolReserve) GetPriceUsd() string {
+	if x != nil {
+		return x.PriceUsd
+	}
+	return ""
+}
+
+func (x *PoolReserve) GetValueUsd() string {
+	if x != nil {
+		return x.ValueUsd
+	}
+	return ""
+}
+
+// V3集中流动性池
+type V3Pool struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dex           string                 `protobuf:"bytes,1,opt,name=dex,proto3" json:"dex,omitempty"`
+	PoolAddress   string                 `protobuf:"bytes,2,opt,name=pool_address,json=poolAddress,proto3" json:"pool_address,omitempty"`
+	Token0        string                 `protobuf:"bytes,3,opt,name=token0,proto3" json:"token0,omitempty"`
+	Token1        string                 `protobuf:"bytes,4,opt,name=token1,proto3" json:"token1,omitempty"`
+	Fee           uint32                 `protobuf:"varint,5,opt,name=fee,proto3" json:"fee,omitempty"`
+	SqrtPriceX96  string                 `protobuf:"bytes,6,opt,name=sqrt_price_x96,json=sqrtPriceX96,proto3" json:"sqrt_price_x96,omitempty"`
+	Tick          int64                  `protobuf:"varint,7,opt,name=tick,proto3" json:"tick,omitempty"`
+	Liquidity     string                 `protobuf:"bytes,8,opt,name=liquidity,proto3" json:"liquidity,omitempty"`
+	Price         string                 `protobuf:"bytes,9,opt,name=price,proto3" json:"price,omitempty"`
+	Reserve0      string                 `protobuf:"bytes,10,opt,name=reserve0,proto3" json:"reserve0,omitempty"`
+	Reserve1      string                 `protobuf:"bytes,11,opt,name=reserve1,proto3" json:"reserve1,omitempty"`
+	TvlUsd        string                 `protobuf:"bytes,12,opt,name=tvl_usd,json=tvlUsd,proto3" json:"tvl_usd,omitempty"` // 池中代币余额的USD价值
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *V3Pool) Reset() {
+	*x = V3Pool{}
+	mi := &file_proto_chain_service_proto_msgTypes[31]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *V3Pool) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*V3Pool) ProtoMessage() {}
+
+func (x *V3Pool) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[31]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use V3Pool.ProtoReflect.Descriptor instead.
+func (*V3Pool) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{31}
+}
+
+func (x *V3Pool) GetDex() string {
+	if x != nil {
+		return x.Dex
+	}
+	return ""
+}
+
+func (x *V3Pool) GetPoolAddress() string {
+	if x != nil {
+		return x.PoolAddress
+	}
+	return ""
+}
+
+func (x *V3Pool) GetToken0() string {
+	if x != nil {
+		return x.Token0
+	}
+	return ""
+}
+
+func (x *V3Pool) GetToken1() string {
+	if x != nil {
+		return x.Token1
+	}
+	return ""
+}
+
+func (x *V3Pool) GetFee() uint32 {
+	if x != nil {
+		return x.Fee
+	}
+	return 0
+}
+
+func (x *V3Pool) GetSqrtPriceX96() string {
+	if x != nil {
+		return x.SqrtPriceX96
+	}
+	return ""
+}
+
+func (x *V3Pool) GetTick() int64 {
+	if x != nil {
+		return x.Tick
+	}
+	return 0
+}
+
+func (x *V3Pool) GetLiquidity() string {
+	if x != nil {
+		return x.Liquidity
+	}
+	return ""
+}
+
+func (x *V3Pool) GetPrice() string {
+	if x != nil {
+		return x.Price
+	}
+	return ""
+}
+
+func (x *V3Pool) GetReserve0() string {
+	if x != nil {
+		return x.Reserve0
+	}
+	return ""
+}
+
+func (x *V3Pool) GetReserve1() string {
+	if x != nil {
+		return x.Reserve1
+	}
+	return ""
+}
+
+func (x *V3Pool) GetTvlUsd() string {
+	if x != nil {
+		return x.TvlUsd
+	}
+	return ""
+}
+
+// 兑换报价
+type QuoteTradeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	TokenIn       string                 `protobuf:"bytes,1,opt,name=token_in,json=tokenIn,proto3" json:"token_in,omitempty"`
+	TokenOut      string                 `protobuf:"bytes,2,opt,name=token_out,json=tokenOut,proto3" json:"token_out,omitempty"`
+	Amount        string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"`                               // 按代币精度表示的输入数量
+	SlippageBps   uint32                 `protobuf:"varint,4,opt,name=slippage_bps,json=slippageBps,proto3" json:"slippage_bps,omitempty"` // 滑点容忍度（基点），0表示使用默认值
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuoteTradeRequest) Reset() {
+	*x = QuoteTradeRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[32]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteTradeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteTradeRequest) ProtoMessage() {}
+
+func (x *QuoteTradeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[32]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteTradeRequest.ProtoReflect.Descriptor instead.
+func (*QuoteTradeRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{32}
+}
+
+func (x *QuoteTradeRequest) GetTokenIn() string {
+	if x != nil {
+		return x.TokenIn
+	}
+	return ""
+}
+
+func (x *QuoteTradeRequest) GetTokenOut() string {
+	if x != nil {
+		return x.TokenOut
+	}
+	return ""
+}
+
+func (x *QuoteTradeRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *QuoteTradeRequest) GetSlippageBps() uint32 {
+	if x != nil {
+		return x.SlippageBps
+	}
+	return 0
+}
+
+type TradeQuote struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	TokenIn            string                 `protobuf:"bytes,1,opt,name=token_in,json=tokenIn,proto3" json:"token_in,omitempty"`
+	TokenOut           string                 `protobuf:"bytes,2,opt,name=token_out,json=tokenOut,proto3" json:"token_out,omitempty"`
+	AmountIn           string                 `protobuf:"bytes,3,opt,name=amount_in,json=amountIn,proto3" json:"amount_in,omitempty"`
+	AmountInRaw        string                 `protobuf:"bytes,4,opt,name=amount_in_raw,json=amountInRaw,proto3" json:"amount_in_raw,omitempty"`
+	AmountOut          string                 `protobuf:"bytes,5,opt,name=amount_out,json=amountOut,proto3" json:"amount_out,omitempty"`
+	AmountOutRaw       string                 `protobuf:"bytes,6,opt,name=amount_out_raw,json=amountOutRaw,proto3" json:"amount_out_raw,omitempty"`
+	ExecutionPrice     string                 `protobuf:"bytes,7,opt,name=execution_price,json=executionPrice,proto3" json:"execution_price,omitempty"`
+	SpotPrice          string                 `protobuf:"bytes,8,opt,name=spot_price,json=spotPrice,proto3" json:"spot_price,omitempty"`
+	PriceImpact        string                 `protobuf:"bytes,9,opt,name=price_impact,json=priceImpact,proto3" json:"price_impact,omitempty"` // 价格影响百分比
+	SlippageBps        uint32                 `protobuf:"varint,10,opt,name=slippage_bps,json=slippageBps,proto3" json:"slippage_bps,omitempty"`
+	MinimumReceived    string                 `protobuf:"bytes,11,opt,name=minimum_received,json=minimumReceived,proto3" json:"minimum_received,omitempty"`
+	MinimumReceivedRaw string                 `protobuf:"bytes,12,opt,name=minimum_received_raw,json=minimumReceivedRaw,proto3" json:"minimum_received_raw,omitempty"`
+	Route              []string               `protobuf:"bytes,13,rep,name=route,proto3" json:"route,omitempty"`
+	RoutePools         []string               `protobuf:"bytes,14,rep,name=route_pools,json=routePools,proto3" json:"route_pools,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TradeQuote) Reset() {
+	*x = TradeQuote{}
+	mi := &file_proto_chain_service_proto_msgTypes[33]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TradeQuote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TradeQuote) ProtoMessage() {}
+
+func (x *TradeQuote) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[33]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TradeQuote.ProtoReflect.Descriptor instead.
+func (*TradeQuote) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{33}
+}
+
+func (x *TradeQuote) GetTokenIn() string {
+	if x != nil {
+		return x.TokenIn
+	}
+	return ""
+}
+
+func (x *TradeQuote) GetTokenOut() string {
+	if x != nil {
+		return x.TokenOut
+	}
+	return ""
+}
+
+func (x *TradeQuote) GetAmountIn() string {
+	if x != nil {
+		return x.AmountIn
+	}
+	return ""
+}
+
+func (x *TradeQuote) GetAmountInRaw() string {
+	if x != nil {
+		return x.AmountInRaw
+	}
+	return ""
+}
+
+func (x *TradeQuote) GetAmountOut() string {
+	if x != nil {
+		return x.AmountOut
+	}
+	return ""
+}
+
+func (x *TradeQuote) GetAmountOutRaw() string {
+	if x != nil {
+		return x.AmountOutRaw
+	}
+	return ""
+}
+
+func (x *TradeQuote) GetExecutionPrice() string {
+	if x != nil {
+		return x.ExecutionPrice
+	}
+	return ""
+}
+
+func (x *TradeQuote) GetSpotPrice() string {
+	if x != nil {
+		return x.SpotPrice
+	}
+	return ""
+}
+
+func (x *TradeQuote) GetPriceImpact() string {
+	if x != nil {
+		return x.PriceImpact
+	}
+	return ""
+}
+
+func (x *TradeQuote) GetSlippageBps() uint32 {
+	if x != nil {
+		return x.SlippageBps
+	}
+	return 0
+}
+
+func (x *TradeQuote) GetMinimumReceived() string {
+	if x != nil {
+		return x.MinimumReceived
+	}
+	return ""
+}
+
+func (x *TradeQuote) GetMinimumReceivedRaw() string {
+	if x != nil {
+		return x.MinimumReceivedRaw
+	}
+	return ""
+}
+
+func (x *TradeQuote) GetRoute() []string {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+func (x *TradeQuote) GetRoutePools() []string {
+	if x != nil {
+		return x.RoutePools
+	}
+	return nil
+}
+
+type QuoteTradeResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Quote         *TradeQuote            `protobuf:"bytes,1,opt,name=quote,proto3" json:"quote,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *QuoteTradeResponse) Reset() {
+	*x = QuoteTradeResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[34]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *QuoteTradeResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*QuoteTradeResponse) ProtoMessage() {}
+
+func (x *QuoteTradeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[34]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use QuoteTradeResponse.ProtoReflect.Descriptor instead.
+func (*QuoteTradeResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{34}
+}
+
+func (x *QuoteTradeResponse) GetQuote() *TradeQuote {
+	if x != nil {
+		return x.Quote
+	}
+	return nil
+}
+
+func (x *QuoteTradeResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *QuoteTradeResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// 执行兑换
+type SwapRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TokenIn         string                 `protobuf:"bytes,1,opt,name=token_in,json=tokenIn,proto3" json:"token_in,omitempty"` // 代币地址，BNB表示原生BNB
+	TokenOut        string                 `protobuf:"bytes,2,opt,name=token_out,json=tokenOut,proto3" json:"token_out,omitempty"`
+	Amount          string                 `protobuf:"bytes,3,opt,name=amount,proto3" json:"amount,omitempty"` // 精确输入时为输入数量，精确输出时为输出数量
+	ExactOut        bool                   `protobuf:"varint,4,opt,name=exact_out,json=exactOut,proto3" json:"exact_out,omitempty"`
+	SlippageBps     uint32                 `protobuf:"varint,5,opt,name=slippage_bps,json=slippageBps,proto3" json:"slippage_bps,omitempty"`
+	DeadlineSeconds int64                  `protobuf:"varint,6,opt,name=deadline_seconds,json=deadlineSeconds,proto3" json:"deadline_seconds,omitempty"` // 交易有效期，0表示默认20分钟
+	Recipient       string                 `protobuf:"bytes,7,opt,name=recipient,proto3" json:"recipient,omitempty"`                                     // 接收地址，为空时为签名账户，其他地址需在 bsc.swap_recipients 中
+	FeeOnTransfer   bool                   `protobuf:"varint,8,opt,name=fee_on_transfer,json=feeOnTransfer,proto3" json:"fee_on_transfer,omitempty"`     // 使用支持转账扣费代币的方法
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SwapRequest) Reset() {
+	*x = SwapRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[35]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwapRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwapRequest) ProtoMessage() {}
+
+func (x *SwapRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[35]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwapRequest.ProtoReflect.Descriptor instead.
+func (*SwapRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{35}
+}
+
+func (x *SwapRequest) GetTokenIn() string {
+	if x != nil {
+		return x.TokenIn
+	}
+	return ""
+}
+
+func (x *SwapRequest) GetTokenOut() string {
+	if x != nil {
+		return x.TokenOut
+	}
+	return ""
+}
+
+func (x *SwapRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *SwapRequest) GetExactOut() bool {
+	if x != nil {
+		return x.ExactOut
+	}
+	return false
+}
+
+func (x *SwapRequest) GetSlippageBps() uint32 {
+	if x != nil {
+		return x.SlippageBps
+	}
+	return 0
+}
+
+func (x *SwapRequest) GetDeadlineSeconds() int64 {
+	if x != nil {
+		return x.DeadlineSeconds
+	}
+	return 0
+}
+
+func (x *SwapRequest) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *SwapRequest) GetFeeOnTransfer() bool {
+	if x != nil {
+		return x.FeeOnTransfer
+	}
+	return false
+}
+
+type SwapResult struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	TxHash          string                 `protobuf:"bytes,1,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	ApproveTxHash   string                 `protobuf:"bytes,2,opt,name=approve_tx_hash,json=approveTxHash,proto3" json:"approve_tx_hash,omitempty"`
+	Method          string                 `protobuf:"bytes,3,opt,name=method,proto3" json:"method,omitempty"`
+	BlockNumber     uint64                 `protobuf:"varint,4,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	GasUsed         uint64                 `protobuf:"varint,5,opt,name=gas_used,json=gasUsed,proto3" json:"gas_used,omitempty"`
+	TokenIn         string                 `protobuf:"bytes,6,opt,name=token_in,json=tokenIn,proto3" json:"token_in,omitempty"`
+	TokenOut        string                 `protobuf:"bytes,7,opt,name=token_out,json=tokenOut,proto3" json:"token_out,omitempty"`
+	Recipient       string                 `protobuf:"bytes,8,opt,name=recipient,proto3" json:"recipient,omitempty"`
+	Route           []string               `protobuf:"bytes,9,rep,name=route,proto3" json:"route,omitempty"`
+	AmountIn        string                 `protobuf:"bytes,10,opt,name=amount_in,json=amountIn,proto3" json:"amount_in,omitempty"`
+	AmountInRaw     string                 `protobuf:"bytes,11,opt,name=amount_in_raw,json=amountInRaw,proto3" json:"amount_in_raw,omitempty"`
+	AmountOut       string                 `protobuf:"bytes,12,opt,name=amount_out,json=amountOut,proto3" json:"amount_out,omitempty"`
+	AmountOutRaw    string                 `protobuf:"bytes,13,opt,name=amount_out_raw,json=amountOutRaw,proto3" json:"amount_out_raw,omitempty"`
+	AmountOutMinRaw string                 `protobuf:"bytes,14,opt,name=amount_out_min_raw,json=amountOutMinRaw,proto3" json:"amount_out_min_raw,omitempty"`
+	AmountInMaxRaw  string                 `protobuf:"bytes,15,opt,name=amount_in_max_raw,json=amountInMaxRaw,proto3" json:"amount_in_max_raw,omitempty"`
+	SlippageBps     uint32                 `protobuf:"varint,16,opt,name=slippage_bps,json=slippageBps,proto3" json:"slippage_bps,omitempty"`
+	Deadline        int64                  `protobuf:"varint,17,opt,name=deadline,proto3" json:"deadline,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *SwapResult) Reset() {
+	*x = SwapResult{}
+	mi := &file_proto_chain_service_proto_msgTypes[36]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwapResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwapResult) ProtoMessage() {}
+
+func (x *SwapResult) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[36]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwapResult.ProtoReflect.Descriptor instead.
+func (*SwapResult) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{36}
+}
+
+func (x *SwapResult) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+func (x *SwapResult) GetApproveTxHash() string {
+	if x != nil {
+		return x.ApproveTxHash
+	}
+	return ""
+}
+
+func (x *SwapResult) GetMethod() string {
+	if x != nil {
+		return x.Method
+	}
+	return ""
+}
+
+func (x *SwapResult) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *SwapResult) GetGasUsed() uint64 {
+	if x != nil {
+		return x.GasUsed
+	}
+	return 0
+}
+
+func (x *SwapResult) GetTokenIn() string {
+	if x != nil {
+		return x.TokenIn
+	}
+	return ""
+}
+
+func (x *SwapResult) GetTokenOut() string {
+	if x != nil {
+		return x.TokenOut
+	}
+	return ""
+}
+
+func (x *SwapResult) GetRecipient() string {
+	if x != nil {
+		return x.Recipient
+	}
+	return ""
+}
+
+func (x *SwapResult) GetRoute() []string {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+func (x *SwapResult) GetAmountIn() string {
+	if x != nil {
+		return x.AmountIn
+	}
+	return ""
+}
+
+func (x *SwapResult) GetAmountInRaw() string {
+	if x != nil {
+		return x.AmountInRaw
+	}
+	return ""
+}
+
+func (x *SwapResult) GetAmountOut() string {
+	if x != nil {
+		return x.AmountOut
+	}
+	return ""
+}
+
+func (x *SwapResult) GetAmountOutRaw() string {
+	if x != nil {
+		return x.AmountOutRaw
+	}
+	return ""
+}
+
+func (x *SwapResult) GetAmountOutMinRaw() string {
+	if x != nil {
+		return x.AmountOutMinRaw
+	}
+	return ""
+}
+
+func (x *SwapResult) GetAmountInMaxRaw() string {
+	if x != nil {
+		return x.AmountInMaxRaw
+	}
+	return ""
+}
+
+func (x *SwapResult) GetSlippageBps() uint32 {
+	if x != nil {
+		return x.SlippageBps
+	}
+	return 0
+}
+
+func (x *SwapResult) GetDeadline() int64 {
+	if x != nil {
+		return x.Deadline
+	}
+	return 0
+}
+
+type SwapResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Result        *SwapResult            `protobuf:"bytes,1,opt,name=result,proto3" json:"result,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SwapResponse) Reset() {
+	*x = SwapResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[37]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SwapResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SwapResponse) ProtoMessage() {}
+
+func (x *SwapResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[37]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SwapResponse.ProtoReflect.Descriptor instead.
+func (*SwapResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{37}
+}
+
+func (x *SwapResponse) GetResult() *SwapResult {
+	if x != nil {
+		return x.Result
+	}
+	return nil
+}
+
+func (x *SwapResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SwapResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// 工厂合约PairCreated事件索引得到的交易对
+type DexPair struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Dex           string                 `protobuf:"bytes,1,opt,name=dex,proto3" json:"dex,omitempty"`
+	Factory       string                 `protobuf:"bytes,2,opt,name=factory,proto3" json:"factory,omitempty"`
+	PairAddress   string                 `protobuf:"bytes,3,opt,name=pair_address,json=pairAddress,proto3" json:"pair_address,omitempty"`
+	Token0        string                 `protobuf:"bytes,4,opt,name=token0,proto3" json:"token0,omitempty"`
+	Token1        string                 `protobuf:"bytes,5,opt,name=token1,proto3" json:"token1,omitempty"`
+	PairIndex     uint64                 `protobuf:"varint,6,opt,name=pair_index,json=pairIndex,proto3" json:"pair_index,omitempty"`
+	BlockNumber   uint64                 `protobuf:"varint,7,opt,name=block_number,json=blockNumber,proto3" json:"block_number,omitempty"`
+	BlockTime     int64                  `protobuf:"varint,8,opt,name=block_time,json=blockTime,proto3" json:"block_time,omitempty"` // Unix时间戳（秒）
+	TxHash        string                 `protobuf:"bytes,9,opt,name=tx_hash,json=txHash,proto3" json:"tx_hash,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DexPair) Reset() {
+	*x = DexPair{}
+	mi := &file_proto_chain_service_proto_msgTypes[38]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DexPair) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DexPair) ProtoMessage() {}
+
+func (x *DexPair) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[38]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DexPair.ProtoReflect.Descriptor instead.
+func (*DexPair) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{38}
+}
+
+func (x *DexPair) GetDex() string {
+	if x != nil {
+		return x.Dex
+	}
+	return ""
+}
+
+func (x *DexPair) GetFactory() string {
+	if x != nil {
+		return x.Factory
+	}
+	return ""
+}
+
+func (x *DexPair) GetPairAddress() string {
+	if x != nil {
+		return x.PairAddress
+	}
+	return ""
+}
+
+func (x *DexPair) GetToken0() string {
+	if x != nil {
+		return x.Token0
+	}
+	return ""
+}
+
+func (x *DexPair) GetToken1() string {
+	if x != nil {
+		return x.Token1
+	}
+	return ""
+}
+
+func (x *DexPair) GetPairIndex() uint64 {
+	if x != nil {
+		return x.PairIndex
+	}
+	return 0
+}
+
+func (x *DexPair) GetBlockNumber() uint64 {
+	if x != nil {
+		return x.BlockNumber
+	}
+	return 0
+}
+
+func (x *DexPair) GetBlockTime() int64 {
+	if x != nil {
+		return x.BlockTime
+	}
+	return 0
+}
+
+func (x *DexPair) GetTxHash() string {
+	if x != nil {
+		return x.TxHash
+	}
+	return ""
+}
+
+type GetTokenPairsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTokenPairsRequest) Reset() {
+	*x = GetTokenPairsRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[39]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTokenPairsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTokenPairsRequest) ProtoMessage() {}
+
+func (x *GetTokenPairsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[39]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTokenPairsRequest.ProtoReflect.Descriptor instead.
+func (*GetTokenPairsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{39}
+}
+
+func (x *GetTokenPairsRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+type GetTokenPairsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pairs         []*DexPair             `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTokenPairsResponse) Reset() {
+	*x = GetTokenPairsResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[40]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTokenPairsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTokenPairsResponse) ProtoMessage() {}
+
+func (x *GetTokenPairsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[40]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTokenPairsResponse.ProtoReflect.Descriptor instead.
+func (*GetTokenPairsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{40}
+}
+
+func (x *GetTokenPairsResponse) GetPairs() []*DexPair {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+func (x *GetTokenPairsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetTokenPairsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetRecentPairsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Minutes       uint32                 `protobuf:"varint,1,opt,name=minutes,proto3" json:"minutes,omitempty"` // 默认60，最大1440
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecentPairsRequest) Reset() {
+	*x = GetRecentPairsRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[41]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecentPairsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecentPairsRequest) ProtoMessage() {}
+
+func (x *GetRecentPairsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[41]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecentPairsRequest.ProtoReflect.Descriptor instead.
+func (*GetRecentPairsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{41}
+}
+
+func (x *GetRecentPairsRequest) GetMinutes() uint32 {
+	if x != nil {
+		return x.Minutes
+	}
+	return 0
+}
+
+type GetRecentPairsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pairs         []*DexPair             `protobuf:"bytes,1,rep,name=pairs,proto3" json:"pairs,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetRecentPairsResponse) Reset() {
+	*x = GetRecentPairsResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[42]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetRecentPairsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetRecentPairsResponse) ProtoMessage() {}
+
+func (x *GetRecentPairsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[42]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetRecentPairsResponse.ProtoReflect.Descriptor instead.
+func (*GetRecentPairsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{42}
+}
+
+func (x *GetRecentPairsResponse) GetPairs() []*DexPair {
+	if x != nil {
+		return x.Pairs
+	}
+	return nil
+}
+
+func (x *GetRecentPairsResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetRecentPairsResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// 新交易对订阅条件，为空表示不过滤
+type StreamNewPairsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // 只推送包含该代币的交易对
+	Dex           string                 `protobuf:"bytes,2,opt,name=dex,proto3" json:"dex,omitempty"`     // 只推送指定DEX的交易对
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *StreamNewPairsRequest) Reset() {
+	*x = StreamNewPairsRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[43]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *StreamNewPairsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StreamNewPairsRequest) ProtoMessage() {}
+
+func (x *StreamNewPairsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[43]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StreamNewPairsRequest.ProtoReflect.Descriptor instead.
+func (*StreamNewPairsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{43}
+}
+
+func (x *StreamNewPairsRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *StreamNewPairsRequest) GetDex() string {
+	if x != nil {
+		return x.Dex
+	}
+	return ""
+}
+
+type AnalyzeTokenRiskRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Amount        string                 `protobuf:"bytes,2,opt,name=amount,proto3" json:"amount,omitempty"` // 模拟买入的BNB数量，默认0.1
+	Abi           string                 `protobuf:"bytes,3,opt,name=abi,proto3" json:"abi,omitempty"`       // 合约ABI（JSON），为空时从字节码识别特权
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeTokenRiskRequest) Reset() {
+	*x = AnalyzeTokenRiskRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[44]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeTokenRiskRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeTokenRiskRequest) ProtoMessage() {}
+
+func (x *AnalyzeTokenRiskRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[44]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeTokenRiskRequest.ProtoReflect.Descriptor instead.
+func (*AnalyzeTokenRiskRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{44}
+}
+
+func (x *AnalyzeTokenRiskRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *AnalyzeTokenRiskRequest) GetAmount() string {
+	if x != nil {
+		return x.Amount
+	}
+	return ""
+}
+
+func (x *AnalyzeTokenRiskRequest) GetAbi() string {
+	if x != nil {
+		return x.Abi
+	}
+	return ""
+}
+
+// 代币风险分析报告，税率为百分比
+type TokenRiskReport struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Token              string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Name               string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Symbol             string                 `protobuf:"bytes,3,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Route              []string               `protobuf:"bytes,4,rep,name=route,proto3" json:"route,omitempty"`
+	BuyAmount          string                 `protobuf:"bytes,5,opt,name=buy_amount,json=buyAmount,proto3" json:"buy_amount,omitempty"`
+	IsHoneypot         bool                   `protobuf:"varint,6,opt,name=is_honeypot,json=isHoneypot,proto3" json:"is_honeypot,omitempty"`
+	CanBuy             bool                   `protobuf:"varint,7,opt,name=can_buy,json=canBuy,proto3" json:"can_buy,omitempty"`
+	CanSell            bool                   `protobuf:"varint,8,opt,name=can_sell,json=canSell,proto3" json:"can_sell,omitempty"`
+	BuyError           string                 `protobuf:"bytes,9,opt,name=buy_error,json=buyError,proto3" json:"buy_error,omitempty"`
+	SellError          string                 `protobuf:"bytes,10,opt,name=sell_error,json=sellError,proto3" json:"sell_error,omitempty"`
+	BuyTax             string                 `protobuf:"bytes,11,opt,name=buy_tax,json=buyTax,proto3" json:"buy_tax,omitempty"`
+	SellTax            string                 `protobuf:"bytes,12,opt,name=sell_tax,json=sellTax,proto3" json:"sell_tax,omitempty"`
+	TransferTax        string                 `protobuf:"bytes,13,opt,name=transfer_tax,json=transferTax,proto3" json:"transfer_tax,omitempty"`
+	BuyGas             uint64                 `protobuf:"varint,14,opt,name=buy_gas,json=buyGas,proto3" json:"buy_gas,omitempty"`
+	SellGas            uint64                 `protobuf:"varint,15,opt,name=sell_gas,json=sellGas,proto3" json:"sell_gas,omitempty"`
+	MaxTxAmount        string                 `protobuf:"bytes,16,opt,name=max_tx_amount,json=maxTxAmount,proto3" json:"max_tx_amount,omitempty"`
+	MaxWalletAmount    string                 `protobuf:"bytes,17,opt,name=max_wallet_amount,json=maxWalletAmount,proto3" json:"max_wallet_amount,omitempty"`
+	Owner              string                 `protobuf:"bytes,18,opt,name=owner,proto3" json:"owner,omitempty"`
+	OwnershipRenounced bool                   `protobuf:"varint,19,opt,name=ownership_renounced,json=ownershipRenounced,proto3" json:"ownership_renounced,omitempty"`
+	Privileges         []string               `protobuf:"bytes,20,rep,name=privileges,proto3" json:"privileges,omitempty"`
+	PrivilegeSource    string                 `protobuf:"bytes,21,opt,name=privilege_source,json=privilegeSource,proto3" json:"privilege_source,omitempty"` // abi 或 bytecode
+	RiskLevel          string                 `protobuf:"bytes,22,opt,name=risk_level,json=riskLevel,proto3" json:"risk_level,omitempty"`                   // low、medium 或 high
+	Risks              []string               `protobuf:"bytes,23,rep,name=risks,proto3" json:"risks,omitempty"`
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *TokenRiskReport) Reset() {
+	*x = TokenRiskReport{}
+	mi := &file_proto_chain_service_proto_msgTypes[45]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenRiskReport) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenRiskReport) ProtoMessage() {}
+
+func (x *TokenRiskReport) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[45]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenRiskReport.ProtoReflect.Descriptor instead.
+func (*TokenRiskReport) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{45}
+}
+
+func (x *TokenRiskReport) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetRoute() []string {
+	if x != nil {
+		return x.Route
+	}
+	return nil
+}
+
+func (x *TokenRiskReport) GetBuyAmount() string {
+	if x != nil {
+		return x.BuyAmount
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetIsHoneypot() bool {
+	if x != nil {
+		return x.IsHoneypot
+	}
+	return false
+}
+
+func (x *TokenRiskReport) GetCanBuy() bool {
+	if x != nil {
+		return x.CanBuy
+	}
+	return false
+}
+
+func (x *TokenRiskReport) GetCanSell() bool {
+	if x != nil {
+		return x.CanSell
+	}
+	return false
+}
+
+func (x *TokenRiskReport) GetBuyError() string {
+	if x != nil {
+		return x.BuyError
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetSellError() string {
+	if x != nil {
+		return x.SellError
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetBuyTax() string {
+	if x != nil {
+		return x.BuyTax
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetSellTax() string {
+	if x != nil {
+		return x.SellTax
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetTransferTax() string {
+	if x != nil {
+		return x.TransferTax
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetBuyGas() uint64 {
+	if x != nil {
+		return x.BuyGas
+	}
+	return 0
+}
+
+func (x *TokenRiskReport) GetSellGas() uint64 {
+	if x != nil {
+		return x.SellGas
+	}
+	return 0
+}
+
+func (x *TokenRiskReport) GetMaxTxAmount() string {
+	if x != nil {
+		return x.MaxTxAmount
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetMaxWalletAmount() string {
+	if x != nil {
+		return x.MaxWalletAmount
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetOwner() string {
+	if x != nil {
+		return x.Owner
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetOwnershipRenounced() bool {
+	if x != nil {
+		return x.OwnershipRenounced
+	}
+	return false
+}
+
+func (x *TokenRiskReport) GetPrivileges() []string {
+	if x != nil {
+		return x.Privileges
+	}
+	return nil
+}
+
+func (x *TokenRiskReport) GetPrivilegeSource() string {
+	if x != nil {
+		return x.PrivilegeSource
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetRiskLevel() string {
+	if x != nil {
+		return x.RiskLevel
+	}
+	return ""
+}
+
+func (x *TokenRiskReport) GetRisks() []string {
+	if x != nil {
+		return x.Risks
+	}
+	return nil
+}
+
+type AnalyzeTokenRiskResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Report        *TokenRiskReport       `protobuf:"bytes,1,opt,name=report,proto3" json:"report,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AnalyzeTokenRiskResponse) Reset() {
+	*x = AnalyzeTokenRiskResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[46]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AnalyzeTokenRiskResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AnalyzeTokenRiskResponse) ProtoMessage() {}
+
+func (x *AnalyzeTokenRiskResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[46]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AnalyzeTokenRiskResponse.ProtoReflect.Descriptor instead.
+func (*AnalyzeTokenRiskResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{46}
+}
+
+func (x *AnalyzeTokenRiskResponse) GetReport() *TokenRiskReport {
+	if x != nil {
+		return x.Report
+	}
+	return nil
+}
+
+func (x *AnalyzeTokenRiskResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *AnalyzeTokenRiskResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetTWAPRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	WindowSeconds int64                  `protobuf:"varint,2,opt,name=window_seconds,json=windowSeconds,proto3" json:"window_seconds,omitempty"` // 默认1800
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTWAPRequest) Reset() {
+	*x = GetTWAPRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[47]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTWAPRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTWAPRequest) ProtoMessage() {}
+
+func (x *GetTWAPRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[47]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTWAPRequest.ProtoReflect.Descriptor instead.
+func (*GetTWAPRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{47}
+}
+
+func (x *GetTWAPRequest) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *GetTWAPRequest) GetWindowSeconds() int64 {
+	if x != nil {
+		return x.WindowSeconds
+	}
+	return 0
+}
+
+type TWAPPrice struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          string                 `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Token0        string                 `protobuf:"bytes,2,opt,name=token0,proto3" json:"token0,omitempty"`
+	Token1        string                 `protobuf:"bytes,3,opt,name=token1,proto3" json:"token1,omitempty"`
+	Price0        string                 `protobuf:"bytes,4,opt,name=price0,proto3" json:"price0,omitempty"` // 以token1计价的token0价格
+	Price1        string                 `protobuf:"bytes,5,opt,name=price1,proto3" json:"price1,omitempty"` // 以token0计价的token1价格
+	WindowStart   int64                  `protobuf:"varint,6,opt,name=window_start,json=windowStart,proto3" json:"window_start,omitempty"`
+	WindowEnd     int64                  `protobuf:"varint,7,opt,name=window_end,json=windowEnd,proto3" json:"window_end,omitempty"`
+	Source        string                 `protobuf:"bytes,8,opt,name=source,proto3" json:"source,omitempty"` // v2-cumulative、v3-observe 或 v3-samples
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TWAPPrice) Reset() {
+	*x = TWAPPrice{}
+	mi := &file_proto_chain_service_proto_msgTypes[48]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TWAPPrice) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TWAPPrice) ProtoMessage() {}
+
+func (x *TWAPPrice) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[48]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TWAPPrice.ProtoReflect.Descriptor instead.
+func (*TWAPPrice) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{48}
+}
+
+func (x *TWAPPrice) GetPool() string {
+	if x != nil {
+		return x.Pool
+	}
+	return ""
+}
+
+func (x *TWAPPrice) GetToken0() string {
+	if x != nil {
+		return x.Token0
+	}
+	return ""
+}
+
+func (x *TWAPPrice) GetToken1() string {
+	if x != nil {
+		return x.Token1
+	}
+	return ""
+}
+
+func (x *TWAPPrice) GetPrice0() string {
+	if x != nil {
+		return x.Price0
+	}
+	return ""
+}
+
+func (x *TWAPPrice) GetPrice1() string {
+	if x != nil {
+		return x.Price1
+	}
+	return ""
+}
+
+func (x *TWAPPrice) GetWindowStart() int64 {
+	if x != nil {
+		return x.WindowStart
+	}
+	return 0
+}
+
+func (x *TWAPPrice) GetWindowEnd() int64 {
+	if x != nil {
+		return x.WindowEnd
+	}
+	return 0
+}
+
+func (x *TWAPPrice) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+type GetTWAPResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Price         *TWAPPrice             `protobuf:"bytes,1,opt,name=price,proto3" json:"price,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTWAPResponse) Reset() {
+	*x = GetTWAPResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[49]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTWAPResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTWAPResponse) ProtoMessage() {}
+
+func (x *GetTWAPResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[49]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTWAPResponse.ProtoReflect.Descriptor instead.
+func (*GetTWAPResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{49}
+}
+
+func (x *GetTWAPResponse) GetPrice() *TWAPPrice {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *GetTWAPResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetTWAPResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+type GetTokenCandlesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Interval      string                 `protobuf:"bytes,2,opt,name=interval,proto3" json:"interval,omitempty"` // 1m、5m、1h 或 1d，默认1h
+	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`        // Unix秒，默认为to之前的99个周期
+	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`            // Unix秒，默认为当前时间
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTokenCandlesRequest) Reset() {
+	*x = GetTokenCandlesRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[50]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTokenCandlesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTokenCandlesRequest) ProtoMessage() {}
+
+func (x *GetTokenCandlesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[50]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTokenCandlesRequest.ProtoReflect.Descriptor instead.
+func (*GetTokenCandlesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{50}
+}
+
+func (x *GetTokenCandlesRequest) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *GetTokenCandlesRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetTokenCandlesRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetTokenCandlesRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+type Candle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	OpenTime      int64                  `protobuf:"varint,1,opt,name=open_time,json=openTime,proto3" json:"open_time,omitempty"`
+	OpenUsd       string                 `protobuf:"bytes,2,opt,name=open_usd,json=openUsd,proto3" json:"open_usd,omitempty"`
+	HighUsd       string                 `protobuf:"bytes,3,opt,name=high_usd,json=highUsd,proto3" json:"high_usd,omitempty"`
+	LowUsd        string                 `protobuf:"bytes,4,opt,name=low_usd,json=lowUsd,proto3" json:"low_usd,omitempty"`
+	CloseUsd      string                 `protobuf:"bytes,5,opt,name=close_usd,json=closeUsd,proto3" json:"close_usd,omitempty"`
+	OpenBnb       string                 `protobuf:"bytes,6,opt,name=open_bnb,json=openBnb,proto3" json:"open_bnb,omitempty"`
+	HighBnb       string                 `protobuf:"bytes,7,opt,name=high_bnb,json=highBnb,proto3" json:"high_bnb,omitempty"`
+	LowBnb        string                 `protobuf:"bytes,8,opt,name=low_bnb,json=lowBnb,proto3" json:"low_bnb,omitempty"`
+	CloseBnb      string                 `protobuf:"bytes,9,opt,name=close_bnb,json=closeBnb,proto3" json:"close_bnb,omitempty"`
+	Volume        string                 `protobuf:"bytes,10,opt,name=volume,proto3" json:"volume,omitempty"` // 代币成交数量
+	VolumeUsd     string                 `protobuf:"bytes,11,opt,name=volume_usd,json=volumeUsd,proto3" json:"volume_usd,omitempty"`
+	Trades        int32                  `protobuf:"varint,12,opt,name=trades,proto3" json:"trades,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Candle) Reset() {
+	*x = Candle{}
+	mi := &file_proto_chain_service_proto_msgTypes[51]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Candle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Candle) ProtoMessage() {}
+
+func (x *Candle) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[51]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Candle.ProtoReflect.Descriptor instead.
+func (*Candle) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{51}
+}
+
+func (x *Candle) GetOpenTime() int64 {
+	if x != nil {
+		return x.OpenTime
+	}
+	return 0
+}
+
+func (x *Candle) GetOpenUsd() string {
+	if x != nil {
+		return x.OpenUsd
+	}
+	return ""
+}
+
+func (x *Candle) GetHighUsd() string {
+	if x != nil {
+		return x.HighUsd
+	}
+	return ""
+}
+
+func (x *Candle) GetLowUsd() string {
+	if x != nil {
+		return x.LowUsd
+	}
+	return ""
+}
+
+func (x *Candle) GetCloseUsd() string {
+	if x != nil {
+		return x.CloseUsd
+	}
+	return ""
+}
+
+func (x *Candle) GetOpenBnb() string {
+	if x != nil {
+		return x.OpenBnb
+	}
+	return ""
+}
+
+func (x *Candle) GetHighBnb() string {
+	if x != nil {
+		return x.HighBnb
+	}
+	return ""
+}
+
+func (x *Candle) GetLowBnb() string {
+	if x != nil {
+		return x.LowBnb
+	}
+	return ""
+}
+
+func (x *Candle) GetCloseBnb() string {
+	if x != nil {
+		return x.CloseBnb
+	}
+	return ""
+}
+
+func (x *Candle) GetVolume() string {
+	if x != nil {
+		return x.Volume
+	}
+	return ""
+}
+
+func (x *Candle) GetVolumeUsd() string {
+	if x != nil {
+		return x.VolumeUsd
+	}
+	return ""
+}
+
+func (x *Candle) GetTrades() int32 {
+	if x != nil {
+		return x.Trades
+	}
+	return 0
+}
+
+type GetTokenCandlesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Candles       []*Candle              `protobuf:"bytes,1,rep,name=candles,proto3" json:"candles,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTokenCandlesResponse) Reset() {
+	*x = GetTokenCandlesResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[52]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTokenCandlesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTokenCandlesResponse) ProtoMessage() {}
+
+func (x *GetTokenCandlesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[52]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTokenCandlesResponse.ProtoReflect.Descriptor instead.
+func (*GetTokenCandlesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{52}
+}
+
+func (x *GetTokenCandlesResponse) GetCandles() []*Candle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+func (x *GetTokenCandlesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetTokenCandlesResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// 价格服务消息
+type CryptoPriceInfo struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Symbol                 string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Name                   string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	CurrentPrice           float64                `protobuf:"fixed64,3,opt,name=current_price,json=currentPrice,proto3" json:"current_price,omitempty"`
+	MarketCap              float64                `protobuf:"fixed64,4,opt,name=market_cap,json=marketCap,proto3" json:"market_cap,omitempty"`
+	Volume_24H             float64                `protobuf:"fixed64,5,opt,name=volume_24h,json=volume24h,proto3" json:"volume_24h,omitempty"`
+	PriceChange_24H        float64                `protobuf:"fixed64,6,opt,name=price_change_24h,json=priceChange24h,proto3" json:"price_change_24h,omitempty"`
+	PriceChangePercent_24H float64                `protobuf:"fixed64,7,opt,name=price_change_percent_24h,json=priceChangePercent24h,proto3" json:"price_change_percent_24h,omitempty"`
+	LastUpdated            string                 `protobuf:"bytes,8,opt,name=last_updated,json=lastUpdated,proto3" json:"last_updated,omitempty"`
+	Source                 string                 `protobuf:"bytes,9,opt,name=source,proto3" json:"source,omitempty"`                                                                            // 产生该结果的数据源，中位数聚合时为 median
+	Sources                []string               `protobuf:"bytes,10,rep,name=sources,proto3" json:"sources,omitempty"`                                                                         // 中位数聚合时参与计算的数据源
+	CoinId                 string                 `protobuf:"bytes,11,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`                                                             // 解析得到的CoinGecko币种ID
+	Quotes                 map[string]*PriceQuote `protobuf:"bytes,12,rep,name=quotes,proto3" json:"quotes,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // 请求的计价货币（小写）=> 报价，顶层价格字段始终为USD
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *CryptoPriceInfo) Reset() {
+	*x = CryptoPriceInfo{}
+	mi := &file_proto_chain_service_proto_msgTypes[53]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CryptoPriceInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CryptoPriceInfo) ProtoMessage() {}
+
+func (x *CryptoPriceInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[53]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CryptoPriceInfo.ProtoReflect.Descriptor instead.
+func (*CryptoPriceInfo) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{53}
+}
+
+func (x *CryptoPriceInfo) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *CryptoPriceInfo) GetName() string {
+	if x != nil {
+		return x.Name
 	}
 	return ""
 }
 
 func (x *CryptoPriceInfo) GetCurrentPrice() float64 {
 	if x != nil {
-		return x.CurrentPrice
+		return x.CurrentPrice
+	}
+	return 0
+}
+
+func (x *CryptoPriceInfo) GetMarketCap() float64 {
+	if x != nil {
+		return x.MarketCap
+	}
+	return 0
+}
+
+func (x *CryptoPriceInfo) GetVolume_24H() float64 {
+	if x != nil {
+		return x.Volume_24H
+	}
+	return 0
+}
+
+func (x *CryptoPriceInfo) GetPriceChange_24H() float64 {
+	if x != nil {
+		return x.PriceChange_24H
+	}
+	return 0
+}
+
+func (x *CryptoPriceInfo) GetPriceChangePercent_24H() float64 {
+	if x != nil {
+		return x.PriceChangePercent_24H
+	}
+	return 0
+}
+
+func (x *CryptoPriceInfo) GetLastUpdated() string {
+	if x != nil {
+		return x.LastUpdated
+	}
+	return ""
+}
+
+func (x *CryptoPriceInfo) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CryptoPriceInfo) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+func (x *CryptoPriceInfo) GetCoinId() string {
+	if x != nil {
+		return x.CoinId
+	}
+	return ""
+}
+
+func (x *CryptoPriceInfo) GetQuotes() map[string]*PriceQuote {
+	if x != nil {
+		return x.Quotes
+	}
+	return nil
+}
+
+// PriceQuote 以某种计价货币表示的价格
+type PriceQuote struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Price                  float64                `protobuf:"fixed64,1,opt,name=price,proto3" json:"price,omitempty"`
+	MarketCap              float64                `protobuf:"fixed64,2,opt,name=market_cap,json=marketCap,proto3" json:"market_cap,omitempty"`
+	Volume_24H             float64                `protobuf:"fixed64,3,opt,name=volume_24h,json=volume24h,proto3" json:"volume_24h,omitempty"`
+	PriceChange_24H        float64                `protobuf:"fixed64,4,opt,name=price_change_24h,json=priceChange24h,proto3" json:"price_change_24h,omitempty"`
+	PriceChangePercent_24H float64                `protobuf:"fixed64,5,opt,name=price_change_percent_24h,json=priceChangePercent24h,proto3" json:"price_change_percent_24h,omitempty"`
+	Converted              bool                   `protobuf:"varint,6,opt,name=converted,proto3" json:"converted,omitempty"` // 由USD价格按当前汇率换算
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *PriceQuote) Reset() {
+	*x = PriceQuote{}
+	mi := &file_proto_chain_service_proto_msgTypes[54]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceQuote) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceQuote) ProtoMessage() {}
+
+func (x *PriceQuote) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[54]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceQuote.ProtoReflect.Descriptor instead.
+func (*PriceQuote) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{54}
+}
+
+func (x *PriceQuote) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PriceQuote) GetMarketCap() float64 {
+	if x != nil {
+		return x.MarketCap
+	}
+	return 0
+}
+
+func (x *PriceQuote) GetVolume_24H() float64 {
+	if x != nil {
+		return x.Volume_24H
+	}
+	return 0
+}
+
+func (x *PriceQuote) GetPriceChange_24H() float64 {
+	if x != nil {
+		return x.PriceChange_24H
+	}
+	return 0
+}
+
+func (x *PriceQuote) GetPriceChangePercent_24H() float64 {
+	if x != nil {
+		return x.PriceChangePercent_24H
+	}
+	return 0
+}
+
+func (x *PriceQuote) GetConverted() bool {
+	if x != nil {
+		return x.Converted
+	}
+	return false
+}
+
+type GetCryptoPriceRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Currencies    []string               `protobuf:"bytes,2,rep,name=currencies,proto3" json:"currencies,omitempty"` // 计价货币：usd, eur, cny, jpy, btc, bnb
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCryptoPriceRequest) Reset() {
+	*x = GetCryptoPriceRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[55]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCryptoPriceRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCryptoPriceRequest) ProtoMessage() {}
+
+func (x *GetCryptoPriceRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[55]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCryptoPriceRequest.ProtoReflect.Descriptor instead.
+func (*GetCryptoPriceRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{55}
+}
+
+func (x *GetCryptoPriceRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetCryptoPriceRequest) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+type GetCryptoPriceResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Price         *CryptoPriceInfo       `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetCryptoPriceResponse) Reset() {
+	*x = GetCryptoPriceResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[56]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetCryptoPriceResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetCryptoPriceResponse) ProtoMessage() {}
+
+func (x *GetCryptoPriceResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[56]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetCryptoPriceResponse.ProtoReflect.Descriptor instead.
+func (*GetCryptoPriceResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{56}
+}
+
+func (x *GetCryptoPriceResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetCryptoPriceResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *GetCryptoPriceResponse) GetPrice() *CryptoPriceInfo {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+type GetMultipleCryptoPricesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	Currencies    []string               `protobuf:"bytes,2,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMultipleCryptoPricesRequest) Reset() {
+	*x = GetMultipleCryptoPricesRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[57]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMultipleCryptoPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMultipleCryptoPricesRequest) ProtoMessage() {}
+
+func (x *GetMultipleCryptoPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[57]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMultipleCryptoPricesRequest.ProtoReflect.Descriptor instead.
+func (*GetMultipleCryptoPricesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{57}
+}
+
+func (x *GetMultipleCryptoPricesRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *GetMultipleCryptoPricesRequest) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+type GetMultipleCryptoPricesResponse struct {
+	state         protoimpl.MessageState      `protogen:"open.v1"`
+	Success       bool                        `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                      `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Prices        map[string]*CryptoPriceInfo `protobuf:"bytes,3,rep,name=prices,proto3" json:"prices,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetMultipleCryptoPricesResponse) Reset() {
+	*x = GetMultipleCryptoPricesResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[58]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetMultipleCryptoPricesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetMultipleCryptoPricesResponse) ProtoMessage() {}
+
+func (x *GetMultipleCryptoPricesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[58]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetMultipleCryptoPricesResponse.ProtoReflect.Descriptor instead.
+func (*GetMultipleCryptoPricesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{58}
+}
+
+func (x *GetMultipleCryptoPricesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetMultipleCryptoPricesResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *GetMultipleCryptoPricesResponse) GetPrices() map[string]*CryptoPriceInfo {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+type GetTopCryptoPricesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Currencies    []string               `protobuf:"bytes,2,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopCryptoPricesRequest) Reset() {
+	*x = GetTopCryptoPricesRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[59]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopCryptoPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopCryptoPricesRequest) ProtoMessage() {}
+
+func (x *GetTopCryptoPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[59]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopCryptoPricesRequest.ProtoReflect.Descriptor instead.
+func (*GetTopCryptoPricesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{59}
+}
+
+func (x *GetTopCryptoPricesRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetTopCryptoPricesRequest) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+type GetTopCryptoPricesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Prices        []*CryptoPriceInfo     `protobuf:"bytes,3,rep,name=prices,proto3" json:"prices,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetTopCryptoPricesResponse) Reset() {
+	*x = GetTopCryptoPricesResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[60]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetTopCryptoPricesResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetTopCryptoPricesResponse) ProtoMessage() {}
+
+func (x *GetTopCryptoPricesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[60]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetTopCryptoPricesResponse.ProtoReflect.Descriptor instead.
+func (*GetTopCryptoPricesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{60}
+}
+
+func (x *GetTopCryptoPricesResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetTopCryptoPricesResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *GetTopCryptoPricesResponse) GetPrices() []*CryptoPriceInfo {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+type SearchCryptoRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
+	Currencies    []string               `protobuf:"bytes,2,rep,name=currencies,proto3" json:"currencies,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchCryptoRequest) Reset() {
+	*x = SearchCryptoRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[61]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchCryptoRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCryptoRequest) ProtoMessage() {}
+
+func (x *SearchCryptoRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[61]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCryptoRequest.ProtoReflect.Descriptor instead.
+func (*SearchCryptoRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{61}
+}
+
+func (x *SearchCryptoRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *SearchCryptoRequest) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+type SearchCryptoResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Results       []*CryptoPriceInfo     `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SearchCryptoResponse) Reset() {
+	*x = SearchCryptoResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[62]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SearchCryptoResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SearchCryptoResponse) ProtoMessage() {}
+
+func (x *SearchCryptoResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[62]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SearchCryptoResponse.ProtoReflect.Descriptor instead.
+func (*SearchCryptoResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{62}
+}
+
+func (x *SearchCryptoResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *SearchCryptoResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *SearchCryptoResponse) GetResults() []*CryptoPriceInfo {
+	if x != nil {
+		return x.Results
+	}
+	return nil
+}
+
+type GetPriceHistoryRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Days          int32                  `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"`
+	From          int64                  `protobuf:"varint,3,opt,name=from,proto3" json:"from,omitempty"`        // 起始时间（Unix秒），为0时查询to之前days天
+	To            int64                  `protobuf:"varint,4,opt,name=to,proto3" json:"to,omitempty"`            // 结束时间（Unix秒），为0时为当前时间
+	Interval      string                 `protobuf:"bytes,5,opt,name=interval,proto3" json:"interval,omitempty"` // 5m、1h、1d 或 1w，为空时按时间范围自动选择
+	Currency      string                 `protobuf:"bytes,6,opt,name=currency,proto3" json:"currency,omitempty"` // 计价货币，默认usd
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPriceHistoryRequest) Reset() {
+	*x = GetPriceHistoryRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[63]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceHistoryRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceHistoryRequest) ProtoMessage() {}
+
+func (x *GetPriceHistoryRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[63]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceHistoryRequest.ProtoReflect.Descriptor instead.
+func (*GetPriceHistoryRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{63}
+}
+
+func (x *GetPriceHistoryRequest) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *GetPriceHistoryRequest) GetDays() int32 {
+	if x != nil {
+		return x.Days
+	}
+	return 0
+}
+
+func (x *GetPriceHistoryRequest) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetPriceHistoryRequest) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetPriceHistoryRequest) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetPriceHistoryRequest) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+type PricePoint struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Price         float64                `protobuf:"fixed64,2,opt,name=price,proto3" json:"price,omitempty"`
+	MarketCap     float64                `protobuf:"fixed64,3,opt,name=market_cap,json=marketCap,proto3" json:"market_cap,omitempty"`
+	Volume        float64                `protobuf:"fixed64,4,opt,name=volume,proto3" json:"volume,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PricePoint) Reset() {
+	*x = PricePoint{}
+	mi := &file_proto_chain_service_proto_msgTypes[64]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PricePoint) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PricePoint) ProtoMessage() {}
+
+func (x *PricePoint) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[64]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PricePoint.ProtoReflect.Descriptor instead.
+func (*PricePoint) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{64}
+}
+
+func (x *PricePoint) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *PricePoint) GetPrice() float64 {
+	if x != nil {
+		return x.Price
+	}
+	return 0
+}
+
+func (x *PricePoint) GetMarketCap() float64 {
+	if x != nil {
+		return x.MarketCap
+	}
+	return 0
+}
+
+func (x *PricePoint) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+type PriceHistoryCandle struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Timestamp     int64                  `protobuf:"varint,1,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Open          float64                `protobuf:"fixed64,2,opt,name=open,proto3" json:"open,omitempty"`
+	High          float64                `protobuf:"fixed64,3,opt,name=high,proto3" json:"high,omitempty"`
+	Low           float64                `protobuf:"fixed64,4,opt,name=low,proto3" json:"low,omitempty"`
+	Close         float64                `protobuf:"fixed64,5,opt,name=close,proto3" json:"close,omitempty"`
+	Volume        float64                `protobuf:"fixed64,6,opt,name=volume,proto3" json:"volume,omitempty"`
+	MarketCap     float64                `protobuf:"fixed64,7,opt,name=market_cap,json=marketCap,proto3" json:"market_cap,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *PriceHistoryCandle) Reset() {
+	*x = PriceHistoryCandle{}
+	mi := &file_proto_chain_service_proto_msgTypes[65]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *PriceHistoryCandle) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*PriceHistoryCandle) ProtoMessage() {}
+
+func (x *PriceHistoryCandle) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[65]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PriceHistoryCandle.ProtoReflect.Descriptor instead.
+func (*PriceHistoryCandle) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{65}
+}
+
+func (x *PriceHistoryCandle) GetTimestamp() int64 {
+	if x != nil {
+		return x.Timestamp
+	}
+	return 0
+}
+
+func (x *PriceHistoryCandle) GetOpen() float64 {
+	if x != nil {
+		return x.Open
+	}
+	return 0
+}
+
+func (x *PriceHistoryCandle) GetHigh() float64 {
+	if x != nil {
+		return x.High
+	}
+	return 0
+}
+
+func (x *PriceHistoryCandle) GetLow() float64 {
+	if x != nil {
+		return x.Low
+	}
+	return 0
+}
+
+func (x *PriceHistoryCandle) GetClose() float64 {
+	if x != nil {
+		return x.Close
+	}
+	return 0
+}
+
+func (x *PriceHistoryCandle) GetVolume() float64 {
+	if x != nil {
+		return x.Volume
+	}
+	return 0
+}
+
+func (x *PriceHistoryCandle) GetMarketCap() float64 {
+	if x != nil {
+		return x.MarketCap
+	}
+	return 0
+}
+
+type GetPriceHistoryResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Prices        []float64              `protobuf:"fixed64,3,rep,packed,name=prices,proto3" json:"prices,omitempty"`
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Points        []*PricePoint          `protobuf:"bytes,5,rep,name=points,proto3" json:"points,omitempty"`
+	Candles       []*PriceHistoryCandle  `protobuf:"bytes,6,rep,name=candles,proto3" json:"candles,omitempty"`
+	Interval      string                 `protobuf:"bytes,7,opt,name=interval,proto3" json:"interval,omitempty"`
+	Currency      string                 `protobuf:"bytes,8,opt,name=currency,proto3" json:"currency,omitempty"`
+	CoinId        string                 `protobuf:"bytes,9,opt,name=coin_id,json=coinId,proto3" json:"coin_id,omitempty"`
+	From          int64                  `protobuf:"varint,10,opt,name=from,proto3" json:"from,omitempty"`
+	To            int64                  `protobuf:"varint,11,opt,name=to,proto3" json:"to,omitempty"`
+	Converted     bool                   `protobuf:"varint,12,opt,name=converted,proto3" json:"converted,omitempty"`
+	Sources       []string               `protobuf:"bytes,13,rep,name=sources,proto3" json:"sources,omitempty"` // 使用本地记录时，local和补充未覆盖时间范围的数据源
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetPriceHistoryResponse) Reset() {
+	*x = GetPriceHistoryResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[66]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetPriceHistoryResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetPriceHistoryResponse) ProtoMessage() {}
+
+func (x *GetPriceHistoryResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[66]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetPriceHistoryResponse.ProtoReflect.Descriptor instead.
+func (*GetPriceHistoryResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{66}
+}
+
+func (x *GetPriceHistoryResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetPriceHistoryResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *GetPriceHistoryResponse) GetPrices() []float64 {
+	if x != nil {
+		return x.Prices
+	}
+	return nil
+}
+
+func (x *GetPriceHistoryResponse) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *GetPriceHistoryResponse) GetPoints() []*PricePoint {
+	if x != nil {
+		return x.Points
+	}
+	return nil
+}
+
+func (x *GetPriceHistoryResponse) GetCandles() []*PriceHistoryCandle {
+	if x != nil {
+		return x.Candles
+	}
+	return nil
+}
+
+func (x *GetPriceHistoryResponse) GetInterval() string {
+	if x != nil {
+		return x.Interval
+	}
+	return ""
+}
+
+func (x *GetPriceHistoryResponse) GetCurrency() string {
+	if x != nil {
+		return x.Currency
+	}
+	return ""
+}
+
+func (x *GetPriceHistoryResponse) GetCoinId() string {
+	if x != nil {
+		return x.CoinId
+	}
+	return ""
+}
+
+func (x *GetPriceHistoryResponse) GetFrom() int64 {
+	if x != nil {
+		return x.From
+	}
+	return 0
+}
+
+func (x *GetPriceHistoryResponse) GetTo() int64 {
+	if x != nil {
+		return x.To
+	}
+	return 0
+}
+
+func (x *GetPriceHistoryResponse) GetConverted() bool {
+	if x != nil {
+		return x.Converted
+	}
+	return false
+}
+
+func (x *GetPriceHistoryResponse) GetSources() []string {
+	if x != nil {
+		return x.Sources
+	}
+	return nil
+}
+
+// 价格订阅
+type SubscribeTokenPricesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Tokens        []string               `protobuf:"bytes,1,rep,name=tokens,proto3" json:"tokens,omitempty"`      // BSC代币地址
+	Epsilon       float64                `protobuf:"fixed64,2,opt,name=epsilon,proto3" json:"epsilon,omitempty"`  // 价格相对上次推送变化达到该百分比时推送，0使用服务端默认值
+	Interval      int64                  `protobuf:"varint,3,opt,name=interval,proto3" json:"interval,omitempty"` // 价格未变化时也推送的间隔（秒），0使用服务端默认值
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeTokenPricesRequest) Reset() {
+	*x = SubscribeTokenPricesRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[67]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeTokenPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeTokenPricesRequest) ProtoMessage() {}
+
+func (x *SubscribeTokenPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[67]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeTokenPricesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeTokenPricesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{67}
+}
+
+func (x *SubscribeTokenPricesRequest) GetTokens() []string {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+func (x *SubscribeTokenPricesRequest) GetEpsilon() float64 {
+	if x != nil {
+		return x.Epsilon
+	}
+	return 0
+}
+
+func (x *SubscribeTokenPricesRequest) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+type TokenPriceUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Token         string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"`
+	Price         *TokenPrice            `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"` // 查询失败时为空
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"` // 查询价格的时间（Unix秒）
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TokenPriceUpdate) Reset() {
+	*x = TokenPriceUpdate{}
+	mi := &file_proto_chain_service_proto_msgTypes[68]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TokenPriceUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TokenPriceUpdate) ProtoMessage() {}
+
+func (x *TokenPriceUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[68]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TokenPriceUpdate.ProtoReflect.Descriptor instead.
+func (*TokenPriceUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{68}
+}
+
+func (x *TokenPriceUpdate) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *TokenPriceUpdate) GetPrice() *TokenPrice {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *TokenPriceUpdate) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *TokenPriceUpdate) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type SubscribeCryptoPricesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`       // 符号、名称或币种ID
+	Currencies    []string               `protobuf:"bytes,2,rep,name=currencies,proto3" json:"currencies,omitempty"` // 计价货币：usd, eur, cny, jpy, btc, bnb
+	Epsilon       float64                `protobuf:"fixed64,3,opt,name=epsilon,proto3" json:"epsilon,omitempty"`
+	Interval      int64                  `protobuf:"varint,4,opt,name=interval,proto3" json:"interval,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SubscribeCryptoPricesRequest) Reset() {
+	*x = SubscribeCryptoPricesRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[69]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SubscribeCryptoPricesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SubscribeCryptoPricesRequest) ProtoMessage() {}
+
+func (x *SubscribeCryptoPricesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[69]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SubscribeCryptoPricesRequest.ProtoReflect.Descriptor instead.
+func (*SubscribeCryptoPricesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{69}
+}
+
+func (x *SubscribeCryptoPricesRequest) GetSymbols() []string {
+	if x != nil {
+		return x.Symbols
+	}
+	return nil
+}
+
+func (x *SubscribeCryptoPricesRequest) GetCurrencies() []string {
+	if x != nil {
+		return x.Currencies
+	}
+	return nil
+}
+
+func (x *SubscribeCryptoPricesRequest) GetEpsilon() float64 {
+	if x != nil {
+		return x.Epsilon
+	}
+	return 0
+}
+
+func (x *SubscribeCryptoPricesRequest) GetInterval() int64 {
+	if x != nil {
+		return x.Interval
+	}
+	return 0
+}
+
+type CryptoPriceUpdate struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Price         *CryptoPriceInfo       `protobuf:"bytes,2,opt,name=price,proto3" json:"price,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	UpdatedAt     int64                  `protobuf:"varint,4,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CryptoPriceUpdate) Reset() {
+	*x = CryptoPriceUpdate{}
+	mi := &file_proto_chain_service_proto_msgTypes[70]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CryptoPriceUpdate) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CryptoPriceUpdate) ProtoMessage() {}
+
+func (x *CryptoPriceUpdate) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[70]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CryptoPriceUpdate.ProtoReflect.Descriptor instead.
+func (*CryptoPriceUpdate) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{70}
+}
+
+func (x *CryptoPriceUpdate) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
+
+func (x *CryptoPriceUpdate) GetPrice() *CryptoPriceInfo {
+	if x != nil {
+		return x.Price
+	}
+	return nil
+}
+
+func (x *CryptoPriceUpdate) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *CryptoPriceUpdate) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type GetLiquidityPoolResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Pool          *LiquidityPool         `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
+	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetLiquidityPoolResponse) Reset() {
+	*x = GetLiquidityPoolResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[71]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetLiquidityPoolResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetLiquidityPoolResponse) ProtoMessage() {}
+
+func (x *GetLiquidityPoolResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[71]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetLiquidityPoolResponse.ProtoReflect.Descriptor instead.
+func (*GetLiquidityPoolResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{71}
+}
+
+func (x *GetLiquidityPoolResponse) GetPool() *LiquidityPool {
+	if x != nil {
+		return x.Pool
+	}
+	return nil
+}
+
+func (x *GetLiquidityPoolResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *GetLiquidityPoolResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+// 告警服务消息
+type AlertRule struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	Id              uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name            string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Asset           string                 `protobuf:"bytes,3,opt,name=asset,proto3" json:"asset,omitempty"`           // 币种符号、名称、币种ID，或BSC代币地址
+	Source          string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`         // crypto 或 token
+	Condition       string                 `protobuf:"bytes,5,opt,name=condition,proto3" json:"condition,omitempty"`   // above、below、change、change_up 或 change_down
+	Threshold       float64                `protobuf:"fixed64,6,opt,name=threshold,proto3" json:"threshold,omitempty"` // above/below为USD价格，change类为涨跌幅百分比
+	Window          int64                  `protobuf:"varint,7,opt,name=window,proto3" json:"window,omitempty"`        // change类条件的时间窗口（秒）
+	Cooldown        int64                  `protobuf:"varint,8,opt,name=cooldown,proto3" json:"cooldown,omitempty"`    // 触发后的冷却时间（秒）
+	Channel         string                 `protobuf:"bytes,9,opt,name=channel,proto3" json:"channel,omitempty"`       // webhook、email 或 telegram
+	Target          string                 `protobuf:"bytes,10,opt,name=target,proto3" json:"target,omitempty"`        // webhook地址、收件人邮箱或Telegram chat_id
+	Enabled         bool                   `protobuf:"varint,11,opt,name=enabled,proto3" json:"enabled,omitempty"`
+	LastTriggeredAt int64                  `protobuf:"varint,12,opt,name=last_triggered_at,json=lastTriggeredAt,proto3" json:"last_triggered_at,omitempty"` // 最近一次触发时间（Unix秒），未触发时为0
+	CreatedAt       int64                  `protobuf:"varint,13,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt       int64                  `protobuf:"varint,14,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AlertRule) Reset() {
+	*x = AlertRule{}
+	mi := &file_proto_chain_service_proto_msgTypes[72]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AlertRule) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AlertRule) ProtoMessage() {}
+
+func (x *AlertRule) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[72]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AlertRule.ProtoReflect.Descriptor instead.
+func (*AlertRule) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{72}
+}
+
+func (x *AlertRule) GetId() uint64 {
+	if x != nil {
+		return x.Id
+	}
+	return 0
+}
+
+func (x *AlertRule) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *AlertRule) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *AlertRule) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *AlertRule) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *AlertRule) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *AlertRule) GetWindow() int64 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+func (x *AlertRule) GetCooldown() int64 {
+	if x != nil {
+		return x.Cooldown
+	}
+	return 0
+}
+
+func (x *AlertRule) GetChannel() string {
+	if x != nil {
+		return x.Channel
+	}
+	return ""
+}
+
+func (x *AlertRule) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *AlertRule) GetEnabled() bool {
+	if x != nil {
+		return x.Enabled
+	}
+	return false
+}
+
+func (x *AlertRule) GetLastTriggeredAt() int64 {
+	if x != nil {
+		return x.LastTriggeredAt
+	}
+	return 0
+}
+
+func (x *AlertRule) GetCreatedAt() int64 {
+	if x != nil {
+		return x.CreatedAt
+	}
+	return 0
+}
+
+func (x *AlertRule) GetUpdatedAt() int64 {
+	if x != nil {
+		return x.UpdatedAt
+	}
+	return 0
+}
+
+type CreateAlertRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Asset         string                 `protobuf:"bytes,2,opt,name=asset,proto3" json:"asset,omitempty"`
+	Source        string                 `protobuf:"bytes,3,opt,name=source,proto3" json:"source,omitempty"`
+	Condition     string                 `protobuf:"bytes,4,opt,name=condition,proto3" json:"condition,omitempty"`
+	Threshold     float64                `protobuf:"fixed64,5,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Window        int64                  `protobuf:"varint,6,opt,name=window,proto3" json:"window,omitempty"`
+	Cooldown      int64                  `protobuf:"varint,7,opt,name=cooldown,proto3" json:"cooldown,omitempty"`
+	Channel       string                 `protobuf:"bytes,8,opt,name=channel,proto3" json:"channel,omitempty"`
+	Target        string                 `protobuf:"bytes,9,opt,name=target,proto3" json:"target,omitempty"`
+	Disabled      bool                   `protobuf:"varint,10,opt,name=disabled,proto3" json:"disabled,omitempty"` // 新规则默认启用
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CreateAlertRuleRequest) Reset() {
+	*x = CreateAlertRuleRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[73]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CreateAlertRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CreateAlertRuleRequest) ProtoMessage() {}
+
+func (x *CreateAlertRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[73]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CreateAlertRuleRequest.ProtoReflect.Descriptor instead.
+func (*CreateAlertRuleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{73}
+}
+
+func (x *CreateAlertRuleRequest) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *CreateAlertRuleRequest) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *CreateAlertRuleRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *CreateAlertRuleRequest) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *CreateAlertRuleRequest) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
+	}
+	return 0
+}
+
+func (x *CreateAlertRuleRequest) GetWindow() int64 {
+	if x != nil {
+		return x.Window
+	}
+	return 0
+}
+
+func (x *CreateAlertRuleRequest) GetCooldown() int64 {
+	if x != nil {
+		return x.Cooldown
+	}
+	return 0
+}
+
+func (x *CreateAlertRuleRequest) GetChannel() string {
+	if x != nil {
+		return x.Channel
 	}
-	return 0
+	return ""
 }
 
-func (x *CryptoPriceInfo) GetMarketCap() float64 {
+func (x *CreateAlertRuleRequest) GetTarget() string {
 	if x != nil {
-		return x.MarketCap
+		return x.Target
+	}
+	return ""
+}
+
+func (x *CreateAlertRuleRequest) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+type UpdateAlertRuleRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Asset         string                 `protobuf:"bytes,3,opt,name=asset,proto3" json:"asset,omitempty"`
+	Source        string                 `protobuf:"bytes,4,opt,name=source,proto3" json:"source,omitempty"`
+	Condition     string                 `protobuf:"bytes,5,opt,name=condition,proto3" json:"condition,omitempty"`
+	Threshold     float64                `protobuf:"fixed64,6,opt,name=threshold,proto3" json:"threshold,omitempty"`
+	Window        int64                  `protobuf:"varint,7,opt,name=window,proto3" json:"window,omitempty"`
+	Cooldown      int64                  `protobuf:"varint,8,opt,name=cooldown,proto3" json:"cooldown,omitempty"`
+	Channel       string                 `protobuf:"bytes,9,opt,name=channel,proto3" json:"channel,omitempty"`
+	Target        string                 `protobuf:"bytes,10,opt,name=target,proto3" json:"target,omitempty"`
+	Disabled      bool                   `protobuf:"varint,11,opt,name=disabled,proto3" json:"disabled,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *UpdateAlertRuleRequest) Reset() {
+	*x = UpdateAlertRuleRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[74]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *UpdateAlertRuleRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*UpdateAlertRuleRequest) ProtoMessage() {}
+
+func (x *UpdateAlertRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[74]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use UpdateAlertRuleRequest.ProtoReflect.Descriptor instead.
+func (*UpdateAlertRuleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{74}
+}
+
+func (x *UpdateAlertRuleRequest) GetId() uint64 {
+	if x != nil {
+		return x.Id
 	}
 	return 0
 }
 
-func (x *CryptoPriceInfo) GetVolume_24H() float64 {
+func (x *UpdateAlertRuleRequest) GetName() string {
 	if x != nil {
-		return x.Volume_24H
+		return x.Name
+	}
+	return ""
+}
+
+func (x *UpdateAlertRuleRequest) GetAsset() string {
+	if x != nil {
+		return x.Asset
+	}
+	return ""
+}
+
+func (x *UpdateAlertRuleRequest) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *UpdateAlertRuleRequest) GetCondition() string {
+	if x != nil {
+		return x.Condition
+	}
+	return ""
+}
+
+func (x *UpdateAlertRuleRequest) GetThreshold() float64 {
+	if x != nil {
+		return x.Threshold
 	}
 	return 0
 }
 
-func (x *CryptoPriceInfo) GetPriceChange_24H() float64 {
+func (x *UpdateAlertRuleRequest) GetWindow() int64 {
 	if x != nil {
-		return x.PriceChange_24H
+		return x.Window
 	}
 	return 0
 }
 
-func (x *CryptoPriceInfo) GetPriceChangePercent_24H() float64 {
+func (x *UpdateAlertRuleRequest) GetCooldown() int64 {
 	if x != nil {
-		return x.PriceChangePercent_24H
+		return x.Cooldown
 	}
 	return 0
 }
 
-func (x *CryptoPriceInfo) GetLastUpdated() string {
+func (x *UpdateAlertRuleRequest) GetChannel() string {
 	if x != nil {
-		return x.LastUpdated
+		return x.Channel
 	}
 	return ""
 }
 
-type GetCryptoPriceRequest struct {
+func (x *UpdateAlertRuleRequest) GetTarget() string {
+	if x != nil {
+		return x.Target
+	}
+	return ""
+}
+
+func (x *UpdateAlertRuleRequest) GetDisabled() bool {
+	if x != nil {
+		return x.Disabled
+	}
+	return false
+}
+
+type GetAlertRuleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCryptoPriceRequest) Reset() {
-	*x = GetCryptoPriceRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[26]
+func (x *GetAlertRuleRequest) Reset() {
+	*x = GetAlertRuleRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[75]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCryptoPriceRequest) String() string {
+func (x *GetAlertRuleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCryptoPriceRequest) ProtoMessage() {}
+func (*GetAlertRuleRequest) ProtoMessage() {}
 
-func (x *GetCryptoPriceRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[26]
+func (x *GetAlertRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[75]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1625,42 +5785,40 @@ func (x *GetCryptoPriceRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCryptoPriceRequest.ProtoReflect.Descriptor instead.
-func (*GetCryptoPriceRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{26}
+// Deprecated: Use GetAlertRuleRequest.ProtoReflect.Descriptor instead.
+func (*GetAlertRuleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{75}
 }
 
-func (x *GetCryptoPriceRequest) GetSymbol() string {
+func (x *GetAlertRuleRequest) GetId() uint64 {
 	if x != nil {
-		return x.Symbol
+		return x.Id
 	}
-	return ""
+	return 0
 }
 
-type GetCryptoPriceResponse struct {
+type DeleteAlertRuleRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	Price         *CryptoPriceInfo       `protobuf:"bytes,3,opt,name=price,proto3" json:"price,omitempty"`
+	Id            uint64                 `protobuf:"varint,1,opt,name=id,proto3" json:"id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCryptoPriceResponse) Reset() {
-	*x = GetCryptoPriceResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[27]
+func (x *DeleteAlertRuleRequest) Reset() {
+	*x = DeleteAlertRuleRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[76]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetCryptoPriceResponse) String() string {
+func (x *DeleteAlertRuleRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetCryptoPriceResponse) ProtoMessage() {}
+func (*DeleteAlertRuleRequest) ProtoMessage() {}
 
-func (x *GetCryptoPriceResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[27]
+func (x *DeleteAlertRuleRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[76]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1671,54 +5829,78 @@ func (x *GetCryptoPriceResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetCryptoPriceResponse.ProtoReflect.Descriptor instead.
-func (*GetCryptoPriceResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{27}
+// Deprecated: Use DeleteAlertRuleRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAlertRuleRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{76}
 }
 
-func (x *GetCryptoPriceResponse) GetSuccess() bool {
+func (x *DeleteAlertRuleRequest) GetId() uint64 {
 	if x != nil {
-		return x.Success
+		return x.Id
 	}
-	return false
+	return 0
 }
 
-func (x *GetCryptoPriceResponse) GetError() string {
-	if x != nil {
-		return x.Error
-	}
-	return ""
+type ListAlertRulesRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetCryptoPriceResponse) GetPrice() *CryptoPriceInfo {
+func (x *ListAlertRulesRequest) Reset() {
+	*x = ListAlertRulesRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[77]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ListAlertRulesRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ListAlertRulesRequest) ProtoMessage() {}
+
+func (x *ListAlertRulesRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[77]
 	if x != nil {
-		return x.Price
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return nil
+	return mi.MessageOf(x)
 }
 
-type GetMultipleCryptoPricesRequest struct {
+// Deprecated: Use ListAlertRulesRequest.ProtoReflect.Descriptor instead.
+func (*ListAlertRulesRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{77}
+}
+
+type AlertRuleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbols       []string               `protobuf:"bytes,1,rep,name=symbols,proto3" json:"symbols,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Rule          *AlertRule             `protobuf:"bytes,3,opt,name=rule,proto3" json:"rule,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMultipleCryptoPricesRequest) Reset() {
-	*x = GetMultipleCryptoPricesRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[28]
+func (x *AlertRuleResponse) Reset() {
+	*x = AlertRuleResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[78]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMultipleCryptoPricesRequest) String() string {
+func (x *AlertRuleResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMultipleCryptoPricesRequest) ProtoMessage() {}
+func (*AlertRuleResponse) ProtoMessage() {}
 
-func (x *GetMultipleCryptoPricesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[28]
+func (x *AlertRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[78]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1729,42 +5911,56 @@ func (x *GetMultipleCryptoPricesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMultipleCryptoPricesRequest.ProtoReflect.Descriptor instead.
-func (*GetMultipleCryptoPricesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{28}
+// Deprecated: Use AlertRuleResponse.ProtoReflect.Descriptor instead.
+func (*AlertRuleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{78}
 }
 
-func (x *GetMultipleCryptoPricesRequest) GetSymbols() []string {
+func (x *AlertRuleResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Symbols
+		return x.Success
+	}
+	return false
+}
+
+func (x *AlertRuleResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AlertRuleResponse) GetRule() *AlertRule {
+	if x != nil {
+		return x.Rule
 	}
 	return nil
 }
 
-type GetMultipleCryptoPricesResponse struct {
-	state         protoimpl.MessageState      `protogen:"open.v1"`
-	Success       bool                        `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Error         string                      `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	Prices        map[string]*CryptoPriceInfo `protobuf:"bytes,3,rep,name=prices,proto3" json:"prices,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+type ListAlertRulesResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Rules         []*AlertRule           `protobuf:"bytes,3,rep,name=rules,proto3" json:"rules,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetMultipleCryptoPricesResponse) Reset() {
-	*x = GetMultipleCryptoPricesResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[29]
+func (x *ListAlertRulesResponse) Reset() {
+	*x = ListAlertRulesResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[79]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetMultipleCryptoPricesResponse) String() string {
+func (x *ListAlertRulesResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetMultipleCryptoPricesResponse) ProtoMessage() {}
+func (*ListAlertRulesResponse) ProtoMessage() {}
 
-func (x *GetMultipleCryptoPricesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[29]
+func (x *ListAlertRulesResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[79]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1775,54 +5971,55 @@ func (x *GetMultipleCryptoPricesResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetMultipleCryptoPricesResponse.ProtoReflect.Descriptor instead.
-func (*GetMultipleCryptoPricesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{29}
+// Deprecated: Use ListAlertRulesResponse.ProtoReflect.Descriptor instead.
+func (*ListAlertRulesResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{79}
 }
 
-func (x *GetMultipleCryptoPricesResponse) GetSuccess() bool {
+func (x *ListAlertRulesResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *GetMultipleCryptoPricesResponse) GetError() string {
+func (x *ListAlertRulesResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-func (x *GetMultipleCryptoPricesResponse) GetPrices() map[string]*CryptoPriceInfo {
+func (x *ListAlertRulesResponse) GetRules() []*AlertRule {
 	if x != nil {
-		return x.Prices
+		return x.Rules
 	}
 	return nil
 }
 
-type GetTopCryptoPricesRequest struct {
+type DeleteAlertRuleResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Limit         int32                  `protobuf:"varint,1,opt,name=limit,proto3" json:"limit,omitempty"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetTopCryptoPricesRequest) Reset() {
-	*x = GetTopCryptoPricesRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[30]
+func (x *DeleteAlertRuleResponse) Reset() {
+	*x = DeleteAlertRuleResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[80]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTopCryptoPricesRequest) String() string {
+func (x *DeleteAlertRuleResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTopCryptoPricesRequest) ProtoMessage() {}
+func (*DeleteAlertRuleResponse) ProtoMessage() {}
 
-func (x *GetTopCryptoPricesRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[30]
+func (x *DeleteAlertRuleResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[80]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1833,146 +6030,192 @@ func (x *GetTopCryptoPricesRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetTopCryptoPricesRequest.ProtoReflect.Descriptor instead.
-func (*GetTopCryptoPricesRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{30}
+// Deprecated: Use DeleteAlertRuleResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAlertRuleResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{80}
 }
 
-func (x *GetTopCryptoPricesRequest) GetLimit() int32 {
+func (x *DeleteAlertRuleResponse) GetSuccess() bool {
 	if x != nil {
-		return x.Limit
+		return x.Success
 	}
-	return 0
+	return false
 }
 
-type GetTopCryptoPricesResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	Prices        []*CryptoPriceInfo     `protobuf:"bytes,3,rep,name=prices,proto3" json:"prices,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *DeleteAlertRuleResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
 }
 
-func (x *GetTopCryptoPricesResponse) Reset() {
-	*x = GetTopCryptoPricesResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[31]
+// 资产估值服务消息
+type PortfolioHolding struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Token                  string                 `protobuf:"bytes,1,opt,name=token,proto3" json:"token,omitempty"` // 代币合约地址，BNB为空
+	Symbol                 string                 `protobuf:"bytes,2,opt,name=symbol,proto3" json:"symbol,omitempty"`
+	Name                   string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`
+	Decimals               uint32                 `protobuf:"varint,4,opt,name=decimals,proto3" json:"decimals,omitempty"`
+	Native                 bool                   `protobuf:"varint,5,opt,name=native,proto3" json:"native,omitempty"`
+	Balance                string                 `protobuf:"bytes,6,opt,name=balance,proto3" json:"balance,omitempty"`                         // 按精度换算的数量
+	BalanceRaw             string                 `protobuf:"bytes,7,opt,name=balance_raw,json=balanceRaw,proto3" json:"balance_raw,omitempty"` // 最小单位的数量
+	PriceUsd               float64                `protobuf:"fixed64,8,opt,name=price_usd,json=priceUsd,proto3" json:"price_usd,omitempty"`
+	PriceChangePercent_24H float64                `protobuf:"fixed64,9,opt,name=price_change_percent_24h,json=priceChangePercent24h,proto3" json:"price_change_percent_24h,omitempty"`
+	ValueUsd               float64                `protobuf:"fixed64,10,opt,name=value_usd,json=valueUsd,proto3" json:"value_usd,omitempty"`
+	ValueChange_24H        float64                `protobuf:"fixed64,11,opt,name=value_change_24h,json=valueChange24h,proto3" json:"value_change_24h,omitempty"` // 按当前余额计算的24小时USD价值变化
+	PriceSource            string                 `protobuf:"bytes,12,opt,name=price_source,json=priceSource,proto3" json:"price_source,omitempty"`              // 产生价格的数据源，未能估值时为空
+	Sources                []string               `protobuf:"bytes,13,rep,name=sources,proto3" json:"sources,omitempty"`                                         // 余额来源：chain 或 stored
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
+}
+
+func (x *PortfolioHolding) Reset() {
+	*x = PortfolioHolding{}
+	mi := &file_proto_chain_service_proto_msgTypes[81]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetTopCryptoPricesResponse) String() string {
+func (x *PortfolioHolding) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetTopCryptoPricesResponse) ProtoMessage() {}
+func (*PortfolioHolding) ProtoMessage() {}
+
+func (x *PortfolioHolding) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[81]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use PortfolioHolding.ProtoReflect.Descriptor instead.
+func (*PortfolioHolding) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{81}
+}
+
+func (x *PortfolioHolding) GetToken() string {
+	if x != nil {
+		return x.Token
+	}
+	return ""
+}
+
+func (x *PortfolioHolding) GetSymbol() string {
+	if x != nil {
+		return x.Symbol
+	}
+	return ""
+}
 
-func (x *GetTopCryptoPricesResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[31]
+func (x *PortfolioHolding) GetName() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.Name
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use GetTopCryptoPricesResponse.ProtoReflect.Descriptor instead.
-func (*GetTopCryptoPricesResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{31}
+func (x *PortfolioHolding) GetDecimals() uint32 {
+	if x != nil {
+		return x.Decimals
+	}
+	return 0
 }
 
-func (x *GetTopCryptoPricesResponse) GetSuccess() bool {
+func (x *PortfolioHolding) GetNative() bool {
 	if x != nil {
-		return x.Success
+		return x.Native
 	}
 	return false
 }
 
-func (x *GetTopCryptoPricesResponse) GetError() string {
+func (x *PortfolioHolding) GetBalance() string {
 	if x != nil {
-		return x.Error
+		return x.Balance
 	}
 	return ""
 }
 
-func (x *GetTopCryptoPricesResponse) GetPrices() []*CryptoPriceInfo {
+func (x *PortfolioHolding) GetBalanceRaw() string {
 	if x != nil {
-		return x.Prices
+		return x.BalanceRaw
 	}
-	return nil
+	return ""
 }
 
-type SearchCryptoRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Query         string                 `protobuf:"bytes,1,opt,name=query,proto3" json:"query,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *PortfolioHolding) GetPriceUsd() float64 {
+	if x != nil {
+		return x.PriceUsd
+	}
+	return 0
 }
 
-func (x *SearchCryptoRequest) Reset() {
-	*x = SearchCryptoRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[32]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *PortfolioHolding) GetPriceChangePercent_24H() float64 {
+	if x != nil {
+		return x.PriceChangePercent_24H
+	}
+	return 0
 }
 
-func (x *SearchCryptoRequest) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *PortfolioHolding) GetValueUsd() float64 {
+	if x != nil {
+		return x.ValueUsd
+	}
+	return 0
 }
 
-func (*SearchCryptoRequest) ProtoMessage() {}
-
-func (x *SearchCryptoRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[32]
+func (x *PortfolioHolding) GetValueChange_24H() float64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.ValueChange_24H
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use SearchCryptoRequest.ProtoReflect.Descriptor instead.
-func (*SearchCryptoRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{32}
+func (x *PortfolioHolding) GetPriceSource() string {
+	if x != nil {
+		return x.PriceSource
+	}
+	return ""
 }
 
-func (x *SearchCryptoRequest) GetQuery() string {
+func (x *PortfolioHolding) GetSources() []string {
 	if x != nil {
-		return x.Query
+		return x.Sources
 	}
-	return ""
+	return nil
 }
 
-type SearchCryptoResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	Results       []*CryptoPriceInfo     `protobuf:"bytes,3,rep,name=results,proto3" json:"results,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type AccountPortfolio struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Address                string                 `protobuf:"bytes,1,opt,name=address,proto3" json:"address,omitempty"`
+	Holdings               []*PortfolioHolding    `protobuf:"bytes,2,rep,name=holdings,proto3" json:"holdings,omitempty"`
+	TotalValueUsd          float64                `protobuf:"fixed64,3,opt,name=total_value_usd,json=totalValueUsd,proto3" json:"total_value_usd,omitempty"`
+	ValueChange_24H        float64                `protobuf:"fixed64,4,opt,name=value_change_24h,json=valueChange24h,proto3" json:"value_change_24h,omitempty"`
+	ValueChangePercent_24H float64                `protobuf:"fixed64,5,opt,name=value_change_percent_24h,json=valueChangePercent24h,proto3" json:"value_change_percent_24h,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
-func (x *SearchCryptoResponse) Reset() {
-	*x = SearchCryptoResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[33]
+func (x *AccountPortfolio) Reset() {
+	*x = AccountPortfolio{}
+	mi := &file_proto_chain_service_proto_msgTypes[82]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SearchCryptoResponse) String() string {
+func (x *AccountPortfolio) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SearchCryptoResponse) ProtoMessage() {}
+func (*AccountPortfolio) ProtoMessage() {}
 
-func (x *SearchCryptoResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[33]
+func (x *AccountPortfolio) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[82]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -1983,55 +6226,69 @@ func (x *SearchCryptoResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SearchCryptoResponse.ProtoReflect.Descriptor instead.
-func (*SearchCryptoResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{33}
+// Deprecated: Use AccountPortfolio.ProtoReflect.Descriptor instead.
+func (*AccountPortfolio) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{82}
 }
 
-func (x *SearchCryptoResponse) GetSuccess() bool {
+func (x *AccountPortfolio) GetAddress() string {
 	if x != nil {
-		return x.Success
+		return x.Address
 	}
-	return false
+	return ""
 }
 
-func (x *SearchCryptoResponse) GetError() string {
+func (x *AccountPortfolio) GetHoldings() []*PortfolioHolding {
 	if x != nil {
-		return x.Error
+		return x.Holdings
 	}
-	return ""
+	return nil
 }
 
-func (x *SearchCryptoResponse) GetResults() []*CryptoPriceInfo {
+func (x *AccountPortfolio) GetTotalValueUsd() float64 {
 	if x != nil {
-		return x.Results
+		return x.TotalValueUsd
 	}
-	return nil
+	return 0
 }
 
-type GetPriceHistoryRequest struct {
+func (x *AccountPortfolio) GetValueChange_24H() float64 {
+	if x != nil {
+		return x.ValueChange_24H
+	}
+	return 0
+}
+
+func (x *AccountPortfolio) GetValueChangePercent_24H() float64 {
+	if x != nil {
+		return x.ValueChangePercent_24H
+	}
+	return 0
+}
+
+type GetPortfolioRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Symbol        string                 `protobuf:"bytes,1,opt,name=symbol,proto3" json:"symbol,omitempty"`
-	Days          int32                  `protobuf:"varint,2,opt,name=days,proto3" json:"days,omitempty"`
+	Addresses     []string               `protobuf:"bytes,1,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Tokens        []string               `protobuf:"bytes,2,rep,name=tokens,proto3" json:"tokens,omitempty"` // 额外查询余额的代币地址
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetPriceHistoryRequest) Reset() {
-	*x = GetPriceHistoryRequest{}
-	mi := &file_proto_chain_service_proto_msgTypes[34]
+func (x *GetPortfolioRequest) Reset() {
+	*x = GetPortfolioRequest{}
+	mi := &file_proto_chain_service_proto_msgTypes[83]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPriceHistoryRequest) String() string {
+func (x *GetPortfolioRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPriceHistoryRequest) ProtoMessage() {}
+func (*GetPortfolioRequest) ProtoMessage() {}
 
-func (x *GetPriceHistoryRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[34]
+func (x *GetPortfolioRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[83]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2042,49 +6299,56 @@ func (x *GetPriceHistoryRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPriceHistoryRequest.ProtoReflect.Descriptor instead.
-func (*GetPriceHistoryRequest) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{34}
+// Deprecated: Use GetPortfolioRequest.ProtoReflect.Descriptor instead.
+func (*GetPortfolioRequest) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{83}
 }
 
-func (x *GetPriceHistoryRequest) GetSymbol() string {
+func (x *GetPortfolioRequest) GetAddresses() []string {
 	if x != nil {
-		return x.Symbol
+		return x.Addresses
 	}
-	return ""
+	return nil
 }
 
-func (x *GetPriceHistoryRequest) GetDays() int32 {
+func (x *GetPortfolioRequest) GetTokens() []string {
 	if x != nil {
-		return x.Days
+		return x.Tokens
 	}
-	return 0
+	return nil
 }
 
-type GetPriceHistoryResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Error         string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
-	Prices        []float64              `protobuf:"fixed64,3,rep,packed,name=prices,proto3" json:"prices,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+type GetPortfolioResponse struct {
+	state                  protoimpl.MessageState `protogen:"open.v1"`
+	Success                bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Error                  string                 `protobuf:"bytes,2,opt,name=error,proto3" json:"error,omitempty"`
+	Addresses              []string               `protobuf:"bytes,3,rep,name=addresses,proto3" json:"addresses,omitempty"`
+	Holdings               []*PortfolioHolding    `protobuf:"bytes,4,rep,name=holdings,proto3" json:"holdings,omitempty"` // 所有地址按资产合计
+	Accounts               []*AccountPortfolio    `protobuf:"bytes,5,rep,name=accounts,proto3" json:"accounts,omitempty"`
+	TotalValueUsd          float64                `protobuf:"fixed64,6,opt,name=total_value_usd,json=totalValueUsd,proto3" json:"total_value_usd,omitempty"`
+	ValueChange_24H        float64                `protobuf:"fixed64,7,opt,name=value_change_24h,json=valueChange24h,proto3" json:"value_change_24h,omitempty"`
+	ValueChangePercent_24H float64                `protobuf:"fixed64,8,opt,name=value_change_percent_24h,json=valueChangePercent24h,proto3" json:"value_change_percent_24h,omitempty"`
+	Warnings               []string               `protobuf:"bytes,9,rep,name=warnings,proto3" json:"warnings,omitempty"`
+	UpdatedAt              int64                  `protobuf:"varint,10,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	unknownFields          protoimpl.UnknownFields
+	sizeCache              protoimpl.SizeCache
 }
 
-func (x *GetPriceHistoryResponse) Reset() {
-	*x = GetPriceHistoryResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[35]
+func (x *GetPortfolioResponse) Reset() {
+	*x = GetPortfolioResponse{}
+	mi := &file_proto_chain_service_proto_msgTypes[84]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetPriceHistoryResponse) String() string {
+func (x *GetPortfolioResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetPriceHistoryResponse) ProtoMessage() {}
+func (*GetPortfolioResponse) ProtoMessage() {}
 
-func (x *GetPriceHistoryResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[35]
+func (x *GetPortfolioResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_chain_service_proto_msgTypes[84]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -2095,90 +6359,79 @@ func (x *GetPriceHistoryResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetPriceHistoryResponse.ProtoReflect.Descriptor instead.
-func (*GetPriceHistoryResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{35}
+// Deprecated: Use GetPortfolioResponse.ProtoReflect.Descriptor instead.
+func (*GetPortfolioResponse) Descriptor() ([]byte, []int) {
+	return file_proto_chain_service_proto_rawDescGZIP(), []int{84}
 }
 
-func (x *GetPriceHistoryResponse) GetSuccess() bool {
+func (x *GetPortfolioResponse) GetSuccess() bool {
 	if x != nil {
 		return x.Success
 	}
 	return false
 }
 
-func (x *GetPriceHistoryResponse) GetError() string {
+func (x *GetPortfolioResponse) GetError() string {
 	if x != nil {
 		return x.Error
 	}
 	return ""
 }
 
-func (x *GetPriceHistoryResponse) GetPrices() []float64 {
+func (x *GetPortfolioResponse) GetAddresses() []string {
 	if x != nil {
-		return x.Prices
+		return x.Addresses
 	}
 	return nil
 }
 
-type GetLiquidityPoolResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Pool          *LiquidityPool         `protobuf:"bytes,1,opt,name=pool,proto3" json:"pool,omitempty"`
-	Success       bool                   `protobuf:"varint,2,opt,name=success,proto3" json:"success,omitempty"`
-	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *GetLiquidityPoolResponse) Reset() {
-	*x = GetLiquidityPoolResponse{}
-	mi := &file_proto_chain_service_proto_msgTypes[36]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *GetPortfolioResponse) GetHoldings() []*PortfolioHolding {
+	if x != nil {
+		return x.Holdings
+	}
+	return nil
 }
 
-func (x *GetLiquidityPoolResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *GetPortfolioResponse) GetAccounts() []*AccountPortfolio {
+	if x != nil {
+		return x.Accounts
+	}
+	return nil
 }
 
-func (*GetLiquidityPoolResponse) ProtoMessage() {}
-
-func (x *GetLiquidityPoolResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_chain_service_proto_msgTypes[36]
+func (x *GetPortfolioResponse) GetTotalValueUsd() float64 {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.TotalValueUsd
 	}
-	return mi.MessageOf(x)
+	return 0
 }
 
-// Deprecated: Use GetLiquidityPoolResponse.ProtoReflect.Descriptor instead.
-func (*GetLiquidityPoolResponse) Descriptor() ([]byte, []int) {
-	return file_proto_chain_service_proto_rawDescGZIP(), []int{36}
+func (x *GetPortfolioResponse) GetValueChange_24H() float64 {
+	if x != nil {
+		return x.ValueChange_24H
+	}
+	return 0
 }
 
-func (x *GetLiquidityPoolResponse) GetPool() *LiquidityPool {
+func (x *GetPortfolioResponse) GetValueChangePercent_24H() float64 {
 	if x != nil {
-		return x.Pool
+		return x.ValueChangePercent_24H
 	}
-	return nil
+	return 0
 }
 
-func (x *GetLiquidityPoolResponse) GetSuccess() bool {
+func (x *GetPortfolioResponse) GetWarnings() []string {
 	if x != nil {
-		return x.Success
+		return x.Warnings
 	}
-	return false
+	return nil
 }
 
-func (x *GetLiquidityPoolResponse) GetError() string {
+func (x *GetPortfolioResponse) GetUpdatedAt() int64 {
 	if x != nil {
-		return x.Error
+		return x.UpdatedAt
 	}
-	return ""
+	return 0
 }
 
 var File_proto_chain_service_proto protoreflect.FileDescriptor
@@ -2196,7 +6449,16 @@ const file_proto_chain_service_proto_rawDesc = "" +
 	"\abalance\x18\x01 \x01(\tR\abalance\x12\x18\n" +
 	"\aaddress\x18\x02 \x01(\tR\aaddress\x12\x18\n" +
 	"\asuccess\x18\x03 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x04 \x01(\tR\x05error\"9\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error\"2\n" +
+	"\x12GetBalancesRequest\x12\x1c\n" +
+	"\taddresses\x18\x01 \x03(\tR\taddresses\"D\n" +
+	"\x0eAccountBalance\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x18\n" +
+	"\abalance\x18\x02 \x01(\tR\abalance\"x\n" +
+	"\x13GetBalancesResponse\x121\n" +
+	"\bbalances\x18\x01 \x03(\v2\x15.chain.AccountBalanceR\bbalances\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"9\n" +
 	"\x0fTransferRequest\x12\x0e\n" +
 	"\x02to\x18\x01 \x01(\tR\x02to\x12\x16\n" +
 	"\x06amount\x18\x02 \x01(\tR\x06amount\"m\n" +
@@ -2235,34 +6497,64 @@ const file_proto_chain_service_proto_rawDesc = "" +
 	"\asuccess\x18\x03 \x01(\bR\asuccess\x12\x14\n" +
 	"\x05error\x18\x04 \x01(\tR\x05error\"/\n" +
 	"\x13GetTokenInfoRequest\x12\x18\n" +
-	"\aaddress\x18\x01 \x01(\tR\aaddress\"\x90\x01\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\"\xb4\x02\n" +
 	"\tTokenInfo\x12\x18\n" +
 	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
 	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12\x1a\n" +
 	"\bdecimals\x18\x04 \x01(\rR\bdecimals\x12!\n" +
-	"\ftotal_supply\x18\x05 \x01(\tR\vtotalSupply\"n\n" +
+	"\ftotal_supply\x18\x05 \x01(\tR\vtotalSupply\x12\x19\n" +
+	"\blogo_uri\x18\x06 \x01(\tR\alogoUri\x12\x1a\n" +
+	"\bverified\x18\a \x01(\bR\bverified\x12(\n" +
+	"\x10total_supply_raw\x18\b \x01(\tR\x0etotalSupplyRaw\x12\x19\n" +
+	"\bis_proxy\x18\t \x01(\bR\aisProxy\x12&\n" +
+	"\x0eimplementation\x18\n" +
+	" \x01(\tR\x0eimplementation\"n\n" +
 	"\x14GetTokenInfoResponse\x12&\n" +
 	"\x05token\x18\x01 \x01(\v2\x10.chain.TokenInfoR\x05token\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x03 \x01(\tR\x05error\"(\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"{\n" +
 	"\x12SearchTokenRequest\x12\x12\n" +
-	"\x04name\x18\x01 \x01(\tR\x04name\"o\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06prefix\x18\x02 \x01(\bR\x06prefix\x12#\n" +
+	"\rverified_only\x18\x03 \x01(\bR\fverifiedOnly\x12\x14\n" +
+	"\x05limit\x18\x04 \x01(\rR\x05limit\"o\n" +
 	"\x13SearchTokenResponse\x12(\n" +
 	"\x06tokens\x18\x01 \x03(\v2\x10.chain.TokenInfoR\x06tokens\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x03 \x01(\tR\x05error\"O\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"*\n" +
+	"\x16ImportTokenListRequest\x12\x10\n" +
+	"\x03url\x18\x01 \x01(\tR\x03url\"\x93\x01\n" +
+	"\x17ImportTokenListResponse\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x1a\n" +
+	"\bimported\x18\x02 \x01(\rR\bimported\x12\x18\n" +
+	"\askipped\x18\x03 \x01(\rR\askipped\x12\x18\n" +
+	"\asuccess\x18\x04 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\"O\n" +
 	"\x14GetTokenPriceRequest\x12\x18\n" +
 	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x1d\n" +
 	"\n" +
-	"token_name\x18\x02 \x01(\tR\ttokenName\"\x8c\x01\n" +
+	"token_name\x18\x02 \x01(\tR\ttokenName\"\xea\x03\n" +
 	"\n" +
 	"TokenPrice\x12\x18\n" +
 	"\aaddress\x18\x01 \x01(\tR\aaddress\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
 	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12\x1b\n" +
 	"\tprice_usd\x18\x04 \x01(\tR\bpriceUsd\x12\x1b\n" +
-	"\tprice_bnb\x18\x05 \x01(\tR\bpriceBnb\"p\n" +
+	"\tprice_bnb\x18\x05 \x01(\tR\bpriceBnb\x12\x1a\n" +
+	"\bdecimals\x18\x06 \x01(\rR\bdecimals\x12\"\n" +
+	"\rprice_usd_raw\x18\a \x01(\tR\vpriceUsdRaw\x12\"\n" +
+	"\rprice_bnb_raw\x18\b \x01(\tR\vpriceBnbRaw\x12\x14\n" +
+	"\x05route\x18\t \x03(\tR\x05route\x12\x1f\n" +
+	"\vroute_pools\x18\n" +
+	" \x03(\tR\n" +
+	"routePools\x12\x1d\n" +
+	"\n" +
+	"volume_24h\x18\v \x01(\tR\tvolume24h\x12(\n" +
+	"\x10price_change_24h\x18\f \x01(\tR\x0epriceChange24h\x12(\n" +
+	"\x10oracle_price_usd\x18\r \x01(\tR\x0eoraclePriceUsd\x12'\n" +
+	"\x0fprice_deviation\x18\x0e \x01(\tR\x0epriceDeviation\x12%\n" +
+	"\x0eprice_deviated\x18\x0f \x01(\bR\rpriceDeviated\"p\n" +
 	"\x15GetTokenPriceResponse\x12'\n" +
 	"\x05price\x18\x01 \x01(\v2\x11.chain.TokenPriceR\x05price\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
@@ -2278,12 +6570,213 @@ const file_proto_chain_service_proto_rawDesc = "" +
 	"\x05error\x18\x03 \x01(\tR\x05error\"I\n" +
 	"\x17GetLiquidityPoolRequest\x12\x16\n" +
 	"\x06token0\x18\x01 \x01(\tR\x06token0\x12\x16\n" +
-	"\x06token1\x18\x02 \x01(\tR\x06token1\"\x8b\x01\n" +
+	"\x06token1\x18\x02 \x01(\tR\x06token1\"\xb2\x03\n" +
 	"\rLiquidityPool\x12!\n" +
 	"\fpair_address\x18\x01 \x01(\tR\vpairAddress\x12\x16\n" +
 	"\x06token0\x18\x02 \x01(\tR\x06token0\x12\x16\n" +
 	"\x06token1\x18\x03 \x01(\tR\x06token1\x12'\n" +
-	"\x0ftotal_liquidity\x18\x04 \x01(\tR\x0etotalLiquidity\"\xa6\x02\n" +
+	"\x0ftotal_liquidity\x18\x04 \x01(\tR\x0etotalLiquidity\x12(\n" +
+	"\bv3_pools\x18\x05 \x03(\v2\r.chain.V3PoolR\av3Pools\x12.\n" +
+	"\breserve0\x18\x06 \x01(\v2\x12.chain.PoolReserveR\breserve0\x12.\n" +
+	"\breserve1\x18\a \x01(\v2\x12.chain.PoolReserveR\breserve1\x12\x17\n" +
+	"\atvl_usd\x18\b \x01(\tR\x06tvlUsd\x12&\n" +
+	"\x0flp_total_supply\x18\t \x01(\tR\rlpTotalSupply\x12-\n" +
+	"\x13lp_total_supply_raw\x18\n" +
+	" \x01(\tR\x10lpTotalSupplyRaw\x12+\n" +
+	"\x12lp_token_price_usd\x18\v \x01(\tR\x0flpTokenPriceUsd\"\xc8\x01\n" +
+	"\vPoolReserve\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x1a\n" +
+	"\bdecimals\x18\x03 \x01(\rR\bdecimals\x12\x16\n" +
+	"\x06amount\x18\x04 \x01(\tR\x06amount\x12\x1d\n" +
+	"\n" +
+	"amount_raw\x18\x05 \x01(\tR\tamountRaw\x12\x1b\n" +
+	"\tprice_usd\x18\x06 \x01(\tR\bpriceUsd\x12\x1b\n" +
+	"\tvalue_usd\x18\a \x01(\tR\bvalueUsd\"\xbe\x02\n" +
+	"\x06V3Pool\x12\x10\n" +
+	"\x03dex\x18\x01 \x01(\tR\x03dex\x12!\n" +
+	"\fpool_address\x18\x02 \x01(\tR\vpoolAddress\x12\x16\n" +
+	"\x06token0\x18\x03 \x01(\tR\x06token0\x12\x16\n" +
+	"\x06token1\x18\x04 \x01(\tR\x06token1\x12\x10\n" +
+	"\x03fee\x18\x05 \x01(\rR\x03fee\x12$\n" +
+	"\x0esqrt_price_x96\x18\x06 \x01(\tR\fsqrtPriceX96\x12\x12\n" +
+	"\x04tick\x18\a \x01(\x03R\x04tick\x12\x1c\n" +
+	"\tliquidity\x18\b \x01(\tR\tliquidity\x12\x14\n" +
+	"\x05price\x18\t \x01(\tR\x05price\x12\x1a\n" +
+	"\breserve0\x18\n" +
+	" \x01(\tR\breserve0\x12\x1a\n" +
+	"\breserve1\x18\v \x01(\tR\breserve1\x12\x17\n" +
+	"\atvl_usd\x18\f \x01(\tR\x06tvlUsd\"\x86\x01\n" +
+	"\x11QuoteTradeRequest\x12\x19\n" +
+	"\btoken_in\x18\x01 \x01(\tR\atokenIn\x12\x1b\n" +
+	"\ttoken_out\x18\x02 \x01(\tR\btokenOut\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\x12!\n" +
+	"\fslippage_bps\x18\x04 \x01(\rR\vslippageBps\"\xec\x03\n" +
+	"\n" +
+	"TradeQuote\x12\x19\n" +
+	"\btoken_in\x18\x01 \x01(\tR\atokenIn\x12\x1b\n" +
+	"\ttoken_out\x18\x02 \x01(\tR\btokenOut\x12\x1b\n" +
+	"\tamount_in\x18\x03 \x01(\tR\bamountIn\x12\"\n" +
+	"\ramount_in_raw\x18\x04 \x01(\tR\vamountInRaw\x12\x1d\n" +
+	"\n" +
+	"amount_out\x18\x05 \x01(\tR\tamountOut\x12$\n" +
+	"\x0eamount_out_raw\x18\x06 \x01(\tR\famountOutRaw\x12'\n" +
+	"\x0fexecution_price\x18\a \x01(\tR\x0eexecutionPrice\x12\x1d\n" +
+	"\n" +
+	"spot_price\x18\b \x01(\tR\tspotPrice\x12!\n" +
+	"\fprice_impact\x18\t \x01(\tR\vpriceImpact\x12!\n" +
+	"\fslippage_bps\x18\n" +
+	" \x01(\rR\vslippageBps\x12)\n" +
+	"\x10minimum_received\x18\v \x01(\tR\x0fminimumReceived\x120\n" +
+	"\x14minimum_received_raw\x18\f \x01(\tR\x12minimumReceivedRaw\x12\x14\n" +
+	"\x05route\x18\r \x03(\tR\x05route\x12\x1f\n" +
+	"\vroute_pools\x18\x0e \x03(\tR\n" +
+	"routePools\"m\n" +
+	"\x12QuoteTradeResponse\x12'\n" +
+	"\x05quote\x18\x01 \x01(\v2\x11.chain.TradeQuoteR\x05quote\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x8e\x02\n" +
+	"\vSwapRequest\x12\x19\n" +
+	"\btoken_in\x18\x01 \x01(\tR\atokenIn\x12\x1b\n" +
+	"\ttoken_out\x18\x02 \x01(\tR\btokenOut\x12\x16\n" +
+	"\x06amount\x18\x03 \x01(\tR\x06amount\x12\x1b\n" +
+	"\texact_out\x18\x04 \x01(\bR\bexactOut\x12!\n" +
+	"\fslippage_bps\x18\x05 \x01(\rR\vslippageBps\x12)\n" +
+	"\x10deadline_seconds\x18\x06 \x01(\x03R\x0fdeadlineSeconds\x12\x1c\n" +
+	"\trecipient\x18\a \x01(\tR\trecipient\x12&\n" +
+	"\x0ffee_on_transfer\x18\b \x01(\bR\rfeeOnTransfer\"\xac\x04\n" +
+	"\n" +
+	"SwapResult\x12\x17\n" +
+	"\atx_hash\x18\x01 \x01(\tR\x06txHash\x12&\n" +
+	"\x0fapprove_tx_hash\x18\x02 \x01(\tR\rapproveTxHash\x12\x16\n" +
+	"\x06method\x18\x03 \x01(\tR\x06method\x12!\n" +
+	"\fblock_number\x18\x04 \x01(\x04R\vblockNumber\x12\x19\n" +
+	"\bgas_used\x18\x05 \x01(\x04R\agasUsed\x12\x19\n" +
+	"\btoken_in\x18\x06 \x01(\tR\atokenIn\x12\x1b\n" +
+	"\ttoken_out\x18\a \x01(\tR\btokenOut\x12\x1c\n" +
+	"\trecipient\x18\b \x01(\tR\trecipient\x12\x14\n" +
+	"\x05route\x18\t \x03(\tR\x05route\x12\x1b\n" +
+	"\tamount_in\x18\n" +
+	" \x01(\tR\bamountIn\x12\"\n" +
+	"\ramount_in_raw\x18\v \x01(\tR\vamountInRaw\x12\x1d\n" +
+	"\n" +
+	"amount_out\x18\f \x01(\tR\tamountOut\x12$\n" +
+	"\x0eamount_out_raw\x18\r \x01(\tR\famountOutRaw\x12+\n" +
+	"\x12amount_out_min_raw\x18\x0e \x01(\tR\x0famountOutMinRaw\x12)\n" +
+	"\x11amount_in_max_raw\x18\x0f \x01(\tR\x0eamountInMaxRaw\x12!\n" +
+	"\fslippage_bps\x18\x10 \x01(\rR\vslippageBps\x12\x1a\n" +
+	"\bdeadline\x18\x11 \x01(\x03R\bdeadline\"i\n" +
+	"\fSwapResponse\x12)\n" +
+	"\x06result\x18\x01 \x01(\v2\x11.chain.SwapResultR\x06result\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x82\x02\n" +
+	"\aDexPair\x12\x10\n" +
+	"\x03dex\x18\x01 \x01(\tR\x03dex\x12\x18\n" +
+	"\afactory\x18\x02 \x01(\tR\afactory\x12!\n" +
+	"\fpair_address\x18\x03 \x01(\tR\vpairAddress\x12\x16\n" +
+	"\x06token0\x18\x04 \x01(\tR\x06token0\x12\x16\n" +
+	"\x06token1\x18\x05 \x01(\tR\x06token1\x12\x1d\n" +
+	"\n" +
+	"pair_index\x18\x06 \x01(\x04R\tpairIndex\x12!\n" +
+	"\fblock_number\x18\a \x01(\x04R\vblockNumber\x12\x1d\n" +
+	"\n" +
+	"block_time\x18\b \x01(\x03R\tblockTime\x12\x17\n" +
+	"\atx_hash\x18\t \x01(\tR\x06txHash\",\n" +
+	"\x14GetTokenPairsRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\"m\n" +
+	"\x15GetTokenPairsResponse\x12$\n" +
+	"\x05pairs\x18\x01 \x03(\v2\x0e.chain.DexPairR\x05pairs\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"1\n" +
+	"\x15GetRecentPairsRequest\x12\x18\n" +
+	"\aminutes\x18\x01 \x01(\rR\aminutes\"n\n" +
+	"\x16GetRecentPairsResponse\x12$\n" +
+	"\x05pairs\x18\x01 \x03(\v2\x0e.chain.DexPairR\x05pairs\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"?\n" +
+	"\x15StreamNewPairsRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x10\n" +
+	"\x03dex\x18\x02 \x01(\tR\x03dex\"Y\n" +
+	"\x17AnalyzeTokenRiskRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x16\n" +
+	"\x06amount\x18\x02 \x01(\tR\x06amount\x12\x10\n" +
+	"\x03abi\x18\x03 \x01(\tR\x03abi\"\xbb\x05\n" +
+	"\x0fTokenRiskReport\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x16\n" +
+	"\x06symbol\x18\x03 \x01(\tR\x06symbol\x12\x14\n" +
+	"\x05route\x18\x04 \x03(\tR\x05route\x12\x1d\n" +
+	"\n" +
+	"buy_amount\x18\x05 \x01(\tR\tbuyAmount\x12\x1f\n" +
+	"\vis_honeypot\x18\x06 \x01(\bR\n" +
+	"isHoneypot\x12\x17\n" +
+	"\acan_buy\x18\a \x01(\bR\x06canBuy\x12\x19\n" +
+	"\bcan_sell\x18\b \x01(\bR\acanSell\x12\x1b\n" +
+	"\tbuy_error\x18\t \x01(\tR\bbuyError\x12\x1d\n" +
+	"\n" +
+	"sell_error\x18\n" +
+	" \x01(\tR\tsellError\x12\x17\n" +
+	"\abuy_tax\x18\v \x01(\tR\x06buyTax\x12\x19\n" +
+	"\bsell_tax\x18\f \x01(\tR\asellTax\x12!\n" +
+	"\ftransfer_tax\x18\r \x01(\tR\vtransferTax\x12\x17\n" +
+	"\abuy_gas\x18\x0e \x01(\x04R\x06buyGas\x12\x19\n" +
+	"\bsell_gas\x18\x0f \x01(\x04R\asellGas\x12\"\n" +
+	"\rmax_tx_amount\x18\x10 \x01(\tR\vmaxTxAmount\x12*\n" +
+	"\x11max_wallet_amount\x18\x11 \x01(\tR\x0fmaxWalletAmount\x12\x14\n" +
+	"\x05owner\x18\x12 \x01(\tR\x05owner\x12/\n" +
+	"\x13ownership_renounced\x18\x13 \x01(\bR\x12ownershipRenounced\x12\x1e\n" +
+	"\n" +
+	"privileges\x18\x14 \x03(\tR\n" +
+	"privileges\x12)\n" +
+	"\x10privilege_source\x18\x15 \x01(\tR\x0fprivilegeSource\x12\x1d\n" +
+	"\n" +
+	"risk_level\x18\x16 \x01(\tR\triskLevel\x12\x14\n" +
+	"\x05risks\x18\x17 \x03(\tR\x05risks\"z\n" +
+	"\x18AnalyzeTokenRiskResponse\x12.\n" +
+	"\x06report\x18\x01 \x01(\v2\x16.chain.TokenRiskReportR\x06report\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"K\n" +
+	"\x0eGetTWAPRequest\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12%\n" +
+	"\x0ewindow_seconds\x18\x02 \x01(\x03R\rwindowSeconds\"\xd9\x01\n" +
+	"\tTWAPPrice\x12\x12\n" +
+	"\x04pool\x18\x01 \x01(\tR\x04pool\x12\x16\n" +
+	"\x06token0\x18\x02 \x01(\tR\x06token0\x12\x16\n" +
+	"\x06token1\x18\x03 \x01(\tR\x06token1\x12\x16\n" +
+	"\x06price0\x18\x04 \x01(\tR\x06price0\x12\x16\n" +
+	"\x06price1\x18\x05 \x01(\tR\x06price1\x12!\n" +
+	"\fwindow_start\x18\x06 \x01(\x03R\vwindowStart\x12\x1d\n" +
+	"\n" +
+	"window_end\x18\a \x01(\x03R\twindowEnd\x12\x16\n" +
+	"\x06source\x18\b \x01(\tR\x06source\"i\n" +
+	"\x0fGetTWAPResponse\x12&\n" +
+	"\x05price\x18\x01 \x01(\v2\x10.chain.TWAPPriceR\x05price\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"n\n" +
+	"\x16GetTokenCandlesRequest\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x1a\n" +
+	"\binterval\x18\x02 \x01(\tR\binterval\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\"\xcc\x02\n" +
+	"\x06Candle\x12\x1b\n" +
+	"\topen_time\x18\x01 \x01(\x03R\bopenTime\x12\x19\n" +
+	"\bopen_usd\x18\x02 \x01(\tR\aopenUsd\x12\x19\n" +
+	"\bhigh_usd\x18\x03 \x01(\tR\ahighUsd\x12\x17\n" +
+	"\alow_usd\x18\x04 \x01(\tR\x06lowUsd\x12\x1b\n" +
+	"\tclose_usd\x18\x05 \x01(\tR\bcloseUsd\x12\x19\n" +
+	"\bopen_bnb\x18\x06 \x01(\tR\aopenBnb\x12\x19\n" +
+	"\bhigh_bnb\x18\a \x01(\tR\ahighBnb\x12\x17\n" +
+	"\alow_bnb\x18\b \x01(\tR\x06lowBnb\x12\x1b\n" +
+	"\tclose_bnb\x18\t \x01(\tR\bcloseBnb\x12\x16\n" +
+	"\x06volume\x18\n" +
+	" \x01(\tR\x06volume\x12\x1d\n" +
+	"\n" +
+	"volume_usd\x18\v \x01(\tR\tvolumeUsd\x12\x16\n" +
+	"\x06trades\x18\f \x01(\x05R\x06trades\"r\n" +
+	"\x17GetTokenCandlesResponse\x12'\n" +
+	"\acandles\x18\x01 \x03(\v2\r.chain.CandleR\acandles\x12\x18\n" +
+	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\xfb\x03\n" +
 	"\x0fCryptoPriceInfo\x12\x16\n" +
 	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
 	"\x04name\x18\x02 \x01(\tR\x04name\x12#\n" +
@@ -2294,67 +6787,269 @@ const file_proto_chain_service_proto_rawDesc = "" +
 	"volume_24h\x18\x05 \x01(\x01R\tvolume24h\x12(\n" +
 	"\x10price_change_24h\x18\x06 \x01(\x01R\x0epriceChange24h\x127\n" +
 	"\x18price_change_percent_24h\x18\a \x01(\x01R\x15priceChangePercent24h\x12!\n" +
-	"\flast_updated\x18\b \x01(\tR\vlastUpdated\"/\n" +
+	"\flast_updated\x18\b \x01(\tR\vlastUpdated\x12\x16\n" +
+	"\x06source\x18\t \x01(\tR\x06source\x12\x18\n" +
+	"\asources\x18\n" +
+	" \x03(\tR\asources\x12\x17\n" +
+	"\acoin_id\x18\v \x01(\tR\x06coinId\x12:\n" +
+	"\x06quotes\x18\f \x03(\v2\".chain.CryptoPriceInfo.QuotesEntryR\x06quotes\x1aL\n" +
+	"\vQuotesEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12'\n" +
+	"\x05value\x18\x02 \x01(\v2\x11.chain.PriceQuoteR\x05value:\x028\x01\"\xe1\x01\n" +
+	"\n" +
+	"PriceQuote\x12\x14\n" +
+	"\x05price\x18\x01 \x01(\x01R\x05price\x12\x1d\n" +
+	"\n" +
+	"market_cap\x18\x02 \x01(\x01R\tmarketCap\x12\x1d\n" +
+	"\n" +
+	"volume_24h\x18\x03 \x01(\x01R\tvolume24h\x12(\n" +
+	"\x10price_change_24h\x18\x04 \x01(\x01R\x0epriceChange24h\x127\n" +
+	"\x18price_change_percent_24h\x18\x05 \x01(\x01R\x15priceChangePercent24h\x12\x1c\n" +
+	"\tconverted\x18\x06 \x01(\bR\tconverted\"O\n" +
 	"\x15GetCryptoPriceRequest\x12\x16\n" +
-	"\x06symbol\x18\x01 \x01(\tR\x06symbol\"v\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x02 \x03(\tR\n" +
+	"currencies\"v\n" +
 	"\x16GetCryptoPriceResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
 	"\x05error\x18\x02 \x01(\tR\x05error\x12,\n" +
-	"\x05price\x18\x03 \x01(\v2\x16.chain.CryptoPriceInfoR\x05price\":\n" +
+	"\x05price\x18\x03 \x01(\v2\x16.chain.CryptoPriceInfoR\x05price\"Z\n" +
 	"\x1eGetMultipleCryptoPricesRequest\x12\x18\n" +
-	"\asymbols\x18\x01 \x03(\tR\asymbols\"\xf0\x01\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x02 \x03(\tR\n" +
+	"currencies\"\xf0\x01\n" +
 	"\x1fGetMultipleCryptoPricesResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
 	"\x05error\x18\x02 \x01(\tR\x05error\x12J\n" +
 	"\x06prices\x18\x03 \x03(\v22.chain.GetMultipleCryptoPricesResponse.PricesEntryR\x06prices\x1aQ\n" +
 	"\vPricesEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12,\n" +
-	"\x05value\x18\x02 \x01(\v2\x16.chain.CryptoPriceInfoR\x05value:\x028\x01\"1\n" +
+	"\x05value\x18\x02 \x01(\v2\x16.chain.CryptoPriceInfoR\x05value:\x028\x01\"Q\n" +
 	"\x19GetTopCryptoPricesRequest\x12\x14\n" +
-	"\x05limit\x18\x01 \x01(\x05R\x05limit\"|\n" +
+	"\x05limit\x18\x01 \x01(\x05R\x05limit\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x02 \x03(\tR\n" +
+	"currencies\"|\n" +
 	"\x1aGetTopCryptoPricesResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
 	"\x05error\x18\x02 \x01(\tR\x05error\x12.\n" +
-	"\x06prices\x18\x03 \x03(\v2\x16.chain.CryptoPriceInfoR\x06prices\"+\n" +
+	"\x06prices\x18\x03 \x03(\v2\x16.chain.CryptoPriceInfoR\x06prices\"K\n" +
 	"\x13SearchCryptoRequest\x12\x14\n" +
-	"\x05query\x18\x01 \x01(\tR\x05query\"x\n" +
+	"\x05query\x18\x01 \x01(\tR\x05query\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x02 \x03(\tR\n" +
+	"currencies\"x\n" +
 	"\x14SearchCryptoResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
 	"\x05error\x18\x02 \x01(\tR\x05error\x120\n" +
-	"\aresults\x18\x03 \x03(\v2\x16.chain.CryptoPriceInfoR\aresults\"D\n" +
+	"\aresults\x18\x03 \x03(\v2\x16.chain.CryptoPriceInfoR\aresults\"\xa0\x01\n" +
 	"\x16GetPriceHistoryRequest\x12\x16\n" +
 	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12\x12\n" +
-	"\x04days\x18\x02 \x01(\x05R\x04days\"a\n" +
+	"\x04days\x18\x02 \x01(\x05R\x04days\x12\x12\n" +
+	"\x04from\x18\x03 \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\x04 \x01(\x03R\x02to\x12\x1a\n" +
+	"\binterval\x18\x05 \x01(\tR\binterval\x12\x1a\n" +
+	"\bcurrency\x18\x06 \x01(\tR\bcurrency\"w\n" +
+	"\n" +
+	"PricePoint\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x14\n" +
+	"\x05price\x18\x02 \x01(\x01R\x05price\x12\x1d\n" +
+	"\n" +
+	"market_cap\x18\x03 \x01(\x01R\tmarketCap\x12\x16\n" +
+	"\x06volume\x18\x04 \x01(\x01R\x06volume\"\xb9\x01\n" +
+	"\x12PriceHistoryCandle\x12\x1c\n" +
+	"\ttimestamp\x18\x01 \x01(\x03R\ttimestamp\x12\x12\n" +
+	"\x04open\x18\x02 \x01(\x01R\x04open\x12\x12\n" +
+	"\x04high\x18\x03 \x01(\x01R\x04high\x12\x10\n" +
+	"\x03low\x18\x04 \x01(\x01R\x03low\x12\x14\n" +
+	"\x05close\x18\x05 \x01(\x01R\x05close\x12\x16\n" +
+	"\x06volume\x18\x06 \x01(\x01R\x06volume\x12\x1d\n" +
+	"\n" +
+	"market_cap\x18\a \x01(\x01R\tmarketCap\"\x86\x03\n" +
 	"\x17GetPriceHistoryResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
 	"\x05error\x18\x02 \x01(\tR\x05error\x12\x16\n" +
-	"\x06prices\x18\x03 \x03(\x01R\x06prices\"t\n" +
+	"\x06prices\x18\x03 \x03(\x01R\x06prices\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12)\n" +
+	"\x06points\x18\x05 \x03(\v2\x11.chain.PricePointR\x06points\x123\n" +
+	"\acandles\x18\x06 \x03(\v2\x19.chain.PriceHistoryCandleR\acandles\x12\x1a\n" +
+	"\binterval\x18\a \x01(\tR\binterval\x12\x1a\n" +
+	"\bcurrency\x18\b \x01(\tR\bcurrency\x12\x17\n" +
+	"\acoin_id\x18\t \x01(\tR\x06coinId\x12\x12\n" +
+	"\x04from\x18\n" +
+	" \x01(\x03R\x04from\x12\x0e\n" +
+	"\x02to\x18\v \x01(\x03R\x02to\x12\x1c\n" +
+	"\tconverted\x18\f \x01(\bR\tconverted\x12\x18\n" +
+	"\asources\x18\r \x03(\tR\asources\"k\n" +
+	"\x1bSubscribeTokenPricesRequest\x12\x16\n" +
+	"\x06tokens\x18\x01 \x03(\tR\x06tokens\x12\x18\n" +
+	"\aepsilon\x18\x02 \x01(\x01R\aepsilon\x12\x1a\n" +
+	"\binterval\x18\x03 \x01(\x03R\binterval\"\x86\x01\n" +
+	"\x10TokenPriceUpdate\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12'\n" +
+	"\x05price\x18\x02 \x01(\v2\x11.chain.TokenPriceR\x05price\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\x03R\tupdatedAt\"\x8e\x01\n" +
+	"\x1cSubscribeCryptoPricesRequest\x12\x18\n" +
+	"\asymbols\x18\x01 \x03(\tR\asymbols\x12\x1e\n" +
+	"\n" +
+	"currencies\x18\x02 \x03(\tR\n" +
+	"currencies\x12\x18\n" +
+	"\aepsilon\x18\x03 \x01(\x01R\aepsilon\x12\x1a\n" +
+	"\binterval\x18\x04 \x01(\x03R\binterval\"\x8e\x01\n" +
+	"\x11CryptoPriceUpdate\x12\x16\n" +
+	"\x06symbol\x18\x01 \x01(\tR\x06symbol\x12,\n" +
+	"\x05price\x18\x02 \x01(\v2\x16.chain.CryptoPriceInfoR\x05price\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x04 \x01(\x03R\tupdatedAt\"t\n" +
 	"\x18GetLiquidityPoolResponse\x12(\n" +
 	"\x04pool\x18\x01 \x01(\v2\x14.chain.LiquidityPoolR\x04pool\x12\x18\n" +
 	"\asuccess\x18\x02 \x01(\bR\asuccess\x12\x14\n" +
-	"\x05error\x18\x03 \x01(\tR\x05error2\xf5\x02\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\"\x83\x03\n" +
+	"\tAlertRule\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05asset\x18\x03 \x01(\tR\x05asset\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x1c\n" +
+	"\tcondition\x18\x05 \x01(\tR\tcondition\x12\x1c\n" +
+	"\tthreshold\x18\x06 \x01(\x01R\tthreshold\x12\x16\n" +
+	"\x06window\x18\a \x01(\x03R\x06window\x12\x1a\n" +
+	"\bcooldown\x18\b \x01(\x03R\bcooldown\x12\x18\n" +
+	"\achannel\x18\t \x01(\tR\achannel\x12\x16\n" +
+	"\x06target\x18\n" +
+	" \x01(\tR\x06target\x12\x18\n" +
+	"\aenabled\x18\v \x01(\bR\aenabled\x12*\n" +
+	"\x11last_triggered_at\x18\f \x01(\x03R\x0flastTriggeredAt\x12\x1d\n" +
+	"\n" +
+	"created_at\x18\r \x01(\x03R\tcreatedAt\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\x0e \x01(\x03R\tupdatedAt\"\x98\x02\n" +
+	"\x16CreateAlertRuleRequest\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x14\n" +
+	"\x05asset\x18\x02 \x01(\tR\x05asset\x12\x16\n" +
+	"\x06source\x18\x03 \x01(\tR\x06source\x12\x1c\n" +
+	"\tcondition\x18\x04 \x01(\tR\tcondition\x12\x1c\n" +
+	"\tthreshold\x18\x05 \x01(\x01R\tthreshold\x12\x16\n" +
+	"\x06window\x18\x06 \x01(\x03R\x06window\x12\x1a\n" +
+	"\bcooldown\x18\a \x01(\x03R\bcooldown\x12\x18\n" +
+	"\achannel\x18\b \x01(\tR\achannel\x12\x16\n" +
+	"\x06target\x18\t \x01(\tR\x06target\x12\x1a\n" +
+	"\bdisabled\x18\n" +
+	" \x01(\bR\bdisabled\"\xa8\x02\n" +
+	"\x16UpdateAlertRuleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x14\n" +
+	"\x05asset\x18\x03 \x01(\tR\x05asset\x12\x16\n" +
+	"\x06source\x18\x04 \x01(\tR\x06source\x12\x1c\n" +
+	"\tcondition\x18\x05 \x01(\tR\tcondition\x12\x1c\n" +
+	"\tthreshold\x18\x06 \x01(\x01R\tthreshold\x12\x16\n" +
+	"\x06window\x18\a \x01(\x03R\x06window\x12\x1a\n" +
+	"\bcooldown\x18\b \x01(\x03R\bcooldown\x12\x18\n" +
+	"\achannel\x18\t \x01(\tR\achannel\x12\x16\n" +
+	"\x06target\x18\n" +
+	" \x01(\tR\x06target\x12\x1a\n" +
+	"\bdisabled\x18\v \x01(\bR\bdisabled\"%\n" +
+	"\x13GetAlertRuleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"(\n" +
+	"\x16DeleteAlertRuleRequest\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\x04R\x02id\"\x17\n" +
+	"\x15ListAlertRulesRequest\"i\n" +
+	"\x11AlertRuleResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12$\n" +
+	"\x04rule\x18\x03 \x01(\v2\x10.chain.AlertRuleR\x04rule\"p\n" +
+	"\x16ListAlertRulesResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12&\n" +
+	"\x05rules\x18\x03 \x03(\v2\x10.chain.AlertRuleR\x05rules\"I\n" +
+	"\x17DeleteAlertRuleResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\"\x9d\x03\n" +
+	"\x10PortfolioHolding\x12\x14\n" +
+	"\x05token\x18\x01 \x01(\tR\x05token\x12\x16\n" +
+	"\x06symbol\x18\x02 \x01(\tR\x06symbol\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\x12\x1a\n" +
+	"\bdecimals\x18\x04 \x01(\rR\bdecimals\x12\x16\n" +
+	"\x06native\x18\x05 \x01(\bR\x06native\x12\x18\n" +
+	"\abalance\x18\x06 \x01(\tR\abalance\x12\x1f\n" +
+	"\vbalance_raw\x18\a \x01(\tR\n" +
+	"balanceRaw\x12\x1b\n" +
+	"\tprice_usd\x18\b \x01(\x01R\bpriceUsd\x127\n" +
+	"\x18price_change_percent_24h\x18\t \x01(\x01R\x15priceChangePercent24h\x12\x1b\n" +
+	"\tvalue_usd\x18\n" +
+	" \x01(\x01R\bvalueUsd\x12(\n" +
+	"\x10value_change_24h\x18\v \x01(\x01R\x0evalueChange24h\x12!\n" +
+	"\fprice_source\x18\f \x01(\tR\vpriceSource\x12\x18\n" +
+	"\asources\x18\r \x03(\tR\asources\"\xec\x01\n" +
+	"\x10AccountPortfolio\x12\x18\n" +
+	"\aaddress\x18\x01 \x01(\tR\aaddress\x123\n" +
+	"\bholdings\x18\x02 \x03(\v2\x17.chain.PortfolioHoldingR\bholdings\x12&\n" +
+	"\x0ftotal_value_usd\x18\x03 \x01(\x01R\rtotalValueUsd\x12(\n" +
+	"\x10value_change_24h\x18\x04 \x01(\x01R\x0evalueChange24h\x127\n" +
+	"\x18value_change_percent_24h\x18\x05 \x01(\x01R\x15valueChangePercent24h\"K\n" +
+	"\x13GetPortfolioRequest\x12\x1c\n" +
+	"\taddresses\x18\x01 \x03(\tR\taddresses\x12\x16\n" +
+	"\x06tokens\x18\x02 \x03(\tR\x06tokens\"\x94\x03\n" +
+	"\x14GetPortfolioResponse\x12\x18\n" +
+	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x14\n" +
+	"\x05error\x18\x02 \x01(\tR\x05error\x12\x1c\n" +
+	"\taddresses\x18\x03 \x03(\tR\taddresses\x123\n" +
+	"\bholdings\x18\x04 \x03(\v2\x17.chain.PortfolioHoldingR\bholdings\x123\n" +
+	"\baccounts\x18\x05 \x03(\v2\x17.chain.AccountPortfolioR\baccounts\x12&\n" +
+	"\x0ftotal_value_usd\x18\x06 \x01(\x01R\rtotalValueUsd\x12(\n" +
+	"\x10value_change_24h\x18\a \x01(\x01R\x0evalueChange24h\x127\n" +
+	"\x18value_change_percent_24h\x18\b \x01(\x01R\x15valueChangePercent24h\x12\x1a\n" +
+	"\bwarnings\x18\t \x03(\tR\bwarnings\x12\x1d\n" +
+	"\n" +
+	"updated_at\x18\n" +
+	" \x01(\x03R\tupdatedAt2\xbb\x03\n" +
 	"\fChainService\x12A\n" +
 	"\n" +
-	"GetBalance\x12\x18.chain.GetBalanceRequest\x1a\x19.chain.GetBalanceResponse\x12;\n" +
+	"GetBalance\x12\x18.chain.GetBalanceRequest\x1a\x19.chain.GetBalanceResponse\x12D\n" +
+	"\vGetBalances\x12\x19.chain.GetBalancesRequest\x1a\x1a.chain.GetBalancesResponse\x12;\n" +
 	"\bTransfer\x12\x16.chain.TransferRequest\x1a\x17.chain.TransferResponse\x12M\n" +
 	"\x0eGetTransaction\x12\x1c.chain.GetTransactionRequest\x1a\x1d.chain.GetTransactionResponse\x12G\n" +
 	"\fCallContract\x12\x1a.chain.CallContractRequest\x1a\x1b.chain.CallContractResponse\x12M\n" +
-	"\x0eDeployContract\x12\x1c.chain.DeployContractRequest\x1a\x1d.chain.DeployContractResponse2\xa3\x03\n" +
+	"\x0eDeployContract\x12\x1c.chain.DeployContractRequest\x1a\x1d.chain.DeployContractResponse2\xa7\b\n" +
 	"\n" +
 	"BSCService\x12G\n" +
 	"\fGetTokenInfo\x12\x1a.chain.GetTokenInfoRequest\x1a\x1b.chain.GetTokenInfoResponse\x12D\n" +
-	"\vSearchToken\x12\x19.chain.SearchTokenRequest\x1a\x1a.chain.SearchTokenResponse\x12J\n" +
+	"\vSearchToken\x12\x19.chain.SearchTokenRequest\x1a\x1a.chain.SearchTokenResponse\x12P\n" +
+	"\x0fImportTokenList\x12\x1d.chain.ImportTokenListRequest\x1a\x1e.chain.ImportTokenListResponse\x12J\n" +
 	"\rGetTokenPrice\x12\x1b.chain.GetTokenPriceRequest\x1a\x1c.chain.GetTokenPriceResponse\x12e\n" +
 	"\x16GetMultipleTokenPrices\x12$.chain.GetMultipleTokenPricesRequest\x1a%.chain.GetMultipleTokenPricesResponse\x12S\n" +
-	"\x10GetLiquidityPool\x12\x1e.chain.GetLiquidityPoolRequest\x1a\x1f.chain.GetLiquidityPoolResponse2O\n" +
+	"\x10GetLiquidityPool\x12\x1e.chain.GetLiquidityPoolRequest\x1a\x1f.chain.GetLiquidityPoolResponse\x12A\n" +
+	"\n" +
+	"QuoteTrade\x12\x18.chain.QuoteTradeRequest\x1a\x19.chain.QuoteTradeResponse\x12/\n" +
+	"\x04Swap\x12\x12.chain.SwapRequest\x1a\x13.chain.SwapResponse\x12J\n" +
+	"\rGetTokenPairs\x12\x1b.chain.GetTokenPairsRequest\x1a\x1c.chain.GetTokenPairsResponse\x12M\n" +
+	"\x0eGetRecentPairs\x12\x1c.chain.GetRecentPairsRequest\x1a\x1d.chain.GetRecentPairsResponse\x12@\n" +
+	"\x0eStreamNewPairs\x12\x1c.chain.StreamNewPairsRequest\x1a\x0e.chain.DexPair0\x01\x12S\n" +
+	"\x10AnalyzeTokenRisk\x12\x1e.chain.AnalyzeTokenRiskRequest\x1a\x1f.chain.AnalyzeTokenRiskResponse\x128\n" +
+	"\aGetTWAP\x12\x15.chain.GetTWAPRequest\x1a\x16.chain.GetTWAPResponse\x12P\n" +
+	"\x0fGetTokenCandles\x12\x1d.chain.GetTokenCandlesRequest\x1a\x1e.chain.GetTokenCandlesResponse2O\n" +
 	"\rHealthService\x12>\n" +
-	"\x05Check\x12\x19.chain.HealthCheckRequest\x1a\x1a.chain.HealthCheckResponse2\xbd\x03\n" +
+	"\x05Check\x12\x19.chain.HealthCheckRequest\x1a\x1a.chain.HealthCheckResponse2\xee\x04\n" +
 	"\fPriceService\x12M\n" +
 	"\x0eGetCryptoPrice\x12\x1c.chain.GetCryptoPriceRequest\x1a\x1d.chain.GetCryptoPriceResponse\x12h\n" +
 	"\x17GetMultipleCryptoPrices\x12%.chain.GetMultipleCryptoPricesRequest\x1a&.chain.GetMultipleCryptoPricesResponse\x12Y\n" +
 	"\x12GetTopCryptoPrices\x12 .chain.GetTopCryptoPricesRequest\x1a!.chain.GetTopCryptoPricesResponse\x12G\n" +
 	"\fSearchCrypto\x12\x1a.chain.SearchCryptoRequest\x1a\x1b.chain.SearchCryptoResponse\x12P\n" +
-	"\x0fGetPriceHistory\x12\x1d.chain.GetPriceHistoryRequest\x1a\x1e.chain.GetPriceHistoryResponseB\rZ\vchain/protob\x06proto3"
+	"\x0fGetPriceHistory\x12\x1d.chain.GetPriceHistoryRequest\x1a\x1e.chain.GetPriceHistoryResponse\x12U\n" +
+	"\x14SubscribeTokenPrices\x12\".chain.SubscribeTokenPricesRequest\x1a\x17.chain.TokenPriceUpdate0\x01\x12X\n" +
+	"\x15SubscribeCryptoPrices\x12#.chain.SubscribeCryptoPricesRequest\x1a\x18.chain.CryptoPriceUpdate0\x012\x8d\x03\n" +
+	"\fAlertService\x12J\n" +
+	"\x0fCreateAlertRule\x12\x1d.chain.CreateAlertRuleRequest\x1a\x18.chain.AlertRuleResponse\x12D\n" +
+	"\fGetAlertRule\x12\x1a.chain.GetAlertRuleRequest\x1a\x18.chain.AlertRuleResponse\x12M\n" +
+	"\x0eListAlertRules\x12\x1c.chain.ListAlertRulesRequest\x1a\x1d.chain.ListAlertRulesResponse\x12J\n" +
+	"\x0fUpdateAlertRule\x12\x1d.chain.UpdateAlertRuleRequest\x1a\x18.chain.AlertRuleResponse\x12P\n" +
+	"\x0fDeleteAlertRule\x12\x1d.chain.DeleteAlertRuleRequest\x1a\x1e.chain.DeleteAlertRuleResponse2[\n" +
+	"\x10PortfolioService\x12G\n" +
+	"\fGetPortfolio\x12\x1a.chain.GetPortfolioRequest\x1a\x1b.chain.GetPortfolioResponseB\rZ\vchain/protob\x06proto3"
 
 var (
 	file_proto_chain_service_proto_rawDescOnce sync.Once
@@ -2368,96 +7063,203 @@ func file_proto_chain_service_proto_rawDescGZIP() []byte {
 	return file_proto_chain_service_proto_rawDescData
 }
 
-var file_proto_chain_service_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
+var file_proto_chain_service_proto_msgTypes = make([]protoimpl.MessageInfo, 87)
 var file_proto_chain_service_proto_goTypes = []any{
 	(*HealthCheckRequest)(nil),              // 0: chain.HealthCheckRequest
 	(*HealthCheckResponse)(nil),             // 1: chain.HealthCheckResponse
 	(*GetBalanceRequest)(nil),               // 2: chain.GetBalanceRequest
 	(*GetBalanceResponse)(nil),              // 3: chain.GetBalanceResponse
-	(*TransferRequest)(nil),                 // 4: chain.TransferRequest
-	(*TransferResponse)(nil),                // 5: chain.TransferResponse
-	(*GetTransactionRequest)(nil),           // 6: chain.GetTransactionRequest
-	(*GetTransactionResponse)(nil),          // 7: chain.GetTransactionResponse
-	(*CallContractRequest)(nil),             // 8: chain.CallContractRequest
-	(*CallContractResponse)(nil),            // 9: chain.CallContractResponse
-	(*DeployContractRequest)(nil),           // 10: chain.DeployContractRequest
-	(*DeployContractResponse)(nil),          // 11: chain.DeployContractResponse
-	(*GetTokenInfoRequest)(nil),             // 12: chain.GetTokenInfoRequest
-	(*TokenInfo)(nil),                       // 13: chain.TokenInfo
-	(*GetTokenInfoResponse)(nil),            // 14: chain.GetTokenInfoResponse
-	(*SearchTokenRequest)(nil),              // 15: chain.SearchTokenRequest
-	(*SearchTokenResponse)(nil),             // 16: chain.SearchTokenResponse
-	(*GetTokenPriceRequest)(nil),            // 17: chain.GetTokenPriceRequest
-	(*TokenPrice)(nil),                      // 18: chain.TokenPrice
-	(*GetTokenPriceResponse)(nil),           // 19: chain.GetTokenPriceResponse
-	(*TokenRequest)(nil),                    // 20: chain.TokenRequest
-	(*GetMultipleTokenPricesRequest)(nil),   // 21: chain.GetMultipleTokenPricesRequest
-	(*GetMultipleTokenPricesResponse)(nil),  // 22: chain.GetMultipleTokenPricesResponse
-	(*GetLiquidityPoolRequest)(nil),         // 23: chain.GetLiquidityPoolRequest
-	(*LiquidityPool)(nil),                   // 24: chain.LiquidityPool
-	(*CryptoPriceInfo)(nil),                 // 25: chain.CryptoPriceInfo
-	(*GetCryptoPriceRequest)(nil),           // 26: chain.GetCryptoPriceRequest
-	(*GetCryptoPriceResponse)(nil),          // 27: chain.GetCryptoPriceResponse
-	(*GetMultipleCryptoPricesRequest)(nil),  // 28: chain.GetMultipleCryptoPricesRequest
-	(*GetMultipleCryptoPricesResponse)(nil), // 29: chain.GetMultipleCryptoPricesResponse
-	(*GetTopCryptoPricesRequest)(nil),       // 30: chain.GetTopCryptoPricesRequest
-	(*GetTopCryptoPricesResponse)(nil),      // 31: chain.GetTopCryptoPricesResponse
-	(*SearchCryptoRequest)(nil),             // 32: chain.SearchCryptoRequest
-	(*SearchCryptoResponse)(nil),            // 33: chain.SearchCryptoResponse
-	(*GetPriceHistoryRequest)(nil),          // 34: chain.GetPriceHistoryRequest
-	(*GetPriceHistoryResponse)(nil),         // 35: chain.GetPriceHistoryResponse
-	(*GetLiquidityPoolResponse)(nil),        // 36: chain.GetLiquidityPoolResponse
-	nil,                                     // 37: chain.GetMultipleCryptoPricesResponse.PricesEntry
+	(*GetBalancesRequest)(nil),              // 4: chain.GetBalancesRequest
+	(*AccountBalance)(nil),                  // 5: chain.AccountBalance
+	(*GetBalancesResponse)(nil),             // 6: chain.GetBalancesResponse
+	(*TransferRequest)(nil),                 // 7: chain.TransferRequest
+	(*TransferResponse)(nil),                // 8: chain.TransferResponse
+	(*GetTransactionRequest)(nil),           // 9: chain.GetTransactionRequest
+	(*GetTransactionResponse)(nil),          // 10: chain.GetTransactionResponse
+	(*CallContractRequest)(nil),             // 11: chain.CallContractRequest
+	(*CallContractResponse)(nil),            // 12: chain.CallContractResponse
+	(*DeployContractRequest)(nil),           // 13: chain.DeployContractRequest
+	(*DeployContractResponse)(nil),          // 14: chain.DeployContractResponse
+	(*GetTokenInfoRequest)(nil),             // 15: chain.GetTokenInfoRequest
+	(*TokenInfo)(nil),                       // 16: chain.TokenInfo
+	(*GetTokenInfoResponse)(nil),            // 17: chain.GetTokenInfoResponse
+	(*SearchTokenRequest)(nil),              // 18: chain.SearchTokenRequest
+	(*SearchTokenResponse)(nil),             // 19: chain.SearchTokenResponse
+	(*ImportTokenListRequest)(nil),          // 20: chain.ImportTokenListRequest
+	(*ImportTokenListResponse)(nil),         // 21: chain.ImportTokenListResponse
+	(*GetTokenPriceRequest)(nil),            // 22: chain.GetTokenPriceRequest
+	(*TokenPrice)(nil),                      // 23: chain.TokenPrice
+	(*GetTokenPriceResponse)(nil),           // 24: chain.GetTokenPriceResponse
+	(*TokenRequest)(nil),                    // 25: chain.TokenRequest
+	(*GetMultipleTokenPricesRequest)(nil),   // 26: chain.GetMultipleTokenPricesRequest
+	(*GetMultipleTokenPricesResponse)(nil),  // 27: chain.GetMultipleTokenPricesResponse
+	(*GetLiquidityPoolRequest)(nil),         // 28: chain.GetLiquidityPoolRequest
+	(*LiquidityPool)(nil),                   // 29: chain.LiquidityPool
+	(*PoolReserve)(nil),                     // 30: chain.PoolReserve
+	(*V3Pool)(nil),                          // 31: chain.V3Pool
+	(*QuoteTradeRequest)(nil),               // 32: chain.QuoteTradeRequest
+	(*TradeQuote)(nil),                      // 33: chain.TradeQuote
+	(*QuoteTradeResponse)(nil),              // 34: chain.QuoteTradeResponse
+	(*SwapRequest)(nil),                     // 35: chain.SwapRequest
+	(*SwapResult)(nil),                      // 36: chain.SwapResult
+	(*SwapResponse)(nil),                    // 37: chain.SwapResponse
+	(*DexPair)(nil),                         // 38: chain.DexPair
+	(*GetTokenPairsRequest)(nil),            // 39: chain.GetTokenPairsRequest
+	(*GetTokenPairsResponse)(nil),           // 40: chain.GetTokenPairsResponse
+	(*GetRecentPairsRequest)(nil),           // 41: chain.GetRecentPairsRequest
+	(*GetRecentPairsResponse)(nil),          // 42: chain.GetRecentPairsResponse
+	(*StreamNewPairsRequest)(nil),           // 43: chain.StreamNewPairsRequest
+	(*AnalyzeTokenRiskRequest)(nil),         // 44: chain.AnalyzeTokenRiskRequest
+	(*TokenRiskReport)(nil),                 // 45: chain.TokenRiskReport
+	(*AnalyzeTokenRiskResponse)(nil),        // 46: chain.AnalyzeTokenRiskResponse
+	(*GetTWAPRequest)(nil),                  // 47: chain.GetTWAPRequest
+	(*TWAPPrice)(nil),                       // 48: chain.TWAPPrice
+	(*GetTWAPResponse)(nil),                 // 49: chain.GetTWAPResponse
+	(*GetTokenCandlesRequest)(nil),          // 50: chain.GetTokenCandlesRequest
+	(*Candle)(nil),                          // 51: chain.Candle
+	(*GetTokenCandlesResponse)(nil),         // 52: chain.GetTokenCandlesResponse
+	(*CryptoPriceInfo)(nil),                 // 53: chain.CryptoPriceInfo
+	(*PriceQuote)(nil),                      // 54: chain.PriceQuote
+	(*GetCryptoPriceRequest)(nil),           // 55: chain.GetCryptoPriceRequest
+	(*GetCryptoPriceResponse)(nil),          // 56: chain.GetCryptoPriceResponse
+	(*GetMultipleCryptoPricesRequest)(nil),  // 57: chain.GetMultipleCryptoPricesRequest
+	(*GetMultipleCryptoPricesResponse)(nil), // 58: chain.GetMultipleCryptoPricesResponse
+	(*GetTopCryptoPricesRequest)(nil),       // 59: chain.GetTopCryptoPricesRequest
+	(*GetTopCryptoPricesResponse)(nil),      // 60: chain.GetTopCryptoPricesResponse
+	(*SearchCryptoRequest)(nil),             // 61: chain.SearchCryptoRequest
+	(*SearchCryptoResponse)(nil),            // 62: chain.SearchCryptoResponse
+	(*GetPriceHistoryRequest)(nil),          // 63: chain.GetPriceHistoryRequest
+	(*PricePoint)(nil),                      // 64: chain.PricePoint
+	(*PriceHistoryCandle)(nil),              // 65: chain.PriceHistoryCandle
+	(*GetPriceHistoryResponse)(nil),         // 66: chain.GetPriceHistoryResponse
+	(*SubscribeTokenPricesRequest)(nil),     // 67: chain.SubscribeTokenPricesRequest
+	(*TokenPriceUpdate)(nil),                // 68: chain.TokenPriceUpdate
+	(*SubscribeCryptoPricesRequest)(nil),    // 69: chain.SubscribeCryptoPricesRequest
+	(*CryptoPriceUpdate)(nil),               // 70: chain.CryptoPriceUpdate
+	(*GetLiquidityPoolResponse)(nil),        // 71: chain.GetLiquidityPoolResponse
+	(*AlertRule)(nil),                       // 72: chain.AlertRule
+	(*CreateAlertRuleRequest)(nil),          // 73: chain.CreateAlertRuleRequest
+	(*UpdateAlertRuleRequest)(nil),          // 74: chain.UpdateAlertRuleRequest
+	(*GetAlertRuleRequest)(nil),             // 75: chain.GetAlertRuleRequest
+	(*DeleteAlertRuleRequest)(nil),          // 76: chain.DeleteAlertRuleRequest
+	(*ListAlertRulesRequest)(nil),           // 77: chain.ListAlertRulesRequest
+	(*AlertRuleResponse)(nil),               // 78: chain.AlertRuleResponse
+	(*ListAlertRulesResponse)(nil),          // 79: chain.ListAlertRulesResponse
+	(*DeleteAlertRuleResponse)(nil),         // 80: chain.DeleteAlertRuleResponse
+	(*PortfolioHolding)(nil),                // 81: chain.PortfolioHolding
+	(*AccountPortfolio)(nil),                // 82: chain.AccountPortfolio
+	(*GetPortfolioRequest)(nil),             // 83: chain.GetPortfolioRequest
+	(*GetPortfolioResponse)(nil),            // 84: chain.GetPortfolioResponse
+	nil,                                     // 85: chain.CryptoPriceInfo.QuotesEntry
+	nil,                                     // 86: chain.GetMultipleCryptoPricesResponse.PricesEntry
 }
 var file_proto_chain_service_proto_depIdxs = []int32{
-	13, // 0: chain.GetTokenInfoResponse.token:type_name -> chain.TokenInfo
-	13, // 1: chain.SearchTokenResponse.tokens:type_name -> chain.TokenInfo
-	18, // 2: chain.GetTokenPriceResponse.price:type_name -> chain.TokenPrice
-	20, // 3: chain.GetMultipleTokenPricesRequest.tokens:type_name -> chain.TokenRequest
-	18, // 4: chain.GetMultipleTokenPricesResponse.prices:type_name -> chain.TokenPrice
-	25, // 5: chain.GetCryptoPriceResponse.price:type_name -> chain.CryptoPriceInfo
-	37, // 6: chain.GetMultipleCryptoPricesResponse.prices:type_name -> chain.GetMultipleCryptoPricesResponse.PricesEntry
-	25, // 7: chain.GetTopCryptoPricesResponse.prices:type_name -> chain.CryptoPriceInfo
-	25, // 8: chain.SearchCryptoResponse.results:type_name -> chain.CryptoPriceInfo
-	24, // 9: chain.GetLiquidityPoolResponse.pool:type_name -> chain.LiquidityPool
-	25, // 10: chain.GetMultipleCryptoPricesResponse.PricesEntry.value:type_name -> chain.CryptoPriceInfo
-	2,  // 11: chain.ChainService.GetBalance:input_type -> chain.GetBalanceRequest
-	4,  // 12: chain.ChainService.Transfer:input_type -> chain.TransferRequest
-	6,  // 13: chain.ChainService.GetTransaction:input_type -> chain.GetTransactionRequest
-	8,  // 14: chain.ChainService.CallContract:input_type -> chain.CallContractRequest
-	10, // 15: chain.ChainService.DeployContract:input_type -> chain.DeployContractRequest
-	12, // 16: chain.BSCService.GetTokenInfo:input_type -> chain.GetTokenInfoRequest
-	15, // 17: chain.BSCService.SearchToken:input_type -> chain.SearchTokenRequest
-	17, // 18: chain.BSCService.GetTokenPrice:input_type -> chain.GetTokenPriceRequest
-	21, // 19: chain.BSCService.GetMultipleTokenPrices:input_type -> chain.GetMultipleTokenPricesRequest
-	23, // 20: chain.BSCService.GetLiquidityPool:input_type -> chain.GetLiquidityPoolRequest
-	0,  // 21: chain.HealthService.Check:input_type -> chain.HealthCheckRequest
-	26, // 22: chain.PriceService.GetCryptoPrice:input_type -> chain.GetCryptoPriceRequest
-	28, // 23: chain.PriceService.GetMultipleCryptoPrices:input_type -> chain.GetMultipleCryptoPricesRequest
-	30, // 24: chain.PriceService.GetTopCryptoPrices:input_type -> chain.GetTopCryptoPricesRequest
-	32, // 25: chain.PriceService.SearchCrypto:input_type -> chain.SearchCryptoRequest
-	34, // 26: chain.PriceService.GetPriceHistory:input_type -> chain.GetPriceHistoryRequest
-	3,  // 27: chain.ChainService.GetBalance:output_type -> chain.GetBalanceResponse
-	5,  // 28: chain.ChainService.Transfer:output_type -> chain.TransferResponse
-	7,  // 29: chain.ChainService.GetTransaction:output_type -> chain.GetTransactionResponse
-	9,  // 30: chain.ChainService.CallContract:output_type -> chain.CallContractResponse
-	11, // 31: chain.ChainService.DeployContract:output_type -> chain.DeployContractResponse
-	14, // 32: chain.BSCService.GetTokenInfo:output_type -> chain.GetTokenInfoResponse
-	16, // 33: chain.BSCService.SearchToken:output_type -> chain.SearchTokenResponse
-	19, // 34: chain.BSCService.GetTokenPrice:output_type -> chain.GetTokenPriceResponse
-	22, // 35: chain.BSCService.GetMultipleTokenPrices:output_type -> chain.GetMultipleTokenPricesResponse
-	36, // 36: chain.BSCService.GetLiquidityPool:output_type -> chain.GetLiquidityPoolResponse
-	1,  // 37: chain.HealthService.Check:output_type -> chain.HealthCheckResponse
-	27, // 38: chain.PriceService.GetCryptoPrice:output_type -> chain.GetCryptoPriceResponse
-	29, // 39: chain.PriceService.GetMultipleCryptoPrices:output_type -> chain.GetMultipleCryptoPricesResponse
-	31, // 40: chain.PriceService.GetTopCryptoPrices:output_type -> chain.GetTopCryptoPricesResponse
-	33, // 41: chain.PriceService.SearchCrypto:output_type -> chain.SearchCryptoResponse
-	35, // 42: chain.PriceService.GetPriceHistory:output_type -> chain.GetPriceHistoryResponse
-	27, // [27:43] is the sub-list for method output_type
-	11, // [11:27] is the sub-list for method input_type
-	11, // [11:11] is the sub-list for extension type_name
-	11, // [11:11] is the sub-list for extension extendee
-	0,  // [0:11] is the sub-list for field type_name
+	5,  // 0: chain.GetBalancesResponse.balances:type_name -> chain.AccountBalance
+	16, // 1: chain.GetTokenInfoResponse.token:type_name -> chain.TokenInfo
+	16, // 2: chain.SearchTokenResponse.tokens:type_name -> chain.TokenInfo
+	23, // 3: chain.GetTokenPriceResponse.price:type_name -> chain.TokenPrice
+	25, // 4: chain.GetMultipleTokenPricesRequest.tokens:type_name -> chain.TokenRequest
+	23, // 5: chain.GetMultipleTokenPricesResponse.prices:type_name -> chain.TokenPrice
+	31, // 6: chain.LiquidityPool.v3_pools:type_name -> chain.V3Pool
+	30, // 7: chain.LiquidityPool.reserve0:type_name -> chain.PoolReserve
+	30, // 8: chain.LiquidityPool.reserve1:type_name -> chain.PoolReserve
+	33, // 9: chain.QuoteTradeResponse.quote:type_name -> chain.TradeQuote
+	36, // 10: chain.SwapResponse.result:type_name -> chain.SwapResult
+	38, // 11: chain.GetTokenPairsResponse.pairs:type_name -> chain.DexPair
+	38, // 12: chain.GetRecentPairsResponse.pairs:type_name -> chain.DexPair
+	45, // 13: chain.AnalyzeTokenRiskResponse.report:type_name -> chain.TokenRiskReport
+	48, // 14: chain.GetTWAPResponse.price:type_name -> chain.TWAPPrice
+	51, // 15: chain.GetTokenCandlesResponse.candles:type_name -> chain.Candle
+	85, // 16: chain.CryptoPriceInfo.quotes:type_name -> chain.CryptoPriceInfo.QuotesEntry
+	53, // 17: chain.GetCryptoPriceResponse.price:type_name -> chain.CryptoPriceInfo
+	86, // 18: chain.GetMultipleCryptoPricesResponse.prices:type_name -> chain.GetMultipleCryptoPricesResponse.PricesEntry
+	53, // 19: chain.GetTopCryptoPricesResponse.prices:type_name -> chain.CryptoPriceInfo
+	53, // 20: chain.SearchCryptoResponse.results:type_name -> chain.CryptoPriceInfo
+	64, // 21: chain.GetPriceHistoryResponse.points:type_name -> chain.PricePoint
+	65, // 22: chain.GetPriceHistoryResponse.candles:type_name -> chain.PriceHistoryCandle
+	23, // 23: chain.TokenPriceUpdate.price:type_name -> chain.TokenPrice
+	53, // 24: chain.CryptoPriceUpdate.price:type_name -> chain.CryptoPriceInfo
+	29, // 25: chain.GetLiquidityPoolResponse.pool:type_name -> chain.LiquidityPool
+	72, // 26: chain.AlertRuleResponse.rule:type_name -> chain.AlertRule
+	72, // 27: chain.ListAlertRulesResponse.rules:type_name -> chain.AlertRule
+	81, // 28: chain.AccountPortfolio.holdings:type_name -> chain.PortfolioHolding
+	81, // 29: chain.GetPortfolioResponse.holdings:type_name -> chain.PortfolioHolding
+	82, // 30: chain.GetPortfolioResponse.accounts:type_name -> chain.AccountPortfolio
+	54, // 31: chain.CryptoPriceInfo.QuotesEntry.value:type_name -> chain.PriceQuote
+	53, // 32: chain.GetMultipleCryptoPricesResponse.PricesEntry.value:type_name -> chain.CryptoPriceInfo
+	2,  // 33: chain.ChainService.GetBalance:input_type -> chain.GetBalanceRequest
+	4,  // 34: chain.ChainService.GetBalances:input_type -> chain.GetBalancesRequest
+	7,  // 35: chain.ChainService.Transfer:input_type -> chain.TransferRequest
+	9,  // 36: chain.ChainService.GetTransaction:input_type -> chain.GetTransactionRequest
+	11, // 37: chain.ChainService.CallContract:input_type -> chain.CallContractRequest
+	13, // 38: chain.ChainService.DeployContract:input_type -> chain.DeployContractRequest
+	15, // 39: chain.BSCService.GetTokenInfo:input_type -> chain.GetTokenInfoRequest
+	18, // 40: chain.BSCService.SearchToken:input_type -> chain.SearchTokenRequest
+	20, // 41: chain.BSCService.ImportTokenList:input_type -> chain.ImportTokenListRequest
+	22, // 42: chain.BSCService.GetTokenPrice:input_type -> chain.GetTokenPriceRequest
+	26, // 43: chain.BSCService.GetMultipleTokenPrices:input_type -> chain.GetMultipleTokenPricesRequest
+	28, // 44: chain.BSCService.GetLiquidityPool:input_type -> chain.GetLiquidityPoolRequest
+	32, // 45: chain.BSCService.QuoteTrade:input_type -> chain.QuoteTradeRequest
+	35, // 46: chain.BSCService.Swap:input_type -> chain.SwapRequest
+	39, // 47: chain.BSCService.GetTokenPairs:input_type -> chain.GetTokenPairsRequest
+	41, // 48: chain.BSCService.GetRecentPairs:input_type -> chain.GetRecentPairsRequest
+	43, // 49: chain.BSCService.StreamNewPairs:input_type -> chain.StreamNewPairsRequest
+	44, // 50: chain.BSCService.AnalyzeTokenRisk:input_type -> chain.AnalyzeTokenRiskRequest
+	47, // 51: chain.BSCService.GetTWAP:input_type -> chain.GetTWAPRequest
+	50, // 52: chain.BSCService.GetTokenCandles:input_type -> chain.GetTokenCandlesRequest
+	0,  // 53: chain.HealthService.Check:input_type -> chain.HealthCheckRequest
+	55, // 54: chain.PriceService.GetCryptoPrice:input_type -> chain.GetCryptoPriceRequest
+	57, // 55: chain.PriceService.GetMultipleCryptoPrices:input_type -> chain.GetMultipleCryptoPricesRequest
+	59, // 56: chain.PriceService.GetTopCryptoPrices:input_type -> chain.GetTopCryptoPricesRequest
+	61, // 57: chain.PriceService.SearchCrypto:input_type -> chain.SearchCryptoRequest
+	63, // 58: chain.PriceService.GetPriceHistory:input_type -> chain.GetPriceHistoryRequest
+	67, // 59: chain.PriceService.SubscribeTokenPrices:input_type -> chain.SubscribeTokenPricesRequest
+	69, // 60: chain.PriceService.SubscribeCryptoPrices:input_type -> chain.SubscribeCryptoPricesRequest
+	73, // 61: chain.AlertService.CreateAlertRule:input_type -> chain.CreateAlertRuleRequest
+	75, // 62: chain.AlertService.GetAlertRule:input_type -> chain.GetAlertRuleRequest
+	77, // 63: chain.AlertService.ListAlertRules:input_type -> chain.ListAlertRulesRequest
+	74, // 64: chain.AlertService.UpdateAlertRule:input_type -> chain.UpdateAlertRuleRequest
+	76, // 65: chain.AlertService.DeleteAlertRule:input_type -> chain.DeleteAlertRuleRequest
+	83, // 66: chain.PortfolioService.GetPortfolio:input_type -> chain.GetPortfolioRequest
+	3,  // 67: chain.ChainService.GetBalance:output_type -> chain.GetBalanceResponse
+	6,  // 68: chain.ChainService.GetBalances:output_type -> chain.GetBalancesResponse
+	8,  // 69: chain.ChainService.Transfer:output_type -> chain.TransferResponse
+	10, // 70: chain.ChainService.GetTransaction:output_type -> chain.GetTransactionResponse
+	12, // 71: chain.ChainService.CallContract:output_type -> chain.CallContractResponse
+	14, // 72: chain.ChainService.DeployContract:output_type -> chain.DeployContractResponse
+	17, // 73: chain.BSCService.GetTokenInfo:output_type -> chain.GetTokenInfoResponse
+	19, // 74: chain.BSCService.SearchToken:output_type -> chain.SearchTokenResponse
+	21, // 75: chain.BSCService.ImportTokenList:output_type -> chain.ImportTokenListResponse
+	24, // 76: chain.BSCService.GetTokenPrice:output_type -> chain.GetTokenPriceResponse
+	27, // 77: chain.BSCService.GetMultipleTokenPrices:output_type -> chain.GetMultipleTokenPricesResponse
+	71, // 78: chain.BSCService.GetLiquidityPool:output_type -> chain.GetLiquidityPoolResponse
+	34, // 79: chain.BSCService.QuoteTrade:output_type -> chain.QuoteTradeResponse
+	37, // 80: chain.BSCService.Swap:output_type -> chain.SwapResponse
+	40, // 81: chain.BSCService.GetTokenPairs:output_type -> chain.GetTokenPairsResponse
+	42, // 82: chain.BSCService.GetRecentPairs:output_type -> chain.GetRecentPairsResponse
+	38, // 83: chain.BSCService.StreamNewPairs:output_type -> chain.DexPair
+	46, // 84: chain.BSCService.AnalyzeTokenRisk:output_type -> chain.AnalyzeTokenRiskResponse
+	49, // 85: chain.BSCService.GetTWAP:output_type -> chain.GetTWAPResponse
+	52, // 86: chain.BSCService.GetTokenCandles:output_type -> chain.GetTokenCandlesResponse
+	1,  // 87: chain.HealthService.Check:output_type -> chain.HealthCheckResponse
+	56, // 88: chain.PriceService.GetCryptoPrice:output_type -> chain.GetCryptoPriceResponse
+	58, // 89: chain.PriceService.GetMultipleCryptoPrices:output_type -> chain.GetMultipleCryptoPricesResponse
+	60, // 90: chain.PriceService.GetTopCryptoPrices:output_type -> chain.GetTopCryptoPricesResponse
+	62, // 91: chain.PriceService.SearchCrypto:output_type -> chain.SearchCryptoResponse
+	66, // 92: chain.PriceService.GetPriceHistory:output_type -> chain.GetPriceHistoryResponse
+	68, // 93: chain.PriceService.SubscribeTokenPrices:output_type -> chain.TokenPriceUpdate
+	70, // 94: chain.PriceService.SubscribeCryptoPrices:output_type -> chain.CryptoPriceUpdate
+	78, // 95: chain.AlertService.CreateAlertRule:output_type -> chain.AlertRuleResponse
+	78, // 96: chain.AlertService.GetAlertRule:output_type -> chain.AlertRuleResponse
+	79, // 97: chain.AlertService.ListAlertRules:output_type -> chain.ListAlertRulesResponse
+	78, // 98: chain.AlertService.UpdateAlertRule:output_type -> chain.AlertRuleResponse
+	80, // 99: chain.AlertService.DeleteAlertRule:output_type -> chain.DeleteAlertRuleResponse
+	84, // 100: chain.PortfolioService.GetPortfolio:output_type -> chain.GetPortfolioResponse
+	67, // [67:101] is the sub-list for method output_type
+	33, // [33:67] is the sub-list for method input_type
+	33, // [33:33] is the sub-list for extension type_name
+	33, // [33:33] is the sub-list for extension extendee
+	0,  // [0:33] is the sub-list for field type_name
 }
 
 func init() { file_proto_chain_service_proto_init() }
@@ -2471,9 +7273,9 @@ func file_proto_chain_service_proto_init() {
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_chain_service_proto_rawDesc), len(file_proto_chain_service_proto_rawDesc)),
 			NumEnums:      0,
-			NumMessages:   38,
+			NumMessages:   87,
 			NumExtensions: 0,
-			NumServices:   4,
+			NumServices:   6,
 		},
 		GoTypes:           file_proto_chain_service_proto_goTypes,
 		DependencyIndexes: file_proto_chain_service_proto_depIdxs,
@@ -20,6 +20,7 @@ const _ = grpc.SupportPackageIsVersion9
 
 const (
 	ChainService_GetBalance_FullMethodName     = "/chain.ChainService/GetBalance"
+	ChainService_GetBalances_FullMethodName    = "/chain.ChainService/GetBalances"
 	ChainService_Transfer_FullMethodName       = "/chain.ChainService/Transfer"
 	ChainService_GetTransaction_FullMethodName = "/chain.ChainService/GetTransaction"
 	ChainService_CallContract_FullMethodName   = "/chain.ChainService/CallContract"
@@ -34,6 +35,8 @@ const (
 type ChainServiceClient interface {
 	// 获取账户余额
 	GetBalance(ctx context.Context, in *GetBalanceRequest, opts ...grpc.CallOption) (*GetBalanceResponse, error)
+	// 批量获取账户余额
+	GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error)
 	// 代币转账
 	Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error)
 	// 获取交易信息
@@ -62,6 +65,16 @@ func (c *chainServiceClient) GetBalance(ctx context.Context, in *GetBalanceReque
 	return out, nil
 }
 
+func (c *chainServiceClient) GetBalances(ctx context.Context, in *GetBalancesRequest, opts ...grpc.CallOption) (*GetBalancesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetBalancesResponse)
+	err := c.cc.Invoke(ctx, ChainService_GetBalances_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *chainServiceClient) Transfer(ctx context.Context, in *TransferRequest, opts ...grpc.CallOption) (*TransferResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(TransferResponse)
@@ -110,6 +123,8 @@ func (c *chainServiceClient) DeployContract(ctx context.Context, in *DeployContr
 type ChainServiceServer interface {
 	// 获取账户余额
 	GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error)
+	// 批量获取账户余额
+	GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error)
 	// 代币转账
 	Transfer(context.Context, *TransferRequest) (*TransferResponse, error)
 	// 获取交易信息
@@ -131,6 +146,9 @@ type UnimplementedChainServiceServer struct{}
 func (UnimplementedChainServiceServer) GetBalance(context.Context, *GetBalanceRequest) (*GetBalanceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetBalance not implemented")
 }
+func (UnimplementedChainServiceServer) GetBalances(context.Context, *GetBalancesRequest) (*GetBalancesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetBalances not implemented")
+}
 func (UnimplementedChainServiceServer) Transfer(context.Context, *TransferRequest) (*TransferResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Transfer not implemented")
 }
@@ -182,6 +200,24 @@ func _ChainService_GetBalance_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _ChainService_GetBalances_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetBalancesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(ChainServiceServer).GetBalances(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: ChainService_GetBalances_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(ChainServiceServer).GetBalances(ctx, req.(*GetBalancesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _ChainService_Transfer_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(TransferRequest)
 	if err := dec(in); err != nil {
@@ -265,6 +301,10 @@ var ChainService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetBalance",
 			Handler:    _ChainService_GetBalance_Handler,
 		},
+		{
+			MethodName: "GetBalances",
+			Handler:    _ChainService_GetBalances_Handler,
+		},
 		{
 			MethodName: "Transfer",
 			Handler:    _ChainService_Transfer_Handler,
@@ -289,9 +329,18 @@ var ChainService_ServiceDesc = grpc.ServiceDesc{
 const (
 	BSCService_GetTokenInfo_FullMethodName           = "/chain.BSCService/GetTokenInfo"
 	BSCService_SearchToken_FullMethodName            = "/chain.BSCService/SearchToken"
+	BSCService_ImportTokenList_FullMethodName        = "/chain.BSCService/ImportTokenList"
 	BSCService_GetTokenPrice_FullMethodName          = "/chain.BSCService/GetTokenPrice"
 	BSCService_GetMultipleTokenPrices_FullMethodName = "/chain.BSCService/GetMultipleTokenPrices"
 	BSCService_GetLiquidityPool_FullMethodName       = "/chain.BSCService/GetLiquidityPool"
+	BSCService_QuoteTrade_FullMethodName             = "/chain.BSCService/QuoteTrade"
+	BSCService_Swap_FullMethodName                   = "/chain.BSCService/Swap"
+	BSCService_GetTokenPairs_FullMethodName          = "/chain.BSCService/GetTokenPairs"
+	BSCService_GetRecentPairs_FullMethodName         = "/chain.BSCService/GetRecentPairs"
+	BSCService_StreamNewPairs_FullMethodName         = "/chain.BSCService/StreamNewPairs"
+	BSCService_AnalyzeTokenRisk_FullMethodName       = "/chain.BSCService/AnalyzeTokenRisk"
+	BSCService_GetTWAP_FullMethodName                = "/chain.BSCService/GetTWAP"
+	BSCService_GetTokenCandles_FullMethodName        = "/chain.BSCService/GetTokenCandles"
 )
 
 // BSCServiceClient is the client API for BSCService service.
@@ -304,12 +353,30 @@ type BSCServiceClient interface {
 	GetTokenInfo(ctx context.Context, in *GetTokenInfoRequest, opts ...grpc.CallOption) (*GetTokenInfoResponse, error)
 	// 搜索代币
 	SearchToken(ctx context.Context, in *SearchTokenRequest, opts ...grpc.CallOption) (*SearchTokenResponse, error)
+	// 从HTTP(S)地址导入代币列表，需要在请求元数据 authorization: Bearer <token> 中携带代币列表导入令牌
+	ImportTokenList(ctx context.Context, in *ImportTokenListRequest, opts ...grpc.CallOption) (*ImportTokenListResponse, error)
 	// 获取代币价格
 	GetTokenPrice(ctx context.Context, in *GetTokenPriceRequest, opts ...grpc.CallOption) (*GetTokenPriceResponse, error)
 	// 批量获取代币价格
 	GetMultipleTokenPrices(ctx context.Context, in *GetMultipleTokenPricesRequest, opts ...grpc.CallOption) (*GetMultipleTokenPricesResponse, error)
 	// 获取流动性池信息
 	GetLiquidityPool(ctx context.Context, in *GetLiquidityPoolRequest, opts ...grpc.CallOption) (*GetLiquidityPoolResponse, error)
+	// 查询指定数量的兑换报价
+	QuoteTrade(ctx context.Context, in *QuoteTradeRequest, opts ...grpc.CallOption) (*QuoteTradeResponse, error)
+	// 通过PancakeSwap Router执行兑换，需要在请求元数据 authorization: Bearer <token> 中携带兑换令牌
+	Swap(ctx context.Context, in *SwapRequest, opts ...grpc.CallOption) (*SwapResponse, error)
+	// 获取已索引的包含指定代币的所有交易对
+	GetTokenPairs(ctx context.Context, in *GetTokenPairsRequest, opts ...grpc.CallOption) (*GetTokenPairsResponse, error)
+	// 获取最近创建的交易对
+	GetRecentPairs(ctx context.Context, in *GetRecentPairsRequest, opts ...grpc.CallOption) (*GetRecentPairsResponse, error)
+	// 订阅新创建的交易对
+	StreamNewPairs(ctx context.Context, in *StreamNewPairsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DexPair], error)
+	// 分析代币风险（貔貅、买卖税、交易限额和所有者特权）
+	AnalyzeTokenRisk(ctx context.Context, in *AnalyzeTokenRiskRequest, opts ...grpc.CallOption) (*AnalyzeTokenRiskResponse, error)
+	// 获取交易对/V3池的时间加权平均价格
+	GetTWAP(ctx context.Context, in *GetTWAPRequest, opts ...grpc.CallOption) (*GetTWAPResponse, error)
+	// 获取由Swap事件聚合的代币OHLCV K线
+	GetTokenCandles(ctx context.Context, in *GetTokenCandlesRequest, opts ...grpc.CallOption) (*GetTokenCandlesResponse, error)
 }
 
 type bSCServiceClient struct {
@@ -340,6 +407,16 @@ func (c *bSCServiceClient) SearchToken(ctx context.Context, in *SearchTokenReque
 	return out, nil
 }
 
+func (c *bSCServiceClient) ImportTokenList(ctx context.Context, in *ImportTokenListRequest, opts ...grpc.CallOption) (*ImportTokenListResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ImportTokenListResponse)
+	err := c.cc.Invoke(ctx, BSCService_ImportTokenList_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *bSCServiceClient) GetTokenPrice(ctx context.Context, in *GetTokenPriceRequest, opts ...grpc.CallOption) (*GetTokenPriceResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(GetTokenPriceResponse)
@@ -370,6 +447,95 @@ func (c *bSCServiceClient) GetLiquidityPool(ctx context.Context, in *GetLiquidit
 	return out, nil
 }
 
+func (c *bSCServiceClient) QuoteTrade(ctx context.Context, in *QuoteTradeRequest, opts ...grpc.CallOption) (*QuoteTradeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(QuoteTradeResponse)
+	err := c.cc.Invoke(ctx, BSCService_QuoteTrade_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bSCServiceClient) Swap(ctx context.Context, in *SwapRequest, opts ...grpc.CallOption) (*SwapResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(SwapResponse)
+	err := c.cc.Invoke(ctx, BSCService_Swap_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bSCServiceClient) GetTokenPairs(ctx context.Context, in *GetTokenPairsRequest, opts ...grpc.CallOption) (*GetTokenPairsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTokenPairsResponse)
+	err := c.cc.Invoke(ctx, BSCService_GetTokenPairs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bSCServiceClient) GetRecentPairs(ctx context.Context, in *GetRecentPairsRequest, opts ...grpc.CallOption) (*GetRecentPairsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetRecentPairsResponse)
+	err := c.cc.Invoke(ctx, BSCService_GetRecentPairs_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bSCServiceClient) StreamNewPairs(ctx context.Context, in *StreamNewPairsRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[DexPair], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &BSCService_ServiceDesc.Streams[0], BSCService_StreamNewPairs_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[StreamNewPairsRequest, DexPair]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BSCService_StreamNewPairsClient = grpc.ServerStreamingClient[DexPair]
+
+func (c *bSCServiceClient) AnalyzeTokenRisk(ctx context.Context, in *AnalyzeTokenRiskRequest, opts ...grpc.CallOption) (*AnalyzeTokenRiskResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AnalyzeTokenRiskResponse)
+	err := c.cc.Invoke(ctx, BSCService_AnalyzeTokenRisk_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bSCServiceClient) GetTWAP(ctx context.Context, in *GetTWAPRequest, opts ...grpc.CallOption) (*GetTWAPResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTWAPResponse)
+	err := c.cc.Invoke(ctx, BSCService_GetTWAP_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *bSCServiceClient) GetTokenCandles(ctx context.Context, in *GetTokenCandlesRequest, opts ...grpc.CallOption) (*GetTokenCandlesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetTokenCandlesResponse)
+	err := c.cc.Invoke(ctx, BSCService_GetTokenCandles_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // BSCServiceServer is the server API for BSCService service.
 // All implementations must embed UnimplementedBSCServiceServer
 // for forward compatibility.
@@ -380,12 +546,30 @@ type BSCServiceServer interface {
 	GetTokenInfo(context.Context, *GetTokenInfoRequest) (*GetTokenInfoResponse, error)
 	// 搜索代币
 	SearchToken(context.Context, *SearchTokenRequest) (*SearchTokenResponse, error)
+	// 从HTTP(S)地址导入代币列表，需要在请求元数据 authorization: Bearer <token> 中携带代币列表导入令牌
+	ImportTokenList(context.Context, *ImportTokenListRequest) (*ImportTokenListResponse, error)
 	// 获取代币价格
 	GetTokenPrice(context.Context, *GetTokenPriceRequest) (*GetTokenPriceResponse, error)
 	// 批量获取代币价格
 	GetMultipleTokenPrices(context.Context, *GetMultipleTokenPricesRequest) (*GetMultipleTokenPricesResponse, error)
 	// 获取流动性池信息
 	GetLiquidityPool(context.Context, *GetLiquidityPoolRequest) (*GetLiquidityPoolResponse, error)
+	// 查询指定数量的兑换报价
+	QuoteTrade(context.Context, *QuoteTradeRequest) (*QuoteTradeResponse, error)
+	// 通过PancakeSwap Router执行兑换，需要在请求元数据 authorization: Bearer <token> 中携带兑换令牌
+	Swap(context.Context, *SwapRequest) (*SwapResponse, error)
+	// 获取已索引的包含指定代币的所有交易对
+	GetTokenPairs(context.Context, *GetTokenPairsRequest) (*GetTokenPairsResponse, error)
+	// 获取最近创建的交易对
+	GetRecentPairs(context.Context, *GetRecentPairsRequest) (*GetRecentPairsResponse, error)
+	// 订阅新创建的交易对
+	StreamNewPairs(*StreamNewPairsRequest, grpc.ServerStreamingServer[DexPair]) error
+	// 分析代币风险（貔貅、买卖税、交易限额和所有者特权）
+	AnalyzeTokenRisk(context.Context, *AnalyzeTokenRiskRequest) (*AnalyzeTokenRiskResponse, error)
+	// 获取交易对/V3池的时间加权平均价格
+	GetTWAP(context.Context, *GetTWAPRequest) (*GetTWAPResponse, error)
+	// 获取由Swap事件聚合的代币OHLCV K线
+	GetTokenCandles(context.Context, *GetTokenCandlesRequest) (*GetTokenCandlesResponse, error)
 	mustEmbedUnimplementedBSCServiceServer()
 }
 
@@ -402,6 +586,9 @@ func (UnimplementedBSCServiceServer) GetTokenInfo(context.Context, *GetTokenInfo
 func (UnimplementedBSCServiceServer) SearchToken(context.Context, *SearchTokenRequest) (*SearchTokenResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SearchToken not implemented")
 }
+func (UnimplementedBSCServiceServer) ImportTokenList(context.Context, *ImportTokenListRequest) (*ImportTokenListResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ImportTokenList not implemented")
+}
 func (UnimplementedBSCServiceServer) GetTokenPrice(context.Context, *GetTokenPriceRequest) (*GetTokenPriceResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetTokenPrice not implemented")
 }
@@ -411,6 +598,30 @@ func (UnimplementedBSCServiceServer) GetMultipleTokenPrices(context.Context, *Ge
 func (UnimplementedBSCServiceServer) GetLiquidityPool(context.Context, *GetLiquidityPoolRequest) (*GetLiquidityPoolResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetLiquidityPool not implemented")
 }
+func (UnimplementedBSCServiceServer) QuoteTrade(context.Context, *QuoteTradeRequest) (*QuoteTradeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method QuoteTrade not implemented")
+}
+func (UnimplementedBSCServiceServer) Swap(context.Context, *SwapRequest) (*SwapResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Swap not implemented")
+}
+func (UnimplementedBSCServiceServer) GetTokenPairs(context.Context, *GetTokenPairsRequest) (*GetTokenPairsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTokenPairs not implemented")
+}
+func (UnimplementedBSCServiceServer) GetRecentPairs(context.Context, *GetRecentPairsRequest) (*GetRecentPairsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetRecentPairs not implemented")
+}
+func (UnimplementedBSCServiceServer) StreamNewPairs(*StreamNewPairsRequest, grpc.ServerStreamingServer[DexPair]) error {
+	return status.Errorf(codes.Unimplemented, "method StreamNewPairs not implemented")
+}
+func (UnimplementedBSCServiceServer) AnalyzeTokenRisk(context.Context, *AnalyzeTokenRiskRequest) (*AnalyzeTokenRiskResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AnalyzeTokenRisk not implemented")
+}
+func (UnimplementedBSCServiceServer) GetTWAP(context.Context, *GetTWAPRequest) (*GetTWAPResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTWAP not implemented")
+}
+func (UnimplementedBSCServiceServer) GetTokenCandles(context.Context, *GetTokenCandlesRequest) (*GetTokenCandlesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetTokenCandles not implemented")
+}
 func (UnimplementedBSCServiceServer) mustEmbedUnimplementedBSCServiceServer() {}
 func (UnimplementedBSCServiceServer) testEmbeddedByValue()                    {}
 
@@ -468,6 +679,24 @@ func _BSCService_SearchToken_Handler(srv interface{}, ctx context.Context, dec f
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BSCService_ImportTokenList_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ImportTokenListRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BSCServiceServer).ImportTokenList(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BSCService_ImportTokenList_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BSCServiceServer).ImportTokenList(ctx, req.(*ImportTokenListRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _BSCService_GetTokenPrice_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(GetTokenPriceRequest)
 	if err := dec(in); err != nil {
@@ -522,6 +751,143 @@ func _BSCService_GetLiquidityPool_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _BSCService_QuoteTrade_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(QuoteTradeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BSCServiceServer).QuoteTrade(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BSCService_QuoteTrade_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BSCServiceServer).QuoteTrade(ctx, req.(*QuoteTradeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BSCService_Swap_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SwapRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BSCServiceServer).Swap(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BSCService_Swap_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BSCServiceServer).Swap(ctx, req.(*SwapRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BSCService_GetTokenPairs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenPairsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BSCServiceServer).GetTokenPairs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BSCService_GetTokenPairs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BSCServiceServer).GetTokenPairs(ctx, req.(*GetTokenPairsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BSCService_GetRecentPairs_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetRecentPairsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BSCServiceServer).GetRecentPairs(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BSCService_GetRecentPairs_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BSCServiceServer).GetRecentPairs(ctx, req.(*GetRecentPairsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BSCService_StreamNewPairs_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StreamNewPairsRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(BSCServiceServer).StreamNewPairs(m, &grpc.GenericServerStream[StreamNewPairsRequest, DexPair]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type BSCService_StreamNewPairsServer = grpc.ServerStreamingServer[DexPair]
+
+func _BSCService_AnalyzeTokenRisk_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AnalyzeTokenRiskRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BSCServiceServer).AnalyzeTokenRisk(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BSCService_AnalyzeTokenRisk_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BSCServiceServer).AnalyzeTokenRisk(ctx, req.(*AnalyzeTokenRiskRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BSCService_GetTWAP_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTWAPRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BSCServiceServer).GetTWAP(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BSCService_GetTWAP_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BSCServiceServer).GetTWAP(ctx, req.(*GetTWAPRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _BSCService_GetTokenCandles_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetTokenCandlesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(BSCServiceServer).GetTokenCandles(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: BSCService_GetTokenCandles_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(BSCServiceServer).GetTokenCandles(ctx, req.(*GetTokenCandlesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // BSCService_ServiceDesc is the grpc.ServiceDesc for BSCService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -537,6 +903,10 @@ var BSCService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SearchToken",
 			Handler:    _BSCService_SearchToken_Handler,
 		},
+		{
+			MethodName: "ImportTokenList",
+			Handler:    _BSCService_ImportTokenList_Handler,
+		},
 		{
 			MethodName: "GetTokenPrice",
 			Handler:    _BSCService_GetTokenPrice_Handler,
@@ -549,8 +919,42 @@ var BSCService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "GetLiquidityPool",
 			Handler:    _BSCService_GetLiquidityPool_Handler,
 		},
+		{
+			MethodName: "QuoteTrade",
+			Handler:    _BSCService_QuoteTrade_Handler,
+		},
+		{
+			MethodName: "Swap",
+			Handler:    _BSCService_Swap_Handler,
+		},
+		{
+			MethodName: "GetTokenPairs",
+			Handler:    _BSCService_GetTokenPairs_Handler,
+		},
+		{
+			MethodName: "GetRecentPairs",
+			Handler:    _BSCService_GetRecentPairs_Handler,
+		},
+		{
+			MethodName: "AnalyzeTokenRisk",
+			Handler:    _BSCService_AnalyzeTokenRisk_Handler,
+		},
+		{
+			MethodName: "GetTWAP",
+			Handler:    _BSCService_GetTWAP_Handler,
+		},
+		{
+			MethodName: "GetTokenCandles",
+			Handler:    _BSCService_GetTokenCandles_Handler,
+		},
+	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StreamNewPairs",
+			Handler:       _BSCService_StreamNewPairs_Handler,
+			ServerStreams: true,
+		},
 	},
-	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/chain_service.proto",
 }
 
@@ -666,6 +1070,8 @@ const (
 	PriceService_GetTopCryptoPrices_FullMethodName      = "/chain.PriceService/GetTopCryptoPrices"
 	PriceService_SearchCrypto_FullMethodName            = "/chain.PriceService/SearchCrypto"
 	PriceService_GetPriceHistory_FullMethodName         = "/chain.PriceService/GetPriceHistory"
+	PriceService_SubscribeTokenPrices_FullMethodName    = "/chain.PriceService/SubscribeTokenPrices"
+	PriceService_SubscribeCryptoPrices_FullMethodName   = "/chain.PriceService/SubscribeCryptoPrices"
 )
 
 // PriceServiceClient is the client API for PriceService service.
@@ -679,6 +1085,10 @@ type PriceServiceClient interface {
 	GetTopCryptoPrices(ctx context.Context, in *GetTopCryptoPricesRequest, opts ...grpc.CallOption) (*GetTopCryptoPricesResponse, error)
 	SearchCrypto(ctx context.Context, in *SearchCryptoRequest, opts ...grpc.CallOption) (*SearchCryptoResponse, error)
 	GetPriceHistory(ctx context.Context, in *GetPriceHistoryRequest, opts ...grpc.CallOption) (*GetPriceHistoryResponse, error)
+	// 订阅BSC代币的DEX价格，价格变化达到epsilon或超过推送间隔时推送
+	SubscribeTokenPrices(ctx context.Context, in *SubscribeTokenPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TokenPriceUpdate], error)
+	// 订阅加密货币行情价格，推送条件与 SubscribeTokenPrices 相同
+	SubscribeCryptoPrices(ctx context.Context, in *SubscribeCryptoPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CryptoPriceUpdate], error)
 }
 
 type priceServiceClient struct {
@@ -739,6 +1149,44 @@ func (c *priceServiceClient) GetPriceHistory(ctx context.Context, in *GetPriceHi
 	return out, nil
 }
 
+func (c *priceServiceClient) SubscribeTokenPrices(ctx context.Context, in *SubscribeTokenPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[TokenPriceUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceService_ServiceDesc.Streams[0], PriceService_SubscribeTokenPrices_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeTokenPricesRequest, TokenPriceUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceService_SubscribeTokenPricesClient = grpc.ServerStreamingClient[TokenPriceUpdate]
+
+func (c *priceServiceClient) SubscribeCryptoPrices(ctx context.Context, in *SubscribeCryptoPricesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[CryptoPriceUpdate], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &PriceService_ServiceDesc.Streams[1], PriceService_SubscribeCryptoPrices_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SubscribeCryptoPricesRequest, CryptoPriceUpdate]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceService_SubscribeCryptoPricesClient = grpc.ServerStreamingClient[CryptoPriceUpdate]
+
 // PriceServiceServer is the server API for PriceService service.
 // All implementations must embed UnimplementedPriceServiceServer
 // for forward compatibility.
@@ -750,6 +1198,10 @@ type PriceServiceServer interface {
 	GetTopCryptoPrices(context.Context, *GetTopCryptoPricesRequest) (*GetTopCryptoPricesResponse, error)
 	SearchCrypto(context.Context, *SearchCryptoRequest) (*SearchCryptoResponse, error)
 	GetPriceHistory(context.Context, *GetPriceHistoryRequest) (*GetPriceHistoryResponse, error)
+	// 订阅BSC代币的DEX价格，价格变化达到epsilon或超过推送间隔时推送
+	SubscribeTokenPrices(*SubscribeTokenPricesRequest, grpc.ServerStreamingServer[TokenPriceUpdate]) error
+	// 订阅加密货币行情价格，推送条件与 SubscribeTokenPrices 相同
+	SubscribeCryptoPrices(*SubscribeCryptoPricesRequest, grpc.ServerStreamingServer[CryptoPriceUpdate]) error
 	mustEmbedUnimplementedPriceServiceServer()
 }
 
@@ -775,6 +1227,12 @@ func (UnimplementedPriceServiceServer) SearchCrypto(context.Context, *SearchCryp
 func (UnimplementedPriceServiceServer) GetPriceHistory(context.Context, *GetPriceHistoryRequest) (*GetPriceHistoryResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method GetPriceHistory not implemented")
 }
+func (UnimplementedPriceServiceServer) SubscribeTokenPrices(*SubscribeTokenPricesRequest, grpc.ServerStreamingServer[TokenPriceUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeTokenPrices not implemented")
+}
+func (UnimplementedPriceServiceServer) SubscribeCryptoPrices(*SubscribeCryptoPricesRequest, grpc.ServerStreamingServer[CryptoPriceUpdate]) error {
+	return status.Errorf(codes.Unimplemented, "method SubscribeCryptoPrices not implemented")
+}
 func (UnimplementedPriceServiceServer) mustEmbedUnimplementedPriceServiceServer() {}
 func (UnimplementedPriceServiceServer) testEmbeddedByValue()                      {}
 
@@ -886,6 +1344,28 @@ func _PriceService_GetPriceHistory_Handler(srv interface{}, ctx context.Context,
 	return interceptor(ctx, in, info, handler)
 }
 
+func _PriceService_SubscribeTokenPrices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeTokenPricesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceServiceServer).SubscribeTokenPrices(m, &grpc.GenericServerStream[SubscribeTokenPricesRequest, TokenPriceUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceService_SubscribeTokenPricesServer = grpc.ServerStreamingServer[TokenPriceUpdate]
+
+func _PriceService_SubscribeCryptoPrices_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SubscribeCryptoPricesRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(PriceServiceServer).SubscribeCryptoPrices(m, &grpc.GenericServerStream[SubscribeCryptoPricesRequest, CryptoPriceUpdate]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type PriceService_SubscribeCryptoPricesServer = grpc.ServerStreamingServer[CryptoPriceUpdate]
+
 // PriceService_ServiceDesc is the grpc.ServiceDesc for PriceService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -914,6 +1394,381 @@ var PriceService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _PriceService_GetPriceHistory_Handler,
 		},
 	},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "SubscribeTokenPrices",
+			Handler:       _PriceService_SubscribeTokenPrices_Handler,
+			ServerStreams: true,
+		},
+		{
+			StreamName:    "SubscribeCryptoPrices",
+			Handler:       _PriceService_SubscribeCryptoPrices_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/chain_service.proto",
+}
+
+const (
+	AlertService_CreateAlertRule_FullMethodName = "/chain.AlertService/CreateAlertRule"
+	AlertService_GetAlertRule_FullMethodName    = "/chain.AlertService/GetAlertRule"
+	AlertService_ListAlertRules_FullMethodName  = "/chain.AlertService/ListAlertRules"
+	AlertService_UpdateAlertRule_FullMethodName = "/chain.AlertService/UpdateAlertRule"
+	AlertService_DeleteAlertRule_FullMethodName = "/chain.AlertService/DeleteAlertRule"
+)
+
+// AlertServiceClient is the client API for AlertService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// 价格告警服务
+type AlertServiceClient interface {
+	CreateAlertRule(ctx context.Context, in *CreateAlertRuleRequest, opts ...grpc.CallOption) (*AlertRuleResponse, error)
+	GetAlertRule(ctx context.Context, in *GetAlertRuleRequest, opts ...grpc.CallOption) (*AlertRuleResponse, error)
+	ListAlertRules(ctx context.Context, in *ListAlertRulesRequest, opts ...grpc.CallOption) (*ListAlertRulesResponse, error)
+	UpdateAlertRule(ctx context.Context, in *UpdateAlertRuleRequest, opts ...grpc.CallOption) (*AlertRuleResponse, error)
+	DeleteAlertRule(ctx context.Context, in *DeleteAlertRuleRequest, opts ...grpc.CallOption) (*DeleteAlertRuleResponse, error)
+}
+
+type alertServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAlertServiceClient(cc grpc.ClientConnInterface) AlertServiceClient {
+	return &alertServiceClient{cc}
+}
+
+func (c *alertServiceClient) CreateAlertRule(ctx context.Context, in *CreateAlertRuleRequest, opts ...grpc.CallOption) (*AlertRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRuleResponse)
+	err := c.cc.Invoke(ctx, AlertService_CreateAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertServiceClient) GetAlertRule(ctx context.Context, in *GetAlertRuleRequest, opts ...grpc.CallOption) (*AlertRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRuleResponse)
+	err := c.cc.Invoke(ctx, AlertService_GetAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertServiceClient) ListAlertRules(ctx context.Context, in *ListAlertRulesRequest, opts ...grpc.CallOption) (*ListAlertRulesResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ListAlertRulesResponse)
+	err := c.cc.Invoke(ctx, AlertService_ListAlertRules_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertServiceClient) UpdateAlertRule(ctx context.Context, in *UpdateAlertRuleRequest, opts ...grpc.CallOption) (*AlertRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AlertRuleResponse)
+	err := c.cc.Invoke(ctx, AlertService_UpdateAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *alertServiceClient) DeleteAlertRule(ctx context.Context, in *DeleteAlertRuleRequest, opts ...grpc.CallOption) (*DeleteAlertRuleResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAlertRuleResponse)
+	err := c.cc.Invoke(ctx, AlertService_DeleteAlertRule_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AlertServiceServer is the server API for AlertService service.
+// All implementations must embed UnimplementedAlertServiceServer
+// for forward compatibility.
+//
+// 价格告警服务
+type AlertServiceServer interface {
+	CreateAlertRule(context.Context, *CreateAlertRuleRequest) (*AlertRuleResponse, error)
+	GetAlertRule(context.Context, *GetAlertRuleRequest) (*AlertRuleResponse, error)
+	ListAlertRules(context.Context, *ListAlertRulesRequest) (*ListAlertRulesResponse, error)
+	UpdateAlertRule(context.Context, *UpdateAlertRuleRequest) (*AlertRuleResponse, error)
+	DeleteAlertRule(context.Context, *DeleteAlertRuleRequest) (*DeleteAlertRuleResponse, error)
+	mustEmbedUnimplementedAlertServiceServer()
+}
+
+// UnimplementedAlertServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAlertServiceServer struct{}
+
+func (UnimplementedAlertServiceServer) CreateAlertRule(context.Context, *CreateAlertRuleRequest) (*AlertRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateAlertRule not implemented")
+}
+func (UnimplementedAlertServiceServer) GetAlertRule(context.Context, *GetAlertRuleRequest) (*AlertRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAlertRule not implemented")
+}
+func (UnimplementedAlertServiceServer) ListAlertRules(context.Context, *ListAlertRulesRequest) (*ListAlertRulesResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAlertRules not implemented")
+}
+func (UnimplementedAlertServiceServer) UpdateAlertRule(context.Context, *UpdateAlertRuleRequest) (*AlertRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method UpdateAlertRule not implemented")
+}
+func (UnimplementedAlertServiceServer) DeleteAlertRule(context.Context, *DeleteAlertRuleRequest) (*DeleteAlertRuleResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method DeleteAlertRule not implemented")
+}
+func (UnimplementedAlertServiceServer) mustEmbedUnimplementedAlertServiceServer() {}
+func (UnimplementedAlertServiceServer) testEmbeddedByValue()                      {}
+
+// UnsafeAlertServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AlertServiceServer will
+// result in compilation errors.
+type UnsafeAlertServiceServer interface {
+	mustEmbedUnimplementedAlertServiceServer()
+}
+
+func RegisterAlertServiceServer(s grpc.ServiceRegistrar, srv AlertServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAlertServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AlertService_ServiceDesc, srv)
+}
+
+func _AlertService_CreateAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateAlertRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).CreateAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_CreateAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).CreateAlertRule(ctx, req.(*CreateAlertRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertService_GetAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAlertRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).GetAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_GetAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).GetAlertRule(ctx, req.(*GetAlertRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertService_ListAlertRules_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ListAlertRulesRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).ListAlertRules(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_ListAlertRules_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).ListAlertRules(ctx, req.(*ListAlertRulesRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertService_UpdateAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UpdateAlertRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).UpdateAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_UpdateAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).UpdateAlertRule(ctx, req.(*UpdateAlertRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AlertService_DeleteAlertRule_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(DeleteAlertRuleRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AlertServiceServer).DeleteAlertRule(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AlertService_DeleteAlertRule_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AlertServiceServer).DeleteAlertRule(ctx, req.(*DeleteAlertRuleRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AlertService_ServiceDesc is the grpc.ServiceDesc for AlertService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AlertService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "chain.AlertService",
+	HandlerType: (*AlertServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CreateAlertRule",
+			Handler:    _AlertService_CreateAlertRule_Handler,
+		},
+		{
+			MethodName: "GetAlertRule",
+			Handler:    _AlertService_GetAlertRule_Handler,
+		},
+		{
+			MethodName: "ListAlertRules",
+			Handler:    _AlertService_ListAlertRules_Handler,
+		},
+		{
+			MethodName: "UpdateAlertRule",
+			Handler:    _AlertService_UpdateAlertRule_Handler,
+		},
+		{
+			MethodName: "DeleteAlertRule",
+			Handler:    _AlertService_DeleteAlertRule_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/chain_service.proto",
+}
+
+const (
+	PortfolioService_GetPortfolio_FullMethodName = "/chain.PortfolioService/GetPortfolio"
+)
+
+// PortfolioServiceClient is the client API for PortfolioService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// 地址资产估值服务
+type PortfolioServiceClient interface {
+	GetPortfolio(ctx context.Context, in *GetPortfolioRequest, opts ...grpc.CallOption) (*GetPortfolioResponse, error)
+}
+
+type portfolioServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewPortfolioServiceClient(cc grpc.ClientConnInterface) PortfolioServiceClient {
+	return &portfolioServiceClient{cc}
+}
+
+func (c *portfolioServiceClient) GetPortfolio(ctx context.Context, in *GetPortfolioRequest, opts ...grpc.CallOption) (*GetPortfolioResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetPortfolioResponse)
+	err := c.cc.Invoke(ctx, PortfolioService_GetPortfolio_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// PortfolioServiceServer is the server API for PortfolioService service.
+// All implementations must embed UnimplementedPortfolioServiceServer
+// for forward compatibility.
+//
+// 地址资产估值服务
+type PortfolioServiceServer interface {
+	GetPortfolio(context.Context, *GetPortfolioRequest) (*GetPortfolioResponse, error)
+	mustEmbedUnimplementedPortfolioServiceServer()
+}
+
+// UnimplementedPortfolioServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedPortfolioServiceServer struct{}
+
+func (UnimplementedPortfolioServiceServer) GetPortfolio(context.Context, *GetPortfolioRequest) (*GetPortfolioResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetPortfolio not implemented")
+}
+func (UnimplementedPortfolioServiceServer) mustEmbedUnimplementedPortfolioServiceServer() {}
+func (UnimplementedPortfolioServiceServer) testEmbeddedByValue()                          {}
+
+// UnsafePortfolioServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to PortfolioServiceServer will
+// result in compilation errors.
+type UnsafePortfolioServiceServer interface {
+	mustEmbedUnimplementedPortfolioServiceServer()
+}
+
+func RegisterPortfolioServiceServer(s grpc.ServiceRegistrar, srv PortfolioServiceServer) {
+	// If the following call pancis, it indicates UnimplementedPortfolioServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&PortfolioService_ServiceDesc, srv)
+}
+
+func _PortfolioService_GetPortfolio_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetPortfolioRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(PortfolioServiceServer).GetPortfolio(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: PortfolioService_GetPortfolio_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(PortfolioServiceServer).GetPortfolio(ctx, req.(*GetPortfolioRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// PortfolioService_ServiceDesc is the grpc.ServiceDesc for PortfolioService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var PortfolioService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "chain.PortfolioService",
+	HandlerType: (*PortfolioServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "GetPortfolio",
+			Handler:    _PortfolioService_GetPortfolio_Handler,
+		},
+	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/chain_service.proto",
 }
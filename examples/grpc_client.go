@@ -113,12 +113,42 @@ func main() {
 	} else {
 		if liquidityResp.Success {
 			pool := liquidityResp.Pool
-			log.Printf("Liquidity Pool: %s\n  Token0: %s\n  Token1: %s\n  Total Liquidity: %s",
-				pool.PairAddress, pool.Token0, pool.Token1, pool.TotalLiquidity)
+			log.Printf("Liquidity Pool: %s\n  Token0: %s\n  Token1: %s\n  TVL (USD): %s\n  LP Price (USD): %s",
+				pool.PairAddress, pool.Token0, pool.Token1, pool.TvlUsd, pool.LpTokenPriceUsd)
 		} else {
 			log.Printf("Get liquidity pool error: %s", liquidityResp.Error)
 		}
 	}
 
+	// 测试获取最近一小时创建的交易对
+	log.Println("\nTesting Get Recent Pairs...")
+	pairsResp, err := bscClient.GetRecentPairs(ctx, &pb.GetRecentPairsRequest{Minutes: 60})
+	if err != nil {
+		log.Printf("Get recent pairs failed: %v", err)
+	} else if pairsResp.Success {
+		log.Printf("Recent pairs: %d", len(pairsResp.Pairs))
+		for _, pair := range pairsResp.Pairs {
+			log.Printf("  [%s] %s: %s/%s", pair.Dex, pair.PairAddress, pair.Token0, pair.Token1)
+		}
+	} else {
+		log.Printf("Get recent pairs error: %s", pairsResp.Error)
+	}
+
+	// 测试订阅新交易对（直到超时）
+	log.Println("\nTesting Stream New Pairs...")
+	stream, err := bscClient.StreamNewPairs(ctx, &pb.StreamNewPairsRequest{})
+	if err != nil {
+		log.Printf("Stream new pairs failed: %v", err)
+	} else {
+		for {
+			pair, err := stream.Recv()
+			if err != nil {
+				log.Printf("Stream ended: %v", err)
+				break
+			}
+			log.Printf("New pair [%s] %s: %s/%s", pair.Dex, pair.PairAddress, pair.Token0, pair.Token1)
+		}
+	}
+
 	log.Println("\nAll tests completed!")
 }
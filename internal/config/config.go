@@ -9,11 +9,17 @@ import (
 
 // Config 应用配置结构
 type Config struct {
-	Server   ServerConfig   `mapstructure:"server"`
-	Chain    ChainConfig    `mapstructure:"chain"`
-	Database DatabaseConfig `mapstructure:"database"`
-	Registry RegistryConfig `mapstructure:"registry"`
-	LogLevel string         `mapstructure:"log_level"`
+	Server      ServerConfig      `mapstructure:"server"`
+	Chain       ChainConfig       `mapstructure:"chain"`
+	BSC         BSCConfig         `mapstructure:"bsc"`
+	Price       PriceConfig       `mapstructure:"price"`
+	PriceStream PriceStreamConfig `mapstructure:"price_stream"`
+	Cache       CacheConfig       `mapstructure:"cache"`
+	Alert       AlertConfig       `mapstructure:"alert"`
+	Portfolio   PortfolioConfig   `mapstructure:"portfolio"`
+	Database    DatabaseConfig    `mapstructure:"database"`
+	Registry    RegistryConfig    `mapstructure:"registry"`
+	LogLevel    string            `mapstructure:"log_level"`
 }
 
 // ServerConfig 服务器配置
@@ -29,6 +35,66 @@ type ChainConfig struct {
 	PrivateKey string `mapstructure:"private_key"`
 	ChainID    int64  `mapstructure:"chain_id"`
 	GasLimit   uint64 `mapstructure:"gas_limit"`
+
+	MulticallAddress   string `mapstructure:"multicall_address"`    // Multicall3合约地址，为空使用默认地址
+	MulticallWait      int    `mapstructure:"multicall_wait"`       // 合并并发只读调用的等待时间（毫秒）
+	MulticallBatchSize int    `mapstructure:"multicall_batch_size"` // 单次aggregate3包含的调用数
+}
+
+// BSCConfig BSC链DEX相关配置
+type BSCConfig struct {
+	BaseTokens []string      `mapstructure:"base_tokens"` // 路由查找时可经过的中间代币地址
+	MaxHops    int           `mapstructure:"max_hops"`    // 兑换路径的最大跳数
+	V3Dexes    []V3DexConfig `mapstructure:"v3_dexes"`    // 集中流动性DEX，留空则使用PancakeSwap V3和Uniswap V3
+
+	LogBlockRange int `mapstructure:"log_block_range"` // 单次查询事件日志的最大区块数
+	StatsCacheTTL int `mapstructure:"stats_cache_ttl"` // 24小时成交量和涨跌幅的缓存时间（秒）
+
+	V2Dexes            []V2DexConfig `mapstructure:"v2_dexes"`             // 建立交易对索引的V2 DEX，留空则只索引PancakeSwap V2
+	PairSyncInterval   int           `mapstructure:"pair_sync_interval"`   // 交易对索引同步间隔（秒），0表示不启动索引
+	PairBackfillBlocks int           `mapstructure:"pair_backfill_blocks"` // 未指定起始区块时首次索引回溯的区块数
+
+	TokenLists       []string `mapstructure:"token_lists"`        // 启动时导入的Uniswap格式代币列表（本地文件或HTTP(S)地址）
+	TokenListHosts   []string `mapstructure:"token_list_hosts"`   // 接口导入代币列表时允许的域名（含子域名），token_lists中的列表不受限制
+	TokenListToken   string   `mapstructure:"token_list_token"`   // 通过接口导入代币列表所需的令牌（Authorization: Bearer），为空时不能通过接口导入
+	TokenMetadataTTL int      `mapstructure:"token_metadata_ttl"` // 代币元数据缓存时间（秒），过期后访问时从链上刷新
+
+	SwapAdminToken string   `mapstructure:"swap_admin_token"` // 执行兑换所需的令牌（Authorization: Bearer），为空时不能执行兑换
+	SwapRecipients []string `mapstructure:"swap_recipients"`  // 签名账户以外允许接收兑换输出的地址
+
+	OracleFeeds         []OracleFeedConfig `mapstructure:"oracle_feeds"`          // Chainlink价格源，留空则使用BNB/USD价格源
+	OracleMaxAge        int                `mapstructure:"oracle_max_age"`        // 预言机价格的最长有效时间（秒），超过视为过期
+	MaxPriceDeviation   float64            `mapstructure:"max_price_deviation"`   // DEX价格与预言机价格的最大偏差（百分比）
+	RejectDeviatedPrice bool               `mapstructure:"reject_deviated_price"` // 偏差超过阈值时拒绝返回价格，否则只做标记
+
+	TWAPPools          []string `mapstructure:"twap_pools"`           // 持续采样累计价格的V2交易对或V3池地址
+	TWAPSampleInterval int      `mapstructure:"twap_sample_interval"` // TWAP采样间隔（秒），0表示不启动采样
+	TWAPRetention      int      `mapstructure:"twap_retention"`       // 累计价格观测的保留时间（秒），也是TWAP的最大时间窗口
+
+	CandleTokens         []string `mapstructure:"candle_tokens"`          // 持续聚合K线的代币地址
+	CandleSyncInterval   int      `mapstructure:"candle_sync_interval"`   // K线聚合同步间隔（秒），0表示不启动后台聚合
+	CandleBackfillBlocks int      `mapstructure:"candle_backfill_blocks"` // 首次聚合代币K线时回溯的区块数
+}
+
+// OracleFeedConfig Chainlink AggregatorV3Interface价格源配置
+type OracleFeedConfig struct {
+	Asset string `mapstructure:"asset"` // 代币地址，BNB使用WBNB地址
+	Feed  string `mapstructure:"feed"`  // 代币/USD价格源合约地址
+}
+
+// V2DexConfig Uniswap V2风格DEX配置
+type V2DexConfig struct {
+	Name       string `mapstructure:"name"`
+	Factory    string `mapstructure:"factory"`
+	StartBlock uint64 `mapstructure:"start_block"` // 首次索引的起始区块，0表示从最新区块回溯
+}
+
+// V3DexConfig Uniswap V3风格DEX配置
+type V3DexConfig struct {
+	Name     string   `mapstructure:"name"`
+	Factory  string   `mapstructure:"factory"`
+	Quoter   string   `mapstructure:"quoter"` // QuoterV2合约地址
+	FeeTiers []uint32 `mapstructure:"fee_tiers"`
 }
 
 // DatabaseConfig 数据库配置
@@ -41,6 +107,86 @@ type DatabaseConfig struct {
 	Driver   string `mapstructure:"driver"`
 }
 
+// PriceConfig 加密货币行情服务配置
+type PriceConfig struct {
+	APIURL  string `mapstructure:"api_url"` // CoinGecko API地址，CoinGecko数据源未指定地址时使用
+	Timeout int    `mapstructure:"timeout"` // 请求超时时间（秒）
+
+	Providers         []PriceProviderConfig `mapstructure:"providers"`           // 行情数据源，留空则只使用CoinGecko
+	Aggregate         string                `mapstructure:"aggregate"`           // 为空时按优先级故障转移，median表示取各数据源价格的中位数
+	RateLimitCooldown int                   `mapstructure:"rate_limit_cooldown"` // 数据源被限流后暂停使用的时间（秒），响应带Retry-After时以其为准
+
+	FXAPIURL string `mapstructure:"fx_api_url"` // 法币汇率接口（ExchangeRate-API格式，以USD为基准），数据源缺少交易对时用于换算
+
+	CoinListRefresh int               `mapstructure:"coin_list_refresh"` // 从CoinGecko刷新币种列表的间隔（秒），0表示不刷新
+	CoinRankPages   int               `mapstructure:"coin_rank_pages"`   // 刷新时读取的市值排名页数（每页250个），用于区分同符号的币种
+	SymbolOverrides map[string]string `mapstructure:"symbol_overrides"`  // 符号、名称或BSC合约地址 => 币种ID，优先于自动解析
+
+	HistoryCoins     []string `mapstructure:"history_coins"`     // 定期记录价格历史的币种（符号、名称或币种ID）
+	HistoryTokens    []string `mapstructure:"history_tokens"`    // 定期记录DEX价格历史的BSC代币地址
+	HistoryInterval  int      `mapstructure:"history_interval"`  // 记录价格历史的间隔（秒），0表示不记录
+	HistoryRetention int      `mapstructure:"history_retention"` // 本地价格历史的保留时间（秒），过期记录由记录任务清理
+}
+
+// PriceStreamConfig 价格订阅推送配置
+type PriceStreamConfig struct {
+	PollInterval   int     `mapstructure:"poll_interval"`   // 每个被订阅资产查询价格的间隔（秒）
+	Epsilon        float64 `mapstructure:"epsilon"`         // 价格相对上次推送变化达到该百分比时推送，0表示任何变化都推送
+	Interval       int     `mapstructure:"interval"`        // 价格未变化时也推送的间隔（秒），0表示只在变化时推送
+	MaxAssets      int     `mapstructure:"max_assets"`      // 单个订阅的最大资产数
+	MaxTopics      int     `mapstructure:"max_topics"`      // 同时轮询的最大资产数
+	MaxSubscribers int     `mapstructure:"max_subscribers"` // 同时存在的最大订阅数
+}
+
+// PriceProviderConfig 行情数据源配置
+type PriceProviderConfig struct {
+	Type     string `mapstructure:"type"`     // coingecko, binance, coinmarketcap, bsc
+	APIURL   string `mapstructure:"api_url"`  // 留空使用官方地址
+	APIKey   string `mapstructure:"api_key"`  // CoinMarketCap必填，CoinGecko Pro可选
+	Priority int    `mapstructure:"priority"` // 数值越小越优先，相同时按配置顺序
+}
+
+// AlertConfig 价格告警配置
+type AlertConfig struct {
+	EvaluateInterval int            `mapstructure:"evaluate_interval"` // 评估告警规则的间隔（秒），0表示不评估
+	Timeout          int            `mapstructure:"timeout"`           // 发送通知的超时时间（秒）
+	SMTP             SMTPConfig     `mapstructure:"smtp"`              // 邮件通知
+	Telegram         TelegramConfig `mapstructure:"telegram"`          // Telegram机器人通知
+	AdminToken       string         `mapstructure:"admin_token"`       // 管理告警规则所需的令牌，为空时不能管理规则
+}
+
+// SMTPConfig 邮件通知使用的SMTP服务器配置
+type SMTPConfig struct {
+	Host     string `mapstructure:"host"` // 为空时不能发送邮件通知
+	Port     int    `mapstructure:"port"`
+	Username string `mapstructure:"username"` // 为空时不认证
+	Password string `mapstructure:"password"`
+	From     string `mapstructure:"from"` // 发件人地址
+}
+
+// TelegramConfig Telegram机器人配置
+type TelegramConfig struct {
+	APIURL   string `mapstructure:"api_url"`   // Bot API地址，默认 https://api.telegram.org
+	BotToken string `mapstructure:"bot_token"` // 为空时不能发送Telegram通知
+}
+
+// PortfolioConfig 地址资产估值配置
+type PortfolioConfig struct {
+	Tokens       []string `mapstructure:"tokens"`        // 链上查询余额的代币地址，留空则使用内置代币
+	MaxAddresses int      `mapstructure:"max_addresses"` // 单次估值的最大地址数
+}
+
+// CacheConfig 行情和链上价格缓存配置
+type CacheConfig struct {
+	Backend       string         `mapstructure:"backend"`        // memory, redis, none
+	RedisAddr     string         `mapstructure:"redis_addr"`     // Redis地址，如 localhost:6379
+	RedisPassword string         `mapstructure:"redis_password"` // Redis密码
+	RedisDB       int            `mapstructure:"redis_db"`       // Redis数据库编号
+	RedisPrefix   string         `mapstructure:"redis_prefix"`   // Redis键前缀
+	StaleTTL      int            `mapstructure:"stale_ttl"`      // 过期后仍可返回旧值并在后台刷新的时间（秒），负数表示不返回过期数据
+	TTLs          map[string]int `mapstructure:"ttls"`           // 各接口的缓存时间（秒），未配置的使用默认值，负数表示不缓存
+}
+
 // RegistryConfig 注册中心配置
 type RegistryConfig struct {
 	Type      string `mapstructure:"type" json:"type"`           // etcd, consul, memory
@@ -82,6 +228,64 @@ func setDefaults() {
 	viper.SetDefault("chain.rpc_url", getEnv("CHAIN_RPC_URL", "https://mainnet.infura.io/v3/your-project-id"))
 	viper.SetDefault("chain.chain_id", getEnvInt("CHAIN_ID", 1))
 	viper.SetDefault("chain.gas_limit", getEnvUint64("GAS_LIMIT", 21000))
+	viper.SetDefault("chain.multicall_address", getEnv("CHAIN_MULTICALL_ADDRESS", ""))
+	viper.SetDefault("chain.multicall_wait", getEnvInt("CHAIN_MULTICALL_WAIT", 5))
+	viper.SetDefault("chain.multicall_batch_size", getEnvInt("CHAIN_MULTICALL_BATCH_SIZE", 100))
+	viper.SetDefault("bsc.max_hops", getEnvInt("BSC_MAX_HOPS", 3))
+	viper.SetDefault("bsc.log_block_range", getEnvInt("BSC_LOG_BLOCK_RANGE", 5000))
+	viper.SetDefault("bsc.stats_cache_ttl", getEnvInt("BSC_STATS_CACHE_TTL", 300))
+	viper.SetDefault("bsc.pair_sync_interval", getEnvInt("BSC_PAIR_SYNC_INTERVAL", 15))
+	viper.SetDefault("bsc.pair_backfill_blocks", getEnvInt("BSC_PAIR_BACKFILL_BLOCKS", 28800))
+	viper.SetDefault("bsc.token_lists", getEnv("BSC_TOKEN_LISTS", "")) // 多个列表用逗号分隔
+	viper.SetDefault("bsc.token_list_hosts", getEnv("BSC_TOKEN_LIST_HOSTS", "tokens.pancakeswap.finance,tokens.coingecko.com"))
+	viper.SetDefault("bsc.token_list_token", getEnv("BSC_TOKEN_LIST_TOKEN", ""))
+	viper.SetDefault("bsc.token_metadata_ttl", getEnvInt("BSC_TOKEN_METADATA_TTL", 86400))
+	viper.SetDefault("bsc.swap_admin_token", getEnv("BSC_SWAP_ADMIN_TOKEN", ""))
+	viper.SetDefault("bsc.swap_recipients", getEnv("BSC_SWAP_RECIPIENTS", "")) // 多个地址用逗号分隔
+	viper.SetDefault("bsc.oracle_max_age", getEnvInt("BSC_ORACLE_MAX_AGE", 3600))
+	viper.SetDefault("bsc.max_price_deviation", getEnvFloat("BSC_MAX_PRICE_DEVIATION", 5))
+	viper.SetDefault("bsc.reject_deviated_price", getEnvBool("BSC_REJECT_DEVIATED_PRICE", false))
+	viper.SetDefault("bsc.twap_pools", getEnv("BSC_TWAP_POOLS", "")) // 多个池子用逗号分隔
+	viper.SetDefault("bsc.twap_sample_interval", getEnvInt("BSC_TWAP_SAMPLE_INTERVAL", 60))
+	viper.SetDefault("bsc.twap_retention", getEnvInt("BSC_TWAP_RETENTION", 604800))
+	viper.SetDefault("bsc.candle_tokens", getEnv("BSC_CANDLE_TOKENS", "")) // 多个代币用逗号分隔
+	viper.SetDefault("bsc.candle_sync_interval", getEnvInt("BSC_CANDLE_SYNC_INTERVAL", 60))
+	viper.SetDefault("bsc.candle_backfill_blocks", getEnvInt("BSC_CANDLE_BACKFILL_BLOCKS", 28800))
+	viper.SetDefault("price.api_url", getEnv("PRICE_API_URL", "https://api.coingecko.com/api/v3"))
+	viper.SetDefault("price.timeout", getEnvInt("PRICE_API_TIMEOUT", 30))
+	viper.SetDefault("price.aggregate", getEnv("PRICE_AGGREGATE", ""))
+	viper.SetDefault("price.rate_limit_cooldown", getEnvInt("PRICE_RATE_LIMIT_COOLDOWN", 60))
+	viper.SetDefault("price.fx_api_url", getEnv("PRICE_FX_API_URL", "https://open.er-api.com/v6/latest/USD"))
+	viper.SetDefault("price.coin_list_refresh", getEnvInt("PRICE_COIN_LIST_REFRESH", 86400))
+	viper.SetDefault("price.coin_rank_pages", getEnvInt("PRICE_COIN_RANK_PAGES", 4))
+	viper.SetDefault("price.history_coins", getEnv("PRICE_HISTORY_COINS", ""))   // 多个币种用逗号分隔
+	viper.SetDefault("price.history_tokens", getEnv("PRICE_HISTORY_TOKENS", "")) // 多个代币用逗号分隔
+	viper.SetDefault("price.history_interval", getEnvInt("PRICE_HISTORY_INTERVAL", 300))
+	viper.SetDefault("price.history_retention", getEnvInt("PRICE_HISTORY_RETENTION", 2592000))
+	viper.SetDefault("price_stream.poll_interval", getEnvInt("PRICE_STREAM_POLL_INTERVAL", 2))
+	viper.SetDefault("price_stream.epsilon", getEnvFloat("PRICE_STREAM_EPSILON", 0.1))
+	viper.SetDefault("price_stream.interval", getEnvInt("PRICE_STREAM_INTERVAL", 30))
+	viper.SetDefault("price_stream.max_assets", getEnvInt("PRICE_STREAM_MAX_ASSETS", 50))
+	viper.SetDefault("price_stream.max_topics", getEnvInt("PRICE_STREAM_MAX_TOPICS", 500))
+	viper.SetDefault("price_stream.max_subscribers", getEnvInt("PRICE_STREAM_MAX_SUBSCRIBERS", 1000))
+	viper.SetDefault("cache.backend", getEnv("CACHE_BACKEND", "memory"))
+	viper.SetDefault("cache.redis_addr", getEnv("CACHE_REDIS_ADDR", "localhost:6379"))
+	viper.SetDefault("cache.redis_password", getEnv("CACHE_REDIS_PASSWORD", ""))
+	viper.SetDefault("cache.redis_db", getEnvInt("CACHE_REDIS_DB", 0))
+	viper.SetDefault("cache.redis_prefix", getEnv("CACHE_REDIS_PREFIX", "chain:cache:"))
+	viper.SetDefault("cache.stale_ttl", getEnvInt("CACHE_STALE_TTL", 30))
+	viper.SetDefault("alert.evaluate_interval", getEnvInt("ALERT_EVALUATE_INTERVAL", 30))
+	viper.SetDefault("alert.timeout", getEnvInt("ALERT_TIMEOUT", 10))
+	viper.SetDefault("alert.smtp.host", getEnv("ALERT_SMTP_HOST", ""))
+	viper.SetDefault("alert.smtp.port", getEnvInt("ALERT_SMTP_PORT", 587))
+	viper.SetDefault("alert.smtp.username", getEnv("ALERT_SMTP_USERNAME", ""))
+	viper.SetDefault("alert.smtp.password", getEnv("ALERT_SMTP_PASSWORD", ""))
+	viper.SetDefault("alert.smtp.from", getEnv("ALERT_SMTP_FROM", ""))
+	viper.SetDefault("alert.telegram.api_url", getEnv("ALERT_TELEGRAM_API_URL", "https://api.telegram.org"))
+	viper.SetDefault("alert.telegram.bot_token", getEnv("ALERT_TELEGRAM_BOT_TOKEN", ""))
+	viper.SetDefault("alert.admin_token", getEnv("ALERT_ADMIN_TOKEN", ""))
+	viper.SetDefault("portfolio.tokens", getEnv("PORTFOLIO_TOKENS", "")) // 多个代币用逗号分隔
+	viper.SetDefault("portfolio.max_addresses", getEnvInt("PORTFOLIO_MAX_ADDRESSES", 20))
 	viper.SetDefault("registry.type", getEnv("REGISTRY_TYPE", "etcd"))
 	viper.SetDefault("registry.endpoints", getEnv("REGISTRY_ENDPOINTS", "localhost:2379"))
 }
@@ -104,6 +308,26 @@ func getEnvInt(key string, defaultValue int64) int64 {
 	return defaultValue
 }
 
+// getEnvFloat 获取浮点型环境变量
+func getEnvFloat(key string, defaultValue float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		if floatValue, err := strconv.ParseFloat(value, 64); err == nil {
+			return floatValue
+		}
+	}
+	return defaultValue
+}
+
+// getEnvBool 获取布尔型环境变量
+func getEnvBool(key string, defaultValue bool) bool {
+	if value := os.Getenv(key); value != "" {
+		if boolValue, err := strconv.ParseBool(value); err == nil {
+			return boolValue
+		}
+	}
+	return defaultValue
+}
+
 // getEnvUint64 获取uint64环境变量
 func getEnvUint64(key string, defaultValue uint64) uint64 {
 	if value := os.Getenv(key); value != "" {
@@ -112,4 +336,4 @@ func getEnvUint64(key string, defaultValue uint64) uint64 {
 		}
 	}
 	return defaultValue
-}
\ No newline at end of file
+}
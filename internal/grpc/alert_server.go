@@ -0,0 +1,206 @@
+package grpc
+
+import (
+	"context"
+	"strings"
+
+	pb "chain/chain/proto"
+	"chain/internal/models"
+	"chain/internal/services"
+
+	"google.golang.org/grpc/metadata"
+)
+
+// AlertServer 价格告警服务gRPC实现
+type AlertServer struct {
+	pb.UnimplementedAlertServiceServer
+	alertService *services.AlertService
+}
+
+// NewAlertServer 创建价格告警服务gRPC服务器
+func NewAlertServer(alertService *services.AlertService) *AlertServer {
+	return &AlertServer{
+		alertService: alertService,
+	}
+}
+
+// authorize 校验请求元数据 authorization: Bearer <token> 中的管理令牌
+func (s *AlertServer) authorize(ctx context.Context) error {
+	return s.alertService.Authorize(bearerToken(ctx))
+}
+
+// bearerToken 读取请求元数据 authorization: Bearer <token> 中的令牌，没有时返回空字符串
+func bearerToken(ctx context.Context) string {
+	if md, ok := metadata.FromIncomingContext(ctx); ok {
+		if values := md.Get("authorization"); len(values) > 0 {
+			if token, ok := strings.CutPrefix(values[0], "Bearer "); ok {
+				return token
+			}
+		}
+	}
+	return ""
+}
+
+// CreateAlertRule 创建告警规则
+func (s *AlertServer) CreateAlertRule(ctx context.Context, req *pb.CreateAlertRuleRequest) (*pb.AlertRuleResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return &pb.AlertRuleResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	rule, err := s.alertService.CreateRule(&models.AlertRule{
+		Name:      req.Name,
+		Asset:     req.Asset,
+		Source:    req.Source,
+		Condition: req.Condition,
+		Threshold: req.Threshold,
+		Window:    int(req.Window),
+		Cooldown:  int(req.Cooldown),
+		Channel:   req.Channel,
+		Target:    req.Target,
+		Enabled:   !req.Disabled,
+	})
+	if err != nil {
+		return &pb.AlertRuleResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &pb.AlertRuleResponse{
+		Success: true,
+		Rule:    toPBAlertRule(rule),
+	}, nil
+}
+
+// GetAlertRule 获取告警规则
+func (s *AlertServer) GetAlertRule(ctx context.Context, req *pb.GetAlertRuleRequest) (*pb.AlertRuleResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return &pb.AlertRuleResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	rule, err := s.alertService.GetRule(uint(req.Id))
+	if err != nil {
+		return &pb.AlertRuleResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &pb.AlertRuleResponse{
+		Success: true,
+		Rule:    toPBAlertRule(rule),
+	}, nil
+}
+
+// ListAlertRules 列出所有告警规则
+func (s *AlertServer) ListAlertRules(ctx context.Context, req *pb.ListAlertRulesRequest) (*pb.ListAlertRulesResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return &pb.ListAlertRulesResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	rules, err := s.alertService.ListRules()
+	if err != nil {
+		return &pb.ListAlertRulesResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	ruleList := make([]*pb.AlertRule, 0, len(rules))
+	for _, rule := range rules {
+		ruleList = append(ruleList, toPBAlertRule(rule))
+	}
+
+	return &pb.ListAlertRulesResponse{
+		Success: true,
+		Rules:   ruleList,
+	}, nil
+}
+
+// UpdateAlertRule 覆盖告警规则
+func (s *AlertServer) UpdateAlertRule(ctx context.Context, req *pb.UpdateAlertRuleRequest) (*pb.AlertRuleResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return &pb.AlertRuleResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	rule, err := s.alertService.UpdateRule(&models.AlertRule{
+		ID:        uint(req.Id),
+		Name:      req.Name,
+		Asset:     req.Asset,
+		Source:    req.Source,
+		Condition: req.Condition,
+		Threshold: req.Threshold,
+		Window:    int(req.Window),
+		Cooldown:  int(req.Cooldown),
+		Channel:   req.Channel,
+		Target:    req.Target,
+		Enabled:   !req.Disabled,
+	})
+	if err != nil {
+		return &pb.AlertRuleResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &pb.AlertRuleResponse{
+		Success: true,
+		Rule:    toPBAlertRule(rule),
+	}, nil
+}
+
+// DeleteAlertRule 删除告警规则
+func (s *AlertServer) DeleteAlertRule(ctx context.Context, req *pb.DeleteAlertRuleRequest) (*pb.DeleteAlertRuleResponse, error) {
+	if err := s.authorize(ctx); err != nil {
+		return &pb.DeleteAlertRuleResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	if err := s.alertService.DeleteRule(uint(req.Id)); err != nil {
+		return &pb.DeleteAlertRuleResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &pb.DeleteAlertRuleResponse{Success: true}, nil
+}
+
+// toPBAlertRule 转换为gRPC告警规则
+func toPBAlertRule(rule *models.AlertRule) *pb.AlertRule {
+	var lastTriggeredAt int64
+	if rule.LastTriggeredAt != nil {
+		lastTriggeredAt = rule.LastTriggeredAt.Unix()
+	}
+
+	return &pb.AlertRule{
+		Id:              uint64(rule.ID),
+		Name:            rule.Name,
+		Asset:           rule.Asset,
+		Source:          rule.Source,
+		Condition:       rule.Condition,
+		Threshold:       rule.Threshold,
+		Window:          int64(rule.Window),
+		Cooldown:        int64(rule.Cooldown),
+		Channel:         rule.Channel,
+		Target:          rule.Target,
+		Enabled:         rule.Enabled,
+		LastTriggeredAt: lastTriggeredAt,
+		CreatedAt:       rule.CreatedAt.Unix(),
+		UpdatedAt:       rule.UpdatedAt.Unix(),
+	}
+}
@@ -0,0 +1,75 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	pb "chain/chain/proto"
+	"chain/internal/config"
+	"chain/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestAlertServer(t *testing.T) {
+	cfg := &config.Config{Alert: config.AlertConfig{AdminToken: "secret"}}
+	server := NewAlertServer(services.NewAlertService(cfg, services.NewPriceService(cfg)))
+
+	// 缺少或错误的管理令牌被拒绝
+	for _, ctx := range []context.Context{
+		context.Background(),
+		metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer wrong")),
+	} {
+		list, err := server.ListAlertRules(ctx, &pb.ListAlertRulesRequest{})
+		require.NoError(t, err)
+		assert.False(t, list.Success)
+		assert.Equal(t, services.ErrAlertUnauthorized.Error(), list.Error)
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer secret"))
+
+	created, err := server.CreateAlertRule(ctx, &pb.CreateAlertRuleRequest{
+		Asset: "bitcoin", Condition: "below", Threshold: 60000, Cooldown: 600, Channel: "telegram", Target: "12345",
+	})
+	require.NoError(t, err)
+	require.True(t, created.Success, created.Error)
+	assert.Equal(t, uint64(1), created.Rule.Id)
+	assert.True(t, created.Rule.Enabled)
+	assert.Equal(t, "crypto", created.Rule.Source)
+	assert.Equal(t, int64(600), created.Rule.Cooldown)
+	assert.Zero(t, created.Rule.LastTriggeredAt)
+	assert.NotZero(t, created.Rule.CreatedAt)
+
+	updated, err := server.UpdateAlertRule(ctx, &pb.UpdateAlertRuleRequest{
+		Id: 1, Asset: "bitcoin", Condition: "change_down", Threshold: 10, Window: 3600, Channel: "webhook", Target: "https://example.com/hook", Disabled: true,
+	})
+	require.NoError(t, err)
+	require.True(t, updated.Success, updated.Error)
+	assert.False(t, updated.Rule.Enabled)
+	assert.Equal(t, int64(3600), updated.Rule.Window)
+
+	got, err := server.GetAlertRule(ctx, &pb.GetAlertRuleRequest{Id: 1})
+	require.NoError(t, err)
+	require.True(t, got.Success)
+	assert.Equal(t, "change_down", got.Rule.Condition)
+
+	list, err := server.ListAlertRules(ctx, &pb.ListAlertRulesRequest{})
+	require.NoError(t, err)
+	assert.Len(t, list.Rules, 1)
+
+	// 业务错误通过响应返回
+	invalid, err := server.CreateAlertRule(ctx, &pb.CreateAlertRuleRequest{Asset: "bitcoin", Condition: "equals", Threshold: 1, Channel: "telegram", Target: "1"})
+	require.NoError(t, err)
+	assert.False(t, invalid.Success)
+	assert.Contains(t, invalid.Error, "unsupported condition equals")
+
+	deleted, err := server.DeleteAlertRule(ctx, &pb.DeleteAlertRuleRequest{Id: 1})
+	require.NoError(t, err)
+	assert.True(t, deleted.Success)
+	deleted, err = server.DeleteAlertRule(ctx, &pb.DeleteAlertRuleRequest{Id: 1})
+	require.NoError(t, err)
+	assert.False(t, deleted.Success)
+	assert.Equal(t, services.ErrAlertRuleNotFound.Error(), deleted.Error)
+}
@@ -0,0 +1,78 @@
+package grpc
+
+import (
+	"context"
+
+	pb "chain/chain/proto"
+	"chain/internal/services"
+)
+
+// PortfolioServer 地址资产估值服务gRPC实现
+type PortfolioServer struct {
+	pb.UnimplementedPortfolioServiceServer
+	portfolioService *services.PortfolioService
+}
+
+// NewPortfolioServer 创建资产估值服务gRPC服务器
+func NewPortfolioServer(portfolioService *services.PortfolioService) *PortfolioServer {
+	return &PortfolioServer{
+		portfolioService: portfolioService,
+	}
+}
+
+// GetPortfolio 获取一个或多个地址的持仓估值
+func (s *PortfolioServer) GetPortfolio(ctx context.Context, req *pb.GetPortfolioRequest) (*pb.GetPortfolioResponse, error) {
+	portfolio, err := s.portfolioService.GetPortfolio(ctx, req.Addresses, req.Tokens)
+	if err != nil {
+		return &pb.GetPortfolioResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	accounts := make([]*pb.AccountPortfolio, 0, len(portfolio.Accounts))
+	for _, account := range portfolio.Accounts {
+		accounts = append(accounts, &pb.AccountPortfolio{
+			Address:                account.Address,
+			Holdings:               toPBPortfolioHoldings(account.Holdings),
+			TotalValueUsd:          account.TotalValueUSD,
+			ValueChange_24H:        account.ValueChange24h,
+			ValueChangePercent_24H: account.ValueChangePercent24h,
+		})
+	}
+
+	return &pb.GetPortfolioResponse{
+		Success:                true,
+		Addresses:              portfolio.Addresses,
+		Holdings:               toPBPortfolioHoldings(portfolio.Holdings),
+		Accounts:               accounts,
+		TotalValueUsd:          portfolio.TotalValueUSD,
+		ValueChange_24H:        portfolio.ValueChange24h,
+		ValueChangePercent_24H: portfolio.ValueChangePercent24h,
+		Warnings:               portfolio.Warnings,
+		UpdatedAt:              portfolio.UpdatedAt.Unix(),
+	}, nil
+}
+
+// toPBPortfolioHoldings 转换持仓列表为gRPC消息
+func toPBPortfolioHoldings(holdings []*services.PortfolioHolding) []*pb.PortfolioHolding {
+	result := make([]*pb.PortfolioHolding, 0, len(holdings))
+	for _, holding := range holdings {
+		result = append(result, &pb.PortfolioHolding{
+			Token:                  holding.Token,
+			Symbol:                 holding.Symbol,
+			Name:                   holding.Name,
+			Decimals:               uint32(holding.Decimals),
+			Native:                 holding.Native,
+			Balance:                holding.Balance,
+			BalanceRaw:             holding.BalanceRaw,
+			PriceUsd:               holding.PriceUSD,
+			PriceChangePercent_24H: holding.PriceChangePercent24h,
+			ValueUsd:               holding.ValueUSD,
+			ValueChange_24H:        holding.ValueChange24h,
+			PriceSource:            holding.PriceSource,
+			Sources:                holding.Sources,
+		})
+	}
+	return result
+}
@@ -0,0 +1,80 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	pb "chain/chain/proto"
+	"chain/internal/config"
+	"chain/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPortfolioServer(t *testing.T) {
+	// 模拟没有部署合约的节点，每个账户持有2 BNB
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		type request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		reply := func(req request) map[string]interface{} {
+			result := "0x"
+			if req.Method == "eth_getBalance" {
+				result = "0x1bc16d674ec80000"
+			}
+			return map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": result}
+		}
+
+		var raw json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		var batch []request
+		if err := json.Unmarshal(raw, &batch); err != nil {
+			var single request
+			require.NoError(t, json.Unmarshal(raw, &single))
+			json.NewEncoder(w).Encode(reply(single))
+			return
+		}
+		results := make([]map[string]interface{}, 0, len(batch))
+		for _, req := range batch {
+			results = append(results, reply(req))
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	defer node.Close()
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusServiceUnavailable)
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{
+		Chain: config.ChainConfig{RPCURL: node.URL, ChainID: 56, MulticallWait: 1},
+		Price: config.PriceConfig{APIURL: upstream.URL, Timeout: 5},
+	}
+	server := NewPortfolioServer(services.NewPortfolioService(cfg, services.NewBSCService(cfg), services.NewPriceService(cfg)))
+	ctx := context.Background()
+
+	resp, err := server.GetPortfolio(ctx, &pb.GetPortfolioRequest{Addresses: []string{"0x00000000000000000000000000000000000a11ce"}})
+	require.NoError(t, err)
+	require.True(t, resp.Success, resp.Error)
+	assert.Equal(t, []string{"0x00000000000000000000000000000000000A11cE"}, resp.Addresses)
+	require.Len(t, resp.Holdings, 1)
+	assert.True(t, resp.Holdings[0].Native)
+	assert.Equal(t, "2", resp.Holdings[0].Balance)
+	assert.Equal(t, []string{services.HoldingSourceChain}, resp.Holdings[0].Sources)
+	require.Len(t, resp.Accounts, 1)
+	assert.Len(t, resp.Accounts[0].Holdings, 1)
+	assert.Zero(t, resp.TotalValueUsd)
+	assert.NotEmpty(t, resp.Warnings)
+	assert.NotZero(t, resp.UpdatedAt)
+
+	// 业务错误通过响应返回
+	invalid, err := server.GetPortfolio(ctx, &pb.GetPortfolioRequest{Addresses: []string{"0x123"}})
+	require.NoError(t, err)
+	assert.False(t, invalid.Success)
+	assert.Contains(t, invalid.Error, "invalid address: 0x123")
+}
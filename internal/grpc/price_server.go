@@ -2,27 +2,34 @@ package grpc
 
 import (
 	"context"
+	"errors"
+	"time"
 
 	pb "chain/chain/proto"
 	"chain/internal/services"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 )
 
 // PriceServer 价格服务gRPC实现
 type PriceServer struct {
 	pb.UnimplementedPriceServiceServer
 	priceService *services.PriceService
+	priceStreams *services.PriceStreamService
 }
 
 // NewPriceServer 创建价格服务gRPC服务器
-func NewPriceServer(priceService *services.PriceService) *PriceServer {
+func NewPriceServer(priceService *services.PriceService, priceStreams *services.PriceStreamService) *PriceServer {
 	return &PriceServer{
 		priceService: priceService,
+		priceStreams: priceStreams,
 	}
 }
 
 // GetCryptoPrice 获取单个加密货币价格
 func (s *PriceServer) GetCryptoPrice(ctx context.Context, req *pb.GetCryptoPriceRequest) (*pb.GetCryptoPriceResponse, error) {
-	price, err := s.priceService.GetCryptoPrice(ctx, req.Symbol)
+	price, err := s.priceService.GetCryptoPrice(ctx, req.Symbol, req.Currencies...)
 	if err != nil {
 		return &pb.GetCryptoPriceResponse{
 			Success: false,
@@ -32,22 +39,13 @@ func (s *PriceServer) GetCryptoPrice(ctx context.Context, req *pb.GetCryptoPrice
 
 	return &pb.GetCryptoPriceResponse{
 		Success: true,
-		Price: &pb.CryptoPriceInfo{
-			Symbol:                 price.Symbol,
-			Name:                   price.Name,
-			CurrentPrice:           price.CurrentPrice,
-			MarketCap:              price.MarketCap,
-			Volume_24H:             price.Volume24h,
-			PriceChange_24H:        price.PriceChange24h,
-			PriceChangePercent_24H: price.PriceChangePercent24h,
-			LastUpdated:           price.LastUpdated.Format("2006-01-02 15:04:05"),
-		},
+		Price:   toPBCryptoPrice(price),
 	}, nil
 }
 
 // GetMultipleCryptoPrices 获取多个加密货币价格
 func (s *PriceServer) GetMultipleCryptoPrices(ctx context.Context, req *pb.GetMultipleCryptoPricesRequest) (*pb.GetMultipleCryptoPricesResponse, error) {
-	prices, err := s.priceService.GetMultipleCryptoPrices(ctx, req.Symbols)
+	prices, err := s.priceService.GetMultipleCryptoPrices(ctx, req.Symbols, req.Currencies...)
 	if err != nil {
 		return &pb.GetMultipleCryptoPricesResponse{
 			Success: false,
@@ -57,16 +55,7 @@ func (s *PriceServer) GetMultipleCryptoPrices(ctx context.Context, req *pb.GetMu
 
 	pricesMap := make(map[string]*pb.CryptoPriceInfo)
 	for symbol, price := range prices {
-		pricesMap[symbol] = &pb.CryptoPriceInfo{
-			Symbol:                 price.Symbol,
-			Name:                   price.Name,
-			CurrentPrice:           price.CurrentPrice,
-			MarketCap:              price.MarketCap,
-			Volume_24H:             price.Volume24h,
-			PriceChange_24H:        price.PriceChange24h,
-			PriceChangePercent_24H: price.PriceChangePercent24h,
-			LastUpdated:           price.LastUpdated.Format("2006-01-02 15:04:05"),
-		}
+		pricesMap[symbol] = toPBCryptoPrice(price)
 	}
 
 	return &pb.GetMultipleCryptoPricesResponse{
@@ -77,7 +66,7 @@ func (s *PriceServer) GetMultipleCryptoPrices(ctx context.Context, req *pb.GetMu
 
 // GetTopCryptoPrices 获取市值排名前N的加密货币价格
 func (s *PriceServer) GetTopCryptoPrices(ctx context.Context, req *pb.GetTopCryptoPricesRequest) (*pb.GetTopCryptoPricesResponse, error) {
-	prices, err := s.priceService.GetTopCryptoPrices(ctx, int(req.Limit))
+	prices, err := s.priceService.GetTopCryptoPrices(ctx, int(req.Limit), req.Currencies...)
 	if err != nil {
 		return &pb.GetTopCryptoPricesResponse{
 			Success: false,
@@ -87,16 +76,7 @@ func (s *PriceServer) GetTopCryptoPrices(ctx context.Context, req *pb.GetTopCryp
 
 	var priceList []*pb.CryptoPriceInfo
 	for _, price := range prices {
-		priceList = append(priceList, &pb.CryptoPriceInfo{
-			Symbol:                 price.Symbol,
-			Name:                   price.Name,
-			CurrentPrice:           price.CurrentPrice,
-			MarketCap:              price.MarketCap,
-			Volume_24H:             price.Volume24h,
-			PriceChange_24H:        price.PriceChange24h,
-			PriceChangePercent_24H: price.PriceChangePercent24h,
-			LastUpdated:           price.LastUpdated.Format("2006-01-02 15:04:05"),
-		})
+		priceList = append(priceList, toPBCryptoPrice(price))
 	}
 
 	return &pb.GetTopCryptoPricesResponse{
@@ -107,7 +87,7 @@ func (s *PriceServer) GetTopCryptoPrices(ctx context.Context, req *pb.GetTopCryp
 
 // SearchCrypto 搜索加密货币
 func (s *PriceServer) SearchCrypto(ctx context.Context, req *pb.SearchCryptoRequest) (*pb.SearchCryptoResponse, error) {
-	results, err := s.priceService.SearchCrypto(ctx, req.Query)
+	results, err := s.priceService.SearchCrypto(ctx, req.Query, req.Currencies...)
 	if err != nil {
 		return &pb.SearchCryptoResponse{
 			Success: false,
@@ -117,16 +97,7 @@ func (s *PriceServer) SearchCrypto(ctx context.Context, req *pb.SearchCryptoRequ
 
 	var resultList []*pb.CryptoPriceInfo
 	for _, result := range results {
-		resultList = append(resultList, &pb.CryptoPriceInfo{
-			Symbol:                 result.Symbol,
-			Name:                   result.Name,
-			CurrentPrice:           result.CurrentPrice,
-			MarketCap:              result.MarketCap,
-			Volume_24H:             result.Volume24h,
-			PriceChange_24H:        result.PriceChange24h,
-			PriceChangePercent_24H: result.PriceChangePercent24h,
-			LastUpdated:           result.LastUpdated.Format("2006-01-02 15:04:05"),
-		})
+		resultList = append(resultList, toPBCryptoPrice(result))
 	}
 
 	return &pb.SearchCryptoResponse{
@@ -137,7 +108,19 @@ func (s *PriceServer) SearchCrypto(ctx context.Context, req *pb.SearchCryptoRequ
 
 // GetPriceHistory 获取价格历史
 func (s *PriceServer) GetPriceHistory(ctx context.Context, req *pb.GetPriceHistoryRequest) (*pb.GetPriceHistoryResponse, error) {
-	prices, err := s.priceService.GetPriceHistory(ctx, req.Symbol, int(req.Days))
+	query := services.HistoryQuery{
+		Days:     int(req.Days),
+		Interval: req.Interval,
+		Currency: req.Currency,
+	}
+	if req.From > 0 {
+		query.From = time.Unix(req.From, 0).UTC()
+	}
+	if req.To > 0 {
+		query.To = time.Unix(req.To, 0).UTC()
+	}
+
+	history, err := s.priceService.GetPriceHistory(ctx, req.Symbol, query)
 	if err != nil {
 		return &pb.GetPriceHistoryResponse{
 			Success: false,
@@ -145,8 +128,153 @@ func (s *PriceServer) GetPriceHistory(ctx context.Context, req *pb.GetPriceHisto
 		}, nil
 	}
 
+	points := make([]*pb.PricePoint, 0, len(history.Points))
+	for _, point := range history.Points {
+		points = append(points, &pb.PricePoint{
+			Timestamp: point.Timestamp.Unix(),
+			Price:     point.Price,
+			MarketCap: point.MarketCap,
+			Volume:    point.Volume,
+		})
+	}
+	candles := make([]*pb.PriceHistoryCandle, 0, len(history.Candles))
+	for _, candle := range history.Candles {
+		candles = append(candles, &pb.PriceHistoryCandle{
+			Timestamp: candle.Timestamp.Unix(),
+			Open:      candle.Open,
+			High:      candle.High,
+			Low:       candle.Low,
+			Close:     candle.Close,
+			Volume:    candle.Volume,
+			MarketCap: candle.MarketCap,
+		})
+	}
+
 	return &pb.GetPriceHistoryResponse{
-		Success: true,
-		Prices:  prices,
+		Success:   true,
+		Prices:    history.Prices,
+		Source:    history.Source,
+		Points:    points,
+		Candles:   candles,
+		Interval:  history.Interval,
+		Currency:  history.Currency,
+		CoinId:    history.CoinID,
+		From:      history.From.Unix(),
+		To:        history.To.Unix(),
+		Converted: history.Converted,
+		Sources:   history.Sources,
 	}, nil
-}
\ No newline at end of file
+}
+
+// SubscribeTokenPrices 订阅BSC代币的DEX价格，直到客户端断开
+func (s *PriceServer) SubscribeTokenPrices(req *pb.SubscribeTokenPricesRequest, stream pb.PriceService_SubscribeTokenPricesServer) error {
+	updates, cancel, err := s.priceStreams.SubscribeTokenPrices(req.Tokens, subscribeOptions(req.Epsilon, req.Interval))
+	if err != nil {
+		return subscribeError(err)
+	}
+	defer cancel()
+
+	return sendPriceUpdates(stream.Context(), updates, func(update *services.PriceUpdate) error {
+		msg := &pb.TokenPriceUpdate{
+			Token:     update.Asset,
+			UpdatedAt: update.At.Unix(),
+		}
+		if update.Err != nil {
+			msg.Error = update.Err.Error()
+		} else {
+			msg.Price = toPBTokenPrice(update.Token)
+		}
+		return stream.Send(msg)
+	})
+}
+
+// SubscribeCryptoPrices 订阅加密货币行情价格，直到客户端断开
+func (s *PriceServer) SubscribeCryptoPrices(req *pb.SubscribeCryptoPricesRequest, stream pb.PriceService_SubscribeCryptoPricesServer) error {
+	updates, cancel, err := s.priceStreams.SubscribeCryptoPrices(req.Symbols, req.Currencies, subscribeOptions(req.Epsilon, req.Interval))
+	if err != nil {
+		return subscribeError(err)
+	}
+	defer cancel()
+
+	return sendPriceUpdates(stream.Context(), updates, func(update *services.PriceUpdate) error {
+		msg := &pb.CryptoPriceUpdate{
+			Symbol:    update.Asset,
+			UpdatedAt: update.At.Unix(),
+		}
+		if update.Err != nil {
+			msg.Error = update.Err.Error()
+		} else {
+			msg.Price = toPBCryptoPrice(update.Crypto)
+		}
+		return stream.Send(msg)
+	})
+}
+
+// subscribeOptions 转换订阅请求中的推送条件，间隔单位为秒
+func subscribeOptions(epsilon float64, interval int64) services.PriceSubscribeOptions {
+	return services.PriceSubscribeOptions{
+		Epsilon:  epsilon,
+		Interval: time.Duration(interval) * time.Second,
+	}
+}
+
+// subscribeError 订阅参数无效时返回InvalidArgument，达到订阅上限时返回ResourceExhausted
+func subscribeError(err error) error {
+	if errors.Is(err, services.ErrInvalidPriceSubscription) {
+		return status.Error(codes.InvalidArgument, err.Error())
+	}
+	if errors.Is(err, services.ErrPriceStreamLimit) {
+		return status.Error(codes.ResourceExhausted, err.Error())
+	}
+	return status.Error(codes.FailedPrecondition, err.Error())
+}
+
+// sendPriceUpdates 持续发送价格更新，直到客户端断开或发送失败
+func sendPriceUpdates(ctx context.Context, updates <-chan *services.PriceUpdate, send func(update *services.PriceUpdate) error) error {
+	for {
+		select {
+		case <-ctx.Done():
+			return nil
+		case update, ok := <-updates:
+			if !ok {
+				return nil
+			}
+			if err := send(update); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+// toPBCryptoPrice 转换为gRPC价格信息
+func toPBCryptoPrice(price *services.CryptoPriceInfo) *pb.CryptoPriceInfo {
+	var quotes map[string]*pb.PriceQuote
+	if len(price.Quotes) > 0 {
+		quotes = make(map[string]*pb.PriceQuote, len(price.Quotes))
+		for currency, quote := range price.Quotes {
+			quotes[currency] = &pb.PriceQuote{
+				Price:                  quote.Price,
+				MarketCap:              quote.MarketCap,
+				Volume_24H:             quote.Volume24h,
+				PriceChange_24H:        quote.PriceChange24h,
+				PriceChangePercent_24H: quote.PriceChangePercent24h,
+				Converted:              quote.Converted,
+			}
+		}
+	}
+
+	return &pb.CryptoPriceInfo{
+		Symbol:                 price.Symbol,
+		Name:                   price.Name,
+		CurrentPrice:           price.CurrentPrice,
+		MarketCap:              price.MarketCap,
+		Volume_24H:             price.Volume24h,
+		PriceChange_24H:        price.PriceChange24h,
+		PriceChangePercent_24H: price.PriceChangePercent24h,
+		LastUpdated:            price.LastUpdated.Format("2006-01-02 15:04:05"),
+		Source:                 price.Source,
+		Sources:                price.Sources,
+		CoinId:                 price.CoinID,
+		Quotes:                 quotes,
+	}
+}
@@ -0,0 +1,152 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	pb "chain/chain/proto"
+	"chain/internal/config"
+	"chain/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestPriceServer(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/coins/markets":
+			json.NewEncoder(w).Encode([]map[string]interface{}{
+				{"id": "bitcoin", "symbol": "btc", "name": "Bitcoin", "current_price": 65000.5, "last_updated": "2026-01-01T00:00:00Z"},
+			})
+		case "/simple/price":
+			json.NewEncoder(w).Encode(map[string]interface{}{"bitcoin": map[string]float64{"eur": 60000, "eur_24h_change": 2}})
+		case "/coins/bitcoin/market_chart/range":
+			assert.Equal(t, "eur", r.URL.Query().Get("vs_currency"))
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"prices":      [][]float64{{1767225600000, 64000}, {1767229200000, 65000}},
+				"market_caps": [][]float64{{1767225600000, 1.2e12}, {1767229200000, 1.3e12}},
+			})
+		default:
+			w.WriteHeader(http.StatusServiceUnavailable)
+		}
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{Price: config.PriceConfig{APIURL: upstream.URL, Timeout: 5}}
+	priceService := services.NewPriceService(cfg)
+	server := NewPriceServer(priceService, services.NewPriceStreamService(cfg, nil, priceService))
+	ctx := context.Background()
+
+	price, err := server.GetCryptoPrice(ctx, &pb.GetCryptoPriceRequest{Symbol: "bitcoin"})
+	require.NoError(t, err)
+	require.True(t, price.Success, price.Error)
+	assert.Equal(t, 65000.5, price.Price.CurrentPrice)
+	assert.Equal(t, "2026-01-01 00:00:00", price.Price.LastUpdated)
+	assert.Equal(t, "coingecko", price.Price.Source)
+	assert.Empty(t, price.Price.Quotes)
+
+	// 指定计价货币时在quotes中返回
+	price, err = server.GetCryptoPrice(ctx, &pb.GetCryptoPriceRequest{Symbol: "bitcoin", Currencies: []string{"EUR", "usd"}})
+	require.NoError(t, err)
+	require.True(t, price.Success, price.Error)
+	require.Len(t, price.Price.Quotes, 2)
+	assert.Equal(t, 60000.0, price.Price.Quotes["eur"].Price)
+	assert.Equal(t, 2.0, price.Price.Quotes["eur"].PriceChangePercent_24H)
+	assert.False(t, price.Price.Quotes["eur"].Converted)
+	assert.Equal(t, 65000.5, price.Price.Quotes["usd"].Price)
+
+	price, err = server.GetCryptoPrice(ctx, &pb.GetCryptoPriceRequest{Symbol: "bitcoin", Currencies: []string{"gbp"}})
+	require.NoError(t, err)
+	assert.False(t, price.Success)
+	assert.Equal(t, "unsupported quote currency: gbp", price.Error)
+
+	history, err := server.GetPriceHistory(ctx, &pb.GetPriceHistoryRequest{
+		Symbol: "bitcoin", From: 1767225600, To: 1767232800, Interval: "1d", Currency: "EUR",
+	})
+	require.NoError(t, err)
+	require.True(t, history.Success, history.Error)
+	assert.Equal(t, []float64{64000, 65000}, history.Prices)
+	assert.Equal(t, "coingecko", history.Source)
+	assert.Equal(t, "eur", history.Currency)
+	require.Len(t, history.Points, 2)
+	assert.Equal(t, int64(1767229200), history.Points[1].Timestamp)
+	assert.Equal(t, 1.3e12, history.Points[1].MarketCap)
+	// 由价格点聚合为日K线
+	require.Len(t, history.Candles, 1)
+	assert.Equal(t, &pb.PriceHistoryCandle{Timestamp: 1767225600, Open: 64000, High: 65000, Low: 64000, Close: 65000, MarketCap: 1.3e12}, history.Candles[0])
+
+	history, err = server.GetPriceHistory(ctx, &pb.GetPriceHistoryRequest{Symbol: "bitcoin", Interval: "2h"})
+	require.NoError(t, err)
+	assert.False(t, history.Success)
+	assert.Contains(t, history.Error, "unsupported interval 2h")
+
+	// 上游错误通过响应返回而不是gRPC错误
+	search, err := server.SearchCrypto(ctx, &pb.SearchCryptoRequest{Query: "btc"})
+	require.NoError(t, err)
+	assert.False(t, search.Success)
+	assert.Contains(t, search.Error, "status: 503")
+}
+
+// fakeCryptoPriceStream 记录发送的价格更新
+type fakeCryptoPriceStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent chan *pb.CryptoPriceUpdate
+}
+
+func (f *fakeCryptoPriceStream) Context() context.Context {
+	return f.ctx
+}
+
+func (f *fakeCryptoPriceStream) Send(update *pb.CryptoPriceUpdate) error {
+	f.sent <- update
+	return nil
+}
+
+func TestPriceServerSubscribeCryptoPrices(t *testing.T) {
+	upstream := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode([]map[string]interface{}{
+			{"id": "bitcoin", "symbol": "btc", "name": "Bitcoin", "current_price": 65000.5, "last_updated": "2026-01-01T00:00:00Z"},
+		})
+	}))
+	defer upstream.Close()
+
+	cfg := &config.Config{Price: config.PriceConfig{APIURL: upstream.URL, Timeout: 5}}
+	priceService := services.NewPriceService(cfg)
+	server := NewPriceServer(priceService, services.NewPriceStreamService(cfg, nil, priceService))
+
+	ctx, cancel := context.WithCancel(context.Background())
+	stream := &fakeCryptoPriceStream{ctx: ctx, sent: make(chan *pb.CryptoPriceUpdate, 1)}
+	done := make(chan error, 1)
+	go func() {
+		done <- server.SubscribeCryptoPrices(&pb.SubscribeCryptoPricesRequest{Symbols: []string{"bitcoin"}}, stream)
+	}()
+
+	select {
+	case update := <-stream.sent:
+		assert.Equal(t, "bitcoin", update.Symbol)
+		assert.Empty(t, update.Error)
+		assert.Equal(t, 65000.5, update.Price.CurrentPrice)
+		assert.NotZero(t, update.UpdatedAt)
+	case <-time.After(5 * time.Second):
+		t.Fatal("no price update received")
+	}
+
+	// 客户端断开后结束订阅
+	cancel()
+	require.NoError(t, <-done)
+
+	// 订阅参数无效时返回InvalidArgument
+	err := server.SubscribeCryptoPrices(&pb.SubscribeCryptoPricesRequest{Symbols: []string{"bitcoin"}, Epsilon: -1}, stream)
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+	err = server.SubscribeTokenPrices(&pb.SubscribeTokenPricesRequest{Tokens: []string{"0x00000000000000000000000000000000000000ca"}}, nil)
+	assert.Equal(t, codes.FailedPrecondition, status.Code(err))
+}
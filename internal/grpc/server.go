@@ -9,12 +9,17 @@ import (
 	"time"
 
 	"chain/internal/config"
+	"chain/internal/database"
+	"chain/internal/models"
 	"chain/internal/registry"
 	"chain/internal/services"
 	pb "chain/chain/proto"
 
+	"github.com/ethereum/go-ethereum/common"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/reflection"
+	"google.golang.org/grpc/status"
 )
 
 // Server gRPC服务器
@@ -22,9 +27,16 @@ type Server struct {
 	grpcServer   *grpc.Server
 	chainService *services.ChainService
 	bscService   *services.BSCService
+	priceService *services.PriceService
+	alertService *services.AlertService
 	config       *config.Config
 	registry     registry.Registry
 	serviceID    string
+
+	// 后台任务及其生命周期，随服务器启动和停止
+	jobs        *services.BackgroundJobs
+	indexerCtx  context.Context
+	stopIndexer context.CancelFunc
 }
 
 // NewServer 创建新的gRPC服务器
@@ -32,26 +44,67 @@ func NewServer(cfg *config.Config) *Server {
 	chainService := services.NewChainService(cfg)
 	bscService := services.NewBSCService(cfg)
 
+	priceService := services.NewPriceService(cfg)
+	alertService := services.NewAlertService(cfg, priceService)
+	portfolioService := services.NewPortfolioService(cfg, bscService, priceService)
+	priceStreams := services.NewPriceStreamService(cfg, bscService, priceService)
+
+	// 价格快照、交易对索引、代币信息、TWAP观测、K线、币种列表、价格历史和告警规则优先持久化到数据库，资产估值合并数据库中保存的代币余额，数据库不可用时保存在内存中
+	if db, err := database.New(&cfg.Database); err != nil {
+		log.Printf("Database unavailable, price snapshots, pairs, tokens, pool observations, candles, coins, price history and alert rules kept in memory, stored token balances skipped: %v", err)
+	} else if err := db.AutoMigrate(
+		&models.PriceSnapshot{}, &models.DexPair{}, &models.PairSyncState{}, &models.Token{},
+		&models.PoolObservation{}, &models.PriceCandle{}, &models.CandleSyncState{}, &models.Coin{},
+		&models.HistoricalPrice{}, &models.AlertRule{}, &models.Account{}, &models.TokenBalance{},
+	); err != nil {
+		log.Printf("Failed to migrate price snapshots, pairs, tokens, pool observations, candles, coins, price history, alert rules and token balances: %v", err)
+	} else {
+		bscService.SetSnapshotStore(services.NewDBSnapshotStore(db.GetDB()))
+		bscService.SetPairStore(services.NewDBPairStore(db.GetDB()))
+		bscService.SetTokenStore(services.NewDBTokenStore(db.GetDB()))
+		bscService.SetObservationStore(services.NewDBObservationStore(db.GetDB()))
+		bscService.SetCandleStore(services.NewDBCandleStore(db.GetDB()))
+		priceService.SetCoinStore(services.NewDBCoinStore(db.GetDB()))
+		priceService.SetHistoryStore(services.NewDBHistoryStore(db.GetDB()))
+		alertService.SetStore(services.NewDBAlertStore(db.GetDB()))
+		portfolioService.SetBalanceStore(services.NewDBTokenBalanceStore(db.GetDB()))
+	}
+
 	// 初始化注册中心
 	reg := registry.NewRegistry(cfg.Registry.Type, cfg.Registry.Endpoints)
 
 	// 生成服务ID
 	serviceID := fmt.Sprintf("chain-grpc-%d", time.Now().Unix())
 
+	indexerCtx, stopIndexer := context.WithCancel(context.Background())
+
 	s := &Server{
 		grpcServer:   grpc.NewServer(),
 		chainService: chainService,
 		bscService:   bscService,
+		priceService: priceService,
+		alertService: alertService,
 		config:       cfg,
 		registry:     reg,
 		serviceID:    serviceID,
+		jobs:         services.NewBackgroundJobs(bscService, priceService, alertService),
+		indexerCtx:   indexerCtx,
+		stopIndexer:  stopIndexer,
 	}
 
 	// 注册服务
 	pb.RegisterChainServiceServer(s.grpcServer, &chainServiceServer{chainService: chainService})
 	pb.RegisterBSCServiceServer(s.grpcServer, &bscServiceServer{bscService: bscService})
 	pb.RegisterHealthServiceServer(s.grpcServer, &healthServiceServer{})
-	pb.RegisterPriceServiceServer(s.grpcServer, &PriceServer{})
+	// BSC代币价格和行情共用同一个缓存
+	cache := services.NewCache(cfg)
+	bscService.SetCache(cache)
+	priceService.SetBSCService(bscService)
+	priceService.SetCache(cache)
+	pb.RegisterPriceServiceServer(s.grpcServer, NewPriceServer(priceService, priceStreams))
+	alertService.SetBSCService(bscService)
+	pb.RegisterAlertServiceServer(s.grpcServer, NewAlertServer(alertService))
+	pb.RegisterPortfolioServiceServer(s.grpcServer, NewPortfolioServer(portfolioService))
 
 	// 启用反射（用于调试）
 	reflection.Register(s.grpcServer)
@@ -89,6 +142,9 @@ func (s *Server) Start() error {
 		log.Printf("Service registered successfully with ID: %s", s.serviceID)
 	}
 
+	// 启动后台任务，交易对索引为新交易对订阅提供数据
+	s.jobs.Start(s.indexerCtx)
+
 	log.Printf("gRPC server starting on port %s", s.config.Server.GRPCPort)
 	return s.grpcServer.Serve(lis)
 }
@@ -108,6 +164,8 @@ func (s *Server) Stop() {
 		log.Printf("Failed to close registry: %v", err)
 	}
 
+	s.stopIndexer()
+
 	s.grpcServer.GracefulStop()
 }
 
@@ -133,6 +191,29 @@ func (s *chainServiceServer) GetBalance(ctx context.Context, req *pb.GetBalanceR
 	}, nil
 }
 
+func (s *chainServiceServer) GetBalances(ctx context.Context, req *pb.GetBalancesRequest) (*pb.GetBalancesResponse, error) {
+	balances, err := s.chainService.GetBalances(req.Addresses)
+	if err != nil {
+		return &pb.GetBalancesResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	pbBalances := make([]*pb.AccountBalance, len(balances))
+	for i, balance := range balances {
+		pbBalances[i] = &pb.AccountBalance{
+			Address: req.Addresses[i],
+			Balance: balance,
+		}
+	}
+
+	return &pb.GetBalancesResponse{
+		Balances: pbBalances,
+		Success:  true,
+	}, nil
+}
+
 func (s *chainServiceServer) Transfer(ctx context.Context, req *pb.TransferRequest) (*pb.TransferResponse, error) {
 	txHash, err := s.chainService.Transfer(req.To, req.Amount)
 	if err != nil {
@@ -229,17 +310,33 @@ func (s *bscServiceServer) GetTokenInfo(ctx context.Context, req *pb.GetTokenInf
 
 	return &pb.GetTokenInfoResponse{
 		Token: &pb.TokenInfo{
-			Address:  tokenInfo.Address,
-			Name:     tokenInfo.Name,
-			Symbol:   tokenInfo.Symbol,
-			Decimals: uint32(tokenInfo.Decimals),
+			Address:        tokenInfo.Address,
+			Name:           tokenInfo.Name,
+			Symbol:         tokenInfo.Symbol,
+			Decimals:       uint32(tokenInfo.Decimals),
+			TotalSupply:    tokenInfo.TotalSupply,
+			TotalSupplyRaw: tokenInfo.TotalSupplyRaw,
+			IsProxy:        tokenInfo.IsProxy,
+			Implementation: tokenInfo.Implementation,
 		},
 		Success: true,
 	}, nil
 }
 
 func (s *bscServiceServer) SearchToken(ctx context.Context, req *pb.SearchTokenRequest) (*pb.SearchTokenResponse, error) {
-	tokens, err := s.bscService.FindTokenByName(req.Name)
+	if req.Limit > 100 {
+		return &pb.SearchTokenResponse{
+			Success: false,
+			Error:   "limit must not exceed 100",
+		}, nil
+	}
+
+	tokens, err := s.bscService.SearchTokens(services.TokenQuery{
+		Text:         req.Name,
+		Prefix:       req.Prefix,
+		VerifiedOnly: req.VerifiedOnly,
+		Limit:        int(req.Limit),
+	})
 	if err != nil {
 		return &pb.SearchTokenResponse{
 			Success: false,
@@ -254,6 +351,8 @@ func (s *bscServiceServer) SearchToken(ctx context.Context, req *pb.SearchTokenR
 			Name:     token.Name,
 			Symbol:   token.Symbol,
 			Decimals: uint32(token.Decimals),
+			LogoUri:  token.LogoURI,
+			Verified: token.Verified,
 		})
 	}
 
@@ -263,6 +362,31 @@ func (s *bscServiceServer) SearchToken(ctx context.Context, req *pb.SearchTokenR
 	}, nil
 }
 
+// ImportTokenList 导入代币列表，需要在请求元数据 authorization: Bearer <token> 中携带代币列表导入令牌
+func (s *bscServiceServer) ImportTokenList(ctx context.Context, req *pb.ImportTokenListRequest) (*pb.ImportTokenListResponse, error) {
+	if err := s.bscService.AuthorizeTokenListImport(bearerToken(ctx)); err != nil {
+		return &pb.ImportTokenListResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	result, err := s.bscService.ImportTokenList(ctx, req.Url)
+	if err != nil {
+		return &pb.ImportTokenListResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &pb.ImportTokenListResponse{
+		Name:     result.Name,
+		Imported: uint32(result.Imported),
+		Skipped:  uint32(result.Skipped),
+		Success:  true,
+	}, nil
+}
+
 func (s *bscServiceServer) GetTokenPrice(ctx context.Context, req *pb.GetTokenPriceRequest) (*pb.GetTokenPriceResponse, error) {
 	price, err := s.bscService.GetTokenPrice(req.Address, req.TokenName)
 	if err != nil {
@@ -273,32 +397,61 @@ func (s *bscServiceServer) GetTokenPrice(ctx context.Context, req *pb.GetTokenPr
 	}
 
 	return &pb.GetTokenPriceResponse{
-		Price: &pb.TokenPrice{
-			Address:  price.TokenAddress,
-			Name:     price.TokenName,
-			Symbol:   price.TokenSymbol,
-			PriceUsd: price.PriceInUSD,
-			PriceBnb: price.PriceInBNB,
-		},
+		Price:   toPBTokenPrice(price),
 		Success: true,
 	}, nil
 }
 
+// toPBTokenPrice 将价格信息转换为gRPC消息
+func toPBTokenPrice(price *services.PriceInfo) *pb.TokenPrice {
+	return &pb.TokenPrice{
+		Address:         price.TokenAddress,
+		Name:            price.TokenName,
+		Symbol:          price.TokenSymbol,
+		PriceUsd:        price.PriceInUSD,
+		PriceBnb:        price.PriceInBNB,
+		Decimals:        uint32(price.TokenDecimals),
+		PriceUsdRaw:     price.PriceInUSDRaw,
+		PriceBnbRaw:     price.PriceInBNBRaw,
+		Route:           price.Route,
+		RoutePools:      price.RoutePools,
+		Volume_24H:      price.Volume24h,
+		PriceChange_24H: price.PriceChange24h,
+		OraclePriceUsd:  price.OraclePriceUSD,
+		PriceDeviation:  price.PriceDeviation,
+		PriceDeviated:   price.PriceDeviated,
+	}
+}
+
+// toPBPoolReserve 将池子储备量转换为gRPC消息
+func toPBPoolReserve(reserve *services.PoolReserve) *pb.PoolReserve {
+	if reserve == nil {
+		return nil
+	}
+	return &pb.PoolReserve{
+		Token:     reserve.Token,
+		Symbol:    reserve.Symbol,
+		Decimals:  uint32(reserve.Decimals),
+		Amount:    reserve.Amount,
+		AmountRaw: reserve.AmountRaw,
+		PriceUsd:  reserve.PriceUSD,
+		ValueUsd:  reserve.ValueUSD,
+	}
+}
+
 func (s *bscServiceServer) GetMultipleTokenPrices(ctx context.Context, req *pb.GetMultipleTokenPricesRequest) (*pb.GetMultipleTokenPricesResponse, error) {
-	// 简化实现，逐个获取价格
+	queries := make([]services.TokenPriceQuery, len(req.Tokens))
+	for i, token := range req.Tokens {
+		queries[i] = services.TokenPriceQuery{Address: token.Address, Name: token.Name}
+	}
+
 	var pbPrices []*pb.TokenPrice
-	for _, token := range req.Tokens {
-		price, err := s.bscService.GetTokenPrice(token.Address, token.Name)
-		if err != nil {
+	prices, errs := s.bscService.GetTokenPrices(queries)
+	for i, price := range prices {
+		if errs[i] != nil {
 			continue // 跳过错误的代币
 		}
-		pbPrices = append(pbPrices, &pb.TokenPrice{
-			Address:  price.TokenAddress,
-			Name:     price.TokenName,
-			Symbol:   price.TokenSymbol,
-			PriceUsd: price.PriceInUSD,
-			PriceBnb: price.PriceInBNB,
-		})
+		pbPrices = append(pbPrices, toPBTokenPrice(price))
 	}
 
 	return &pb.GetMultipleTokenPricesResponse{
@@ -316,22 +469,390 @@ func (s *bscServiceServer) GetLiquidityPool(ctx context.Context, req *pb.GetLiqu
 		}, nil
 	}
 
-	totalLiquidity, err := s.bscService.GetTotalLiquidity(req.Token0, req.Token1)
+	liquidityInfo, err := s.bscService.GetLiquidityInfo(req.Token0, req.Token1)
+	if err != nil {
+		// 如果获取失败，流动性设为0
+		liquidityInfo = &services.LiquidityInfo{TVLUSD: "0", LPTotalSupply: "0", LPTotalSupplyRaw: "0", LPTokenPriceUSD: "0"}
+	}
+
+	// 部分V3池查询失败时仍返回读取成功的池子
+	v3Pools, err := s.bscService.GetV3Pools(req.Token0, req.Token1)
 	if err != nil {
-		totalLiquidity = "0" // 如果获取失败，设为0
+		log.Printf("Failed to get V3 pools: %v", err)
 	}
 
+	var pbV3Pools []*pb.V3Pool
+	for _, pool := range v3Pools {
+		pbV3Pools = append(pbV3Pools, &pb.V3Pool{
+			Dex:          pool.Dex,
+			PoolAddress:  pool.PoolAddress,
+			Token0:       pool.Token0,
+			Token1:       pool.Token1,
+			Fee:          pool.Fee,
+			SqrtPriceX96: pool.SqrtPriceX96,
+			Tick:         pool.Tick,
+			Liquidity:    pool.Liquidity,
+			Price:        pool.Price,
+			Reserve0:     pool.Reserve0,
+			Reserve1:     pool.Reserve1,
+			TvlUsd:       pool.TVLUSD,
+		})
+	}
+
+	// 与储备量和V3池一致，按交易对的token0和token1顺序返回
+	token0, token1 := services.SortTokenAddresses(req.Token0, req.Token1)
 	return &pb.GetLiquidityPoolResponse{
 		Pool: &pb.LiquidityPool{
-			PairAddress:    pairAddress,
-			Token0:         req.Token0,
-			Token1:         req.Token1,
-			TotalLiquidity: totalLiquidity,
+			PairAddress:      pairAddress,
+			Token0:           token0,
+			Token1:           token1,
+			TotalLiquidity:   services.CombinedTVLUSD(liquidityInfo, v3Pools),
+			V3Pools:          pbV3Pools,
+			Reserve0:         toPBPoolReserve(liquidityInfo.Reserve0),
+			Reserve1:         toPBPoolReserve(liquidityInfo.Reserve1),
+			TvlUsd:           liquidityInfo.TVLUSD,
+			LpTotalSupply:    liquidityInfo.LPTotalSupply,
+			LpTotalSupplyRaw: liquidityInfo.LPTotalSupplyRaw,
+			LpTokenPriceUsd:  liquidityInfo.LPTokenPriceUSD,
 		},
 		Success: true,
 	}, nil
 }
 
+func (s *bscServiceServer) QuoteTrade(ctx context.Context, req *pb.QuoteTradeRequest) (*pb.QuoteTradeResponse, error) {
+	quote, err := s.bscService.QuoteTrade(req.TokenIn, req.TokenOut, req.Amount, req.SlippageBps)
+	if err != nil {
+		return &pb.QuoteTradeResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &pb.QuoteTradeResponse{
+		Quote: &pb.TradeQuote{
+			TokenIn:            quote.TokenIn,
+			TokenOut:           quote.TokenOut,
+			AmountIn:           quote.AmountIn,
+			AmountInRaw:        quote.AmountInRaw,
+			AmountOut:          quote.AmountOut,
+			AmountOutRaw:       quote.AmountOutRaw,
+			ExecutionPrice:     quote.ExecutionPrice,
+			SpotPrice:          quote.SpotPrice,
+			PriceImpact:        quote.PriceImpact,
+			SlippageBps:        quote.SlippageBps,
+			MinimumReceived:    quote.MinimumReceived,
+			MinimumReceivedRaw: quote.MinimumReceivedRaw,
+			Route:              quote.Route,
+			RoutePools:         quote.RoutePools,
+		},
+		Success: true,
+	}, nil
+}
+
+// Swap 执行兑换，需要在请求元数据 authorization: Bearer <token> 中携带兑换令牌
+func (s *bscServiceServer) Swap(ctx context.Context, req *pb.SwapRequest) (*pb.SwapResponse, error) {
+	if err := s.bscService.AuthorizeSwap(bearerToken(ctx)); err != nil {
+		return &pb.SwapResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	result, err := s.bscService.Swap(ctx, services.SwapRequest{
+		TokenIn:       req.TokenIn,
+		TokenOut:      req.TokenOut,
+		Amount:        req.Amount,
+		ExactOut:      req.ExactOut,
+		SlippageBps:   req.SlippageBps,
+		Deadline:      time.Duration(req.DeadlineSeconds) * time.Second,
+		Recipient:     req.Recipient,
+		FeeOnTransfer: req.FeeOnTransfer,
+	})
+	if err != nil {
+		return &pb.SwapResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &pb.SwapResponse{
+		Result: &pb.SwapResult{
+			TxHash:          result.TxHash,
+			ApproveTxHash:   result.ApproveTxHash,
+			Method:          result.Method,
+			BlockNumber:     result.BlockNumber,
+			GasUsed:         result.GasUsed,
+			TokenIn:         result.TokenIn,
+			TokenOut:        result.TokenOut,
+			Recipient:       result.Recipient,
+			Route:           result.Route,
+			AmountIn:        result.AmountIn,
+			AmountInRaw:     result.AmountInRaw,
+			AmountOut:       result.AmountOut,
+			AmountOutRaw:    result.AmountOutRaw,
+			AmountOutMinRaw: result.AmountOutMin,
+			AmountInMaxRaw:  result.AmountInMax,
+			SlippageBps:     result.SlippageBps,
+			Deadline:        result.Deadline,
+		},
+		Success: true,
+	}, nil
+}
+
+// toPBDexPairs 将交易对列表转换为gRPC消息
+func toPBDexPairs(pairs []*models.DexPair) []*pb.DexPair {
+	pbPairs := make([]*pb.DexPair, 0, len(pairs))
+	for _, pair := range pairs {
+		pbPairs = append(pbPairs, toPBDexPair(pair))
+	}
+	return pbPairs
+}
+
+// toPBDexPair 将交易对转换为gRPC消息
+func toPBDexPair(pair *models.DexPair) *pb.DexPair {
+	return &pb.DexPair{
+		Dex:         pair.Dex,
+		Factory:     pair.Factory,
+		PairAddress: pair.PairAddress,
+		Token0:      pair.Token0,
+		Token1:      pair.Token1,
+		PairIndex:   pair.PairIndex,
+		BlockNumber: pair.BlockNumber,
+		BlockTime:   pair.BlockTime.Unix(),
+		TxHash:      pair.TxHash,
+	}
+}
+
+func (s *bscServiceServer) GetTokenPairs(ctx context.Context, req *pb.GetTokenPairsRequest) (*pb.GetTokenPairsResponse, error) {
+	if !common.IsHexAddress(req.Token) {
+		return &pb.GetTokenPairsResponse{
+			Success: false,
+			Error:   "invalid token address format",
+		}, nil
+	}
+
+	pairs, err := s.bscService.GetTokenPairs(req.Token)
+	if err != nil {
+		return &pb.GetTokenPairsResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &pb.GetTokenPairsResponse{
+		Pairs:   toPBDexPairs(pairs),
+		Success: true,
+	}, nil
+}
+
+func (s *bscServiceServer) GetRecentPairs(ctx context.Context, req *pb.GetRecentPairsRequest) (*pb.GetRecentPairsResponse, error) {
+	minutes := req.Minutes
+	if minutes == 0 {
+		minutes = 60
+	}
+	if minutes > 24*60 {
+		return &pb.GetRecentPairsResponse{
+			Success: false,
+			Error:   "minutes must not exceed 1440",
+		}, nil
+	}
+
+	pairs, err := s.bscService.GetRecentPairs(time.Duration(minutes) * time.Minute)
+	if err != nil {
+		return &pb.GetRecentPairsResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &pb.GetRecentPairsResponse{
+		Pairs:   toPBDexPairs(pairs),
+		Success: true,
+	}, nil
+}
+
+func (s *bscServiceServer) StreamNewPairs(req *pb.StreamNewPairsRequest, stream pb.BSCService_StreamNewPairsServer) error {
+	var token common.Address
+	if req.Token != "" {
+		if !common.IsHexAddress(req.Token) {
+			return status.Error(codes.InvalidArgument, "invalid token address format")
+		}
+		token = common.HexToAddress(req.Token)
+	}
+
+	pairs, cancel := s.bscService.SubscribeNewPairs()
+	defer cancel()
+
+	for {
+		select {
+		case <-stream.Context().Done():
+			return nil
+		case pair, ok := <-pairs:
+			if !ok {
+				return nil
+			}
+			if req.Dex != "" && pair.Dex != req.Dex {
+				continue
+			}
+			if req.Token != "" && common.HexToAddress(pair.Token0) != token && common.HexToAddress(pair.Token1) != token {
+				continue
+			}
+			if err := stream.Send(toPBDexPair(pair)); err != nil {
+				return err
+			}
+		}
+	}
+}
+
+func (s *bscServiceServer) AnalyzeTokenRisk(ctx context.Context, req *pb.AnalyzeTokenRiskRequest) (*pb.AnalyzeTokenRiskResponse, error) {
+	if !common.IsHexAddress(req.Token) {
+		return &pb.AnalyzeTokenRiskResponse{
+			Success: false,
+			Error:   "invalid token address format",
+		}, nil
+	}
+
+	report, err := s.bscService.AnalyzeTokenRisk(services.TokenRiskRequest{
+		Token:  req.Token,
+		Amount: req.Amount,
+		ABI:    req.Abi,
+	})
+	if err != nil {
+		return &pb.AnalyzeTokenRiskResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &pb.AnalyzeTokenRiskResponse{
+		Report: &pb.TokenRiskReport{
+			Token:              report.Token,
+			Name:               report.Name,
+			Symbol:             report.Symbol,
+			Route:              report.Route,
+			BuyAmount:          report.BuyAmount,
+			IsHoneypot:         report.IsHoneypot,
+			CanBuy:             report.CanBuy,
+			CanSell:            report.CanSell,
+			BuyError:           report.BuyError,
+			SellError:          report.SellError,
+			BuyTax:             report.BuyTax,
+			SellTax:            report.SellTax,
+			TransferTax:        report.TransferTax,
+			BuyGas:             report.BuyGas,
+			SellGas:            report.SellGas,
+			MaxTxAmount:        report.MaxTxAmount,
+			MaxWalletAmount:    report.MaxWalletAmount,
+			Owner:              report.Owner,
+			OwnershipRenounced: report.OwnershipRenounced,
+			Privileges:         report.Privileges,
+			PrivilegeSource:    report.PrivilegeSource,
+			RiskLevel:          report.RiskLevel,
+			Risks:              report.Risks,
+		},
+		Success: true,
+	}, nil
+}
+
+// GetTWAP 获取交易对/V3池的时间加权平均价格
+func (s *bscServiceServer) GetTWAP(ctx context.Context, req *pb.GetTWAPRequest) (*pb.GetTWAPResponse, error) {
+	if !common.IsHexAddress(req.Pool) {
+		return &pb.GetTWAPResponse{
+			Success: false,
+			Error:   "invalid pool address format",
+		}, nil
+	}
+
+	window := req.WindowSeconds
+	if window == 0 {
+		window = 1800
+	}
+
+	twap, err := s.bscService.GetTWAP(req.Pool, time.Duration(window)*time.Second)
+	if err != nil {
+		return &pb.GetTWAPResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	return &pb.GetTWAPResponse{
+		Price: &pb.TWAPPrice{
+			Pool:        twap.Pool,
+			Token0:      twap.Token0,
+			Token1:      twap.Token1,
+			Price0:      twap.Price0,
+			Price1:      twap.Price1,
+			WindowStart: twap.WindowStart,
+			WindowEnd:   twap.WindowEnd,
+			Source:      twap.Source,
+		},
+		Success: true,
+	}, nil
+}
+
+// GetTokenCandles 获取代币的OHLCV K线，未指定时间范围时返回截至当前的100根K线
+func (s *bscServiceServer) GetTokenCandles(ctx context.Context, req *pb.GetTokenCandlesRequest) (*pb.GetTokenCandlesResponse, error) {
+	if !common.IsHexAddress(req.Token) {
+		return &pb.GetTokenCandlesResponse{
+			Success: false,
+			Error:   "invalid token address format",
+		}, nil
+	}
+
+	interval := req.Interval
+	if interval == "" {
+		interval = "1h"
+	}
+	duration, ok := services.CandleInterval(interval)
+	if !ok {
+		return &pb.GetTokenCandlesResponse{
+			Success: false,
+			Error:   "interval must be one of 1m, 5m, 1h, 1d",
+		}, nil
+	}
+
+	to := time.Now()
+	if req.To > 0 {
+		to = time.Unix(req.To, 0)
+	}
+	from := to.Add(-(services.DefaultCandleCount - 1) * duration)
+	if req.From > 0 {
+		from = time.Unix(req.From, 0)
+	}
+
+	candles, err := s.bscService.GetTokenCandles(req.Token, interval, from, to)
+	if err != nil {
+		return &pb.GetTokenCandlesResponse{
+			Success: false,
+			Error:   err.Error(),
+		}, nil
+	}
+
+	pbCandles := make([]*pb.Candle, 0, len(candles))
+	for _, candle := range candles {
+		pbCandles = append(pbCandles, &pb.Candle{
+			OpenTime:  candle.OpenTime.Unix(),
+			OpenUsd:   candle.OpenUSD,
+			HighUsd:   candle.HighUSD,
+			LowUsd:    candle.LowUSD,
+			CloseUsd:  candle.CloseUSD,
+			OpenBnb:   candle.OpenBNB,
+			HighBnb:   candle.HighBNB,
+			LowBnb:    candle.LowBNB,
+			CloseBnb:  candle.CloseBNB,
+			Volume:    candle.Volume,
+			VolumeUsd: candle.VolumeUSD,
+			Trades:    int32(candle.Trades),
+		})
+	}
+
+	return &pb.GetTokenCandlesResponse{
+		Candles: pbCandles,
+		Success: true,
+	}, nil
+}
+
 // healthServiceServer 健康检查服务实现
 type healthServiceServer struct {
 	pb.UnimplementedHealthServiceServer
@@ -0,0 +1,88 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	pb "chain/chain/proto"
+	"chain/internal/config"
+	"chain/internal/services"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/metadata"
+)
+
+func TestBSCSwapRequiresAdminToken(t *testing.T) {
+	newServer := func(adminToken string) *bscServiceServer {
+		cfg := &config.Config{
+			Chain: config.ChainConfig{RPCURL: "https://bsc-dataseed1.binance.org/", ChainID: 56},
+			BSC:   config.BSCConfig{SwapAdminToken: adminToken},
+		}
+		return &bscServiceServer{bscService: services.NewBSCService(cfg)}
+	}
+	req := &pb.SwapRequest{TokenIn: "BNB", TokenOut: "0x55d398326f99059fF775485246999027B3197955", Amount: "1"}
+
+	// 未配置令牌时兑换不可用
+	resp, err := newServer("").Swap(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Equal(t, services.ErrSwapDisabled.Error(), resp.Error)
+
+	// 缺少或错误的令牌
+	server := newServer("secret")
+	for _, ctx := range []context.Context{
+		context.Background(),
+		metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer wrong")),
+	} {
+		resp, err := server.Swap(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, resp.Success)
+		assert.Equal(t, services.ErrSwapUnauthorized.Error(), resp.Error)
+	}
+}
+
+func TestBSCImportTokenListRequiresAdminToken(t *testing.T) {
+	newServer := func(adminToken string) *bscServiceServer {
+		cfg := &config.Config{
+			Chain: config.ChainConfig{RPCURL: "https://bsc-dataseed1.binance.org/", ChainID: 56},
+			BSC:   config.BSCConfig{TokenListToken: adminToken},
+		}
+		return &bscServiceServer{bscService: services.NewBSCService(cfg)}
+	}
+	req := &pb.ImportTokenListRequest{Url: "https://tokens.pancakeswap.finance/pancakeswap-extended.json"}
+
+	// 未配置令牌时不能通过接口导入
+	resp, err := newServer("").ImportTokenList(context.Background(), req)
+	require.NoError(t, err)
+	assert.False(t, resp.Success)
+	assert.Equal(t, services.ErrTokenListDisabled.Error(), resp.Error)
+
+	// 缺少或错误的令牌
+	server := newServer("secret")
+	for _, ctx := range []context.Context{
+		context.Background(),
+		metadata.NewIncomingContext(context.Background(), metadata.Pairs("authorization", "Bearer wrong")),
+	} {
+		resp, err := server.ImportTokenList(ctx, req)
+		require.NoError(t, err)
+		assert.False(t, resp.Success)
+		assert.Equal(t, services.ErrTokenListUnauthorized.Error(), resp.Error)
+	}
+}
+
+func TestGetBalancesValidation(t *testing.T) {
+	server := &chainServiceServer{chainService: &services.ChainService{}}
+
+	// 地址为空、超过上限或格式无效时不访问节点
+	tooMany := make([]string, services.MaxBalanceAddresses+1)
+	for i := range tooMany {
+		tooMany[i] = "0x55d398326f99059fF775485246999027B3197955"
+	}
+	for _, addresses := range [][]string{nil, tooMany, {"0x55d398326f99059fF775485246999027B3197955", "not-an-address"}} {
+		resp, err := server.GetBalances(context.Background(), &pb.GetBalancesRequest{Addresses: addresses})
+		require.NoError(t, err)
+		assert.False(t, resp.Success)
+		assert.Contains(t, resp.Error, services.ErrInvalidBalanceQuery.Error())
+	}
+}
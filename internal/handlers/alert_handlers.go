@@ -0,0 +1,202 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strconv"
+	"strings"
+
+	"chain/internal/models"
+	"chain/internal/services"
+	"chain/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// AlertHandler 价格告警规则处理器
+type AlertHandler struct {
+	alertService *services.AlertService
+}
+
+// NewAlertHandler 创建告警规则处理器
+func NewAlertHandler(alertService *services.AlertService) *AlertHandler {
+	return &AlertHandler{
+		alertService: alertService,
+	}
+}
+
+// registerAlertRoutes 注册告警规则的路由，所有请求需要携带管理令牌
+func registerAlertRoutes(router *gin.Engine, alertHandler *AlertHandler) {
+	alerts := router.Group("/api/v1/alerts", alertHandler.RequireAdmin)
+	{
+		alerts.GET("", alertHandler.ListAlertRules)
+		alerts.POST("", alertHandler.CreateAlertRule)
+		alerts.GET("/:id", alertHandler.GetAlertRule)
+		alerts.PUT("/:id", alertHandler.UpdateAlertRule)
+		alerts.DELETE("/:id", alertHandler.DeleteAlertRule)
+	}
+}
+
+// alertRuleRequest 创建或更新告警规则的请求体
+type alertRuleRequest struct {
+	Name      string  `json:"name"`
+	Asset     string  `json:"asset" binding:"required"`
+	Source    string  `json:"source"` // crypto（默认）或 token
+	Condition string  `json:"condition" binding:"required"`
+	Threshold float64 `json:"threshold" binding:"required"`
+	Window    int     `json:"window"`   // change类条件的时间窗口（秒）
+	Cooldown  int     `json:"cooldown"` // 触发后的冷却时间（秒）
+	Channel   string  `json:"channel" binding:"required"`
+	Target    string  `json:"target" binding:"required"`
+	Enabled   *bool   `json:"enabled"` // 默认启用
+}
+
+// rule 转换为告警规则
+func (r *alertRuleRequest) rule() *models.AlertRule {
+	enabled := true
+	if r.Enabled != nil {
+		enabled = *r.Enabled
+	}
+	return &models.AlertRule{
+		Name:      r.Name,
+		Asset:     r.Asset,
+		Source:    r.Source,
+		Condition: r.Condition,
+		Threshold: r.Threshold,
+		Window:    r.Window,
+		Cooldown:  r.Cooldown,
+		Channel:   r.Channel,
+		Target:    r.Target,
+		Enabled:   enabled,
+	}
+}
+
+// RequireAdmin 校验 Authorization: Bearer <token> 中的管理令牌，令牌错误返回401，未配置令牌返回503
+func (h *AlertHandler) RequireAdmin(c *gin.Context) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		token = ""
+	}
+	err := h.alertService.Authorize(token)
+	switch {
+	case errors.Is(err, services.ErrAlertAdminDisabled):
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		c.Next()
+	}
+}
+
+// CreateAlertRule 创建告警规则
+func (h *AlertHandler) CreateAlertRule(c *gin.Context) {
+	var req alertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule, err := h.alertService.CreateRule(req.rule())
+	if err != nil {
+		h.respondError(c, "create", err)
+		return
+	}
+	c.JSON(http.StatusCreated, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// ListAlertRules 列出所有告警规则
+func (h *AlertHandler) ListAlertRules(c *gin.Context) {
+	rules, err := h.alertService.ListRules()
+	if err != nil {
+		h.respondError(c, "list", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rules,
+		"count":   len(rules),
+	})
+}
+
+// GetAlertRule 获取告警规则
+func (h *AlertHandler) GetAlertRule(c *gin.Context) {
+	id, ok := alertRuleID(c)
+	if !ok {
+		return
+	}
+
+	rule, err := h.alertService.GetRule(id)
+	if err != nil {
+		h.respondError(c, "get", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// UpdateAlertRule 覆盖告警规则
+func (h *AlertHandler) UpdateAlertRule(c *gin.Context) {
+	id, ok := alertRuleID(c)
+	if !ok {
+		return
+	}
+	var req alertRuleRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	rule := req.rule()
+	rule.ID = id
+	rule, err := h.alertService.UpdateRule(rule)
+	if err != nil {
+		h.respondError(c, "update", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    rule,
+	})
+}
+
+// DeleteAlertRule 删除告警规则
+func (h *AlertHandler) DeleteAlertRule(c *gin.Context) {
+	id, ok := alertRuleID(c)
+	if !ok {
+		return
+	}
+
+	if err := h.alertService.DeleteRule(id); err != nil {
+		h.respondError(c, "delete", err)
+		return
+	}
+	c.JSON(http.StatusOK, gin.H{"success": true})
+}
+
+// alertRuleID 解析路径中的规则ID，无效时返回400
+func alertRuleID(c *gin.Context) (uint, bool) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 32)
+	if err != nil || id == 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "id must be a positive integer"})
+		return 0, false
+	}
+	return uint(id), true
+}
+
+// respondError 无效规则返回400，规则不存在返回404，其他错误返回500
+func (h *AlertHandler) respondError(c *gin.Context, action string, err error) {
+	switch {
+	case errors.Is(err, services.ErrInvalidAlertRule):
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+	case errors.Is(err, services.ErrAlertRuleNotFound):
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+	default:
+		logger.Errorf("Failed to %s alert rule: %v", action, err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+	}
+}
@@ -0,0 +1,115 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"chain/internal/config"
+	"chain/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newAlertTestRouter 创建使用内存规则存储的告警路由，管理令牌为adminToken
+func newAlertTestRouter(adminToken string) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{Alert: config.AlertConfig{AdminToken: adminToken}}
+	router := gin.New()
+	registerAlertRoutes(router, NewAlertHandler(services.NewAlertService(cfg, services.NewPriceService(cfg))))
+	return router
+}
+
+// serveAlertRequest 携带管理令牌发送请求并解析JSON响应
+func serveAlertRequest(t *testing.T, router *gin.Engine, method, path string, body []byte) (int, map[string]interface{}) {
+	return serveAuthorizedRequest(t, router, method, path, body, "Bearer secret")
+}
+
+// serveAuthorizedRequest 使用指定的Authorization头发送请求并解析JSON响应
+func serveAuthorizedRequest(t *testing.T, router *gin.Engine, method, path string, body []byte, authorization string) (int, map[string]interface{}) {
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("Authorization", authorization)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w.Code, resp
+}
+
+func TestAlertRuleRoutes(t *testing.T) {
+	router := newAlertTestRouter("secret")
+
+	body, _ := json.Marshal(gin.H{"name": "btc breakout", "asset": "BTC", "condition": "above", "threshold": 65000, "cooldown": 3600, "channel": "webhook", "target": "https://example.com/hook"})
+	code, resp := serveAlertRequest(t, router, "POST", "/api/v1/alerts", body)
+	require.Equal(t, http.StatusCreated, code)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, 1.0, data["id"])
+	assert.Equal(t, "BTC", data["asset"])
+	assert.Equal(t, "crypto", data["source"])
+	assert.Equal(t, true, data["enabled"])
+
+	// 覆盖规则并停用
+	body, _ = json.Marshal(gin.H{"asset": "eth", "condition": "change", "threshold": 5, "window": 3600, "channel": "telegram", "target": "12345", "enabled": false})
+	code, resp = serveAlertRequest(t, router, "PUT", "/api/v1/alerts/1", body)
+	require.Equal(t, http.StatusOK, code)
+	data = resp["data"].(map[string]interface{})
+	assert.Equal(t, "eth", data["asset"])
+	assert.Equal(t, false, data["enabled"])
+
+	code, resp = serveAlertRequest(t, router, "GET", "/api/v1/alerts", nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, 1.0, resp["count"])
+
+	code, resp = serveAlertRequest(t, router, "GET", "/api/v1/alerts/1", nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "change", resp["data"].(map[string]interface{})["condition"])
+
+	code, _ = serveAlertRequest(t, router, "DELETE", "/api/v1/alerts/1", nil)
+	assert.Equal(t, http.StatusOK, code)
+	code, _ = serveAlertRequest(t, router, "GET", "/api/v1/alerts/1", nil)
+	assert.Equal(t, http.StatusNotFound, code)
+}
+
+func TestAlertRuleRoutesErrors(t *testing.T) {
+	router := newAlertTestRouter("secret")
+
+	tests := []struct {
+		method string
+		path   string
+		body   string
+		code   int
+	}{
+		{"POST", "/api/v1/alerts", `{"asset":"btc"}`, http.StatusBadRequest},
+		{"POST", "/api/v1/alerts", `{"asset":"btc","condition":"equals","threshold":1,"channel":"telegram","target":"1"}`, http.StatusBadRequest},
+		{"POST", "/api/v1/alerts", `{"asset":"btc","condition":"above","threshold":1,"channel":"email","target":"trader"}`, http.StatusBadRequest},
+		{"PUT", "/api/v1/alerts/9", `{"asset":"btc","condition":"above","threshold":1,"channel":"telegram","target":"1"}`, http.StatusNotFound},
+		{"GET", "/api/v1/alerts/abc", "", http.StatusBadRequest},
+		{"DELETE", "/api/v1/alerts/0", "", http.StatusBadRequest},
+		{"DELETE", "/api/v1/alerts/9", "", http.StatusNotFound},
+	}
+	for _, tt := range tests {
+		code, resp := serveAlertRequest(t, router, tt.method, tt.path, []byte(tt.body))
+		assert.Equal(t, tt.code, code, tt.method+" "+tt.path)
+		assert.NotEmpty(t, resp["error"])
+	}
+}
+
+func TestAlertRuleRoutesRequireAdminToken(t *testing.T) {
+	router := newAlertTestRouter("secret")
+	for _, authorization := range []string{"", "Bearer wrong", "secret"} {
+		code, resp := serveAuthorizedRequest(t, router, "GET", "/api/v1/alerts", nil, authorization)
+		assert.Equal(t, http.StatusUnauthorized, code, authorization)
+		assert.Equal(t, services.ErrAlertUnauthorized.Error(), resp["error"])
+	}
+
+	// 未配置令牌时不能管理规则
+	code, _ := serveAuthorizedRequest(t, newAlertTestRouter(""), "GET", "/api/v1/alerts", nil, "Bearer ")
+	assert.Equal(t, http.StatusServiceUnavailable, code)
+}
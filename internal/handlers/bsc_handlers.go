@@ -1,9 +1,12 @@
 package handlers
 
 import (
+	"errors"
 	"fmt"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
 	"chain/internal/config"
 	"chain/internal/services"
@@ -25,10 +28,13 @@ func NewBSCHandler(cfg *config.Config) *BSCHandler {
 	}
 }
 
-// RegisterBSCRoutes 注册BSC相关路由
+// RegisterBSCRoutes 注册BSC相关路由，价格快照保存在内存中
 func RegisterBSCRoutes(router *gin.Engine, cfg *config.Config) {
-	bscHandler := NewBSCHandler(cfg)
+	registerBSCRoutes(router, NewBSCHandler(cfg))
+}
 
+// registerBSCRoutes 注册BSC处理器的路由
+func registerBSCRoutes(router *gin.Engine, bscHandler *BSCHandler) {
 	// BSC API路由组
 	bsc := router.Group("/api/v1/bsc")
 	{
@@ -44,11 +50,35 @@ func RegisterBSCRoutes(router *gin.Engine, cfg *config.Config) {
 		// 通过名称查找代币
 		bsc.GET("/token/search/:name", bscHandler.FindTokenByName)
 		
+		// 导入Uniswap格式的代币列表
+		bsc.POST("/tokens/import", bscHandler.RequireTokenListAdmin, bscHandler.ImportTokenList)
+		
 		// 批量查询代币价格
 		bsc.POST("/tokens/prices", bscHandler.GetMultipleTokenPrices)
 		
 		// 获取流动性池信息
 		bsc.GET("/liquidity/:tokenA/:tokenB", bscHandler.GetLiquidityInfo)
+		
+		// 查询兑换报价（价格影响和滑点）
+		bsc.POST("/quote", bscHandler.QuoteTrade)
+		
+		// 执行兑换（精确输入 / 精确输出）
+		bsc.POST("/swap/exact-in", bscHandler.RequireSwapAdmin, bscHandler.SwapExactIn)
+		bsc.POST("/swap/exact-out", bscHandler.RequireSwapAdmin, bscHandler.SwapExactOut)
+
+		// 交易对索引：代币的所有交易对、最近创建的交易对
+		bsc.GET("/pairs/token/:address", bscHandler.GetTokenPairs)
+		bsc.GET("/pairs/recent", bscHandler.GetRecentPairs)
+
+		// 代币风险分析（貔貅、买卖税、交易限额和所有者特权）
+		bsc.GET("/token/risk/:address", bscHandler.GetTokenRisk)
+		bsc.POST("/token/risk", bscHandler.AnalyzeTokenRisk)
+
+		// 交易对/V3池的时间加权平均价格
+		bsc.GET("/twap/:pool", bscHandler.GetTWAP)
+
+		// 由Swap事件聚合的代币价格K线
+		bsc.GET("/candles/:address", bscHandler.GetTokenCandles)
 	}
 }
 
@@ -137,7 +167,8 @@ func (h *BSCHandler) GetTokenPriceByAddressAndName(c *gin.Context) {
 	})
 }
 
-// FindTokenByName 通过名称查找代币
+// FindTokenByName 通过名称或符号搜索代币
+// 查询参数：prefix=true 只匹配前缀（否则包含匹配并模糊匹配），verified=true 只返回已验证代币，limit 返回数量（默认20，最大100）
 func (h *BSCHandler) FindTokenByName(c *gin.Context) {
 	name := c.Param("name")
 	if name == "" {
@@ -145,7 +176,18 @@ func (h *BSCHandler) FindTokenByName(c *gin.Context) {
 		return
 	}
 
-	tokens, err := h.bscService.FindTokenByName(name)
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "20"))
+	if err != nil || limit <= 0 || limit > 100 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "limit must be between 1 and 100"})
+		return
+	}
+
+	tokens, err := h.bscService.SearchTokens(services.TokenQuery{
+		Text:         name,
+		Prefix:       c.Query("prefix") == "true",
+		VerifiedOnly: c.Query("verified") == "true",
+		Limit:        limit,
+	})
 	if err != nil {
 		logger.Errorf("Failed to find tokens: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -159,6 +201,35 @@ func (h *BSCHandler) FindTokenByName(c *gin.Context) {
 	})
 }
 
+// ImportTokenList 从配置的代币列表或允许域名下的HTTP(S)地址导入Uniswap格式的代币列表，需要代币列表导入令牌
+func (h *BSCHandler) ImportTokenList(c *gin.Context) {
+	var req struct {
+		URL string `json:"url" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 接口只允许导入配置的列表或允许域名下的远程列表，本地文件通过配置导入
+	result, err := h.bscService.ImportTokenList(c.Request.Context(), req.URL)
+	if errors.Is(err, services.ErrTokenListNotAllowed) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to import token list: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
 // GetMultipleTokenPrices 批量获取代币价格
 func (h *BSCHandler) GetMultipleTokenPrices(c *gin.Context) {
 	var req struct {
@@ -186,17 +257,22 @@ func (h *BSCHandler) GetMultipleTokenPrices(c *gin.Context) {
 	var results []interface{}
 	var errors []string
 
+	var queries []services.TokenPriceQuery
 	for _, token := range req.Tokens {
 		// 验证地址格式
 		if !strings.HasPrefix(token.Address, "0x") || len(token.Address) != 42 {
 			errors = append(errors, fmt.Sprintf("invalid address format: %s", token.Address))
 			continue
 		}
+		queries = append(queries, services.TokenPriceQuery{Address: token.Address, Name: token.TokenName})
+	}
 
-		priceInfo, err := h.bscService.GetTokenPrice(token.Address, token.TokenName)
-		if err != nil {
-			logger.Warnf("Failed to get price for token %s: %v", token.Address, err)
-			errors = append(errors, fmt.Sprintf("failed to get price for %s: %v", token.Address, err))
+	// 并发查询的链上读取被合并为批量请求
+	prices, errs := h.bscService.GetTokenPrices(queries)
+	for i, priceInfo := range prices {
+		if err := errs[i]; err != nil {
+			logger.Warnf("Failed to get price for token %s: %v", queries[i].Address, err)
+			errors = append(errors, fmt.Sprintf("failed to get price for %s: %v", queries[i].Address, err))
 			continue
 		}
 
@@ -217,6 +293,161 @@ func (h *BSCHandler) GetMultipleTokenPrices(c *gin.Context) {
 	c.JSON(http.StatusOK, response)
 }
 
+// QuoteTrade 查询指定数量的兑换报价
+func (h *BSCHandler) QuoteTrade(c *gin.Context) {
+	var req struct {
+		TokenIn     string `json:"token_in" binding:"required"`
+		TokenOut    string `json:"token_out" binding:"required"`
+		Amount      string `json:"amount" binding:"required"`
+		SlippageBps uint32 `json:"slippage_bps"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 验证地址格式
+	if !strings.HasPrefix(req.TokenIn, "0x") || len(req.TokenIn) != 42 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token_in address format"})
+		return
+	}
+
+	if !strings.HasPrefix(req.TokenOut, "0x") || len(req.TokenOut) != 42 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token_out address format"})
+		return
+	}
+
+	if req.SlippageBps > services.MaxSlippageBps {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("slippage_bps must not exceed %d", services.MaxSlippageBps)})
+		return
+	}
+
+	quote, err := h.bscService.QuoteTrade(req.TokenIn, req.TokenOut, req.Amount, req.SlippageBps)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidTradeQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Errorf("Failed to quote trade: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    quote,
+	})
+}
+
+// swapRequest 兑换请求参数
+type swapRequest struct {
+	TokenIn       string `json:"token_in" binding:"required"`  // 代币地址或BNB
+	TokenOut      string `json:"token_out" binding:"required"` // 代币地址或BNB
+	Amount        string `json:"amount" binding:"required"`
+	SlippageBps   uint32 `json:"slippage_bps"`
+	Deadline      int64  `json:"deadline"` // 交易有效期（秒）
+	Recipient     string `json:"recipient"`
+	FeeOnTransfer bool   `json:"fee_on_transfer"`
+}
+
+// RequireSwapAdmin 校验 Authorization: Bearer <token> 中的兑换令牌，令牌错误返回401，未配置令牌返回503
+func (h *BSCHandler) RequireSwapAdmin(c *gin.Context) {
+	requireAdmin(c, h.bscService.AuthorizeSwap, services.ErrSwapDisabled)
+}
+
+// RequireTokenListAdmin 校验 Authorization: Bearer <token> 中的代币列表导入令牌，令牌错误返回401，未配置令牌返回503
+func (h *BSCHandler) RequireTokenListAdmin(c *gin.Context) {
+	requireAdmin(c, h.bscService.AuthorizeTokenListImport, services.ErrTokenListDisabled)
+}
+
+// requireAdmin 用authorize校验 Authorization: Bearer <token> 中的令牌，返回disabled时表示未配置令牌
+func requireAdmin(c *gin.Context, authorize func(token string) error, disabled error) {
+	token, ok := strings.CutPrefix(c.GetHeader("Authorization"), "Bearer ")
+	if !ok {
+		token = ""
+	}
+	err := authorize(token)
+	switch {
+	case errors.Is(err, disabled):
+		c.AbortWithStatusJSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+	case err != nil:
+		c.AbortWithStatusJSON(http.StatusUnauthorized, gin.H{"error": err.Error()})
+	default:
+		c.Next()
+	}
+}
+
+// SwapExactIn 以精确输入数量执行兑换
+func (h *BSCHandler) SwapExactIn(c *gin.Context) {
+	h.swap(c, false)
+}
+
+// SwapExactOut 以精确输出数量执行兑换
+func (h *BSCHandler) SwapExactOut(c *gin.Context) {
+	h.swap(c, true)
+}
+
+// swap 执行兑换
+func (h *BSCHandler) swap(c *gin.Context, exactOut bool) {
+	var req swapRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	// 验证地址格式
+	for _, token := range []string{req.TokenIn, req.TokenOut} {
+		if strings.EqualFold(token, services.NativeBNB) {
+			continue
+		}
+		if !strings.HasPrefix(token, "0x") || len(token) != 42 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("invalid token address format: %s", token)})
+			return
+		}
+	}
+
+	if req.Recipient != "" && (!strings.HasPrefix(req.Recipient, "0x") || len(req.Recipient) != 42) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid recipient address format"})
+		return
+	}
+
+	if req.SlippageBps > services.MaxSlippageBps {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("slippage_bps must not exceed %d", services.MaxSlippageBps)})
+		return
+	}
+
+	if exactOut && req.FeeOnTransfer {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "fee_on_transfer is only supported for exact-in swaps"})
+		return
+	}
+
+	result, err := h.bscService.Swap(c.Request.Context(), services.SwapRequest{
+		TokenIn:       req.TokenIn,
+		TokenOut:      req.TokenOut,
+		Amount:        req.Amount,
+		ExactOut:      exactOut,
+		SlippageBps:   req.SlippageBps,
+		Deadline:      time.Duration(req.Deadline) * time.Second,
+		Recipient:     req.Recipient,
+		FeeOnTransfer: req.FeeOnTransfer,
+	})
+	if errors.Is(err, services.ErrSwapRecipientNotAllowed) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to swap: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    result,
+	})
+}
+
 // GetLiquidityInfo 获取流动性池信息
 func (h *BSCHandler) GetLiquidityInfo(c *gin.Context) {
 	tokenA := c.Param("tokenA")
@@ -246,12 +477,27 @@ func (h *BSCHandler) GetLiquidityInfo(c *gin.Context) {
 		return
 	}
 
-	// 获取总流动性
-	totalLiquidity, err := h.bscService.GetTotalLiquidity(tokenA, tokenB)
+	// 获取V3集中流动性池，部分池子查询失败时仍使用读取成功的池子
+	v3Pools, err := h.bscService.GetV3Pools(tokenA, tokenB)
 	if err != nil {
-		logger.Errorf("Failed to get total liquidity: %v", err)
-		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
-		return
+		logger.Warnf("Failed to get V3 pools: %v", err)
+	}
+
+	// 获取V2储备量和USD锁仓价值，只存在V3池时V2流动性记为0
+	liquidityInfo, err := h.bscService.GetLiquidityInfo(tokenA, tokenB)
+	if err != nil {
+		if len(v3Pools) == 0 {
+			logger.Errorf("Failed to get liquidity info: %v", err)
+			c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+			return
+		}
+		liquidityInfo = &services.LiquidityInfo{
+			PairAddress:      liquidityPool,
+			TVLUSD:           "0",
+			LPTotalSupply:    "0",
+			LPTotalSupplyRaw: "0",
+			LPTokenPriceUSD:  "0",
+		}
 	}
 
 	// 获取代币信息
@@ -270,11 +516,209 @@ func (h *BSCHandler) GetLiquidityInfo(c *gin.Context) {
 	c.JSON(http.StatusOK, gin.H{
 		"success": true,
 		"data": gin.H{
-			"liquidity_pool":  liquidityPool,
-			"total_liquidity": totalLiquidity,
-			"token_a":         tokenAInfo,
-			"token_b":         tokenBInfo,
-			"pair_name":       fmt.Sprintf("%s/%s", tokenAInfo.Symbol, tokenBInfo.Symbol),
+			"liquidity_pool":      liquidityPool,
+			"total_liquidity":     services.CombinedTVLUSD(liquidityInfo, v3Pools),
+			"tvl_usd":             liquidityInfo.TVLUSD,
+			"reserve0":            liquidityInfo.Reserve0,
+			"reserve1":            liquidityInfo.Reserve1,
+			"lp_total_supply":     liquidityInfo.LPTotalSupply,
+			"lp_total_supply_raw": liquidityInfo.LPTotalSupplyRaw,
+			"lp_token_price_usd":  liquidityInfo.LPTokenPriceUSD,
+			"v3_pools":            v3Pools,
+			"token_a":             tokenAInfo,
+			"token_b":             tokenBInfo,
+			"pair_name":           fmt.Sprintf("%s/%s", tokenAInfo.Symbol, tokenBInfo.Symbol),
 		},
 	})
-}
\ No newline at end of file
+}
+
+// maxRecentPairsMinutes 查询最近创建交易对的最大时间范围（分钟）
+const maxRecentPairsMinutes = 24 * 60
+
+// GetTokenPairs 获取已索引的包含指定代币的所有交易对
+func (h *BSCHandler) GetTokenPairs(c *gin.Context) {
+	address := c.Param("address")
+	if !strings.HasPrefix(address, "0x") || len(address) != 42 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token address format"})
+		return
+	}
+
+	pairs, err := h.bscService.GetTokenPairs(address)
+	if err != nil {
+		logger.Errorf("Failed to get token pairs: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    pairs,
+		"count":   len(pairs),
+	})
+}
+
+// GetRecentPairs 获取最近创建的交易对，minutes默认60，最大1440
+func (h *BSCHandler) GetRecentPairs(c *gin.Context) {
+	minutes, err := strconv.Atoi(c.DefaultQuery("minutes", "60"))
+	if err != nil || minutes <= 0 || minutes > maxRecentPairsMinutes {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("minutes must be between 1 and %d", maxRecentPairsMinutes)})
+		return
+	}
+
+	pairs, err := h.bscService.GetRecentPairs(time.Duration(minutes) * time.Minute)
+	if err != nil {
+		logger.Errorf("Failed to get recent pairs: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    pairs,
+		"count":   len(pairs),
+	})
+}
+
+// GetTokenRisk 分析代币风险，amount为模拟买入的BNB数量
+func (h *BSCHandler) GetTokenRisk(c *gin.Context) {
+	h.analyzeTokenRisk(c, services.TokenRiskRequest{
+		Token:  c.Param("address"),
+		Amount: c.Query("amount"),
+	})
+}
+
+// AnalyzeTokenRisk 分析代币风险，可提供合约ABI以识别所有者特权
+func (h *BSCHandler) AnalyzeTokenRisk(c *gin.Context) {
+	var req struct {
+		Address string `json:"address" binding:"required"`
+		Amount  string `json:"amount"`
+		ABI     string `json:"abi"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	h.analyzeTokenRisk(c, services.TokenRiskRequest{
+		Token:  req.Address,
+		Amount: req.Amount,
+		ABI:    req.ABI,
+	})
+}
+
+// analyzeTokenRisk 执行代币风险分析
+func (h *BSCHandler) analyzeTokenRisk(c *gin.Context, req services.TokenRiskRequest) {
+	if !strings.HasPrefix(req.Token, "0x") || len(req.Token) != 42 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token address format"})
+		return
+	}
+
+	report, err := h.bscService.AnalyzeTokenRisk(req)
+	if errors.Is(err, services.ErrInvalidRiskQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to analyze token risk: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    report,
+	})
+}
+
+// GetTWAP 获取池子的时间加权平均价格，window为窗口秒数，默认30分钟
+func (h *BSCHandler) GetTWAP(c *gin.Context) {
+	pool := c.Param("pool")
+	if !strings.HasPrefix(pool, "0x") || len(pool) != 42 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid pool address format"})
+		return
+	}
+
+	window, err := strconv.Atoi(c.DefaultQuery("window", "1800"))
+	if err != nil || window <= 0 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "window must be a positive number of seconds"})
+		return
+	}
+
+	twap, err := h.bscService.GetTWAP(pool, time.Duration(window)*time.Second)
+	if errors.Is(err, services.ErrInvalidTWAPQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrInsufficientObservations) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to get TWAP: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    twap,
+	})
+}
+
+// GetTokenCandles 获取代币的OHLCV K线，from和to为Unix秒，默认返回截至当前的100根K线
+// 未聚合过的代币加入索引队列，索引器聚合前返回空列表
+func (h *BSCHandler) GetTokenCandles(c *gin.Context) {
+	address := c.Param("address")
+	if !strings.HasPrefix(address, "0x") || len(address) != 42 {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "invalid token address format"})
+		return
+	}
+
+	interval := c.DefaultQuery("interval", "1h")
+	duration, ok := services.CandleInterval(interval)
+	if !ok {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "interval must be one of 1m, 5m, 1h, 1d"})
+		return
+	}
+
+	to := time.Now()
+	if value := c.Query("to"); value != "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "to must be a unix timestamp in seconds"})
+			return
+		}
+		to = time.Unix(seconds, 0)
+	}
+	from := to.Add(-(services.DefaultCandleCount - 1) * duration)
+	if value := c.Query("from"); value != "" {
+		seconds, err := strconv.ParseInt(value, 10, 64)
+		if err != nil {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "from must be a unix timestamp in seconds"})
+			return
+		}
+		from = time.Unix(seconds, 0)
+	}
+
+	candles, err := h.bscService.GetTokenCandles(address, interval, from, to)
+	if errors.Is(err, services.ErrInvalidCandleQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if errors.Is(err, services.ErrCandleTokenLimit) {
+		c.JSON(http.StatusServiceUnavailable, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to get token candles: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    candles,
+		"count":   len(candles),
+	})
+}
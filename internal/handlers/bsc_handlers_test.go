@@ -164,4 +164,118 @@ func TestBSCMultipleTokenPricesValidation(t *testing.T) {
 	err := json.Unmarshal(w.Body.Bytes(), &response)
 	assert.NoError(t, err)
 	assert.Contains(t, response["error"], "maximum 10 tokens allowed")
-}
\ No newline at end of file
+}
+func TestBSCTWAPWindowValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		Chain: config.ChainConfig{
+			RPCURL:  "https://bsc-dataseed1.binance.org/",
+			ChainID: 56,
+		},
+	}
+	handler := NewBSCHandler(cfg)
+	router := gin.New()
+	router.GET("/twap/:pool", handler.GetTWAP)
+
+	// 非法窗口和超过观测保留时间的窗口都返回400，不访问节点
+	pool := "0x16b9a82891338f9bA80E2D6970FddA79D1eb0daE"
+	for _, window := range []string{"abc", "-1", "99999999"} {
+		req, _ := http.NewRequest("GET", "/twap/"+pool+"?window="+window, nil)
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, window)
+	}
+}
+
+func TestBSCTokenRiskValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		Chain: config.ChainConfig{
+			RPCURL:  "https://bsc-dataseed1.binance.org/",
+			ChainID: 56,
+		},
+	}
+	handler := NewBSCHandler(cfg)
+	router := gin.New()
+	router.POST("/token/risk", handler.AnalyzeTokenRisk)
+
+	// 无效的买入数量和WBNB都返回400，不访问节点
+	cake := "0x0E09FaBB73Bd3Ade0a17ECC321fD13a19e81cE82"
+	for _, body := range []string{
+		`{"address":"` + cake + `","amount":"abc"}`,
+		`{"address":"` + cake + `","amount":"-1"}`,
+		`{"address":"0xbb4CdB9CBd36B01bD1cBaEBF2De08d9173bc095c"}`,
+	} {
+		req, _ := http.NewRequest("POST", "/token/risk", bytes.NewBufferString(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code, body)
+	}
+}
+
+func TestBSCSwapRequiresAdminToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(adminToken, authorization string) int {
+		cfg := &config.Config{
+			Chain: config.ChainConfig{
+				RPCURL:  "https://bsc-dataseed1.binance.org/",
+				ChainID: 56,
+			},
+			BSC: config.BSCConfig{SwapAdminToken: adminToken},
+		}
+		router := gin.New()
+		RegisterBSCRoutes(router, cfg)
+
+		body := []byte(`{"token_in":"BNB","token_out":"0x55d398326f99059fF775485246999027B3197955","amount":"1"}`)
+		req, _ := http.NewRequest("POST", "/api/v1/bsc/swap/exact-in", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 未配置令牌时兑换不可用
+	assert.Equal(t, http.StatusServiceUnavailable, serve("", "Bearer "))
+	// 缺少或错误的令牌，不会发送交易
+	assert.Equal(t, http.StatusUnauthorized, serve("secret", ""))
+	assert.Equal(t, http.StatusUnauthorized, serve("secret", "Bearer wrong"))
+}
+
+func TestBSCImportTokenListRequiresAdminToken(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	serve := func(adminToken, authorization string) int {
+		cfg := &config.Config{
+			Chain: config.ChainConfig{
+				RPCURL:  "https://bsc-dataseed1.binance.org/",
+				ChainID: 56,
+			},
+			BSC: config.BSCConfig{TokenListToken: adminToken},
+		}
+		router := gin.New()
+		RegisterBSCRoutes(router, cfg)
+
+		body := []byte(`{"url":"https://tokens.pancakeswap.finance/pancakeswap-extended.json"}`)
+		req, _ := http.NewRequest("POST", "/api/v1/bsc/tokens/import", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		if authorization != "" {
+			req.Header.Set("Authorization", authorization)
+		}
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		return w.Code
+	}
+
+	// 未配置令牌时不能通过接口导入
+	assert.Equal(t, http.StatusServiceUnavailable, serve("", "Bearer "))
+	// 缺少或错误的令牌，不会下载列表
+	assert.Equal(t, http.StatusUnauthorized, serve("secret", ""))
+	assert.Equal(t, http.StatusUnauthorized, serve("secret", "Bearer wrong"))
+}
@@ -1,6 +1,7 @@
 package handlers
 
 import (
+	"errors"
 	"net/http"
 
 	"chain/internal/config"
@@ -24,10 +25,10 @@ func NewChainHandler(cfg *config.Config) *ChainHandler {
 	}
 }
 
-// RegisterRoutes 注册路由
-func RegisterRoutes(router *gin.Engine, cfg *config.Config, db *database.Database) {
+// RegisterRoutes 注册路由，db为nil时不注册数据库查询路由，BSC数据只保存在内存中
+// 返回路由使用的服务的后台任务，由调用方在启动服务器时启动
+func RegisterRoutes(router *gin.Engine, cfg *config.Config, db *database.Database) *services.BackgroundJobs {
 	chainHandler := NewChainHandler(cfg)
-	databaseHandler := NewDatabaseHandler(db)
 
 	// 健康检查
 	router.GET("/health", healthCheck)
@@ -39,39 +40,96 @@ func RegisterRoutes(router *gin.Engine, cfg *config.Config, db *database.Databas
 		chain := api.Group("/chain")
 		{
 			chain.GET("/balance/:address", chainHandler.GetBalance)
+			chain.POST("/balances", chainHandler.GetBalances)
 			chain.POST("/transfer", chainHandler.Transfer)
 			chain.GET("/transaction/:hash", chainHandler.GetTransaction)
 			chain.POST("/contract/call", chainHandler.CallContract)
 			chain.POST("/contract/deploy", chainHandler.DeployContract)
 		}
 
-		// 数据库查询相关路由
-		db := api.Group("/db")
-		{
-			// 交易相关
-			db.GET("/transaction/:hash", databaseHandler.GetTransactionByHash)
-			db.GET("/transactions/address/:address", databaseHandler.GetTransactionsByAddress)
-			db.GET("/transactions/search", databaseHandler.SearchTransactions)
+		if db != nil {
+			registerDatabaseRoutes(api, NewDatabaseHandler(db))
+		}
+	}
 
-			// 区块相关
-			db.GET("/block/:number", databaseHandler.GetBlockByNumber)
-			db.GET("/block/hash/:hash", databaseHandler.GetBlockByHash)
-			db.GET("/blocks/latest", databaseHandler.GetLatestBlocks)
+	// BSC代币价格和行情共用同一个缓存
+	cache := services.NewCache(cfg)
+	router.GET("/api/v1/cache/stats", cacheStats(cache))
+
+	// 注册BSC相关路由，价格快照、交易对索引、TWAP观测和K线持久化到数据库
+	bscHandler := NewBSCHandler(cfg)
+	bscHandler.bscService.SetCache(cache)
+	if db != nil {
+		bscHandler.bscService.SetSnapshotStore(services.NewDBSnapshotStore(db.GetDB()))
+		bscHandler.bscService.SetPairStore(services.NewDBPairStore(db.GetDB()))
+		bscHandler.bscService.SetTokenStore(services.NewDBTokenStore(db.GetDB()))
+		bscHandler.bscService.SetObservationStore(services.NewDBObservationStore(db.GetDB()))
+		bscHandler.bscService.SetCandleStore(services.NewDBCandleStore(db.GetDB()))
+	}
+	registerBSCRoutes(router, bscHandler)
+
+	// 注册行情相关路由，bsc数据源和价格历史记录使用同一个BSC服务，币种列表和价格历史持久化到数据库
+	priceService := services.NewPriceService(cfg)
+	priceService.SetBSCService(bscHandler.bscService)
+	priceService.SetCache(cache)
+	if db != nil {
+		priceService.SetCoinStore(services.NewDBCoinStore(db.GetDB()))
+		priceService.SetHistoryStore(services.NewDBHistoryStore(db.GetDB()))
+	}
+	registerPriceRoutes(router, NewPriceHandler(priceService))
 
-			// 账户相关
-			db.GET("/account/:address", databaseHandler.GetAccountByAddress)
+	// 注册告警规则路由，规则持久化到数据库，后台任务按间隔评估并发送通知
+	alertService := services.NewAlertService(cfg, priceService)
+	alertService.SetBSCService(bscHandler.bscService)
+	if db != nil {
+		alertService.SetStore(services.NewDBAlertStore(db.GetDB()))
+	}
+	registerAlertRoutes(router, NewAlertHandler(alertService))
 
-			// 代币相关
-			db.GET("/token/:address", databaseHandler.GetTokenByAddress)
-			db.GET("/account/:address/balances", databaseHandler.GetTokenBalancesByAccount)
+	// 注册资产估值路由，数据库可用时计入 token_balances 表中保存的代币余额
+	portfolioService := services.NewPortfolioService(cfg, bscHandler.bscService, priceService)
+	if db != nil {
+		portfolioService.SetBalanceStore(services.NewDBTokenBalanceStore(db.GetDB()))
+	}
+	registerPortfolioRoutes(router, NewPortfolioHandler(portfolioService))
 
-			// 统计相关
-			db.GET("/stats", databaseHandler.GetStatistics)
-		}
+	return services.NewBackgroundJobs(bscHandler.bscService, priceService, alertService)
+}
+
+// registerDatabaseRoutes 注册数据库查询相关路由
+func registerDatabaseRoutes(api *gin.RouterGroup, databaseHandler *DatabaseHandler) {
+	db := api.Group("/db")
+	{
+		// 交易相关
+		db.GET("/transaction/:hash", databaseHandler.GetTransactionByHash)
+		db.GET("/transactions/address/:address", databaseHandler.GetTransactionsByAddress)
+		db.GET("/transactions/search", databaseHandler.SearchTransactions)
+
+		// 区块相关
+		db.GET("/block/:number", databaseHandler.GetBlockByNumber)
+		db.GET("/block/hash/:hash", databaseHandler.GetBlockByHash)
+		db.GET("/blocks/latest", databaseHandler.GetLatestBlocks)
+
+		// 账户相关
+		db.GET("/account/:address", databaseHandler.GetAccountByAddress)
+
+		// 代币相关
+		db.GET("/token/:address", databaseHandler.GetTokenByAddress)
+		db.GET("/account/:address/balances", databaseHandler.GetTokenBalancesByAccount)
+
+		// 统计相关
+		db.GET("/stats", databaseHandler.GetStatistics)
 	}
+}
 
-	// 注册BSC相关路由
-	RegisterBSCRoutes(router, cfg)
+// cacheStats 返回各接口的缓存命中统计
+func cacheStats(cache *services.Cache) gin.HandlerFunc {
+	return func(c *gin.Context) {
+		c.JSON(http.StatusOK, gin.H{
+			"success": true,
+			"data":    cache.Stats(),
+		})
+	}
 }
 
 // healthCheck 健康检查
@@ -91,6 +149,10 @@ func (h *ChainHandler) GetBalance(c *gin.Context) {
 	}
 
 	balance, err := h.chainService.GetBalance(address)
+	if errors.Is(err, services.ErrInvalidBalanceQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
 	if err != nil {
 		logger.Errorf("Failed to get balance: %v", err)
 		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
@@ -103,6 +165,42 @@ func (h *ChainHandler) GetBalance(c *gin.Context) {
 	})
 }
 
+// GetBalances 批量获取地址余额
+func (h *ChainHandler) GetBalances(c *gin.Context) {
+	var req struct {
+		Addresses []string `json:"addresses" binding:"required"`
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	balances, err := h.chainService.GetBalances(req.Addresses)
+	if errors.Is(err, services.ErrInvalidBalanceQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to get balances: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	results := make([]gin.H, len(balances))
+	for i, balance := range balances {
+		results[i] = gin.H{
+			"address": req.Addresses[i],
+			"balance": balance,
+		}
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"balances": results,
+		"count":    len(results),
+	})
+}
+
 // Transfer 转账
 func (h *ChainHandler) Transfer(c *gin.Context) {
 	var req struct {
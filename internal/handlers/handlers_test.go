@@ -8,6 +8,7 @@ import (
 	"testing"
 
 	"chain/internal/config"
+	"chain/internal/services"
 
 	"github.com/gin-gonic/gin"
 	"github.com/stretchr/testify/assert"
@@ -56,15 +57,23 @@ func TestRegisterRoutes(t *testing.T) {
 		LogLevel: "info",
 	}
 
-	// 创建路由
+	// 创建路由，后台任务由调用方启动
 	router := gin.New()
-	RegisterRoutes(router, cfg)
+	jobs := RegisterRoutes(router, cfg, nil)
+	assert.NotNil(t, jobs)
 
 	// 测试健康检查路由
 	req, _ := http.NewRequest("GET", "/health", nil)
 	w := httptest.NewRecorder()
 	router.ServeHTTP(w, req)
 	assert.Equal(t, http.StatusOK, w.Code)
+
+	// 测试缓存统计路由，未配置后端时使用内存缓存
+	req, _ = http.NewRequest("GET", "/api/v1/cache/stats", nil)
+	w = httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"backend":"memory"`)
 }
 
 func TestGetBalanceInvalidAddress(t *testing.T) {
@@ -128,4 +137,31 @@ func TestTransferInvalidRequest(t *testing.T) {
 
 	// 应该返回400错误
 	assert.Equal(t, http.StatusBadRequest, w.Code)
-}
\ No newline at end of file
+}
+func TestGetBalancesValidation(t *testing.T) {
+	gin.SetMode(gin.TestMode)
+
+	handler := &ChainHandler{chainService: &services.ChainService{}}
+	router := gin.New()
+	router.GET("/balance/:address", handler.GetBalance)
+	router.POST("/balances", handler.GetBalances)
+
+	// 地址为空、超过上限或格式无效时返回400，不访问节点
+	tooMany := make([]string, services.MaxBalanceAddresses+1)
+	for i := range tooMany {
+		tooMany[i] = "0x55d398326f99059fF775485246999027B3197955"
+	}
+	for _, addresses := range [][]string{{}, tooMany, {"not-an-address"}} {
+		body, _ := json.Marshal(gin.H{"addresses": addresses})
+		req, _ := http.NewRequest("POST", "/balances", bytes.NewBuffer(body))
+		req.Header.Set("Content-Type", "application/json")
+		w := httptest.NewRecorder()
+		router.ServeHTTP(w, req)
+		assert.Equal(t, http.StatusBadRequest, w.Code)
+	}
+
+	req, _ := http.NewRequest("GET", "/balance/not-an-address", nil)
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+}
@@ -0,0 +1,78 @@
+package handlers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+
+	"chain/internal/services"
+	"chain/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// PortfolioHandler 地址资产估值处理器
+type PortfolioHandler struct {
+	portfolioService *services.PortfolioService
+}
+
+// NewPortfolioHandler 创建资产估值处理器
+func NewPortfolioHandler(portfolioService *services.PortfolioService) *PortfolioHandler {
+	return &PortfolioHandler{
+		portfolioService: portfolioService,
+	}
+}
+
+// registerPortfolioRoutes 注册资产估值的路由
+func registerPortfolioRoutes(router *gin.Engine, portfolioHandler *PortfolioHandler) {
+	portfolio := router.Group("/api/v1/portfolio")
+	{
+		// 多个地址合计估值
+		portfolio.POST("", portfolioHandler.GetMultiplePortfolio)
+
+		// 单个地址估值
+		portfolio.GET("/:address", portfolioHandler.GetPortfolio)
+	}
+}
+
+// GetPortfolio 获取单个地址的持仓估值，tokens 查询参数为额外查询余额的代币（逗号分隔）
+func (h *PortfolioHandler) GetPortfolio(c *gin.Context) {
+	var tokens []string
+	if raw := c.Query("tokens"); raw != "" {
+		tokens = strings.Split(raw, ",")
+	}
+	h.respond(c, []string{c.Param("address")}, tokens)
+}
+
+// GetMultiplePortfolio 获取多个地址合计的持仓估值
+func (h *PortfolioHandler) GetMultiplePortfolio(c *gin.Context) {
+	var req struct {
+		Addresses []string `json:"addresses" binding:"required"`
+		Tokens    []string `json:"tokens"` // 额外查询余额的代币地址
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	h.respond(c, req.Addresses, req.Tokens)
+}
+
+// respond 查询持仓估值，地址或代币无效时返回400
+func (h *PortfolioHandler) respond(c *gin.Context, addresses, tokens []string) {
+	portfolio, err := h.portfolioService.GetPortfolio(c.Request.Context(), addresses, tokens)
+	if err != nil {
+		if errors.Is(err, services.ErrInvalidPortfolioQuery) {
+			c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+			return
+		}
+		logger.Errorf("Failed to get portfolio: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    portfolio,
+	})
+}
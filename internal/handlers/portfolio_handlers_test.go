@@ -0,0 +1,100 @@
+package handlers
+
+import (
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"chain/internal/config"
+	"chain/internal/services"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubBSCNode 模拟没有部署合约的BSC节点，每个账户持有1 BNB
+func stubBSCNode(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		type request struct {
+			ID     json.RawMessage `json:"id"`
+			Method string          `json:"method"`
+		}
+		reply := func(req request) gin.H {
+			result := "0x"
+			if req.Method == "eth_getBalance" {
+				result = "0xde0b6b3a7640000"
+			}
+			return gin.H{"jsonrpc": "2.0", "id": req.ID, "result": result}
+		}
+
+		var raw json.RawMessage
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&raw))
+		var batch []request
+		if err := json.Unmarshal(raw, &batch); err != nil {
+			var single request
+			require.NoError(t, json.Unmarshal(raw, &single))
+			json.NewEncoder(w).Encode(reply(single))
+			return
+		}
+
+		results := make([]gin.H, 0, len(batch))
+		for _, req := range batch {
+			results = append(results, reply(req))
+		}
+		json.NewEncoder(w).Encode(results)
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newPortfolioTestRouter 创建连接到模拟节点和模拟行情API的估值路由
+func newPortfolioTestRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	cfg := &config.Config{
+		Chain:     config.ChainConfig{RPCURL: stubBSCNode(t).URL, ChainID: 56, MulticallWait: 1},
+		Price:     config.PriceConfig{APIURL: stubCoinGecko(t).URL, Timeout: 5},
+		Portfolio: config.PortfolioConfig{MaxAddresses: 2},
+	}
+	router := gin.New()
+	registerPortfolioRoutes(router, NewPortfolioHandler(services.NewPortfolioService(cfg, services.NewBSCService(cfg), services.NewPriceService(cfg))))
+	return router
+}
+
+func TestPortfolioRoutes(t *testing.T) {
+	router := newPortfolioTestRouter(t)
+
+	// 代币余额和BNB价格都无法查询时仍返回BNB余额，并说明失败原因
+	code, resp := servePriceRequest(t, router, "GET", "/api/v1/portfolio/0x00000000000000000000000000000000000a11ce", nil)
+	require.Equal(t, http.StatusOK, code)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{"0x00000000000000000000000000000000000A11cE"}, data["addresses"])
+	holdings := data["holdings"].([]interface{})
+	require.Len(t, holdings, 1)
+	assert.Equal(t, "1", holdings[0].(map[string]interface{})["balance"])
+	assert.Equal(t, true, holdings[0].(map[string]interface{})["native"])
+	assert.Equal(t, 0.0, data["total_value_usd"])
+	assert.NotEmpty(t, data["warnings"])
+
+	body, _ := json.Marshal(gin.H{"addresses": []string{"0x00000000000000000000000000000000000a11ce", "0x0000000000000000000000000000000000000b0b"}})
+	code, resp = servePriceRequest(t, router, "POST", "/api/v1/portfolio", body)
+	require.Equal(t, http.StatusOK, code)
+	data = resp["data"].(map[string]interface{})
+	assert.Len(t, data["accounts"], 2)
+	assert.Equal(t, "2", data["holdings"].([]interface{})[0].(map[string]interface{})["balance"])
+
+	for _, tt := range []struct {
+		method, path, body string
+	}{
+		{"GET", "/api/v1/portfolio/0x123", ""},
+		{"GET", "/api/v1/portfolio/0x00000000000000000000000000000000000a11ce?tokens=cake", ""},
+		{"POST", "/api/v1/portfolio", `{}`},
+		{"POST", "/api/v1/portfolio", `{"addresses":["0x00000000000000000000000000000000000000a1","0x00000000000000000000000000000000000000a2","0x00000000000000000000000000000000000000a3"]}`},
+	} {
+		code, resp = servePriceRequest(t, router, tt.method, tt.path, []byte(tt.body))
+		assert.Equal(t, http.StatusBadRequest, code, tt.path+" "+tt.body)
+		assert.NotEmpty(t, resp["error"])
+	}
+}
@@ -0,0 +1,244 @@
+package handlers
+
+import (
+	"errors"
+	"fmt"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"chain/internal/config"
+	"chain/internal/services"
+	"chain/pkg/logger"
+
+	"github.com/gin-gonic/gin"
+)
+
+// 行情查询参数限制
+const (
+	maxPriceSymbols = 250 // 单次批量查询的最大币种数
+	maxTopLimit     = 250
+)
+
+// PriceHandler 加密货币行情处理器
+type PriceHandler struct {
+	priceService *services.PriceService
+}
+
+// NewPriceHandler 创建行情处理器
+func NewPriceHandler(priceService *services.PriceService) *PriceHandler {
+	return &PriceHandler{
+		priceService: priceService,
+	}
+}
+
+// RegisterPriceRoutes 注册行情相关路由
+func RegisterPriceRoutes(router *gin.Engine, cfg *config.Config) {
+	registerPriceRoutes(router, NewPriceHandler(services.NewPriceService(cfg)))
+}
+
+// registerPriceRoutes 注册行情处理器的路由
+func registerPriceRoutes(router *gin.Engine, priceHandler *PriceHandler) {
+	price := router.Group("/api/v1/price")
+	{
+		// 市值排名前N的币种
+		price.GET("/top", priceHandler.GetTopCryptoPrices)
+
+		// 按名称或符号搜索币种
+		price.GET("/search", priceHandler.SearchCrypto)
+
+		// 将符号、名称或BSC合约地址解析为币种ID
+		price.GET("/resolve", priceHandler.ResolveCoin)
+
+		// 批量查询价格
+		price.POST("/batch", priceHandler.GetMultipleCryptoPrices)
+
+		// 单个币种的价格和历史价格
+		price.GET("/:symbol", priceHandler.GetCryptoPrice)
+		price.GET("/:symbol/history", priceHandler.GetPriceHistory)
+	}
+}
+
+// quoteCurrencies 解析 currencies 查询参数（逗号分隔），不支持的货币返回400
+func quoteCurrencies(c *gin.Context) ([]string, bool) {
+	currencies, err := services.ParseQuoteCurrencies(c.Query("currencies"))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return nil, false
+	}
+	return currencies, true
+}
+
+// GetCryptoPrice 获取单个币种的价格
+func (h *PriceHandler) GetCryptoPrice(c *gin.Context) {
+	currencies, ok := quoteCurrencies(c)
+	if !ok {
+		return
+	}
+
+	price, err := h.priceService.GetCryptoPrice(c.Request.Context(), c.Param("symbol"), currencies...)
+	if errors.Is(err, services.ErrPriceNotFound) {
+		c.JSON(http.StatusNotFound, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to get crypto price: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    price,
+	})
+}
+
+// GetMultipleCryptoPrices 批量获取币种价格
+func (h *PriceHandler) GetMultipleCryptoPrices(c *gin.Context) {
+	var req struct {
+		Symbols    []string `json:"symbols" binding:"required"`
+		Currencies []string `json:"currencies"` // 计价货币，留空只返回USD价格
+	}
+
+	if err := c.ShouldBindJSON(&req); err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if len(req.Symbols) == 0 || len(req.Symbols) > maxPriceSymbols {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("symbols must contain between 1 and %d entries", maxPriceSymbols)})
+		return
+	}
+	currencies, err := services.ParseQuoteCurrencies(strings.Join(req.Currencies, ","))
+	if err != nil {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+
+	prices, err := h.priceService.GetMultipleCryptoPrices(c.Request.Context(), req.Symbols, currencies...)
+	if err != nil {
+		logger.Errorf("Failed to get crypto prices: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    prices,
+		"count":   len(prices),
+	})
+}
+
+// GetTopCryptoPrices 获取市值排名前N的币种价格，limit默认10
+func (h *PriceHandler) GetTopCryptoPrices(c *gin.Context) {
+	limit, err := strconv.Atoi(c.DefaultQuery("limit", "10"))
+	if err != nil || limit <= 0 || limit > maxTopLimit {
+		c.JSON(http.StatusBadRequest, gin.H{"error": fmt.Sprintf("limit must be between 1 and %d", maxTopLimit)})
+		return
+	}
+	currencies, ok := quoteCurrencies(c)
+	if !ok {
+		return
+	}
+
+	prices, err := h.priceService.GetTopCryptoPrices(c.Request.Context(), limit, currencies...)
+	if err != nil {
+		logger.Errorf("Failed to get top crypto prices: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    prices,
+		"count":   len(prices),
+	})
+}
+
+// SearchCrypto 按名称或符号搜索币种
+func (h *PriceHandler) SearchCrypto(c *gin.Context) {
+	query := c.Query("query")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query is required"})
+		return
+	}
+	currencies, ok := quoteCurrencies(c)
+	if !ok {
+		return
+	}
+
+	results, err := h.priceService.SearchCrypto(c.Request.Context(), query, currencies...)
+	if err != nil {
+		logger.Errorf("Failed to search crypto: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    results,
+		"count":   len(results),
+	})
+}
+
+// ResolveCoin 返回查询解析得到的币种及匹配方式
+func (h *PriceHandler) ResolveCoin(c *gin.Context) {
+	query := c.Query("query")
+	if query == "" {
+		c.JSON(http.StatusBadRequest, gin.H{"error": "query is required"})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    h.priceService.ResolveCoin(query),
+	})
+}
+
+// GetPriceHistory 获取币种的价格历史，from和to为Unix秒；未指定from时返回to之前days天（默认7）
+// interval为5m、1h、1d或1w，为空时按时间范围自动选择
+func (h *PriceHandler) GetPriceHistory(c *gin.Context) {
+	query := services.HistoryQuery{
+		Interval: c.Query("interval"),
+		Currency: c.Query("currency"),
+	}
+	if value := c.Query("days"); value != "" {
+		days, err := strconv.Atoi(value)
+		if err != nil || days <= 0 {
+			c.JSON(http.StatusBadRequest, gin.H{"error": "days must be a positive integer"})
+			return
+		}
+		query.Days = days
+	}
+	for _, param := range []struct {
+		name   string
+		target *time.Time
+	}{{"from", &query.From}, {"to", &query.To}} {
+		if value := c.Query(param.name); value != "" {
+			seconds, err := strconv.ParseInt(value, 10, 64)
+			if err != nil {
+				c.JSON(http.StatusBadRequest, gin.H{"error": param.name + " must be a unix timestamp in seconds"})
+				return
+			}
+			*param.target = time.Unix(seconds, 0).UTC()
+		}
+	}
+
+	history, err := h.priceService.GetPriceHistory(c.Request.Context(), c.Param("symbol"), query)
+	if errors.Is(err, services.ErrInvalidHistoryQuery) {
+		c.JSON(http.StatusBadRequest, gin.H{"error": err.Error()})
+		return
+	}
+	if err != nil {
+		logger.Errorf("Failed to get price history: %v", err)
+		c.JSON(http.StatusInternalServerError, gin.H{"error": err.Error()})
+		return
+	}
+
+	c.JSON(http.StatusOK, gin.H{
+		"success": true,
+		"data":    history,
+		"source":  history.Source,
+		"count":   len(history.Points),
+	})
+}
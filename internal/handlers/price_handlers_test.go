@@ -0,0 +1,207 @@
+package handlers
+
+import (
+	"bytes"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	"chain/internal/config"
+
+	"github.com/gin-gonic/gin"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubCoinGecko 模拟CoinGecko API，只认识bitcoin和ethereum
+func stubCoinGecko(t *testing.T) *httptest.Server {
+	coins := map[string]gin.H{
+		"bitcoin":  {"id": "bitcoin", "symbol": "btc", "name": "Bitcoin", "current_price": 65000.5, "market_cap": 1.2e12, "total_volume": 3.1e10, "last_updated": "2026-01-01T00:00:00Z"},
+		"ethereum": {"id": "ethereum", "symbol": "eth", "name": "Ethereum", "current_price": 3200.25, "market_cap": 3.8e11, "total_volume": 1.5e10, "last_updated": "2026-01-01T00:00:00Z"},
+	}
+
+	mux := http.NewServeMux()
+	mux.HandleFunc("/coins/markets", func(w http.ResponseWriter, r *http.Request) {
+		var result []gin.H
+		if ids := r.URL.Query().Get("ids"); ids != "" {
+			for _, id := range strings.Split(ids, ",") {
+				if coin, ok := coins[id]; ok {
+					result = append(result, coin)
+				}
+			}
+		} else {
+			result = []gin.H{coins["bitcoin"], coins["ethereum"]}
+		}
+		json.NewEncoder(w).Encode(result)
+	})
+	mux.HandleFunc("/simple/price", func(w http.ResponseWriter, r *http.Request) {
+		result := gin.H{}
+		for _, id := range strings.Split(r.URL.Query().Get("ids"), ",") {
+			if _, ok := coins[id]; ok {
+				result[id] = gin.H{"eur": 60000}
+			}
+		}
+		json.NewEncoder(w).Encode(result)
+	})
+	mux.HandleFunc("/search", func(w http.ResponseWriter, r *http.Request) {
+		json.NewEncoder(w).Encode(gin.H{"coins": []gin.H{{"id": "ethereum", "name": "Ethereum", "symbol": "ETH"}}})
+	})
+	mux.HandleFunc("/coins/bitcoin/market_chart/range", func(w http.ResponseWriter, r *http.Request) {
+		// 从from开始每小时一个价格点
+		from, err := strconv.ParseFloat(r.URL.Query().Get("from"), 64)
+		require.NoError(t, err)
+		json.NewEncoder(w).Encode(gin.H{"prices": [][]float64{{from * 1000, 64000}, {(from + 3600) * 1000, 64500}, {(from + 7200) * 1000, 65000}}})
+	})
+	mux.HandleFunc("/coins/unknown/market_chart/range", func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusNotFound)
+	})
+
+	server := httptest.NewServer(mux)
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newPriceTestRouter 创建连接到模拟API的行情路由
+func newPriceTestRouter(t *testing.T) *gin.Engine {
+	gin.SetMode(gin.TestMode)
+
+	upstream := stubCoinGecko(t)
+	router := gin.New()
+	RegisterPriceRoutes(router, &config.Config{
+		Price: config.PriceConfig{APIURL: upstream.URL + "/", Timeout: 5},
+	})
+	return router
+}
+
+// servePriceRequest 发送请求并解析JSON响应
+func servePriceRequest(t *testing.T, router *gin.Engine, method, path string, body []byte) (int, map[string]interface{}) {
+	req, _ := http.NewRequest(method, path, bytes.NewBuffer(body))
+	req.Header.Set("Content-Type", "application/json")
+	w := httptest.NewRecorder()
+	router.ServeHTTP(w, req)
+
+	var resp map[string]interface{}
+	require.NoError(t, json.Unmarshal(w.Body.Bytes(), &resp))
+	return w.Code, resp
+}
+
+func TestGetCryptoPriceRoute(t *testing.T) {
+	router := newPriceTestRouter(t)
+
+	code, resp := servePriceRequest(t, router, "GET", "/api/v1/price/Bitcoin", nil)
+	require.Equal(t, http.StatusOK, code)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "btc", data["symbol"])
+	assert.Equal(t, 65000.5, data["current_price"])
+	assert.Equal(t, 3.1e10, data["volume_24h"])
+	assert.Equal(t, "coingecko", data["source"])
+
+	code, resp = servePriceRequest(t, router, "GET", "/api/v1/price/dogecoin", nil)
+	assert.Equal(t, http.StatusNotFound, code)
+	assert.Contains(t, resp["error"], "no price data found")
+
+	// 指定计价货币
+	code, resp = servePriceRequest(t, router, "GET", "/api/v1/price/bitcoin?currencies=EUR,usd", nil)
+	require.Equal(t, http.StatusOK, code)
+	quotes := resp["data"].(map[string]interface{})["quotes"].(map[string]interface{})
+	assert.Equal(t, 60000.0, quotes["eur"].(map[string]interface{})["price"])
+	assert.Equal(t, 65000.5, quotes["usd"].(map[string]interface{})["price"])
+
+	code, resp = servePriceRequest(t, router, "GET", "/api/v1/price/bitcoin?currencies=gbp", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+	assert.Equal(t, "unsupported quote currency: gbp", resp["error"])
+}
+
+func TestGetMultipleCryptoPricesRoute(t *testing.T) {
+	router := newPriceTestRouter(t)
+
+	body, _ := json.Marshal(gin.H{"symbols": []string{"bitcoin", "ethereum"}})
+	code, resp := servePriceRequest(t, router, "POST", "/api/v1/price/batch", body)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(2), resp["count"])
+	data := resp["data"].(map[string]interface{})
+	assert.Contains(t, data, "btc")
+	assert.Contains(t, data, "eth")
+
+	code, _ = servePriceRequest(t, router, "POST", "/api/v1/price/batch", []byte(`{"symbols":[]}`))
+	assert.Equal(t, http.StatusBadRequest, code)
+
+	code, _ = servePriceRequest(t, router, "POST", "/api/v1/price/batch", []byte(`{"symbols":["bitcoin"],"currencies":["xyz"]}`))
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestGetTopCryptoPricesRoute(t *testing.T) {
+	router := newPriceTestRouter(t)
+
+	code, resp := servePriceRequest(t, router, "GET", "/api/v1/price/top?limit=2", nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, float64(2), resp["count"])
+
+	code, _ = servePriceRequest(t, router, "GET", "/api/v1/price/top?limit=0", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestSearchCryptoRoute(t *testing.T) {
+	router := newPriceTestRouter(t)
+
+	code, resp := servePriceRequest(t, router, "GET", "/api/v1/price/search?query=eth", nil)
+	require.Equal(t, http.StatusOK, code)
+	results := resp["data"].([]interface{})
+	require.Len(t, results, 1)
+	assert.Equal(t, "Ethereum", results[0].(map[string]interface{})["name"])
+
+	code, _ = servePriceRequest(t, router, "GET", "/api/v1/price/search", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestResolveCoinRoute(t *testing.T) {
+	router := newPriceTestRouter(t)
+
+	// 币种列表尚未刷新时原样作为币种ID
+	code, resp := servePriceRequest(t, router, "GET", "/api/v1/price/resolve?query=Bitcoin", nil)
+	require.Equal(t, http.StatusOK, code)
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, "bitcoin", data["coin_id"])
+	assert.Equal(t, "Bitcoin", data["query"])
+	assert.Equal(t, "none", data["matched_by"])
+
+	code, _ = servePriceRequest(t, router, "GET", "/api/v1/price/resolve", nil)
+	assert.Equal(t, http.StatusBadRequest, code)
+}
+
+func TestGetPriceHistoryRoute(t *testing.T) {
+	router := newPriceTestRouter(t)
+
+	code, resp := servePriceRequest(t, router, "GET", "/api/v1/price/bitcoin/history?from=1767225600&to=1767236400&interval=1h", nil)
+	require.Equal(t, http.StatusOK, code)
+	assert.Equal(t, "coingecko", resp["source"])
+	assert.Equal(t, 3.0, resp["count"])
+	data := resp["data"].(map[string]interface{})
+	assert.Equal(t, []interface{}{64000.0, 64500.0, 65000.0}, data["prices"])
+	assert.Equal(t, "1h", data["interval"])
+	assert.Equal(t, "usd", data["currency"])
+	assert.Equal(t, "2026-01-01T00:00:00Z", data["from"])
+	points := data["points"].([]interface{})
+	require.Len(t, points, 3)
+	assert.Equal(t, "2026-01-01T01:00:00Z", points[1].(map[string]interface{})["timestamp"])
+	assert.Len(t, data["candles"], 3)
+
+	// 指定计价货币时由数据源直接查询该货币的历史
+	code, resp = servePriceRequest(t, router, "GET", "/api/v1/price/bitcoin/history?days=1&currency=btc", nil)
+	require.Equal(t, http.StatusOK, code)
+	data = resp["data"].(map[string]interface{})
+	assert.Equal(t, "btc", data["currency"])
+	assert.Equal(t, "5m", data["interval"])
+
+	for _, query := range []string{"days=0", "from=abc", "interval=2h", "currency=gbp", "days=100&interval=5m", "from=1767236400&to=1767225600"} {
+		code, _ = servePriceRequest(t, router, "GET", "/api/v1/price/bitcoin/history?"+query, nil)
+		assert.Equal(t, http.StatusBadRequest, code, query)
+	}
+
+	code, resp = servePriceRequest(t, router, "GET", "/api/v1/price/unknown/history", nil)
+	assert.Equal(t, http.StatusInternalServerError, code)
+	assert.Contains(t, resp["error"], "status: 404")
+}
@@ -60,16 +60,22 @@ type Account struct {
 
 // Token 代币信息模型
 type Token struct {
-	ID          uint           `gorm:"primaryKey" json:"id"`
-	Address     string         `gorm:"uniqueIndex;size:42" json:"address"`
-	Name        string         `gorm:"size:100" json:"name"`
-	Symbol      string         `gorm:"size:20" json:"symbol"`
-	Decimals    uint8          `json:"decimals"`
-	TotalSupply string         `gorm:"type:varchar(78)" json:"total_supply"`
-	ChainID     uint64         `gorm:"index" json:"chain_id"`
-	CreatedAt   time.Time      `json:"created_at"`
-	UpdatedAt   time.Time      `json:"updated_at"`
-	DeletedAt   gorm.DeletedAt `gorm:"index" json:"-"`
+	ID                uint           `gorm:"primaryKey" json:"id"`
+	Address           string         `gorm:"uniqueIndex;size:42" json:"address"`
+	Name              string         `gorm:"size:100;index" json:"name"`
+	Symbol            string         `gorm:"size:20;index" json:"symbol"`
+	Decimals          uint8          `json:"decimals"`
+	TotalSupply       string         `gorm:"type:varchar(78)" json:"total_supply"`
+	ChainID           uint64         `gorm:"index" json:"chain_id"`
+	LogoURI           string         `gorm:"size:512" json:"logo_uri"`
+	Verified          bool           `gorm:"index" json:"verified"`  // 来自代币列表的代币为已验证，索引发现的代币为未验证
+	Source            string         `gorm:"size:100" json:"source"` // 代币列表名称或发现该代币的DEX
+	IsProxy           bool           `json:"is_proxy"`               // 是否为EIP-1967代理合约
+	Implementation    string         `gorm:"size:42" json:"implementation"`
+	MetadataUpdatedAt *time.Time     `json:"metadata_updated_at"` // 最近一次从链上读取元数据的时间，为空表示尚未读取
+	CreatedAt         time.Time      `json:"created_at"`
+	UpdatedAt         time.Time      `json:"updated_at"`
+	DeletedAt         gorm.DeletedAt `gorm:"index" json:"-"`
 }
 
 // TokenBalance 代币余额模型
@@ -88,6 +94,127 @@ type TokenBalance struct {
 	Token   Token   `gorm:"foreignKey:TokenID" json:"token,omitempty"`
 }
 
+// PriceSnapshot 代币价格快照模型，用于计算24小时涨跌幅
+type PriceSnapshot struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	TokenAddress string    `gorm:"index:idx_price_snapshot_token_time;size:42" json:"token_address"`
+	PriceUSD     string    `gorm:"type:varchar(78)" json:"price_usd"`
+	PriceBNB     string    `gorm:"type:varchar(78)" json:"price_bnb"`
+	ChainID      uint64    `gorm:"index" json:"chain_id"`
+	Timestamp    time.Time `gorm:"index:idx_price_snapshot_token_time" json:"timestamp"`
+	CreatedAt    time.Time `json:"created_at"`
+}
+
+// PoolObservation 池子累计价格观测，用于计算时间加权平均价格（TWAP）
+type PoolObservation struct {
+	ID               uint      `gorm:"primaryKey" json:"id"`
+	Pool             string    `gorm:"index:idx_pool_observation_pool_time;size:42" json:"pool"`
+	Price0Cumulative string    `gorm:"type:varchar(78)" json:"price0_cumulative"` // V2交易对UQ112x112格式的累计价格，V3池为空
+	Price1Cumulative string    `gorm:"type:varchar(78)" json:"price1_cumulative"`
+	TickCumulative   string    `gorm:"type:varchar(32)" json:"tick_cumulative"` // V3池的累计tick，V2交易对为空
+	ChainID          uint64    `gorm:"index" json:"chain_id"`
+	Timestamp        time.Time `gorm:"index:idx_pool_observation_pool_time" json:"timestamp"` // 观测对应的区块时间
+	CreatedAt        time.Time `json:"created_at"`
+}
+
+// PriceCandle 代币价格K线，由代币主要交易对的Swap事件聚合得到
+type PriceCandle struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	TokenAddress string    `gorm:"uniqueIndex:idx_price_candle_token_interval_time;size:42" json:"token_address"`
+	Interval     string    `gorm:"column:candle_interval;uniqueIndex:idx_price_candle_token_interval_time;size:8" json:"interval"` // 1m、5m、1h 或 1d
+	OpenTime     time.Time `gorm:"uniqueIndex:idx_price_candle_token_interval_time" json:"open_time"`
+	OpenUSD      string    `gorm:"type:varchar(78)" json:"open_usd"`
+	HighUSD      string    `gorm:"type:varchar(78)" json:"high_usd"`
+	LowUSD       string    `gorm:"type:varchar(78)" json:"low_usd"`
+	CloseUSD     string    `gorm:"type:varchar(78)" json:"close_usd"`
+	OpenBNB      string    `gorm:"type:varchar(78)" json:"open_bnb"`
+	HighBNB      string    `gorm:"type:varchar(78)" json:"high_bnb"`
+	LowBNB       string    `gorm:"type:varchar(78)" json:"low_bnb"`
+	CloseBNB     string    `gorm:"type:varchar(78)" json:"close_bnb"`
+	Volume       string    `gorm:"type:varchar(78)" json:"volume"`     // 代币成交数量
+	VolumeUSD    string    `gorm:"type:varchar(78)" json:"volume_usd"` // USD成交额
+	Trades       int       `json:"trades"`
+	LastBlock    uint64    `json:"-"` // 最后计入的成交所在的区块，重复处理区块时跳过已计入的成交
+	ChainID      uint64    `gorm:"index" json:"chain_id"`
+	CreatedAt    time.Time `json:"created_at"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// CandleSyncState K线聚合进度，记录每个代币已处理到的区块
+type CandleSyncState struct {
+	ID           uint      `gorm:"primaryKey" json:"id"`
+	TokenAddress string    `gorm:"uniqueIndex;size:42" json:"token_address"`
+	LastBlock    uint64    `json:"last_block"`
+	UpdatedAt    time.Time `json:"updated_at"`
+}
+
+// DexPair DEX交易对模型，由工厂合约的PairCreated事件索引得到
+type DexPair struct {
+	ID          uint      `gorm:"primaryKey" json:"id"`
+	Dex         string    `gorm:"index;size:50" json:"dex"`
+	Factory     string    `gorm:"size:42" json:"factory"`
+	PairAddress string    `gorm:"uniqueIndex;size:42" json:"pair_address"`
+	Token0      string    `gorm:"index;size:42" json:"token0"`
+	Token1      string    `gorm:"index;size:42" json:"token1"`
+	PairIndex   uint64    `json:"pair_index"` // 工厂合约allPairs中的序号
+	BlockNumber uint64    `gorm:"index" json:"block_number"`
+	BlockTime   time.Time `gorm:"index" json:"block_time"`
+	TxHash      string    `gorm:"size:66" json:"tx_hash"`
+	ChainID     uint64    `gorm:"index" json:"chain_id"`
+	CreatedAt   time.Time `json:"created_at"`
+}
+
+// PairSyncState 交易对索引进度，记录每个工厂合约已同步到的区块
+type PairSyncState struct {
+	ID        uint      `gorm:"primaryKey" json:"id"`
+	Factory   string    `gorm:"uniqueIndex;size:42" json:"factory"`
+	LastBlock uint64    `json:"last_block"`
+	UpdatedAt time.Time `json:"updated_at"`
+}
+
+// Coin 行情数据源的币种，用于将符号、名称和BSC合约地址解析为币种ID
+type Coin struct {
+	ID            uint      `gorm:"primaryKey" json:"-"`
+	CoinID        string    `gorm:"uniqueIndex;size:100" json:"coin_id"` // CoinGecko币种ID，如 bitcoin
+	Symbol        string    `gorm:"size:50;index" json:"symbol"`         // 小写符号
+	Name          string    `gorm:"size:200;index" json:"name"`
+	BSCAddress    string    `gorm:"size:42;index" json:"bsc_address,omitempty"` // BSC合约地址，没有时为空
+	MarketCapRank int       `json:"market_cap_rank"`                            // 市值排名，0表示未进入排名
+	CreatedAt     time.Time `json:"-"`
+	UpdatedAt     time.Time `json:"updated_at"`
+}
+
+// HistoricalPrice 按固定间隔记录的USD价格，用于在本地提供价格历史
+type HistoricalPrice struct {
+	ID        uint      `gorm:"primaryKey" json:"-"`
+	Asset     string    `gorm:"uniqueIndex:idx_historical_price_asset_time;size:100" json:"asset"` // 币种ID，或小写的BSC代币地址
+	Kind      string    `gorm:"size:10" json:"kind"`                                               // coin 或 token
+	Price     float64   `json:"price"`
+	MarketCap float64   `json:"market_cap"`
+	Volume24h float64   `json:"volume_24h"`
+	Source    string    `gorm:"size:20" json:"source"`                                        // 产生价格的数据源
+	Timestamp time.Time `gorm:"uniqueIndex:idx_historical_price_asset_time" json:"timestamp"` // 按记录间隔对齐
+	CreatedAt time.Time `json:"-"`
+}
+
+// AlertRule 价格告警规则
+type AlertRule struct {
+	ID              uint       `gorm:"primaryKey" json:"id"`
+	Name            string     `gorm:"size:100" json:"name"`
+	Asset           string     `gorm:"size:100;index" json:"asset"` // 币种符号、名称、币种ID，或BSC代币地址
+	Source          string     `gorm:"size:10" json:"source"`       // crypto（行情数据源）或 token（BSC DEX价格）
+	Condition       string     `gorm:"size:20" json:"condition"`    // above、below、change、change_up 或 change_down
+	Threshold       float64    `json:"threshold"`                   // above/below为USD价格，change类为涨跌幅百分比
+	Window          int        `json:"window"`                      // change类条件的时间窗口（秒）
+	Cooldown        int        `json:"cooldown"`                    // 触发后再次通知前的冷却时间（秒）
+	Channel         string     `gorm:"size:20" json:"channel"`      // webhook、email 或 telegram
+	Target          string     `gorm:"size:500" json:"target"`      // webhook地址、收件人邮箱或Telegram chat_id
+	Enabled         bool       `json:"enabled"`
+	LastTriggeredAt *time.Time `json:"last_triggered_at,omitempty"`
+	CreatedAt       time.Time  `json:"created_at"`
+	UpdatedAt       time.Time  `json:"updated_at"`
+}
+
 // TableName 设置表名
 func (Transaction) TableName() string {
 	return "transactions"
@@ -107,4 +234,40 @@ func (Token) TableName() string {
 
 func (TokenBalance) TableName() string {
 	return "token_balances"
-}
\ No newline at end of file
+}
+
+func (PriceSnapshot) TableName() string {
+	return "price_snapshots"
+}
+
+func (DexPair) TableName() string {
+	return "dex_pairs"
+}
+
+func (PairSyncState) TableName() string {
+	return "pair_sync_states"
+}
+
+func (PoolObservation) TableName() string {
+	return "pool_observations"
+}
+
+func (PriceCandle) TableName() string {
+	return "price_candles"
+}
+
+func (CandleSyncState) TableName() string {
+	return "candle_sync_states"
+}
+
+func (Coin) TableName() string {
+	return "coins"
+}
+
+func (HistoricalPrice) TableName() string {
+	return "historical_prices"
+}
+
+func (AlertRule) TableName() string {
+	return "alert_rules"
+}
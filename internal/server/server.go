@@ -13,6 +13,7 @@ import (
 	"chain/internal/database"
 	"chain/internal/handlers"
 	"chain/internal/models"
+	"chain/internal/services"
 	"chain/pkg/logger"
 
 	"github.com/gin-gonic/gin"
@@ -24,6 +25,7 @@ type Server struct {
 	router *gin.Engine
 	server *http.Server
 	db     *database.Database
+	jobs   *services.BackgroundJobs
 }
 
 // New 创建新的服务器实例
@@ -38,7 +40,7 @@ func New(cfg *config.Config) *Server {
 	// 初始化数据库
 	db, err := database.New(&cfg.Database)
 	if err != nil {
-		logger.Error("Failed to initialize database: %v", err)
+		logger.Errorf("Failed to initialize database: %v", err)
 		panic(err)
 	}
 
@@ -49,9 +51,18 @@ func New(cfg *config.Config) *Server {
 		&models.Account{},
 		&models.Token{},
 		&models.TokenBalance{},
+		&models.PriceSnapshot{},
+		&models.DexPair{},
+		&models.PairSyncState{},
+		&models.PoolObservation{},
+		&models.PriceCandle{},
+		&models.CandleSyncState{},
+		&models.Coin{},
+		&models.HistoricalPrice{},
+		&models.AlertRule{},
 	)
 	if err != nil {
-		logger.Error("Failed to migrate database: %v", err)
+		logger.Errorf("Failed to migrate database: %v", err)
 		panic(err)
 	}
 
@@ -63,12 +74,13 @@ func New(cfg *config.Config) *Server {
 	router.Use(corsMiddleware())
 
 	// 注册路由
-	handlers.RegisterRoutes(router, cfg, db)
+	jobs := handlers.RegisterRoutes(router, cfg, db)
 
 	return &Server{
 		config: cfg,
 		router: router,
 		db:     db,
+		jobs:   jobs,
 	}
 }
 
@@ -89,12 +101,17 @@ func (s *Server) Start() error {
 		}
 	}()
 
+	// 启动后台任务，关闭服务器时停止
+	jobsCtx, stopJobs := context.WithCancel(context.Background())
+	s.jobs.Start(jobsCtx)
+
 	// 等待中断信号
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
 	<-quit
 
 	logger.Info("Shutting down server...")
+	stopJobs()
 
 	// 优雅关闭
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
@@ -0,0 +1,204 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"crypto/tls"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"mime"
+	"net"
+	"net/http"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+
+	"chain/internal/config"
+)
+
+// 告警通知渠道
+const (
+	AlertChannelWebhook  = "webhook"
+	AlertChannelEmail    = "email"
+	AlertChannelTelegram = "telegram"
+)
+
+const defaultTelegramAPIURL = "https://api.telegram.org"
+
+// Notifier 告警通知渠道，target为规则配置的接收方
+type Notifier interface {
+	Notify(ctx context.Context, target string, event *AlertEvent) error
+}
+
+// newNotifiers 按配置创建各渠道的通知器，webhook地址由用户提供，只允许连接公网地址
+func newNotifiers(cfg config.AlertConfig, timeout time.Duration) map[string]Notifier {
+	return map[string]Notifier{
+		AlertChannelWebhook:  &webhookNotifier{httpClient: newPublicHTTPClient(timeout, nil)},
+		AlertChannelEmail:    &emailNotifier{config: cfg.SMTP},
+		AlertChannelTelegram: newTelegramNotifier(cfg.Telegram, &http.Client{Timeout: timeout}),
+	}
+}
+
+// webhookNotifier 以JSON POST告警事件到规则配置的地址
+type webhookNotifier struct {
+	httpClient *http.Client
+}
+
+// Notify 发送告警事件，非2xx响应视为失败
+func (w *webhookNotifier) Notify(ctx context.Context, target string, event *AlertEvent) error {
+	body, err := json.Marshal(event)
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, target, bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := w.httpClient.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to send webhook: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, resp.Body)
+
+	if resp.StatusCode < 200 || resp.StatusCode >= 300 {
+		return fmt.Errorf("webhook responded with status: %d", resp.StatusCode)
+	}
+	return nil
+}
+
+// emailNotifier 通过SMTP发送告警邮件，配置了用户名时使用PLAIN认证
+type emailNotifier struct {
+	config config.SMTPConfig
+}
+
+// Notify 向target邮箱发送告警邮件
+func (e *emailNotifier) Notify(ctx context.Context, target string, event *AlertEvent) error {
+	if e.config.Host == "" {
+		return errors.New("SMTP server not configured")
+	}
+
+	var auth smtp.Auth
+	if e.config.Username != "" {
+		auth = smtp.PlainAuth("", e.config.Username, e.config.Password, e.config.Host)
+	}
+
+	var msg strings.Builder
+	fmt.Fprintf(&msg, "From: %s\r\n", e.config.From)
+	fmt.Fprintf(&msg, "To: %s\r\n", target)
+	fmt.Fprintf(&msg, "Subject: %s\r\n", mime.QEncoding.Encode("utf-8", event.Subject()))
+	fmt.Fprintf(&msg, "Date: %s\r\n", event.TriggeredAt.Format(time.RFC1123Z))
+	msg.WriteString("Content-Type: text/plain; charset=UTF-8\r\n\r\n")
+	msg.WriteString(event.Message)
+	msg.WriteString("\r\n")
+
+	if err := e.send(ctx, auth, target, []byte(msg.String())); err != nil {
+		return fmt.Errorf("failed to send email: %w", err)
+	}
+	return nil
+}
+
+// send 与 smtp.SendMail 相同，连接和会话受ctx的截止时间限制
+func (e *emailNotifier) send(ctx context.Context, auth smtp.Auth, to string, msg []byte) error {
+	conn, err := (&net.Dialer{}).DialContext(ctx, "tcp", net.JoinHostPort(e.config.Host, strconv.Itoa(e.config.Port)))
+	if err != nil {
+		return err
+	}
+	if deadline, ok := ctx.Deadline(); ok {
+		conn.SetDeadline(deadline)
+	}
+
+	client, err := smtp.NewClient(conn, e.config.Host)
+	if err != nil {
+		conn.Close()
+		return err
+	}
+	defer client.Close()
+
+	if ok, _ := client.Extension("STARTTLS"); ok {
+		if err := client.StartTLS(&tls.Config{ServerName: e.config.Host}); err != nil {
+			return err
+		}
+	}
+	if auth != nil {
+		if ok, _ := client.Extension("AUTH"); ok {
+			if err := client.Auth(auth); err != nil {
+				return err
+			}
+		}
+	}
+	if err := client.Mail(e.config.From); err != nil {
+		return err
+	}
+	if err := client.Rcpt(to); err != nil {
+		return err
+	}
+	w, err := client.Data()
+	if err != nil {
+		return err
+	}
+	if _, err := w.Write(msg); err != nil {
+		return err
+	}
+	if err := w.Close(); err != nil {
+		return err
+	}
+	return client.Quit()
+}
+
+// telegramNotifier 通过Telegram Bot API的sendMessage发送告警，target为chat_id
+type telegramNotifier struct {
+	apiURL     string
+	botToken   string
+	httpClient *http.Client
+}
+
+// newTelegramNotifier 创建Telegram通知器，API地址为空时使用官方地址
+func newTelegramNotifier(cfg config.TelegramConfig, client *http.Client) *telegramNotifier {
+	apiURL := strings.TrimRight(cfg.APIURL, "/")
+	if apiURL == "" {
+		apiURL = defaultTelegramAPIURL
+	}
+	return &telegramNotifier{apiURL: apiURL, botToken: cfg.BotToken, httpClient: client}
+}
+
+// Notify 向target聊天发送告警消息
+func (t *telegramNotifier) Notify(ctx context.Context, target string, event *AlertEvent) error {
+	if t.botToken == "" {
+		return errors.New("telegram bot token not configured")
+	}
+
+	body, err := json.Marshal(map[string]string{"chat_id": target, "text": event.Message})
+	if err != nil {
+		return err
+	}
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, t.apiURL+"/bot"+t.botToken+"/sendMessage", bytes.NewReader(body))
+	if err != nil {
+		return err
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	resp, err := t.httpClient.Do(req)
+	if err != nil {
+		// 错误中的地址包含机器人令牌，不返回原始错误
+		return errors.New("failed to send telegram message: request failed")
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode telegram response with status %d: %w", resp.StatusCode, err)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram API error: %s", result.Description)
+	}
+	return nil
+}
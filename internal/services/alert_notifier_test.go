@@ -0,0 +1,180 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"net/textproto"
+	"strings"
+	"testing"
+	"time"
+
+	"chain/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// testAlertEvent 通知器测试使用的告警事件
+func testAlertEvent() *AlertEvent {
+	return &AlertEvent{
+		RuleID: 7, Name: "btc breakout", Asset: "btc", Source: AlertSourceCrypto, Condition: AlertConditionAbove,
+		Threshold: 65000, Price: 65100, PreviousPrice: 64500, TriggeredAt: time.Unix(1767225600, 0).UTC(),
+		Message: "[btc breakout] btc price crossed above 65000 USD: now 65100 (was 64500)",
+	}
+}
+
+func TestWebhookNotifier(t *testing.T) {
+	var received AlertEvent
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, http.MethodPost, r.Method)
+		assert.Equal(t, "application/json", r.Header.Get("Content-Type"))
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&received))
+		if r.URL.Path == "/fail" {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	notifier := &webhookNotifier{httpClient: server.Client()}
+	require.NoError(t, notifier.Notify(context.Background(), server.URL+"/hook", testAlertEvent()))
+	assert.Equal(t, *testAlertEvent(), received)
+
+	err := notifier.Notify(context.Background(), server.URL+"/fail", testAlertEvent())
+	assert.EqualError(t, err, "webhook responded with status: 502")
+}
+
+func TestWebhookNotifierRejectsPrivateAddresses(t *testing.T) {
+	var calls int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		calls++
+	}))
+	defer server.Close()
+
+	// 默认的webhook通知器只连接公网地址
+	notifier := newNotifiers(config.AlertConfig{}, time.Second)[AlertChannelWebhook]
+	err := notifier.Notify(context.Background(), server.URL+"/hook", testAlertEvent())
+	assert.ErrorIs(t, err, ErrNonPublicAddress)
+	assert.Zero(t, calls)
+}
+
+func TestTelegramNotifier(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var body map[string]string
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&body))
+		if r.URL.Path != "/bottest-token/sendMessage" {
+			w.WriteHeader(http.StatusNotFound)
+			w.Write([]byte(`{"ok":false,"description":"Not Found"}`))
+			return
+		}
+		if body["chat_id"] != "12345" {
+			w.WriteHeader(http.StatusBadRequest)
+			w.Write([]byte(`{"ok":false,"description":"Bad Request: chat not found"}`))
+			return
+		}
+		assert.Equal(t, testAlertEvent().Message, body["text"])
+		w.Write([]byte(`{"ok":true,"result":{"message_id":1}}`))
+	}))
+	defer server.Close()
+
+	notifier := newTelegramNotifier(config.TelegramConfig{APIURL: server.URL + "/", BotToken: "test-token"}, server.Client())
+	require.NoError(t, notifier.Notify(context.Background(), "12345", testAlertEvent()))
+
+	err := notifier.Notify(context.Background(), "999", testAlertEvent())
+	assert.EqualError(t, err, "telegram API error: Bad Request: chat not found")
+
+	notifier = newTelegramNotifier(config.TelegramConfig{APIURL: server.URL}, server.Client())
+	err = notifier.Notify(context.Background(), "12345", testAlertEvent())
+	assert.EqualError(t, err, "telegram bot token not configured")
+}
+
+// smtpMessage 模拟SMTP服务器收到的邮件
+type smtpMessage struct {
+	from string
+	to   []string
+	data string
+}
+
+// stubSMTPServer 只实现发送邮件所需命令的SMTP服务器，不支持STARTTLS和认证
+func stubSMTPServer(t *testing.T) (string, int, <-chan smtpMessage) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	t.Cleanup(func() { listener.Close() })
+
+	messages := make(chan smtpMessage, 1)
+	go func() {
+		conn, err := listener.Accept()
+		if err != nil {
+			return
+		}
+		defer conn.Close()
+
+		text := textproto.NewConn(conn)
+		text.PrintfLine("220 localhost ESMTP")
+		var msg smtpMessage
+		for {
+			line, err := text.ReadLine()
+			if err != nil {
+				return
+			}
+			command := strings.ToUpper(line)
+			switch {
+			case strings.HasPrefix(command, "EHLO"), strings.HasPrefix(command, "HELO"):
+				text.PrintfLine("250 localhost")
+			case strings.HasPrefix(command, "MAIL FROM:"):
+				msg.from = strings.Trim(line[len("MAIL FROM:"):], "<>")
+				text.PrintfLine("250 OK")
+			case strings.HasPrefix(command, "RCPT TO:"):
+				msg.to = append(msg.to, strings.Trim(line[len("RCPT TO:"):], "<>"))
+				text.PrintfLine("250 OK")
+			case command == "DATA":
+				text.PrintfLine("354 End data with <CR><LF>.<CR><LF>")
+				lines, err := text.ReadDotLines()
+				if err != nil {
+					return
+				}
+				msg.data = strings.Join(lines, "\n")
+				text.PrintfLine("250 OK")
+				messages <- msg
+			case command == "QUIT":
+				text.PrintfLine("221 Bye")
+				return
+			default:
+				text.PrintfLine("502 Command not implemented")
+			}
+		}
+	}()
+
+	addr := listener.Addr().(*net.TCPAddr)
+	return addr.IP.String(), addr.Port, messages
+}
+
+func TestEmailNotifier(t *testing.T) {
+	host, port, messages := stubSMTPServer(t)
+	notifier := &emailNotifier{config: config.SMTPConfig{Host: host, Port: port, From: "alerts@example.com"}}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	require.NoError(t, notifier.Notify(ctx, "trader@example.com", testAlertEvent()))
+
+	msg := <-messages
+	assert.Equal(t, "alerts@example.com", msg.from)
+	assert.Equal(t, []string{"trader@example.com"}, msg.to)
+	assert.Contains(t, msg.data, "Subject: Price alert: btc breakout")
+	assert.Contains(t, msg.data, "To: trader@example.com")
+	assert.Contains(t, msg.data, testAlertEvent().Message)
+
+	// 非ASCII标题按RFC 2047编码
+	host, port, messages = stubSMTPServer(t)
+	notifier = &emailNotifier{config: config.SMTPConfig{Host: host, Port: port, From: "alerts@example.com"}}
+	event := testAlertEvent()
+	event.Name = "比特币突破"
+	require.NoError(t, notifier.Notify(ctx, "trader@example.com", event))
+	msg = <-messages
+	assert.Contains(t, msg.data, "Subject: =?utf-8?q?Price_alert:_")
+
+	err := (&emailNotifier{}).Notify(ctx, "trader@example.com", testAlertEvent())
+	assert.EqualError(t, err, "SMTP server not configured")
+}
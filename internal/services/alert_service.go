@@ -0,0 +1,541 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math"
+	"net/mail"
+	"net/netip"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+	"unicode"
+
+	"chain/internal/config"
+	"chain/internal/models"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 告警条件
+const (
+	AlertConditionAbove      = "above"       // 价格由下向上穿过阈值
+	AlertConditionBelow      = "below"       // 价格由上向下穿过阈值
+	AlertConditionChange     = "change"      // 时间窗口内涨跌幅的绝对值达到阈值
+	AlertConditionChangeUp   = "change_up"   // 时间窗口内涨幅达到阈值
+	AlertConditionChangeDown = "change_down" // 时间窗口内跌幅达到阈值
+)
+
+// 告警价格来源
+const (
+	AlertSourceCrypto = "crypto" // PriceService的行情价格，资产为符号、名称或币种ID
+	AlertSourceToken  = "token"  // BSCService的DEX价格，资产为BSC代币地址
+)
+
+// 告警服务默认参数
+const (
+	defaultAlertTimeout = 10 * time.Second
+	maxAlertWindow      = 7 * 24 * time.Hour // change类条件的最大时间窗口
+)
+
+// ErrInvalidAlertRule 告警规则无效
+var ErrInvalidAlertRule = errors.New("invalid alert rule")
+
+// 告警规则管理的鉴权错误
+var (
+	ErrAlertUnauthorized  = errors.New("invalid or missing alert admin token")
+	ErrAlertAdminDisabled = errors.New("alert rule management disabled: admin token not configured")
+)
+
+// AlertEvent 告警规则触发时发送给通知渠道的事件
+type AlertEvent struct {
+	RuleID        uint      `json:"rule_id"`
+	Name          string    `json:"name"`
+	Asset         string    `json:"asset"`
+	Source        string    `json:"source"`
+	Condition     string    `json:"condition"`
+	Threshold     float64   `json:"threshold"`
+	Window        int       `json:"window,omitempty"`
+	Price         float64   `json:"price"`                    // 触发时的USD价格
+	PreviousPrice float64   `json:"previous_price"`           // 穿越类为上次评估时的价格，涨跌幅类为窗口起点的价格
+	ChangePercent float64   `json:"change_percent,omitempty"` // 涨跌幅类条件的涨跌幅（百分比）
+	TriggeredAt   time.Time `json:"triggered_at"`
+	Message       string    `json:"message"`
+}
+
+// Subject 返回通知标题
+func (e *AlertEvent) Subject() string {
+	return "Price alert: " + e.Name
+}
+
+// priceSample 评估告警时记录的价格
+type priceSample struct {
+	at    time.Time
+	price float64
+}
+
+// AlertService 价格告警服务，按评估间隔查询规则涉及资产的价格，触发的规则通过对应渠道发送通知
+type AlertService struct {
+	prices    *PriceService
+	tokens    tokenPriceSource // 查询BSC代币价格，未设置BSC服务时为nil
+	store     AlertStore
+	interval  time.Duration // 评估间隔，0表示不评估
+	timeout   time.Duration // 单次通知的超时时间
+	notifiers map[string]Notifier
+
+	adminToken string // 管理规则所需的令牌，为空时不能管理规则
+
+	mu         sync.Mutex
+	samples    map[string][]priceSample // 价格来源:资产 => 按时间升序的价格，用于计算窗口涨跌幅
+	lastPrices map[uint]float64         // 规则ID => 上次评估时的价格，用于判断穿越
+}
+
+// NewAlertService 创建告警服务，使用内存存储
+func NewAlertService(cfg *config.Config, priceService *PriceService) *AlertService {
+	timeout := defaultAlertTimeout
+	if cfg.Alert.Timeout > 0 {
+		timeout = time.Duration(cfg.Alert.Timeout) * time.Second
+	}
+
+	return &AlertService{
+		prices:     priceService,
+		store:      NewMemoryAlertStore(),
+		interval:   time.Duration(cfg.Alert.EvaluateInterval) * time.Second,
+		timeout:    timeout,
+		adminToken: cfg.Alert.AdminToken,
+		notifiers:  newNotifiers(cfg.Alert, timeout),
+		samples:    make(map[string][]priceSample),
+		lastPrices: make(map[uint]float64),
+	}
+}
+
+// SetBSCService 设置查询代币价格的BSC服务，未设置时token来源的规则无法评估
+func (a *AlertService) SetBSCService(bscService *BSCService) {
+	a.tokens = bscService
+}
+
+// SetStore 设置告警规则存储，默认使用内存存储
+func (a *AlertService) SetStore(store AlertStore) {
+	a.store = store
+}
+
+// Authorize 校验管理规则的令牌，未配置令牌时所有请求都被拒绝
+func (a *AlertService) Authorize(token string) error {
+	if a.adminToken == "" {
+		return ErrAlertAdminDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(a.adminToken)) != 1 {
+		return ErrAlertUnauthorized
+	}
+	return nil
+}
+
+// CreateRule 校验并保存新规则
+func (a *AlertService) CreateRule(rule *models.AlertRule) (*models.AlertRule, error) {
+	if err := normalizeAlertRule(rule); err != nil {
+		return nil, err
+	}
+	rule.ID = 0
+	rule.LastTriggeredAt = nil
+	if err := a.store.CreateRule(rule); err != nil {
+		return nil, err
+	}
+	return rule, nil
+}
+
+// UpdateRule 校验并覆盖已有规则，保留创建时间和最近触发时间
+func (a *AlertService) UpdateRule(rule *models.AlertRule) (*models.AlertRule, error) {
+	existing, err := a.store.GetRule(rule.ID)
+	if err != nil {
+		return nil, err
+	}
+	if err := normalizeAlertRule(rule); err != nil {
+		return nil, err
+	}
+	rule.CreatedAt = existing.CreatedAt
+	rule.LastTriggeredAt = existing.LastTriggeredAt
+	if err := a.store.UpdateRule(rule); err != nil {
+		return nil, err
+	}
+
+	a.mu.Lock()
+	delete(a.lastPrices, rule.ID)
+	a.mu.Unlock()
+	return rule, nil
+}
+
+// DeleteRule 删除规则
+func (a *AlertService) DeleteRule(id uint) error {
+	if err := a.store.DeleteRule(id); err != nil {
+		return err
+	}
+
+	a.mu.Lock()
+	delete(a.lastPrices, id)
+	a.mu.Unlock()
+	return nil
+}
+
+// GetRule 返回规则
+func (a *AlertService) GetRule(id uint) (*models.AlertRule, error) {
+	return a.store.GetRule(id)
+}
+
+// ListRules 返回所有规则
+func (a *AlertService) ListRules() ([]*models.AlertRule, error) {
+	return a.store.ListRules()
+}
+
+// normalizeAlertRule 填充默认值并校验规则
+func normalizeAlertRule(rule *models.AlertRule) error {
+	invalid := func(format string, args ...interface{}) error {
+		return fmt.Errorf("%w: %s", ErrInvalidAlertRule, fmt.Sprintf(format, args...))
+	}
+
+	rule.Asset = strings.TrimSpace(rule.Asset)
+	if rule.Asset == "" {
+		return invalid("asset is required")
+	}
+	// 名称和资产会写入邮件标题，不允许换行等控制字符
+	if hasControlChars(rule.Asset) {
+		return invalid("asset must not contain control characters")
+	}
+	rule.Source = strings.ToLower(strings.TrimSpace(rule.Source))
+	switch rule.Source {
+	case "":
+		rule.Source = AlertSourceCrypto
+	case AlertSourceCrypto:
+	case AlertSourceToken:
+		if !common.IsHexAddress(rule.Asset) {
+			return invalid("asset must be a BSC token address for source token")
+		}
+		rule.Asset = common.HexToAddress(rule.Asset).Hex()
+	default:
+		return invalid("unsupported source %s, expected crypto or token", rule.Source)
+	}
+
+	rule.Condition = strings.ToLower(strings.TrimSpace(rule.Condition))
+	switch rule.Condition {
+	case AlertConditionAbove, AlertConditionBelow:
+		rule.Window = 0
+	case AlertConditionChange, AlertConditionChangeUp, AlertConditionChangeDown:
+		if rule.Window <= 0 || time.Duration(rule.Window)*time.Second > maxAlertWindow {
+			return invalid("window must be between 1 and %d seconds for change conditions", int(maxAlertWindow.Seconds()))
+		}
+	default:
+		return invalid("unsupported condition %s, expected above, below, change, change_up or change_down", rule.Condition)
+	}
+	if rule.Threshold <= 0 || math.IsInf(rule.Threshold, 0) || math.IsNaN(rule.Threshold) {
+		return invalid("threshold must be positive")
+	}
+	if rule.Cooldown < 0 {
+		return invalid("cooldown must not be negative")
+	}
+
+	rule.Channel = strings.ToLower(strings.TrimSpace(rule.Channel))
+	rule.Target = strings.TrimSpace(rule.Target)
+	if rule.Target == "" {
+		return invalid("target is required")
+	}
+	switch rule.Channel {
+	case AlertChannelWebhook:
+		u, err := url.Parse(rule.Target)
+		if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Host == "" {
+			return invalid("webhook target must be an http(s) URL")
+		}
+		// 发送时只连接公网地址，这里提前拒绝明显的内网目标
+		host := u.Hostname()
+		if ip, err := netip.ParseAddr(host); strings.EqualFold(host, "localhost") || (err == nil && !isPublicIP(ip)) {
+			return invalid("webhook target must be a public address")
+		}
+	case AlertChannelEmail:
+		address, err := mail.ParseAddress(rule.Target)
+		if err != nil {
+			return invalid("email target must be an email address")
+		}
+		rule.Target = address.Address
+	case AlertChannelTelegram:
+	default:
+		return invalid("unsupported channel %s, expected webhook, email or telegram", rule.Channel)
+	}
+
+	rule.Name = strings.TrimSpace(rule.Name)
+	if hasControlChars(rule.Name) {
+		return invalid("name must not contain control characters")
+	}
+	if rule.Name == "" {
+		rule.Name = fmt.Sprintf("%s %s %s", rule.Asset, rule.Condition, formatAlertNumber(rule.Threshold))
+	}
+	return nil
+}
+
+// hasControlChars 判断字符串是否包含换行、制表符等控制字符
+func hasControlChars(s string) bool {
+	return strings.ContainsFunc(s, unicode.IsControl)
+}
+
+// RunEvaluator 按评估间隔持续评估已启用的规则，直到ctx取消；评估间隔为0时不启动
+func (a *AlertService) RunEvaluator(ctx context.Context) {
+	if a.interval <= 0 {
+		logger.Info("Alert evaluator disabled")
+		return
+	}
+
+	ticker := time.NewTicker(a.interval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := a.Evaluate(ctx); err != nil {
+			logger.Warnf("Failed to evaluate alert rules: %v", err)
+		} else if count > 0 {
+			logger.Infof("Triggered %d alert rules", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Evaluate 查询已启用规则涉及资产的当前价格并评估规则，返回成功发送通知的规则数量
+// 冷却期内的规则不发送通知；通知失败的规则不记录触发时间，下次评估时条件仍满足会重新发送
+func (a *AlertService) Evaluate(ctx context.Context) (int, error) {
+	rules, err := a.store.ListRules()
+	if err != nil {
+		return 0, fmt.Errorf("failed to list alert rules: %w", err)
+	}
+	enabled := rules[:0]
+	for _, rule := range rules {
+		if rule.Enabled {
+			enabled = append(enabled, rule)
+		}
+	}
+	if len(enabled) == 0 {
+		return 0, nil
+	}
+
+	now := time.Now()
+	keys := make(map[uint]string, len(enabled))
+	for _, rule := range enabled {
+		keys[rule.ID] = a.assetKey(rule)
+	}
+	prices, errs := a.currentPrices(ctx, enabled)
+
+	type triggered struct {
+		rule  *models.AlertRule
+		event *AlertEvent
+	}
+	var events []triggered
+	a.mu.Lock()
+	a.recordSamples(prices, enabled, now)
+	for _, rule := range enabled {
+		price, ok := prices[keys[rule.ID]]
+		if !ok {
+			continue
+		}
+		event := a.check(rule, keys[rule.ID], price, now)
+		a.lastPrices[rule.ID] = price
+		if event == nil {
+			continue
+		}
+		if rule.LastTriggeredAt != nil && now.Before(rule.LastTriggeredAt.Add(time.Duration(rule.Cooldown)*time.Second)) {
+			continue
+		}
+		events = append(events, triggered{rule, event})
+	}
+	a.mu.Unlock()
+
+	var count int
+	for _, t := range events {
+		if err := a.notify(ctx, t.rule, t.event); err != nil {
+			errs = append(errs, fmt.Errorf("rule %d: %w", t.rule.ID, err))
+			// 穿越类规则恢复上次的价格，使下次评估时重新判断为穿越
+			if t.rule.Condition == AlertConditionAbove || t.rule.Condition == AlertConditionBelow {
+				a.mu.Lock()
+				a.lastPrices[t.rule.ID] = t.event.PreviousPrice
+				a.mu.Unlock()
+			}
+			continue
+		}
+		if err := a.store.MarkTriggered(t.rule.ID, t.event.TriggeredAt); err != nil {
+			logger.Warnf("Failed to mark alert rule %d triggered: %v", t.rule.ID, err)
+		}
+		count++
+	}
+	return count, errors.Join(errs...)
+}
+
+// assetKey 返回规则资产的价格键，行情资产按解析后的币种ID区分
+func (a *AlertService) assetKey(rule *models.AlertRule) string {
+	if rule.Source == AlertSourceToken {
+		return AlertSourceToken + ":" + strings.ToLower(rule.Asset)
+	}
+	return AlertSourceCrypto + ":" + a.prices.ResolveCoin(rule.Asset).CoinID
+}
+
+// currentPrices 查询规则涉及资产的当前USD价格，以assetKey为键；查询失败的资产不出现在结果中
+func (a *AlertService) currentPrices(ctx context.Context, rules []*models.AlertRule) (map[string]float64, []error) {
+	var (
+		errs   []error
+		coins  []string
+		tokens []string
+		seen   = make(map[string]bool)
+	)
+	for _, rule := range rules {
+		key := rule.Source + ":" + strings.ToLower(rule.Asset)
+		if seen[key] {
+			continue
+		}
+		seen[key] = true
+		if rule.Source == AlertSourceToken {
+			tokens = append(tokens, rule.Asset)
+		} else {
+			coins = append(coins, rule.Asset)
+		}
+	}
+
+	prices := make(map[string]float64)
+	if len(coins) > 0 {
+		result, err := a.prices.GetMultipleCryptoPrices(ctx, coins)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, price := range result {
+			prices[AlertSourceCrypto+":"+price.CoinID] = price.CurrentPrice
+		}
+	}
+
+	if len(tokens) > 0 && a.tokens == nil {
+		errs = append(errs, errors.New("BSC service not configured for token alerts"))
+	} else {
+		for _, token := range tokens {
+			price, err := a.tokens.GetTokenPrice(token, "")
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", token, err))
+				continue
+			}
+			if info := dexPriceInfo(price); info != nil {
+				prices[AlertSourceToken+":"+strings.ToLower(token)] = info.CurrentPrice
+			}
+		}
+	}
+	return prices, errs
+}
+
+// recordSamples 记录本次评估的价格并清理超出所有规则时间窗口的价格，调用方持有锁
+func (a *AlertService) recordSamples(prices map[string]float64, rules []*models.AlertRule, now time.Time) {
+	var window time.Duration
+	for _, rule := range rules {
+		window = max(window, time.Duration(rule.Window)*time.Second)
+	}
+	cutoff := now.Add(-window - a.interval)
+
+	for key, price := range prices {
+		a.samples[key] = append(a.samples[key], priceSample{at: now, price: price})
+	}
+	for key, samples := range a.samples {
+		i := 0
+		for i < len(samples) && samples[i].at.Before(cutoff) {
+			i++
+		}
+		if i == len(samples) {
+			delete(a.samples, key)
+			continue
+		}
+		a.samples[key] = samples[i:]
+	}
+}
+
+// check 评估单个规则，条件满足时返回事件，调用方持有锁
+func (a *AlertService) check(rule *models.AlertRule, key string, price float64, now time.Time) *AlertEvent {
+	event := &AlertEvent{
+		RuleID:      rule.ID,
+		Name:        rule.Name,
+		Asset:       rule.Asset,
+		Source:      rule.Source,
+		Condition:   rule.Condition,
+		Threshold:   rule.Threshold,
+		Window:      rule.Window,
+		Price:       price,
+		TriggeredAt: now,
+	}
+
+	switch rule.Condition {
+	case AlertConditionAbove, AlertConditionBelow:
+		previous, ok := a.lastPrices[rule.ID]
+		if !ok {
+			return nil
+		}
+		crossed := previous < rule.Threshold && price >= rule.Threshold
+		direction := "above"
+		if rule.Condition == AlertConditionBelow {
+			crossed = previous > rule.Threshold && price <= rule.Threshold
+			direction = "below"
+		}
+		if !crossed {
+			return nil
+		}
+		event.PreviousPrice = previous
+		event.Message = fmt.Sprintf("[%s] %s price crossed %s %s USD: now %s (was %s)",
+			rule.Name, rule.Asset, direction, formatAlertNumber(rule.Threshold), formatAlertNumber(price), formatAlertNumber(previous))
+		return event
+	}
+
+	// 以窗口内最早的价格为起点，本次评估之前没有窗口内的价格时不触发
+	window := time.Duration(rule.Window) * time.Second
+	samples := a.samples[key]
+	var base *priceSample
+	for i := range samples {
+		if !samples[i].at.Before(now.Add(-window)) {
+			base = &samples[i]
+			break
+		}
+	}
+	if base == nil || !base.at.Before(now) || base.price <= 0 {
+		return nil
+	}
+	change := (price - base.price) / base.price * 100
+	switch rule.Condition {
+	case AlertConditionChange:
+		if math.Abs(change) < rule.Threshold {
+			return nil
+		}
+	case AlertConditionChangeUp:
+		if change < rule.Threshold {
+			return nil
+		}
+	case AlertConditionChangeDown:
+		if change > -rule.Threshold {
+			return nil
+		}
+	}
+	event.PreviousPrice = base.price
+	event.ChangePercent = change
+	event.Message = fmt.Sprintf("[%s] %s moved %+.2f%% in %s: %s -> %s USD",
+		rule.Name, rule.Asset, change, now.Sub(base.at).Round(time.Second), formatAlertNumber(base.price), formatAlertNumber(price))
+	return event
+}
+
+// notify 通过规则的渠道发送事件
+func (a *AlertService) notify(ctx context.Context, rule *models.AlertRule, event *AlertEvent) error {
+	notifier, ok := a.notifiers[rule.Channel]
+	if !ok {
+		return fmt.Errorf("unsupported channel: %s", rule.Channel)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, a.timeout)
+	defer cancel()
+	return notifier.Notify(ctx, rule.Target, event)
+}
+
+// formatAlertNumber 格式化告警消息中的数值
+func formatAlertNumber(value float64) string {
+	return strconv.FormatFloat(value, 'f', -1, 64)
+}
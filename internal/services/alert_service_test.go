@@ -0,0 +1,220 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"chain/internal/config"
+	"chain/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// recordingNotifier 记录收到的告警，err非nil时发送失败
+type recordingNotifier struct {
+	mu      sync.Mutex
+	targets []string
+	events  []*AlertEvent
+	err     error
+}
+
+func (r *recordingNotifier) Notify(ctx context.Context, target string, event *AlertEvent) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.err != nil {
+		return r.err
+	}
+	r.targets = append(r.targets, target)
+	r.events = append(r.events, event)
+	return nil
+}
+
+// newTestAlertService 创建使用固定价格数据源和记录通知器的告警服务
+func newTestAlertService(provider *stubPriceProvider) (*AlertService, *recordingNotifier) {
+	service := NewAlertService(&config.Config{Alert: config.AlertConfig{EvaluateInterval: 30}}, newPriceServiceWithProviders("", provider))
+	notifier := &recordingNotifier{}
+	service.notifiers = map[string]Notifier{AlertChannelWebhook: notifier, AlertChannelTelegram: notifier}
+	return service, notifier
+}
+
+func TestNormalizeAlertRule(t *testing.T) {
+	rule := &models.AlertRule{Asset: " 0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82 ", Source: "Token", Condition: "CHANGE_UP", Threshold: 5, Window: 3600, Channel: "email", Target: "Trader <trader@example.com>"}
+	require.NoError(t, normalizeAlertRule(rule))
+	assert.Equal(t, CAKEAddress, rule.Asset)
+	assert.Equal(t, AlertSourceToken, rule.Source)
+	assert.Equal(t, AlertConditionChangeUp, rule.Condition)
+	assert.Equal(t, "trader@example.com", rule.Target)
+	assert.Equal(t, CAKEAddress+" change_up 5", rule.Name)
+
+	rule = &models.AlertRule{Asset: "btc", Condition: "above", Threshold: 65000, Window: 60, Channel: "telegram", Target: "12345"}
+	require.NoError(t, normalizeAlertRule(rule))
+	assert.Equal(t, AlertSourceCrypto, rule.Source)
+	assert.Zero(t, rule.Window)
+
+	tests := []struct {
+		rule models.AlertRule
+		err  string
+	}{
+		{models.AlertRule{Condition: "above", Threshold: 1, Channel: "telegram", Target: "1"}, "asset is required"},
+		{models.AlertRule{Asset: "btc", Source: "token", Condition: "above", Threshold: 1, Channel: "telegram", Target: "1"}, "must be a BSC token address"},
+		{models.AlertRule{Asset: "btc", Source: "nasdaq", Condition: "above", Threshold: 1, Channel: "telegram", Target: "1"}, "unsupported source nasdaq"},
+		{models.AlertRule{Asset: "btc", Condition: "equals", Threshold: 1, Channel: "telegram", Target: "1"}, "unsupported condition equals"},
+		{models.AlertRule{Asset: "btc", Condition: "change", Threshold: 1, Channel: "telegram", Target: "1"}, "window must be between"},
+		{models.AlertRule{Asset: "btc", Condition: "above", Channel: "telegram", Target: "1"}, "threshold must be positive"},
+		{models.AlertRule{Asset: "btc", Condition: "above", Threshold: 1, Cooldown: -1, Channel: "telegram", Target: "1"}, "cooldown must not be negative"},
+		{models.AlertRule{Asset: "btc", Condition: "above", Threshold: 1, Channel: "sms", Target: "1"}, "unsupported channel sms"},
+		{models.AlertRule{Asset: "btc", Condition: "above", Threshold: 1, Channel: "webhook", Target: "ftp://example.com"}, "http(s) URL"},
+		{models.AlertRule{Asset: "btc", Condition: "above", Threshold: 1, Channel: "webhook", Target: "http://127.0.0.1:8080/hook"}, "public address"},
+		{models.AlertRule{Asset: "btc", Condition: "above", Threshold: 1, Channel: "webhook", Target: "http://localhost/hook"}, "public address"},
+		{models.AlertRule{Asset: "btc", Condition: "above", Threshold: 1, Channel: "webhook", Target: "http://[::1]/hook"}, "public address"},
+		{models.AlertRule{Name: "btc\r\nBcc: victim@example.com", Asset: "btc", Condition: "above", Threshold: 1, Channel: "telegram", Target: "1"}, "name must not contain control characters"},
+		{models.AlertRule{Asset: "btc\nBcc: victim@example.com", Condition: "above", Threshold: 1, Channel: "telegram", Target: "1"}, "asset must not contain control characters"},
+		{models.AlertRule{Asset: "btc", Condition: "above", Threshold: 1, Channel: "email", Target: "trader"}, "email address"},
+		{models.AlertRule{Asset: "btc", Condition: "above", Threshold: 1, Channel: "telegram"}, "target is required"},
+	}
+	for _, tt := range tests {
+		err := normalizeAlertRule(&tt.rule)
+		assert.ErrorIs(t, err, ErrInvalidAlertRule)
+		assert.ErrorContains(t, err, tt.err)
+	}
+}
+
+func TestAlertServiceRuleCRUD(t *testing.T) {
+	service, _ := newTestAlertService(&stubPriceProvider{name: "primary"})
+
+	rule, err := service.CreateRule(&models.AlertRule{Asset: "btc", Condition: "above", Threshold: 65000, Channel: "telegram", Target: "1", Enabled: true})
+	require.NoError(t, err)
+	assert.Equal(t, uint(1), rule.ID)
+
+	require.NoError(t, service.store.MarkTriggered(rule.ID, time.Now()))
+	updated, err := service.UpdateRule(&models.AlertRule{ID: rule.ID, Asset: "eth", Condition: "below", Threshold: 3000, Channel: "telegram", Target: "2"})
+	require.NoError(t, err)
+	assert.NotNil(t, updated.LastTriggeredAt)
+
+	stored, err := service.GetRule(rule.ID)
+	require.NoError(t, err)
+	assert.Equal(t, "eth", stored.Asset)
+	assert.False(t, stored.Enabled)
+	assert.Equal(t, rule.CreatedAt, stored.CreatedAt)
+
+	_, err = service.UpdateRule(&models.AlertRule{ID: rule.ID, Asset: "eth"})
+	assert.ErrorIs(t, err, ErrInvalidAlertRule)
+	_, err = service.UpdateRule(&models.AlertRule{ID: 99, Asset: "eth", Condition: "below", Threshold: 1, Channel: "telegram", Target: "2"})
+	assert.ErrorIs(t, err, ErrAlertRuleNotFound)
+
+	require.NoError(t, service.DeleteRule(rule.ID))
+	assert.ErrorIs(t, service.DeleteRule(rule.ID), ErrAlertRuleNotFound)
+	rules, err := service.ListRules()
+	require.NoError(t, err)
+	assert.Empty(t, rules)
+}
+
+func TestAlertEvaluatorThresholdCrossing(t *testing.T) {
+	provider := &stubPriceProvider{name: "primary", prices: map[string]float64{"bitcoin": 64000}}
+	service, notifier := newTestAlertService(provider)
+	rule, err := service.CreateRule(&models.AlertRule{Name: "btc breakout", Asset: "bitcoin", Condition: "above", Threshold: 65000, Cooldown: 3600, Channel: "webhook", Target: "https://example.com/hook", Enabled: true})
+	require.NoError(t, err)
+	// 未启用的规则不评估
+	_, err = service.CreateRule(&models.AlertRule{Asset: "bitcoin", Condition: "above", Threshold: 60000, Channel: "telegram", Target: "1"})
+	require.NoError(t, err)
+
+	evaluate := func(price float64) int {
+		provider.prices["bitcoin"] = price
+		count, err := service.Evaluate(context.Background())
+		require.NoError(t, err)
+		return count
+	}
+
+	// 第一次评估只记录价格，之后由下向上穿过阈值时触发
+	assert.Equal(t, 0, evaluate(64000))
+	assert.Equal(t, 0, evaluate(64500))
+	assert.Equal(t, 1, evaluate(65100))
+	require.Len(t, notifier.events, 1)
+	event := notifier.events[0]
+	assert.Equal(t, rule.ID, event.RuleID)
+	assert.Equal(t, 65100.0, event.Price)
+	assert.Equal(t, 64500.0, event.PreviousPrice)
+	assert.Equal(t, "[btc breakout] bitcoin price crossed above 65000 USD: now 65100 (was 64500)", event.Message)
+	assert.Equal(t, "https://example.com/hook", notifier.targets[0])
+
+	stored, err := service.GetRule(rule.ID)
+	require.NoError(t, err)
+	require.NotNil(t, stored.LastTriggeredAt)
+
+	// 保持在阈值之上不重复触发，冷却期内再次穿越不通知
+	assert.Equal(t, 0, evaluate(65200))
+	assert.Equal(t, 0, evaluate(64000))
+	assert.Equal(t, 0, evaluate(65300))
+	assert.Len(t, notifier.events, 1)
+}
+
+func TestAlertEvaluatorWindowChange(t *testing.T) {
+	service, notifier := newTestAlertService(&stubPriceProvider{name: "primary"})
+	tokens := stubTokenPrices{strings.ToLower(CAKEAddress): {TokenSymbol: "Cake", PriceInUSD: "2"}}
+	service.tokens = tokens
+	_, err := service.CreateRule(&models.AlertRule{Asset: CAKEAddress, Source: "token", Condition: "change_up", Threshold: 10, Window: 3600, Channel: "telegram", Target: "1", Enabled: true})
+	require.NoError(t, err)
+	_, err = service.CreateRule(&models.AlertRule{Asset: CAKEAddress, Source: "token", Condition: "change_down", Threshold: 10, Window: 3600, Channel: "telegram", Target: "2", Enabled: true})
+	require.NoError(t, err)
+
+	count, err := service.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+
+	// 相对窗口起点上涨25%，只触发上涨规则
+	tokens[strings.ToLower(CAKEAddress)].PriceInUSD = "2.5"
+	count, err = service.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	require.Len(t, notifier.events, 1)
+	assert.Equal(t, 25.0, notifier.events[0].ChangePercent)
+	assert.Equal(t, 2.0, notifier.events[0].PreviousPrice)
+	assert.Contains(t, notifier.events[0].Message, "moved +25.00%")
+
+	// 超出时间窗口的价格被清理，窗口起点变为2.5后不再触发
+	service.mu.Lock()
+	service.samples[AlertSourceToken+":"+strings.ToLower(CAKEAddress)][0].at = time.Now().Add(-3 * time.Hour)
+	service.mu.Unlock()
+	count, err = service.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 0, count)
+	service.mu.Lock()
+	assert.Len(t, service.samples[AlertSourceToken+":"+strings.ToLower(CAKEAddress)], 2)
+	service.mu.Unlock()
+}
+
+func TestAlertEvaluatorRetriesFailedNotification(t *testing.T) {
+	provider := &stubPriceProvider{name: "primary", prices: map[string]float64{"bitcoin": 66000}}
+	service, notifier := newTestAlertService(provider)
+	rule, err := service.CreateRule(&models.AlertRule{Asset: "bitcoin", Condition: "below", Threshold: 65000, Cooldown: 3600, Channel: "telegram", Target: "1", Enabled: true})
+	require.NoError(t, err)
+	_, err = service.Evaluate(context.Background())
+	require.NoError(t, err)
+
+	notifier.err = errors.New("bot was blocked by the user")
+	provider.prices["bitcoin"] = 64000
+	count, err := service.Evaluate(context.Background())
+	assert.Equal(t, 0, count)
+	assert.EqualError(t, err, "rule 1: bot was blocked by the user")
+	stored, err := service.GetRule(rule.ID)
+	require.NoError(t, err)
+	assert.Nil(t, stored.LastTriggeredAt)
+
+	// 通知恢复后重新发送
+	notifier.err = nil
+	count, err = service.Evaluate(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	assert.Equal(t, 66000.0, notifier.events[0].PreviousPrice)
+
+	// 没有BSC服务时代币规则无法评估，其他规则照常评估
+	_, err = service.CreateRule(&models.AlertRule{Asset: CAKEAddress, Source: "token", Condition: "above", Threshold: 1, Channel: "telegram", Target: "1", Enabled: true})
+	require.NoError(t, err)
+	_, err = service.Evaluate(context.Background())
+	assert.ErrorContains(t, err, "BSC service not configured for token alerts")
+}
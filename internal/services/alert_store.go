@@ -0,0 +1,190 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"chain/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ErrAlertRuleNotFound 告警规则不存在
+var ErrAlertRuleNotFound = errors.New("alert rule not found")
+
+// AlertStore 价格告警规则存储
+type AlertStore interface {
+	// CreateRule 保存新规则并设置其ID
+	CreateRule(rule *models.AlertRule) error
+	// UpdateRule 覆盖已有规则，不存在时返回 ErrAlertRuleNotFound
+	UpdateRule(rule *models.AlertRule) error
+	// DeleteRule 删除规则，不存在时返回 ErrAlertRuleNotFound
+	DeleteRule(id uint) error
+	// GetRule 返回规则，不存在时返回 ErrAlertRuleNotFound
+	GetRule(id uint) (*models.AlertRule, error)
+	// ListRules 返回所有规则，按ID升序
+	ListRules() ([]*models.AlertRule, error)
+	// MarkTriggered 记录规则最近一次触发的时间
+	MarkTriggered(id uint, at time.Time) error
+}
+
+// memoryAlertStore 进程内的告警规则存储，服务重启后规则丢失
+type memoryAlertStore struct {
+	mu     sync.RWMutex
+	nextID uint
+	rules  map[uint]*models.AlertRule
+}
+
+// NewMemoryAlertStore 创建内存告警规则存储
+func NewMemoryAlertStore() AlertStore {
+	return &memoryAlertStore{nextID: 1, rules: make(map[uint]*models.AlertRule)}
+}
+
+// CreateRule 保存新规则
+func (m *memoryAlertStore) CreateRule(rule *models.AlertRule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	rule.ID = m.nextID
+	rule.CreatedAt, rule.UpdatedAt = now, now
+	m.nextID++
+
+	stored := *rule
+	m.rules[rule.ID] = &stored
+	return nil
+}
+
+// UpdateRule 覆盖已有规则
+func (m *memoryAlertStore) UpdateRule(rule *models.AlertRule) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.rules[rule.ID]; !ok {
+		return ErrAlertRuleNotFound
+	}
+	rule.UpdatedAt = time.Now()
+	stored := *rule
+	m.rules[rule.ID] = &stored
+	return nil
+}
+
+// DeleteRule 删除规则
+func (m *memoryAlertStore) DeleteRule(id uint) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, ok := m.rules[id]; !ok {
+		return ErrAlertRuleNotFound
+	}
+	delete(m.rules, id)
+	return nil
+}
+
+// GetRule 返回规则
+func (m *memoryAlertStore) GetRule(id uint) (*models.AlertRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rule, ok := m.rules[id]
+	if !ok {
+		return nil, ErrAlertRuleNotFound
+	}
+	copied := *rule
+	return &copied, nil
+}
+
+// ListRules 返回所有规则
+func (m *memoryAlertStore) ListRules() ([]*models.AlertRule, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	rules := make([]*models.AlertRule, 0, len(m.rules))
+	for _, rule := range m.rules {
+		copied := *rule
+		rules = append(rules, &copied)
+	}
+	sort.Slice(rules, func(i, j int) bool {
+		return rules[i].ID < rules[j].ID
+	})
+	return rules, nil
+}
+
+// MarkTriggered 记录规则最近一次触发的时间
+func (m *memoryAlertStore) MarkTriggered(id uint, at time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	rule, ok := m.rules[id]
+	if !ok {
+		return ErrAlertRuleNotFound
+	}
+	rule.LastTriggeredAt = &at
+	return nil
+}
+
+// dbAlertStore 基于数据库alert_rules表的告警规则存储
+type dbAlertStore struct {
+	db *gorm.DB
+}
+
+// NewDBAlertStore 创建数据库告警规则存储，需要已迁移 models.AlertRule
+func NewDBAlertStore(db *gorm.DB) AlertStore {
+	return &dbAlertStore{db: db}
+}
+
+// CreateRule 保存新规则
+func (d *dbAlertStore) CreateRule(rule *models.AlertRule) error {
+	return d.db.Create(rule).Error
+}
+
+// UpdateRule 覆盖已有规则
+func (d *dbAlertStore) UpdateRule(rule *models.AlertRule) error {
+	result := d.db.Model(&models.AlertRule{}).Where("id = ?", rule.ID).Select("*").Omit("id", "created_at").Updates(rule)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAlertRuleNotFound
+	}
+	return nil
+}
+
+// DeleteRule 删除规则
+func (d *dbAlertStore) DeleteRule(id uint) error {
+	result := d.db.Delete(&models.AlertRule{}, id)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected == 0 {
+		return ErrAlertRuleNotFound
+	}
+	return nil
+}
+
+// GetRule 返回规则
+func (d *dbAlertStore) GetRule(id uint) (*models.AlertRule, error) {
+	var rule models.AlertRule
+	err := d.db.First(&rule, id).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, ErrAlertRuleNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &rule, nil
+}
+
+// ListRules 返回所有规则
+func (d *dbAlertStore) ListRules() ([]*models.AlertRule, error) {
+	var rules []*models.AlertRule
+	err := d.db.Order("id ASC").Find(&rules).Error
+	return rules, err
+}
+
+// MarkTriggered 记录规则最近一次触发的时间
+func (d *dbAlertStore) MarkTriggered(id uint, at time.Time) error {
+	return d.db.Model(&models.AlertRule{}).Where("id = ?", id).Update("last_triggered_at", at).Error
+}
@@ -0,0 +1,39 @@
+package services
+
+import (
+	"context"
+
+	"chain/pkg/logger"
+)
+
+// BackgroundJobs 服务的后台任务：交易对索引、TWAP采样、K线聚合、代币列表导入、币种列表刷新、价格历史记录和告警评估
+// 由服务器在启动时统一启动，停止时取消ctx，注册路由和创建服务都不会启动后台任务
+type BackgroundJobs struct {
+	bsc    *BSCService
+	prices *PriceService
+	alerts *AlertService
+}
+
+// NewBackgroundJobs 创建后台任务
+func NewBackgroundJobs(bscService *BSCService, priceService *PriceService, alertService *AlertService) *BackgroundJobs {
+	return &BackgroundJobs{
+		bsc:    bscService,
+		prices: priceService,
+		alerts: alertService,
+	}
+}
+
+// Start 启动所有后台任务，ctx取消时停止
+func (j *BackgroundJobs) Start(ctx context.Context) {
+	go j.bsc.RunPairIndexer(ctx)
+	go j.bsc.RunTWAPSampler(ctx)
+	go j.bsc.RunCandleIndexer(ctx)
+	go func() {
+		if err := j.bsc.ImportTokenLists(ctx); err != nil {
+			logger.Warnf("Failed to import token lists: %v", err)
+		}
+	}()
+	go j.prices.RunCoinListRefresher(ctx)
+	go j.prices.RunPriceHistoryRecorder(ctx)
+	go j.alerts.RunEvaluator(ctx)
+}
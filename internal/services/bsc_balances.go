@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// GetNativeBalances 批量查询账户的BNB余额（wei），结果与账户顺序一致
+func (s *BSCService) GetNativeBalances(ctx context.Context, accounts []common.Address) ([]*big.Int, error) {
+	balances, err := s.calls.BalancesAt(ctx, accounts)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BNB balances: %w", err)
+	}
+	return balances, nil
+}
+
+// GetTokenBalances 并发查询账户持有的代币余额（最小单位），并发的balanceOf调用被合并为批量请求
+// 返回结果与代币顺序一致，查询失败的代币余额为nil并返回对应的错误
+func (s *BSCService) GetTokenBalances(account common.Address, tokens []common.Address) ([]*big.Int, []error) {
+	balances := make([]*big.Int, len(tokens))
+	errs := make([]error, len(tokens))
+
+	var wg sync.WaitGroup
+	for i, token := range tokens {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			output, err := s.callContract(erc20ABI, token, "balanceOf", account)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to get balance of %s: %w", token.Hex(), err)
+				return
+			}
+			balances[i] = output[0].(*big.Int)
+		}()
+	}
+	wg.Wait()
+
+	return balances, errs
+}
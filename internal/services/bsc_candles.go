@@ -0,0 +1,607 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"chain/internal/models"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// K线相关参数
+const (
+	defaultCandleBackfillBlocks = 28800          // 约24小时的区块数
+	maxCandles                  = 1000           // 单次查询返回的最大K线数量
+	maxCandleTokens             = 500            // 按需聚合的代币数量上限，不含配置的代币
+	candleTokenIdleTTL          = 24 * time.Hour // 按需聚合的代币超过该时间没有被查询时停止聚合
+
+	// DefaultCandleCount 未指定起始时间时返回的K线数量
+	DefaultCandleCount = 100
+)
+
+var (
+	// ErrInvalidCandleQuery K线查询参数无效
+	ErrInvalidCandleQuery = errors.New("invalid candle query")
+	// ErrCandleTokenLimit 按需聚合K线的代币数量已达上限，无法加入新的代币
+	ErrCandleTokenLimit = errors.New("too many tokens with candles")
+)
+
+// candleIntervals 支持的K线周期，按从小到大排列
+var candleIntervals = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"1m", time.Minute},
+	{"5m", 5 * time.Minute},
+	{"1h", time.Hour},
+	{"1d", 24 * time.Hour},
+}
+
+// candleStablecoins 聚合K线时按1 USD计价的稳定币
+var candleStablecoins = []string{USDTAddress, BUSDAddress, USDCAddress}
+
+// CandleInterval 返回K线周期对应的时长
+func CandleInterval(interval string) (time.Duration, bool) {
+	for _, c := range candleIntervals {
+		if c.name == interval {
+			return c.duration, true
+		}
+	}
+	return 0, false
+}
+
+// candlePair 聚合K线使用的交易对
+type candlePair struct {
+	quote         common.Address // 报价代币，WBNB或稳定币
+	quoteDecimals uint8
+	stable        bool // 报价代币是否为稳定币
+	isToken0      bool // 代币是否为token0
+}
+
+// ohlc 一组开高低收价格
+type ohlc struct {
+	open, high, low, close *big.Rat
+}
+
+// add 按成交顺序加入一个价格
+func (o *ohlc) add(price *big.Rat) {
+	if o.open == nil {
+		o.open, o.high, o.low = price, price, price
+	}
+	if price.Cmp(o.high) > 0 {
+		o.high = price
+	}
+	if price.Cmp(o.low) < 0 {
+		o.low = price
+	}
+	o.close = price
+}
+
+// prepend 合并更早的开高低收价格，解析失败时保持不变
+func (o *ohlc) prepend(open, high, low string) {
+	openRat, ok1 := new(big.Rat).SetString(open)
+	highRat, ok2 := new(big.Rat).SetString(high)
+	lowRat, ok3 := new(big.Rat).SetString(low)
+	if !ok1 || !ok2 || !ok3 {
+		return
+	}
+	o.open = openRat
+	if highRat.Cmp(o.high) > 0 {
+		o.high = highRat
+	}
+	if lowRat.Cmp(o.low) < 0 {
+		o.low = lowRat
+	}
+}
+
+// candleSwap 一笔按USD和BNB计价的成交
+type candleSwap struct {
+	block    uint64
+	time     time.Time
+	priceUSD *big.Rat
+	priceBNB *big.Rat
+	volume   *big.Rat // 代币成交数量
+}
+
+// candleBuilder 聚合中的K线
+type candleBuilder struct {
+	openTime  time.Time
+	usd       ohlc
+	bnb       ohlc
+	volume    *big.Rat // 代币成交数量
+	volumeUSD *big.Rat
+	trades    int
+	lastBlock uint64
+}
+
+// add 按成交顺序加入一笔成交
+func (b *candleBuilder) add(swap candleSwap) {
+	b.usd.add(swap.priceUSD)
+	b.bnb.add(swap.priceBNB)
+	b.volume.Add(b.volume, swap.volume)
+	b.volumeUSD.Add(b.volumeUSD, new(big.Rat).Mul(swap.volume, swap.priceUSD))
+	b.trades++
+	b.lastBlock = swap.block
+}
+
+// merge 合并已保存的同一根K线，调用方保证已保存的成交都早于聚合中的成交
+func (b *candleBuilder) merge(stored *models.PriceCandle) {
+	b.usd.prepend(stored.OpenUSD, stored.HighUSD, stored.LowUSD)
+	b.bnb.prepend(stored.OpenBNB, stored.HighBNB, stored.LowBNB)
+	if volume, ok := new(big.Rat).SetString(stored.Volume); ok {
+		b.volume.Add(b.volume, volume)
+	}
+	if volumeUSD, ok := new(big.Rat).SetString(stored.VolumeUSD); ok {
+		b.volumeUSD.Add(b.volumeUSD, volumeUSD)
+	}
+	b.trades += stored.Trades
+}
+
+// candleAggregator 将代币交易对的Swap事件聚合为各周期的K线
+type candleAggregator struct {
+	token     common.Address
+	decimals  uint8
+	pairs     map[common.Address]*candlePair
+	reference common.Address // 跟踪BNB的USD价格的WBNB/USDT交易对
+	wbnbIs0   bool           // WBNB是否为参考交易对的token0
+	wbnbDec   uint8
+	usdtDec   uint8
+	bnbUSD    *big.Rat
+	swap      abi.Event
+	swaps     []candleSwap // 尚未保存的成交，按区块顺序排列
+}
+
+// SetCandleStore 设置K线存储，默认使用内存存储
+func (s *BSCService) SetCandleStore(store CandleStore) {
+	s.candles = store
+}
+
+// GetTokenCandles 返回已聚合的代币在[from, to]内的K线，只读取K线存储，没有成交的周期不返回K线
+// 查询的代币由索引器按需聚合，未聚合过的代币从最新区块回溯candleBackfill个区块开始，此前返回空列表
+func (s *BSCService) GetTokenCandles(token, interval string, from, to time.Time) ([]*models.PriceCandle, error) {
+	if !common.IsHexAddress(token) {
+		return nil, fmt.Errorf("%w: invalid token address: %s", ErrInvalidCandleQuery, token)
+	}
+	duration, ok := CandleInterval(interval)
+	if !ok {
+		return nil, fmt.Errorf("%w: unsupported interval %s, expected 1m, 5m, 1h or 1d", ErrInvalidCandleQuery, interval)
+	}
+	if to.Before(from) {
+		return nil, fmt.Errorf("%w: from must not be after to", ErrInvalidCandleQuery)
+	}
+	from = from.Truncate(duration)
+	if count := to.Sub(from)/duration + 1; count > maxCandles {
+		return nil, fmt.Errorf("%w: time range covers %d candles, at most %d allowed", ErrInvalidCandleQuery, count, maxCandles)
+	}
+
+	if err := s.touchCandleToken(common.HexToAddress(token)); err != nil {
+		return nil, err
+	}
+	return s.candles.GetCandles(token, interval, from, to)
+}
+
+// touchCandleToken 记录代币的查询时间，未按需聚合的代币加入索引器，配置的代币不变
+// 超过 candleTokenIdleTTL 没有被查询的代币停止聚合；按需聚合的代币达到 maxCandleTokens 时返回 ErrCandleTokenLimit
+func (s *BSCService) touchCandleToken(token common.Address) error {
+	if slices.Contains(s.candleTokens, token) {
+		return nil
+	}
+
+	now := time.Now()
+	s.candleQueueMu.Lock()
+	defer s.candleQueueMu.Unlock()
+	if _, ok := s.candleQueried[token]; !ok {
+		s.evictIdleCandleTokens(now)
+		if len(s.candleQueried) >= maxCandleTokens {
+			return fmt.Errorf("%w: at most %d tokens are indexed on demand", ErrCandleTokenLimit, maxCandleTokens)
+		}
+	}
+	s.candleQueried[token] = now
+	return nil
+}
+
+// evictIdleCandleTokens 移除超过 candleTokenIdleTTL 没有被查询的按需聚合代币，调用方持有candleQueueMu
+// 已保存的K线和聚合进度保留，代币再次被查询时从上次聚合的区块继续
+func (s *BSCService) evictIdleCandleTokens(now time.Time) {
+	for token, queried := range s.candleQueried {
+		if now.Sub(queried) > candleTokenIdleTTL {
+			delete(s.candleQueried, token)
+		}
+	}
+}
+
+// RunCandleIndexer 按同步间隔持续聚合配置的代币和近期查询过的代币的K线，直到ctx取消；同步间隔为0时不启动
+func (s *BSCService) RunCandleIndexer(ctx context.Context) {
+	if s.candleSyncInterval <= 0 {
+		logger.Info("Candle indexer disabled")
+		return
+	}
+
+	ticker := time.NewTicker(s.candleSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := s.SyncCandles(ctx); err != nil {
+			logger.Warnf("Failed to sync candles: %v", err)
+		} else if count > 0 {
+			logger.Debugf("Aggregated %d swaps into candles", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncCandles 聚合配置的代币和 candleTokenIdleTTL 内查询过的代币到最新区块，返回处理的Swap数量
+// 单个代币聚合失败不影响其他代币，从未成功聚合过的按需代币聚合一次后移除
+func (s *BSCService) SyncCandles(ctx context.Context) (int, error) {
+	seen := make(map[common.Address]bool)
+	var tokens []common.Address
+	for _, token := range s.candleTokens {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+		}
+	}
+	s.candleQueueMu.Lock()
+	s.evictIdleCandleTokens(time.Now())
+	onDemand := make([]common.Address, 0, len(s.candleQueried))
+	for token := range s.candleQueried {
+		if !seen[token] {
+			seen[token] = true
+			tokens = append(tokens, token)
+			onDemand = append(onDemand, token)
+		}
+	}
+	s.candleQueueMu.Unlock()
+
+	total := 0
+	var errs []error
+	for _, token := range tokens {
+		count, err := s.SyncTokenCandles(ctx, token.Hex())
+		total += count
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to sync candles of %s: %w", token.Hex(), err))
+			if ctx.Err() == nil && slices.Contains(onDemand, token) {
+				s.dropUnsyncedCandleToken(token)
+			}
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// dropUnsyncedCandleToken 移除从未成功聚合过的按需代币，如没有交易对的代币
+func (s *BSCService) dropUnsyncedCandleToken(token common.Address) {
+	_, synced, err := s.candles.GetSyncedBlock(token.Hex())
+	if err != nil || synced {
+		return
+	}
+	s.candleQueueMu.Lock()
+	delete(s.candleQueried, token)
+	s.candleQueueMu.Unlock()
+}
+
+// SyncTokenCandles 从代币上次聚合的区块开始，按logBlockRange分段将代币主要交易对的Swap事件聚合为K线
+// 每段完成后在同一事务中保存K线和进度，返回处理的Swap数量；同一代币的聚合串行执行
+func (s *BSCService) SyncTokenCandles(ctx context.Context, token string) (int, error) {
+	lock, _ := s.candleLocks.LoadOrStore(common.HexToAddress(token), &sync.Mutex{})
+	lock.(*sync.Mutex).Lock()
+	defer lock.(*sync.Mutex).Unlock()
+
+	latest, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	head := latest.Number.Uint64()
+
+	synced, ok, err := s.candles.GetSyncedBlock(token)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get sync state: %w", err)
+	}
+	from := synced + 1
+	if !ok {
+		from = 0
+		if head > s.candleBackfill {
+			from = head - s.candleBackfill
+		}
+	}
+	if from > head {
+		return 0, nil
+	}
+
+	aggregator, err := s.newCandleAggregator(common.HexToAddress(token))
+	if err != nil {
+		return 0, err
+	}
+	addresses := []common.Address{aggregator.reference}
+	for pair := range aggregator.pairs {
+		if pair != aggregator.reference {
+			addresses = append(addresses, pair)
+		}
+	}
+
+	count := 0
+	for start := from; start <= head; start += s.logBlockRange {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		end := start + s.logBlockRange - 1
+		if end > head {
+			end = head
+		}
+
+		logs, err := s.filterLogs(ethereum.FilterQuery{
+			Addresses: addresses,
+			Topics:    [][]common.Hash{{aggregator.swap.ID}},
+		}, start, end)
+		if err != nil {
+			return count, err
+		}
+		if len(logs) > 0 {
+			blockTime, err := s.blockTimeEstimator(ctx, start, end)
+			if err != nil {
+				return count, err
+			}
+			if err := aggregator.addSwaps(logs, blockTime); err != nil {
+				return count, err
+			}
+		}
+		saved, err := s.saveCandles(aggregator, end)
+		if err != nil {
+			return count, err
+		}
+		count += saved
+	}
+	return count, nil
+}
+
+// newCandleAggregator 查找代币与WBNB和稳定币的交易对，并以当前BNB价格作为参考交易对出现Swap之前的BNB的USD价格
+func (s *BSCService) newCandleAggregator(token common.Address) (*candleAggregator, error) {
+	parsedABI, err := parseABI(pairABI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pair ABI: %w", err)
+	}
+
+	decimals, err := s.getTokenDecimals(token.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token decimals: %w", err)
+	}
+	wbnb, usdt := common.HexToAddress(WBNBAddress), common.HexToAddress(USDTAddress)
+	wbnbDec, err := s.getTokenDecimals(WBNBAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get WBNB decimals: %w", err)
+	}
+	usdtDec, err := s.getTokenDecimals(USDTAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get USDT decimals: %w", err)
+	}
+
+	reference, err := s.getLiquidityPool(WBNBAddress, USDTAddress)
+	if err != nil {
+		return nil, err
+	}
+	if common.HexToAddress(reference) == (common.Address{}) {
+		return nil, fmt.Errorf("WBNB/USDT pair not found")
+	}
+	bnbPrice, err := s.getBNBPriceInUSD()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get BNB price: %w", err)
+	}
+
+	// 并发查找代币与各报价代币的交易对，查询由批量读取合并为一次请求
+	quotes := append([]string{WBNBAddress}, candleStablecoins...)
+	found := make([]string, len(quotes))
+	errs := make([]error, len(quotes))
+	var wg sync.WaitGroup
+	for i, quote := range quotes {
+		if common.HexToAddress(quote) == token {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found[i], errs[i] = s.getLiquidityPool(token.Hex(), quote)
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	pairs := make(map[common.Address]*candlePair)
+	for i, quote := range quotes {
+		pair := common.HexToAddress(found[i])
+		if pair == (common.Address{}) {
+			continue
+		}
+		quoteAddr := common.HexToAddress(quote)
+		quoteDecimals, err := s.getTokenDecimals(quote)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get decimals of %s: %w", quote, err)
+		}
+		token0, _ := sortTokens(token, quoteAddr)
+		pairs[pair] = &candlePair{
+			quote:         quoteAddr,
+			quoteDecimals: quoteDecimals,
+			stable:        i > 0,
+			isToken0:      token0 == token,
+		}
+	}
+	if len(pairs) == 0 {
+		return nil, fmt.Errorf("no WBNB or stablecoin pair found for %s", token.Hex())
+	}
+
+	token0, _ := sortTokens(wbnb, usdt)
+	return &candleAggregator{
+		token:     token,
+		decimals:  decimals,
+		pairs:     pairs,
+		reference: common.HexToAddress(reference),
+		wbnbIs0:   token0 == wbnb,
+		wbnbDec:   wbnbDec,
+		usdtDec:   usdtDec,
+		bnbUSD:    new(big.Rat).SetFrac(bnbPrice, pow10(usdtDec)),
+		swap:      parsedABI.Events["Swap"],
+	}, nil
+}
+
+// addSwaps 按区块顺序处理Swap事件，参考交易对的Swap更新BNB的USD价格
+func (a *candleAggregator) addSwaps(logs []types.Log, blockTime func(uint64) time.Time) error {
+	sort.SliceStable(logs, func(i, j int) bool {
+		if logs[i].BlockNumber != logs[j].BlockNumber {
+			return logs[i].BlockNumber < logs[j].BlockNumber
+		}
+		return logs[i].Index < logs[j].Index
+	})
+
+	for _, log := range logs {
+		if log.Removed {
+			continue
+		}
+		values, err := a.swap.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil {
+			return fmt.Errorf("failed to unpack Swap event: %w", err)
+		}
+
+		if log.Address == a.reference {
+			wbnbAmount, usdtAmount := swapAmounts(values, a.wbnbIs0)
+			if wbnbAmount.Sign() > 0 && usdtAmount.Sign() > 0 {
+				a.bnbUSD = unitPrice(usdtAmount, a.usdtDec, wbnbAmount, a.wbnbDec)
+			}
+		}
+
+		pair, ok := a.pairs[log.Address]
+		if !ok {
+			continue
+		}
+		tokenAmount, quoteAmount := swapAmounts(values, pair.isToken0)
+		if tokenAmount.Sign() == 0 || quoteAmount.Sign() == 0 {
+			continue
+		}
+
+		price := unitPrice(quoteAmount, pair.quoteDecimals, tokenAmount, a.decimals)
+		priceUSD, priceBNB := new(big.Rat).Mul(price, a.bnbUSD), price
+		if pair.stable {
+			priceUSD, priceBNB = price, new(big.Rat).Quo(price, a.bnbUSD)
+		}
+		a.swaps = append(a.swaps, candleSwap{
+			block:    log.BlockNumber,
+			time:     blockTime(log.BlockNumber),
+			priceUSD: priceUSD,
+			priceBNB: priceBNB,
+			volume:   new(big.Rat).SetFrac(tokenAmount, pow10(a.decimals)),
+		})
+	}
+	return nil
+}
+
+// saveCandles 将尚未保存的成交计入各周期的K线，与已保存的K线合并后和已聚合到的区块一起保存，返回保存的成交数量
+// 已保存的K线记录了最后计入的区块，重复处理的区块中的成交会被跳过，不会重复计入成交量和成交笔数
+func (s *BSCService) saveCandles(a *candleAggregator, syncedBlock uint64) (int, error) {
+	var candles []*models.PriceCandle
+	for _, interval := range candleIntervals {
+		if len(a.swaps) == 0 {
+			break
+		}
+
+		var openTimes []time.Time
+		buckets := make(map[int64][]candleSwap)
+		for _, swap := range a.swaps {
+			openTime := swap.time.Truncate(interval.duration)
+			if _, ok := buckets[openTime.Unix()]; !ok {
+				openTimes = append(openTimes, openTime)
+			}
+			buckets[openTime.Unix()] = append(buckets[openTime.Unix()], swap)
+		}
+		sort.Slice(openTimes, func(i, j int) bool { return openTimes[i].Before(openTimes[j]) })
+
+		stored, err := s.candles.GetCandles(a.token.Hex(), interval.name, openTimes[0], openTimes[len(openTimes)-1])
+		if err != nil {
+			return 0, fmt.Errorf("failed to get candles: %w", err)
+		}
+		storedByTime := make(map[int64]*models.PriceCandle, len(stored))
+		for _, candle := range stored {
+			storedByTime[candle.OpenTime.Unix()] = candle
+		}
+
+		for _, openTime := range openTimes {
+			builder := &candleBuilder{openTime: openTime, volume: new(big.Rat), volumeUSD: new(big.Rat)}
+			storedCandle := storedByTime[openTime.Unix()]
+			for _, swap := range buckets[openTime.Unix()] {
+				if storedCandle != nil && swap.block <= storedCandle.LastBlock {
+					continue
+				}
+				builder.add(swap)
+			}
+			if builder.trades == 0 {
+				continue
+			}
+			if storedCandle != nil {
+				builder.merge(storedCandle)
+			}
+			candles = append(candles, a.candle(interval.name, builder, s.chainID.Uint64()))
+		}
+	}
+
+	count := len(a.swaps)
+	if err := s.candles.SaveCandles(a.token.Hex(), candles, syncedBlock); err != nil {
+		return 0, fmt.Errorf("failed to save candles: %w", err)
+	}
+	a.swaps = nil
+	return count, nil
+}
+
+// candle 将聚合中的K线转换为K线模型
+func (a *candleAggregator) candle(interval string, builder *candleBuilder, chainID uint64) *models.PriceCandle {
+	return &models.PriceCandle{
+		TokenAddress: a.token.Hex(),
+		Interval:     interval,
+		OpenTime:     builder.openTime,
+		OpenUSD:      formatRat(builder.usd.open, 18),
+		HighUSD:      formatRat(builder.usd.high, 18),
+		LowUSD:       formatRat(builder.usd.low, 18),
+		CloseUSD:     formatRat(builder.usd.close, 18),
+		OpenBNB:      formatRat(builder.bnb.open, 18),
+		HighBNB:      formatRat(builder.bnb.high, 18),
+		LowBNB:       formatRat(builder.bnb.low, 18),
+		CloseBNB:     formatRat(builder.bnb.close, 18),
+		Volume:       formatRat(builder.volume, int(a.decimals)),
+		VolumeUSD:    formatRat(builder.volumeUSD, 18),
+		Trades:       builder.trades,
+		LastBlock:    builder.lastBlock,
+		ChainID:      chainID,
+	}
+}
+
+// swapAmounts 返回Swap事件中代币一侧和另一侧的成交数量（输入与输出之和）
+func swapAmounts(values []interface{}, isToken0 bool) (*big.Int, *big.Int) {
+	// amount0In, amount1In, amount0Out, amount1Out
+	amount0 := new(big.Int).Add(values[0].(*big.Int), values[2].(*big.Int))
+	amount1 := new(big.Int).Add(values[1].(*big.Int), values[3].(*big.Int))
+	if isToken0 {
+		return amount0, amount1
+	}
+	return amount1, amount0
+}
+
+// unitPrice 计算以最小单位给出的成交数量对应的单价：1个完整代币可兑换的报价代币数量
+func unitPrice(quoteAmount *big.Int, quoteDecimals uint8, tokenAmount *big.Int, tokenDecimals uint8) *big.Rat {
+	return new(big.Rat).SetFrac(
+		new(big.Int).Mul(quoteAmount, pow10(tokenDecimals)),
+		new(big.Int).Mul(tokenAmount, pow10(quoteDecimals)),
+	)
+}
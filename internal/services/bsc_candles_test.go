@@ -0,0 +1,222 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"chain/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// addTokenSwapLog 添加一条卖出amountIn个tokenIn、得到amountOut个另一代币的Swap事件，数量以完整代币给出
+func addTokenSwapLog(chain *fakeChain, pair common.Address, age time.Duration, tokenIn common.Address, amountIn, amountOut int64) {
+	p := chain.pairs[pair]
+	tokenOut := p.token0
+	if tokenIn == p.token0 {
+		tokenOut = p.token1
+	}
+	in := new(big.Int).Mul(big.NewInt(amountIn), pow10(chain.tokens[tokenIn].decimals))
+	out := new(big.Int).Mul(big.NewInt(amountOut), pow10(chain.tokens[tokenOut].decimals))
+	zero := big.NewInt(0)
+	if tokenIn == p.token0 {
+		chain.addSwapLog(pair, age, in, zero, zero, out)
+	} else {
+		chain.addSwapLog(pair, age, zero, in, out, zero)
+	}
+}
+
+func TestGetTokenCandles(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	chain.headTime = uint64(time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC).Unix())
+	cake, wbnb := tokens["CAKE"], tokens["WBNB"]
+	cakePair, _ := chain.findPair(cake, wbnb)
+	bnbPair, _ := chain.findPair(wbnb, tokens["USDT"])
+
+	addTokenSwapLog(chain, bnbPair, 20*time.Minute, wbnb, 1, 300)     // 11:40 BNB = 300 USD
+	addTokenSwapLog(chain, cakePair, 10*time.Minute, cake, 1_000, 10) // 11:50 0.01 BNB
+	addTokenSwapLog(chain, cakePair, 9*time.Minute, wbnb, 24, 2_000)  // 11:51 0.012 BNB
+	addTokenSwapLog(chain, bnbPair, 5*time.Minute, wbnb, 1, 250)      // 11:55 BNB = 250 USD
+	addTokenSwapLog(chain, cakePair, 4*time.Minute, cake, 1_000, 11)  // 11:56 0.011 BNB
+
+	service := newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{ChainID: 56, GasLimit: 21000, MulticallWait: 1},
+		BSC:   config.BSCConfig{CandleTokens: []string{cake.Hex()}},
+	})
+	count, err := service.SyncCandles(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	end := time.Unix(int64(chain.headTime), 0)
+	candles, err := service.GetTokenCandles(cake.Hex(), "1h", end.Add(-time.Hour), end)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	candle := candles[0]
+	assert.Equal(t, end.Add(-time.Hour).Unix(), candle.OpenTime.Unix())
+	assert.Equal(t, "3", candle.OpenUSD)
+	assert.Equal(t, "3.6", candle.HighUSD)
+	assert.Equal(t, "2.75", candle.LowUSD)
+	assert.Equal(t, "2.75", candle.CloseUSD)
+	assert.Equal(t, "0.01", candle.OpenBNB)
+	assert.Equal(t, "0.012", candle.HighBNB)
+	assert.Equal(t, "0.01", candle.LowBNB)
+	assert.Equal(t, "0.011", candle.CloseBNB)
+	assert.Equal(t, "4000", candle.Volume)
+	assert.Equal(t, "12950", candle.VolumeUSD)
+	assert.Equal(t, 3, candle.Trades)
+
+	candles, err = service.GetTokenCandles(cake.Hex(), "5m", end.Add(-time.Hour), end)
+	require.NoError(t, err)
+	require.Len(t, candles, 2)
+	assert.Equal(t, 2, candles[0].Trades)
+	assert.Equal(t, 1, candles[1].Trades)
+
+	// 新区块的成交与已保存的同一根K线合并
+	chain.advance(20)
+	addTokenSwapLog(chain, cakePair, 30*time.Second, cake, 1_000, 12) // 12:00:30
+	_, err = service.SyncCandles(context.Background())
+	require.NoError(t, err)
+	candles, err = service.GetTokenCandles(cake.Hex(), "1m", end, end.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+
+	chain.advance(10)
+	addTokenSwapLog(chain, cakePair, 0, wbnb, 9, 1_000) // 12:01:30
+	_, err = service.SyncCandles(context.Background())
+	require.NoError(t, err)
+	candles, err = service.GetTokenCandles(cake.Hex(), "1h", end, end.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.Equal(t, "0.012", candles[0].OpenBNB)
+	assert.Equal(t, "0.009", candles[0].CloseBNB)
+	assert.Equal(t, "0.009", candles[0].LowBNB)
+	assert.Equal(t, "2000", candles[0].Volume)
+	assert.Equal(t, 2, candles[0].Trades)
+
+	// 重复处理已聚合的区块不会重复计入成交
+	require.NoError(t, service.candles.SaveCandles(cake.Hex(), nil, chain.head-40))
+	count, err = service.SyncCandles(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 2, count)
+	candles, err = service.GetTokenCandles(cake.Hex(), "1h", end, end.Add(time.Hour))
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.Equal(t, "2000", candles[0].Volume)
+	assert.Equal(t, 2, candles[0].Trades)
+	assert.Equal(t, "0.012", candles[0].OpenBNB)
+}
+
+func TestGetTokenCandlesStablecoinPair(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Stable Only", "SO", 9)
+	pair := chain.addPair(token, tokens["USDT"], 1_000_000, 3_000_000)
+	bnbPair, _ := chain.findPair(tokens["WBNB"], tokens["USDT"])
+
+	addTokenSwapLog(chain, bnbPair, 10*time.Minute, tokens["WBNB"], 1, 300)
+	addTokenSwapLog(chain, pair, 5*time.Minute, token, 100, 330)
+
+	service := newTestBSCService(chain)
+	end := time.Unix(int64(chain.headTime), 0)
+
+	// 查询不会同步聚合，未聚合过的代币由索引器聚合
+	candles, err := service.GetTokenCandles(token.Hex(), "1d", end.Add(-time.Hour), end)
+	require.NoError(t, err)
+	assert.Empty(t, candles)
+	assert.Zero(t, chain.callCount(pair, "token0")+chain.calls["FilterLogs"])
+
+	count, err := service.SyncCandles(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+	candles, err = service.GetTokenCandles(token.Hex(), "1d", end.Add(-time.Hour), end)
+	require.NoError(t, err)
+	require.Len(t, candles, 1)
+	assert.Equal(t, "3.3", candles[0].CloseUSD)
+	assert.Equal(t, "0.011", candles[0].CloseBNB)
+	assert.Equal(t, "100", candles[0].Volume)
+	assert.Equal(t, "330", candles[0].VolumeUSD)
+}
+
+func TestGetTokenCandlesValidation(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	orphan := chain.addToken("0x00000000000000000000000000000000000000aa", "Orphan", "ORP", 18)
+	service := newTestBSCService(chain)
+	now := time.Now()
+
+	_, err := service.GetTokenCandles("0x123", "1h", now.Add(-time.Hour), now)
+	assert.ErrorIs(t, err, ErrInvalidCandleQuery)
+	_, err = service.GetTokenCandles(tokens["CAKE"].Hex(), "15m", now.Add(-time.Hour), now)
+	assert.ErrorContains(t, err, "unsupported interval")
+	_, err = service.GetTokenCandles(tokens["CAKE"].Hex(), "1m", now.Add(-24*time.Hour), now)
+	assert.ErrorContains(t, err, "at most")
+	_, err = service.GetTokenCandles(tokens["CAKE"].Hex(), "1h", now, now.Add(-time.Hour))
+	assert.ErrorIs(t, err, ErrInvalidCandleQuery)
+
+	// 没有交易对的代币聚合失败后移出队列
+	_, err = service.GetTokenCandles(orphan.Hex(), "1h", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	_, err = service.SyncCandles(context.Background())
+	assert.ErrorContains(t, err, "no WBNB or stablecoin pair")
+	assert.NotContains(t, service.candleQueried, orphan)
+	_, synced, err := service.candles.GetSyncedBlock(orphan.Hex())
+	require.NoError(t, err)
+	assert.False(t, synced)
+}
+
+func TestCandleTokenLimit(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service := newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{ChainID: 56, GasLimit: 21000, MulticallWait: 1},
+		BSC:   config.BSCConfig{CandleTokens: []string{tokens["CAKE"].Hex()}},
+	})
+	now := time.Now()
+	for i := 0; i < maxCandleTokens-1; i++ {
+		service.candleQueried[common.BigToAddress(big.NewInt(int64(0x10000+i)))] = now
+	}
+
+	_, err := service.GetTokenCandles(tokens["BUSD"].Hex(), "1h", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	_, err = service.GetTokenCandles(tokens["USDT"].Hex(), "1h", now.Add(-time.Hour), now)
+	assert.ErrorIs(t, err, ErrCandleTokenLimit)
+
+	// 配置的代币和已按需聚合的代币不受限制
+	_, err = service.GetTokenCandles(tokens["CAKE"].Hex(), "1h", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	_, err = service.GetTokenCandles(tokens["BUSD"].Hex(), "1h", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+
+	// 长时间没有被查询的代币被移除，腾出名额
+	service.candleQueried[common.BigToAddress(big.NewInt(0x10000))] = now.Add(-candleTokenIdleTTL - time.Minute)
+	_, err = service.GetTokenCandles(tokens["USDT"].Hex(), "1h", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	assert.Len(t, service.candleQueried, maxCandleTokens)
+}
+
+func TestSyncCandlesSkipsIdleTokens(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	cakePair, _ := chain.findPair(tokens["CAKE"], tokens["WBNB"])
+	addTokenSwapLog(chain, cakePair, 5*time.Minute, tokens["CAKE"], 1_000, 10)
+	service := newTestBSCService(chain)
+	end := time.Unix(int64(chain.headTime), 0)
+
+	_, err := service.GetTokenCandles(tokens["CAKE"].Hex(), "1h", end.Add(-time.Hour), end)
+	require.NoError(t, err)
+	count, err := service.SyncCandles(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	// 超过 candleTokenIdleTTL 没有被查询后不再聚合，聚合进度保留
+	service.candleQueried[tokens["CAKE"]] = time.Now().Add(-candleTokenIdleTTL - time.Minute)
+	filtered := chain.calls["FilterLogs"]
+	count, err = service.SyncCandles(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Equal(t, filtered, chain.calls["FilterLogs"])
+	assert.Empty(t, service.candleQueried)
+	_, synced, err := service.candles.GetSyncedBlock(tokens["CAKE"].Hex())
+	require.NoError(t, err)
+	assert.True(t, synced)
+}
@@ -0,0 +1,187 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// PoolReserve 池子中单个代币的储备量
+type PoolReserve struct {
+	Token     string `json:"token"`
+	Symbol    string `json:"symbol"`
+	Decimals  uint8  `json:"decimals"`
+	Amount    string `json:"amount"` // 按精度调整后的数量
+	AmountRaw string `json:"amount_raw"`
+	PriceUSD  string `json:"price_usd"`
+	ValueUSD  string `json:"value_usd"`
+}
+
+// LiquidityInfo V2交易对流动性信息
+type LiquidityInfo struct {
+	PairAddress      string       `json:"pair_address"`
+	Reserve0         *PoolReserve `json:"reserve0"`
+	Reserve1         *PoolReserve `json:"reserve1"`
+	TVLUSD           string       `json:"tvl_usd"` // 池子锁仓总价值
+	LPTotalSupply    string       `json:"lp_total_supply"`
+	LPTotalSupplyRaw string       `json:"lp_total_supply_raw"`
+	LPTokenPriceUSD  string       `json:"lp_token_price_usd"` // 每个完整LP代币的USD价值
+}
+
+// lpTokenDecimals PancakeSwap V2 LP代币精度
+const lpTokenDecimals = 18
+
+// GetLiquidityInfo 获取两个代币之间V2交易对的储备量、USD价值和LP代币信息
+func (s *BSCService) GetLiquidityInfo(tokenA, tokenB string) (*LiquidityInfo, error) {
+	return s.getLiquidityInfo(tokenA, tokenB, nil)
+}
+
+// getLiquidityInfo 获取V2交易对流动性信息，prices中已知的代币USD价格不再重复查询
+func (s *BSCService) getLiquidityInfo(tokenA, tokenB string, prices map[common.Address]*big.Rat) (*LiquidityInfo, error) {
+	reserves, err := s.getPairReserves(tokenA, tokenB)
+	if err != nil {
+		return nil, err
+	}
+
+	reserve0, reserve1, tvl, err := s.valueReserves(
+		reserves.token0, reserves.token1, reserves.reserve0, reserves.reserve1, prices)
+	if err != nil {
+		return nil, err
+	}
+
+	// LP代币总量和单价
+	output, err := s.callContract(erc20ABI, reserves.pair, "totalSupply")
+	if err != nil {
+		return nil, fmt.Errorf("failed to get LP total supply: %w", err)
+	}
+	totalSupply := output[0].(*big.Int)
+
+	lpPrice := new(big.Rat)
+	if totalSupply.Sign() > 0 {
+		lpPrice.Mul(tvl, new(big.Rat).SetInt(pow10(lpTokenDecimals)))
+		lpPrice.Quo(lpPrice, new(big.Rat).SetInt(totalSupply))
+	}
+
+	return &LiquidityInfo{
+		PairAddress:      reserves.pair.Hex(),
+		Reserve0:         reserve0,
+		Reserve1:         reserve1,
+		TVLUSD:           formatRat(tvl, 18),
+		LPTotalSupply:    formatUnits(totalSupply, lpTokenDecimals),
+		LPTotalSupplyRaw: totalSupply.String(),
+		LPTokenPriceUSD:  formatRat(lpPrice, 18),
+	}, nil
+}
+
+// getTokenUSDPrice 获取1个完整代币的USD价格（以USDT计价）
+func (s *BSCService) getTokenUSDPrice(token common.Address) (*big.Rat, error) {
+	if token == common.HexToAddress(USDTAddress) {
+		return big.NewRat(1, 1), nil
+	}
+
+	route, err := s.getTokenPriceInBNB(token.Hex())
+	if err != nil {
+		return nil, err
+	}
+	bnbPriceInUSDRaw, err := s.getBNBPriceInUSD()
+	if err != nil {
+		return nil, err
+	}
+	wbnbDecimals, err := s.getTokenDecimals(WBNBAddress)
+	if err != nil {
+		return nil, err
+	}
+	usdDecimals, err := s.getTokenDecimals(USDTAddress)
+	if err != nil {
+		return nil, err
+	}
+
+	// 价格 = 代币BNB价格 * BNB USD价格 / 10^(WBNB精度 + USDT精度)
+	numerator := new(big.Int).Mul(route.amountOut(), bnbPriceInUSDRaw)
+	denominator := new(big.Int).Mul(pow10(wbnbDecimals), pow10(usdDecimals))
+	return new(big.Rat).SetFrac(numerator, denominator), nil
+}
+
+// valueReserves 计算池子两种代币储备的USD价值和锁仓总价值
+// 只有一种代币可定价时，按恒定乘积池两侧价值相等估算另一侧
+func (s *BSCService) valueReserves(token0, token1 common.Address, amount0, amount1 *big.Int, prices map[common.Address]*big.Rat) (*PoolReserve, *PoolReserve, *big.Rat, error) {
+	tokens := [2]common.Address{token0, token1}
+	amounts := [2]*big.Int{amount0, amount1}
+	var reserves [2]*PoolReserve
+	var values [2]*big.Rat
+	var adjusted [2]*big.Rat
+
+	for i, token := range tokens {
+		decimals, err := s.getTokenDecimals(token.Hex())
+		if err != nil {
+			return nil, nil, nil, fmt.Errorf("failed to get decimals of %s: %w", token.Hex(), err)
+		}
+
+		symbol := ""
+		if output, err := s.callContract(erc20ABI, token, "symbol"); err == nil {
+			symbol = output[0].(string)
+		}
+
+		reserves[i] = &PoolReserve{
+			Token:     token.Hex(),
+			Symbol:    symbol,
+			Decimals:  decimals,
+			Amount:    formatUnits(amounts[i], decimals),
+			AmountRaw: amounts[i].String(),
+			PriceUSD:  "0",
+			ValueUSD:  "0",
+		}
+		adjusted[i] = new(big.Rat).SetFrac(amounts[i], pow10(decimals))
+
+		price := prices[token]
+		if price == nil {
+			price, err = s.getTokenUSDPrice(token)
+			if err != nil {
+				continue
+			}
+		}
+		values[i] = new(big.Rat).Mul(adjusted[i], price)
+		reserves[i].PriceUSD = formatRat(price, 18)
+	}
+
+	if values[0] == nil && values[1] == nil {
+		return nil, nil, nil, fmt.Errorf("no USD price for %s or %s", token0.Hex(), token1.Hex())
+	}
+
+	// 按另一侧价值估算无法定价的代币
+	for i := range values {
+		if values[i] != nil {
+			continue
+		}
+		values[i] = new(big.Rat).Set(values[1-i])
+		if adjusted[i].Sign() > 0 {
+			reserves[i].PriceUSD = formatRat(new(big.Rat).Quo(values[i], adjusted[i]), 18)
+		}
+	}
+
+	for i := range reserves {
+		reserves[i].ValueUSD = formatRat(values[i], 18)
+	}
+	tvl := new(big.Rat).Add(values[0], values[1])
+	return reserves[0], reserves[1], tvl, nil
+}
+
+// CombinedTVLUSD 合计V2交易对和各V3池的USD锁仓价值，info为nil时只合计V3池
+func CombinedTVLUSD(info *LiquidityInfo, v3Pools []*V3PoolInfo) string {
+	values := make([]string, 0, len(v3Pools)+1)
+	if info != nil {
+		values = append(values, info.TVLUSD)
+	}
+	for _, pool := range v3Pools {
+		values = append(values, pool.TVLUSD)
+	}
+
+	total := new(big.Rat)
+	for _, value := range values {
+		if v, ok := new(big.Rat).SetString(value); ok {
+			total.Add(total, v)
+		}
+	}
+	return formatRat(total, 18)
+}
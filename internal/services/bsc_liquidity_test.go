@@ -0,0 +1,93 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetLiquidityInfo(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service := newTestBSCService(chain)
+
+	info, err := service.GetLiquidityInfo(tokens["WBNB"].Hex(), tokens["USDT"].Hex())
+	require.NoError(t, err)
+
+	pair, _ := chain.findPair(tokens["WBNB"], tokens["USDT"])
+	assert.Equal(t, pair.Hex(), info.PairAddress)
+
+	reserves := map[string]*PoolReserve{info.Reserve0.Symbol: info.Reserve0, info.Reserve1.Symbol: info.Reserve1}
+	require.Contains(t, reserves, "USDT")
+	require.Contains(t, reserves, "WBNB")
+
+	usdt := reserves["USDT"]
+	assert.Equal(t, tokens["USDT"].Hex(), usdt.Token)
+	assert.Equal(t, uint8(18), usdt.Decimals)
+	assert.Equal(t, "300000000", usdt.Amount)
+	assert.Equal(t, "1", usdt.PriceUSD)
+	assert.Equal(t, "300000000", usdt.ValueUSD)
+
+	// WBNB按扣除手续费后的路由价格（约299.1）计价
+	wbnb := reserves["WBNB"]
+	assert.Equal(t, "1000000", wbnb.Amount)
+	assertDecimalBetween(t, wbnb.PriceUSD, "299", "300")
+	assertDecimalBetween(t, wbnb.ValueUSD, "299000000", "300000000")
+	assertDecimalBetween(t, info.TVLUSD, "599000000", "600000000")
+
+	// LP总量为 sqrt(1e6 * 3e8) ≈ 17320508 个
+	assertDecimalBetween(t, info.LPTotalSupply, "17320508", "17320509")
+	supply, ok := new(big.Rat).SetString(info.LPTotalSupply)
+	require.True(t, ok)
+	tvl, _ := new(big.Rat).SetString(info.TVLUSD)
+	lpPrice, ok := new(big.Rat).SetString(info.LPTokenPriceUSD)
+	require.True(t, ok)
+	assert.InDelta(t, 1.0, ratFloat(new(big.Rat).Quo(new(big.Rat).Mul(lpPrice, supply), tvl)), 1e-9)
+}
+
+func TestGetLiquidityInfoUnpricedSide(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Unpriced", "UNP", 9)
+	chain.addPair(token, tokens["USDT"], 500, 1_000)
+
+	// 限制为单跳后该代币无法得到BNB价格，按USDT一侧的价值估算
+	service := newTestBSCService(chain)
+	service.maxHops = 1
+
+	info, err := service.GetLiquidityInfo(token.Hex(), tokens["USDT"].Hex())
+	require.NoError(t, err)
+
+	reserves := map[string]*PoolReserve{info.Reserve0.Symbol: info.Reserve0, info.Reserve1.Symbol: info.Reserve1}
+	assert.Equal(t, "500", reserves["UNP"].Amount)
+	assert.Equal(t, "1000", reserves["UNP"].ValueUSD)
+	assert.Equal(t, "2", reserves["UNP"].PriceUSD)
+	assert.Equal(t, "2000", info.TVLUSD)
+}
+
+func TestGetLiquidityInfoNoPrice(t *testing.T) {
+	chain, _ := newRouteTestChain()
+	tokenA := chain.addToken("0x00000000000000000000000000000000000000aa", "Token A", "TKA", 18)
+	tokenB := chain.addToken("0x00000000000000000000000000000000000000bb", "Token B", "TKB", 18)
+	chain.addPair(tokenA, tokenB, 1_000, 1_000)
+
+	service := newTestBSCService(chain)
+	_, err := service.GetLiquidityInfo(tokenA.Hex(), tokenB.Hex())
+	assert.Error(t, err)
+}
+
+func TestGetTokenPriceTotalLiquidityInUSD(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service := newTestBSCService(chain)
+
+	// CAKE/WBNB池中100000 WBNB约合2990万USD，两侧合计约5980万
+	price, err := service.GetTokenPrice(tokens["CAKE"].Hex(), "")
+	require.NoError(t, err)
+	assertDecimalBetween(t, price.TotalLiquidity, "59000000", "60000000")
+}
+
+// ratFloat 将有理数转换为float64，便于近似比较
+func ratFloat(value *big.Rat) float64 {
+	f, _ := value.Float64()
+	return f
+}
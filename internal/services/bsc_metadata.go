@@ -0,0 +1,369 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+	"unicode/utf8"
+
+	"chain/internal/models"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// defaultTokenMetadataTTL 代币元数据默认缓存时间
+const defaultTokenMetadataTTL = 24 * time.Hour
+
+// EIP-1967 代理合约的存储槽
+var (
+	// eip1967ImplementationSlot bytes32(uint256(keccak256('eip1967.proxy.implementation')) - 1)
+	eip1967ImplementationSlot = common.HexToHash("0x360894a13ba1a3210667c828492db98dca3e2076cc3735a920a3ca505d382bbc")
+	// eip1967BeaconSlot bytes32(uint256(keccak256('eip1967.proxy.beacon')) - 1)
+	eip1967BeaconSlot = common.HexToHash("0xa3f0ad74e5423aebfd80d3ef4346578335a9a72aeaee59ff6cb3582b35133d50")
+)
+
+// beaconABI EIP-1967 信标合约ABI
+const beaconABI = `[
+	{
+		"inputs": [],
+		"name": "implementation",
+		"outputs": [{"name": "", "type": "address"}],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// tokens表中名称和符号的最大长度
+const (
+	maxTokenNameLength   = 100
+	maxTokenSymbolLength = 20
+)
+
+// tokenMetadata 从链上读取的代币元数据，合约不支持的方法对应字段为零值
+type tokenMetadata struct {
+	name           string
+	symbol         string
+	decimals       uint8
+	hasDecimals    bool
+	totalSupply    *big.Int // 合约不支持totalSupply时为nil
+	isProxy        bool
+	implementation common.Address // 代理合约的实现合约，信标代理读取失败时为零地址
+}
+
+// GetTokenInfo 获取代币信息，已收录或有交易对的代币元数据在代币存储中缓存，过期后从链上刷新
+// 刷新失败时返回过期的缓存；总量随增发和销毁变化，使用缓存时仍与缓存查询并行从链上读取
+func (s *BSCService) GetTokenInfo(tokenAddress string) (*TokenInfo, error) {
+	if !common.IsHexAddress(tokenAddress) {
+		return nil, fmt.Errorf("invalid token address: %s", tokenAddress)
+	}
+	addr := common.HexToAddress(tokenAddress)
+	ctx := context.Background()
+
+	var (
+		wg          sync.WaitGroup
+		totalSupply *big.Int
+		supplyErr   error
+	)
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		totalSupply, supplyErr = s.readTotalSupply(ctx, addr)
+	}()
+
+	var stored *models.Token
+	tokens, err := s.tokens.GetTokens(s.chainID.Uint64(), []string{addr.Hex()})
+	if err != nil {
+		logger.Warnf("Failed to get cached metadata of token %s: %v", addr.Hex(), err)
+	} else if len(tokens) > 0 {
+		stored = tokens[0]
+	}
+
+	// cachedInfo 使用缓存的元数据和链上读取的总量，读取失败时使用缓存的总量
+	cachedInfo := func() *TokenInfo {
+		wg.Wait()
+		info := tokenInfoFromModel(stored)
+		if supplyErr != nil {
+			logger.Warnf("Failed to read total supply of token %s, using cached value: %v", addr.Hex(), supplyErr)
+		} else if totalSupply != nil {
+			info.TotalSupplyRaw = totalSupply.String()
+			info.TotalSupply = formatUnits(totalSupply, info.Decimals)
+		}
+		return info
+	}
+
+	cached := stored != nil && stored.MetadataUpdatedAt != nil
+	if cached && time.Since(*stored.MetadataUpdatedAt) < s.metadataTTL {
+		return cachedInfo(), nil
+	}
+
+	token, err := s.refreshTokenMetadata(ctx, addr, stored)
+	if err != nil {
+		if cached {
+			logger.Warnf("Failed to refresh metadata of token %s, using cached metadata: %v", addr.Hex(), err)
+			return cachedInfo(), nil
+		}
+		return nil, err
+	}
+	return tokenInfoFromModel(token), nil
+}
+
+// readTotalSupply 从链上读取代币总量，合约不支持totalSupply时返回nil
+func (s *BSCService) readTotalSupply(ctx context.Context, addr common.Address) (*big.Int, error) {
+	parsedABI, err := parseABI(erc20ABI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
+	data, err := s.callOptional(ctx, addr, parsedABI.Methods["totalSupply"].ID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call totalSupply: %w", err)
+	}
+	if len(data) < 32 {
+		return nil, nil
+	}
+	return decodeUint256(data), nil
+}
+
+// refreshTokenMetadata 从链上读取代币元数据并保存到代币存储
+// 链上缺少的名称、符号和精度使用已保存的值（如代币列表中的信息）
+func (s *BSCService) refreshTokenMetadata(ctx context.Context, addr common.Address, stored *models.Token) (*models.Token, error) {
+	metadata, err := s.readTokenMetadata(ctx, addr)
+	if err != nil {
+		return nil, err
+	}
+
+	token := metadata.model(addr, s.chainID.Uint64())
+	if stored != nil {
+		if token.Name == "" {
+			token.Name = stored.Name
+		}
+		if token.Symbol == "" {
+			token.Symbol = stored.Symbol
+		}
+		if !metadata.hasDecimals {
+			token.Decimals = stored.Decimals
+		}
+		token.LogoURI = stored.LogoURI
+		token.Verified = stored.Verified
+		token.Source = stored.Source
+	}
+
+	// 只缓存已收录或有交易对的代币，避免任意地址的查询写满代币表
+	if stored != nil || s.hasPool(addr) {
+		if err := s.tokens.SaveTokenMetadata(token); err != nil {
+			logger.Warnf("Failed to save metadata of token %s: %v", addr.Hex(), err)
+		}
+	}
+	if metadata.hasDecimals || stored != nil {
+		s.cacheTokenDecimals(addr, token.Decimals)
+	}
+	return token, nil
+}
+
+// hasPool 判断代币是否有已索引的交易对或PancakeSwap V2的WBNB交易对，查询失败时按没有处理
+func (s *BSCService) hasPool(addr common.Address) bool {
+	pairs, err := s.pairs.GetPairsByToken(addr.Hex())
+	if err != nil {
+		logger.Warnf("Failed to get pairs of token %s: %v", addr.Hex(), err)
+	} else if len(pairs) > 0 {
+		return true
+	}
+
+	pair, err := s.getLiquidityPool(addr.Hex(), WBNBAddress)
+	if err != nil {
+		logger.Warnf("Failed to get WBNB pair of token %s: %v", addr.Hex(), err)
+		return false
+	}
+	return common.HexToAddress(pair) != (common.Address{})
+}
+
+// readTokenMetadata 从链上读取代币元数据
+// 各方法调用和代理存储槽读取并发发起，由批量读取合并为一次请求；兼容以bytes32返回名称和符号的代币，
+// 不支持的方法被忽略。地址没有代码或不支持任何ERC20元数据方法时返回错误
+func (s *BSCService) readTokenMetadata(ctx context.Context, addr common.Address) (*tokenMetadata, error) {
+	parsedABI, err := parseABI(erc20ABI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
+
+	methods := []string{"name", "symbol", "decimals", "totalSupply"}
+	slots := []common.Hash{eip1967ImplementationSlot, eip1967BeaconSlot}
+	outputs := make([][]byte, len(methods))
+	values := make([][]byte, len(slots))
+	errs := make([]error, len(methods)+len(slots))
+
+	var wg sync.WaitGroup
+	for i, method := range methods {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			data, err := s.callOptional(ctx, addr, parsedABI.Methods[method].ID)
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to call %s: %w", method, err)
+				return
+			}
+			outputs[i] = data
+		}()
+	}
+	for i, slot := range slots {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			value, err := s.client.StorageAt(ctx, addr, slot, nil)
+			if err != nil {
+				errs[len(methods)+i] = fmt.Errorf("failed to read proxy slot: %w", err)
+				return
+			}
+			values[i] = value
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	metadata := &tokenMetadata{
+		name:   decodeMetadataString(parsedABI.Methods["name"].Outputs, outputs[0]),
+		symbol: decodeMetadataString(parsedABI.Methods["symbol"].Outputs, outputs[1]),
+	}
+	// 部分代币以uint256返回精度，超出uint8范围视为不支持
+	if len(outputs[2]) >= 32 {
+		if value := decodeUint256(outputs[2]); value.IsUint64() && value.Uint64() <= 255 {
+			metadata.decimals = uint8(value.Uint64())
+			metadata.hasDecimals = true
+		}
+	}
+	if len(outputs[3]) >= 32 {
+		metadata.totalSupply = decodeUint256(outputs[3])
+	}
+
+	if metadata.name == "" && metadata.symbol == "" && !metadata.hasDecimals && metadata.totalSupply == nil {
+		// 调用没有代码的地址同样返回空数据，需要区分非合约地址和非ERC20合约
+		code, err := s.client.CodeAt(ctx, addr, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get contract code: %w", err)
+		}
+		if len(code) == 0 {
+			return nil, fmt.Errorf("address %s is not a contract", addr.Hex())
+		}
+		return nil, fmt.Errorf("address %s is not an ERC20 token", addr.Hex())
+	}
+
+	s.detectProxy(metadata, common.BytesToAddress(values[0]), common.BytesToAddress(values[1]))
+	return metadata, nil
+}
+
+// detectProxy 根据EIP-1967实现合约槽和信标槽识别代理合约及其实现合约
+func (s *BSCService) detectProxy(metadata *tokenMetadata, implementation, beacon common.Address) {
+	if implementation != (common.Address{}) {
+		metadata.isProxy = true
+		metadata.implementation = implementation
+		return
+	}
+	if beacon == (common.Address{}) {
+		return
+	}
+
+	metadata.isProxy = true
+	output, err := s.callContract(beaconABI, beacon, "implementation")
+	if err != nil {
+		logger.Debugf("Failed to get implementation of beacon %s: %v", beacon.Hex(), err)
+		return
+	}
+	metadata.implementation = output[0].(common.Address)
+}
+
+// callOptional 调用合约方法，方法不存在或执行回滚时返回空数据
+func (s *BSCService) callOptional(ctx context.Context, to common.Address, data []byte) ([]byte, error) {
+	result, err := s.client.CallContract(ctx, ethereum.CallMsg{To: &to, Data: data}, nil)
+	if err != nil {
+		if isRevertError(err) {
+			return nil, nil
+		}
+		return nil, err
+	}
+	return result, nil
+}
+
+// revertErrorCode 节点返回带回滚数据的调用错误时使用的错误码
+const revertErrorCode = 3
+
+// isRevertError 判断错误是否为调用回滚（合约不支持该方法），限流、超时、区块不存在等节点错误不算
+func isRevertError(err error) bool {
+	var rpcErr rpc.Error
+	if errors.As(err, &rpcErr) && rpcErr.ErrorCode() == revertErrorCode {
+		return true
+	}
+	return strings.Contains(err.Error(), "execution reverted")
+}
+
+// decodeMetadataString 解析名称或符号，兼容ABI字符串和bytes32两种返回格式，无法解析时返回空字符串
+func decodeMetadataString(outputs abi.Arguments, data []byte) string {
+	var raw []byte
+	if values, err := outputs.Unpack(data); err == nil && len(values) == 1 {
+		text, _ := values[0].(string)
+		raw = []byte(text)
+	} else if len(data) == 32 {
+		raw = data
+	}
+
+	raw = bytes.TrimRight(raw, "\x00")
+	if !utf8.Valid(raw) || bytes.IndexByte(raw, 0) >= 0 {
+		return ""
+	}
+	return strings.TrimSpace(string(raw))
+}
+
+// model 转换为代币模型，名称和符号按tokens表的字段长度截断
+func (m *tokenMetadata) model(addr common.Address, chainID uint64) *models.Token {
+	now := time.Now()
+	token := &models.Token{
+		Address:           addr.Hex(),
+		Name:              truncateRunes(m.name, maxTokenNameLength),
+		Symbol:            truncateRunes(m.symbol, maxTokenSymbolLength),
+		Decimals:          m.decimals,
+		ChainID:           chainID,
+		IsProxy:           m.isProxy,
+		MetadataUpdatedAt: &now,
+	}
+	if m.totalSupply != nil {
+		token.TotalSupply = m.totalSupply.String()
+	}
+	if m.implementation != (common.Address{}) {
+		token.Implementation = m.implementation.Hex()
+	}
+	return token
+}
+
+// truncateRunes 将字符串截断为不超过n个字符
+func truncateRunes(text string, n int) string {
+	if utf8.RuneCountInString(text) <= n {
+		return text
+	}
+	return string([]rune(text)[:n])
+}
+
+// tokenInfoFromModel 将代币模型转换为代币信息
+func tokenInfoFromModel(token *models.Token) *TokenInfo {
+	info := &TokenInfo{
+		Address:        token.Address,
+		Name:           token.Name,
+		Symbol:         token.Symbol,
+		Decimals:       token.Decimals,
+		TotalSupplyRaw: token.TotalSupply,
+		IsProxy:        token.IsProxy,
+		Implementation: token.Implementation,
+	}
+	if totalSupply, ok := new(big.Int).SetString(token.TotalSupply, 10); ok {
+		info.TotalSupply = formatUnits(totalSupply, token.Decimals)
+	}
+	return info
+}
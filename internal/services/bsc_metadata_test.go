@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"testing"
+	"time"
+
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTokenInfoNonStandard(t *testing.T) {
+	chain := newFakeChain()
+	maker := chain.addToken("0x00000000000000000000000000000000000000aa", "Maker", "MKR", 18)
+	chain.tokens[maker].bytes32Metadata = true
+	chain.tokens[maker].balances[common.HexToAddress("0x01")] = new(big.Int).Mul(big.NewInt(1500), pow10(18))
+	legacy := chain.addToken("0x00000000000000000000000000000000000000ab", "Legacy", "OLD", 8)
+	chain.tokens[legacy].noDecimals = true
+	service := newTestBSCService(chain)
+
+	// bytes32格式的名称和符号
+	info, err := service.GetTokenInfo(maker.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "Maker", info.Name)
+	assert.Equal(t, "MKR", info.Symbol)
+	assert.Equal(t, uint8(18), info.Decimals)
+	assert.Equal(t, "1500", info.TotalSupply)
+	assert.Equal(t, "1500000000000000000000", info.TotalSupplyRaw)
+	assert.False(t, info.IsProxy)
+
+	// 缺少decimals()的代币精度为0
+	info, err = service.GetTokenInfo(legacy.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "Legacy", info.Name)
+	assert.Zero(t, info.Decimals)
+
+	// 代币列表中的信息补充链上缺少的字段
+	require.NoError(t, service.tokens.UpsertTokens([]*models.Token{{
+		Address: legacy.Hex(), Name: "Legacy Token", Symbol: "OLD", Decimals: 8, ChainID: 56, Verified: true,
+	}}))
+	chain.tokens[legacy].name = ""
+	service.metadataTTL = 0
+	info, err = service.GetTokenInfo(legacy.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "Legacy Token", info.Name)
+	assert.Equal(t, uint8(8), info.Decimals)
+	tokens, err := service.SearchTokens(TokenQuery{Text: "legacy"})
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.True(t, tokens[0].Verified)
+
+	// 非合约地址和非ERC20合约
+	_, err = service.GetTokenInfo("0x00000000000000000000000000000000000000ac")
+	assert.ErrorContains(t, err, "is not a contract")
+	tokenA := chain.addToken("0x00000000000000000000000000000000000000ad", "A", "A", 18)
+	pair := chain.addPair(tokenA, legacy, 1, 1)
+	_, err = service.GetTokenInfo(pair.Hex())
+	require.NoError(t, err, "LP tokens are ERC20 tokens")
+	_, err = service.GetTokenInfo("not-an-address")
+	assert.Error(t, err)
+}
+
+func TestGetTokenInfoProxy(t *testing.T) {
+	chain := newFakeChain()
+	implementation := common.HexToAddress("0x00000000000000000000000000000000000000c1")
+	proxy := chain.addToken("0x00000000000000000000000000000000000000aa", "Proxy Token", "PRX", 18)
+	chain.tokens[proxy].storage = map[common.Hash]common.Hash{
+		eip1967ImplementationSlot: common.BytesToHash(implementation.Bytes()),
+	}
+
+	beacon := common.HexToAddress("0x00000000000000000000000000000000000000c2")
+	chain.beacons[beacon] = implementation
+	beaconProxy := chain.addToken("0x00000000000000000000000000000000000000ab", "Beacon Token", "BCN", 18)
+	chain.tokens[beaconProxy].storage = map[common.Hash]common.Hash{
+		eip1967BeaconSlot: common.BytesToHash(beacon.Bytes()),
+	}
+	service := newTestBSCService(chain)
+
+	info, err := service.GetTokenInfo(proxy.Hex())
+	require.NoError(t, err)
+	assert.True(t, info.IsProxy)
+	assert.Equal(t, implementation.Hex(), info.Implementation)
+
+	info, err = service.GetTokenInfo(beaconProxy.Hex())
+	require.NoError(t, err)
+	assert.True(t, info.IsProxy)
+	assert.Equal(t, implementation.Hex(), info.Implementation)
+}
+
+func TestGetTokenInfoCache(t *testing.T) {
+	chain := newFakeChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Test Token", "TEST", 9)
+	service := newTestBSCService(chain)
+
+	// 未收录且没有交易对的代币不缓存，每次从链上读取
+	for i := 0; i < 2; i++ {
+		_, err := service.GetTokenInfo(token.Hex())
+		require.NoError(t, err)
+	}
+	assert.Equal(t, 2, chain.callCount(token, "symbol"))
+	tokens, err := service.tokens.GetTokens(56, []string{token.Hex()})
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+
+	// 有已索引交易对的代币缓存元数据
+	require.NoError(t, service.pairs.SavePairs([]*models.DexPair{{
+		PairAddress: "0x0000000000000000000000000000000000002001",
+		Token0:      token.Hex(),
+		Token1:      "0x00000000000000000000000000000000000000bb",
+		BlockTime:   time.Now(),
+	}}))
+
+	for i := 0; i < 3; i++ {
+		info, err := service.GetTokenInfo(token.Hex())
+		require.NoError(t, err)
+		assert.Equal(t, "TEST", info.Symbol)
+	}
+	assert.Equal(t, 3, chain.callCount(token, "symbol"))
+
+	// 使用缓存时总量仍从链上读取
+	chain.mint(token, common.HexToAddress("0x01"), 5)
+	info, err := service.GetTokenInfo(token.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "5", info.TotalSupply)
+	assert.Equal(t, 3, chain.callCount(token, "symbol"))
+
+	// 缓存过期后从链上刷新
+	service.metadataTTL = 0
+	chain.tokens[token].symbol = "NEW"
+	info, err = service.GetTokenInfo(token.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "NEW", info.Symbol)
+	assert.Equal(t, 4, chain.callCount(token, "symbol"))
+
+	// 刷新失败时返回过期的缓存
+	delete(chain.tokens, token)
+	info, err = service.GetTokenInfo(token.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, "NEW", info.Symbol)
+}
+
+// testRPCError 带错误码的节点错误
+type testRPCError struct {
+	code int
+	msg  string
+}
+
+func (e *testRPCError) Error() string  { return e.msg }
+func (e *testRPCError) ErrorCode() int { return e.code }
+
+func TestIsRevertError(t *testing.T) {
+	assert.True(t, isRevertError(&testRPCError{code: 3, msg: "execution reverted: not supported"}))
+	assert.True(t, isRevertError(&testRPCError{code: -32000, msg: "execution reverted"}))
+	assert.True(t, isRevertError(fmt.Errorf("call failed: %w", &testRPCError{code: 3, msg: "reverted"})))
+	assert.False(t, isRevertError(&testRPCError{code: -32005, msg: "limit exceeded"}))
+	assert.False(t, isRevertError(&testRPCError{code: -32000, msg: "header not found"}))
+	assert.False(t, isRevertError(errors.New("connection refused")))
+}
@@ -0,0 +1,197 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"chain/internal/config"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// aggregatorV3ABI Chainlink AggregatorV3Interface ABI（部分）
+const aggregatorV3ABI = `[
+	{
+		"inputs": [],
+		"name": "decimals",
+		"outputs": [{"name": "", "type": "uint8"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "latestRoundData",
+		"outputs": [
+			{"name": "roundId", "type": "uint80"},
+			{"name": "answer", "type": "int256"},
+			{"name": "startedAt", "type": "uint256"},
+			{"name": "updatedAt", "type": "uint256"},
+			{"name": "answeredInRound", "type": "uint80"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// 预言机价格校验默认参数
+const (
+	defaultOracleMaxAge      = time.Hour
+	defaultMaxPriceDeviation = 5.0 // 百分比
+)
+
+// defaultOracleFeeds BSC主网的Chainlink价格源
+var defaultOracleFeeds = []config.OracleFeedConfig{
+	{Asset: WBNBAddress, Feed: "0x0567F2323251f0Aab15c8dFb1967E4e8A7D42aeE"}, // BNB/USD
+}
+
+// oraclePrice 预言机报价
+type oraclePrice struct {
+	price     *big.Rat // USD价格
+	updatedAt time.Time
+}
+
+// newOracleFeeds 根据配置生成 代币 => 价格源 映射
+func newOracleFeeds(configs []config.OracleFeedConfig) map[common.Address]common.Address {
+	if len(configs) == 0 {
+		configs = defaultOracleFeeds
+	}
+
+	feeds := make(map[common.Address]common.Address, len(configs))
+	for _, c := range configs {
+		feeds[common.HexToAddress(c.Asset)] = common.HexToAddress(c.Feed)
+	}
+	return feeds
+}
+
+// hasOracleFeed 判断代币是否配置了价格源
+func (s *BSCService) hasOracleFeed(token common.Address) bool {
+	_, ok := s.oracleFeeds[token]
+	return ok
+}
+
+// tokenOracles 校验代币USD价格使用的预言机报价，价格源未配置或不可用时为nil
+type tokenOracles struct {
+	bnb   *oraclePrice // BNB/USD价格源，用于校验WBNB/USDT池子给出的BNB价格
+	token *oraclePrice // 代币自己的价格源
+}
+
+// getTokenOracles 并发读取BNB/USD价格源和代币自己的价格源，不可用的价格源记录日志后跳过
+func (s *BSCService) getTokenOracles(token common.Address) tokenOracles {
+	var (
+		oracles tokenOracles
+		wg      sync.WaitGroup
+	)
+	read := func(asset common.Address, dst **oraclePrice) {
+		defer wg.Done()
+		price, err := s.getOraclePrice(asset)
+		if err != nil {
+			logger.Warnf("Failed to get oracle price of %s: %v", asset.Hex(), err)
+			return
+		}
+		*dst = price
+	}
+
+	wbnb := common.HexToAddress(WBNBAddress)
+	if s.hasOracleFeed(wbnb) {
+		wg.Add(1)
+		go read(wbnb, &oracles.bnb)
+	}
+	// WBNB自己的价格已由BNB/USD价格源校验
+	if token != wbnb && s.hasOracleFeed(token) {
+		wg.Add(1)
+		go read(token, &oracles.token)
+	}
+	wg.Wait()
+	return oracles
+}
+
+// fallbackPrice DEX上没有可用的BNB/USDT价格时代币的USD价格
+// 优先使用代币自己的价格源，否则以BNB/USD价格源和代币的BNB价格换算；都不可用时返回nil
+func (o tokenOracles) fallbackPrice(priceInBNBRaw *big.Int, wbnbDecimals uint8) *big.Rat {
+	if o.token != nil {
+		return o.token.price
+	}
+	if o.bnb != nil {
+		return new(big.Rat).Mul(new(big.Rat).SetFrac(priceInBNBRaw, pow10(wbnbDecimals)), o.bnb.price)
+	}
+	return nil
+}
+
+// getOraclePrice 读取代币在Chainlink价格源上的最新USD价格
+// 价格非正、轮次未完成、答案来自旧轮次或更新时间超过oracleMaxAge时返回错误
+func (s *BSCService) getOraclePrice(asset common.Address) (*oraclePrice, error) {
+	feed, ok := s.oracleFeeds[asset]
+	if !ok {
+		return nil, fmt.Errorf("no oracle feed for %s", asset.Hex())
+	}
+
+	decimals, err := s.getFeedDecimals(feed)
+	if err != nil {
+		return nil, err
+	}
+
+	output, err := s.callContract(aggregatorV3ABI, feed, "latestRoundData")
+	if err != nil {
+		return nil, fmt.Errorf("failed to read oracle feed %s: %w", feed.Hex(), err)
+	}
+	roundID := output[0].(*big.Int)
+	answer := output[1].(*big.Int)
+	updatedAt := output[3].(*big.Int)
+	answeredInRound := output[4].(*big.Int)
+
+	switch {
+	case answer.Sign() <= 0:
+		return nil, fmt.Errorf("oracle feed %s returned invalid answer %s", feed.Hex(), answer)
+	case updatedAt.Sign() == 0:
+		return nil, fmt.Errorf("oracle feed %s round %s is not complete", feed.Hex(), roundID)
+	case answeredInRound.Cmp(roundID) < 0:
+		return nil, fmt.Errorf("oracle feed %s answer is from stale round %s", feed.Hex(), answeredInRound)
+	}
+
+	updated := time.Unix(updatedAt.Int64(), 0)
+	if age := time.Since(updated); age > s.oracleMaxAge {
+		return nil, fmt.Errorf("oracle price of %s is stale: updated %s ago", asset.Hex(), age.Truncate(time.Second))
+	}
+
+	return &oraclePrice{
+		price:     new(big.Rat).SetFrac(answer, pow10(decimals)),
+		updatedAt: updated,
+	}, nil
+}
+
+// getFeedDecimals 获取价格源的精度，结果会被缓存
+func (s *BSCService) getFeedDecimals(feed common.Address) (uint8, error) {
+	if cached, ok := s.feedDecimals.Load(feed); ok {
+		return cached.(uint8), nil
+	}
+
+	output, err := s.callContract(aggregatorV3ABI, feed, "decimals")
+	if err != nil {
+		return 0, fmt.Errorf("failed to get decimals of oracle feed %s: %w", feed.Hex(), err)
+	}
+	decimals := output[0].(uint8)
+	s.feedDecimals.Store(feed, decimals)
+	return decimals, nil
+}
+
+// checkPriceDeviation 计算DEX价格相对预言机价格的偏差（百分比）并判断是否超过阈值
+// 偏差超过阈值且配置为拒绝时返回错误
+func (s *BSCService) checkPriceDeviation(token string, dexPrice, oraclePrice *big.Rat) (*big.Rat, bool, error) {
+	deviation := new(big.Rat).Sub(dexPrice, oraclePrice)
+	deviation.Quo(deviation, oraclePrice)
+	deviation.Mul(deviation, big.NewRat(100, 1))
+	formatted := formatRat(deviation, 4)
+
+	if new(big.Rat).Abs(deviation).Cmp(s.maxDeviation) <= 0 {
+		return deviation, false, nil
+	}
+	if s.rejectDeviated {
+		return deviation, true, fmt.Errorf("price of %s deviates %s%% from oracle price %s USD",
+			token, formatted, formatRat(oraclePrice, 8))
+	}
+	logger.Warnf("Price of %s deviates %s%% from oracle price %s USD", token, formatted, formatRat(oraclePrice, 8))
+	return deviation, true, nil
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"chain/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newOracleTestService 创建BNB/USD和CAKE都配置了价格源的服务
+func newOracleTestService(chain *fakeChain, cakeFeed common.Address, bsc config.BSCConfig) *BSCService {
+	bsc.OracleFeeds = []config.OracleFeedConfig{
+		{Asset: WBNBAddress, Feed: defaultOracleFeeds[0].Feed},
+		{Asset: CAKEAddress, Feed: cakeFeed.Hex()},
+	}
+	return newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{ChainID: 56, GasLimit: 21000, MulticallWait: 1},
+		BSC:   bsc,
+	})
+}
+
+func TestGetTokenPriceOracleCheck(t *testing.T) {
+	bnbFeed := defaultOracleFeeds[0].Feed
+	cakeFeedAddress := "0x00000000000000000000000000000000000000f1"
+
+	t.Run("within threshold", func(t *testing.T) {
+		chain, tokens := newRouteTestChain()
+		chain.addFeed(bnbFeed, 300_00)
+		service := newOracleTestService(chain, chain.addFeed(cakeFeedAddress, 3_00), config.BSCConfig{})
+
+		price, err := service.GetTokenPrice(tokens["CAKE"].Hex(), "")
+		require.NoError(t, err)
+		// 1 CAKE ≈ 0.01 BNB，BNB/USDT约为300
+		assert.Equal(t, "3", price.OraclePriceUSD)
+		assertDecimalBetween(t, price.PriceDeviation, "-1", "0")
+		assert.False(t, price.PriceDeviated)
+	})
+
+	t.Run("deviation flagged", func(t *testing.T) {
+		chain, tokens := newRouteTestChain()
+		service := newOracleTestService(chain, chain.addFeed(cakeFeedAddress, 2_50), config.BSCConfig{})
+
+		price, err := service.GetTokenPrice(tokens["CAKE"].Hex(), "")
+		require.NoError(t, err)
+		assertDecimalBetween(t, price.PriceDeviation, "19", "20")
+		assert.True(t, price.PriceDeviated)
+		assertDecimalBetween(t, price.PriceInUSD, "2.9", "3")
+	})
+
+	t.Run("deviation rejected", func(t *testing.T) {
+		chain, tokens := newRouteTestChain()
+		feed := chain.addFeed(cakeFeedAddress, 2_50)
+		service := newOracleTestService(chain, feed, config.BSCConfig{MaxPriceDeviation: 10, RejectDeviatedPrice: true})
+
+		_, err := service.GetTokenPrice(tokens["CAKE"].Hex(), "")
+		assert.ErrorContains(t, err, "deviates")
+
+		// 阈值以内的价格正常返回
+		chain.feeds[feed].answer = big.NewInt(2_80 * 1_000_000)
+		price, err := service.GetTokenPrice(tokens["CAKE"].Hex(), "")
+		require.NoError(t, err)
+		assert.False(t, price.PriceDeviated)
+	})
+
+	t.Run("skewed BNB/USDT pool", func(t *testing.T) {
+		chain := newFakeChain()
+		wbnb := chain.addToken(WBNBAddress, "Wrapped BNB", "WBNB", 18)
+		usdt := chain.addToken(USDTAddress, "Tether USD", "USDT", 18)
+		cake := chain.addToken(CAKEAddress, "PancakeSwap Token", "Cake", 18)
+		// WBNB/USDT池子被操纵到1 BNB ≈ 450 USDT，BNB/USD价格源为300
+		chain.addPair(wbnb, usdt, 1_000_000, 450_000_000)
+		chain.addPair(cake, wbnb, 10_000_000, 100_000)
+		feed := chain.addFeed(bnbFeed, 300_00)
+		service := newTestBSCService(chain)
+
+		// 没有自己价格源的代币同样经过BNB价格校验
+		price, err := service.GetTokenPrice(cake.Hex(), "")
+		require.NoError(t, err)
+		assertDecimalBetween(t, price.OraclePriceUSD, "2.98", "3")
+		assertDecimalBetween(t, price.PriceDeviation, "49", "50")
+		assert.True(t, price.PriceDeviated)
+
+		service = newBSCService(chain, &config.Config{
+			Chain: config.ChainConfig{ChainID: 56, GasLimit: 21000, MulticallWait: 1},
+			BSC:   config.BSCConfig{MaxPriceDeviation: 10, RejectDeviatedPrice: true},
+		})
+		_, err = service.GetTokenPrice(cake.Hex(), "")
+		assert.ErrorContains(t, err, "price of BNB deviates")
+
+		// 价格源与池子一致时正常返回
+		chain.feeds[feed].answer = big.NewInt(450_00 * 1_000_000)
+		price, err = service.GetTokenPrice(cake.Hex(), "")
+		require.NoError(t, err)
+		assert.False(t, price.PriceDeviated)
+	})
+
+	t.Run("token feed checked with BNB feed", func(t *testing.T) {
+		chain, tokens := newRouteTestChain()
+		chain.addFeed(bnbFeed, 300_00)
+		service := newOracleTestService(chain, chain.addFeed(cakeFeedAddress, 2_00), config.BSCConfig{})
+
+		// BNB价格正常，代币价格与自己的价格源偏差较大
+		price, err := service.GetTokenPrice(tokens["CAKE"].Hex(), "")
+		require.NoError(t, err)
+		assert.Equal(t, "2", price.OraclePriceUSD)
+		assertDecimalBetween(t, price.PriceDeviation, "49", "50")
+		assert.True(t, price.PriceDeviated)
+	})
+
+	t.Run("stale feed ignored", func(t *testing.T) {
+		chain, tokens := newRouteTestChain()
+		feed := chain.addFeed(cakeFeedAddress, 2_50)
+		chain.feeds[feed].updatedAt = chain.headTime - 2*3600
+		service := newOracleTestService(chain, feed, config.BSCConfig{})
+
+		price, err := service.GetTokenPrice(tokens["CAKE"].Hex(), "")
+		require.NoError(t, err)
+		assert.Empty(t, price.OraclePriceUSD)
+		assert.Empty(t, price.PriceDeviation)
+		assert.False(t, price.PriceDeviated)
+
+		// 答案来自旧轮次
+		chain.feeds[feed].updatedAt = chain.headTime
+		chain.feeds[feed].answeredInRound = big.NewInt(99)
+		_, err = service.getOraclePrice(tokens["CAKE"])
+		assert.ErrorContains(t, err, "stale round")
+	})
+
+	t.Run("oracle fallback without BNB/USDT pool", func(t *testing.T) {
+		chain := newFakeChain()
+		wbnb := chain.addToken(WBNBAddress, "Wrapped BNB", "WBNB", 18)
+		chain.addToken(USDTAddress, "Tether USD", "USDT", 6)
+		cake := chain.addToken(CAKEAddress, "PancakeSwap Token", "Cake", 18)
+		chain.addPair(cake, wbnb, 10_000_000, 100_000)
+		chain.addFeed(bnbFeed, 300_00)
+		service := newTestBSCService(chain)
+
+		price, err := service.GetTokenPrice(cake.Hex(), "")
+		require.NoError(t, err)
+		// 以BNB/USD价格源换算的USD价格，不作为预言机价格返回
+		assertDecimalBetween(t, price.PriceInUSD, "2.98", "3")
+		assert.Empty(t, price.OraclePriceUSD)
+		assert.Empty(t, price.PriceDeviation)
+	})
+}
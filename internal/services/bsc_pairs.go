@@ -0,0 +1,274 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"chain/internal/config"
+	"chain/internal/models"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 交易对索引相关参数
+const (
+	defaultPairBackfillBlocks = 28800 // 约24小时的区块数
+	pairSubscriberBuffer      = 64    // 每个订阅者缓冲的交易对数量，缓冲满时丢弃
+)
+
+// 默认的V2 DEX配置
+var defaultV2Dexes = []config.V2DexConfig{
+	{Name: "pancakeswap-v2", Factory: PancakeSwapV2Factory},
+}
+
+// v2Dex Uniswap V2风格DEX
+type v2Dex struct {
+	name       string
+	factory    common.Address
+	startBlock uint64
+}
+
+// newV2Dexes 根据配置生成V2 DEX列表
+func newV2Dexes(configs []config.V2DexConfig) []*v2Dex {
+	if len(configs) == 0 {
+		configs = defaultV2Dexes
+	}
+
+	dexes := make([]*v2Dex, 0, len(configs))
+	for _, c := range configs {
+		dexes = append(dexes, &v2Dex{
+			name:       c.Name,
+			factory:    common.HexToAddress(c.Factory),
+			startBlock: c.StartBlock,
+		})
+	}
+	return dexes
+}
+
+// SetPairStore 设置交易对索引存储，默认使用内存存储
+func (s *BSCService) SetPairStore(store PairStore) {
+	s.pairs = store
+}
+
+// GetTokenPairs 返回已索引的包含指定代币的所有交易对
+func (s *BSCService) GetTokenPairs(token string) ([]*models.DexPair, error) {
+	return s.pairs.GetPairsByToken(token)
+}
+
+// GetRecentPairs 返回最近一段时间内创建的交易对
+func (s *BSCService) GetRecentPairs(window time.Duration) ([]*models.DexPair, error) {
+	return s.pairs.GetPairsSince(time.Now().Add(-window))
+}
+
+// SubscribeNewPairs 订阅新索引到的交易对，调用返回的函数取消订阅
+// 订阅者处理过慢导致缓冲区满时，新交易对会被丢弃
+func (s *BSCService) SubscribeNewPairs() (<-chan *models.DexPair, func()) {
+	ch := make(chan *models.DexPair, pairSubscriberBuffer)
+
+	s.pairSubsMu.Lock()
+	s.pairSubs[ch] = struct{}{}
+	s.pairSubsMu.Unlock()
+
+	var once sync.Once
+	return ch, func() {
+		once.Do(func() {
+			s.pairSubsMu.Lock()
+			delete(s.pairSubs, ch)
+			close(ch)
+			s.pairSubsMu.Unlock()
+		})
+	}
+}
+
+// publishPairs 将新交易对推送给所有订阅者
+func (s *BSCService) publishPairs(pairs []*models.DexPair) {
+	s.pairSubsMu.Lock()
+	defer s.pairSubsMu.Unlock()
+
+	for ch := range s.pairSubs {
+		for _, pair := range pairs {
+			copied := *pair
+			select {
+			case ch <- &copied:
+			default:
+				logger.Warnf("Pair subscriber buffer full, dropping pair %s", pair.PairAddress)
+			}
+		}
+	}
+}
+
+// RunPairIndexer 按同步间隔持续索引新交易对，直到ctx取消；同步间隔为0时不启动
+func (s *BSCService) RunPairIndexer(ctx context.Context) {
+	if s.pairSyncInterval <= 0 {
+		logger.Info("Pair indexer disabled")
+		return
+	}
+
+	ticker := time.NewTicker(s.pairSyncInterval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := s.SyncPairs(ctx); err != nil {
+			logger.Warnf("Failed to sync pairs: %v", err)
+		} else if count > 0 {
+			logger.Infof("Indexed %d new pairs", count)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SyncPairs 从各工厂合约上次同步的区块开始索引PairCreated事件，返回新索引的交易对数量
+func (s *BSCService) SyncPairs(ctx context.Context) (int, error) {
+	s.pairSyncMu.Lock()
+	defer s.pairSyncMu.Unlock()
+
+	latest, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	head := latest.Number.Uint64()
+
+	// 单个DEX同步失败不影响其他DEX
+	total := 0
+	var errs []error
+	for _, dex := range s.v2Dexes {
+		count, err := s.syncDexPairs(ctx, dex, head)
+		total += count
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to sync %s pairs: %w", dex.name, err))
+		}
+	}
+	return total, errors.Join(errs...)
+}
+
+// syncDexPairs 按logBlockRange分段索引单个工厂合约到head为止的交易对，每段完成后记录进度
+func (s *BSCService) syncDexPairs(ctx context.Context, dex *v2Dex, head uint64) (int, error) {
+	synced, ok, err := s.pairs.GetSyncedBlock(dex.factory.Hex())
+	if err != nil {
+		return 0, fmt.Errorf("failed to get sync state: %w", err)
+	}
+
+	from := synced + 1
+	if !ok {
+		from = dex.startBlock
+		if from == 0 && head > s.pairBackfill {
+			from = head - s.pairBackfill
+		}
+	}
+
+	parsedABI, err := parseABI(pancakeFactoryABI)
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse factory ABI: %w", err)
+	}
+	event := parsedABI.Events["PairCreated"]
+
+	count := 0
+	for start := from; start <= head; start += s.logBlockRange {
+		if err := ctx.Err(); err != nil {
+			return count, err
+		}
+		end := start + s.logBlockRange - 1
+		if end > head {
+			end = head
+		}
+
+		logs, err := s.filterLogs(ethereum.FilterQuery{
+			Addresses: []common.Address{dex.factory},
+			Topics:    [][]common.Hash{{event.ID}},
+		}, start, end)
+		if err != nil {
+			return count, err
+		}
+
+		pairs, err := s.decodePairCreated(ctx, dex, event, logs, start, end)
+		if err != nil {
+			return count, err
+		}
+		if err := s.pairs.SavePairs(pairs); err != nil {
+			return count, fmt.Errorf("failed to save pairs: %w", err)
+		}
+		if err := s.pairs.SetSyncedBlock(dex.factory.Hex(), end); err != nil {
+			return count, fmt.Errorf("failed to save sync state: %w", err)
+		}
+
+		s.discoverTokens(dex, pairs)
+		s.publishPairs(pairs)
+		count += len(pairs)
+	}
+	return count, nil
+}
+
+// decodePairCreated 解析区块范围[start, end]内的PairCreated事件
+func (s *BSCService) decodePairCreated(ctx context.Context, dex *v2Dex, event abi.Event, logs []types.Log, start, end uint64) ([]*models.DexPair, error) {
+	if len(logs) == 0 {
+		return nil, nil
+	}
+
+	blockTime, err := s.blockTimeEstimator(ctx, start, end)
+	if err != nil {
+		return nil, err
+	}
+
+	pairs := make([]*models.DexPair, 0, len(logs))
+	for _, log := range logs {
+		if log.Removed || len(log.Topics) < 3 {
+			continue
+		}
+		values, err := event.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack PairCreated event: %w", err)
+		}
+
+		pairs = append(pairs, &models.DexPair{
+			Dex:         dex.name,
+			Factory:     dex.factory.Hex(),
+			PairAddress: values[0].(common.Address).Hex(),
+			Token0:      common.BytesToAddress(log.Topics[1].Bytes()).Hex(),
+			Token1:      common.BytesToAddress(log.Topics[2].Bytes()).Hex(),
+			PairIndex:   values[1].(*big.Int).Uint64(),
+			BlockNumber: log.BlockNumber,
+			BlockTime:   blockTime(log.BlockNumber),
+			TxHash:      log.TxHash.Hex(),
+			ChainID:     s.chainID.Uint64(),
+		})
+	}
+	return pairs, nil
+}
+
+// blockTimeEstimator 读取区块范围两端的区块头，按线性插值估算范围内区块的时间
+// 避免为每个事件所在区块单独查询区块头
+func (s *BSCService) blockTimeEstimator(ctx context.Context, start, end uint64) (func(uint64) time.Time, error) {
+	startHeader, err := s.client.HeaderByNumber(ctx, new(big.Int).SetUint64(start))
+	if err != nil {
+		return nil, fmt.Errorf("failed to get block %d: %w", start, err)
+	}
+	endHeader := startHeader
+	if end != start {
+		endHeader, err = s.client.HeaderByNumber(ctx, new(big.Int).SetUint64(end))
+		if err != nil {
+			return nil, fmt.Errorf("failed to get block %d: %w", end, err)
+		}
+	}
+
+	return func(number uint64) time.Time {
+		timestamp := startHeader.Time
+		if end != start && number > start {
+			elapsed := (endHeader.Time - startHeader.Time) * (number - start) / (end - start)
+			timestamp += elapsed
+		}
+		return time.Unix(int64(timestamp), 0)
+	}, nil
+}
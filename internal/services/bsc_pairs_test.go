@@ -0,0 +1,157 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"chain/internal/config"
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testBiswapFactory = "0x858E3312ed3A876947EA49d572A7C42DE08af7EE"
+
+func newTestPairService(chain *fakeChain) *BSCService {
+	return newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{ChainID: 56, MulticallWait: 1},
+		BSC: config.BSCConfig{
+			V2Dexes: []config.V2DexConfig{
+				{Name: "pancakeswap-v2", Factory: PancakeSwapV2Factory},
+				{Name: "biswap", Factory: testBiswapFactory},
+			},
+			PairBackfillBlocks: 28800,
+		},
+	})
+}
+
+func pairAddresses(pairs []*models.DexPair) []string {
+	var addresses []string
+	for _, pair := range pairs {
+		addresses = append(addresses, pair.PairAddress)
+	}
+	return addresses
+}
+
+func TestSyncPairs(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	pancake := common.HexToAddress(PancakeSwapV2Factory)
+	biswap := common.HexToAddress(testBiswapFactory)
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	chain.addPairCreatedLog(pancake, token, tokens["USDT"], 48*time.Hour) // 超出回溯范围
+	old := chain.addPairCreatedLog(pancake, token, tokens["WBNB"], 3*time.Hour)
+	recent := chain.addPairCreatedLog(biswap, tokens["WBNB"], token, 10*time.Minute)
+	other := chain.addPairCreatedLog(pancake, tokens["CAKE"], tokens["USDT"], 5*time.Minute)
+
+	service := newTestPairService(chain)
+	count, err := service.SyncPairs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 3, count)
+
+	pairs, err := service.GetTokenPairs(token.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, []string{old.Hex(), recent.Hex()}, pairAddresses(pairs))
+
+	token0, token1 := sortTokens(token, tokens["WBNB"])
+	assert.Equal(t, "biswap", pairs[1].Dex)
+	assert.Equal(t, biswap.Hex(), pairs[1].Factory)
+	assert.Equal(t, token0.Hex(), pairs[1].Token0)
+	assert.Equal(t, token1.Hex(), pairs[1].Token1)
+	assert.Equal(t, uint64(56), pairs[1].ChainID)
+	assert.WithinDuration(t, time.Now().Add(-10*time.Minute), pairs[1].BlockTime, time.Minute)
+
+	recentPairs, err := service.GetRecentPairs(time.Hour)
+	require.NoError(t, err)
+	assert.Equal(t, []string{recent.Hex(), other.Hex()}, pairAddresses(recentPairs))
+
+	// 再次同步只处理新区块
+	filterCalls := chain.calls["FilterLogs"]
+	count, err = service.SyncPairs(context.Background())
+	require.NoError(t, err)
+	assert.Zero(t, count)
+	assert.Equal(t, filterCalls, chain.calls["FilterLogs"])
+}
+
+func TestSyncPairsStartBlock(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	pancake := common.HexToAddress(PancakeSwapV2Factory)
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+	first := chain.addPairCreatedLog(pancake, token, tokens["USDT"], 48*time.Hour)
+
+	service := newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{ChainID: 56, MulticallWait: 1},
+		BSC: config.BSCConfig{
+			V2Dexes: []config.V2DexConfig{
+				{Name: "pancakeswap-v2", Factory: PancakeSwapV2Factory, StartBlock: chain.head - 60_000},
+			},
+		},
+	})
+	_, err := service.SyncPairs(context.Background())
+	require.NoError(t, err)
+
+	pairs, err := service.GetTokenPairs(token.Hex())
+	require.NoError(t, err)
+	assert.Equal(t, []string{first.Hex()}, pairAddresses(pairs))
+}
+
+func TestSubscribeNewPairs(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	pancake := common.HexToAddress(PancakeSwapV2Factory)
+	token := common.HexToAddress("0x00000000000000000000000000000000000000aa")
+
+	service := newTestPairService(chain)
+	_, err := service.SyncPairs(context.Background())
+	require.NoError(t, err)
+
+	pairs, cancel := service.SubscribeNewPairs()
+	defer cancel()
+
+	chain.advance(10)
+	created := chain.addPairCreatedLog(pancake, token, tokens["WBNB"], 0)
+	count, err := service.SyncPairs(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, 1, count)
+
+	select {
+	case pair := <-pairs:
+		assert.Equal(t, created.Hex(), pair.PairAddress)
+		assert.Equal(t, "pancakeswap-v2", pair.Dex)
+	case <-time.After(time.Second):
+		t.Fatal("new pair not published")
+	}
+
+	// 取消订阅后通道关闭
+	cancel()
+	_, ok := <-pairs
+	assert.False(t, ok)
+}
+
+func TestMemoryPairStore(t *testing.T) {
+	store := NewMemoryPairStore()
+	pair := &models.DexPair{
+		PairAddress: "0x0000000000000000000000000000000000002001",
+		Token0:      "0x00000000000000000000000000000000000000aa",
+		Token1:      WBNBAddress,
+		BlockNumber: 10,
+		BlockTime:   time.Now(),
+	}
+	require.NoError(t, store.SavePairs([]*models.DexPair{pair, pair}))
+
+	pairs, err := store.GetPairsByToken("0x00000000000000000000000000000000000000AA")
+	require.NoError(t, err)
+	assert.Len(t, pairs, 1)
+
+	_, ok, err := store.GetSyncedBlock(PancakeSwapV2Factory)
+	require.NoError(t, err)
+	assert.False(t, ok)
+
+	require.NoError(t, store.SetSyncedBlock(PancakeSwapV2Factory, 100))
+	block, ok, err := store.GetSyncedBlock(PancakeSwapV2Factory)
+	require.NoError(t, err)
+	assert.True(t, ok)
+	assert.Equal(t, uint64(100), block)
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 滑点容忍度（基点）
+const (
+	DefaultSlippageBps = 50   // 默认0.5%
+	MaxSlippageBps     = 5000 // 最大50%
+)
+
+// ErrInvalidTradeQuery 兑换的代币地址、数量或滑点无效
+var ErrInvalidTradeQuery = errors.New("invalid trade query")
+
+// TradeQuote 指定交易数量的报价
+type TradeQuote struct {
+	TokenIn            string   `json:"token_in"`
+	TokenOut           string   `json:"token_out"`
+	AmountIn           string   `json:"amount_in"`
+	AmountInRaw        string   `json:"amount_in_raw"`
+	AmountOut          string   `json:"amount_out"`
+	AmountOutRaw       string   `json:"amount_out_raw"`
+	ExecutionPrice     string   `json:"execution_price"` // 每个输入代币实际可获得的输出代币数量
+	SpotPrice          string   `json:"spot_price"`      // 按池子当前状态计算的中间价
+	PriceImpact        string   `json:"price_impact"`    // 成交价相对中间价的偏离百分比（含手续费）
+	SlippageBps        uint32   `json:"slippage_bps"`
+	MinimumReceived    string   `json:"minimum_received"`
+	MinimumReceivedRaw string   `json:"minimum_received_raw"`
+	Route              []string `json:"route"`
+	RoutePools         []string `json:"route_pools"`
+}
+
+// QuoteTrade 查询以指定数量的tokenIn兑换tokenOut的报价
+// amountIn为按代币精度表示的十进制数量，slippageBps为0时使用默认滑点
+func (s *BSCService) QuoteTrade(tokenIn, tokenOut, amountIn string, slippageBps uint32) (*TradeQuote, error) {
+	if slippageBps == 0 {
+		slippageBps = DefaultSlippageBps
+	}
+	if slippageBps > MaxSlippageBps {
+		return nil, fmt.Errorf("%w: slippage must not exceed %d bps", ErrInvalidTradeQuery, MaxSlippageBps)
+	}
+	for _, token := range []string{tokenIn, tokenOut} {
+		if !common.IsHexAddress(token) {
+			return nil, fmt.Errorf("%w: invalid token address: %s", ErrInvalidTradeQuery, token)
+		}
+	}
+
+	decimalsIn, err := s.getTokenDecimals(tokenIn)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decimals of %s: %w", tokenIn, err)
+	}
+	decimalsOut, err := s.getTokenDecimals(tokenOut)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decimals of %s: %w", tokenOut, err)
+	}
+
+	amountInRaw, err := parseUnits(amountIn, decimalsIn)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid amount: %v", ErrInvalidTradeQuery, err)
+	}
+	if amountInRaw.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidTradeQuery)
+	}
+
+	route, err := s.findBestPriceRoute(common.HexToAddress(tokenIn), common.HexToAddress(tokenOut), amountInRaw)
+	if err != nil {
+		return nil, err
+	}
+	amountOutRaw := route.amountOut()
+
+	// 中间价（最小单位之比）
+	spotRaw, err := s.getRouteSpotPrice(route)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get spot price: %w", err)
+	}
+
+	// 价格影响 = (按中间价应得数量 - 实际数量) / 按中间价应得数量
+	expectedOut := new(big.Rat).Mul(spotRaw, new(big.Rat).SetInt(amountInRaw))
+	priceImpact := new(big.Rat)
+	if expectedOut.Sign() > 0 {
+		priceImpact.Sub(expectedOut, new(big.Rat).SetInt(amountOutRaw))
+		priceImpact.Quo(priceImpact, expectedOut)
+		priceImpact.Mul(priceImpact, big.NewRat(100, 1))
+	}
+
+	// 最少获得数量 = 输出数量 * (1 - 滑点)
+	minimumReceived := new(big.Int).Mul(amountOutRaw, big.NewInt(int64(10000-slippageBps)))
+	minimumReceived.Quo(minimumReceived, big.NewInt(10000))
+
+	// 将最小单位价格换算为按精度调整后的价格
+	scale := new(big.Rat).SetFrac(pow10(decimalsIn), pow10(decimalsOut))
+	executionPrice := new(big.Rat).SetFrac(amountOutRaw, amountInRaw)
+	executionPrice.Mul(executionPrice, scale)
+	spotPrice := new(big.Rat).Mul(spotRaw, scale)
+
+	return &TradeQuote{
+		TokenIn:            tokenIn,
+		TokenOut:           tokenOut,
+		AmountIn:           formatUnits(amountInRaw, decimalsIn),
+		AmountInRaw:        amountInRaw.String(),
+		AmountOut:          formatUnits(amountOutRaw, decimalsOut),
+		AmountOutRaw:       amountOutRaw.String(),
+		ExecutionPrice:     formatRat(executionPrice, 18),
+		SpotPrice:          formatRat(spotPrice, 18),
+		PriceImpact:        formatRat(priceImpact, 4),
+		SlippageBps:        slippageBps,
+		MinimumReceived:    formatUnits(minimumReceived, decimalsOut),
+		MinimumReceivedRaw: minimumReceived.String(),
+		Route:              route.pathStrings(),
+		RoutePools:         route.poolLabels(),
+	}, nil
+}
+
+// getRouteSpotPrice 计算兑换路径的中间价：每个输入最小单位可兑换的输出最小单位数量
+func (s *BSCService) getRouteSpotPrice(route *routeQuote) (*big.Rat, error) {
+	price := big.NewRat(1, 1)
+	for i, hop := range route.hops {
+		tokenIn, tokenOut := route.path[i], route.path[i+1]
+		hopPrice, err := s.getHopSpotPrice(tokenIn, tokenOut, hop)
+		if err != nil {
+			return nil, err
+		}
+		price.Mul(price, hopPrice)
+	}
+	return price, nil
+}
+
+// getHopSpotPrice 计算单跳池子的中间价（最小单位之比）
+func (s *BSCService) getHopSpotPrice(tokenIn, tokenOut common.Address, hop routeHop) (*big.Rat, error) {
+	// V2：储备量之比
+	if hop.pool == (common.Address{}) {
+		reserves, err := s.getPairReserves(tokenIn.Hex(), tokenOut.Hex())
+		if err != nil {
+			return nil, err
+		}
+		reserveIn, reserveOut := reserves.reservesFor(tokenIn)
+		if reserveIn.Sign() == 0 {
+			return nil, fmt.Errorf("pair %s has no liquidity", reserves.pair.Hex())
+		}
+		return new(big.Rat).SetFrac(reserveOut, reserveIn), nil
+	}
+
+	// V3：由sqrtPriceX96得到token1/token0的价格
+	slot0, err := s.callContract(v3PoolABI, hop.pool, "slot0")
+	if err != nil {
+		return nil, err
+	}
+	price := v3SpotPrice(slot0[0].(*big.Int), 0, 0)
+	if price.Sign() == 0 {
+		return nil, fmt.Errorf("pool %s has no price", hop.pool.Hex())
+	}
+
+	token0, _ := sortTokens(tokenIn, tokenOut)
+	if tokenIn != token0 {
+		price.Inv(price)
+	}
+	return price, nil
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestQuoteTradePriceImpact(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Thin Token", "THIN", 6)
+	chain.addPair(token, tokens["WBNB"], 1_000, 10)
+
+	service := newTestBSCService(chain)
+	quote, err := service.QuoteTrade(token.Hex(), tokens["WBNB"].Hex(), "100", 100)
+	require.NoError(t, err)
+
+	expected := v2AmountOut(
+		new(big.Int).Mul(big.NewInt(100), pow10(6)),
+		new(big.Int).Mul(big.NewInt(1_000), pow10(6)),
+		new(big.Int).Mul(big.NewInt(10), pow10(18)),
+	)
+	assert.Equal(t, "100", quote.AmountIn)
+	assert.Equal(t, "100000000", quote.AmountInRaw)
+	assert.Equal(t, expected.String(), quote.AmountOutRaw)
+	assert.Equal(t, formatUnits(expected, 18), quote.AmountOut)
+	assert.Equal(t, "0.01", quote.SpotPrice)
+	assertDecimalBetween(t, quote.ExecutionPrice, "0.00907", "0.00908")
+
+	// 100 THIN 按中间价应得1 WBNB，实际约0.907 WBNB
+	assertDecimalBetween(t, quote.PriceImpact, "9.29", "9.30")
+
+	minimum := new(big.Int).Mul(expected, big.NewInt(9900))
+	minimum.Quo(minimum, big.NewInt(10000))
+	assert.Equal(t, uint32(100), quote.SlippageBps)
+	assert.Equal(t, minimum.String(), quote.MinimumReceivedRaw)
+	assert.Equal(t, []string{PancakeSwapV2Protocol}, quote.RoutePools)
+}
+
+func TestQuoteTradeSmallAmountOnlyPaysFee(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service := newTestBSCService(chain)
+
+	quote, err := service.QuoteTrade(tokens["WBNB"].Hex(), tokens["USDT"].Hex(), "0.001", 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, uint32(DefaultSlippageBps), quote.SlippageBps)
+	assert.Equal(t, "300", quote.SpotPrice)
+	// 数量极小时价格影响接近0.25%的手续费
+	assertDecimalBetween(t, quote.PriceImpact, "0.25", "0.2501")
+}
+
+func TestQuoteTradeV3Hop(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "V3 Token", "VT", 9)
+	chain.addV3Pool(PancakeSwapV3Factory, token, tokens["WBNB"], 500, 1_000_000, 500_000)
+
+	service := newTestBSCService(chain)
+	quote, err := service.QuoteTrade(tokens["WBNB"].Hex(), token.Hex(), "1", 0)
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{"pancakeswap-v3/500"}, quote.RoutePools)
+	assertDecimalBetween(t, quote.SpotPrice, "1.9999", "2.0001")
+	assertDecimalBetween(t, quote.PriceImpact, "0.05", "0.051")
+}
+
+func TestQuoteTradeInvalidInput(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service := newTestBSCService(chain)
+	wbnb, usdt := tokens["WBNB"].Hex(), tokens["USDT"].Hex()
+
+	_, err := service.QuoteTrade(wbnb, usdt, "1", MaxSlippageBps+1)
+	assert.ErrorIs(t, err, ErrInvalidTradeQuery)
+
+	_, err = service.QuoteTrade(wbnb, usdt, "0", 0)
+	assert.ErrorIs(t, err, ErrInvalidTradeQuery)
+
+	_, err = service.QuoteTrade(wbnb, usdt, "abc", 0)
+	assert.ErrorIs(t, err, ErrInvalidTradeQuery)
+
+	_, err = service.QuoteTrade(wbnb, usdt, "0.0000000000000000001", 0)
+	assert.ErrorIs(t, err, ErrInvalidTradeQuery)
+
+	_, err = service.QuoteTrade("wbnb", usdt, "1", 0)
+	assert.ErrorIs(t, err, ErrInvalidTradeQuery)
+}
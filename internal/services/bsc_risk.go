@@ -0,0 +1,600 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+	"unicode"
+
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 代币风险分析参数
+const (
+	DefaultRiskBuyAmount = "0.1" // 默认模拟买入的BNB数量
+	highTaxPercent       = 50    // 买卖税率达到该百分比视为高风险
+	mediumTaxPercent     = 10    // 买卖税率超过该百分比视为中风险
+)
+
+// ErrInvalidRiskQuery 风险分析参数无效，如代币地址、买入数量或ABI
+var ErrInvalidRiskQuery = errors.New("invalid token risk query")
+
+// 风险等级
+const (
+	RiskLevelLow    = "low"
+	RiskLevelMedium = "medium"
+	RiskLevelHigh   = "high"
+)
+
+// 合约所有者特权类别
+const (
+	PrivilegeMint           = "mint"
+	PrivilegeBlacklist      = "blacklist"
+	PrivilegePause          = "pause"
+	PrivilegeTradingControl = "trading_control"
+	PrivilegeModifyFees     = "modify_fees"
+	PrivilegeModifyLimits   = "modify_limits"
+)
+
+// privilegeOrder 特权在报告中的顺序
+var privilegeOrder = []string{
+	PrivilegeMint,
+	PrivilegeBlacklist,
+	PrivilegePause,
+	PrivilegeTradingControl,
+	PrivilegeModifyFees,
+	PrivilegeModifyLimits,
+}
+
+// privilegeSignatures 各类特权对应的常见函数签名，用于匹配字节码中的函数选择器
+var privilegeSignatures = map[string][]string{
+	PrivilegeMint: {
+		"mint(address,uint256)",
+		"mint(uint256)",
+		"mintTo(address,uint256)",
+	},
+	PrivilegeBlacklist: {
+		"blacklist(address)",
+		"blacklistAddress(address,bool)",
+		"addToBlacklist(address)",
+		"setBlacklist(address,bool)",
+		"addBots(address[])",
+		"setBots(address[])",
+		"setBot(address,bool)",
+		"blockBots(address[])",
+	},
+	PrivilegePause: {
+		"pause()",
+		"setPaused(bool)",
+	},
+	PrivilegeTradingControl: {
+		"enableTrading()",
+		"openTrading()",
+		"setTradingEnabled(bool)",
+		"setTrading(bool)",
+	},
+	PrivilegeModifyFees: {
+		"setFee(uint256)",
+		"setFees(uint256,uint256)",
+		"setTaxFee(uint256)",
+		"setTaxFeePercent(uint256)",
+		"setBuyFee(uint256)",
+		"setSellFee(uint256)",
+		"updateFees(uint256,uint256)",
+	},
+	PrivilegeModifyLimits: {
+		"setMaxTxAmount(uint256)",
+		"setMaxTxPercent(uint256)",
+		"setMaxWalletSize(uint256)",
+		"setMaxWallet(uint256)",
+	},
+}
+
+// privilegeSelectors 函数选择器到特权类别的映射
+var privilegeSelectors = func() map[[4]byte]string {
+	selectors := make(map[[4]byte]string)
+	for privilege, signatures := range privilegeSignatures {
+		for _, signature := range signatures {
+			var selector [4]byte
+			copy(selector[:], crypto.Keccak256([]byte(signature))[:4])
+			selectors[selector] = privilege
+		}
+	}
+	return selectors
+}()
+
+// tokenRiskABI 风险分析读取的常见只读方法，不同代币实现的方法名不同
+const tokenRiskABI = `[
+	{"inputs": [], "name": "owner", "outputs": [{"name": "", "type": "address"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "getOwner", "outputs": [{"name": "", "type": "address"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "_maxTxAmount", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "maxTxAmount", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "maxTransactionAmount", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "_maxWalletSize", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "maxWalletSize", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "maxWallet", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "maxWalletAmount", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"},
+	{"inputs": [], "name": "_maxWalletToken", "outputs": [{"name": "", "type": "uint256"}], "stateMutability": "view", "type": "function"}
+]`
+
+// 按顺序尝试的限额和所有者查询方法
+var (
+	maxTxGetters     = []string{"_maxTxAmount", "maxTxAmount", "maxTransactionAmount"}
+	maxWalletGetters = []string{"_maxWalletSize", "maxWalletSize", "maxWallet", "maxWalletAmount", "_maxWalletToken"}
+	ownerGetters     = []string{"owner", "getOwner"}
+)
+
+// deadAddress 常用于放弃所有权的销毁地址
+var deadAddress = common.HexToAddress("0x000000000000000000000000000000000000dEaD")
+
+// TokenRiskRequest 代币风险分析请求
+type TokenRiskRequest struct {
+	Token  string
+	Amount string // 模拟买入的BNB数量，为空时使用 DefaultRiskBuyAmount
+	ABI    string // 代币合约ABI（JSON），为空时从字节码中识别函数选择器
+}
+
+// TokenRiskReport 代币风险分析报告
+type TokenRiskReport struct {
+	Token     string   `json:"token"`
+	Name      string   `json:"name"`
+	Symbol    string   `json:"symbol"`
+	Route     []string `json:"route"`      // 模拟买入路径，卖出使用反向路径
+	BuyAmount string   `json:"buy_amount"` // 模拟买入的BNB数量
+
+	IsHoneypot  bool   `json:"is_honeypot"`
+	CanBuy      bool   `json:"can_buy"`
+	CanSell     bool   `json:"can_sell"`
+	BuyError    string `json:"buy_error,omitempty"`
+	SellError   string `json:"sell_error,omitempty"`
+	BuyTax      string `json:"buy_tax"`      // 有效买入税率（百分比）
+	SellTax     string `json:"sell_tax"`     // 有效卖出税率（百分比）
+	TransferTax string `json:"transfer_tax"` // 钱包间转账税率（百分比）
+	BuyGas      uint64 `json:"buy_gas"`
+	SellGas     uint64 `json:"sell_gas"`
+
+	MaxTxAmount     string `json:"max_tx_amount,omitempty"`     // 单笔交易上限，未检测到时为空
+	MaxWalletAmount string `json:"max_wallet_amount,omitempty"` // 单个钱包持仓上限，未检测到时为空
+
+	Owner              string   `json:"owner,omitempty"`
+	OwnershipRenounced bool     `json:"ownership_renounced"`
+	Privileges         []string `json:"privileges"`
+	PrivilegeSource    string   `json:"privilege_source"` // abi 或 bytecode
+
+	RiskLevel string   `json:"risk_level"`
+	Risks     []string `json:"risks"`
+}
+
+// AnalyzeTokenRisk 分析代币的貔貅（无法卖出）风险、买卖税率、交易限额和所有者特权
+// 买卖通过带状态覆盖的eth_call在PancakeSwap V2 Router上模拟执行，不会发送交易
+func (s *BSCService) AnalyzeTokenRisk(req TokenRiskRequest) (*TokenRiskReport, error) {
+	if !common.IsHexAddress(req.Token) {
+		return nil, fmt.Errorf("%w: invalid token address: %s", ErrInvalidRiskQuery, req.Token)
+	}
+	token := common.HexToAddress(req.Token)
+	wbnb := common.HexToAddress(WBNBAddress)
+	if token == wbnb {
+		return nil, fmt.Errorf("%w: cannot analyze WBNB", ErrInvalidRiskQuery)
+	}
+
+	amountText := req.Amount
+	if amountText == "" {
+		amountText = DefaultRiskBuyAmount
+	}
+	amount, err := parseUnits(amountText, 18)
+	if err != nil {
+		return nil, fmt.Errorf("%w: invalid amount: %v", ErrInvalidRiskQuery, err)
+	}
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("%w: amount must be positive", ErrInvalidRiskQuery)
+	}
+
+	tokenInfo, err := s.GetTokenInfo(token.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token info: %w", err)
+	}
+
+	route, err := s.findBestRoute(wbnb, token, amount)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &TokenRiskReport{
+		Token:       token.Hex(),
+		Name:        tokenInfo.Name,
+		Symbol:      tokenInfo.Symbol,
+		Route:       route.pathStrings(),
+		BuyAmount:   formatUnits(amount, 18),
+		BuyTax:      "0",
+		SellTax:     "0",
+		TransferTax: "0",
+		Privileges:  []string{},
+		Risks:       []string{},
+	}
+
+	if err := s.simulateTrades(report, token, route, amount, tokenInfo.Decimals); err != nil {
+		return nil, err
+	}
+
+	report.MaxTxAmount = s.readTokenAmount(token, maxTxGetters, tokenInfo.Decimals)
+	report.MaxWalletAmount = s.readTokenAmount(token, maxWalletGetters, tokenInfo.Decimals)
+	s.readOwner(report, token)
+
+	if err := s.detectPrivileges(report, token, req.ABI); err != nil {
+		return nil, err
+	}
+
+	assessRisk(report)
+	return report, nil
+}
+
+// simulateTrades 模拟买入后卖出以及钱包间转账，计算各环节的有效税率
+func (s *BSCService) simulateTrades(report *TokenRiskReport, token common.Address, route *routeQuote, amount *big.Int, decimals uint8) error {
+	routerABI, err := parseABI(pancakeRouterSwapABI)
+	if err != nil {
+		return fmt.Errorf("failed to parse router ABI: %w", err)
+	}
+	quoteABI, err := parseABI(pancakeRouterABI)
+	if err != nil {
+		return fmt.Errorf("failed to parse router ABI: %w", err)
+	}
+	tokenABI, err := parseABI(erc20ABI)
+	if err != nil {
+		return fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
+
+	router := common.HexToAddress(PancakeSwapV2Router)
+	wbnb := common.HexToAddress(WBNBAddress)
+	deadline := big.NewInt(time.Now().Add(DefaultSwapDeadline).Unix())
+	sellPath := make([]common.Address, len(route.path))
+	for i, addr := range route.path {
+		sellPath[len(route.path)-1-i] = addr
+	}
+
+	buyData, err := routerABI.Pack("swapExactETHForTokensSupportingFeeOnTransferTokens", big.NewInt(0), route.path, simulatorAddress, deadline)
+	if err != nil {
+		return fmt.Errorf("failed to pack buy call: %w", err)
+	}
+	buy := simCall{target: router, value: amount, data: buyData}
+	balanceCall := func(owner common.Address) simCall {
+		data, _ := tokenABI.Pack("balanceOf", owner)
+		return simCall{target: token, data: data}
+	}
+
+	// 买入并读取实际到账数量
+	ctx := context.Background()
+	results, err := s.simulate(ctx, amount, []simCall{buy, balanceCall(simulatorAddress)})
+	if err != nil {
+		return err
+	}
+	report.BuyGas = results[0].gasUsed
+	if !results[0].success {
+		report.BuyError = results[0].revertReason()
+		return nil
+	}
+	report.CanBuy = true
+
+	received := decodeUint256(results[1].data)
+	report.BuyTax = formatRat(taxPercent(route.amountOut(), received), 2)
+	if received.Sign() == 0 {
+		report.SellError = "no tokens received from buy"
+		return nil
+	}
+
+	// 买入后卖出全部代币，在卖出前按买入后的储备量查询预期输出
+	approveData, err := tokenABI.Pack("approve", router, received)
+	if err != nil {
+		return fmt.Errorf("failed to pack approve call: %w", err)
+	}
+	quoteData, err := quoteABI.Pack("getAmountsOut", received, sellPath)
+	if err != nil {
+		return fmt.Errorf("failed to pack quote call: %w", err)
+	}
+	sellData, err := routerABI.Pack("swapExactTokensForTokensSupportingFeeOnTransferTokens", received, big.NewInt(0), sellPath, simulatorAddress, deadline)
+	if err != nil {
+		return fmt.Errorf("failed to pack sell call: %w", err)
+	}
+	wbnbBalanceData, _ := tokenABI.Pack("balanceOf", simulatorAddress)
+
+	results, err = s.simulate(ctx, amount, []simCall{
+		buy,
+		{target: token, data: approveData},
+		{target: router, data: quoteData},
+		{target: router, data: sellData},
+		{target: wbnb, data: wbnbBalanceData},
+	})
+	if err != nil {
+		return err
+	}
+	report.SellGas = results[3].gasUsed
+	switch {
+	case !results[1].success:
+		report.SellError = "approve failed: " + results[1].revertReason()
+	case !results[2].success:
+		report.SellError = "quote failed: " + results[2].revertReason()
+	case !results[3].success:
+		report.SellError = results[3].revertReason()
+	default:
+		amounts, err := quoteABI.Unpack("getAmountsOut", results[2].data)
+		if err != nil {
+			return fmt.Errorf("failed to decode sell quote: %w", err)
+		}
+		expected := amounts[0].([]*big.Int)
+		sold := decodeUint256(results[4].data)
+		report.SellTax = formatRat(taxPercent(expected[len(expected)-1], sold), 2)
+		report.CanSell = sold.Sign() > 0
+		if !report.CanSell {
+			report.SellError = "sell returned no BNB"
+		}
+	}
+
+	// 买入后转账给另一个钱包，测量转账税
+	transferData, err := tokenABI.Pack("transfer", simulationWallet, received)
+	if err != nil {
+		return fmt.Errorf("failed to pack transfer call: %w", err)
+	}
+	results, err = s.simulate(ctx, amount, []simCall{
+		buy,
+		{target: token, data: transferData},
+		balanceCall(simulationWallet),
+	})
+	if err != nil {
+		return err
+	}
+	if !results[1].success {
+		report.TransferTax = "100"
+		report.Risks = append(report.Risks, fmt.Sprintf("wallet transfers revert: %s", results[1].revertReason()))
+	} else {
+		report.TransferTax = formatRat(taxPercent(received, decodeUint256(results[2].data)), 2)
+	}
+
+	logger.Debugf("Simulated trades of %s: bought %s, buy tax %s%%, sell tax %s%%",
+		token.Hex(), formatUnits(received, decimals), report.BuyTax, report.SellTax)
+	return nil
+}
+
+// decodeUint256 解析32字节的uint256返回值，数据无效时返回0
+func decodeUint256(data []byte) *big.Int {
+	if len(data) < 32 {
+		return new(big.Int)
+	}
+	return new(big.Int).SetBytes(data[:32])
+}
+
+// taxPercent 按预期数量和实际数量计算有效税率（百分比），实际不少于预期时为0
+func taxPercent(expected, actual *big.Int) *big.Rat {
+	if expected.Sign() <= 0 || actual.Cmp(expected) >= 0 {
+		return new(big.Rat)
+	}
+	lost := new(big.Int).Sub(expected, actual)
+	tax := new(big.Rat).SetFrac(lost, expected)
+	return tax.Mul(tax, big.NewRat(100, 1))
+}
+
+// readTokenAmount 依次尝试只读方法，返回第一个成功的代币数量，均失败时返回空字符串
+func (s *BSCService) readTokenAmount(token common.Address, getters []string, decimals uint8) string {
+	for _, getter := range getters {
+		output, err := s.callContract(tokenRiskABI, token, getter)
+		if err != nil || len(output) == 0 {
+			continue
+		}
+		return formatUnits(output[0].(*big.Int), decimals)
+	}
+	return ""
+}
+
+// readOwner 读取合约所有者，零地址或销毁地址视为已放弃所有权
+func (s *BSCService) readOwner(report *TokenRiskReport, token common.Address) {
+	for _, getter := range ownerGetters {
+		output, err := s.callContract(tokenRiskABI, token, getter)
+		if err != nil || len(output) == 0 {
+			continue
+		}
+		owner := output[0].(common.Address)
+		report.Owner = owner.Hex()
+		report.OwnershipRenounced = owner == (common.Address{}) || owner == deadAddress
+		return
+	}
+	// 没有所有者的合约不存在所有者特权
+	report.OwnershipRenounced = true
+}
+
+// detectPrivileges 从ABI的函数名或字节码的函数选择器中识别所有者特权
+func (s *BSCService) detectPrivileges(report *TokenRiskReport, token common.Address, abiJSON string) error {
+	found := make(map[string]bool)
+
+	if abiJSON != "" {
+		contractABI, err := abi.JSON(strings.NewReader(abiJSON))
+		if err != nil {
+			return fmt.Errorf("%w: invalid ABI: %v", ErrInvalidRiskQuery, err)
+		}
+		report.PrivilegeSource = "abi"
+		for _, method := range contractABI.Methods {
+			if method.IsConstant() {
+				continue
+			}
+			// 优先按已知的函数签名匹配，再按函数名判断
+			if privilege, ok := privilegeSelectors[[4]byte(method.ID)]; ok {
+				found[privilege] = true
+			} else if privilege := privilegeFromName(method.RawName); privilege != "" {
+				found[privilege] = true
+			}
+		}
+	} else {
+		code, err := s.client.CodeAt(context.Background(), token, nil)
+		if err != nil {
+			return fmt.Errorf("failed to get contract code: %w", err)
+		}
+		report.PrivilegeSource = "bytecode"
+		for _, selector := range codeSelectors(code) {
+			if privilege, ok := privilegeSelectors[selector]; ok {
+				found[privilege] = true
+			}
+		}
+	}
+
+	for _, privilege := range privilegeOrder {
+		if found[privilege] {
+			report.Privileges = append(report.Privileges, privilege)
+		}
+	}
+	return nil
+}
+
+// privilegeFromName 按函数名判断特权类别
+// 函数名按驼峰和下划线拆分为单词，增发和机器人名单只匹配完整单词，避免 isBotProtected、mintingFinished 等误判
+func privilegeFromName(name string) string {
+	words := identifierWords(name)
+	if len(words) == 0 {
+		return ""
+	}
+	verb, lower := words[0], strings.Join(words, "")
+	setter := verb == "set" || verb == "update" || verb == "change"
+	hasWord := func(targets ...string) bool {
+		for _, word := range words[1:] {
+			for _, target := range targets {
+				if word == target {
+					return true
+				}
+			}
+		}
+		return false
+	}
+
+	switch {
+	case verb == "mint":
+		return PrivilegeMint
+	case strings.Contains(lower, "blacklist") || strings.Contains(lower, "blocklist"):
+		return PrivilegeBlacklist
+	case (setter || verb == "add" || verb == "block" || verb == "remove" || verb == "delete") && hasWord("bot", "bots"):
+		return PrivilegeBlacklist
+	case strings.Contains(lower, "pause"):
+		return PrivilegePause
+	case strings.Contains(lower, "trading"):
+		return PrivilegeTradingControl
+	case setter && (strings.Contains(lower, "fee") || strings.Contains(lower, "tax")):
+		return PrivilegeModifyFees
+	case setter && (strings.Contains(lower, "maxtx") || strings.Contains(lower, "maxwallet") || strings.Contains(lower, "maxtransaction")):
+		return PrivilegeModifyLimits
+	}
+	return ""
+}
+
+// identifierWords 将函数名按下划线和驼峰边界拆分为小写单词，连续大写视为一个单词
+func identifierWords(name string) []string {
+	var words []string
+	var word []rune
+	runes := []rune(name)
+	for i, r := range runes {
+		if r == '_' {
+			if len(word) > 0 {
+				words = append(words, strings.ToLower(string(word)))
+				word = nil
+			}
+			continue
+		}
+		if unicode.IsUpper(r) && len(word) > 0 {
+			prev := runes[i-1]
+			nextLower := i+1 < len(runes) && unicode.IsLower(runes[i+1])
+			if !unicode.IsUpper(prev) || nextLower {
+				words = append(words, strings.ToLower(string(word)))
+				word = nil
+			}
+		}
+		word = append(word, r)
+	}
+	if len(word) > 0 {
+		words = append(words, strings.ToLower(string(word)))
+	}
+	return words
+}
+
+// codeSelectors 提取字节码中所有PUSH4指令的操作数，Solidity函数分发表以PUSH4压入函数选择器
+func codeSelectors(code []byte) [][4]byte {
+	var selectors [][4]byte
+	for pc := 0; pc < len(code); pc++ {
+		op := vm.OpCode(code[pc])
+		if !op.IsPush() {
+			continue
+		}
+		size := int(op - vm.PUSH1 + 1)
+		if op == vm.PUSH4 && pc+4 < len(code) {
+			var selector [4]byte
+			copy(selector[:], code[pc+1:pc+5])
+			selectors = append(selectors, selector)
+		}
+		pc += size
+	}
+	return selectors
+}
+
+// assessRisk 根据模拟结果和特权评估风险等级
+func assessRisk(report *TokenRiskReport) {
+	high, medium := false, false
+	buyTax, _ := new(big.Rat).SetString(report.BuyTax)
+	sellTax, _ := new(big.Rat).SetString(report.SellTax)
+	transferTax, _ := new(big.Rat).SetString(report.TransferTax)
+
+	switch {
+	case !report.CanBuy:
+		high = true
+		report.Risks = append(report.Risks, fmt.Sprintf("buy simulation failed: %s", report.BuyError))
+	case !report.CanSell:
+		high = true
+		report.IsHoneypot = true
+		report.Risks = append(report.Risks, fmt.Sprintf("sell simulation failed: %s", report.SellError))
+	}
+
+	for _, tax := range []struct {
+		name  string
+		value *big.Rat
+	}{{"buy", buyTax}, {"sell", sellTax}, {"transfer", transferTax}} {
+		switch {
+		case tax.value.Cmp(big.NewRat(highTaxPercent, 1)) >= 0:
+			high = true
+			report.Risks = append(report.Risks, fmt.Sprintf("%s tax is %s%%", tax.name, formatRat(tax.value, 2)))
+		case tax.value.Cmp(big.NewRat(mediumTaxPercent, 1)) > 0:
+			medium = true
+			report.Risks = append(report.Risks, fmt.Sprintf("%s tax is %s%%", tax.name, formatRat(tax.value, 2)))
+		}
+	}
+	if sellTax.Cmp(big.NewRat(highTaxPercent, 1)) >= 0 {
+		report.IsHoneypot = true
+	}
+
+	if report.MaxTxAmount != "" {
+		report.Risks = append(report.Risks, fmt.Sprintf("max transaction amount is %s", report.MaxTxAmount))
+	}
+	if report.MaxWalletAmount != "" {
+		report.Risks = append(report.Risks, fmt.Sprintf("max wallet amount is %s", report.MaxWalletAmount))
+	}
+
+	// 所有权已放弃时特权无法再被使用
+	if !report.OwnershipRenounced {
+		for _, privilege := range report.Privileges {
+			if privilege == PrivilegeMint {
+				high = true
+			} else {
+				medium = true
+			}
+			report.Risks = append(report.Risks, fmt.Sprintf("owner has %s privilege", privilege))
+		}
+	}
+
+	switch {
+	case high:
+		report.RiskLevel = RiskLevelHigh
+	case medium:
+		report.RiskLevel = RiskLevelMedium
+	default:
+		report.RiskLevel = RiskLevelLow
+	}
+}
@@ -0,0 +1,286 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"encoding/json"
+	"math/big"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSimulatorCode(t *testing.T) {
+	statedb := newFakeEVMState()
+
+	// 返回调用方和转账金额
+	echo := common.HexToAddress("0x00000000000000000000000000000000000000e1")
+	statedb.SetCode(echo, mustCompileAsm(`
+		CALLER
+		PUSH 0
+		MSTORE
+		CALLVALUE
+		PUSH 32
+		MSTORE
+		PUSH 64
+		PUSH 0
+		RETURN
+	`))
+	// 回滚并返回4字节数据
+	reverter := common.HexToAddress("0x00000000000000000000000000000000000000e2")
+	statedb.SetCode(reverter, mustCompileAsm(`
+		PUSH 0xdeadbeef
+		PUSH 224
+		SHL
+		PUSH 0
+		MSTORE
+		PUSH 4
+		PUSH 0
+		REVERT
+	`))
+	// 计数器，每次调用加1并返回新值
+	counter := common.HexToAddress("0x00000000000000000000000000000000000000e3")
+	statedb.SetCode(counter, mustCompileAsm(`
+		PUSH 0
+		SLOAD
+		PUSH 1
+		ADD
+		DUP1
+		PUSH 0
+		SSTORE
+		PUSH 0
+		MSTORE
+		PUSH 32
+		PUSH 0
+		RETURN
+	`))
+
+	simulator := common.BytesToAddress([]byte("contract"))
+	statedb.AddBalance(simulator, big.NewInt(100))
+
+	calls := []simCall{
+		{target: echo, value: big.NewInt(5), data: []byte{0x01}},
+		{target: reverter},
+		{target: counter},
+		{target: counter},
+	}
+	output, err := executeEVM(statedb, simulatorCode, encodeSimCalls(calls))
+	require.NoError(t, err)
+
+	results, err := decodeSimResults(output)
+	require.NoError(t, err)
+	require.Len(t, results, 4)
+
+	assert.True(t, results[0].success)
+	assert.Positive(t, results[0].gasUsed)
+	assert.Equal(t, simulator, common.BytesToAddress(results[0].data[:32]))
+	assert.Equal(t, int64(5), new(big.Int).SetBytes(results[0].data[32:]).Int64())
+
+	assert.False(t, results[1].success)
+	assert.Equal(t, "deadbeef", hex.EncodeToString(results[1].data))
+
+	// 后面的调用可以看到前面调用的状态变化
+	assert.True(t, results[2].success)
+	assert.Equal(t, int64(1), decodeUint256(results[2].data).Int64())
+	assert.Equal(t, int64(2), decodeUint256(results[3].data).Int64())
+}
+
+func TestCallContractWithOverrides(t *testing.T) {
+	// 状态覆盖作为eth_call的第三个参数发送
+	var params []json.RawMessage
+	node := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		var req struct {
+			ID     json.RawMessage   `json:"id"`
+			Method string            `json:"method"`
+			Params []json.RawMessage `json:"params"`
+		}
+		require.NoError(t, json.NewDecoder(r.Body).Decode(&req))
+		assert.Equal(t, "eth_call", req.Method)
+		params = req.Params
+		json.NewEncoder(w).Encode(map[string]interface{}{"jsonrpc": "2.0", "id": req.ID, "result": "0x0102"})
+	}))
+	defer node.Close()
+
+	client, err := dialBSCClient(node.URL)
+	require.NoError(t, err)
+	output, err := client.CallContractWithOverrides(context.Background(), ethereum.CallMsg{
+		From: simulationCaller,
+		To:   &simulatorAddress,
+		Data: []byte{0xab},
+	}, map[common.Address]overrideAccount{
+		simulatorAddress: {Code: []byte{0x60, 0x00}, Balance: (*hexutil.Big)(big.NewInt(16))},
+	})
+	require.NoError(t, err)
+	assert.Equal(t, []byte{0x01, 0x02}, output)
+
+	require.Len(t, params, 3)
+	assert.JSONEq(t, `{"from":"`+strings.ToLower(simulationCaller.Hex())+`","to":"`+strings.ToLower(simulatorAddress.Hex())+`","input":"0xab"}`, strings.ToLower(string(params[0])))
+	assert.JSONEq(t, `"latest"`, string(params[1]))
+	assert.JSONEq(t, `{"`+strings.ToLower(simulatorAddress.Hex())+`":{"code":"0x6000","balance":"0x10"}}`, strings.ToLower(string(params[2])))
+}
+
+// newRiskTestChain 创建包含一个与WBNB组成交易对的待分析代币的模拟链
+func newRiskTestChain() (*fakeChain, common.Address) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Risk Token", "RISK", 18)
+	chain.addPair(token, tokens["WBNB"], 1_000_000, 1_000)
+	return chain, token
+}
+
+// selectorCode 生成以PUSH4压入指定函数选择器的字节码
+func selectorCode(signatures ...string) []byte {
+	var code []byte
+	for _, signature := range signatures {
+		code = append(code, byte(vm.PUSH4))
+		code = append(code, crypto.Keccak256([]byte(signature))[:4]...)
+		code = append(code, byte(vm.EQ))
+	}
+	return code
+}
+
+func TestAnalyzeTokenRiskClean(t *testing.T) {
+	chain, token := newRiskTestChain()
+	pair, _ := chain.findPair(token, common.HexToAddress(WBNBAddress))
+	reserve0 := chain.pairs[pair].reserve0
+	service := newTestBSCService(chain)
+
+	report, err := service.AnalyzeTokenRisk(TokenRiskRequest{Token: token.Hex()})
+	require.NoError(t, err)
+
+	assert.Equal(t, "RISK", report.Symbol)
+	assert.Equal(t, []string{common.HexToAddress(WBNBAddress).Hex(), token.Hex()}, report.Route)
+	assert.Equal(t, DefaultRiskBuyAmount, report.BuyAmount)
+	assert.True(t, report.CanBuy)
+	assert.True(t, report.CanSell)
+	assert.False(t, report.IsHoneypot)
+	assert.Equal(t, "0", report.BuyTax)
+	assert.Equal(t, "0", report.SellTax)
+	assert.Equal(t, "0", report.TransferTax)
+	assert.Positive(t, report.BuyGas)
+	assert.Positive(t, report.SellGas)
+	assert.Empty(t, report.MaxTxAmount)
+	assert.True(t, report.OwnershipRenounced)
+	assert.Empty(t, report.Privileges)
+	assert.Equal(t, "bytecode", report.PrivilegeSource)
+	assert.Equal(t, RiskLevelLow, report.RiskLevel)
+	assert.Empty(t, report.Risks)
+
+	// 模拟执行不修改链上状态
+	assert.Equal(t, 3, chain.calls["CallContractWithOverrides"])
+	assert.Equal(t, reserve0, chain.pairs[pair].reserve0)
+	assert.Zero(t, chain.tokens[token].balanceOf(simulatorAddress).Sign())
+}
+
+func TestAnalyzeTokenRiskTaxed(t *testing.T) {
+	chain, token := newRiskTestChain()
+	chain.tokens[token].transferFeeBps = 1200
+	service := newTestBSCService(chain)
+
+	report, err := service.AnalyzeTokenRisk(TokenRiskRequest{Token: token.Hex(), Amount: "1"})
+	require.NoError(t, err)
+
+	assert.True(t, report.CanSell)
+	assert.False(t, report.IsHoneypot)
+	assert.Equal(t, "12", report.BuyTax)
+	assertDecimalBetween(t, report.SellTax, "11.9", "12.1")
+	assert.Equal(t, "12", report.TransferTax)
+	assert.Equal(t, RiskLevelMedium, report.RiskLevel)
+	assert.Contains(t, report.Risks, "buy tax is 12%")
+}
+
+func TestAnalyzeTokenRiskHoneypot(t *testing.T) {
+	chain, token := newRiskTestChain()
+	chain.tokens[token].sellBlocked = true
+	service := newTestBSCService(chain)
+
+	report, err := service.AnalyzeTokenRisk(TokenRiskRequest{Token: token.Hex()})
+	require.NoError(t, err)
+
+	assert.True(t, report.CanBuy)
+	assert.False(t, report.CanSell)
+	assert.True(t, report.IsHoneypot)
+	assert.Equal(t, "TransferHelper: TRANSFER_FROM_FAILED", report.SellError)
+	assert.Equal(t, RiskLevelHigh, report.RiskLevel)
+}
+
+func TestAnalyzeTokenRiskPrivileges(t *testing.T) {
+	chain, token := newRiskTestChain()
+	owner := common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	chain.tokens[token].owner = &owner
+	chain.tokens[token].maxTxAmount = new(big.Int).Mul(big.NewInt(1000), pow10(18))
+	chain.tokens[token].maxWalletAmount = new(big.Int).Mul(big.NewInt(5000), pow10(18))
+
+	// PUSH32操作数中的选择器不应被识别
+	pauseSelector := crypto.Keccak256([]byte("pause()"))[:4]
+	code := append([]byte{byte(vm.PUSH32)}, common.LeftPadBytes(pauseSelector, 32)...)
+	code = append(code, selectorCode("transfer(address,uint256)", "mint(address,uint256)", "setBots(address[])")...)
+	chain.tokens[token].code = code
+	service := newTestBSCService(chain)
+
+	report, err := service.AnalyzeTokenRisk(TokenRiskRequest{Token: token.Hex()})
+	require.NoError(t, err)
+
+	assert.Equal(t, owner.Hex(), report.Owner)
+	assert.False(t, report.OwnershipRenounced)
+	assert.Equal(t, "1000", report.MaxTxAmount)
+	assert.Equal(t, "5000", report.MaxWalletAmount)
+	assert.Equal(t, []string{PrivilegeMint, PrivilegeBlacklist}, report.Privileges)
+	assert.Equal(t, RiskLevelHigh, report.RiskLevel)
+
+	// 从ABI识别特权，只读方法不计入
+	report, err = service.AnalyzeTokenRisk(TokenRiskRequest{Token: token.Hex(), ABI: `[
+		{"inputs": [], "name": "pause", "outputs": [], "stateMutability": "nonpayable", "type": "function"},
+		{"inputs": [{"name": "fee", "type": "uint256"}], "name": "setTaxFeePercent", "outputs": [], "stateMutability": "nonpayable", "type": "function"},
+		{"inputs": [{"name": "account", "type": "address"}], "name": "excludeFromFee", "outputs": [], "stateMutability": "nonpayable", "type": "function"},
+		{"inputs": [{"name": "account", "type": "address"}], "name": "isBlacklisted", "outputs": [{"name": "", "type": "bool"}], "stateMutability": "view", "type": "function"}
+	]`})
+	require.NoError(t, err)
+	assert.Equal(t, "abi", report.PrivilegeSource)
+	assert.Equal(t, []string{PrivilegePause, PrivilegeModifyFees}, report.Privileges)
+	assert.Equal(t, RiskLevelMedium, report.RiskLevel)
+
+	// 放弃所有权后特权不再构成风险
+	dead := deadAddress
+	chain.tokens[token].owner = &dead
+	report, err = service.AnalyzeTokenRisk(TokenRiskRequest{Token: token.Hex()})
+	require.NoError(t, err)
+	assert.True(t, report.OwnershipRenounced)
+	assert.Equal(t, RiskLevelLow, report.RiskLevel)
+}
+
+func TestPrivilegeFromName(t *testing.T) {
+	tests := map[string]string{
+		"mint":                  PrivilegeMint,
+		"mintTo":                PrivilegeMint,
+		"_mint":                 PrivilegeMint,
+		"mintingFinished":       "",
+		"finishMinting":         "",
+		"addToBlackList":        PrivilegeBlacklist,
+		"blacklistAddress":      PrivilegeBlacklist,
+		"setBot":                PrivilegeBlacklist,
+		"addBots":               PrivilegeBlacklist,
+		"blockBots":             PrivilegeBlacklist,
+		"setBOT":                PrivilegeBlacklist,
+		"setBottomPrice":        "",
+		"enableBotProtection":   "",
+		"claimRobotRewards":     "",
+		"unpause":               PrivilegePause,
+		"setTradingEnabled":     PrivilegeTradingControl,
+		"setTaxFeePercent":      PrivilegeModifyFees,
+		"excludeFromFee":        "",
+		"setMaxTxAmount":        PrivilegeModifyLimits,
+		"set_max_wallet_amount": PrivilegeModifyLimits,
+	}
+	for name, privilege := range tests {
+		assert.Equal(t, privilege, privilegeFromName(name), name)
+	}
+}
@@ -0,0 +1,193 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"sync"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 默认的路由中间代币
+var defaultBaseTokens = []string{
+	WBNBAddress,
+	USDTAddress,
+	BUSDAddress,
+	USDCAddress,
+	CAKEAddress,
+}
+
+// defaultMaxHops 默认的兑换路径最大跳数
+const defaultMaxHops = 3
+
+// PancakeSwapV2Protocol V2兑换路径使用的协议名称
+const PancakeSwapV2Protocol = "pancakeswap-v2"
+
+// routeHop 兑换路径中的一跳
+type routeHop struct {
+	protocol string         // 协议名称，如 pancakeswap-v2、pancakeswap-v3
+	fee      uint32         // V3池手续费等级（百万分比），V2为0
+	pool     common.Address // V3池地址，V2为空
+}
+
+// label 返回该跳所用池子的描述，如 pancakeswap-v2 或 pancakeswap-v3/2500
+func (h routeHop) label() string {
+	if h.fee == 0 {
+		return h.protocol
+	}
+	return fmt.Sprintf("%s/%d", h.protocol, h.fee)
+}
+
+// routeQuote 兑换路径报价
+type routeQuote struct {
+	path    []common.Address
+	amounts []*big.Int // 路径上每个代币对应的数量，amounts[0]为输入数量
+	hops    []routeHop // 每一跳使用的池子，长度为 len(path)-1
+}
+
+// amountOut 返回路径最终的输出数量
+func (q *routeQuote) amountOut() *big.Int {
+	return q.amounts[len(q.amounts)-1]
+}
+
+// pathStrings 返回路径的地址字符串形式
+func (q *routeQuote) pathStrings() []string {
+	path := make([]string, len(q.path))
+	for i, addr := range q.path {
+		path[i] = addr.Hex()
+	}
+	return path
+}
+
+// poolLabels 返回每一跳所用池子的描述
+func (q *routeQuote) poolLabels() []string {
+	labels := make([]string, len(q.hops))
+	for i, hop := range q.hops {
+		labels[i] = hop.label()
+	}
+	return labels
+}
+
+// joinRoutes 将两段首尾相接的路径拼接为一条路径
+func joinRoutes(first, second *routeQuote) *routeQuote {
+	return &routeQuote{
+		path:    append(append([]common.Address{}, first.path...), second.path[1:]...),
+		amounts: append(append([]*big.Int{}, first.amounts...), second.amounts[1:]...),
+		hops:    append(append([]routeHop{}, first.hops...), second.hops...),
+	}
+}
+
+// v2Hops 生成全部经由PancakeSwap V2的路径跳
+func v2Hops(path []common.Address) []routeHop {
+	hops := make([]routeHop, len(path)-1)
+	for i := range hops {
+		hops[i] = routeHop{protocol: PancakeSwapV2Protocol}
+	}
+	return hops
+}
+
+// candidatePaths 生成经由基础代币的候选兑换路径
+func (s *BSCService) candidatePaths(tokenIn, tokenOut common.Address) [][]common.Address {
+	var bases []common.Address
+	for _, base := range s.baseTokens {
+		if base != tokenIn && base != tokenOut {
+			bases = append(bases, base)
+		}
+	}
+
+	// 直接路径
+	paths := [][]common.Address{{tokenIn, tokenOut}}
+
+	// 经过一个中间代币
+	if s.maxHops >= 2 {
+		for _, base := range bases {
+			paths = append(paths, []common.Address{tokenIn, base, tokenOut})
+		}
+	}
+
+	// 经过两个中间代币
+	if s.maxHops >= 3 {
+		for _, first := range bases {
+			for _, second := range bases {
+				if first != second {
+					paths = append(paths, []common.Address{tokenIn, first, second, tokenOut})
+				}
+			}
+		}
+	}
+
+	return paths
+}
+
+// quotePaths 并发查询候选路径的报价，不存在交易对的路径调用失败，对应结果为nil
+func quotePaths(paths [][]common.Address, quote func(path []common.Address) ([]*big.Int, error)) []*routeQuote {
+	quotes := make([]*routeQuote, len(paths))
+
+	var wg sync.WaitGroup
+	for i, path := range paths {
+		wg.Add(1)
+		go func(i int, path []common.Address) {
+			defer wg.Done()
+			amounts, err := quote(path)
+			if err != nil {
+				return
+			}
+			quotes[i] = &routeQuote{path: path, amounts: amounts, hops: v2Hops(path)}
+		}(i, path)
+	}
+	wg.Wait()
+
+	return quotes
+}
+
+// findBestRoute 在所有候选路径中选择输出数量最多的路径
+func (s *BSCService) findBestRoute(tokenIn, tokenOut common.Address, amountIn *big.Int) (*routeQuote, error) {
+	if tokenIn == tokenOut {
+		return &routeQuote{
+			path:    []common.Address{tokenIn},
+			amounts: []*big.Int{new(big.Int).Set(amountIn)},
+		}, nil
+	}
+
+	quotes := quotePaths(s.candidatePaths(tokenIn, tokenOut), func(path []common.Address) ([]*big.Int, error) {
+		return s.getAmountsOut(amountIn, path)
+	})
+
+	// 输出相同时优先选择跳数更少的路径（候选路径按跳数递增排列）
+	var best *routeQuote
+	for _, quote := range quotes {
+		if quote == nil || quote.amountOut().Sign() <= 0 {
+			continue
+		}
+		if best == nil || quote.amountOut().Cmp(best.amountOut()) > 0 {
+			best = quote
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no route found from %s to %s", tokenIn.Hex(), tokenOut.Hex())
+	}
+	return best, nil
+}
+
+// findBestRouteExactOut 在所有候选路径中选择获得指定输出数量所需输入最少的路径
+func (s *BSCService) findBestRouteExactOut(tokenIn, tokenOut common.Address, amountOut *big.Int) (*routeQuote, error) {
+	quotes := quotePaths(s.candidatePaths(tokenIn, tokenOut), func(path []common.Address) ([]*big.Int, error) {
+		return s.getAmountsIn(amountOut, path)
+	})
+
+	var best *routeQuote
+	for _, quote := range quotes {
+		if quote == nil || quote.amounts[0].Sign() <= 0 {
+			continue
+		}
+		if best == nil || quote.amounts[0].Cmp(best.amounts[0]) < 0 {
+			best = quote
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no route found from %s to %s", tokenIn.Hex(), tokenOut.Hex())
+	}
+	return best, nil
+}
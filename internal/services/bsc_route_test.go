@@ -0,0 +1,100 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"chain/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newRouteTestChain 创建包含所有默认基础代币的模拟链
+func newRouteTestChain() (*fakeChain, map[string]common.Address) {
+	chain := newFakeChain()
+	tokens := map[string]common.Address{
+		"WBNB": chain.addToken(WBNBAddress, "Wrapped BNB", "WBNB", 18),
+		"USDT": chain.addToken(USDTAddress, "Tether USD", "USDT", 18),
+		"BUSD": chain.addToken(BUSDAddress, "BUSD Token", "BUSD", 18),
+		"USDC": chain.addToken(USDCAddress, "USD Coin", "USDC", 18),
+		"CAKE": chain.addToken(CAKEAddress, "PancakeSwap Token", "Cake", 18),
+	}
+	chain.addPair(tokens["WBNB"], tokens["USDT"], 1_000_000, 300_000_000)
+	chain.addPair(tokens["WBNB"], tokens["BUSD"], 1_000_000, 300_000_000)
+	chain.addPair(tokens["CAKE"], tokens["WBNB"], 10_000_000, 100_000)
+	return chain, tokens
+}
+
+func TestGetTokenPriceViaStablecoin(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Stable Only", "SO", 9)
+
+	// 只与USDT配对：1 SO = 3 USDT
+	chain.addPair(token, tokens["USDT"], 1_000_000, 3_000_000)
+
+	service := newTestBSCService(chain)
+	price, err := service.GetTokenPrice(token.Hex(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{token.Hex(), tokens["USDT"].Hex(), tokens["WBNB"].Hex()}, price.Route)
+	assertDecimalBetween(t, price.PriceInBNB, "0.0099", "0.01")
+	assertDecimalBetween(t, price.PriceInUSD, "2.9", "3")
+
+	pair, _ := chain.findPair(token, tokens["USDT"])
+	assert.Equal(t, pair.Hex(), price.LiquidityPool)
+}
+
+func TestFindBestRoutePrefersBestOutput(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000bb", "Multi Pool", "MP", 18)
+
+	// 直接WBNB池很浅，经CAKE的路径流动性更深
+	chain.addPair(token, tokens["WBNB"], 100, 1)
+	chain.addPair(token, tokens["CAKE"], 1_000_000, 10_000_000)
+
+	service := newTestBSCService(chain)
+	amountIn := new(big.Int).Mul(big.NewInt(10), pow10(18))
+	route, err := service.findBestRoute(token, tokens["WBNB"], amountIn)
+	require.NoError(t, err)
+
+	assert.Equal(t, []common.Address{token, tokens["CAKE"], tokens["WBNB"]}, route.path)
+	assert.Len(t, route.amounts, 3)
+
+	// 与直接路径的输出比较
+	direct, err := service.getAmountsOut(amountIn, []common.Address{token, tokens["WBNB"]})
+	require.NoError(t, err)
+	assert.True(t, route.amountOut().Cmp(direct[1]) > 0)
+}
+
+func TestFindBestRouteTwoIntermediates(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000cc", "Busd Pair", "BP", 18)
+	target := chain.addToken("0x00000000000000000000000000000000000000dd", "Bnb Pair", "NP", 18)
+	chain.addPair(token, tokens["BUSD"], 1_000_000, 1_000_000)
+	chain.addPair(target, tokens["WBNB"], 1_000_000, 1_000_000)
+
+	service := newTestBSCService(chain)
+	route, err := service.findBestRoute(token, target, pow10(18))
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{token, tokens["BUSD"], tokens["WBNB"], target}, route.path)
+}
+
+func TestFindBestRouteRespectsMaxHops(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000cc", "Busd Pair", "BP", 18)
+	chain.addPair(token, tokens["BUSD"], 1_000_000, 1_000_000)
+
+	service := newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{ChainID: 56},
+		BSC:   config.BSCConfig{MaxHops: 1},
+	})
+	_, err := service.findBestRoute(token, tokens["WBNB"], pow10(18))
+	assert.Error(t, err)
+
+	service = newTestBSCService(chain)
+	route, err := service.findBestRoute(token, tokens["WBNB"], pow10(18))
+	require.NoError(t, err)
+	assert.Equal(t, []common.Address{token, tokens["BUSD"], tokens["WBNB"]}, route.path)
+}
@@ -2,54 +2,161 @@ package services
 
 import (
 	"context"
+	"crypto/ecdsa"
 	"fmt"
 	"math/big"
+	"net/http"
 	"strings"
+	"sync"
+	"time"
 
 	"chain/internal/config"
+	"chain/internal/models"
 	"chain/pkg/logger"
 
 	"github.com/ethereum/go-ethereum"
 	"github.com/ethereum/go-ethereum/accounts/abi"
 	"github.com/ethereum/go-ethereum/common"
-	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/crypto"
 )
 
+// bscBackend BSC节点访问接口，便于在测试中替换为模拟实现
+type bscBackend interface {
+	ethereum.ContractCaller
+	ethereum.GasPricer
+	ethereum.GasEstimator
+	ethereum.TransactionSender
+	PendingNonceAt(ctx context.Context, account common.Address) (uint64, error)
+	TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error)
+	HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error)
+	FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error)
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error)
+	stateOverrideCaller
+	batchCaller
+}
+
 // BSCService BSC链交互服务
 type BSCService struct {
-	client   *ethclient.Client
+	client   bscBackend
+	calls    *multicaller // 合并只读调用和余额查询
 	chainID  *big.Int
 	gasLimit uint64
+
+	// 交易签名账户，未配置私钥时为空，此时无法发送交易
+	privateKey *ecdsa.PrivateKey
+	address    common.Address
+	txMu       sync.Mutex // 串行发送交易，避免nonce冲突
+
+	// 兑换的鉴权和接收地址限制
+	swapAdminToken string                  // 执行兑换所需的令牌，为空时不能执行兑换
+	swapRecipients map[common.Address]bool // 签名账户以外允许接收兑换输出的地址
+
+	// 路由查找配置
+	baseTokens []common.Address
+	maxHops    int
+	v3Dexes    []*v3Dex
+
+	// 代币精度缓存，精度在合约部署后不会变化
+	decimalsMu    sync.RWMutex
+	decimalsCache map[common.Address]uint8
+
+	// 24小时成交量和涨跌幅
+	snapshots     PriceSnapshotStore
+	logBlockRange uint64
+	statsTTL      time.Duration
+	statsMu       sync.Mutex
+	statsCache    map[common.Address]*marketStats
+	lastSnapshot  map[common.Address]time.Time
+
+	// 交易对索引
+	v2Dexes          []*v2Dex
+	pairs            PairStore
+	pairSyncInterval time.Duration
+	pairBackfill     uint64
+	pairSyncMu       sync.Mutex // 串行执行同步，避免重复处理区块
+	pairSubsMu       sync.Mutex
+	pairSubs         map[chan *models.DexPair]struct{}
+
+	// 代币搜索和元数据缓存
+	tokens          TokenStore
+	tokenLists      []string
+	tokenListHosts  []string     // 接口导入代币列表时允许的域名
+	tokenListToken  string       // 接口导入代币列表所需的令牌，为空时不能通过接口导入
+	tokenListClient *http.Client // 下载接口导入的代币列表，只连接公网地址
+	metadataTTL     time.Duration
+
+	// 预言机价格校验
+	oracleFeeds    map[common.Address]common.Address // 代币 => Chainlink价格源
+	oracleMaxAge   time.Duration
+	maxDeviation   *big.Rat // 百分比
+	rejectDeviated bool
+	feedDecimals   sync.Map // 价格源 => 精度
+
+	// TWAP采样
+	observations  ObservationStore
+	twapPools     []common.Address // 配置的持续采样池子
+	twapInterval  time.Duration
+	twapRetention time.Duration
+	twapPoolsMu   sync.Mutex
+	twapPoolCache map[common.Address]*twapPool
+
+	// K线聚合
+	candles            CandleStore
+	candleTokens       []common.Address // 配置的持续聚合代币
+	candleSyncInterval time.Duration
+	candleBackfill     uint64
+	candleLocks        sync.Map // 代币 => *sync.Mutex，同一代币的聚合串行执行
+	candleQueueMu      sync.Mutex
+	candleQueried      map[common.Address]time.Time // 按需聚合的代币 => 最近查询时间
+
+	// 代币价格缓存，nil表示不缓存
+	cache *Cache
 }
 
 // TokenInfo 代币信息
 type TokenInfo struct {
-	Address  string `json:"address"`
-	Name     string `json:"name"`
-	Symbol   string `json:"symbol"`
-	Decimals uint8  `json:"decimals"`
+	Address        string `json:"address"`
+	Name           string `json:"name"`
+	Symbol         string `json:"symbol"`
+	Decimals       uint8  `json:"decimals"`
+	TotalSupply    string `json:"total_supply"`             // 按精度换算的总量，合约不支持totalSupply时为空
+	TotalSupplyRaw string `json:"total_supply_raw"`         // 最小单位的总量
+	IsProxy        bool   `json:"is_proxy"`                 // 是否为EIP-1967代理合约
+	Implementation string `json:"implementation,omitempty"` // 代理合约的实现合约地址
 }
 
 // PriceInfo 价格信息
 type PriceInfo struct {
-	TokenAddress    string `json:"token_address"`
-	TokenName       string `json:"token_name"`
-	TokenSymbol     string `json:"token_symbol"`
-	PriceInBNB      string `json:"price_in_bnb"`
-	PriceInUSD      string `json:"price_in_usd"`
-	LiquidityPool   string `json:"liquidity_pool"`
-	TotalLiquidity  string `json:"total_liquidity"`
-	Volume24h       string `json:"volume_24h"`
-	PriceChange24h  string `json:"price_change_24h"`
+	TokenAddress   string   `json:"token_address"`
+	TokenName      string   `json:"token_name"`
+	TokenSymbol    string   `json:"token_symbol"`
+	TokenDecimals  uint8    `json:"token_decimals"`
+	PriceInBNB     string   `json:"price_in_bnb"`
+	PriceInBNBRaw  string   `json:"price_in_bnb_raw"` // 1个完整代币可兑换的WBNB最小单位数量
+	PriceInUSD     string   `json:"price_in_usd"`
+	PriceInUSDRaw  string   `json:"price_in_usd_raw"` // 1个完整代币可兑换的USDT最小单位数量
+	LiquidityPool  string   `json:"liquidity_pool"`
+	TotalLiquidity string   `json:"total_liquidity"`
+	Route          []string `json:"route"`       // 计算BNB价格使用的兑换路径
+	RoutePools     []string `json:"route_pools"` // 路径每一跳使用的池子，如 pancakeswap-v2、pancakeswap-v3/2500
+	Volume24h      string   `json:"volume_24h"`
+	PriceChange24h string   `json:"price_change_24h"`
+	OraclePriceUSD string   `json:"oracle_price_usd,omitempty"` // 由Chainlink价格源得到的USD价格，价格源不可用时为空
+	PriceDeviation string   `json:"price_deviation,omitempty"`  // DEX价格相对预言机价格的偏差（百分比）
+	PriceDeviated  bool     `json:"price_deviated"`             // 偏差是否超过阈值
 }
 
 // PancakeSwap V2 Router 合约地址
 const (
-	PancakeSwapV2Router = "0x10ED43C718714eb63d5aA57B78B54704E256024E"
+	PancakeSwapV2Router  = "0x10ED43C718714eb63d5aA57B78B54704E256024E"
 	PancakeSwapV2Factory = "0xcA143Ce32Fe78f1f7019d7d551a6402fC5350c73"
-	WBNBAddress         = "0xbb4CdB9CBd36B01bD1cBaeBF2De08d9173bc095c"
-	USDTAddress         = "0x55d398326f99059fF775485246999027B3197955"
-	BUSDAddress         = "0xe9e7CEA3DedcA5984780Bafc599bD69ADd087D56"
+	WBNBAddress          = "0xbb4CdB9CBd36B01bD1cBaeBF2De08d9173bc095c"
+	USDTAddress          = "0x55d398326f99059fF775485246999027B3197955"
+	BUSDAddress          = "0xe9e7CEA3DedcA5984780Bafc599bD69ADd087D56"
+	USDCAddress          = "0x8AC76a51cc950d9822D68b83fE1Ad97B32Cd580d"
+	CAKEAddress          = "0x0E09FaBB73Bd3Ade0a17ECC321fD13a19e81cE82"
 )
 
 // ERC20 ABI (简化版)
@@ -75,12 +182,49 @@ const erc20ABI = `[
 		"outputs": [{"name": "", "type": "uint8"}],
 		"type": "function"
 	},
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "totalSupply",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"type": "function"
+	},
 	{
 		"constant": true,
 		"inputs": [{"name": "_owner", "type": "address"}],
 		"name": "balanceOf",
 		"outputs": [{"name": "balance", "type": "uint256"}],
 		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [
+			{"name": "_owner", "type": "address"},
+			{"name": "_spender", "type": "address"}
+		],
+		"name": "allowance",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{"name": "_spender", "type": "address"},
+			{"name": "_value", "type": "uint256"}
+		],
+		"name": "approve",
+		"outputs": [{"name": "", "type": "bool"}],
+		"type": "function"
+	},
+	{
+		"constant": false,
+		"inputs": [
+			{"name": "_to", "type": "address"},
+			{"name": "_value", "type": "uint256"}
+		],
+		"name": "transfer",
+		"outputs": [{"name": "", "type": "bool"}],
+		"type": "function"
 	}
 ]`
 
@@ -116,6 +260,17 @@ const pancakeFactoryABI = `[
 		"name": "getPair",
 		"outputs": [{"name": "pair", "type": "address"}],
 		"type": "function"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "token0", "type": "address"},
+			{"indexed": true, "name": "token1", "type": "address"},
+			{"indexed": false, "name": "pair", "type": "address"},
+			{"indexed": false, "name": "", "type": "uint256"}
+		],
+		"name": "PairCreated",
+		"type": "event"
 	}
 ]`
 
@@ -132,6 +287,20 @@ const pairABI = `[
 		],
 		"type": "function"
 	},
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "price0CumulativeLast",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"type": "function"
+	},
+	{
+		"constant": true,
+		"inputs": [],
+		"name": "price1CumulativeLast",
+		"outputs": [{"name": "", "type": "uint256"}],
+		"type": "function"
+	},
 	{
 		"constant": true,
 		"inputs": [],
@@ -145,98 +314,226 @@ const pairABI = `[
 		"name": "token1",
 		"outputs": [{"name": "", "type": "address"}],
 		"type": "function"
+	},
+	{
+		"anonymous": false,
+		"inputs": [
+			{"indexed": true, "name": "sender", "type": "address"},
+			{"indexed": false, "name": "amount0In", "type": "uint256"},
+			{"indexed": false, "name": "amount1In", "type": "uint256"},
+			{"indexed": false, "name": "amount0Out", "type": "uint256"},
+			{"indexed": false, "name": "amount1Out", "type": "uint256"},
+			{"indexed": true, "name": "to", "type": "address"}
+		],
+		"name": "Swap",
+		"type": "event"
 	}
 ]`
 
 // NewBSCService 创建新的BSC服务
 func NewBSCService(cfg *config.Config) *BSCService {
 	// 连接到BSC节点
-	client, err := ethclient.Dial(cfg.Chain.RPCURL)
+	client, err := dialBSCClient(cfg.Chain.RPCURL)
 	if err != nil {
 		logger.Fatalf("Failed to connect to BSC client: %v", err)
 	}
 
-	chainID := big.NewInt(cfg.Chain.ChainID)
 	logger.Infof("BSC service initialized with chain ID: %d", cfg.Chain.ChainID)
 
-	return &BSCService{
-		client:   client,
-		chainID:  chainID,
-		gasLimit: cfg.Chain.GasLimit,
-	}
+	service := newBSCService(client, cfg)
+	service.SetCache(defaultCache(cfg))
+	return service
 }
 
-// GetTokenInfo 获取代币信息
-func (s *BSCService) GetTokenInfo(tokenAddress string) (*TokenInfo, error) {
-	addr := common.HexToAddress(tokenAddress)
-	
-	// 解析ERC20 ABI
-	parsedABI, err := abi.JSON(strings.NewReader(erc20ABI))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+// SetCache 设置代币价格缓存，默认使用内存缓存，nil表示不缓存
+func (s *BSCService) SetCache(cache *Cache) {
+	s.cache = cache
+}
+
+// newBSCService 使用指定的节点后端创建BSC服务
+func newBSCService(client bscBackend, cfg *config.Config) *BSCService {
+	baseTokens := cfg.BSC.BaseTokens
+	if len(baseTokens) == 0 {
+		baseTokens = defaultBaseTokens
+	}
+	bases := make([]common.Address, 0, len(baseTokens))
+	for _, token := range baseTokens {
+		bases = append(bases, common.HexToAddress(token))
 	}
 
-	// 获取代币名称
-	nameData, err := parsedABI.Pack("name")
-	if err != nil {
-		return nil, fmt.Errorf("failed to pack name call: %w", err)
+	maxHops := cfg.BSC.MaxHops
+	if maxHops <= 0 {
+		maxHops = defaultMaxHops
 	}
-	nameResult, err := s.client.CallContract(context.Background(), ethereum.CallMsg{
-		To:   &addr,
-		Data: nameData,
-	}, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call name: %w", err)
+
+	logBlockRange := uint64(defaultLogBlockRange)
+	if cfg.BSC.LogBlockRange > 0 {
+		logBlockRange = uint64(cfg.BSC.LogBlockRange)
 	}
-	nameOutput, err := parsedABI.Unpack("name", nameResult)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unpack name: %w", err)
+
+	statsTTL := defaultStatsCacheTTL
+	if cfg.BSC.StatsCacheTTL > 0 {
+		statsTTL = time.Duration(cfg.BSC.StatsCacheTTL) * time.Second
 	}
 
-	// 获取代币符号
-	symbolData, err := parsedABI.Pack("symbol")
-	if err != nil {
-		return nil, fmt.Errorf("failed to pack symbol call: %w", err)
+	pairBackfill := uint64(defaultPairBackfillBlocks)
+	if cfg.BSC.PairBackfillBlocks > 0 {
+		pairBackfill = uint64(cfg.BSC.PairBackfillBlocks)
 	}
-	symbolResult, err := s.client.CallContract(context.Background(), ethereum.CallMsg{
-		To:   &addr,
-		Data: symbolData,
-	}, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call symbol: %w", err)
+
+	metadataTTL := defaultTokenMetadataTTL
+	if cfg.BSC.TokenMetadataTTL > 0 {
+		metadataTTL = time.Duration(cfg.BSC.TokenMetadataTTL) * time.Second
 	}
-	symbolOutput, err := parsedABI.Unpack("symbol", symbolResult)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unpack symbol: %w", err)
+
+	oracleMaxAge := defaultOracleMaxAge
+	if cfg.BSC.OracleMaxAge > 0 {
+		oracleMaxAge = time.Duration(cfg.BSC.OracleMaxAge) * time.Second
 	}
 
-	// 获取代币精度
-	decimalsData, err := parsedABI.Pack("decimals")
-	if err != nil {
-		return nil, fmt.Errorf("failed to pack decimals call: %w", err)
+	maxDeviation := defaultMaxPriceDeviation
+	if cfg.BSC.MaxPriceDeviation > 0 {
+		maxDeviation = cfg.BSC.MaxPriceDeviation
 	}
-	decimalsResult, err := s.client.CallContract(context.Background(), ethereum.CallMsg{
-		To:   &addr,
-		Data: decimalsData,
-	}, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call decimals: %w", err)
+
+	twapPools := make([]common.Address, 0, len(cfg.BSC.TWAPPools))
+	for _, pool := range cfg.BSC.TWAPPools {
+		twapPools = append(twapPools, common.HexToAddress(pool))
 	}
-	decimalsOutput, err := parsedABI.Unpack("decimals", decimalsResult)
-	if err != nil {
-		return nil, fmt.Errorf("failed to unpack decimals: %w", err)
+
+	swapRecipients := make(map[common.Address]bool, len(cfg.BSC.SwapRecipients))
+	for _, recipient := range cfg.BSC.SwapRecipients {
+		swapRecipients[common.HexToAddress(recipient)] = true
 	}
 
-	return &TokenInfo{
-		Address:  tokenAddress,
-		Name:     nameOutput[0].(string),
-		Symbol:   symbolOutput[0].(string),
-		Decimals: decimalsOutput[0].(uint8),
-	}, nil
+	candleTokens := make([]common.Address, 0, len(cfg.BSC.CandleTokens))
+	for _, token := range cfg.BSC.CandleTokens {
+		candleTokens = append(candleTokens, common.HexToAddress(token))
+	}
+
+	candleBackfill := uint64(defaultCandleBackfillBlocks)
+	if cfg.BSC.CandleBackfillBlocks > 0 {
+		candleBackfill = uint64(cfg.BSC.CandleBackfillBlocks)
+	}
+
+	twapRetention := defaultTWAPRetention
+	if cfg.BSC.TWAPRetention > 0 {
+		twapRetention = time.Duration(cfg.BSC.TWAPRetention) * time.Second
+	}
+
+	calls := newMulticaller(client, cfg.Chain)
+	service := &BSCService{
+		client:        &batchedBSCBackend{bscBackend: client, calls: calls},
+		calls:         calls,
+		chainID:       big.NewInt(cfg.Chain.ChainID),
+		gasLimit:      cfg.Chain.GasLimit,
+		baseTokens:    bases,
+		maxHops:       maxHops,
+		v3Dexes:       newV3Dexes(cfg.BSC.V3Dexes),
+		decimalsCache: make(map[common.Address]uint8),
+		snapshots:     NewMemorySnapshotStore(),
+		logBlockRange: logBlockRange,
+		statsTTL:      statsTTL,
+		statsCache:    make(map[common.Address]*marketStats),
+		lastSnapshot:  make(map[common.Address]time.Time),
+
+		v2Dexes:          newV2Dexes(cfg.BSC.V2Dexes),
+		pairs:            NewMemoryPairStore(),
+		pairSyncInterval: time.Duration(cfg.BSC.PairSyncInterval) * time.Second,
+		pairBackfill:     pairBackfill,
+		pairSubs:         make(map[chan *models.DexPair]struct{}),
+
+		tokenLists:      cfg.BSC.TokenLists,
+		tokenListHosts:  cfg.BSC.TokenListHosts,
+		tokenListToken:  cfg.BSC.TokenListToken,
+		tokenListClient: newPublicHTTPClient(tokenListTimeout, cfg.BSC.TokenListHosts),
+		metadataTTL:     metadataTTL,
+
+		oracleFeeds:    newOracleFeeds(cfg.BSC.OracleFeeds),
+		oracleMaxAge:   oracleMaxAge,
+		maxDeviation:   new(big.Rat).SetFloat64(maxDeviation),
+		rejectDeviated: cfg.BSC.RejectDeviatedPrice,
+
+		swapAdminToken: cfg.BSC.SwapAdminToken,
+		swapRecipients: swapRecipients,
+
+		observations:  NewMemoryObservationStore(),
+		twapPools:     twapPools,
+		twapInterval:  time.Duration(cfg.BSC.TWAPSampleInterval) * time.Second,
+		twapRetention: twapRetention,
+		twapPoolCache: make(map[common.Address]*twapPool),
+
+		candles:            NewMemoryCandleStore(),
+		candleTokens:       candleTokens,
+		candleQueried:      make(map[common.Address]time.Time),
+		candleSyncInterval: time.Duration(cfg.BSC.CandleSyncInterval) * time.Second,
+		candleBackfill:     candleBackfill,
+	}
+	service.SetTokenStore(NewMemoryTokenStore())
+
+	// 解析签名私钥，只读查询不需要私钥
+	if cfg.Chain.PrivateKey != "" {
+		privateKey, err := crypto.HexToECDSA(strings.TrimPrefix(cfg.Chain.PrivateKey, "0x"))
+		if err != nil {
+			logger.Warnf("Invalid private key, BSC transactions disabled: %v", err)
+		} else {
+			service.privateKey = privateKey
+			service.address = crypto.PubkeyToAddress(privateKey.PublicKey)
+		}
+	}
+
+	return service
 }
 
-// GetTokenPrice 获取代币价格
+// GetTokenPrice 获取代币价格，结果按 token_price 的缓存时间缓存
 func (s *BSCService) GetTokenPrice(tokenAddress, tokenName string) (*PriceInfo, error) {
+	var price *PriceInfo
+	key := strings.ToLower(tokenAddress) + ":" + strings.ToLower(tokenName)
+	err := s.cache.Fetch(context.Background(), CacheTokenPrice, key, &price, func(ctx context.Context) (interface{}, error) {
+		return s.getTokenPrice(tokenAddress, tokenName)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// RefreshTokenPrice 跳过缓存从链上查询代币最新价格，并用结果更新缓存
+func (s *BSCService) RefreshTokenPrice(tokenAddress string) (*PriceInfo, error) {
+	var price *PriceInfo
+	key := strings.ToLower(tokenAddress) + ":"
+	err := s.cache.Refresh(context.Background(), CacheTokenPrice, key, &price, func(ctx context.Context) (interface{}, error) {
+		return s.getTokenPrice(tokenAddress, "")
+	})
+	if err != nil {
+		return nil, err
+	}
+	return price, nil
+}
+
+// getTokenPrice 从链上查询代币价格
+func (s *BSCService) getTokenPrice(tokenAddress, tokenName string) (*PriceInfo, error) {
+	// BNB的USD价格和预言机价格不依赖代币，与代币查询并发进行以便合并链上读取
+	var (
+		bnbPriceInUSDRaw *big.Int
+		bnbPriceErr      error
+		bnbPriceDone     = make(chan struct{})
+	)
+	go func() {
+		defer close(bnbPriceDone)
+		bnbPriceInUSDRaw, bnbPriceErr = s.getBNBPriceInUSD()
+	}()
+
+	token := common.HexToAddress(tokenAddress)
+	var (
+		oracles    tokenOracles
+		oracleDone = make(chan struct{})
+	)
+	go func() {
+		defer close(oracleDone)
+		oracles = s.getTokenOracles(token)
+	}()
+
 	// 获取代币信息
 	tokenInfo, err := s.GetTokenInfo(tokenAddress)
 	if err != nil {
@@ -248,119 +545,233 @@ func (s *BSCService) GetTokenPrice(tokenAddress, tokenName string) (*PriceInfo,
 		return nil, fmt.Errorf("token name/symbol mismatch: expected %s, got %s/%s", tokenName, tokenInfo.Name, tokenInfo.Symbol)
 	}
 
-	// 获取BNB价格
-	priceInBNB, err := s.getTokenPriceInBNB(tokenAddress)
+	// 获取BNB价格（WBNB最小单位）
+	route, err := s.getTokenPriceInBNB(tokenAddress)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get price in BNB: %w", err)
 	}
+	priceInBNBRaw := route.amountOut()
 
-	// 获取BNB/USDT价格来计算USD价格
-	bnbPriceInUSD, err := s.getBNBPriceInUSD()
+	wbnbDecimals, err := s.getTokenDecimals(WBNBAddress)
 	if err != nil {
-		logger.Warnf("Failed to get BNB price in USD: %v", err)
-		bnbPriceInUSD = big.NewFloat(0)
+		return nil, fmt.Errorf("failed to get WBNB decimals: %w", err)
 	}
 
-	// 计算USD价格
-	priceInUSD := new(big.Float).Mul(priceInBNB, bnbPriceInUSD)
+	// 获取BNB/USDT价格来计算USD价格
+	priceInUSDRaw := big.NewInt(0)
+	usdDecimals := uint8(0)
+	<-bnbPriceDone
+	<-oracleDone
+	var fallbackPriceUSD *big.Rat
+	if bnbPriceErr != nil {
+		logger.Warnf("Failed to get BNB price in USD: %v", bnbPriceErr)
+		fallbackPriceUSD = oracles.fallbackPrice(priceInBNBRaw, wbnbDecimals)
+	}
+	if bnbPriceErr == nil || fallbackPriceUSD != nil {
+		if usdDecimals, err = s.getTokenDecimals(USDTAddress); err != nil {
+			logger.Warnf("Failed to get USDT decimals: %v", err)
+		} else if bnbPriceErr == nil {
+			// 代币USD价格 = 代币BNB价格 * BNB USD价格 / 10^WBNB精度
+			priceInUSDRaw.Mul(priceInBNBRaw, bnbPriceInUSDRaw)
+			priceInUSDRaw.Quo(priceInUSDRaw, pow10(wbnbDecimals))
+		} else {
+			// DEX上没有可用的BNB/USDT价格时使用预言机价格
+			scaled := new(big.Rat).Mul(fallbackPriceUSD, new(big.Rat).SetInt(pow10(usdDecimals)))
+			priceInUSDRaw.Quo(scaled.Num(), scaled.Denom())
+		}
+	}
 
-	// 获取流动性池地址
-	liquidityPool, err := s.getLiquidityPool(tokenAddress, WBNBAddress)
-	if err != nil {
-		logger.Warnf("Failed to get liquidity pool: %v", err)
-		liquidityPool = ""
+	// 与预言机价格比较，偏差过大说明DEX价格可能被操纵
+	// WBNB/USDT池子给出的BNB价格与BNB/USD价格源比较，所有代币都经过这项校验；
+	// 代币有自己的价格源时再比较代币价格，结果取偏差较大的一项
+	var oraclePriceUSD, priceDeviation *big.Rat
+	priceDeviated := false
+	if bnbPriceErr == nil && priceInUSDRaw.Sign() > 0 {
+		if oracles.bnb != nil {
+			bnbPriceInUSD := new(big.Rat).SetFrac(bnbPriceInUSDRaw, pow10(usdDecimals))
+			deviation, deviated, err := s.checkPriceDeviation("BNB", bnbPriceInUSD, oracles.bnb.price)
+			if err != nil {
+				return nil, err
+			}
+			oraclePriceUSD = new(big.Rat).Mul(new(big.Rat).SetFrac(priceInBNBRaw, pow10(wbnbDecimals)), oracles.bnb.price)
+			priceDeviation, priceDeviated = deviation, deviated
+		}
+		if oracles.token != nil {
+			priceInUSD := new(big.Rat).SetFrac(priceInUSDRaw, pow10(usdDecimals))
+			deviation, deviated, err := s.checkPriceDeviation(tokenAddress, priceInUSD, oracles.token.price)
+			if err != nil {
+				return nil, err
+			}
+			if priceDeviation == nil || new(big.Rat).Abs(deviation).Cmp(new(big.Rat).Abs(priceDeviation)) >= 0 {
+				oraclePriceUSD, priceDeviation = oracles.token.price, deviation
+			}
+			priceDeviated = priceDeviated || deviated
+		}
 	}
 
-	// 获取流动性信息
-	totalLiquidity, err := s.getTotalLiquidity(tokenAddress, WBNBAddress)
-	if err != nil {
-		logger.Warnf("Failed to get total liquidity: %v", err)
-		totalLiquidity = "0"
-	}
-
-	return &PriceInfo{
-		TokenAddress:    tokenAddress,
-		TokenName:       tokenInfo.Name,
-		TokenSymbol:     tokenInfo.Symbol,
-		PriceInBNB:      priceInBNB.String(),
-		PriceInUSD:      priceInUSD.String(),
-		LiquidityPool:   liquidityPool,
-		TotalLiquidity:  totalLiquidity,
-		Volume24h:       "0", // 需要额外的API来获取24小时交易量
-		PriceChange24h:  "0", // 需要额外的API来获取24小时价格变化
-	}, nil
+	// 获取兑换路径第一跳的流动性池，复用已计算的代币USD价格
+	var knownPrices map[common.Address]*big.Rat
+	if priceInUSDRaw.Sign() > 0 {
+		knownPrices = map[common.Address]*big.Rat{
+			token: new(big.Rat).SetFrac(priceInUSDRaw, pow10(usdDecimals)),
+		}
+	}
+	liquidityPool, totalLiquidity := "", "0"
+	if len(route.hops) > 0 {
+		liquidityPool, totalLiquidity, err = s.getHopLiquidity(route.path[0], route.path[1], route.hops[0], knownPrices)
+		if err != nil {
+			logger.Warnf("Failed to get liquidity for %s: %v", route.hops[0].label(), err)
+		}
+	}
+
+	priceInfo := &PriceInfo{
+		TokenAddress:   tokenAddress,
+		TokenName:      tokenInfo.Name,
+		TokenSymbol:    tokenInfo.Symbol,
+		TokenDecimals:  tokenInfo.Decimals,
+		PriceInBNB:     formatUnits(priceInBNBRaw, wbnbDecimals),
+		PriceInBNBRaw:  priceInBNBRaw.String(),
+		PriceInUSD:     formatUnits(priceInUSDRaw, usdDecimals),
+		PriceInUSDRaw:  priceInUSDRaw.String(),
+		LiquidityPool:  liquidityPool,
+		TotalLiquidity: totalLiquidity,
+		Route:          route.pathStrings(),
+		RoutePools:     route.poolLabels(),
+		PriceDeviated:  priceDeviated,
+	}
+	if oraclePriceUSD != nil {
+		priceInfo.OraclePriceUSD = formatRat(oraclePriceUSD, 18)
+		priceInfo.PriceDeviation = formatRat(priceDeviation, 4)
+	}
+
+	// 24小时成交量和涨跌幅
+	s.fillMarketStats(priceInfo, token, tokenInfo.Decimals, usdDecimals, priceInUSDRaw)
+
+	return priceInfo, nil
 }
 
-// getTokenPriceInBNB 获取代币相对于BNB的价格
-func (s *BSCService) getTokenPriceInBNB(tokenAddress string) (*big.Float, error) {
-	// 解析Router ABI
-	parsedABI, err := abi.JSON(strings.NewReader(pancakeRouterABI))
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse router ABI: %w", err)
+// TokenPriceQuery 批量查询价格的代币
+type TokenPriceQuery struct {
+	Address string
+	Name    string // 可选，用于校验代币名称或符号
+}
+
+// GetTokenPrices 并发获取多个代币的价格，并发发起的链上读取被合并为批量请求
+// 返回结果与查询顺序一致，获取失败的代币价格为nil并返回对应的错误
+func (s *BSCService) GetTokenPrices(queries []TokenPriceQuery) ([]*PriceInfo, []error) {
+	prices := make([]*PriceInfo, len(queries))
+	errs := make([]error, len(queries))
+
+	var wg sync.WaitGroup
+	for i, query := range queries {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			prices[i], errs[i] = s.GetTokenPrice(query.Address, query.Name)
+		}()
+	}
+	wg.Wait()
+
+	return prices, errs
+}
+
+// 已解析的合约ABI缓存，以ABI定义字符串为键
+var parsedABICache sync.Map
+
+// parseABI 解析合约ABI，结果会被缓存
+func parseABI(definition string) (abi.ABI, error) {
+	if cached, ok := parsedABICache.Load(definition); ok {
+		return cached.(abi.ABI), nil
 	}
 
-	// 准备路径：token -> WBNB
-	path := []common.Address{
-		common.HexToAddress(tokenAddress),
-		common.HexToAddress(WBNBAddress),
+	parsed, err := abi.JSON(strings.NewReader(definition))
+	if err != nil {
+		return abi.ABI{}, err
 	}
+	parsedABICache.Store(definition, parsed)
+	return parsed, nil
+}
 
-	// 1个代币单位（考虑精度）
-	amountIn := big.NewInt(1)
-	amountIn = amountIn.Exp(big.NewInt(10), big.NewInt(18), nil) // 假设18位精度
+// callContract 按ABI编码调用合约的只读方法并解码返回值
+func (s *BSCService) callContract(definition string, to common.Address, method string, args ...interface{}) ([]interface{}, error) {
+	parsedABI, err := parseABI(definition)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ABI: %w", err)
+	}
 
-	// 调用getAmountsOut
-	data, err := parsedABI.Pack("getAmountsOut", amountIn, path)
+	data, err := parsedABI.Pack(method, args...)
 	if err != nil {
-		return nil, fmt.Errorf("failed to pack getAmountsOut: %w", err)
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
 	}
 
-	routerAddr := common.HexToAddress(PancakeSwapV2Router)
 	result, err := s.client.CallContract(context.Background(), ethereum.CallMsg{
-		To:   &routerAddr,
+		To:   &to,
 		Data: data,
 	}, nil)
 	if err != nil {
-		return nil, fmt.Errorf("failed to call getAmountsOut: %w", err)
+		return nil, fmt.Errorf("failed to call %s: %w", method, err)
 	}
 
-	output, err := parsedABI.Unpack("getAmountsOut", result)
+	output, err := parsedABI.Unpack(method, result)
 	if err != nil {
-		return nil, fmt.Errorf("failed to unpack getAmountsOut: %w", err)
+		return nil, fmt.Errorf("failed to unpack %s: %w", method, err)
 	}
+	return output, nil
+}
 
-	amounts := output[0].([]*big.Int)
-	if len(amounts) < 2 {
-		return nil, fmt.Errorf("invalid amounts output")
+// getTokenDecimals 获取代币精度，结果会被缓存
+func (s *BSCService) getTokenDecimals(tokenAddress string) (uint8, error) {
+	addr := common.HexToAddress(tokenAddress)
+
+	s.decimalsMu.RLock()
+	decimals, ok := s.decimalsCache[addr]
+	s.decimalsMu.RUnlock()
+	if ok {
+		return decimals, nil
 	}
 
-	// 计算价格：输出BNB数量 / 输入代币数量
-	priceInBNB := new(big.Float).Quo(
-		new(big.Float).SetInt(amounts[1]),
-		new(big.Float).SetInt(amountIn),
-	)
+	// 解析ERC20 ABI
+	parsedABI, err := abi.JSON(strings.NewReader(erc20ABI))
+	if err != nil {
+		return 0, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
 
-	return priceInBNB, nil
+	data, err := parsedABI.Pack("decimals")
+	if err != nil {
+		return 0, fmt.Errorf("failed to pack decimals call: %w", err)
+	}
+	result, err := s.client.CallContract(context.Background(), ethereum.CallMsg{
+		To:   &addr,
+		Data: data,
+	}, nil)
+	if err != nil {
+		return 0, fmt.Errorf("failed to call decimals: %w", err)
+	}
+	output, err := parsedABI.Unpack("decimals", result)
+	if err != nil {
+		return 0, fmt.Errorf("failed to unpack decimals: %w", err)
+	}
+
+	decimals = output[0].(uint8)
+	s.cacheTokenDecimals(addr, decimals)
+	return decimals, nil
 }
 
-// getBNBPriceInUSD 获取BNB相对于USD的价格
-func (s *BSCService) getBNBPriceInUSD() (*big.Float, error) {
+// cacheTokenDecimals 缓存代币精度
+func (s *BSCService) cacheTokenDecimals(addr common.Address, decimals uint8) {
+	s.decimalsMu.Lock()
+	s.decimalsCache[addr] = decimals
+	s.decimalsMu.Unlock()
+}
+
+// getAmountsOut 调用Router的getAmountsOut查询兑换路径上每一跳的输出数量
+func (s *BSCService) getAmountsOut(amountIn *big.Int, path []common.Address) ([]*big.Int, error) {
 	// 解析Router ABI
 	parsedABI, err := abi.JSON(strings.NewReader(pancakeRouterABI))
 	if err != nil {
 		return nil, fmt.Errorf("failed to parse router ABI: %w", err)
 	}
 
-	// 准备路径：WBNB -> USDT
-	path := []common.Address{
-		common.HexToAddress(WBNBAddress),
-		common.HexToAddress(USDTAddress),
-	}
-
-	// 1 BNB
-	amountIn := big.NewInt(1)
-	amountIn = amountIn.Exp(big.NewInt(10), big.NewInt(18), nil)
-
 	// 调用getAmountsOut
 	data, err := parsedABI.Pack("getAmountsOut", amountIn, path)
 	if err != nil {
@@ -382,17 +793,38 @@ func (s *BSCService) getBNBPriceInUSD() (*big.Float, error) {
 	}
 
 	amounts := output[0].([]*big.Int)
-	if len(amounts) < 2 {
+	if len(amounts) != len(path) {
 		return nil, fmt.Errorf("invalid amounts output")
 	}
 
-	// USDT有18位精度，转换为USD价格
-	priceInUSD := new(big.Float).Quo(
-		new(big.Float).SetInt(amounts[1]),
-		new(big.Float).SetInt(amountIn),
-	)
+	return amounts, nil
+}
+
+// getTokenPriceInBNB 查找1个完整代币兑换为WBNB的最优路径
+func (s *BSCService) getTokenPriceInBNB(tokenAddress string) (*routeQuote, error) {
+	decimals, err := s.getTokenDecimals(tokenAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token decimals: %w", err)
+	}
 
-	return priceInUSD, nil
+	// 1个完整代币 = 10^decimals 最小单位
+	return s.findBestPriceRoute(common.HexToAddress(tokenAddress), common.HexToAddress(WBNBAddress), pow10(decimals))
+}
+
+// getBNBPriceInUSD 获取1个BNB可兑换的USDT数量（最小单位）
+func (s *BSCService) getBNBPriceInUSD() (*big.Int, error) {
+	decimals, err := s.getTokenDecimals(WBNBAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get WBNB decimals: %w", err)
+	}
+
+	// 1 BNB
+	route, err := s.findBestPriceRoute(common.HexToAddress(WBNBAddress), common.HexToAddress(USDTAddress), pow10(decimals))
+	if err != nil {
+		return nil, err
+	}
+
+	return route.amountOut(), nil
 }
 
 // getLiquidityPool 获取流动性池地址
@@ -427,50 +859,116 @@ func (s *BSCService) getLiquidityPool(tokenA, tokenB string) (string, error) {
 	return pairAddress.Hex(), nil
 }
 
-// getTotalLiquidity 获取总流动性
-func (s *BSCService) getTotalLiquidity(tokenA, tokenB string) (string, error) {
+// pairReserves V2交易对储备量
+type pairReserves struct {
+	pair     common.Address
+	token0   common.Address
+	token1   common.Address
+	reserve0 *big.Int
+	reserve1 *big.Int
+}
+
+// reservesFor 返回以tokenIn为输入方向的储备量（输入储备, 输出储备）
+func (r *pairReserves) reservesFor(tokenIn common.Address) (*big.Int, *big.Int) {
+	if tokenIn == r.token0 {
+		return r.reserve0, r.reserve1
+	}
+	return r.reserve1, r.reserve0
+}
+
+// sortTokens 按地址排序两个代币，与交易对中token0/token1的顺序一致
+func sortTokens(tokenA, tokenB common.Address) (common.Address, common.Address) {
+	if strings.ToLower(tokenA.Hex()) > strings.ToLower(tokenB.Hex()) {
+		return tokenB, tokenA
+	}
+	return tokenA, tokenB
+}
+
+// SortTokenAddresses 按交易对合约的规则排序两个代币地址，返回校验和格式的token0和token1
+func SortTokenAddresses(tokenA, tokenB string) (string, string) {
+	token0, token1 := sortTokens(common.HexToAddress(tokenA), common.HexToAddress(tokenB))
+	return token0.Hex(), token1.Hex()
+}
+
+// getPairReserves 获取两个代币之间V2交易对的储备量
+func (s *BSCService) getPairReserves(tokenA, tokenB string) (*pairReserves, error) {
 	// 获取流动性池地址
 	pairAddress, err := s.getLiquidityPool(tokenA, tokenB)
 	if err != nil {
-		return "0", err
+		return nil, err
 	}
 
 	if pairAddress == "0x0000000000000000000000000000000000000000" {
-		return "0", fmt.Errorf("no liquidity pool found")
+		return nil, fmt.Errorf("no liquidity pool found")
 	}
 
-	// 解析Pair ABI
-	parsedABI, err := abi.JSON(strings.NewReader(pairABI))
+	// 调用getReserves
+	pairAddr := common.HexToAddress(pairAddress)
+	output, err := s.callContract(pairABI, pairAddr, "getReserves")
 	if err != nil {
-		return "0", fmt.Errorf("failed to parse pair ABI: %w", err)
+		return nil, err
 	}
 
-	// 调用getReserves
-	data, err := parsedABI.Pack("getReserves")
+	token0, token1 := sortTokens(common.HexToAddress(tokenA), common.HexToAddress(tokenB))
+	return &pairReserves{
+		pair:     pairAddr,
+		token0:   token0,
+		token1:   token1,
+		reserve0: output[0].(*big.Int),
+		reserve1: output[1].(*big.Int),
+	}, nil
+}
+
+// getTotalLiquidity 获取V2交易对的锁仓总价值（USD）
+func (s *BSCService) getTotalLiquidity(tokenA, tokenB string) (string, error) {
+	info, err := s.getLiquidityInfo(tokenA, tokenB, nil)
 	if err != nil {
-		return "0", fmt.Errorf("failed to pack getReserves: %w", err)
+		return "0", err
 	}
+	return info.TVLUSD, nil
+}
 
-	pairAddr := common.HexToAddress(pairAddress)
-	result, err := s.client.CallContract(context.Background(), ethereum.CallMsg{
-		To:   &pairAddr,
-		Data: data,
-	}, nil)
-	if err != nil {
-		return "0", fmt.Errorf("failed to call getReserves: %w", err)
+// getHopLiquidity 获取兑换路径中某一跳所用池子的地址和锁仓总价值（USD）
+// prices为已知的代币USD价格，可为nil
+func (s *BSCService) getHopLiquidity(tokenA, tokenB common.Address, hop routeHop, prices map[common.Address]*big.Rat) (string, string, error) {
+	if hop.pool == (common.Address{}) {
+		pool, err := s.getLiquidityPool(tokenA.Hex(), tokenB.Hex())
+		if err != nil {
+			return "", "0", err
+		}
+		info, err := s.getLiquidityInfo(tokenA.Hex(), tokenB.Hex(), prices)
+		if err != nil {
+			return pool, "0", err
+		}
+		return pool, info.TVLUSD, nil
 	}
 
-	output, err := parsedABI.Unpack("getReserves", result)
+	// V3池的储备量即池子持有的代币余额，与 GetV3Pools 相同只计入可定价的一侧
+	var tokens [2]common.Address
+	tokens[0], tokens[1] = sortTokens(tokenA, tokenB)
+	balance0, balance1, err := s.getPoolBalances(hop.pool, tokens[0], tokens[1])
 	if err != nil {
-		return "0", fmt.Errorf("failed to unpack getReserves: %w", err)
+		return hop.pool.Hex(), "0", err
 	}
-
-	reserve0 := output[0].(*big.Int)
-	reserve1 := output[1].(*big.Int)
-
-	// 简单返回两个储备量的和作为总流动性指标
-	totalLiquidity := new(big.Int).Add(reserve0, reserve1)
-	return totalLiquidity.String(), nil
+	var decimals [2]uint8
+	poolPrices := make(map[common.Address]*big.Rat, 2)
+	for i, token := range tokens {
+		if decimals[i], err = s.getTokenDecimals(token.Hex()); err != nil {
+			return hop.pool.Hex(), "0", fmt.Errorf("failed to get decimals of %s: %w", token.Hex(), err)
+		}
+		price := prices[token]
+		if price == nil {
+			if price, err = s.getTokenUSDPrice(token); err != nil {
+				continue
+			}
+		}
+		poolPrices[token] = price
+	}
+	if len(poolPrices) == 0 {
+		return hop.pool.Hex(), "0", fmt.Errorf("no USD price for %s or %s", tokens[0].Hex(), tokens[1].Hex())
+	}
+	tvl := v3PoolTVL(tokens, [2]*big.Int{balance0, balance1}, decimals, poolPrices)
+	return hop.pool.Hex(), formatRat(tvl, 18), nil
 }
 
 // GetLiquidityPool 获取流动性池地址（公开方法）
@@ -478,39 +976,7 @@ func (s *BSCService) GetLiquidityPool(tokenA, tokenB string) (string, error) {
 	return s.getLiquidityPool(tokenA, tokenB)
 }
 
-// GetTotalLiquidity 获取总流动性（公开方法）
+// GetTotalLiquidity 获取V2交易对的锁仓总价值（USD，公开方法）
 func (s *BSCService) GetTotalLiquidity(tokenA, tokenB string) (string, error) {
 	return s.getTotalLiquidity(tokenA, tokenB)
 }
-
-// FindTokenByName 通过名称查找代币
-func (s *BSCService) FindTokenByName(tokenName string) ([]*TokenInfo, error) {
-	// 这里需要维护一个常用代币的映射表
-	// 在实际应用中，可以连接到代币列表API或维护本地数据库
-	commonTokens := map[string]string{
-		"WBNB":     WBNBAddress,
-		"BNB":      WBNBAddress,
-		"USDT":     USDTAddress,
-		"BUSD":     BUSDAddress,
-		"CAKE":     "0x0E09FaBB73Bd3Ade0a17ECC321fD13a19e81cE82",
-		"SAFEMOON": "0x8076C74C5e3F5852037F31Ff0093Eeb8c8ADd8D3",
-	}
-
-	var results []*TokenInfo
-	for name, address := range commonTokens {
-		if strings.Contains(strings.ToLower(name), strings.ToLower(tokenName)) {
-			tokenInfo, err := s.GetTokenInfo(address)
-			if err != nil {
-				logger.Warnf("Failed to get token info for %s: %v", address, err)
-				continue
-			}
-			results = append(results, tokenInfo)
-		}
-	}
-
-	if len(results) == 0 {
-		return nil, fmt.Errorf("no tokens found with name: %s", tokenName)
-	}
-
-	return results, nil
-}
\ No newline at end of file
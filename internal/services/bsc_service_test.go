@@ -0,0 +1,115 @@
+package services
+
+import (
+	"math/big"
+	"strings"
+	"testing"
+
+	"chain/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestBSCService 创建连接到模拟链的BSC服务
+func newTestBSCService(chain *fakeChain) *BSCService {
+	return newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{
+			ChainID:       56,
+			GasLimit:      21000,
+			MulticallWait: 1,
+		},
+	})
+}
+
+func TestGetTokenPriceDecimals(t *testing.T) {
+	tests := []struct {
+		name          string
+		tokenDecimals uint8
+		usdtDecimals  uint8
+	}{
+		{name: "6 decimals token", tokenDecimals: 6, usdtDecimals: 18},
+		{name: "9 decimals token", tokenDecimals: 9, usdtDecimals: 18},
+		{name: "18 decimals token", tokenDecimals: 18, usdtDecimals: 18},
+		{name: "6 decimals USD quote", tokenDecimals: 9, usdtDecimals: 6},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			chain := newFakeChain()
+			wbnb := chain.addToken(WBNBAddress, "Wrapped BNB", "WBNB", 18)
+			usdt := chain.addToken(USDTAddress, "Tether USD", "USDT", tt.usdtDecimals)
+			token := chain.addToken("0x00000000000000000000000000000000000000aa", "Test Token", "TEST", tt.tokenDecimals)
+
+			// 1 TEST = 2 BNB，1 BNB = 300 USDT
+			chain.addPair(token, wbnb, 1_000_000_000, 2_000_000_000)
+			chain.addPair(wbnb, usdt, 1_000_000_000, 300_000_000_000)
+
+			service := newTestBSCService(chain)
+			price, err := service.GetTokenPrice(token.Hex(), "TEST")
+			require.NoError(t, err)
+
+			// 原始数量必须与按代币精度报价的结果一致
+			oneToken := pow10(tt.tokenDecimals)
+			_, pair := chain.findPair(token, wbnb)
+			reserveIn, reserveOut := pair.reserve0, pair.reserve1
+			if pair.token0 != token {
+				reserveIn, reserveOut = reserveOut, reserveIn
+			}
+			expectedBNB := v2AmountOut(oneToken, reserveIn, reserveOut)
+			assert.Equal(t, expectedBNB.String(), price.PriceInBNBRaw)
+			assert.Equal(t, formatUnits(expectedBNB, 18), price.PriceInBNB)
+			assert.Equal(t, tt.tokenDecimals, price.TokenDecimals)
+
+			// 扣除0.25%手续费后价格应接近2 BNB / 600 USD
+			assertDecimalBetween(t, price.PriceInBNB, "1.99", "2")
+			assertDecimalBetween(t, price.PriceInUSD, "596", "600")
+
+			usdRaw, ok := new(big.Int).SetString(price.PriceInUSDRaw, 10)
+			require.True(t, ok)
+			assert.Equal(t, formatUnits(usdRaw, tt.usdtDecimals), price.PriceInUSD)
+		})
+	}
+}
+
+func TestGetTokenDecimalsCached(t *testing.T) {
+	chain := newFakeChain()
+	wbnb := chain.addToken(WBNBAddress, "Wrapped BNB", "WBNB", 18)
+	usdt := chain.addToken(USDTAddress, "Tether USD", "USDT", 18)
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Test Token", "TEST", 6)
+	chain.addPair(token, wbnb, 1_000_000, 1_000)
+	chain.addPair(wbnb, usdt, 1_000_000, 300_000_000)
+
+	service := newTestBSCService(chain)
+	for i := 0; i < 3; i++ {
+		_, err := service.GetTokenPrice(token.Hex(), "")
+		require.NoError(t, err)
+	}
+
+	// 代币元数据和精度都只从链上读取一次
+	assert.Equal(t, 1, chain.callCount(token, "decimals"))
+	assert.Equal(t, 1, chain.callCount(wbnb, "decimals"))
+	assert.Equal(t, 1, chain.callCount(usdt, "decimals"))
+}
+
+// assertDecimalBetween 断言十进制字符串位于 [min, max) 区间
+func assertDecimalBetween(t *testing.T, value, min, max string) {
+	t.Helper()
+	v, ok := new(big.Rat).SetString(value)
+	require.True(t, ok, "invalid decimal %q", value)
+	lo, _ := new(big.Rat).SetString(min)
+	hi, _ := new(big.Rat).SetString(max)
+	assert.True(t, v.Cmp(lo) >= 0 && v.Cmp(hi) < 0, "%s not in [%s, %s)", value, min, max)
+}
+
+func TestSortTokenAddresses(t *testing.T) {
+	// 与交易对合约一致，按地址大小排序并返回校验和格式
+	token0, token1 := SortTokenAddresses(strings.ToLower(WBNBAddress), USDTAddress)
+	assert.Equal(t, USDTAddress, token0)
+	assert.Equal(t, common.HexToAddress(WBNBAddress).Hex(), token1)
+
+	token0, token1 = SortTokenAddresses(USDTAddress, WBNBAddress)
+	assert.Equal(t, USDTAddress, token0)
+	assert.Equal(t, common.HexToAddress(WBNBAddress).Hex(), token1)
+}
@@ -0,0 +1,272 @@
+package services
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/hex"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/core/asm"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// overrideAccount eth_call状态覆盖中的账户，未设置的字段保持链上状态
+type overrideAccount struct {
+	Code    hexutil.Bytes `json:"code,omitempty"`
+	Balance *hexutil.Big  `json:"balance,omitempty"`
+}
+
+// stateOverrideCaller 支持状态覆盖的只读调用
+type stateOverrideCaller interface {
+	CallContractWithOverrides(ctx context.Context, call ethereum.CallMsg, overrides map[common.Address]overrideAccount) ([]byte, error)
+}
+
+// bscClient 节点客户端，在ethclient的基础上支持带状态覆盖的eth_call
+type bscClient struct {
+	*ethclient.Client
+}
+
+// dialBSCClient 连接BSC节点
+func dialBSCClient(rawURL string) (*bscClient, error) {
+	client, err := ethclient.Dial(rawURL)
+	if err != nil {
+		return nil, err
+	}
+	return &bscClient{Client: client}, nil
+}
+
+// BatchCallContext 发送JSON-RPC批量请求
+func (c *bscClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return c.Client.Client().BatchCallContext(ctx, b)
+}
+
+// CallContractWithOverrides 在最新区块上执行带状态覆盖的eth_call，状态覆盖作为eth_call的第三个参数
+func (c *bscClient) CallContractWithOverrides(ctx context.Context, call ethereum.CallMsg, overrides map[common.Address]overrideAccount) ([]byte, error) {
+	arg := map[string]interface{}{
+		"from": call.From,
+		"to":   call.To,
+	}
+	if len(call.Data) > 0 {
+		arg["input"] = hexutil.Bytes(call.Data)
+	}
+	if call.Value != nil {
+		arg["value"] = (*hexutil.Big)(call.Value)
+	}
+	if call.Gas != 0 {
+		arg["gas"] = hexutil.Uint64(call.Gas)
+	}
+
+	var result hexutil.Bytes
+	if err := c.Client.Client().CallContext(ctx, &result, "eth_call", arg, "latest", overrides); err != nil {
+		return nil, err
+	}
+	return result, nil
+}
+
+// simulatorSource 模拟执行合约的汇编源码
+//
+// 合约按顺序执行调用数据中的每个调用，所有调用在同一次eth_call中执行，后面的调用可以看到前面调用的状态变化。
+// 合约自身作为每个调用的msg.sender，相当于一个交易账户。
+//
+// 调用数据由若干条目首尾相接组成：目标地址(20字节) | 转账金额(32字节) | 数据长度(32字节) | 数据
+// 返回数据中每个调用对应：是否成功(32字节) | 消耗gas(32字节) | 返回数据长度(32字节) | 返回数据（失败时为回滚数据）
+const simulatorSource = `
+	PUSH 0                  ;; 输出位置 out
+	PUSH 0                  ;; 调用数据位置 pos
+loop:                       ;; [pos, out]
+	CALLDATASIZE
+	DUP2
+	LT
+	JUMPI @body
+	POP
+	PUSH 0
+	RETURN                  ;; return(0, out)
+body:
+	DUP1
+	CALLDATALOAD
+	PUSH 96
+	SHR                     ;; [target, pos, out]
+	DUP2
+	PUSH 20
+	ADD
+	CALLDATALOAD            ;; [value, target, pos, out]
+	DUP3
+	PUSH 52
+	ADD
+	CALLDATALOAD            ;; [len, value, target, pos, out]
+	DUP1
+	DUP5
+	PUSH 84
+	ADD
+	DUP7
+	PUSH 96
+	ADD
+	CALLDATACOPY            ;; calldatacopy(out+96, pos+84, len)
+	GAS                     ;; [gasBefore, len, value, target, pos, out]
+	PUSH 0
+	PUSH 0
+	DUP4
+	DUP9
+	PUSH 96
+	ADD
+	DUP7
+	DUP9
+	GAS
+	CALL                    ;; call(gas, target, value, out+96, len, 0, 0)
+	GAS
+	DUP3
+	SUB                     ;; [gasUsed, success, gasBefore, len, value, target, pos, out]
+	DUP8
+	PUSH 32
+	ADD
+	MSTORE                  ;; mstore(out+32, gasUsed)
+	DUP7
+	MSTORE                  ;; mstore(out, success)
+	POP
+	SWAP2
+	POP
+	POP                     ;; [len, pos, out]
+	PUSH 84
+	ADD
+	ADD                     ;; [nextPos, out]
+	RETURNDATASIZE
+	DUP1
+	DUP4
+	PUSH 64
+	ADD
+	MSTORE                  ;; mstore(out+64, returndatasize)
+	DUP1
+	PUSH 0
+	DUP5
+	PUSH 96
+	ADD
+	RETURNDATACOPY          ;; returndatacopy(out+96, 0, returndatasize)
+	PUSH 96
+	ADD
+	SWAP1
+	SWAP2
+	ADD
+	SWAP1                   ;; [nextPos, out+96+returndatasize]
+	JUMP @loop
+`
+
+// simulatorCode 模拟执行合约的字节码
+var simulatorCode = mustCompileAsm(simulatorSource)
+
+// mustCompileAsm 编译EVM汇编源码
+func mustCompileAsm(source string) []byte {
+	compiler := asm.NewCompiler(false)
+	compiler.Feed(asm.Lex([]byte(source), false))
+	output, errs := compiler.Compile()
+	if len(errs) > 0 {
+		panic(fmt.Sprintf("failed to compile simulator: %v", errs))
+	}
+	code, err := hex.DecodeString(output)
+	if err != nil {
+		panic(err)
+	}
+	return code
+}
+
+// simCall 模拟执行中的一个调用
+type simCall struct {
+	target common.Address
+	value  *big.Int
+	data   []byte
+}
+
+// simResult 模拟调用的执行结果
+type simResult struct {
+	success bool
+	gasUsed uint64
+	data    []byte
+}
+
+// revertReason 解析回滚数据中的错误信息
+func (r *simResult) revertReason() string {
+	if reason, err := abi.UnpackRevert(r.data); err == nil {
+		return reason
+	}
+	return "execution reverted"
+}
+
+// encodeSimCalls 按模拟合约的格式编码调用列表
+func encodeSimCalls(calls []simCall) []byte {
+	var data []byte
+	for _, call := range calls {
+		value := call.value
+		if value == nil {
+			value = new(big.Int)
+		}
+		data = append(data, call.target.Bytes()...)
+		data = append(data, common.BigToHash(value).Bytes()...)
+		data = append(data, common.BigToHash(big.NewInt(int64(len(call.data)))).Bytes()...)
+		data = append(data, call.data...)
+	}
+	return data
+}
+
+// decodeSimResults 解析模拟合约的返回数据
+func decodeSimResults(output []byte) ([]*simResult, error) {
+	var results []*simResult
+	for pos := 0; pos < len(output); {
+		if pos+96 > len(output) {
+			return nil, fmt.Errorf("truncated simulation result")
+		}
+		length := new(big.Int).SetBytes(output[pos+64 : pos+96])
+		if !length.IsUint64() || uint64(len(output)-pos-96) < length.Uint64() {
+			return nil, fmt.Errorf("truncated simulation result")
+		}
+		end := pos + 96 + int(length.Uint64())
+		results = append(results, &simResult{
+			success: new(big.Int).SetBytes(output[pos:pos+32]).Sign() != 0,
+			gasUsed: binary.BigEndian.Uint64(output[pos+56 : pos+64]),
+			data:    output[pos+96 : end],
+		})
+		pos = end
+	}
+	return results, nil
+}
+
+// simulate 在simulatorAddress部署模拟合约并按顺序执行调用，不修改链上状态
+// balance为模拟合约持有的BNB余额
+func (s *BSCService) simulate(ctx context.Context, balance *big.Int, calls []simCall) ([]*simResult, error) {
+	return s.simulateAs(ctx, simulatorAddress, balance, calls)
+}
+
+// simulateAs 在account部署模拟合约并以account为调用方按顺序执行调用，account保留链上的代币余额和授权
+// balance为account持有的BNB余额，为nil时使用链上余额
+func (s *BSCService) simulateAs(ctx context.Context, account common.Address, balance *big.Int, calls []simCall) ([]*simResult, error) {
+	output, err := s.client.CallContractWithOverrides(ctx, ethereum.CallMsg{
+		From: simulationCaller,
+		To:   &account,
+		Data: encodeSimCalls(calls),
+	}, map[common.Address]overrideAccount{
+		account: {Code: simulatorCode, Balance: (*hexutil.Big)(balance)},
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to run simulation: %w", err)
+	}
+
+	results, err := decodeSimResults(output)
+	if err != nil {
+		return nil, err
+	}
+	if len(results) != len(calls) {
+		return nil, fmt.Errorf("simulation returned %d results for %d calls", len(results), len(calls))
+	}
+	return results, nil
+}
+
+// 模拟执行使用的地址，均为无私钥的固定地址
+var (
+	simulatorAddress = common.HexToAddress("0x51A7A7E5000000000000000000000000000051A7")
+	simulationCaller = common.HexToAddress("0x51A7A7E5000000000000000000000000000CA11E")
+	simulationWallet = common.HexToAddress("0x51A7A7E500000000000000000000000000000A11")
+)
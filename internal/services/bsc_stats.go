@@ -0,0 +1,289 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"chain/internal/models"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 24小时统计相关参数
+const (
+	statsWindow          = 24 * time.Hour
+	snapshotInterval     = 5 * time.Minute // 同一代币两次价格快照的最小间隔
+	snapshotMaxAge       = 48 * time.Hour  // 作为涨跌幅基准的快照最早时间
+	blockTimeSampleSize  = 1000            // 估算出块时间时采样的区块数
+	defaultLogBlockRange = 5000
+	defaultStatsCacheTTL = 5 * time.Minute
+)
+
+// marketStats 缓存的代币24小时统计数据
+type marketStats struct {
+	volume24h      string
+	priceChange24h string
+	expiresAt      time.Time
+}
+
+// SetSnapshotStore 设置价格快照存储，默认使用内存存储
+func (s *BSCService) SetSnapshotStore(store PriceSnapshotStore) {
+	s.snapshots = store
+}
+
+// fillMarketStats 填充代币的24小时成交量（USD）和涨跌幅（百分比），并记录当前价格快照
+// 统计失败时对应字段保持为0
+func (s *BSCService) fillMarketStats(info *PriceInfo, token common.Address, decimals, usdDecimals uint8, priceInUSDRaw *big.Int) {
+	info.Volume24h, info.PriceChange24h = "0", "0"
+
+	s.statsMu.Lock()
+	stats, ok := s.statsCache[token]
+	s.statsMu.Unlock()
+
+	if !ok || time.Now().After(stats.expiresAt) {
+		stats = &marketStats{volume24h: "0", priceChange24h: "0", expiresAt: time.Now().Add(s.statsTTL)}
+
+		if priceInUSDRaw.Sign() > 0 {
+			volume, err := s.getVolume24h(token)
+			if err != nil {
+				logger.Warnf("Failed to get 24h volume of %s: %v", token.Hex(), err)
+			} else {
+				// USD成交量 = 代币成交量 * 1个完整代币的USDT价格 / 10^代币精度
+				volumeUSD := new(big.Int).Mul(volume, priceInUSDRaw)
+				volumeUSD.Quo(volumeUSD, pow10(decimals))
+				stats.volume24h = formatUnits(volumeUSD, usdDecimals)
+			}
+		}
+
+		change, err := s.getPriceChange24h(token, info)
+		if err != nil {
+			logger.Warnf("Failed to get 24h price change of %s: %v", token.Hex(), err)
+		} else {
+			stats.priceChange24h = change
+		}
+
+		s.statsMu.Lock()
+		s.statsCache[token] = stats
+		s.statsMu.Unlock()
+	}
+
+	info.Volume24h, info.PriceChange24h = stats.volume24h, stats.priceChange24h
+	s.recordSnapshot(token, info)
+}
+
+// recordSnapshot 保存当前价格快照，同一代币在snapshotInterval内只保存一次
+// 记录上次保存时间的代币数量不超过memorySnapshotMaxTokens
+func (s *BSCService) recordSnapshot(token common.Address, info *PriceInfo) {
+	now := time.Now()
+
+	s.statsMu.Lock()
+	last, ok := s.lastSnapshot[token]
+	if ok && now.Sub(last) < snapshotInterval {
+		s.statsMu.Unlock()
+		return
+	}
+	if !ok && len(s.lastSnapshot) >= memorySnapshotMaxTokens {
+		// 超过snapshotInterval的记录不再影响是否保存快照，没有这样的记录时删除最早的记录
+		var oldest common.Address
+		var oldestAt time.Time
+		for address, at := range s.lastSnapshot {
+			if now.Sub(at) >= snapshotInterval {
+				delete(s.lastSnapshot, address)
+				continue
+			}
+			if oldestAt.IsZero() || at.Before(oldestAt) {
+				oldest, oldestAt = address, at
+			}
+		}
+		if len(s.lastSnapshot) >= memorySnapshotMaxTokens {
+			delete(s.lastSnapshot, oldest)
+		}
+	}
+	s.lastSnapshot[token] = now
+	s.statsMu.Unlock()
+
+	err := s.snapshots.SaveSnapshot(&models.PriceSnapshot{
+		TokenAddress: token.Hex(),
+		PriceUSD:     info.PriceInUSD,
+		PriceBNB:     info.PriceInBNB,
+		ChainID:      s.chainID.Uint64(),
+		Timestamp:    now,
+	})
+	if err != nil {
+		logger.Warnf("Failed to save price snapshot of %s: %v", token.Hex(), err)
+	}
+}
+
+// getPriceChange24h 与24小时前的价格快照比较计算涨跌幅，优先使用USD价格
+func (s *BSCService) getPriceChange24h(token common.Address, info *PriceInfo) (string, error) {
+	now := time.Now()
+	snapshot, err := s.snapshots.GetSnapshotBefore(token.Hex(), now.Add(-statsWindow))
+	if err != nil {
+		return "", err
+	}
+	if snapshot == nil || snapshot.Timestamp.Before(now.Add(-snapshotMaxAge)) {
+		return "0", nil
+	}
+
+	current, previous := info.PriceInUSD, snapshot.PriceUSD
+	if !isPositiveDecimal(current) || !isPositiveDecimal(previous) {
+		current, previous = info.PriceInBNB, snapshot.PriceBNB
+	}
+	if !isPositiveDecimal(current) || !isPositiveDecimal(previous) {
+		return "0", nil
+	}
+
+	currentRat, _ := new(big.Rat).SetString(current)
+	previousRat, _ := new(big.Rat).SetString(previous)
+	change := new(big.Rat).Sub(currentRat, previousRat)
+	change.Quo(change, previousRat)
+	change.Mul(change, big.NewRat(100, 1))
+	return formatRat(change, 4), nil
+}
+
+// isPositiveDecimal 判断十进制字符串是否为正数
+func isPositiveDecimal(value string) bool {
+	rat, ok := new(big.Rat).SetString(value)
+	return ok && rat.Sign() > 0
+}
+
+// getVolume24h 汇总代币与各基础代币V2交易对过去24小时Swap事件中该代币一侧的成交数量
+func (s *BSCService) getVolume24h(token common.Address) (*big.Int, error) {
+	// 并发查找代币与各基础代币的交易对，查询由批量读取合并为一次请求
+	found := make([]string, len(s.baseTokens))
+	errs := make([]error, len(s.baseTokens))
+	var wg sync.WaitGroup
+	for i, base := range s.baseTokens {
+		if base == token {
+			continue
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			found[i], errs[i] = s.getLiquidityPool(token.Hex(), base.Hex())
+		}()
+	}
+	wg.Wait()
+	if err := errors.Join(errs...); err != nil {
+		return nil, err
+	}
+
+	// 记录代币是否为token0
+	pairs := make(map[common.Address]bool)
+	var addresses []common.Address
+	for i, base := range s.baseTokens {
+		pairAddr := common.HexToAddress(found[i])
+		if base == token || pairAddr == (common.Address{}) {
+			continue
+		}
+		token0, _ := sortTokens(token, base)
+		pairs[pairAddr] = token0 == token
+		addresses = append(addresses, pairAddr)
+	}
+
+	volume := big.NewInt(0)
+	if len(addresses) == 0 {
+		return volume, nil
+	}
+
+	fromBlock, toBlock, err := s.getBlockRangeSince(time.Now().Add(-statsWindow))
+	if err != nil {
+		return nil, err
+	}
+
+	parsedABI, err := parseABI(pairABI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pair ABI: %w", err)
+	}
+	swapEvent := parsedABI.Events["Swap"]
+
+	logs, err := s.filterLogs(ethereum.FilterQuery{
+		Addresses: addresses,
+		Topics:    [][]common.Hash{{swapEvent.ID}},
+	}, fromBlock, toBlock)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, log := range logs {
+		isToken0, ok := pairs[log.Address]
+		if !ok {
+			continue
+		}
+		values, err := swapEvent.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack Swap event: %w", err)
+		}
+		// amount0In, amount1In, amount0Out, amount1Out
+		if isToken0 {
+			volume.Add(volume, values[0].(*big.Int))
+			volume.Add(volume, values[2].(*big.Int))
+		} else {
+			volume.Add(volume, values[1].(*big.Int))
+			volume.Add(volume, values[3].(*big.Int))
+		}
+	}
+	return volume, nil
+}
+
+// getBlockRangeSince 根据平均出块时间估算从指定时间到最新区块的区块范围
+func (s *BSCService) getBlockRangeSince(since time.Time) (uint64, uint64, error) {
+	ctx := context.Background()
+	latest, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get latest block: %w", err)
+	}
+	toBlock := latest.Number.Uint64()
+	if toBlock < blockTimeSampleSize {
+		return 0, toBlock, nil
+	}
+
+	sample, err := s.client.HeaderByNumber(ctx, new(big.Int).SetUint64(toBlock-blockTimeSampleSize))
+	if err != nil {
+		return 0, 0, fmt.Errorf("failed to get sample block: %w", err)
+	}
+
+	// 区块时间戳只精确到秒，跨越多个区块取平均值以得到较准确的出块时间
+	elapsed := time.Duration(latest.Time-sample.Time) * time.Second
+	if elapsed <= 0 {
+		return 0, 0, fmt.Errorf("invalid block timestamps")
+	}
+	blockTime := elapsed / blockTimeSampleSize
+
+	window := time.Unix(int64(latest.Time), 0).Sub(since)
+	if window <= 0 {
+		return toBlock, toBlock, nil
+	}
+	blocks := uint64(window / blockTime)
+	if blocks > toBlock {
+		return 0, toBlock, nil
+	}
+	return toBlock - blocks, toBlock, nil
+}
+
+// filterLogs 按logBlockRange分段查询区块范围内的事件日志
+func (s *BSCService) filterLogs(query ethereum.FilterQuery, fromBlock, toBlock uint64) ([]types.Log, error) {
+	var logs []types.Log
+	for start := fromBlock; start <= toBlock; start += s.logBlockRange {
+		end := start + s.logBlockRange - 1
+		if end > toBlock {
+			end = toBlock
+		}
+
+		query.FromBlock = new(big.Int).SetUint64(start)
+		query.ToBlock = new(big.Int).SetUint64(end)
+		chunk, err := s.client.FilterLogs(context.Background(), query)
+		if err != nil {
+			return nil, fmt.Errorf("failed to filter logs in blocks %d-%d: %w", start, end, err)
+		}
+		logs = append(logs, chunk...)
+	}
+	return logs, nil
+}
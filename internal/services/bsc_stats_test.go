@@ -0,0 +1,175 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+	"time"
+
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTokenPriceVolume24h(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Volume Token", "VOL", 18)
+	pair := chain.addPair(token, tokens["WBNB"], 1_000_000, 1_000)
+	other, _ := chain.findPair(tokens["WBNB"], tokens["USDT"])
+
+	whole := func(n int64) *big.Int { return new(big.Int).Mul(big.NewInt(n), pow10(18)) }
+	zero := big.NewInt(0)
+	_, fakePair := chain.findPair(token, tokens["WBNB"])
+	tokenIsToken0 := fakePair.token0 == token
+
+	// 24小时内卖出100个、买入50个，另有一条过期事件和一条其他交易对的事件
+	swap := func(age time.Duration, tokenIn, tokenOut int64) {
+		if tokenIsToken0 {
+			chain.addSwapLog(pair, age, whole(tokenIn), zero, whole(tokenOut), zero)
+		} else {
+			chain.addSwapLog(pair, age, zero, whole(tokenIn), zero, whole(tokenOut))
+		}
+	}
+	swap(time.Hour, 100, 0)
+	swap(12*time.Hour, 0, 50)
+	swap(30*time.Hour, 1_000, 0)
+	chain.addSwapLog(other, time.Hour, whole(1_000), zero, zero, whole(1_000))
+
+	service := newTestBSCService(chain)
+	price, err := service.GetTokenPrice(token.Hex(), "")
+	require.NoError(t, err)
+
+	usdRaw, ok := new(big.Int).SetString(price.PriceInUSDRaw, 10)
+	require.True(t, ok)
+	require.Positive(t, usdRaw.Sign())
+	assert.Equal(t, formatUnits(new(big.Int).Mul(usdRaw, big.NewInt(150)), 18), price.Volume24h)
+
+	// 28800个区块按5000个一段分6次查询
+	filterCalls := chain.calls["FilterLogs"]
+	assert.Equal(t, 6, filterCalls)
+
+	// 缓存期内不再查询日志
+	_, err = service.GetTokenPrice(token.Hex(), "")
+	require.NoError(t, err)
+	assert.Equal(t, filterCalls, chain.calls["FilterLogs"])
+}
+
+func TestGetTokenPriceChange24h(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Change Token", "CHG", 18)
+	chain.addPair(token, tokens["WBNB"], 1_000_000, 1_000)
+
+	service := newTestBSCService(chain)
+	require.NoError(t, service.snapshots.SaveSnapshot(&models.PriceSnapshot{
+		TokenAddress: token.Hex(),
+		PriceUSD:     "0.2",
+		PriceBNB:     "0.001",
+		Timestamp:    time.Now().Add(-25 * time.Hour),
+	}))
+
+	price, err := service.GetTokenPrice(token.Hex(), "")
+	require.NoError(t, err)
+
+	current, ok := new(big.Rat).SetString(price.PriceInUSD)
+	require.True(t, ok)
+	expected := new(big.Rat).Sub(current, big.NewRat(1, 5))
+	expected.Quo(expected, big.NewRat(1, 5)).Mul(expected, big.NewRat(100, 1))
+	assert.Equal(t, formatRat(expected, 4), price.PriceChange24h)
+
+	// 当前价格已记录为快照
+	snapshot, err := service.snapshots.GetSnapshotBefore(token.Hex(), time.Now())
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	assert.Equal(t, price.PriceInUSD, snapshot.PriceUSD)
+	assert.Equal(t, uint64(56), snapshot.ChainID)
+}
+
+func TestGetTokenPriceChangeIgnoresStaleSnapshot(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Stale Token", "STL", 18)
+	chain.addPair(token, tokens["WBNB"], 1_000_000, 1_000)
+
+	service := newTestBSCService(chain)
+	require.NoError(t, service.snapshots.SaveSnapshot(&models.PriceSnapshot{
+		TokenAddress: token.Hex(),
+		PriceUSD:     "0.2",
+		Timestamp:    time.Now().Add(-72 * time.Hour),
+	}))
+
+	price, err := service.GetTokenPrice(token.Hex(), "")
+	require.NoError(t, err)
+	assert.Equal(t, "0", price.PriceChange24h)
+}
+
+func TestRecordSnapshotBoundsLastSnapshot(t *testing.T) {
+	chain, _ := newRouteTestChain()
+	service := newTestBSCService(chain)
+
+	// 所有记录都在snapshotInterval内时淘汰最早的记录
+	now := time.Now()
+	oldest := common.BigToAddress(big.NewInt(1))
+	for i := 0; i < memorySnapshotMaxTokens; i++ {
+		service.lastSnapshot[common.BigToAddress(big.NewInt(int64(i+1)))] = now.Add(-time.Minute + time.Duration(i)*time.Millisecond)
+	}
+
+	token := common.HexToAddress("0x0000000000000000000000000000000000abcdef")
+	service.recordSnapshot(token, &PriceInfo{PriceInUSD: "1", PriceInBNB: "0.001"})
+	assert.Len(t, service.lastSnapshot, memorySnapshotMaxTokens)
+	assert.Contains(t, service.lastSnapshot, token)
+	assert.NotContains(t, service.lastSnapshot, oldest)
+
+	snapshot, err := service.snapshots.GetSnapshotBefore(token.Hex(), time.Now())
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+}
+
+func TestMemorySnapshotStore(t *testing.T) {
+	store := NewMemorySnapshotStore()
+	now := time.Now()
+	token := "0x00000000000000000000000000000000000000AA"
+
+	// 乱序写入
+	for _, hours := range []int{10, 30, 20} {
+		require.NoError(t, store.SaveSnapshot(&models.PriceSnapshot{
+			TokenAddress: token,
+			PriceUSD:     big.NewInt(int64(hours)).String(),
+			Timestamp:    now.Add(-time.Duration(hours) * time.Hour),
+		}))
+	}
+
+	snapshot, err := store.GetSnapshotBefore("0x00000000000000000000000000000000000000aa", now.Add(-15*time.Hour))
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+	assert.Equal(t, "20", snapshot.PriceUSD)
+
+	snapshot, err = store.GetSnapshotBefore(token, now.Add(-40*time.Hour))
+	require.NoError(t, err)
+	assert.Nil(t, snapshot)
+}
+
+func TestMemorySnapshotStoreLimits(t *testing.T) {
+	store := NewMemorySnapshotStore().(*memorySnapshotStore)
+	store.maxTokens = 2
+	now := time.Now()
+	save := func(token string, age time.Duration) {
+		require.NoError(t, store.SaveSnapshot(&models.PriceSnapshot{TokenAddress: token, PriceUSD: "1", Timestamp: now.Add(-age)}))
+	}
+
+	// 不同大小写和前缀的地址使用同一个键，保存时清理过期快照
+	save("0x00000000000000000000000000000000000000aa", 72*time.Hour)
+	save("00000000000000000000000000000000000000AA", time.Hour)
+	save("0x00000000000000000000000000000000000000Aa", 0)
+	assert.Len(t, store.snapshots, 1)
+	assert.Len(t, store.snapshots[common.HexToAddress("0xaa")], 2)
+
+	// 代币数量达到上限时淘汰最久未更新的代币
+	save("0x00000000000000000000000000000000000000bb", 2*time.Hour)
+	save("0x00000000000000000000000000000000000000cc", 0)
+	assert.Len(t, store.snapshots, 2)
+	assert.NotContains(t, store.snapshots, common.HexToAddress("0xbb"))
+
+	snapshot, err := store.GetSnapshotBefore("0x00000000000000000000000000000000000000AA", now)
+	require.NoError(t, err)
+	require.NotNil(t, snapshot)
+}
@@ -0,0 +1,590 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"errors"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// NativeBNB 兑换请求中表示原生BNB的代币标识
+const NativeBNB = "BNB"
+
+// DefaultSwapDeadline 兑换交易默认的截止时间
+const DefaultSwapDeadline = 20 * time.Minute
+
+// 兑换的鉴权错误
+var (
+	ErrSwapUnauthorized = errors.New("invalid or missing swap admin token")
+	ErrSwapDisabled     = errors.New("swaps disabled: admin token not configured")
+)
+
+// ErrSwapRecipientNotAllowed 接收地址不在允许列表中
+var ErrSwapRecipientNotAllowed = errors.New("swap recipient not allowed")
+
+// PancakeSwap Router 兑换方法ABI
+const pancakeRouterSwapABI = `[
+	{
+		"constant": true,
+		"inputs": [
+			{"name": "amountOut", "type": "uint256"},
+			{"name": "path", "type": "address[]"}
+		],
+		"name": "getAmountsIn",
+		"outputs": [{"name": "amounts", "type": "uint256[]"}],
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "amountIn", "type": "uint256"},
+			{"name": "amountOutMin", "type": "uint256"},
+			{"name": "path", "type": "address[]"},
+			{"name": "to", "type": "address"},
+			{"name": "deadline", "type": "uint256"}
+		],
+		"name": "swapExactTokensForTokens",
+		"outputs": [{"name": "amounts", "type": "uint256[]"}],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "amountOut", "type": "uint256"},
+			{"name": "amountInMax", "type": "uint256"},
+			{"name": "path", "type": "address[]"},
+			{"name": "to", "type": "address"},
+			{"name": "deadline", "type": "uint256"}
+		],
+		"name": "swapTokensForExactTokens",
+		"outputs": [{"name": "amounts", "type": "uint256[]"}],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "amountOutMin", "type": "uint256"},
+			{"name": "path", "type": "address[]"},
+			{"name": "to", "type": "address"},
+			{"name": "deadline", "type": "uint256"}
+		],
+		"name": "swapExactETHForTokens",
+		"outputs": [{"name": "amounts", "type": "uint256[]"}],
+		"stateMutability": "payable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "amountOut", "type": "uint256"},
+			{"name": "path", "type": "address[]"},
+			{"name": "to", "type": "address"},
+			{"name": "deadline", "type": "uint256"}
+		],
+		"name": "swapETHForExactTokens",
+		"outputs": [{"name": "amounts", "type": "uint256[]"}],
+		"stateMutability": "payable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "amountIn", "type": "uint256"},
+			{"name": "amountOutMin", "type": "uint256"},
+			{"name": "path", "type": "address[]"},
+			{"name": "to", "type": "address"},
+			{"name": "deadline", "type": "uint256"}
+		],
+		"name": "swapExactTokensForETH",
+		"outputs": [{"name": "amounts", "type": "uint256[]"}],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "amountOut", "type": "uint256"},
+			{"name": "amountInMax", "type": "uint256"},
+			{"name": "path", "type": "address[]"},
+			{"name": "to", "type": "address"},
+			{"name": "deadline", "type": "uint256"}
+		],
+		"name": "swapTokensForExactETH",
+		"outputs": [{"name": "amounts", "type": "uint256[]"}],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "amountIn", "type": "uint256"},
+			{"name": "amountOutMin", "type": "uint256"},
+			{"name": "path", "type": "address[]"},
+			{"name": "to", "type": "address"},
+			{"name": "deadline", "type": "uint256"}
+		],
+		"name": "swapExactTokensForTokensSupportingFeeOnTransferTokens",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "amountOutMin", "type": "uint256"},
+			{"name": "path", "type": "address[]"},
+			{"name": "to", "type": "address"},
+			{"name": "deadline", "type": "uint256"}
+		],
+		"name": "swapExactETHForTokensSupportingFeeOnTransferTokens",
+		"outputs": [],
+		"stateMutability": "payable",
+		"type": "function"
+	},
+	{
+		"inputs": [
+			{"name": "amountIn", "type": "uint256"},
+			{"name": "amountOutMin", "type": "uint256"},
+			{"name": "path", "type": "address[]"},
+			{"name": "to", "type": "address"},
+			{"name": "deadline", "type": "uint256"}
+		],
+		"name": "swapExactTokensForETHSupportingFeeOnTransferTokens",
+		"outputs": [],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	}
+]`
+
+// SwapRequest 兑换交易请求
+type SwapRequest struct {
+	TokenIn       string        // 输入代币地址，BNB表示原生BNB
+	TokenOut      string        // 输出代币地址，BNB表示原生BNB
+	Amount        string        // 精确输入时为输入数量，精确输出时为输出数量
+	ExactOut      bool          // 是否为精确输出兑换
+	SlippageBps   uint32        // 滑点容忍度（基点），0表示使用默认值
+	Deadline      time.Duration // 交易有效期，0表示使用默认值
+	Recipient     string        // 接收地址，为空时为签名账户，其他地址需在允许列表中
+	FeeOnTransfer bool          // 使用支持转账扣费代币的方法，仅支持精确输入
+}
+
+// SwapResult 兑换交易结果
+type SwapResult struct {
+	TxHash        string   `json:"tx_hash"`
+	ApproveTxHash string   `json:"approve_tx_hash,omitempty"` // 自动授权交易，已有足够授权时为空
+	Method        string   `json:"method"`
+	BlockNumber   uint64   `json:"block_number"`
+	GasUsed       uint64   `json:"gas_used"`
+	TokenIn       string   `json:"token_in"`
+	TokenOut      string   `json:"token_out"`
+	Recipient     string   `json:"recipient"`
+	Route         []string `json:"route"`
+	AmountIn      string   `json:"amount_in"` // 由首个交易对的Swap事件解析的交易对实际收到的数量
+	AmountInRaw   string   `json:"amount_in_raw"`
+	AmountOut     string   `json:"amount_out"` // 由最后一个交易对的Swap事件解析的输出数量，转账扣费兑换为接收地址在交易所在区块的余额增加量
+	AmountOutRaw  string   `json:"amount_out_raw"`
+	AmountOutMin  string   `json:"amount_out_min_raw,omitempty"` // 精确输入时的最少输出
+	AmountInMax   string   `json:"amount_in_max_raw,omitempty"`  // 精确输出时的最多输入
+	SlippageBps   uint32   `json:"slippage_bps"`
+	Deadline      int64    `json:"deadline"`
+}
+
+// isNativeBNB 判断代币标识是否表示原生BNB
+func isNativeBNB(token string) bool {
+	return strings.EqualFold(token, NativeBNB)
+}
+
+// swapTokenAddress 返回兑换路径中使用的代币地址，原生BNB对应WBNB
+func swapTokenAddress(token string) common.Address {
+	if isNativeBNB(token) {
+		return common.HexToAddress(WBNBAddress)
+	}
+	return common.HexToAddress(token)
+}
+
+// swapMethod 根据兑换方向和类型选择Router方法
+func swapMethod(nativeIn, nativeOut, exactOut, feeOnTransfer bool) string {
+	var method string
+	switch {
+	case exactOut && nativeIn:
+		method = "swapETHForExactTokens"
+	case exactOut && nativeOut:
+		method = "swapTokensForExactETH"
+	case exactOut:
+		method = "swapTokensForExactTokens"
+	case nativeIn:
+		method = "swapExactETHForTokens"
+	case nativeOut:
+		method = "swapExactTokensForETH"
+	default:
+		method = "swapExactTokensForTokens"
+	}
+	if feeOnTransfer {
+		method += "SupportingFeeOnTransferTokens"
+	}
+	return method
+}
+
+// getAmountsIn 调用Router的getAmountsIn查询获得指定输出所需的每一跳输入数量
+func (s *BSCService) getAmountsIn(amountOut *big.Int, path []common.Address) ([]*big.Int, error) {
+	output, err := s.callContract(pancakeRouterSwapABI, common.HexToAddress(PancakeSwapV2Router), "getAmountsIn", amountOut, path)
+	if err != nil {
+		return nil, err
+	}
+
+	amounts := output[0].([]*big.Int)
+	if len(amounts) != len(path) {
+		return nil, fmt.Errorf("invalid amounts output")
+	}
+	return amounts, nil
+}
+
+// AuthorizeSwap 校验执行兑换的令牌，未配置令牌时所有兑换都被拒绝
+func (s *BSCService) AuthorizeSwap(token string) error {
+	if s.swapAdminToken == "" {
+		return ErrSwapDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.swapAdminToken)) != 1 {
+		return ErrSwapUnauthorized
+	}
+	return nil
+}
+
+// Swap 通过PancakeSwap V2 Router执行兑换，必要时自动授权输入代币
+// 授权和兑换交易的确认等待共用ctx，ctx没有截止时间时整个兑换最多等待receiptTimeout
+func (s *BSCService) Swap(ctx context.Context, req SwapRequest) (*SwapResult, error) {
+	if s.privateKey == nil {
+		return nil, ErrNoSigner
+	}
+
+	slippageBps := req.SlippageBps
+	if slippageBps == 0 {
+		slippageBps = DefaultSlippageBps
+	}
+	if slippageBps > MaxSlippageBps {
+		return nil, fmt.Errorf("slippage must not exceed %d bps", MaxSlippageBps)
+	}
+	if req.ExactOut && req.FeeOnTransfer {
+		return nil, fmt.Errorf("fee-on-transfer swaps only support exact input")
+	}
+
+	nativeIn, nativeOut := isNativeBNB(req.TokenIn), isNativeBNB(req.TokenOut)
+	tokenIn, tokenOut := swapTokenAddress(req.TokenIn), swapTokenAddress(req.TokenOut)
+	if tokenIn == tokenOut {
+		return nil, fmt.Errorf("token_in and token_out must be different")
+	}
+
+	// 接收地址只能是签名账户或配置允许的地址
+	recipient := s.address
+	if req.Recipient != "" {
+		if !common.IsHexAddress(req.Recipient) {
+			return nil, fmt.Errorf("invalid recipient address: %s", req.Recipient)
+		}
+		recipient = common.HexToAddress(req.Recipient)
+		if recipient != s.address && !s.swapRecipients[recipient] {
+			return nil, fmt.Errorf("%w: %s", ErrSwapRecipientNotAllowed, recipient.Hex())
+		}
+	}
+
+	decimalsIn, err := s.getTokenDecimals(tokenIn.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decimals of %s: %w", tokenIn.Hex(), err)
+	}
+	decimalsOut, err := s.getTokenDecimals(tokenOut.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get decimals of %s: %w", tokenOut.Hex(), err)
+	}
+
+	// 数量的精度取决于兑换类型
+	amountDecimals := decimalsIn
+	if req.ExactOut {
+		amountDecimals = decimalsOut
+	}
+	amount, err := parseUnits(req.Amount, amountDecimals)
+	if err != nil {
+		return nil, fmt.Errorf("invalid amount: %w", err)
+	}
+	if amount.Sign() <= 0 {
+		return nil, fmt.Errorf("amount must be positive")
+	}
+
+	// Router只能执行V2路径
+	var route *routeQuote
+	if req.ExactOut {
+		route, err = s.findBestRouteExactOut(tokenIn, tokenOut, amount)
+	} else {
+		route, err = s.findBestRoute(tokenIn, tokenOut, amount)
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	deadlineDuration := req.Deadline
+	if deadlineDuration <= 0 {
+		deadlineDuration = DefaultSwapDeadline
+	}
+	deadline := time.Now().Add(deadlineDuration).Unix()
+
+	result := &SwapResult{
+		Method:      swapMethod(nativeIn, nativeOut, req.ExactOut, req.FeeOnTransfer),
+		TokenIn:     req.TokenIn,
+		TokenOut:    req.TokenOut,
+		Recipient:   recipient.Hex(),
+		Route:       route.pathStrings(),
+		SlippageBps: slippageBps,
+		Deadline:    deadline,
+	}
+
+	// 按滑点计算成交限制
+	var args []interface{}
+	var value, spend *big.Int
+	deadlineArg := big.NewInt(deadline)
+	if req.ExactOut {
+		amountInMax := new(big.Int).Mul(route.amounts[0], big.NewInt(int64(10000+slippageBps)))
+		amountInMax.Quo(amountInMax, big.NewInt(10000))
+		result.AmountInMax = amountInMax.String()
+
+		if nativeIn {
+			args = []interface{}{amount, route.path, recipient, deadlineArg}
+			value = amountInMax
+		} else {
+			args = []interface{}{amount, amountInMax, route.path, recipient, deadlineArg}
+			spend = amountInMax
+		}
+	} else {
+		// 转账扣费代币的报价未扣除转账费用，按模拟执行的实际到账数量计算最少输出
+		expected := route.amountOut()
+		if req.FeeOnTransfer {
+			expected, err = s.simulateFeeOnTransferSwap(ctx, route.path, nativeIn, amount, recipient, deadlineArg)
+			if err != nil {
+				return nil, err
+			}
+		}
+		amountOutMin := new(big.Int).Mul(expected, big.NewInt(int64(10000-slippageBps)))
+		amountOutMin.Quo(amountOutMin, big.NewInt(10000))
+		result.AmountOutMin = amountOutMin.String()
+
+		if nativeIn {
+			args = []interface{}{amountOutMin, route.path, recipient, deadlineArg}
+			value = amount
+		} else {
+			args = []interface{}{amount, amountOutMin, route.path, recipient, deadlineArg}
+			spend = amount
+		}
+	}
+
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, receiptTimeout)
+		defer cancel()
+	}
+
+	// 授权Router使用输入代币
+	router := common.HexToAddress(PancakeSwapV2Router)
+	if spend != nil {
+		approveTxHash, err := s.ensureAllowance(ctx, tokenIn, router, spend)
+		if err != nil {
+			return nil, fmt.Errorf("failed to approve %s: %w", tokenIn.Hex(), err)
+		}
+		result.ApproveTxHash = approveTxHash
+	}
+
+	parsedABI, err := parseABI(pancakeRouterSwapABI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse router ABI: %w", err)
+	}
+	data, err := parsedABI.Pack(result.Method, args...)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", result.Method, err)
+	}
+
+	tx, receipt, err := s.sendAndWait(ctx, router, value, data)
+	if err != nil {
+		return nil, fmt.Errorf("swap failed: %w", err)
+	}
+	result.TxHash = tx.Hash().Hex()
+	result.BlockNumber = receipt.BlockNumber.Uint64()
+	result.GasUsed = receipt.GasUsed
+
+	amountIn, amountOut, err := s.decodeSwapAmounts(receipt, route.path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode swap %s: %w", result.TxHash, err)
+	}
+	if req.FeeOnTransfer && !nativeOut {
+		// 转账扣费代币的实际到账数量小于Swap事件中的输出，以交易所在区块前后接收地址的余额变化为准
+		amountOut, err = s.receivedInBlock(ctx, tokenOut, recipient, receipt.BlockNumber)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get received amount of swap %s: %w", result.TxHash, err)
+		}
+	}
+	result.AmountIn = formatUnits(amountIn, decimalsIn)
+	result.AmountInRaw = amountIn.String()
+	result.AmountOut = formatUnits(amountOut, decimalsOut)
+	result.AmountOutRaw = amountOut.String()
+
+	logger.Infof("Swapped %s %s for %s %s, tx: %s", result.AmountIn, req.TokenIn, result.AmountOut, req.TokenOut, result.TxHash)
+	return result, nil
+}
+
+// simulateFeeOnTransferSwap 以签名账户为调用方模拟执行转账扣费兑换，返回接收地址实际到账的输出数量
+// 输出为原生BNB时模拟兑换为WBNB，Router解包WBNB后转出的BNB数量相同
+func (s *BSCService) simulateFeeOnTransferSwap(ctx context.Context, path []common.Address, nativeIn bool, amount *big.Int, recipient common.Address, deadline *big.Int) (*big.Int, error) {
+	routerABI, err := parseABI(pancakeRouterSwapABI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse router ABI: %w", err)
+	}
+	tokenABI, err := parseABI(erc20ABI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
+
+	router := common.HexToAddress(PancakeSwapV2Router)
+	tokenOut := path[len(path)-1]
+	balanceData, err := tokenABI.Pack("balanceOf", recipient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack balanceOf call: %w", err)
+	}
+
+	// 输入为代币时先授权Router，模拟执行不会修改链上的授权额度
+	var calls []simCall
+	var balance *big.Int
+	var swapData []byte
+	method := swapMethod(nativeIn, false, false, true)
+	if nativeIn {
+		balance = amount
+		swapData, err = routerABI.Pack(method, big.NewInt(0), path, recipient, deadline)
+	} else {
+		var approveData []byte
+		approveData, err = tokenABI.Pack("approve", router, amount)
+		if err != nil {
+			return nil, fmt.Errorf("failed to pack approve call: %w", err)
+		}
+		calls = append(calls, simCall{target: path[0], data: approveData})
+		swapData, err = routerABI.Pack(method, amount, big.NewInt(0), path, recipient, deadline)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack %s: %w", method, err)
+	}
+	calls = append(calls,
+		simCall{target: tokenOut, data: balanceData},
+		simCall{target: router, value: balance, data: swapData},
+		simCall{target: tokenOut, data: balanceData},
+	)
+
+	results, err := s.simulateAs(ctx, s.address, balance, calls)
+	if err != nil {
+		return nil, err
+	}
+	for _, result := range results {
+		if !result.success {
+			return nil, fmt.Errorf("swap simulation failed: %s", result.revertReason())
+		}
+	}
+	received := new(big.Int).Sub(decodeUint256(results[len(results)-1].data), decodeUint256(results[len(results)-3].data))
+	if received.Sign() <= 0 {
+		return nil, fmt.Errorf("swap simulation returned no %s", tokenOut.Hex())
+	}
+	return received, nil
+}
+
+// receivedInBlock 返回接收地址的代币余额在指定区块中的增加量
+func (s *BSCService) receivedInBlock(ctx context.Context, token, recipient common.Address, block *big.Int) (*big.Int, error) {
+	parsedABI, err := parseABI(erc20ABI)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
+	data, err := parsedABI.Pack("balanceOf", recipient)
+	if err != nil {
+		return nil, fmt.Errorf("failed to pack balanceOf: %w", err)
+	}
+
+	var balances [2]*big.Int
+	for i, number := range []*big.Int{new(big.Int).Sub(block, big.NewInt(1)), block} {
+		output, err := s.client.CallContract(ctx, ethereum.CallMsg{To: &token, Data: data}, number)
+		if err != nil {
+			return nil, fmt.Errorf("failed to call balanceOf at block %s: %w", number, err)
+		}
+		values, err := parsedABI.Unpack("balanceOf", output)
+		if err != nil {
+			return nil, fmt.Errorf("failed to unpack balanceOf: %w", err)
+		}
+		balances[i] = values[0].(*big.Int)
+	}
+	return new(big.Int).Sub(balances[1], balances[0]), nil
+}
+
+// ensureAllowance 确保spender对签名账户代币的授权额度不少于amount，返回授权交易哈希
+func (s *BSCService) ensureAllowance(ctx context.Context, token, spender common.Address, amount *big.Int) (string, error) {
+	output, err := s.callContract(erc20ABI, token, "allowance", s.address, spender)
+	if err != nil {
+		return "", err
+	}
+	if output[0].(*big.Int).Cmp(amount) >= 0 {
+		return "", nil
+	}
+
+	parsedABI, err := parseABI(erc20ABI)
+	if err != nil {
+		return "", fmt.Errorf("failed to parse ERC20 ABI: %w", err)
+	}
+	data, err := parsedABI.Pack("approve", spender, amount)
+	if err != nil {
+		return "", fmt.Errorf("failed to pack approve: %w", err)
+	}
+
+	tx, _, err := s.sendAndWait(ctx, token, nil, data)
+	if err != nil {
+		return "", err
+	}
+	return tx.Hash().Hex(), nil
+}
+
+// decodeSwapAmounts 从交易收据的Swap事件解析兑换数量
+// 输入为路径首个交易对实际收到的数量（amount0In/amount1In），输出为最后一个交易对转出的数量（amount0Out/amount1Out）
+func (s *BSCService) decodeSwapAmounts(receipt *types.Receipt, path []common.Address) (*big.Int, *big.Int, error) {
+	n := len(path)
+	amountIn, _, err := s.decodeSwapEvent(receipt, path[0], path[1])
+	if err != nil {
+		return nil, nil, err
+	}
+	_, amountOut, err := s.decodeSwapEvent(receipt, path[n-2], path[n-1])
+	if err != nil {
+		return nil, nil, err
+	}
+	return amountIn, amountOut, nil
+}
+
+// decodeSwapEvent 解析交易收据中tokenIn兑换为tokenOut的交易对Swap事件，返回该交易对收到的输入和转出的输出数量
+func (s *BSCService) decodeSwapEvent(receipt *types.Receipt, tokenIn, tokenOut common.Address) (*big.Int, *big.Int, error) {
+	parsedABI, err := parseABI(pairABI)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to parse pair ABI: %w", err)
+	}
+	swapEvent := parsedABI.Events["Swap"]
+
+	pair, err := s.getLiquidityPool(tokenIn.Hex(), tokenOut.Hex())
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, log := range receipt.Logs {
+		if len(log.Topics) == 0 || log.Topics[0] != swapEvent.ID || log.Address != common.HexToAddress(pair) {
+			continue
+		}
+
+		// amount0In, amount1In, amount0Out, amount1Out
+		values, err := swapEvent.Inputs.NonIndexed().Unpack(log.Data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to unpack Swap event: %w", err)
+		}
+		token0, _ := sortTokens(tokenIn, tokenOut)
+		if tokenIn == token0 {
+			return values[0].(*big.Int), values[3].(*big.Int), nil
+		}
+		return values[1].(*big.Int), values[2].(*big.Int), nil
+	}
+	return nil, nil, fmt.Errorf("no Swap event of pair %s found in transaction %s", pair, receipt.TxHash.Hex())
+}
@@ -0,0 +1,312 @@
+package services
+
+import (
+	"context"
+	"encoding/hex"
+	"math/big"
+	"testing"
+	"time"
+
+	"chain/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSwapService 创建带签名账户的BSC服务，签名账户持有10 BNB用于支付gas
+// recipients为签名账户以外允许接收兑换输出的地址
+func newTestSwapService(t *testing.T, chain *fakeChain, recipients ...string) (*BSCService, common.Address) {
+	key, err := crypto.GenerateKey()
+	require.NoError(t, err)
+
+	service := newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{
+			ChainID:    56,
+			GasLimit:   21000,
+			PrivateKey: "0x" + hex.EncodeToString(crypto.FromECDSA(key)),
+		},
+		BSC: config.BSCConfig{SwapRecipients: recipients},
+	})
+	account := crypto.PubkeyToAddress(key.PublicKey)
+	require.Equal(t, account.Hex(), service.SignerAddress())
+	chain.nativeBalances[account] = new(big.Int).Mul(big.NewInt(10), pow10(18))
+	return service, account
+}
+
+func TestSwapExactTokensForTokens(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service, account := newTestSwapService(t, chain)
+	chain.mint(tokens["USDT"], account, 1_000)
+
+	// 按报价计算预期输出
+	amountIn := new(big.Int).Mul(big.NewInt(300), pow10(18))
+	_, pair := chain.findPair(tokens["USDT"], tokens["WBNB"])
+	reserveIn, reserveOut := pair.reserve0, pair.reserve1
+	if pair.token0 != tokens["USDT"] {
+		reserveIn, reserveOut = reserveOut, reserveIn
+	}
+	expected := v2AmountOut(amountIn, reserveIn, reserveOut)
+
+	result, err := service.Swap(context.Background(), SwapRequest{
+		TokenIn:     tokens["USDT"].Hex(),
+		TokenOut:    tokens["WBNB"].Hex(),
+		Amount:      "300",
+		SlippageBps: 100,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "swapExactTokensForTokens", result.Method)
+	assert.NotEmpty(t, result.ApproveTxHash)
+	assert.Equal(t, []string{"approve", "swapExactTokensForTokens"}, chain.sentMethods())
+	assert.Equal(t, amountIn.String(), result.AmountInRaw)
+	assert.Equal(t, expected.String(), result.AmountOutRaw)
+	assert.Equal(t, account.Hex(), result.Recipient)
+
+	minimum := new(big.Int).Mul(expected, big.NewInt(9900))
+	assert.Equal(t, minimum.Quo(minimum, big.NewInt(10000)).String(), result.AmountOutMin)
+
+	// 余额和授权额度都已扣除
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(700), pow10(18)), chain.tokens[tokens["USDT"]].balanceOf(account))
+	assert.Equal(t, expected, chain.tokens[tokens["WBNB"]].balanceOf(account))
+	assert.Equal(t, int64(0), chain.tokens[tokens["USDT"]].allowance(account, common.HexToAddress(PancakeSwapV2Router)).Int64())
+}
+
+func TestSwapSkipsApprovalWithAllowance(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service, account := newTestSwapService(t, chain)
+	chain.mint(tokens["USDT"], account, 1_000)
+	chain.tokens[tokens["USDT"]].allowances[account] = map[common.Address]*big.Int{
+		common.HexToAddress(PancakeSwapV2Router): new(big.Int).Mul(big.NewInt(1_000), pow10(18)),
+	}
+
+	result, err := service.Swap(context.Background(), SwapRequest{
+		TokenIn:  tokens["USDT"].Hex(),
+		TokenOut: tokens["BUSD"].Hex(),
+		Amount:   "10",
+	})
+	require.NoError(t, err)
+
+	assert.Empty(t, result.ApproveTxHash)
+	assert.Equal(t, []string{"swapExactTokensForTokens"}, chain.sentMethods())
+	assert.Equal(t, []string{tokens["USDT"].Hex(), tokens["WBNB"].Hex(), tokens["BUSD"].Hex()}, result.Route)
+	assert.Equal(t, result.AmountOutRaw, chain.tokens[tokens["BUSD"]].balanceOf(account).String())
+}
+
+func TestSwapExactBNBForTokens(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service, account := newTestSwapService(t, chain)
+
+	result, err := service.Swap(context.Background(), SwapRequest{
+		TokenIn:  NativeBNB,
+		TokenOut: tokens["CAKE"].Hex(),
+		Amount:   "1",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "swapExactETHForTokens", result.Method)
+	assert.Empty(t, result.ApproveTxHash)
+	assert.Equal(t, "1", result.AmountIn)
+	assert.Equal(t, result.AmountOutRaw, chain.tokens[tokens["CAKE"]].balanceOf(account).String())
+
+	// 交易携带的BNB等于输入数量
+	require.Len(t, chain.sent, 1)
+	assert.Equal(t, pow10(18), chain.sent[0].Value())
+}
+
+func TestSwapTokensForExactBNB(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service, account := newTestSwapService(t, chain)
+	chain.mint(tokens["USDT"], account, 1_000)
+
+	result, err := service.Swap(context.Background(), SwapRequest{
+		TokenIn:  tokens["USDT"].Hex(),
+		TokenOut: NativeBNB,
+		Amount:   "2",
+		ExactOut: true,
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, "swapTokensForExactETH", result.Method)
+	assert.Equal(t, "2", result.AmountOut)
+
+	amountIn, _ := new(big.Int).SetString(result.AmountInRaw, 10)
+	amountInMax, _ := new(big.Int).SetString(result.AmountInMax, 10)
+	assert.True(t, amountIn.Cmp(amountInMax) <= 0)
+	assertDecimalBetween(t, result.AmountIn, "601", "603")
+}
+
+func TestSwapFeeOnTransferToken(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Tax Token", "TAX", 18)
+	chain.tokens[token].transferFeeBps = 500
+	chain.addPair(token, tokens["WBNB"], 1_000_000, 1_000)
+
+	service, account := newTestSwapService(t, chain)
+	chain.mint(token, account, 1_000)
+
+	// 不使用Supporting方法时交易回滚
+	_, err := service.Swap(context.Background(), SwapRequest{TokenIn: token.Hex(), TokenOut: NativeBNB, Amount: "100"})
+	assert.Error(t, err)
+
+	// 按扣除转账费用后的模拟结果计算最少输出，默认滑点即可成交
+	_, pair := chain.findPair(token, tokens["WBNB"])
+	reserveIn, reserveOut := pair.reserve0, pair.reserve1
+	if pair.token0 != token {
+		reserveIn, reserveOut = reserveOut, reserveIn
+	}
+	expected := v2AmountOut(new(big.Int).Mul(big.NewInt(95), pow10(18)), reserveIn, reserveOut)
+
+	result, err := service.Swap(context.Background(), SwapRequest{
+		TokenIn:       token.Hex(),
+		TokenOut:      NativeBNB,
+		Amount:        "100",
+		FeeOnTransfer: true,
+	})
+	require.NoError(t, err)
+
+	// 交易对实际收到扣除5%转账费用后的数量
+	assert.Equal(t, "swapExactTokensForETHSupportingFeeOnTransferTokens", result.Method)
+	assert.Equal(t, "95", result.AmountIn)
+	assert.Equal(t, expected.String(), result.AmountOutRaw)
+	minimum := new(big.Int).Mul(expected, big.NewInt(10000-DefaultSlippageBps))
+	assert.Equal(t, minimum.Quo(minimum, big.NewInt(10000)).String(), result.AmountOutMin)
+}
+
+func TestSwapFeeOnTransferTokenOut(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Tax Token", "TAX", 18)
+	chain.tokens[token].transferFeeBps = 500
+	chain.addPair(token, tokens["WBNB"], 1_000_000, 1_000)
+	recipient := common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	service, account := newTestSwapService(t, chain, recipient.Hex())
+
+	// 兑换之后的区块中接收地址收到的其他转账不计入输出
+	chain.onSend = func() { chain.mint(token, recipient, 50) }
+
+	result, err := service.Swap(context.Background(), SwapRequest{
+		TokenIn:       NativeBNB,
+		TokenOut:      token.Hex(),
+		Amount:        "1",
+		Recipient:     recipient.Hex(),
+		FeeOnTransfer: true,
+	})
+	require.NoError(t, err)
+
+	// 输出数量为接收地址实际到账的数量，而不是交易对转出的数量
+	received := new(big.Int).Sub(chain.tokens[token].balanceOf(recipient), new(big.Int).Mul(big.NewInt(50), pow10(18)))
+	assert.Equal(t, received.String(), result.AmountOutRaw)
+	assertDecimalBetween(t, result.AmountOut, "940", "950")
+	assert.Zero(t, chain.tokens[token].balanceOf(account).Sign())
+
+	// 最少输出按扣除转账费用后的到账数量计算
+	minimum := new(big.Int).Mul(received, big.NewInt(10000-DefaultSlippageBps))
+	assert.Equal(t, minimum.Quo(minimum, big.NewInt(10000)).String(), result.AmountOutMin)
+}
+
+func TestSwapAmountOutFromLastHop(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service, account := newTestSwapService(t, chain)
+	chain.mint(tokens["USDT"], account, 1_000)
+	chain.mint(tokens["BUSD"], account, 5)
+
+	// 兑换之后接收地址收到的其他转账不影响输出数量
+	chain.onSend = func() { chain.mint(tokens["BUSD"], account, 7) }
+
+	amountIn := new(big.Int).Mul(big.NewInt(10), pow10(18))
+	amounts, err := chain.amountsOut(amountIn, []common.Address{tokens["USDT"], tokens["WBNB"], tokens["BUSD"]})
+	require.NoError(t, err)
+
+	result, err := service.Swap(context.Background(), SwapRequest{
+		TokenIn:  tokens["USDT"].Hex(),
+		TokenOut: tokens["BUSD"].Hex(),
+		Amount:   "10",
+	})
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{tokens["USDT"].Hex(), tokens["WBNB"].Hex(), tokens["BUSD"].Hex()}, result.Route)
+	assert.Equal(t, amountIn.String(), result.AmountInRaw)
+	assert.Equal(t, amounts[2].String(), result.AmountOutRaw)
+}
+
+func TestSwapStopsWaitingWhenContextDone(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service, _ := newTestSwapService(t, chain)
+	chain.pending = true
+
+	original := receiptPollInterval
+	receiptPollInterval = time.Millisecond
+	defer func() { receiptPollInterval = original }()
+
+	ctx, cancel := context.WithTimeout(context.Background(), 20*time.Millisecond)
+	defer cancel()
+	_, err := service.Swap(ctx, SwapRequest{TokenIn: NativeBNB, TokenOut: tokens["USDT"].Hex(), Amount: "1"})
+	assert.ErrorIs(t, err, context.DeadlineExceeded)
+	assert.ErrorContains(t, err, chain.sent[0].Hash().Hex())
+}
+
+func TestSwapInvalidRequests(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+
+	// 未配置私钥
+	_, err := newTestBSCService(chain).Swap(context.Background(), SwapRequest{TokenIn: NativeBNB, TokenOut: tokens["USDT"].Hex(), Amount: "1"})
+	assert.ErrorIs(t, err, ErrNoSigner)
+
+	service, _ := newTestSwapService(t, chain)
+	tests := []SwapRequest{
+		{TokenIn: tokens["USDT"].Hex(), TokenOut: NativeBNB, Amount: "1", ExactOut: true, FeeOnTransfer: true},
+		{TokenIn: NativeBNB, TokenOut: tokens["WBNB"].Hex(), Amount: "1"},
+		{TokenIn: NativeBNB, TokenOut: tokens["USDT"].Hex(), Amount: "0"},
+		{TokenIn: NativeBNB, TokenOut: tokens["USDT"].Hex(), Amount: "1", SlippageBps: MaxSlippageBps + 1},
+		{TokenIn: NativeBNB, TokenOut: tokens["USDT"].Hex(), Amount: "1", Recipient: "not-an-address"},
+	}
+	for _, req := range tests {
+		_, err := service.Swap(context.Background(), req)
+		assert.Error(t, err)
+	}
+	assert.Empty(t, chain.sent)
+}
+
+func TestSwapRecipientAllowlist(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	allowed := common.HexToAddress("0x00000000000000000000000000000000000000b0")
+	service, account := newTestSwapService(t, chain, allowed.Hex())
+
+	// 不在允许列表中的接收地址
+	_, err := service.Swap(context.Background(), SwapRequest{
+		TokenIn:   NativeBNB,
+		TokenOut:  tokens["USDT"].Hex(),
+		Amount:    "1",
+		Recipient: "0x00000000000000000000000000000000000000b1",
+	})
+	assert.ErrorIs(t, err, ErrSwapRecipientNotAllowed)
+	assert.Empty(t, chain.sent)
+
+	// 签名账户和允许的地址
+	for _, recipient := range []common.Address{account, allowed} {
+		result, err := service.Swap(context.Background(), SwapRequest{
+			TokenIn:   NativeBNB,
+			TokenOut:  tokens["USDT"].Hex(),
+			Amount:    "1",
+			Recipient: recipient.Hex(),
+		})
+		require.NoError(t, err)
+		assert.Equal(t, recipient.Hex(), result.Recipient)
+	}
+}
+
+func TestAuthorizeSwap(t *testing.T) {
+	chain, _ := newRouteTestChain()
+
+	// 未配置令牌时拒绝所有兑换
+	assert.ErrorIs(t, newTestBSCService(chain).AuthorizeSwap(""), ErrSwapDisabled)
+
+	service := newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{ChainID: 56, GasLimit: 21000},
+		BSC:   config.BSCConfig{SwapAdminToken: "secret"},
+	})
+	assert.ErrorIs(t, service.AuthorizeSwap(""), ErrSwapUnauthorized)
+	assert.ErrorIs(t, service.AuthorizeSwap("wrong"), ErrSwapUnauthorized)
+	assert.NoError(t, service.AuthorizeSwap("secret"))
+}
@@ -0,0 +1,327 @@
+package services
+
+import (
+	"context"
+	"crypto/subtle"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+	"time"
+
+	"chain/internal/models"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 代币列表导入相关参数
+const (
+	tokenListTimeout   = 30 * time.Second
+	maxTokenListSize   = 20 << 20 // 代币列表文件的最大字节数
+	ipfsGateway        = "https://ipfs.io/ipfs/"
+	defaultTokenSource = "default"
+)
+
+// defaultTokens 内置的常用代币，未导入代币列表时也可以搜索
+var defaultTokens = []models.Token{
+	{Address: WBNBAddress, Name: "Wrapped BNB", Symbol: "WBNB", Decimals: 18},
+	{Address: USDTAddress, Name: "Tether USD", Symbol: "USDT", Decimals: 18},
+	{Address: BUSDAddress, Name: "BUSD Token", Symbol: "BUSD", Decimals: 18},
+	{Address: USDCAddress, Name: "USD Coin", Symbol: "USDC", Decimals: 18},
+	{Address: CAKEAddress, Name: "PancakeSwap Token", Symbol: "CAKE", Decimals: 18},
+}
+
+// tokenList Uniswap代币列表格式（https://tokenlists.org）
+type tokenList struct {
+	Name   string           `json:"name"`
+	Tokens []tokenListToken `json:"tokens"`
+}
+
+// tokenListToken 代币列表中的代币
+type tokenListToken struct {
+	ChainID  uint64 `json:"chainId"`
+	Address  string `json:"address"`
+	Name     string `json:"name"`
+	Symbol   string `json:"symbol"`
+	Decimals int    `json:"decimals"`
+	LogoURI  string `json:"logoURI"`
+}
+
+// ErrTokenListNotAllowed 接口导入的代币列表地址不是配置的代币列表，也不在允许的域名中
+var ErrTokenListNotAllowed = errors.New("token list url not allowed")
+
+// 接口导入代币列表的鉴权错误
+var (
+	ErrTokenListUnauthorized = errors.New("invalid or missing token list admin token")
+	ErrTokenListDisabled     = errors.New("token list import disabled: admin token not configured")
+)
+
+// TokenListImportResult 代币列表导入结果
+type TokenListImportResult struct {
+	Name     string `json:"name"`
+	Source   string `json:"source"`
+	Imported int    `json:"imported"` // 导入的当前链代币数量
+	Skipped  int    `json:"skipped"`  // 其他链或格式无效的代币数量
+}
+
+// SetTokenStore 设置代币存储并写入内置代币，默认使用内存存储
+func (s *BSCService) SetTokenStore(store TokenStore) {
+	s.tokens = store
+	if err := store.AddTokens(s.defaultTokens()); err != nil {
+		logger.Warnf("Failed to save default tokens: %v", err)
+	}
+}
+
+// defaultTokens 返回当前链的内置代币
+func (s *BSCService) defaultTokens() []*models.Token {
+	tokens := make([]*models.Token, 0, len(defaultTokens))
+	for _, token := range defaultTokens {
+		copied := token
+		copied.ChainID = s.chainID.Uint64()
+		copied.Verified = true
+		copied.Source = defaultTokenSource
+		tokens = append(tokens, &copied)
+	}
+	return tokens
+}
+
+// SearchTokens 在代币存储中按名称或符号搜索当前链的代币，不访问节点
+func (s *BSCService) SearchTokens(query TokenQuery) ([]*models.Token, error) {
+	if strings.TrimSpace(query.Text) == "" {
+		return nil, fmt.Errorf("search text is required")
+	}
+	query.ChainID = s.chainID.Uint64()
+	return s.tokens.SearchTokens(query)
+}
+
+// FindTokenByName 通过名称或符号查找代币
+func (s *BSCService) FindTokenByName(tokenName string) ([]*models.Token, error) {
+	return s.SearchTokens(TokenQuery{Text: tokenName})
+}
+
+// ImportTokenLists 导入配置的所有代币列表，单个列表失败不影响其他列表
+func (s *BSCService) ImportTokenLists(ctx context.Context) error {
+	var errs []error
+	for _, source := range s.tokenLists {
+		result, err := s.importTokenList(ctx, source, http.DefaultClient)
+		if err != nil {
+			errs = append(errs, fmt.Errorf("failed to import token list %s: %w", source, err))
+			continue
+		}
+		logger.Infof("Imported %d tokens from token list %s (%s), skipped %d", result.Imported, result.Name, source, result.Skipped)
+	}
+	return errors.Join(errs...)
+}
+
+// AuthorizeTokenListImport 校验通过接口导入代币列表的令牌，未配置令牌时所有导入都被拒绝
+func (s *BSCService) AuthorizeTokenListImport(token string) error {
+	if s.tokenListToken == "" {
+		return ErrTokenListDisabled
+	}
+	if subtle.ConstantTimeCompare([]byte(token), []byte(s.tokenListToken)) != 1 {
+		return ErrTokenListUnauthorized
+	}
+	return nil
+}
+
+// ImportTokenList 从HTTP(S)地址导入Uniswap格式的代币列表，导入的代币标记为已验证
+// 地址必须是配置的代币列表或位于tokenListHosts允许的域名下；配置以外的列表只连接公网地址，重定向也只能到允许的域名
+func (s *BSCService) ImportTokenList(ctx context.Context, source string) (*TokenListImportResult, error) {
+	for _, configured := range s.tokenLists {
+		if source == configured {
+			return s.importTokenList(ctx, source, http.DefaultClient)
+		}
+	}
+
+	u, err := url.Parse(source)
+	if err != nil || (u.Scheme != "http" && u.Scheme != "https") || u.Hostname() == "" {
+		return nil, fmt.Errorf("%w: url must start with http:// or https://", ErrTokenListNotAllowed)
+	}
+	if !hostAllowed(u.Hostname(), s.tokenListHosts) {
+		return nil, fmt.Errorf("%w: host %s is not in bsc.token_list_hosts", ErrTokenListNotAllowed, u.Hostname())
+	}
+	result, err := s.importTokenList(ctx, source, s.tokenListClient)
+	if errors.Is(err, ErrRedirectNotAllowed) {
+		return nil, fmt.Errorf("%w: %v", ErrTokenListNotAllowed, err)
+	}
+	return result, err
+}
+
+// hostAllowed 判断域名是否为允许的域名或其子域名
+func hostAllowed(host string, allowed []string) bool {
+	host = strings.ToLower(strings.TrimSuffix(host, "."))
+	for _, entry := range allowed {
+		entry = strings.ToLower(strings.TrimSpace(entry))
+		if entry != "" && (host == entry || strings.HasSuffix(host, "."+entry)) {
+			return true
+		}
+	}
+	return false
+}
+
+// importTokenList 使用client读取本地文件或HTTP(S)地址的代币列表并导入
+func (s *BSCService) importTokenList(ctx context.Context, source string, client *http.Client) (*TokenListImportResult, error) {
+	data, err := readTokenList(ctx, source, client)
+	if err != nil {
+		return nil, err
+	}
+
+	var list tokenList
+	if err := json.Unmarshal(data, &list); err != nil {
+		return nil, fmt.Errorf("failed to parse token list: %w", err)
+	}
+
+	result := &TokenListImportResult{Name: list.Name, Source: source}
+	listName := list.Name
+	if listName == "" {
+		listName = source
+	}
+	if len(listName) > 100 {
+		listName = listName[:100]
+	}
+
+	seen := make(map[common.Address]bool)
+	tokens := make([]*models.Token, 0, len(list.Tokens))
+	for _, entry := range list.Tokens {
+		if entry.ChainID != s.chainID.Uint64() || !validTokenListEntry(entry) {
+			result.Skipped++
+			continue
+		}
+		address := common.HexToAddress(entry.Address)
+		if seen[address] {
+			result.Skipped++
+			continue
+		}
+		seen[address] = true
+
+		tokens = append(tokens, &models.Token{
+			Address:  address.Hex(),
+			Name:     entry.Name,
+			Symbol:   entry.Symbol,
+			Decimals: uint8(entry.Decimals),
+			ChainID:  entry.ChainID,
+			LogoURI:  normalizeLogoURI(entry.LogoURI),
+			Verified: true,
+			Source:   listName,
+		})
+	}
+
+	if err := s.tokens.UpsertTokens(tokens); err != nil {
+		return nil, fmt.Errorf("failed to save tokens: %w", err)
+	}
+	result.Imported = len(tokens)
+	return result, nil
+}
+
+// readTokenList 读取本地文件或HTTP(S)地址的代币列表
+func readTokenList(ctx context.Context, source string, client *http.Client) ([]byte, error) {
+	if !strings.HasPrefix(source, "http://") && !strings.HasPrefix(source, "https://") {
+		file, err := os.Open(source)
+		if err != nil {
+			return nil, fmt.Errorf("failed to open token list: %w", err)
+		}
+		defer file.Close()
+		return readLimited(file)
+	}
+
+	ctx, cancel := context.WithTimeout(ctx, tokenListTimeout)
+	defer cancel()
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, source, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to download token list: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return nil, fmt.Errorf("failed to download token list: status %d", resp.StatusCode)
+	}
+	return readLimited(resp.Body)
+}
+
+// readLimited 读取不超过maxTokenListSize字节的数据
+func readLimited(r io.Reader) ([]byte, error) {
+	data, err := io.ReadAll(io.LimitReader(r, maxTokenListSize+1))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read token list: %w", err)
+	}
+	if len(data) > maxTokenListSize {
+		return nil, fmt.Errorf("token list exceeds %d bytes", maxTokenListSize)
+	}
+	return data, nil
+}
+
+// validTokenListEntry 检查代币列表条目是否符合tokens表的字段限制
+func validTokenListEntry(entry tokenListToken) bool {
+	return common.IsHexAddress(entry.Address) &&
+		entry.Symbol != "" && len(entry.Symbol) <= 20 &&
+		entry.Name != "" && len(entry.Name) <= 100 &&
+		entry.Decimals >= 0 && entry.Decimals <= 255 &&
+		len(entry.LogoURI) <= 512
+}
+
+// normalizeLogoURI 将ipfs://图标地址转换为HTTP网关地址
+func normalizeLogoURI(uri string) string {
+	if strings.HasPrefix(uri, "ipfs://") {
+		return ipfsGateway + strings.TrimPrefix(uri, "ipfs://")
+	}
+	return uri
+}
+
+// discoverTokens 将新交易对中尚未保存的代币作为未验证代币写入代币存储
+// 读取元数据失败或缺少名称、符号的代币被跳过
+func (s *BSCService) discoverTokens(dex *v2Dex, pairs []*models.DexPair) {
+	if len(pairs) == 0 {
+		return
+	}
+
+	seen := make(map[string]bool)
+	var addresses []string
+	for _, pair := range pairs {
+		for _, address := range []string{pair.Token0, pair.Token1} {
+			address = normalizeAddress(address)
+			if !seen[address] {
+				seen[address] = true
+				addresses = append(addresses, address)
+			}
+		}
+	}
+
+	known, err := s.tokens.GetTokens(s.chainID.Uint64(), addresses)
+	if err != nil {
+		logger.Warnf("Failed to get known tokens: %v", err)
+		return
+	}
+	for _, token := range known {
+		delete(seen, normalizeAddress(token.Address))
+	}
+
+	var tokens []*models.Token
+	for _, address := range addresses {
+		if !seen[address] {
+			continue
+		}
+		tokenAddress := common.HexToAddress(address)
+		metadata, err := s.readTokenMetadata(context.Background(), tokenAddress)
+		if err != nil {
+			logger.Debugf("Skipping token %s: %v", address, err)
+			continue
+		}
+		token := metadata.model(tokenAddress, s.chainID.Uint64())
+		token.Source = dex.name
+		if validTokenListEntry(tokenListToken{Address: address, Name: token.Name, Symbol: token.Symbol, Decimals: int(token.Decimals)}) {
+			tokens = append(tokens, token)
+		}
+	}
+
+	if err := s.tokens.AddTokens(tokens); err != nil {
+		logger.Warnf("Failed to save discovered tokens: %v", err)
+	}
+}
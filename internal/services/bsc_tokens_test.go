@@ -0,0 +1,291 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"chain/internal/config"
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testTokenList = `{
+	"name": "Test List",
+	"timestamp": "2024-01-01T00:00:00Z",
+	"version": {"major": 1, "minor": 0, "patch": 0},
+	"tokens": [
+		{"chainId": 56, "address": "0x0000000000000000000000000000000000000aa1", "name": "Safe Dog", "symbol": "SDOG", "decimals": 9, "logoURI": "ipfs://QmDog"},
+		{"chainId": 56, "address": "0x0000000000000000000000000000000000000aa2", "name": "Dogecoin", "symbol": "DOGE", "decimals": 8, "logoURI": "https://example.com/doge.png"},
+		{"chainId": 56, "address": "0x0000000000000000000000000000000000000aa3", "name": "Hot Dog Token", "symbol": "HDT", "decimals": 18},
+		{"chainId": 56, "address": "0x0000000000000000000000000000000000000AA2", "name": "Duplicate", "symbol": "DUP", "decimals": 18},
+		{"chainId": 1, "address": "0x0000000000000000000000000000000000000aa4", "name": "Ethereum Dog", "symbol": "EDOG", "decimals": 18},
+		{"chainId": 56, "address": "not-an-address", "name": "Invalid", "symbol": "BAD", "decimals": 18}
+	]
+}`
+
+func searchSymbols(tokens []*models.Token) []string {
+	var symbols []string
+	for _, token := range tokens {
+		symbols = append(symbols, token.Symbol)
+	}
+	return symbols
+}
+
+func TestImportTokenListFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	require.NoError(t, os.WriteFile(path, []byte(testTokenList), 0o644))
+
+	service := newTestPairService(newFakeChain())
+	result, err := service.importTokenList(context.Background(), path, http.DefaultClient)
+	require.NoError(t, err)
+	assert.Equal(t, "Test List", result.Name)
+	assert.Equal(t, 3, result.Imported)
+	assert.Equal(t, 3, result.Skipped)
+
+	tokens, err := service.SearchTokens(TokenQuery{Text: "sdog", Prefix: true})
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, "Safe Dog", tokens[0].Name)
+	assert.Equal(t, uint8(9), tokens[0].Decimals)
+	assert.Equal(t, "https://ipfs.io/ipfs/QmDog", tokens[0].LogoURI)
+	assert.True(t, tokens[0].Verified)
+	assert.Equal(t, "Test List", tokens[0].Source)
+
+	// 其他链的代币不会导入
+	tokens, err = service.SearchTokens(TokenQuery{Text: "EDOG", Prefix: true})
+	require.NoError(t, err)
+	assert.Empty(t, tokens)
+}
+
+func TestImportTokenListURL(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/tokens.json" {
+			http.NotFound(w, r)
+			return
+		}
+		w.Write([]byte(testTokenList))
+	}))
+	defer server.Close()
+
+	// 测试服务器在回环地址上，放开域名和公网地址限制
+	service := newTestPairService(newFakeChain())
+	service.tokenListHosts = []string{"127.0.0.1"}
+	service.tokenListClient = http.DefaultClient
+	result, err := service.ImportTokenList(context.Background(), server.URL+"/tokens.json")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Imported)
+
+	_, err = service.ImportTokenList(context.Background(), server.URL+"/missing.json")
+	assert.ErrorContains(t, err, "status 404")
+}
+
+func TestImportTokenListRejectsRedirectToOtherHost(t *testing.T) {
+	var requests int
+	target := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(testTokenList))
+	}))
+	defer target.Close()
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Redirect(w, r, target.URL+r.URL.Path, http.StatusFound)
+	}))
+	defer server.Close()
+
+	// 通过localhost访问允许的域名，重定向到127.0.0.1时被拒绝；测试服务器在回环地址上，不检查公网地址
+	service := newTestPairService(newFakeChain())
+	service.tokenListHosts = []string{"localhost"}
+	service.tokenListClient = &http.Client{CheckRedirect: redirectPolicy(service.tokenListHosts)}
+	source := strings.Replace(server.URL, "127.0.0.1", "localhost", 1) + "/tokens.json"
+	_, err := service.ImportTokenList(context.Background(), source)
+	assert.ErrorIs(t, err, ErrTokenListNotAllowed)
+	assert.Zero(t, requests)
+
+	// 重定向到允许的域名时正常导入
+	service.tokenListHosts = []string{"localhost", "127.0.0.1"}
+	service.tokenListClient = &http.Client{CheckRedirect: redirectPolicy(service.tokenListHosts)}
+	result, err := service.ImportTokenList(context.Background(), source)
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Imported)
+	assert.Equal(t, 1, requests)
+}
+
+func TestImportTokenListRestrictions(t *testing.T) {
+	var requests int
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		requests++
+		w.Write([]byte(testTokenList))
+	}))
+	defer server.Close()
+
+	service := newTestPairService(newFakeChain())
+	service.tokenListHosts = []string{"tokens.pancakeswap.finance"}
+
+	for _, source := range []string{
+		server.URL + "/tokens.json",
+		"file:///etc/passwd",
+		"/etc/passwd",
+		"https://evil.example.com/tokens.json",
+		"https://tokens.pancakeswap.finance.evil.com/tokens.json",
+	} {
+		_, err := service.ImportTokenList(context.Background(), source)
+		assert.ErrorIs(t, err, ErrTokenListNotAllowed, source)
+	}
+	assert.Zero(t, requests)
+
+	// 允许的域名解析到内网地址时在连接阶段被拒绝
+	service.tokenListHosts = []string{"127.0.0.1"}
+	_, err := service.ImportTokenList(context.Background(), server.URL+"/tokens.json")
+	assert.ErrorIs(t, err, ErrNonPublicAddress)
+	assert.Zero(t, requests)
+
+	// 配置的代币列表不受限制
+	service.tokenLists = []string{server.URL + "/tokens.json"}
+	service.tokenListHosts = nil
+	result, err := service.ImportTokenList(context.Background(), server.URL+"/tokens.json")
+	require.NoError(t, err)
+	assert.Equal(t, 3, result.Imported)
+}
+
+func TestAuthorizeTokenListImport(t *testing.T) {
+	chain, _ := newRouteTestChain()
+
+	// 未配置令牌时拒绝所有接口导入
+	assert.ErrorIs(t, newTestBSCService(chain).AuthorizeTokenListImport(""), ErrTokenListDisabled)
+
+	service := newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{ChainID: 56, GasLimit: 21000},
+		BSC:   config.BSCConfig{TokenListToken: "secret"},
+	})
+	assert.ErrorIs(t, service.AuthorizeTokenListImport(""), ErrTokenListUnauthorized)
+	assert.ErrorIs(t, service.AuthorizeTokenListImport("wrong"), ErrTokenListUnauthorized)
+	assert.NoError(t, service.AuthorizeTokenListImport("secret"))
+}
+
+func TestHostAllowed(t *testing.T) {
+	allowed := []string{"tokens.pancakeswap.finance", " CoinGecko.com "}
+	assert.True(t, hostAllowed("tokens.pancakeswap.finance", allowed))
+	assert.True(t, hostAllowed("TOKENS.pancakeswap.finance.", allowed))
+	assert.True(t, hostAllowed("tokens.coingecko.com", allowed))
+	assert.False(t, hostAllowed("pancakeswap.finance", allowed))
+	assert.False(t, hostAllowed("evilcoingecko.com", allowed))
+	assert.False(t, hostAllowed("coingecko.com.evil.com", allowed))
+}
+
+func TestIsPublicIP(t *testing.T) {
+	tests := map[string]bool{
+		"1.1.1.1":          true,
+		"2606:4700::1111":  true,
+		"127.0.0.1":        false,
+		"10.0.0.1":         false,
+		"172.16.5.4":       false,
+		"192.168.1.1":      false,
+		"169.254.169.254":  false,
+		"100.64.0.1":       false,
+		"0.0.0.0":          false,
+		"::1":              false,
+		"fe80::1":          false,
+		"fc00::1":          false,
+		"::ffff:127.0.0.1": false,
+		"224.0.0.1":        false,
+	}
+	for ip, public := range tests {
+		assert.Equal(t, public, isPublicIP(netip.MustParseAddr(ip)), ip)
+	}
+}
+
+func TestSearchTokens(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "tokens.json")
+	require.NoError(t, os.WriteFile(path, []byte(testTokenList), 0o644))
+	service := newTestPairService(newFakeChain())
+	_, err := service.importTokenList(context.Background(), path, http.DefaultClient)
+	require.NoError(t, err)
+
+	// 未验证的同名代币排在已验证代币之后
+	require.NoError(t, service.tokens.AddTokens([]*models.Token{{
+		Address: "0x0000000000000000000000000000000000000bb1",
+		Name:    "Doge Killer",
+		Symbol:  "DOGE",
+		ChainID: 56,
+	}}))
+
+	// 符号完全匹配 > 符号前缀 > 名称前缀 > 符号包含 > 名称包含
+	tokens, err := service.SearchTokens(TokenQuery{Text: "dog"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DOGE", "DOGE", "SDOG", "HDT"}, searchSymbols(tokens))
+	assert.True(t, tokens[0].Verified)
+	assert.False(t, tokens[1].Verified)
+
+	tokens, err = service.SearchTokens(TokenQuery{Text: "DOG", Prefix: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DOGE", "DOGE"}, searchSymbols(tokens))
+
+	tokens, err = service.SearchTokens(TokenQuery{Text: "dog", VerifiedOnly: true, Limit: 2})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DOGE", "SDOG"}, searchSymbols(tokens))
+
+	// 模糊匹配排在完全匹配、前缀和包含匹配之后，名称中的单词也参与匹配
+	tokens, err = service.SearchTokens(TokenQuery{Text: "doge"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DOGE", "DOGE", "HDT", "SDOG"}, searchSymbols(tokens))
+
+	tokens, err = service.SearchTokens(TokenQuery{Text: "dogecion"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DOGE"}, searchSymbols(tokens))
+	assert.True(t, tokens[0].Verified)
+
+	tokens, err = service.SearchTokens(TokenQuery{Text: "cakr"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"CAKE"}, searchSymbols(tokens))
+
+	tokens, err = service.SearchTokens(TokenQuery{Text: "doge", Prefix: true})
+	require.NoError(t, err)
+	assert.Equal(t, []string{"DOGE", "DOGE"}, searchSymbols(tokens))
+
+	// 内置代币无需导入即可搜索
+	tokens, err = service.FindTokenByName("wrapped")
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, common.HexToAddress(WBNBAddress).Hex(), tokens[0].Address)
+
+	_, err = service.SearchTokens(TokenQuery{Text: " "})
+	assert.Error(t, err)
+}
+
+func TestEditDistance(t *testing.T) {
+	assert.Equal(t, 0, editDistance("doge", "doge", 2))
+	assert.Equal(t, 1, editDistance("doge", "dgoe", 2))
+	assert.Equal(t, 1, editDistance("cake", "cakr", 2))
+	assert.Equal(t, 3, editDistance("kitten", "sitting", 3))
+	assert.Equal(t, 3, editDistance("bnb", "pancakeswap", 2))
+}
+
+func TestSyncPairsDiscoversTokens(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "New Token", "NEW", 18)
+	unknown := common.HexToAddress("0x00000000000000000000000000000000000000ab")
+	chain.addPairCreatedLog(common.HexToAddress(PancakeSwapV2Factory), token, tokens["WBNB"], 0)
+	chain.addPairCreatedLog(common.HexToAddress(PancakeSwapV2Factory), unknown, tokens["WBNB"], 0)
+
+	service := newTestPairService(chain)
+	_, err := service.SyncPairs(context.Background())
+	require.NoError(t, err)
+
+	found, err := service.SearchTokens(TokenQuery{Text: "NEW"})
+	require.NoError(t, err)
+	require.Len(t, found, 1)
+	assert.Equal(t, token.Hex(), found[0].Address)
+	assert.False(t, found[0].Verified)
+	assert.Equal(t, "pancakeswap-v2", found[0].Source)
+
+	// 已保存的代币不会重新读取
+	assert.Zero(t, chain.callCount(tokens["WBNB"], "name"))
+}
@@ -0,0 +1,415 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"chain/internal/models"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// defaultTWAPRetention 累计价格观测的默认保留时间
+const defaultTWAPRetention = 7 * 24 * time.Hour
+
+// defaultTWAPSampleInterval 未启动采样器时计算观测容差使用的采样间隔
+const defaultTWAPSampleInterval = time.Minute
+
+// TWAP采样和池子缓存的上限
+const (
+	maxIndexedTWAPPools = 500   // 每次采样的已索引交易对数量，取最近创建的交易对
+	maxTWAPPoolCache    = 10000 // 缓存的池子信息数量
+)
+
+var (
+	// ErrInvalidTWAPQuery TWAP查询参数无效，或地址不是V2交易对/V3池
+	ErrInvalidTWAPQuery = errors.New("invalid TWAP query")
+	// ErrInsufficientObservations 池子没有足够早的观测覆盖请求的窗口
+	ErrInsufficientObservations = errors.New("not enough observations")
+)
+
+// TWAP价格来源
+const (
+	TWAPSourceV2Cumulative = "v2-cumulative" // V2交易对的累计价格
+	TWAPSourceV3Observe    = "v3-observe"    // V3池自带的价格预言机
+	TWAPSourceV3Samples    = "v3-samples"    // V3池预言机历史不足时使用采样保存的累计tick
+)
+
+var (
+	// q112 UQ112x112定点数的缩放因子
+	q112 = new(big.Int).Lsh(big.NewInt(1), 112)
+	// uint256Modulus 累计价格按uint256溢出回绕
+	uint256Modulus = new(big.Int).Lsh(big.NewInt(1), 256)
+)
+
+// TWAPPrice 池子在时间窗口内的时间加权平均价格，价格已按代币精度换算
+type TWAPPrice struct {
+	Pool        string `json:"pool"`
+	Token0      string `json:"token0"`
+	Token1      string `json:"token1"`
+	Price0      string `json:"price0"`       // 以token1计价的token0价格
+	Price1      string `json:"price1"`       // 以token0计价的token1价格
+	WindowStart int64  `json:"window_start"` // 实际使用的窗口起点（Unix秒），使用采样观测时为不晚于请求起点的最近观测时间，最多早两个采样间隔
+	WindowEnd   int64  `json:"window_end"`   // 窗口终点，即最新区块时间
+	Source      string `json:"source"`
+}
+
+// twapPool 计算TWAP的池子
+type twapPool struct {
+	address common.Address
+	v3      bool
+	token0  common.Address
+	token1  common.Address
+}
+
+// SetObservationStore 设置累计价格观测存储，默认使用内存存储
+func (s *BSCService) SetObservationStore(store ObservationStore) {
+	s.observations = store
+}
+
+// GetTWAP 计算池子截至最新区块window时间内的时间加权平均价格
+// V3池优先使用池子自带的预言机；V2交易对和预言机历史不足的V3池使用采样器保存的观测，
+// 只有配置的池子和已索引的交易对会被采样，没有足够早的观测，或窗口起点前最近的观测
+// 早于起点超过两个采样间隔时返回 ErrInsufficientObservations
+func (s *BSCService) GetTWAP(poolAddress string, window time.Duration) (*TWAPPrice, error) {
+	if !common.IsHexAddress(poolAddress) {
+		return nil, fmt.Errorf("%w: invalid pool address: %s", ErrInvalidTWAPQuery, poolAddress)
+	}
+	if window < time.Second || window > s.twapRetention {
+		return nil, fmt.Errorf("%w: window must be between 1s and %s", ErrInvalidTWAPQuery, s.twapRetention)
+	}
+
+	pool, err := s.getTWAPPool(common.HexToAddress(poolAddress))
+	if err != nil {
+		return nil, err
+	}
+	current, err := s.observePool(context.Background(), pool)
+	if err != nil {
+		return nil, fmt.Errorf("failed to observe pool: %w", err)
+	}
+	start := current.Timestamp.Add(-window)
+
+	if pool.v3 {
+		ticks, err := s.observeTicks(pool.address, []uint32{uint32(window / time.Second), 0})
+		if err == nil {
+			return s.v3TWAP(pool, ticks[0], ticks[1], start, current.Timestamp, TWAPSourceV3Observe)
+		}
+		logger.Debugf("Oracle of pool %s does not cover %s, using sampled observations: %v", pool.address.Hex(), window, err)
+	}
+
+	past, err := s.observations.GetObservationBefore(pool.address.Hex(), start)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get observation: %w", err)
+	}
+	if past == nil {
+		return nil, fmt.Errorf("%w: pool %s has no sampled observation covering %s", ErrInsufficientObservations, pool.address.Hex(), window)
+	}
+	// 采样中断后最近的观测可能远早于窗口起点，此时的平均价格不能代表请求的窗口
+	if gap := start.Sub(past.Timestamp); gap > s.twapObservationTolerance() {
+		return nil, fmt.Errorf("%w: latest observation of pool %s before the window is %s older than its start",
+			ErrInsufficientObservations, pool.address.Hex(), gap.Truncate(time.Second))
+	}
+
+	if pool.v3 {
+		startTick, ok := new(big.Int).SetString(past.TickCumulative, 10)
+		endTick, ok2 := new(big.Int).SetString(current.TickCumulative, 10)
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("invalid tick cumulative in observation of pool %s", pool.address.Hex())
+		}
+		return s.v3TWAP(pool, startTick, endTick, past.Timestamp, current.Timestamp, TWAPSourceV3Samples)
+	}
+	return s.v2TWAP(pool, past, current)
+}
+
+// twapObservationTolerance 窗口起点与其前最近观测之间允许的最大间隔，为两个采样间隔
+func (s *BSCService) twapObservationTolerance() time.Duration {
+	interval := s.twapInterval
+	if interval <= 0 {
+		interval = defaultTWAPSampleInterval
+	}
+	return 2 * interval
+}
+
+// RunTWAPSampler 按采样间隔持续记录池子的累计价格并清理过期观测，直到ctx取消；采样间隔为0时不启动
+func (s *BSCService) RunTWAPSampler(ctx context.Context) {
+	if s.twapInterval <= 0 {
+		logger.Info("TWAP sampler disabled")
+		return
+	}
+
+	ticker := time.NewTicker(s.twapInterval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := s.SampleTWAPPools(ctx); err != nil {
+			logger.Warnf("Failed to sample TWAP pools: %v", err)
+		} else if count > 0 {
+			logger.Debugf("Sampled %d TWAP pools", count)
+		}
+		if err := s.observations.DeleteObservationsBefore(time.Now().Add(-s.twapRetention)); err != nil {
+			logger.Warnf("Failed to delete expired observations: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// SampleTWAPPools 并发采样配置的池子和保留期内创建的已索引交易对（最多 maxIndexedTWAPPools 个），
+// 返回成功采样的池子数量；单个池子采样失败不影响其他池子
+func (s *BSCService) SampleTWAPPools(ctx context.Context) (int, error) {
+	indexed, err := s.pairs.GetPairsSince(time.Now().Add(-s.twapRetention))
+	if err != nil {
+		return 0, fmt.Errorf("failed to get indexed pairs: %w", err)
+	}
+	if len(indexed) > maxIndexedTWAPPools {
+		indexed = indexed[len(indexed)-maxIndexedTWAPPools:]
+	}
+
+	seen := make(map[common.Address]bool)
+	var pools []common.Address
+	for _, pool := range s.twapPools {
+		if !seen[pool] {
+			seen[pool] = true
+			pools = append(pools, pool)
+		}
+	}
+	for _, pair := range indexed {
+		if pool := common.HexToAddress(pair.PairAddress); !seen[pool] {
+			seen[pool] = true
+			pools = append(pools, pool)
+		}
+	}
+
+	var (
+		wg    sync.WaitGroup
+		mu    sync.Mutex
+		count int
+		errs  []error
+	)
+	for _, address := range pools {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			err := s.sampleTWAPPool(ctx, address)
+
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				errs = append(errs, fmt.Errorf("failed to sample pool %s: %w", address.Hex(), err))
+				return
+			}
+			count++
+		}()
+	}
+	wg.Wait()
+	return count, errors.Join(errs...)
+}
+
+// sampleTWAPPool 读取池子最新的累计价格并保存
+func (s *BSCService) sampleTWAPPool(ctx context.Context, address common.Address) error {
+	pool, err := s.getTWAPPool(address)
+	if err != nil {
+		return err
+	}
+	observation, err := s.observePool(ctx, pool)
+	if err != nil {
+		return err
+	}
+	return s.observations.SaveObservation(observation)
+}
+
+// getTWAPPool 识别V2交易对或V3池及其代币，结果会被缓存，缓存满时随机淘汰一个池子
+func (s *BSCService) getTWAPPool(address common.Address) (*twapPool, error) {
+	s.twapPoolsMu.Lock()
+	cached, ok := s.twapPoolCache[address]
+	s.twapPoolsMu.Unlock()
+	if ok {
+		return cached, nil
+	}
+
+	pool := &twapPool{address: address}
+	token0, err := s.callContract(pairABI, address, "token0")
+	if err != nil {
+		return nil, notTWAPPoolError(address, err)
+	}
+	token1, err := s.callContract(pairABI, address, "token1")
+	if err != nil {
+		return nil, notTWAPPoolError(address, err)
+	}
+	pool.token0 = token0[0].(common.Address)
+	pool.token1 = token1[0].(common.Address)
+
+	// V3池没有getReserves
+	if _, err := s.callContract(pairABI, address, "getReserves"); err != nil {
+		if _, err := s.callContract(v3PoolABI, address, "slot0"); err != nil {
+			return nil, notTWAPPoolError(address, err)
+		}
+		pool.v3 = true
+	}
+
+	s.twapPoolsMu.Lock()
+	if len(s.twapPoolCache) >= maxTWAPPoolCache {
+		for cachedAddress := range s.twapPoolCache {
+			delete(s.twapPoolCache, cachedAddress)
+			break
+		}
+	}
+	s.twapPoolCache[address] = pool
+	s.twapPoolsMu.Unlock()
+	return pool, nil
+}
+
+// notTWAPPoolError 识别池子的调用回滚或返回数据无法解析时，地址不是V2交易对或V3池；节点错误原样返回
+func notTWAPPoolError(address common.Address, err error) error {
+	if isRevertError(err) || strings.Contains(err.Error(), "failed to unpack") {
+		return fmt.Errorf("%w: address %s is not a V2 pair or V3 pool: %v", ErrInvalidTWAPQuery, address.Hex(), err)
+	}
+	return fmt.Errorf("failed to identify pool %s: %w", address.Hex(), err)
+}
+
+// observePool 读取池子在最新区块时间的累计价格
+func (s *BSCService) observePool(ctx context.Context, pool *twapPool) (*models.PoolObservation, error) {
+	header, err := s.client.HeaderByNumber(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get latest block: %w", err)
+	}
+
+	observation := &models.PoolObservation{
+		Pool:      pool.address.Hex(),
+		ChainID:   s.chainID.Uint64(),
+		Timestamp: time.Unix(int64(header.Time), 0),
+	}
+	if pool.v3 {
+		ticks, err := s.observeTicks(pool.address, []uint32{0})
+		if err != nil {
+			return nil, err
+		}
+		observation.TickCumulative = ticks[0].String()
+		return observation, nil
+	}
+
+	price0, price1, err := s.currentCumulativePrices(pool.address, header.Time)
+	if err != nil {
+		return nil, err
+	}
+	observation.Price0Cumulative = price0.String()
+	observation.Price1Cumulative = price1.String()
+	return observation, nil
+}
+
+// currentCumulativePrices 计算V2交易对在timestamp时的累计价格
+// 与UniswapV2OracleLibrary相同，交易对上次更新后经过的时间按当前储备量累加
+func (s *BSCService) currentCumulativePrices(pair common.Address, timestamp uint64) (*big.Int, *big.Int, error) {
+	reserves, err := s.callContract(pairABI, pair, "getReserves")
+	if err != nil {
+		return nil, nil, err
+	}
+	price0, err := s.callContract(pairABI, pair, "price0CumulativeLast")
+	if err != nil {
+		return nil, nil, err
+	}
+	price1, err := s.callContract(pairABI, pair, "price1CumulativeLast")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	reserve0, reserve1 := reserves[0].(*big.Int), reserves[1].(*big.Int)
+	cumulative0 := new(big.Int).Set(price0[0].(*big.Int))
+	cumulative1 := new(big.Int).Set(price1[0].(*big.Int))
+
+	// 区块时间戳按uint32回绕
+	elapsed := uint32(timestamp) - reserves[2].(uint32)
+	if elapsed > 0 && reserve0.Sign() > 0 && reserve1.Sign() > 0 {
+		seconds := big.NewInt(int64(elapsed))
+		average0 := new(big.Int).Quo(new(big.Int).Lsh(reserve1, 112), reserve0)
+		average1 := new(big.Int).Quo(new(big.Int).Lsh(reserve0, 112), reserve1)
+		cumulative0.Add(cumulative0, average0.Mul(average0, seconds))
+		cumulative1.Add(cumulative1, average1.Mul(average1, seconds))
+	}
+	return cumulative0.Mod(cumulative0, uint256Modulus), cumulative1.Mod(cumulative1, uint256Modulus), nil
+}
+
+// observeTicks 读取V3池在secondsAgos秒之前的累计tick，超出池子预言机的历史时调用回滚
+func (s *BSCService) observeTicks(pool common.Address, secondsAgos []uint32) ([]*big.Int, error) {
+	output, err := s.callContract(v3PoolABI, pool, "observe", secondsAgos)
+	if err != nil {
+		return nil, err
+	}
+	return output[0].([]*big.Int), nil
+}
+
+// v2TWAP 根据两次观测的累计价格计算V2交易对的TWAP
+func (s *BSCService) v2TWAP(pool *twapPool, start, end *models.PoolObservation) (*TWAPPrice, error) {
+	elapsed := big.NewInt(end.Timestamp.Unix() - start.Timestamp.Unix())
+	if elapsed.Sign() <= 0 {
+		return nil, fmt.Errorf("no time elapsed between observations of pool %s", pool.address.Hex())
+	}
+
+	averages := make([]*big.Rat, 2)
+	for i, values := range [][2]string{
+		{start.Price0Cumulative, end.Price0Cumulative},
+		{start.Price1Cumulative, end.Price1Cumulative},
+	} {
+		from, ok := new(big.Int).SetString(values[0], 10)
+		to, ok2 := new(big.Int).SetString(values[1], 10)
+		if !ok || !ok2 {
+			return nil, fmt.Errorf("invalid cumulative price in observation of pool %s", pool.address.Hex())
+		}
+		// 累计价格溢出回绕时差值仍然正确
+		diff := new(big.Int).Sub(to, from)
+		diff.Mod(diff, uint256Modulus)
+		averages[i] = new(big.Rat).SetFrac(diff, new(big.Int).Mul(q112, elapsed))
+	}
+	return s.newTWAPPrice(pool, averages[0], averages[1], start.Timestamp, end.Timestamp, TWAPSourceV2Cumulative)
+}
+
+// v3TWAP 根据累计tick计算V3池的TWAP，平均tick向负无穷取整，价格为1.0001^tick
+func (s *BSCService) v3TWAP(pool *twapPool, startTick, endTick *big.Int, start, end time.Time, source string) (*TWAPPrice, error) {
+	elapsed := big.NewInt(end.Unix() - start.Unix())
+	if elapsed.Sign() <= 0 {
+		return nil, fmt.Errorf("no time elapsed between observations of pool %s", pool.address.Hex())
+	}
+
+	// 除数为正时欧几里得除法即向负无穷取整
+	tick := new(big.Int).Div(new(big.Int).Sub(endTick, startTick), elapsed)
+	price0 := new(big.Rat).SetFloat64(math.Pow(1.0001, float64(tick.Int64())))
+	if price0 == nil || price0.Sign() == 0 {
+		return nil, fmt.Errorf("invalid average tick %s of pool %s", tick, pool.address.Hex())
+	}
+	return s.newTWAPPrice(pool, price0, new(big.Rat).Inv(price0), start, end, source)
+}
+
+// newTWAPPrice 将以最小单位计的平均价格按代币精度换算
+func (s *BSCService) newTWAPPrice(pool *twapPool, price0, price1 *big.Rat, start, end time.Time, source string) (*TWAPPrice, error) {
+	decimals0, err := s.getTokenDecimals(pool.token0.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token0 decimals: %w", err)
+	}
+	decimals1, err := s.getTokenDecimals(pool.token1.Hex())
+	if err != nil {
+		return nil, fmt.Errorf("failed to get token1 decimals: %w", err)
+	}
+
+	scale := new(big.Rat).SetFrac(pow10(decimals0), pow10(decimals1))
+	return &TWAPPrice{
+		Pool:        pool.address.Hex(),
+		Token0:      pool.token0.Hex(),
+		Token1:      pool.token1.Hex(),
+		Price0:      formatRat(new(big.Rat).Mul(price0, scale), 18),
+		Price1:      formatRat(new(big.Rat).Quo(price1, scale), 18),
+		WindowStart: start.Unix(),
+		WindowEnd:   end.Unix(),
+		Source:      source,
+	}, nil
+}
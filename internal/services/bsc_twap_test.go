@@ -0,0 +1,192 @@
+package services
+
+import (
+	"context"
+	"math/big"
+	"testing"
+	"time"
+
+	"chain/internal/config"
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestGetTWAPV2(t *testing.T) {
+	chain := newFakeChain()
+	wbnb := chain.addToken(WBNBAddress, "Wrapped BNB", "WBNB", 18)
+	usdt := chain.addToken(USDTAddress, "Tether USD", "USDT", 6)
+	pair := chain.addPair(wbnb, usdt, 1_000, 300_000)
+	service := newTestBSCService(chain)
+	service.twapPools = []common.Address{pair}
+
+	// 查询不会记录观测，池子由采样器采样
+	_, err := service.GetTWAP(pair.Hex(), 30*time.Minute)
+	assert.ErrorIs(t, err, ErrInsufficientObservations)
+	pools, err := service.observations.GetObservedPools()
+	require.NoError(t, err)
+	assert.Empty(t, pools)
+
+	_, err = service.SampleTWAPPools(context.Background())
+	require.NoError(t, err)
+	start := chain.headTime
+
+	// 前15分钟价格为300，后15分钟为400
+	chain.advance(300)
+	reserveWBNB := new(big.Int).Mul(big.NewInt(1_000), pow10(18))
+	reserveUSDT := new(big.Int).Mul(big.NewInt(400_000), pow10(6))
+	if chain.pairs[pair].token0 == wbnb {
+		chain.syncPair(pair, reserveWBNB, reserveUSDT)
+	} else {
+		chain.syncPair(pair, reserveUSDT, reserveWBNB)
+	}
+	chain.advance(300)
+
+	twap, err := service.GetTWAP(pair.Hex(), 30*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, TWAPSourceV2Cumulative, twap.Source)
+	assert.Equal(t, int64(start), twap.WindowStart)
+	assert.Equal(t, int64(chain.headTime), twap.WindowEnd)
+
+	wbnbPrice, usdtPrice := twap.Price0, twap.Price1
+	if twap.Token0 != wbnb.Hex() {
+		wbnbPrice, usdtPrice = usdtPrice, wbnbPrice
+	}
+	assertDecimalBetween(t, wbnbPrice, "349.99", "350.01")
+	// 反向价格单独累计，平均值不是平均价格的倒数
+	assertDecimalBetween(t, usdtPrice, "0.002916", "0.002917")
+}
+
+func TestGetTWAPRejectsStaleObservation(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	pair, _ := chain.findPair(tokens["WBNB"], tokens["USDT"])
+	service := newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{ChainID: 56, GasLimit: 21000, MulticallWait: 1},
+		BSC:   config.BSCConfig{TWAPSampleInterval: 60},
+	})
+	service.twapPools = []common.Address{pair}
+
+	_, err := service.SampleTWAPPools(context.Background())
+	require.NoError(t, err)
+	sampledAt := chain.headTime
+
+	// 采样中断约100分钟后，10分钟窗口起点前最近的观测早于起点约90分钟
+	chain.advance(2000)
+	_, err = service.GetTWAP(pair.Hex(), 10*time.Minute)
+	assert.ErrorIs(t, err, ErrInsufficientObservations)
+	assert.ErrorContains(t, err, "older than its start")
+
+	// 观测在容差（两个采样间隔）以内时正常计算
+	twap, err := service.GetTWAP(pair.Hex(), time.Duration(chain.headTime-sampledAt-60)*time.Second)
+	require.NoError(t, err)
+	assert.Equal(t, int64(sampledAt), twap.WindowStart)
+}
+
+func TestGetTWAPV3(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	poolAddress := chain.addV3Pool(PancakeSwapV3Factory, tokens["WBNB"], tokens["USDT"], 500, 1_000, 300_000)
+	pool := chain.v3Pools[poolAddress]
+	service := newTestBSCService(chain)
+	service.twapPools = []common.Address{poolAddress}
+
+	price := func(twap *TWAPPrice) string {
+		if twap.Token0 == tokens["WBNB"].Hex() {
+			return twap.Price0
+		}
+		return twap.Price1
+	}
+
+	twap, err := service.GetTWAP(poolAddress.Hex(), 30*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, TWAPSourceV3Observe, twap.Source)
+	assertDecimalBetween(t, price(twap), "299.9", "300.1")
+
+	// 预言机历史不足时使用采样的观测
+	pool.oracleHistory = 60
+	_, err = service.GetTWAP(poolAddress.Hex(), 30*time.Minute)
+	assert.ErrorIs(t, err, ErrInsufficientObservations)
+
+	_, err = service.SampleTWAPPools(context.Background())
+	require.NoError(t, err)
+	chain.advance(600)
+	twap, err = service.GetTWAP(poolAddress.Hex(), 30*time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, TWAPSourceV3Samples, twap.Source)
+	assertDecimalBetween(t, price(twap), "299.9", "300.1")
+}
+
+func TestGetTWAPValidation(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	pair, _ := chain.findPair(tokens["WBNB"], tokens["USDT"])
+	service := newTestBSCService(chain)
+
+	_, err := service.GetTWAP("0x123", time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidTWAPQuery)
+	assert.ErrorContains(t, err, "invalid pool address")
+	_, err = service.GetTWAP(pair.Hex(), 0)
+	assert.ErrorIs(t, err, ErrInvalidTWAPQuery)
+	assert.ErrorContains(t, err, "window must be between")
+	_, err = service.GetTWAP(pair.Hex(), 8*24*time.Hour)
+	assert.ErrorIs(t, err, ErrInvalidTWAPQuery)
+	_, err = service.GetTWAP(tokens["CAKE"].Hex(), time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidTWAPQuery)
+	assert.ErrorContains(t, err, "not a V2 pair or V3 pool")
+	_, err = service.GetTWAP("0x00000000000000000000000000000000000000ac", time.Minute)
+	assert.ErrorIs(t, err, ErrInvalidTWAPQuery)
+}
+
+func TestSampleTWAPPools(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	pair, _ := chain.findPair(tokens["WBNB"], tokens["USDT"])
+	indexed, _ := chain.findPair(tokens["CAKE"], tokens["WBNB"])
+	queried, _ := chain.findPair(tokens["BUSD"], tokens["WBNB"])
+	service := newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{ChainID: 56, GasLimit: 21000, MulticallWait: 1},
+		BSC:   config.BSCConfig{TWAPPools: []string{pair.Hex(), tokens["CAKE"].Hex()}},
+	})
+	require.NoError(t, service.pairs.SavePairs([]*models.DexPair{{
+		PairAddress: indexed.Hex(),
+		Token0:      tokens["CAKE"].Hex(),
+		Token1:      tokens["WBNB"].Hex(),
+		BlockTime:   time.Now(),
+	}}))
+
+	// 只采样配置的池子和已索引的交易对，查询过的池子不会被采样
+	_, err := service.GetTWAP(queried.Hex(), time.Minute)
+	assert.ErrorIs(t, err, ErrInsufficientObservations)
+
+	count, err := service.SampleTWAPPools(context.Background())
+	assert.Equal(t, 2, count)
+	assert.ErrorContains(t, err, tokens["CAKE"].Hex())
+
+	pools, err := service.observations.GetObservedPools()
+	require.NoError(t, err)
+	assert.ElementsMatch(t, []string{pair.Hex(), indexed.Hex()}, pools)
+
+	chain.advance(20)
+	twap, err := service.GetTWAP(indexed.Hex(), time.Minute)
+	require.NoError(t, err)
+	assert.Equal(t, int64(chain.headTime-60), twap.WindowStart)
+
+	// 过期观测被清理
+	require.NoError(t, service.observations.DeleteObservationsBefore(time.Unix(int64(chain.headTime), 0)))
+	_, err = service.GetTWAP(indexed.Hex(), time.Minute)
+	assert.ErrorIs(t, err, ErrInsufficientObservations)
+}
+
+func TestTWAPPoolCacheLimit(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	pair, _ := chain.findPair(tokens["WBNB"], tokens["USDT"])
+	service := newTestBSCService(chain)
+	for i := 0; i < maxTWAPPoolCache; i++ {
+		address := common.BigToAddress(big.NewInt(int64(0x10000 + i)))
+		service.twapPoolCache[address] = &twapPool{address: address}
+	}
+
+	_, err := service.getTWAPPool(pair)
+	require.NoError(t, err)
+	assert.Len(t, service.twapPoolCache, maxTWAPPoolCache)
+	assert.Contains(t, service.twapPoolCache, pair)
+}
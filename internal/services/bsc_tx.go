@@ -0,0 +1,131 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"time"
+
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// 交易确认等待参数
+var (
+	receiptPollInterval = 2 * time.Second
+	receiptTimeout      = 2 * time.Minute // 调用方未设置截止时间时的最长等待时间
+)
+
+// ErrNoSigner 未配置签名私钥
+var ErrNoSigner = errors.New("no private key configured for BSC transactions")
+
+// SignerAddress 返回交易签名账户地址，未配置私钥时返回空字符串
+func (s *BSCService) SignerAddress() string {
+	if s.privateKey == nil {
+		return ""
+	}
+	return s.address.Hex()
+}
+
+// sendTransaction 使用服务签名账户构造、签名并发送交易
+func (s *BSCService) sendTransaction(ctx context.Context, to common.Address, value *big.Int, data []byte) (*types.Transaction, error) {
+	if s.privateKey == nil {
+		return nil, ErrNoSigner
+	}
+	if value == nil {
+		value = big.NewInt(0)
+	}
+
+	s.txMu.Lock()
+	defer s.txMu.Unlock()
+
+	// 获取nonce
+	nonce, err := s.client.PendingNonceAt(ctx, s.address)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get nonce: %w", err)
+	}
+
+	// 获取gas价格
+	gasPrice, err := s.client.SuggestGasPrice(ctx)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get gas price: %w", err)
+	}
+
+	// 估算gas并预留20%余量，估算失败通常意味着交易会回滚
+	gasLimit, err := s.client.EstimateGas(ctx, ethereum.CallMsg{
+		From:     s.address,
+		To:       &to,
+		GasPrice: gasPrice,
+		Value:    value,
+		Data:     data,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to estimate gas: %w", err)
+	}
+	gasLimit = gasLimit * 12 / 10
+
+	// 创建并签名交易
+	tx := types.NewTransaction(nonce, to, value, gasLimit, gasPrice, data)
+	signedTx, err := types.SignTx(tx, types.NewEIP155Signer(s.chainID), s.privateKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign transaction: %w", err)
+	}
+
+	// 发送交易
+	if err := s.client.SendTransaction(ctx, signedTx); err != nil {
+		return nil, fmt.Errorf("failed to send transaction: %w", err)
+	}
+
+	logger.Infof("BSC transaction sent: %s", signedTx.Hash().Hex())
+	return signedTx, nil
+}
+
+// waitForReceipt 轮询等待交易上链并返回收据，ctx没有截止时间时最多等待receiptTimeout
+func (s *BSCService) waitForReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	if _, ok := ctx.Deadline(); !ok {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, receiptTimeout)
+		defer cancel()
+	}
+
+	ticker := time.NewTicker(receiptPollInterval)
+	defer ticker.Stop()
+
+	for {
+		receipt, err := s.client.TransactionReceipt(ctx, txHash)
+		if err == nil {
+			return receipt, nil
+		}
+		if !errors.Is(err, ethereum.NotFound) {
+			return nil, fmt.Errorf("failed to get receipt of %s: %w", txHash.Hex(), err)
+		}
+
+		select {
+		case <-ctx.Done():
+			// 交易已发送，停止等待不影响交易上链
+			return nil, fmt.Errorf("stopped waiting for transaction %s: %w", txHash.Hex(), ctx.Err())
+		case <-ticker.C:
+		}
+	}
+}
+
+// sendAndWait 发送交易并等待执行成功
+func (s *BSCService) sendAndWait(ctx context.Context, to common.Address, value *big.Int, data []byte) (*types.Transaction, *types.Receipt, error) {
+	tx, err := s.sendTransaction(ctx, to, value, data)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	receipt, err := s.waitForReceipt(ctx, tx.Hash())
+	if err != nil {
+		return tx, nil, err
+	}
+	if receipt.Status != types.ReceiptStatusSuccessful {
+		return tx, receipt, fmt.Errorf("transaction %s reverted", tx.Hash().Hex())
+	}
+	return tx, receipt, nil
+}
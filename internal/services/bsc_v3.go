@@ -0,0 +1,505 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"math/big"
+	"sync"
+
+	"chain/internal/config"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// BSC上的V3集中流动性DEX合约地址
+const (
+	PancakeSwapV3Factory  = "0x0BFbCF9fa4f9C56B0F40a671Ad40E0805A091865"
+	PancakeSwapV3QuoterV2 = "0xB048Bbc1Ee6b733FFfCFb9e9CeF7375518e25997"
+	UniswapV3Factory      = "0xdB1d10011AD0Ff90774D0C6Bb92e5C5c8b4461F7"
+	UniswapV3QuoterV2     = "0x78D78E420Da98ad378D7799bE8f4AF69033EB077"
+)
+
+// 默认的V3 DEX配置
+var defaultV3Dexes = []config.V3DexConfig{
+	{
+		Name:     "pancakeswap-v3",
+		Factory:  PancakeSwapV3Factory,
+		Quoter:   PancakeSwapV3QuoterV2,
+		FeeTiers: []uint32{100, 500, 2500, 10000},
+	},
+	{
+		Name:     "uniswap-v3",
+		Factory:  UniswapV3Factory,
+		Quoter:   UniswapV3QuoterV2,
+		FeeTiers: []uint32{100, 500, 3000, 10000},
+	},
+}
+
+// V3 Factory ABI (简化版)
+const v3FactoryABI = `[
+	{
+		"inputs": [
+			{"name": "tokenA", "type": "address"},
+			{"name": "tokenB", "type": "address"},
+			{"name": "fee", "type": "uint24"}
+		],
+		"name": "getPool",
+		"outputs": [{"name": "pool", "type": "address"}],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// V3 Pool ABI (简化版)
+// PancakeSwap V3的feeProtocol为uint32，Uniswap V3为uint8，按uint32解码可同时兼容两者
+const v3PoolABI = `[
+	{
+		"inputs": [],
+		"name": "slot0",
+		"outputs": [
+			{"name": "sqrtPriceX96", "type": "uint160"},
+			{"name": "tick", "type": "int24"},
+			{"name": "observationIndex", "type": "uint16"},
+			{"name": "observationCardinality", "type": "uint16"},
+			{"name": "observationCardinalityNext", "type": "uint16"},
+			{"name": "feeProtocol", "type": "uint32"},
+			{"name": "unlocked", "type": "bool"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "liquidity",
+		"outputs": [{"name": "", "type": "uint128"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "secondsAgos", "type": "uint32[]"}],
+		"name": "observe",
+		"outputs": [
+			{"name": "tickCumulatives", "type": "int56[]"},
+			{"name": "secondsPerLiquidityCumulativeX128s", "type": "uint160[]"}
+		],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "token0",
+		"outputs": [{"name": "", "type": "address"}],
+		"stateMutability": "view",
+		"type": "function"
+	},
+	{
+		"inputs": [],
+		"name": "token1",
+		"outputs": [{"name": "", "type": "address"}],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// V3 QuoterV2 ABI (简化版)
+const v3QuoterV2ABI = `[
+	{
+		"inputs": [
+			{
+				"components": [
+					{"name": "tokenIn", "type": "address"},
+					{"name": "tokenOut", "type": "address"},
+					{"name": "amountIn", "type": "uint256"},
+					{"name": "fee", "type": "uint24"},
+					{"name": "sqrtPriceLimitX96", "type": "uint160"}
+				],
+				"name": "params",
+				"type": "tuple"
+			}
+		],
+		"name": "quoteExactInputSingle",
+		"outputs": [
+			{"name": "amountOut", "type": "uint256"},
+			{"name": "sqrtPriceX96After", "type": "uint160"},
+			{"name": "initializedTicksCrossed", "type": "uint32"},
+			{"name": "gasEstimate", "type": "uint256"}
+		],
+		"stateMutability": "nonpayable",
+		"type": "function"
+	}
+]`
+
+// V3PoolInfo V3集中流动性池信息
+type V3PoolInfo struct {
+	Dex          string `json:"dex"`
+	PoolAddress  string `json:"pool_address"`
+	Token0       string `json:"token0"`
+	Token1       string `json:"token1"`
+	Fee          uint32 `json:"fee"` // 手续费等级（百万分比）
+	SqrtPriceX96 string `json:"sqrt_price_x96"`
+	Tick         int64  `json:"tick"`
+	Liquidity    string `json:"liquidity"` // 当前价格区间内的流动性L
+	Price        string `json:"price"`     // 1个token0可兑换的token1数量（已按精度调整）
+	Reserve0     string `json:"reserve0"`  // 池中持有的token0数量（最小单位）
+	Reserve1     string `json:"reserve1"`  // 池中持有的token1数量（最小单位）
+	TVLUSD       string `json:"tvl_usd"`   // 池中代币余额的USD价值，无法定价时为0
+}
+
+// v3Dex V3 DEX合约信息
+type v3Dex struct {
+	name     string
+	factory  common.Address
+	quoter   common.Address
+	feeTiers []uint32
+}
+
+// v3PoolRef 已找到的V3池
+type v3PoolRef struct {
+	dex     *v3Dex
+	fee     uint32
+	address common.Address
+}
+
+// quoteExactInputSingleParams QuoterV2.quoteExactInputSingle的参数
+type quoteExactInputSingleParams struct {
+	TokenIn           common.Address
+	TokenOut          common.Address
+	AmountIn          *big.Int
+	Fee               *big.Int
+	SqrtPriceLimitX96 *big.Int
+}
+
+// newV3Dexes 根据配置生成V3 DEX列表
+func newV3Dexes(configs []config.V3DexConfig) []*v3Dex {
+	if len(configs) == 0 {
+		configs = defaultV3Dexes
+	}
+
+	dexes := make([]*v3Dex, 0, len(configs))
+	for _, c := range configs {
+		dexes = append(dexes, &v3Dex{
+			name:     c.Name,
+			factory:  common.HexToAddress(c.Factory),
+			quoter:   common.HexToAddress(c.Quoter),
+			feeTiers: c.FeeTiers,
+		})
+	}
+	return dexes
+}
+
+// findV3Pools 查找两个代币在所有V3 DEX和手续费等级下的池子，同时返回查询失败的工厂合约调用
+func (s *BSCService) findV3Pools(tokenA, tokenB common.Address) ([]*v3PoolRef, error) {
+	var refs []*v3PoolRef
+	for _, dex := range s.v3Dexes {
+		for _, fee := range dex.feeTiers {
+			refs = append(refs, &v3PoolRef{dex: dex, fee: fee})
+		}
+	}
+
+	errs := make([]error, len(refs))
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		go func(i int, ref *v3PoolRef) {
+			defer wg.Done()
+			output, err := s.callContract(v3FactoryABI, ref.dex.factory, "getPool", tokenA, tokenB, new(big.Int).SetUint64(uint64(ref.fee)))
+			if err != nil {
+				errs[i] = fmt.Errorf("failed to find %s pool with fee %d: %w", ref.dex.name, ref.fee, err)
+				return
+			}
+			ref.address = output[0].(common.Address)
+		}(i, ref)
+	}
+	wg.Wait()
+
+	var pools []*v3PoolRef
+	for _, ref := range refs {
+		if ref.address != (common.Address{}) {
+			pools = append(pools, ref)
+		}
+	}
+	return pools, errors.Join(errs...)
+}
+
+// GetV3Pools 获取两个代币之间所有V3池的状态和USD锁仓价值
+// 部分池子查询失败时返回读取成功的池子和查询失败的原因
+func (s *BSCService) GetV3Pools(tokenA, tokenB string) ([]*V3PoolInfo, error) {
+	refs, err := s.findV3Pools(common.HexToAddress(tokenA), common.HexToAddress(tokenB))
+	errs := []error{err}
+
+	// 同一交易对的池子共用代币USD价格
+	prices := make(map[common.Address]*big.Rat, 2)
+	if len(refs) > 0 {
+		for _, token := range []common.Address{common.HexToAddress(tokenA), common.HexToAddress(tokenB)} {
+			if price, err := s.getTokenUSDPrice(token); err == nil {
+				prices[token] = price
+			}
+		}
+	}
+
+	pools := make([]*V3PoolInfo, len(refs))
+	poolErrs := make([]error, len(refs))
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		go func(i int, ref *v3PoolRef) {
+			defer wg.Done()
+			pools[i], poolErrs[i] = s.getV3PoolInfo(ref, prices)
+		}(i, ref)
+	}
+	wg.Wait()
+
+	var result []*V3PoolInfo
+	for i, pool := range pools {
+		if poolErrs[i] != nil {
+			errs = append(errs, fmt.Errorf("failed to read V3 pool %s: %w", refs[i].address.Hex(), poolErrs[i]))
+			continue
+		}
+		result = append(result, pool)
+	}
+	return result, errors.Join(errs...)
+}
+
+// getV3PoolInfo 读取V3池的slot0、流动性和代币余额，按prices中的代币价格计算锁仓价值
+func (s *BSCService) getV3PoolInfo(ref *v3PoolRef, prices map[common.Address]*big.Rat) (*V3PoolInfo, error) {
+	slot0, err := s.callContract(v3PoolABI, ref.address, "slot0")
+	if err != nil {
+		return nil, err
+	}
+	sqrtPriceX96 := slot0[0].(*big.Int)
+	tick := slot0[1].(*big.Int)
+
+	liquidityOutput, err := s.callContract(v3PoolABI, ref.address, "liquidity")
+	if err != nil {
+		return nil, err
+	}
+	liquidity := liquidityOutput[0].(*big.Int)
+
+	token0, token1, err := s.getPoolTokens(v3PoolABI, ref.address)
+	if err != nil {
+		return nil, err
+	}
+	reserve0, reserve1, err := s.getPoolBalances(ref.address, token0, token1)
+	if err != nil {
+		return nil, err
+	}
+
+	decimals0, err := s.getTokenDecimals(token0.Hex())
+	if err != nil {
+		return nil, err
+	}
+	decimals1, err := s.getTokenDecimals(token1.Hex())
+	if err != nil {
+		return nil, err
+	}
+
+	tvl := v3PoolTVL([2]common.Address{token0, token1}, [2]*big.Int{reserve0, reserve1}, [2]uint8{decimals0, decimals1}, prices)
+
+	return &V3PoolInfo{
+		Dex:          ref.dex.name,
+		PoolAddress:  ref.address.Hex(),
+		Token0:       token0.Hex(),
+		Token1:       token1.Hex(),
+		Fee:          ref.fee,
+		SqrtPriceX96: sqrtPriceX96.String(),
+		Tick:         tick.Int64(),
+		Liquidity:    liquidity.String(),
+		Price:        formatRat(v3SpotPrice(sqrtPriceX96, decimals0, decimals1), 18),
+		Reserve0:     reserve0.String(),
+		Reserve1:     reserve1.String(),
+		TVLUSD:       formatRat(tvl, 18),
+	}, nil
+}
+
+// v3PoolTVL 按prices中的代币价格计算V3池的锁仓价值
+// 集中流动性池两侧价值不一定相等，只计入可定价的代币
+func v3PoolTVL(tokens [2]common.Address, amounts [2]*big.Int, decimals [2]uint8, prices map[common.Address]*big.Rat) *big.Rat {
+	tvl := new(big.Rat)
+	for i, token := range tokens {
+		if price := prices[token]; price != nil {
+			tvl.Add(tvl, new(big.Rat).Mul(new(big.Rat).SetFrac(amounts[i], pow10(decimals[i])), price))
+		}
+	}
+	return tvl
+}
+
+// getPoolTokens 读取池子的token0和token1
+func (s *BSCService) getPoolTokens(definition string, pool common.Address) (common.Address, common.Address, error) {
+	token0, err := s.callContract(definition, pool, "token0")
+	if err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+	token1, err := s.callContract(definition, pool, "token1")
+	if err != nil {
+		return common.Address{}, common.Address{}, err
+	}
+	return token0[0].(common.Address), token1[0].(common.Address), nil
+}
+
+// getPoolBalances 读取池子持有的两种代币余额
+func (s *BSCService) getPoolBalances(pool, token0, token1 common.Address) (*big.Int, *big.Int, error) {
+	balance0, err := s.callContract(erc20ABI, token0, "balanceOf", pool)
+	if err != nil {
+		return nil, nil, err
+	}
+	balance1, err := s.callContract(erc20ABI, token1, "balanceOf", pool)
+	if err != nil {
+		return nil, nil, err
+	}
+	return balance0[0].(*big.Int), balance1[0].(*big.Int), nil
+}
+
+// v3SpotPrice 根据sqrtPriceX96计算1个token0可兑换的token1数量（已按精度调整）
+func v3SpotPrice(sqrtPriceX96 *big.Int, decimals0, decimals1 uint8) *big.Rat {
+	// price = sqrtPriceX96^2 / 2^192 * 10^decimals0 / 10^decimals1
+	numerator := new(big.Int).Mul(sqrtPriceX96, sqrtPriceX96)
+	numerator.Mul(numerator, pow10(decimals0))
+	denominator := new(big.Int).Lsh(big.NewInt(1), 192)
+	denominator.Mul(denominator, pow10(decimals1))
+	return new(big.Rat).SetFrac(numerator, denominator)
+}
+
+// quoteV3ExactIn 通过QuoterV2查询在指定池中兑换的输出数量
+func (s *BSCService) quoteV3ExactIn(ref *v3PoolRef, tokenIn, tokenOut common.Address, amountIn *big.Int) (*big.Int, error) {
+	output, err := s.callContract(v3QuoterV2ABI, ref.dex.quoter, "quoteExactInputSingle", quoteExactInputSingleParams{
+		TokenIn:           tokenIn,
+		TokenOut:          tokenOut,
+		AmountIn:          amountIn,
+		Fee:               new(big.Int).SetUint64(uint64(ref.fee)),
+		SqrtPriceLimitX96: big.NewInt(0),
+	})
+	if err != nil {
+		return nil, err
+	}
+	return output[0].(*big.Int), nil
+}
+
+// findBestV3Route 在两个代币之间的所有V3池中选择输出最多的池子
+func (s *BSCService) findBestV3Route(tokenIn, tokenOut common.Address, amountIn *big.Int) (*routeQuote, error) {
+	refs, err := s.findV3Pools(tokenIn, tokenOut)
+	if err != nil {
+		logger.Warnf("Failed to find some V3 pools: %v", err)
+	}
+
+	amounts := make([]*big.Int, len(refs))
+	var wg sync.WaitGroup
+	for i, ref := range refs {
+		wg.Add(1)
+		go func(i int, ref *v3PoolRef) {
+			defer wg.Done()
+			amountOut, err := s.quoteV3ExactIn(ref, tokenIn, tokenOut, amountIn)
+			if err != nil {
+				return
+			}
+			amounts[i] = amountOut
+		}(i, ref)
+	}
+	wg.Wait()
+
+	var best *routeQuote
+	for i, amountOut := range amounts {
+		if amountOut == nil || amountOut.Sign() <= 0 {
+			continue
+		}
+		if best == nil || amountOut.Cmp(best.amountOut()) > 0 {
+			best = &routeQuote{
+				path:    []common.Address{tokenIn, tokenOut},
+				amounts: []*big.Int{new(big.Int).Set(amountIn), amountOut},
+				hops:    []routeHop{{protocol: refs[i].dex.name, fee: refs[i].fee, pool: refs[i].address}},
+			}
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no V3 pool found from %s to %s", tokenIn.Hex(), tokenOut.Hex())
+	}
+	return best, nil
+}
+
+// findBestPriceRoute 综合V2路由和V3池选择输出最多的兑换路径
+func (s *BSCService) findBestPriceRoute(tokenIn, tokenOut common.Address, amountIn *big.Int) (*routeQuote, error) {
+	if tokenIn == tokenOut {
+		return s.findBestRoute(tokenIn, tokenOut, amountIn)
+	}
+
+	var (
+		mu         sync.Mutex
+		candidates []*routeQuote
+		wg         sync.WaitGroup
+	)
+	addCandidate := func(quote *routeQuote) {
+		mu.Lock()
+		candidates = append(candidates, quote)
+		mu.Unlock()
+	}
+
+	// V2多跳路由
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if quote, err := s.findBestRoute(tokenIn, tokenOut, amountIn); err == nil {
+			addCandidate(quote)
+		}
+	}()
+
+	// V3直接兑换
+	wg.Add(1)
+	go func() {
+		defer wg.Done()
+		if quote, err := s.findBestV3Route(tokenIn, tokenOut, amountIn); err == nil {
+			addCandidate(quote)
+		}
+	}()
+
+	// 先经V3池兑换为基础代币，再经V2或V3兑换为目标代币
+	for _, base := range s.baseTokens {
+		if base == tokenIn || base == tokenOut {
+			continue
+		}
+		wg.Add(1)
+		go func(base common.Address) {
+			defer wg.Done()
+			first, err := s.findBestV3Route(tokenIn, base, amountIn)
+			if err != nil {
+				return
+			}
+
+			var second *routeQuote
+			path := []common.Address{base, tokenOut}
+			if amounts, err := s.getAmountsOut(first.amountOut(), path); err == nil {
+				second = &routeQuote{path: path, amounts: amounts, hops: v2Hops(path)}
+			}
+			if quote, err := s.findBestV3Route(base, tokenOut, first.amountOut()); err == nil {
+				if second == nil || quote.amountOut().Cmp(second.amountOut()) > 0 {
+					second = quote
+				}
+			}
+			if second != nil {
+				addCandidate(joinRoutes(first, second))
+			}
+		}(base)
+	}
+	wg.Wait()
+
+	// 输出相同时优先选择跳数更少的路径
+	var best *routeQuote
+	for _, quote := range candidates {
+		if quote.amountOut().Sign() <= 0 {
+			continue
+		}
+		if best == nil {
+			best = quote
+			continue
+		}
+		cmp := quote.amountOut().Cmp(best.amountOut())
+		if cmp > 0 || (cmp == 0 && len(quote.path) < len(best.path)) {
+			best = quote
+		}
+	}
+
+	if best == nil {
+		return nil, fmt.Errorf("no route found from %s to %s", tokenIn.Hex(), tokenOut.Hex())
+	}
+	return best, nil
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestV3SpotPrice(t *testing.T) {
+	q96 := new(big.Int).Lsh(big.NewInt(1), 96)
+
+	// sqrtPrice = 2 => price = 4
+	sqrtPriceX96 := new(big.Int).Mul(q96, big.NewInt(2))
+	assert.Equal(t, "4", formatRat(v3SpotPrice(sqrtPriceX96, 18, 18), 18))
+
+	// 原始价格 10^12，token0为6位精度、token1为18位精度时实际价格为1
+	sqrtPriceX96 = new(big.Int).Mul(q96, big.NewInt(1_000_000))
+	assert.Equal(t, "1", formatRat(v3SpotPrice(sqrtPriceX96, 6, 18), 18))
+}
+
+func TestGetV3Pools(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "V3 Token", "VT", 9)
+	pool := chain.addV3Pool(PancakeSwapV3Factory, token, tokens["WBNB"], 2500, 1_000_000, 500_000)
+
+	service := newTestBSCService(chain)
+	pools, err := service.GetV3Pools(token.Hex(), tokens["WBNB"].Hex())
+	require.NoError(t, err)
+	require.Len(t, pools, 1)
+
+	info := pools[0]
+	assert.Equal(t, "pancakeswap-v3", info.Dex)
+	assert.Equal(t, pool.Hex(), info.PoolAddress)
+	assert.Equal(t, uint32(2500), info.Fee)
+	assert.Equal(t, token.Hex(), info.Token0)
+
+	// 1 VT = 0.5 WBNB
+	assertDecimalBetween(t, info.Price, "0.4999", "0.5001")
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(1_000_000), pow10(9)).String(), info.Reserve0)
+	assert.Equal(t, new(big.Int).Mul(big.NewInt(500_000), pow10(18)).String(), info.Reserve1)
+	assert.NotEqual(t, "0", info.TVLUSD)
+
+	// V3池的锁仓价值计入合计
+	total := CombinedTVLUSD(&LiquidityInfo{TVLUSD: "100"}, pools)
+	expected, _ := new(big.Rat).SetString(info.TVLUSD)
+	assert.Equal(t, formatRat(expected.Add(expected, big.NewRat(100, 1)), 18), total)
+}
+
+func TestGetTokenPriceFromV3Pool(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "V3 Token", "VT", 9)
+	pool := chain.addV3Pool(PancakeSwapV3Factory, token, tokens["WBNB"], 500, 1_000_000, 500_000)
+
+	service := newTestBSCService(chain)
+	price, err := service.GetTokenPrice(token.Hex(), "")
+	require.NoError(t, err)
+
+	assert.Equal(t, []string{token.Hex(), tokens["WBNB"].Hex()}, price.Route)
+	assert.Equal(t, []string{"pancakeswap-v3/500"}, price.RoutePools)
+	assert.Equal(t, pool.Hex(), price.LiquidityPool)
+	assertDecimalBetween(t, price.PriceInBNB, "0.499", "0.5")
+}
+
+func TestFindBestPriceRouteMergesV2AndV3(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Mixed Token", "MT", 18)
+
+	// V2池价格更差，Uniswap V3池价格更好
+	chain.addPair(token, tokens["WBNB"], 1_000_000, 400_000)
+	pool := chain.addV3Pool(UniswapV3Factory, token, tokens["WBNB"], 3000, 1_000_000, 500_000)
+
+	service := newTestBSCService(chain)
+	route, err := service.findBestPriceRoute(token, tokens["WBNB"], pow10(18))
+	require.NoError(t, err)
+	require.Len(t, route.hops, 1)
+	assert.Equal(t, pool, route.hops[0].pool)
+	assert.Equal(t, "uniswap-v3/3000", route.hops[0].label())
+
+	// 提高V2池价格后应改为选择V2
+	_, pair := chain.findPair(token, tokens["WBNB"])
+	if pair.token0 == token {
+		pair.reserve1 = new(big.Int).Mul(big.NewInt(600_000), pow10(18))
+	} else {
+		pair.reserve0 = new(big.Int).Mul(big.NewInt(600_000), pow10(18))
+	}
+	route, err = service.findBestPriceRoute(token, tokens["WBNB"], pow10(18))
+	require.NoError(t, err)
+	assert.Equal(t, []string{PancakeSwapV2Protocol}, route.poolLabels())
+}
+
+func TestFindBestPriceRouteV3ThenV2(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "Stable V3", "SV", 6)
+	chain.addV3Pool(PancakeSwapV3Factory, token, tokens["USDT"], 100, 1_000_000, 1_000_000)
+
+	service := newTestBSCService(chain)
+	route, err := service.findBestPriceRoute(token, tokens["WBNB"], pow10(6))
+	require.NoError(t, err)
+
+	assert.Equal(t, []common.Address{token, tokens["USDT"], tokens["WBNB"]}, route.path)
+	assert.Equal(t, []string{"pancakeswap-v3/100", PancakeSwapV2Protocol}, route.poolLabels())
+}
+
+func TestGetHopLiquidityV3CountsPriceableSide(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	token := chain.addToken("0x00000000000000000000000000000000000000aa", "V3 Token", "VT", 18)
+	chain.addV3Pool(PancakeSwapV3Factory, token, tokens["WBNB"], 500, 1_000_000, 500_000)
+	// 只与VT组成池子的代币无法定价
+	orphan := chain.addToken("0x00000000000000000000000000000000000000bb", "Orphan", "OR", 18)
+	pool := chain.addV3Pool(PancakeSwapV3Factory, token, orphan, 2500, 1_000, 5_000)
+
+	service := newTestBSCService(chain)
+	price, err := service.getTokenUSDPrice(token)
+	require.NoError(t, err)
+
+	hop := routeHop{protocol: "pancakeswap-v3", fee: 2500, pool: pool}
+	address, tvl, err := service.getHopLiquidity(token, orphan, hop, map[common.Address]*big.Rat{token: price})
+	require.NoError(t, err)
+	assert.Equal(t, pool.Hex(), address)
+
+	// 与 GetV3Pools 相同只计入可定价的VT，不按两侧等值翻倍
+	assert.Equal(t, formatRat(new(big.Rat).Mul(big.NewRat(1_000, 1), price), 18), tvl)
+	pools, err := service.GetV3Pools(token.Hex(), orphan.Hex())
+	require.NoError(t, err)
+	require.Len(t, pools, 1)
+	assert.Equal(t, pools[0].TVLUSD, tvl)
+}
@@ -0,0 +1,414 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"time"
+
+	"chain/internal/config"
+	"chain/pkg/logger"
+
+	"github.com/redis/go-redis/v9"
+	"golang.org/x/sync/singleflight"
+)
+
+// 缓存的接口，也是 cache.ttls 中的键
+const (
+	CacheCryptoPrice  = "crypto_price"
+	CacheCryptoPrices = "crypto_prices"
+	CacheTopPrices    = "top_prices"
+	CacheSearch       = "search"
+	CachePriceHistory = "price_history"
+	CacheTokenPrice   = "token_price"
+	CacheFXRates      = "fx_rates"
+)
+
+// 缓存后端类型
+const (
+	CacheBackendMemory = "memory"
+	CacheBackendRedis  = "redis"
+	CacheBackendNone   = "none"
+)
+
+// defaultCacheTTLs 各接口的默认缓存时间
+var defaultCacheTTLs = map[string]time.Duration{
+	CacheCryptoPrice:  30 * time.Second,
+	CacheCryptoPrices: 30 * time.Second,
+	CacheTopPrices:    time.Minute,
+	CacheSearch:       5 * time.Minute,
+	CachePriceHistory: 5 * time.Minute,
+	CacheTokenPrice:   10 * time.Second,
+	CacheFXRates:      time.Hour,
+}
+
+const (
+	defaultCacheStaleTTL   = 30 * time.Second
+	defaultRedisPrefix     = "chain:cache:"
+	cacheRefreshTimeout    = time.Minute // 后台刷新和未命中查询的最长时间
+	memoryCacheSweepPeriod = time.Minute // 内存缓存清理过期条目的间隔
+)
+
+// CacheBackend 缓存存储后端，值为序列化后的缓存条目
+type CacheBackend interface {
+	// Get 读取缓存，不存在或已过期时返回false
+	Get(ctx context.Context, key string) ([]byte, bool, error)
+	// Set 写入缓存，ttl后过期
+	Set(ctx context.Context, key string, value []byte, ttl time.Duration) error
+	// Name 后端名称
+	Name() string
+}
+
+// Cache 带TTL、请求合并和过期后台刷新的缓存
+//
+// 同一个键的并发未命中只执行一次查询；条目过期后的stale时间内仍返回旧值，
+// 同时在后台刷新。nil *Cache 不缓存，直接执行查询。
+type Cache struct {
+	backend  CacheBackend
+	ttls     map[string]time.Duration
+	staleTTL time.Duration
+	group    singleflight.Group
+
+	statsMu sync.Mutex
+	stats   map[string]*cacheCounters
+}
+
+// cacheEntry 缓存条目
+type cacheEntry struct {
+	Value      json.RawMessage `json:"value"`
+	FreshUntil int64           `json:"fresh_until"` // UnixNano，之后为过期数据
+}
+
+// cacheCounters 单个接口的命中统计
+type cacheCounters struct {
+	hits, staleHits, misses, coalesced, errors atomic.Uint64
+}
+
+// CacheStats 单个接口的缓存统计
+type CacheStats struct {
+	Endpoint  string  `json:"endpoint"`
+	Hits      uint64  `json:"hits"`
+	StaleHits uint64  `json:"stale_hits"` // 返回过期数据并后台刷新的次数
+	Misses    uint64  `json:"misses"`
+	Coalesced uint64  `json:"coalesced"` // 未命中时合并到进行中查询的次数
+	Errors    uint64  `json:"errors"`    // 缓存后端读写失败的次数
+	HitRate   float64 `json:"hit_rate"`  // (hits + stale_hits) / 总请求数
+}
+
+// CacheReport 缓存统计报告
+type CacheReport struct {
+	Backend   string        `json:"backend"`
+	Endpoints []*CacheStats `json:"endpoints"`
+}
+
+// NewCache 按配置创建缓存，backend为none时返回nil
+// Redis不可用时使用内存缓存
+func NewCache(cfg *config.Config) *Cache {
+	switch strings.ToLower(cfg.Cache.Backend) {
+	case CacheBackendNone:
+		return nil
+	case CacheBackendRedis:
+		client := redis.NewClient(&redis.Options{
+			Addr:     cfg.Cache.RedisAddr,
+			Password: cfg.Cache.RedisPassword,
+			DB:       cfg.Cache.RedisDB,
+		})
+		ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+		defer cancel()
+		if err := client.Ping(ctx).Err(); err != nil {
+			logger.Warnf("Redis cache unavailable at %s, using memory cache: %v", cfg.Cache.RedisAddr, err)
+			client.Close()
+			break
+		}
+		return newCache(NewRedisCacheBackend(client, cfg.Cache.RedisPrefix), cfg.Cache)
+	case "", CacheBackendMemory:
+	default:
+		logger.Warnf("Unknown cache backend %s, using memory cache", cfg.Cache.Backend)
+	}
+	return newCache(NewMemoryCacheBackend(), cfg.Cache)
+}
+
+// defaultCache 服务默认使用的内存缓存，backend为none时返回nil
+func defaultCache(cfg *config.Config) *Cache {
+	if strings.EqualFold(cfg.Cache.Backend, CacheBackendNone) {
+		return nil
+	}
+	return newCache(NewMemoryCacheBackend(), cfg.Cache)
+}
+
+// newCache 使用指定后端创建缓存
+func newCache(backend CacheBackend, cfg config.CacheConfig) *Cache {
+	ttls := make(map[string]time.Duration, len(defaultCacheTTLs))
+	for endpoint, ttl := range defaultCacheTTLs {
+		ttls[endpoint] = ttl
+	}
+	for endpoint, seconds := range cfg.TTLs {
+		ttls[strings.ToLower(endpoint)] = time.Duration(seconds) * time.Second
+	}
+
+	staleTTL := defaultCacheStaleTTL
+	if cfg.StaleTTL > 0 {
+		staleTTL = time.Duration(cfg.StaleTTL) * time.Second
+	} else if cfg.StaleTTL < 0 {
+		staleTTL = 0
+	}
+
+	return &Cache{
+		backend:  backend,
+		ttls:     ttls,
+		staleTTL: staleTTL,
+		stats:    make(map[string]*cacheCounters),
+	}
+}
+
+// counters 返回接口的统计计数器
+func (c *Cache) counters(endpoint string) *cacheCounters {
+	c.statsMu.Lock()
+	defer c.statsMu.Unlock()
+	counters, ok := c.stats[endpoint]
+	if !ok {
+		counters = &cacheCounters{}
+		c.stats[endpoint] = counters
+	}
+	return counters
+}
+
+// Fetch 读取缓存，未命中时调用fetch查询并写入缓存，结果解码到out（指针）
+// 查询出错时不缓存
+func (c *Cache) Fetch(ctx context.Context, endpoint, key string, out interface{}, fetch func(ctx context.Context) (interface{}, error)) error {
+	ttl := time.Duration(0)
+	if c != nil {
+		ttl = c.ttls[endpoint]
+	}
+	if ttl <= 0 {
+		return fetchDirect(ctx, out, fetch)
+	}
+
+	counters := c.counters(endpoint)
+	key = endpoint + ":" + key
+
+	raw, ok, err := c.backend.Get(ctx, key)
+	if err != nil {
+		counters.errors.Add(1)
+		logger.Warnf("Failed to read cache %s: %v", key, err)
+	}
+	if ok {
+		var entry cacheEntry
+		if err := json.Unmarshal(raw, &entry); err == nil && json.Unmarshal(entry.Value, out) == nil {
+			if time.Now().UnixNano() < entry.FreshUntil {
+				counters.hits.Add(1)
+				return nil
+			}
+
+			// 过期数据：直接返回，后台刷新（与进行中的查询合并）
+			counters.staleHits.Add(1)
+			refreshCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheRefreshTimeout)
+			ch := c.group.DoChan(key, func() (interface{}, error) {
+				return c.load(refreshCtx, endpoint, key, ttl, counters, fetch)
+			})
+			go func() {
+				<-ch
+				cancel()
+			}()
+			return nil
+		}
+	}
+
+	counters.misses.Add(1)
+	executed := false
+	value, err, _ := c.group.Do(key, func() (interface{}, error) {
+		executed = true
+		// 合并的请求共享查询，不随单个调用方取消，但不超过刷新时限
+		loadCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), cacheRefreshTimeout)
+		defer cancel()
+		return c.load(loadCtx, endpoint, key, ttl, counters, fetch)
+	})
+	if !executed {
+		counters.coalesced.Add(1)
+	}
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(value.([]byte), out)
+}
+
+// Refresh 跳过缓存直接调用fetch查询，结果写入缓存并解码到out（指针），用于需要最新数据的调用方
+// 查询出错时保留原有缓存
+func (c *Cache) Refresh(ctx context.Context, endpoint, key string, out interface{}, fetch func(ctx context.Context) (interface{}, error)) error {
+	ttl := time.Duration(0)
+	if c != nil {
+		ttl = c.ttls[endpoint]
+	}
+	if ttl <= 0 {
+		return fetchDirect(ctx, out, fetch)
+	}
+
+	value, err := c.load(ctx, endpoint, endpoint+":"+key, ttl, c.counters(endpoint), fetch)
+	if err != nil {
+		return err
+	}
+	return json.Unmarshal(value.([]byte), out)
+}
+
+// fetchDirect 不经缓存调用fetch，结果写入out（指针）；与缓存路径一致，nil结果写入零值
+func fetchDirect(ctx context.Context, out interface{}, fetch func(ctx context.Context) (interface{}, error)) error {
+	value, err := fetch(ctx)
+	if err != nil {
+		return err
+	}
+	target := reflect.ValueOf(out).Elem()
+	if value == nil {
+		target.Set(reflect.Zero(target.Type()))
+		return nil
+	}
+	target.Set(reflect.ValueOf(value))
+	return nil
+}
+
+// load 执行查询并写入缓存，返回序列化后的值
+func (c *Cache) load(ctx context.Context, endpoint, key string, ttl time.Duration, counters *cacheCounters, fetch func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	value, err := fetch(ctx)
+	if err != nil {
+		return nil, err
+	}
+	data, err := json.Marshal(value)
+	if err != nil {
+		return nil, fmt.Errorf("failed to encode %s cache value: %w", endpoint, err)
+	}
+
+	entry, _ := json.Marshal(&cacheEntry{Value: data, FreshUntil: time.Now().Add(ttl).UnixNano()})
+	if err := c.backend.Set(ctx, key, entry, ttl+c.staleTTL); err != nil {
+		counters.errors.Add(1)
+		logger.Warnf("Failed to write cache %s: %v", key, err)
+	}
+	return data, nil
+}
+
+// Stats 返回各接口的命中统计，按接口名排序
+func (c *Cache) Stats() *CacheReport {
+	if c == nil {
+		return &CacheReport{Backend: CacheBackendNone, Endpoints: []*CacheStats{}}
+	}
+
+	c.statsMu.Lock()
+	endpoints := make([]string, 0, len(c.stats))
+	for endpoint := range c.stats {
+		endpoints = append(endpoints, endpoint)
+	}
+	c.statsMu.Unlock()
+	sort.Strings(endpoints)
+
+	report := &CacheReport{Backend: c.backend.Name(), Endpoints: make([]*CacheStats, 0, len(endpoints))}
+	for _, endpoint := range endpoints {
+		counters := c.counters(endpoint)
+		stats := &CacheStats{
+			Endpoint:  endpoint,
+			Hits:      counters.hits.Load(),
+			StaleHits: counters.staleHits.Load(),
+			Misses:    counters.misses.Load(),
+			Coalesced: counters.coalesced.Load(),
+			Errors:    counters.errors.Load(),
+		}
+		if total := stats.Hits + stats.StaleHits + stats.Misses; total > 0 {
+			stats.HitRate = float64(stats.Hits+stats.StaleHits) / float64(total)
+		}
+		report.Endpoints = append(report.Endpoints, stats)
+	}
+	return report
+}
+
+// memoryCacheBackend 进程内缓存后端
+type memoryCacheBackend struct {
+	mu        sync.Mutex
+	items     map[string]memoryCacheItem
+	lastSweep time.Time
+}
+
+type memoryCacheItem struct {
+	value     []byte
+	expiresAt time.Time
+}
+
+// NewMemoryCacheBackend 创建内存缓存后端
+func NewMemoryCacheBackend() CacheBackend {
+	return &memoryCacheBackend{
+		items:     make(map[string]memoryCacheItem),
+		lastSweep: time.Now(),
+	}
+}
+
+func (m *memoryCacheBackend) Name() string {
+	return CacheBackendMemory
+}
+
+func (m *memoryCacheBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	item, ok := m.items[key]
+	if !ok {
+		return nil, false, nil
+	}
+	if time.Now().After(item.expiresAt) {
+		delete(m.items, key)
+		return nil, false, nil
+	}
+	return item.value, true, nil
+}
+
+func (m *memoryCacheBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	now := time.Now()
+	m.items[key] = memoryCacheItem{value: value, expiresAt: now.Add(ttl)}
+
+	// 定期清理过期条目，避免不再访问的键一直占用内存
+	if now.Sub(m.lastSweep) >= memoryCacheSweepPeriod {
+		for k, item := range m.items {
+			if now.After(item.expiresAt) {
+				delete(m.items, k)
+			}
+		}
+		m.lastSweep = now
+	}
+	return nil
+}
+
+// redisCacheBackend Redis缓存后端，多个服务实例可共享缓存
+type redisCacheBackend struct {
+	client *redis.Client
+	prefix string
+}
+
+// NewRedisCacheBackend 创建Redis缓存后端，键加上prefix前缀
+func NewRedisCacheBackend(client *redis.Client, prefix string) CacheBackend {
+	if prefix == "" {
+		prefix = defaultRedisPrefix
+	}
+	return &redisCacheBackend{client: client, prefix: prefix}
+}
+
+func (r *redisCacheBackend) Name() string {
+	return CacheBackendRedis
+}
+
+func (r *redisCacheBackend) Get(ctx context.Context, key string) ([]byte, bool, error) {
+	value, err := r.client.Get(ctx, r.prefix+key).Bytes()
+	if err == redis.Nil {
+		return nil, false, nil
+	}
+	if err != nil {
+		return nil, false, err
+	}
+	return value, true, nil
+}
+
+func (r *redisCacheBackend) Set(ctx context.Context, key string, value []byte, ttl time.Duration) error {
+	return r.client.Set(ctx, r.prefix+key, value, ttl).Err()
+}
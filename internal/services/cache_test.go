@@ -0,0 +1,263 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chain/internal/config"
+
+	"github.com/alicebob/miniredis/v2"
+	"github.com/redis/go-redis/v9"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// endpointStats 返回指定接口的统计
+func endpointStats(t *testing.T, cache *Cache, endpoint string) *CacheStats {
+	for _, stats := range cache.Stats().Endpoints {
+		if stats.Endpoint == endpoint {
+			return stats
+		}
+	}
+	t.Fatalf("no stats for endpoint %s", endpoint)
+	return nil
+}
+
+func TestCacheHitMissAndErrors(t *testing.T) {
+	cache := newCache(NewMemoryCacheBackend(), config.CacheConfig{})
+	var calls atomic.Int32
+	fetch := func(ctx context.Context) (interface{}, error) {
+		calls.Add(1)
+		// 未命中查询不随调用方取消，但有时限
+		_, ok := ctx.Deadline()
+		assert.True(t, ok)
+		return &CryptoPriceInfo{Symbol: "btc", CurrentPrice: 65000}, nil
+	}
+
+	for i := 0; i < 3; i++ {
+		var price *CryptoPriceInfo
+		require.NoError(t, cache.Fetch(context.Background(), CacheCryptoPrice, "btc", &price, fetch))
+		assert.Equal(t, 65000.0, price.CurrentPrice)
+	}
+	assert.Equal(t, int32(1), calls.Load())
+
+	// 查询失败不缓存
+	failing := func(ctx context.Context) (interface{}, error) {
+		calls.Add(1)
+		return nil, errors.New("upstream down")
+	}
+	for i := 0; i < 2; i++ {
+		var price *CryptoPriceInfo
+		assert.EqualError(t, cache.Fetch(context.Background(), CacheCryptoPrice, "eth", &price, failing), "upstream down")
+	}
+	assert.Equal(t, int32(3), calls.Load())
+
+	stats := endpointStats(t, cache, CacheCryptoPrice)
+	assert.Equal(t, uint64(2), stats.Hits)
+	assert.Equal(t, uint64(3), stats.Misses)
+	assert.InDelta(t, 0.4, stats.HitRate, 1e-9)
+	assert.Equal(t, CacheBackendMemory, cache.Stats().Backend)
+}
+
+func TestCacheRefresh(t *testing.T) {
+	cache := newCache(NewMemoryCacheBackend(), config.CacheConfig{})
+	price := 65000.0
+	fetch := func(ctx context.Context) (interface{}, error) {
+		return &CryptoPriceInfo{Symbol: "btc", CurrentPrice: price}, nil
+	}
+
+	var cached *CryptoPriceInfo
+	require.NoError(t, cache.Fetch(context.Background(), CacheCryptoPrice, "btc", &cached, fetch))
+
+	// 刷新跳过缓存并更新缓存，之后的读取命中新值
+	price = 66000
+	var fresh *CryptoPriceInfo
+	require.NoError(t, cache.Refresh(context.Background(), CacheCryptoPrice, "btc", &fresh, fetch))
+	assert.Equal(t, 66000.0, fresh.CurrentPrice)
+	require.NoError(t, cache.Fetch(context.Background(), CacheCryptoPrice, "btc", &cached, fetch))
+	assert.Equal(t, 66000.0, cached.CurrentPrice)
+
+	// 刷新失败时保留原有缓存
+	failing := func(ctx context.Context) (interface{}, error) {
+		return nil, errors.New("upstream down")
+	}
+	assert.EqualError(t, cache.Refresh(context.Background(), CacheCryptoPrice, "btc", &fresh, failing), "upstream down")
+	price = 67000
+	require.NoError(t, cache.Fetch(context.Background(), CacheCryptoPrice, "btc", &cached, fetch))
+	assert.Equal(t, 66000.0, cached.CurrentPrice)
+}
+
+func TestCacheCoalescesConcurrentMisses(t *testing.T) {
+	cache := newCache(NewMemoryCacheBackend(), config.CacheConfig{})
+	release := make(chan struct{})
+	var calls atomic.Int32
+	fetch := func(ctx context.Context) (interface{}, error) {
+		calls.Add(1)
+		<-release
+		return []float64{1, 2, 3}, nil
+	}
+
+	const callers = 10
+	var wg sync.WaitGroup
+	results := make([][]float64, callers)
+	for i := 0; i < callers; i++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			require.NoError(t, cache.Fetch(context.Background(), CachePriceHistory, "btc:7", &results[i], fetch))
+		}()
+	}
+
+	// 等待所有调用都进入未命中路径后再返回结果
+	require.Eventually(t, func() bool {
+		return endpointStats(t, cache, CachePriceHistory).Misses == callers
+	}, time.Second, time.Millisecond)
+	close(release)
+	wg.Wait()
+
+	assert.Equal(t, int32(1), calls.Load())
+	for _, result := range results {
+		assert.Equal(t, []float64{1, 2, 3}, result)
+	}
+	assert.Equal(t, uint64(callers-1), endpointStats(t, cache, CachePriceHistory).Coalesced)
+}
+
+func TestCacheStaleWhileRevalidate(t *testing.T) {
+	cache := newCache(NewMemoryCacheBackend(), config.CacheConfig{StaleTTL: 60})
+	var version atomic.Int32
+	refreshed := make(chan struct{}, 1)
+	fetch := func(ctx context.Context) (interface{}, error) {
+		v := version.Add(1)
+		if v > 1 {
+			refreshed <- struct{}{}
+		}
+		return []*CryptoPriceInfo{{Symbol: "btc", CurrentPrice: float64(v)}}, nil
+	}
+
+	// 写入立即过期的条目
+	cache.ttls[CacheTopPrices] = time.Nanosecond
+	var prices []*CryptoPriceInfo
+	require.NoError(t, cache.Fetch(context.Background(), CacheTopPrices, "10", &prices, fetch))
+	assert.Equal(t, 1.0, prices[0].CurrentPrice)
+
+	// 过期后仍返回旧值，并在后台刷新
+	cache.ttls[CacheTopPrices] = time.Minute
+	require.NoError(t, cache.Fetch(context.Background(), CacheTopPrices, "10", &prices, fetch))
+	assert.Equal(t, 1.0, prices[0].CurrentPrice)
+
+	select {
+	case <-refreshed:
+	case <-time.After(time.Second):
+		t.Fatal("stale entry was not refreshed")
+	}
+	require.Eventually(t, func() bool {
+		var latest []*CryptoPriceInfo
+		require.NoError(t, cache.Fetch(context.Background(), CacheTopPrices, "10", &latest, fetch))
+		return latest[0].CurrentPrice == 2
+	}, time.Second, 5*time.Millisecond)
+
+	assert.Equal(t, uint64(1), endpointStats(t, cache, CacheTopPrices).StaleHits)
+}
+
+func TestCacheDisabled(t *testing.T) {
+	var calls int
+	fetch := func(ctx context.Context) (interface{}, error) {
+		calls++
+		return &PriceInfo{PriceInUSD: "1"}, nil
+	}
+
+	// nil缓存和负数TTL都直接查询
+	var nilCache *Cache
+	disabled := newCache(NewMemoryCacheBackend(), config.CacheConfig{TTLs: map[string]int{"TOKEN_PRICE": -1}})
+	for _, cache := range []*Cache{nilCache, disabled, nilCache, disabled} {
+		var price *PriceInfo
+		require.NoError(t, cache.Fetch(context.Background(), CacheTokenPrice, "cake", &price, fetch))
+		assert.Equal(t, "1", price.PriceInUSD)
+	}
+	assert.Equal(t, 4, calls)
+
+	// 查询返回nil时结果为零值
+	price := &PriceInfo{PriceInUSD: "1"}
+	require.NoError(t, disabled.Fetch(context.Background(), CacheTokenPrice, "cake", &price, func(ctx context.Context) (interface{}, error) {
+		return nil, nil
+	}))
+	assert.Nil(t, price)
+	assert.Equal(t, CacheBackendNone, nilCache.Stats().Backend)
+	assert.Nil(t, NewCache(&config.Config{Cache: config.CacheConfig{Backend: "none"}}))
+}
+
+func TestRedisCacheBackend(t *testing.T) {
+	server := miniredis.RunT(t)
+	client := redis.NewClient(&redis.Options{Addr: server.Addr()})
+	defer client.Close()
+
+	cache := newCache(NewRedisCacheBackend(client, "test:"), config.CacheConfig{StaleTTL: 5})
+	var calls int
+	fetch := func(ctx context.Context) (interface{}, error) {
+		calls++
+		return map[string]*CryptoPriceInfo{"btc": {Symbol: "btc", CurrentPrice: 65000}}, nil
+	}
+
+	for i := 0; i < 2; i++ {
+		var prices map[string]*CryptoPriceInfo
+		require.NoError(t, cache.Fetch(context.Background(), CacheCryptoPrices, "bitcoin", &prices, fetch))
+		assert.Equal(t, 65000.0, prices["btc"].CurrentPrice)
+	}
+	assert.Equal(t, 1, calls)
+	assert.Equal(t, CacheBackendRedis, cache.Stats().Backend)
+
+	// Redis中的过期时间包含stale时间
+	require.True(t, server.Exists("test:crypto_prices:bitcoin"))
+	assert.Equal(t, 35*time.Second, server.TTL("test:crypto_prices:bitcoin"))
+
+	// 过期后重新查询
+	server.FastForward(time.Minute)
+	var prices map[string]*CryptoPriceInfo
+	require.NoError(t, cache.Fetch(context.Background(), CacheCryptoPrices, "bitcoin", &prices, fetch))
+	assert.Equal(t, 2, calls)
+
+	// Redis不可用时NewCache使用内存缓存
+	addr := server.Addr()
+	server.Close()
+	fallback := NewCache(&config.Config{Cache: config.CacheConfig{Backend: "redis", RedisAddr: addr}})
+	assert.Equal(t, CacheBackendMemory, fallback.Stats().Backend)
+}
+
+func TestPriceServiceCache(t *testing.T) {
+	provider := &stubPriceProvider{name: "stub", prices: map[string]float64{"btc": 65000, "eth": 3200}}
+	service := newPriceServiceWithProviders("", provider)
+	service.SetCache(newCache(NewMemoryCacheBackend(), config.CacheConfig{}))
+
+	for _, symbol := range []string{"btc", "BTC", "btc"} {
+		price, err := service.GetCryptoPrice(context.Background(), symbol)
+		require.NoError(t, err)
+		assert.Equal(t, "stub", price.Source)
+	}
+	// 批量查询的键与顺序和大小写无关
+	_, err := service.GetMultipleCryptoPrices(context.Background(), []string{"eth", "btc"})
+	require.NoError(t, err)
+	_, err = service.GetMultipleCryptoPrices(context.Background(), []string{"BTC", "eth"})
+	require.NoError(t, err)
+
+	assert.Equal(t, int32(2), provider.calls.Load())
+}
+
+func TestGetTokenPriceCache(t *testing.T) {
+	chain, tokens := newRouteTestChain()
+	service := newTestBSCService(chain)
+	service.SetCache(newCache(NewMemoryCacheBackend(), config.CacheConfig{}))
+
+	first, err := service.GetTokenPrice(tokens["CAKE"].Hex(), "")
+	require.NoError(t, err)
+	requests := chain.rpcRequests()
+
+	second, err := service.GetTokenPrice(tokens["CAKE"].Hex(), "")
+	require.NoError(t, err)
+	assert.Equal(t, first, second)
+	assert.Equal(t, requests, chain.rpcRequests())
+	assert.Equal(t, uint64(1), endpointStats(t, service.cache, CacheTokenPrice).Hits)
+}
@@ -0,0 +1,150 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"chain/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CandleStore 代币价格K线存储，代币地址统一保存为校验和格式
+type CandleStore interface {
+	// SaveCandles 保存K线并记录代币K线已聚合到的区块，两者同时写入；代币、周期和开盘时间相同的K线会被覆盖
+	SaveCandles(token string, candles []*models.PriceCandle, syncedBlock uint64) error
+	// GetCandles 返回开盘时间在[from, to]内的K线，按开盘时间升序排列
+	GetCandles(token, interval string, from, to time.Time) ([]*models.PriceCandle, error)
+	// GetSyncedBlock 返回代币K线已聚合到的区块，未聚合过时ok为false
+	GetSyncedBlock(token string) (block uint64, ok bool, err error)
+}
+
+// candleKey 内存存储中K线的唯一键
+type candleKey struct {
+	token    string
+	interval string
+	openTime int64
+}
+
+// memoryCandleStore 进程内的K线存储，服务重启后需要重新聚合
+type memoryCandleStore struct {
+	mu      sync.RWMutex
+	candles map[candleKey]models.PriceCandle
+	synced  map[string]uint64
+}
+
+// NewMemoryCandleStore 创建内存K线存储
+func NewMemoryCandleStore() CandleStore {
+	return &memoryCandleStore{
+		candles: make(map[candleKey]models.PriceCandle),
+		synced:  make(map[string]uint64),
+	}
+}
+
+// SaveCandles 保存K线并记录代币K线已聚合到的区块
+func (m *memoryCandleStore) SaveCandles(token string, candles []*models.PriceCandle, syncedBlock uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, candle := range candles {
+		copied := *candle
+		copied.TokenAddress = normalizeAddress(candle.TokenAddress)
+		m.candles[candleKey{copied.TokenAddress, copied.Interval, copied.OpenTime.Unix()}] = copied
+	}
+	m.synced[normalizeAddress(token)] = syncedBlock
+	return nil
+}
+
+// GetCandles 返回开盘时间在[from, to]内的K线
+func (m *memoryCandleStore) GetCandles(token, interval string, from, to time.Time) ([]*models.PriceCandle, error) {
+	token = normalizeAddress(token)
+
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var candles []*models.PriceCandle
+	for key, candle := range m.candles {
+		if key.token != token || key.interval != interval || candle.OpenTime.Before(from) || candle.OpenTime.After(to) {
+			continue
+		}
+		copied := candle
+		candles = append(candles, &copied)
+	}
+	sort.Slice(candles, func(i, j int) bool {
+		return candles[i].OpenTime.Before(candles[j].OpenTime)
+	})
+	return candles, nil
+}
+
+// GetSyncedBlock 返回代币K线已聚合到的区块
+func (m *memoryCandleStore) GetSyncedBlock(token string) (uint64, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	block, ok := m.synced[normalizeAddress(token)]
+	return block, ok, nil
+}
+
+// dbCandleStore 基于数据库的K线存储
+type dbCandleStore struct {
+	db *gorm.DB
+}
+
+// NewDBCandleStore 创建数据库K线存储，需要已迁移 models.PriceCandle 和 models.CandleSyncState
+func NewDBCandleStore(db *gorm.DB) CandleStore {
+	return &dbCandleStore{db: db}
+}
+
+// SaveCandles 在同一事务中保存K线和代币K线已聚合到的区块
+func (d *dbCandleStore) SaveCandles(token string, candles []*models.PriceCandle, syncedBlock uint64) error {
+	return d.db.Transaction(func(tx *gorm.DB) error {
+		if len(candles) > 0 {
+			for _, candle := range candles {
+				candle.TokenAddress = normalizeAddress(candle.TokenAddress)
+			}
+			err := tx.Clauses(clause.OnConflict{
+				Columns: []clause.Column{{Name: "token_address"}, {Name: "candle_interval"}, {Name: "open_time"}},
+				DoUpdates: clause.AssignmentColumns([]string{
+					"open_usd", "high_usd", "low_usd", "close_usd",
+					"open_bnb", "high_bnb", "low_bnb", "close_bnb",
+					"volume", "volume_usd", "trades", "last_block", "updated_at",
+				}),
+			}).Create(&candles).Error
+			if err != nil {
+				return err
+			}
+		}
+
+		state := models.CandleSyncState{TokenAddress: normalizeAddress(token), LastBlock: syncedBlock}
+		return tx.Clauses(clause.OnConflict{
+			Columns:   []clause.Column{{Name: "token_address"}},
+			DoUpdates: clause.AssignmentColumns([]string{"last_block", "updated_at"}),
+		}).Create(&state).Error
+	})
+}
+
+// GetCandles 返回开盘时间在[from, to]内的K线
+func (d *dbCandleStore) GetCandles(token, interval string, from, to time.Time) ([]*models.PriceCandle, error) {
+	var candles []*models.PriceCandle
+	err := d.db.Where("token_address = ? AND candle_interval = ? AND open_time >= ? AND open_time <= ?",
+		normalizeAddress(token), interval, from, to).
+		Order("open_time ASC").
+		Find(&candles).Error
+	return candles, err
+}
+
+// GetSyncedBlock 返回代币K线已聚合到的区块
+func (d *dbCandleStore) GetSyncedBlock(token string) (uint64, bool, error) {
+	var state models.CandleSyncState
+	err := d.db.Where("token_address = ?", normalizeAddress(token)).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return state.LastBlock, true, nil
+}
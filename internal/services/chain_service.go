@@ -3,6 +3,7 @@ package services
 import (
 	"context"
 	"crypto/ecdsa"
+	"errors"
 	"fmt"
 	"math/big"
 	"strings"
@@ -19,6 +20,12 @@ import (
 	"github.com/ethereum/go-ethereum/ethclient"
 )
 
+// MaxBalanceAddresses 单次批量查询余额的最大地址数
+const MaxBalanceAddresses = 100
+
+// ErrInvalidBalanceQuery 余额查询的地址为空、数量超过上限或格式无效
+var ErrInvalidBalanceQuery = errors.New("invalid balance query")
+
 // ChainService 链上交互服务
 type ChainService struct {
 	client     *ethclient.Client
@@ -27,6 +34,7 @@ type ChainService struct {
 	address    common.Address
 	chainID    *big.Int
 	gasLimit   uint64
+	calls      *multicaller // 合并只读调用和余额查询
 }
 
 // NewChainService 创建新的链上交互服务
@@ -62,23 +70,55 @@ func NewChainService(cfg *config.Config) *ChainService {
 		address:    address,
 		chainID:    chainID,
 		gasLimit:   cfg.Chain.GasLimit,
+		calls:      newMulticaller(rpcBatchClient{client}, cfg.Chain),
 	}
 }
 
 // GetBalance 获取地址余额
 func (s *ChainService) GetBalance(address string) (string, error) {
-	addr := common.HexToAddress(address)
-	balance, err := s.client.BalanceAt(context.Background(), addr, nil)
+	balances, err := s.GetBalances([]string{address})
+	if err != nil {
+		return "", err
+	}
+	return balances[0], nil
+}
+
+// GetBalances 批量获取地址余额（以太单位），结果与地址顺序一致
+// 已部署Multicall3时通过一次getEthBalance聚合调用查询，否则使用eth_getBalance批量请求
+func (s *ChainService) GetBalances(addresses []string) ([]string, error) {
+	if len(addresses) == 0 {
+		return nil, fmt.Errorf("%w: at least one address is required", ErrInvalidBalanceQuery)
+	}
+	if len(addresses) > MaxBalanceAddresses {
+		return nil, fmt.Errorf("%w: maximum %d addresses allowed per request", ErrInvalidBalanceQuery, MaxBalanceAddresses)
+	}
+
+	accounts := make([]common.Address, len(addresses))
+	for i, address := range addresses {
+		if !common.IsHexAddress(address) {
+			return nil, fmt.Errorf("%w: invalid address format: %s", ErrInvalidBalanceQuery, address)
+		}
+		accounts[i] = common.HexToAddress(address)
+	}
+
+	balances, err := s.calls.BalancesAt(context.Background(), accounts)
 	if err != nil {
-		return "", fmt.Errorf("failed to get balance: %w", err)
+		return nil, fmt.Errorf("failed to get balance: %w", err)
 	}
 
-	// 转换为以太单位
+	results := make([]string, len(balances))
+	for i, balance := range balances {
+		results[i] = formatEther(balance)
+	}
+	return results, nil
+}
+
+// formatEther 将wei转换为以太单位
+func formatEther(wei *big.Int) string {
 	balanceInEther := new(big.Float)
-	balanceInEther.SetString(balance.String())
+	balanceInEther.SetString(wei.String())
 	balanceInEther = balanceInEther.Quo(balanceInEther, big.NewFloat(1e18))
-
-	return balanceInEther.String(), nil
+	return balanceInEther.String()
 }
 
 // Transfer 转账
@@ -172,7 +212,7 @@ func (s *ChainService) CallContract(contractAddress, methodName string, params [
 		Data: callData,
 	}
 
-	result, err := s.client.CallContract(context.Background(), msg, nil)
+	result, err := s.calls.CallContract(context.Background(), msg, nil)
 	if err != nil {
 		return nil, fmt.Errorf("failed to call contract: %w", err)
 	}
@@ -0,0 +1,89 @@
+package services
+
+import (
+	"sort"
+	"sync"
+
+	"chain/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// CoinStore 行情数据源币种列表存储，用于符号解析
+type CoinStore interface {
+	// SaveCoins 保存币种，已存在的币种更新符号、名称、合约地址和市值排名
+	SaveCoins(coins []*models.Coin) error
+	// ListCoins 返回所有已保存的币种，按币种ID升序
+	ListCoins() ([]*models.Coin, error)
+}
+
+// memoryCoinStore 进程内的币种存储，服务重启后需要重新刷新
+type memoryCoinStore struct {
+	mu    sync.RWMutex
+	coins map[string]*models.Coin // 币种ID => 币种
+}
+
+// NewMemoryCoinStore 创建内存币种存储
+func NewMemoryCoinStore() CoinStore {
+	return &memoryCoinStore{coins: make(map[string]*models.Coin)}
+}
+
+// SaveCoins 保存币种，已存在的币种被更新
+func (m *memoryCoinStore) SaveCoins(coins []*models.Coin) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, coin := range coins {
+		stored := *coin
+		m.coins[stored.CoinID] = &stored
+	}
+	return nil
+}
+
+// ListCoins 返回所有已保存的币种
+func (m *memoryCoinStore) ListCoins() ([]*models.Coin, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	coins := make([]*models.Coin, 0, len(m.coins))
+	for _, coin := range m.coins {
+		copied := *coin
+		coins = append(coins, &copied)
+	}
+	sort.Slice(coins, func(i, j int) bool {
+		return coins[i].CoinID < coins[j].CoinID
+	})
+	return coins, nil
+}
+
+// dbCoinStore 基于数据库coins表的币种存储
+type dbCoinStore struct {
+	db *gorm.DB
+}
+
+// NewDBCoinStore 创建数据库币种存储，需要已迁移 models.Coin
+func NewDBCoinStore(db *gorm.DB) CoinStore {
+	return &dbCoinStore{db: db}
+}
+
+// coinBatchSize 批量写入币种的每批数量
+const coinBatchSize = 500
+
+// SaveCoins 保存币种，已存在的币种被更新
+func (d *dbCoinStore) SaveCoins(coins []*models.Coin) error {
+	if len(coins) == 0 {
+		return nil
+	}
+	return d.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "coin_id"}},
+		DoUpdates: clause.AssignmentColumns([]string{"symbol", "name", "bsc_address", "market_cap_rank", "updated_at"}),
+	}).CreateInBatches(&coins, coinBatchSize).Error
+}
+
+// ListCoins 返回所有已保存的币种
+func (d *dbCoinStore) ListCoins() ([]*models.Coin, error) {
+	var coins []*models.Coin
+	err := d.db.Order("coin_id ASC").Find(&coins).Error
+	return coins, err
+}
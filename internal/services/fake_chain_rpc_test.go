@@ -0,0 +1,66 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// BatchCallContext 模拟JSON-RPC批量请求，支持eth_call、eth_getStorageAt和eth_getBalance
+func (f *fakeChain) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["BatchCallContext"]++
+
+	for i := range b {
+		elem := &b[i]
+		switch elem.Method {
+		case "eth_call":
+			args := elem.Args[0].(map[string]interface{})
+			output, err := f.call(args["to"].(common.Address), args["data"].(hexutil.Bytes))
+			if err != nil {
+				elem.Error = err
+				continue
+			}
+			*elem.Result.(*hexutil.Bytes) = output
+		case "eth_getStorageAt":
+			*elem.Result.(*hexutil.Bytes) = f.storageAt(elem.Args[0].(common.Address), elem.Args[1].(common.Hash))
+		case "eth_getBalance":
+			*elem.Result.(*hexutil.Big) = hexutil.Big(*f.nativeBalance(elem.Args[0].(common.Address)))
+		default:
+			elem.Error = fmt.Errorf("method %s not supported", elem.Method)
+		}
+	}
+	return nil
+}
+
+// aggregate3 按Multicall3的语义依次执行调用，调用方需持有锁
+func (f *fakeChain) aggregate3(arg interface{}) []multicall3Result {
+	calls := *abi.ConvertType(arg, new([]multicall3Call)).(*[]multicall3Call)
+	results := make([]multicall3Result, len(calls))
+	for i, call := range calls {
+		output, err := f.call(call.Target, call.CallData)
+		results[i] = multicall3Result{Success: err == nil, ReturnData: revertData(output, err)}
+	}
+	return results
+}
+
+// nativeBalance 返回账户的原生代币余额，调用方需持有锁
+func (f *fakeChain) nativeBalance(account common.Address) *big.Int {
+	if balance := f.nativeBalances[account]; balance != nil {
+		return new(big.Int).Set(balance)
+	}
+	return new(big.Int)
+}
+
+// rpcRequests 返回发送到节点的请求数，JSON-RPC批量请求计为一次
+func (f *fakeChain) rpcRequests() int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls["CallContract"] + f.calls["BatchCallContext"] + f.calls["CodeAt"] + f.calls["StorageAt"]
+}
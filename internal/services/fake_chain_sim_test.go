@@ -0,0 +1,202 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+)
+
+// 模拟执行中交易和只读调用消耗的gas
+const (
+	fakeTxGas   = 100_000
+	fakeCallGas = 30_000
+)
+
+// CodeAt 返回合约字节码，未设置字节码的代币和交易对返回占位代码，其他地址没有代码
+func (f *fakeChain) CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["CodeAt"]++
+	if token := f.tokens[account]; token != nil && token.code != nil {
+		return token.code, nil
+	}
+	if f.tokens[account] != nil || f.pairs[account] != nil || f.v3Pools[account] != nil ||
+		(account == common.HexToAddress(Multicall3Address) && f.multicall) {
+		return []byte{byte(vm.STOP)}, nil
+	}
+	return nil, nil
+}
+
+// StorageAt 返回代币合约的存储，未设置的槽为0
+func (f *fakeChain) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["StorageAt"]++
+	return f.storageAt(account, key), nil
+}
+
+// storageAt 读取合约存储，调用方需持有锁
+func (f *fakeChain) storageAt(account common.Address, key common.Hash) []byte {
+	var value common.Hash
+	if token := f.tokens[account]; token != nil {
+		value = token.storage[key]
+	}
+	return value.Bytes()
+}
+
+// CallContractWithOverrides 按模拟合约的语义执行调用列表，执行完成后恢复全部状态
+// 只支持在被调用地址部署simulatorCode的状态覆盖
+func (f *fakeChain) CallContractWithOverrides(ctx context.Context, call ethereum.CallMsg, overrides map[common.Address]overrideAccount) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["CallContractWithOverrides"]++
+
+	if call.To == nil || !bytes.Equal(overrides[*call.To].Code, simulatorCode) {
+		return nil, fmt.Errorf("unsupported state override")
+	}
+	sender := *call.To
+	calls, err := decodeSimCalls(call.Data)
+	if err != nil {
+		return nil, err
+	}
+
+	defer f.restore(f.snapshot())
+
+	var output []byte
+	for _, c := range calls {
+		success, gasUsed, data := f.simCall(sender, c)
+		output = append(output, common.LeftPadBytes(boolBytes(success), 32)...)
+		output = append(output, common.BigToHash(new(big.Int).SetUint64(gasUsed)).Bytes()...)
+		output = append(output, common.BigToHash(big.NewInt(int64(len(data)))).Bytes()...)
+		output = append(output, data...)
+	}
+	return output, nil
+}
+
+// simCall 以部署模拟合约的sender为调用方执行单个调用，失败时返回Error(string)编码的回滚数据
+func (f *fakeChain) simCall(sender common.Address, c simCall) (bool, uint64, []byte) {
+	tx := types.NewTx(&types.LegacyTx{To: &c.target, Value: c.value, Data: c.data})
+
+	var err error
+	var output []byte
+	swapABI := f.abis["swap"]
+	erc20 := f.abis["erc20"]
+	router := c.target == common.HexToAddress(PancakeSwapV2Router)
+	if method, e := swapABI.MethodById(c.data[:4]); router && e == nil && strings.HasPrefix(method.Name, "swap") {
+		_, err = f.executeSwap(sender, tx)
+		if err == nil {
+			output = common.LeftPadBytes([]byte{1}, 32)
+		}
+		return err == nil, fakeTxGas, revertData(output, err)
+	}
+	if method, e := erc20.MethodById(c.data[:4]); f.tokens[c.target] != nil && e == nil && !method.IsConstant() {
+		_, err = f.execute(sender, tx)
+		if err == nil {
+			output = common.LeftPadBytes([]byte{1}, 32)
+		}
+		return err == nil, fakeTxGas, revertData(output, err)
+	}
+
+	output, err = f.call(c.target, c.data)
+	return err == nil, fakeCallGas, revertData(output, err)
+}
+
+// fakeSnapshot 模拟执行前的代币和交易对状态
+type fakeSnapshot struct {
+	balances   map[common.Address]map[common.Address]*big.Int
+	allowances map[common.Address]map[common.Address]map[common.Address]*big.Int
+	reserves   map[common.Address][2]*big.Int
+}
+
+// snapshot 保存代币余额、授权额度和交易对储备量
+func (f *fakeChain) snapshot() *fakeSnapshot {
+	snap := &fakeSnapshot{
+		balances:   make(map[common.Address]map[common.Address]*big.Int),
+		allowances: make(map[common.Address]map[common.Address]map[common.Address]*big.Int),
+		reserves:   make(map[common.Address][2]*big.Int),
+	}
+	for addr, token := range f.tokens {
+		balances := make(map[common.Address]*big.Int)
+		for owner, balance := range token.balances {
+			balances[owner] = balance
+		}
+		snap.balances[addr] = balances
+
+		allowances := make(map[common.Address]map[common.Address]*big.Int)
+		for owner, spenders := range token.allowances {
+			allowances[owner] = make(map[common.Address]*big.Int)
+			for spender, amount := range spenders {
+				allowances[owner][spender] = amount
+			}
+		}
+		snap.allowances[addr] = allowances
+	}
+	for addr, pair := range f.pairs {
+		snap.reserves[addr] = [2]*big.Int{pair.reserve0, pair.reserve1}
+	}
+	return snap
+}
+
+// restore 恢复快照中的状态，余额和储备量在修改时总是替换为新对象，因此浅拷贝即可
+// 快照之后添加的代币和交易对保持不变
+func (f *fakeChain) restore(snap *fakeSnapshot) {
+	for addr, token := range f.tokens {
+		if balances, ok := snap.balances[addr]; ok {
+			token.balances = balances
+			token.allowances = snap.allowances[addr]
+		}
+	}
+	for addr, pair := range f.pairs {
+		if reserves, ok := snap.reserves[addr]; ok {
+			pair.reserve0, pair.reserve1 = reserves[0], reserves[1]
+		}
+	}
+}
+
+// decodeSimCalls 解析模拟合约的调用数据
+func decodeSimCalls(data []byte) ([]simCall, error) {
+	var calls []simCall
+	for pos := 0; pos < len(data); {
+		if pos+84 > len(data) {
+			return nil, fmt.Errorf("truncated simulation call")
+		}
+		length := int(new(big.Int).SetBytes(data[pos+52 : pos+84]).Int64())
+		if pos+84+length > len(data) {
+			return nil, fmt.Errorf("truncated simulation call")
+		}
+		calls = append(calls, simCall{
+			target: common.BytesToAddress(data[pos : pos+20]),
+			value:  new(big.Int).SetBytes(data[pos+20 : pos+52]),
+			data:   data[pos+84 : pos+84+length],
+		})
+		pos += 84 + length
+	}
+	return calls, nil
+}
+
+// revertData 执行失败时将错误信息编码为Error(string)
+func revertData(output []byte, err error) []byte {
+	if err == nil {
+		return output
+	}
+	stringType, _ := abi.NewType("string", "", nil)
+	reason := strings.TrimPrefix(strings.TrimPrefix(err.Error(), "execution reverted"), ": ")
+	encoded, _ := abi.Arguments{{Type: stringType}}.Pack(reason)
+	return append(crypto.Keccak256([]byte("Error(string)"))[:4], encoded...)
+}
+
+func boolBytes(b bool) []byte {
+	if b {
+		return []byte{1}
+	}
+	return []byte{0}
+}
@@ -0,0 +1,517 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"math/big"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// fakeToken 模拟的ERC20代币
+type fakeToken struct {
+	name     string
+	symbol   string
+	decimals uint8
+	balances map[common.Address]*big.Int
+
+	allowances     map[common.Address]map[common.Address]*big.Int // owner => spender => 额度
+	transferFeeBps int64                                          // 转账扣费（基点），用于模拟转账扣费代币
+
+	// 风险分析相关属性
+	code            []byte          // 合约字节码
+	sellBlocked     bool            // 禁止卖出，用于模拟貔貅代币
+	owner           *common.Address // 合约所有者，为nil时不支持owner()
+	maxTxAmount     *big.Int        // 单笔交易上限，为nil时不支持查询
+	maxWalletAmount *big.Int        // 钱包持仓上限，为nil时不支持查询
+
+	// 非标准元数据
+	bytes32Metadata bool                        // 以bytes32返回名称和符号
+	noDecimals      bool                        // 不支持decimals()
+	storage         map[common.Hash]common.Hash // 合约存储，用于模拟代理合约
+}
+
+// fakePair 模拟的PancakeSwap V2交易对
+type fakePair struct {
+	token0   common.Address
+	token1   common.Address
+	reserve0 *big.Int
+	reserve1 *big.Int
+
+	totalSupply *big.Int // LP代币总量
+
+	// 累计价格预言机
+	price0Cumulative *big.Int
+	price1Cumulative *big.Int
+	timestampLast    uint32
+}
+
+// fakeV3Pool 模拟的V3集中流动性池
+type fakeV3Pool struct {
+	factory      common.Address
+	token0       common.Address
+	token1       common.Address
+	fee          uint32
+	sqrtPriceX96 *big.Int
+	liquidity    *big.Int
+
+	oracleHistory uint32 // 预言机可观测的历史秒数，更早的observe调用回滚
+}
+
+// fakeFeed 模拟的Chainlink价格源
+type fakeFeed struct {
+	decimals        uint8
+	answer          *big.Int
+	roundID         *big.Int
+	answeredInRound *big.Int
+	updatedAt       uint64
+}
+
+// fakeChain 在内存中模拟BSC上的代币、交易对和PancakeSwap合约
+type fakeChain struct {
+	mu      sync.Mutex
+	tokens  map[common.Address]*fakeToken
+	pairs   map[common.Address]*fakePair
+	v3Pools map[common.Address]*fakeV3Pool
+	beacons map[common.Address]common.Address // 信标合约 => 实现合约
+	feeds   map[common.Address]*fakeFeed
+	calls   map[string]int // 按 "地址:方法" 统计调用次数
+	abis    map[string]abi.ABI
+
+	// 交易执行状态
+	nonces   map[common.Address]uint64
+	receipts map[common.Hash]*types.Receipt
+	sent     []*types.Transaction
+	pending  bool                     // 已发送的交易不返回收据，模拟交易未被打包
+	blocks   map[uint64]*fakeSnapshot // 交易所在区块号 => 区块执行完成后的状态，供指定区块的只读调用使用
+	onSend   func()                   // 每笔交易执行后调用，调用时持有锁，模拟之后区块中其他账户的交易
+
+	// 区块和事件日志
+	head      uint64 // 最新区块号
+	headTime  uint64 // 最新区块时间戳
+	blockTime uint64 // 出块间隔（秒）
+	logs      []types.Log
+	pairCount int // 已创建的交易对数量，用于生成PairCreated事件
+
+	// 批量读取
+	multicall      bool                        // 是否部署了Multicall3
+	nativeBalances map[common.Address]*big.Int // 原生代币余额
+}
+
+func newFakeChain() *fakeChain {
+	f := &fakeChain{
+		tokens:  make(map[common.Address]*fakeToken),
+		pairs:   make(map[common.Address]*fakePair),
+		v3Pools: make(map[common.Address]*fakeV3Pool),
+		beacons: make(map[common.Address]common.Address),
+		feeds:   make(map[common.Address]*fakeFeed),
+		calls:   make(map[string]int),
+		abis:    make(map[string]abi.ABI),
+
+		nonces:   make(map[common.Address]uint64),
+		receipts: make(map[common.Hash]*types.Receipt),
+		blocks:   make(map[uint64]*fakeSnapshot),
+
+		head:      1_000_000,
+		headTime:  uint64(time.Now().Unix()),
+		blockTime: 3,
+
+		multicall:      true,
+		nativeBalances: make(map[common.Address]*big.Int),
+	}
+	for name, def := range map[string]string{
+		"erc20":     erc20ABI,
+		"router":    pancakeRouterABI,
+		"swap":      pancakeRouterSwapABI,
+		"factory":   pancakeFactoryABI,
+		"pair":      pairABI,
+		"v3factory": v3FactoryABI,
+		"v3pool":    v3PoolABI,
+		"v3quoter":  v3QuoterV2ABI,
+		"risk":      tokenRiskABI,
+		"beacon":    beaconABI,
+		"multicall": multicall3ABI,
+		"feed":      aggregatorV3ABI,
+	} {
+		parsed, err := abi.JSON(strings.NewReader(def))
+		if err != nil {
+			panic(err)
+		}
+		f.abis[name] = parsed
+	}
+	return f
+}
+
+// addToken 注册一个模拟代币
+func (f *fakeChain) addToken(address, name, symbol string, decimals uint8) common.Address {
+	addr := common.HexToAddress(address)
+	f.tokens[addr] = &fakeToken{
+		name:     name,
+		symbol:   symbol,
+		decimals: decimals,
+		balances: make(map[common.Address]*big.Int),
+
+		allowances: make(map[common.Address]map[common.Address]*big.Int),
+	}
+	return addr
+}
+
+// addPair 注册一个模拟交易对，储备量以完整代币数量给出
+func (f *fakeChain) addPair(tokenA, tokenB common.Address, amountA, amountB int64) common.Address {
+	token0, token1 := tokenA, tokenB
+	reserve0 := new(big.Int).Mul(big.NewInt(amountA), pow10(f.tokens[tokenA].decimals))
+	reserve1 := new(big.Int).Mul(big.NewInt(amountB), pow10(f.tokens[tokenB].decimals))
+	if strings.ToLower(token0.Hex()) > strings.ToLower(token1.Hex()) {
+		token0, token1 = token1, token0
+		reserve0, reserve1 = reserve1, reserve0
+	}
+
+	addr := common.BigToAddress(big.NewInt(int64(0x1000 + len(f.pairs))))
+	// 与首次添加流动性时一致，LP代币总量为 sqrt(reserve0 * reserve1)
+	totalSupply := new(big.Int).Sqrt(new(big.Int).Mul(reserve0, reserve1))
+	f.pairs[addr] = &fakePair{
+		token0:           token0,
+		token1:           token1,
+		reserve0:         reserve0,
+		reserve1:         reserve1,
+		totalSupply:      totalSupply,
+		price0Cumulative: big.NewInt(0),
+		price1Cumulative: big.NewInt(0),
+		timestampLast:    uint32(f.headTime),
+	}
+	return addr
+}
+
+// syncPair 与交易对的_update相同，按旧储备量累加到最新区块的累计价格后设置新的储备量
+func (f *fakeChain) syncPair(addr common.Address, reserve0, reserve1 *big.Int) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	pair := f.pairs[addr]
+	elapsed := big.NewInt(int64(uint32(f.headTime) - pair.timestampLast))
+	price0 := new(big.Int).Quo(new(big.Int).Lsh(pair.reserve1, 112), pair.reserve0)
+	price1 := new(big.Int).Quo(new(big.Int).Lsh(pair.reserve0, 112), pair.reserve1)
+	pair.price0Cumulative = new(big.Int).Add(pair.price0Cumulative, price0.Mul(price0, elapsed))
+	pair.price1Cumulative = new(big.Int).Add(pair.price1Cumulative, price1.Mul(price1, elapsed))
+	pair.timestampLast = uint32(f.headTime)
+	pair.reserve0, pair.reserve1 = reserve0, reserve1
+}
+
+// addV3Pool 注册一个模拟V3池，以完整代币数量给出的储备量推算价格和流动性
+func (f *fakeChain) addV3Pool(factory string, tokenA, tokenB common.Address, fee uint32, amountA, amountB int64) common.Address {
+	token0, token1 := tokenA, tokenB
+	reserve0 := new(big.Int).Mul(big.NewInt(amountA), pow10(f.tokens[tokenA].decimals))
+	reserve1 := new(big.Int).Mul(big.NewInt(amountB), pow10(f.tokens[tokenB].decimals))
+	if strings.ToLower(token0.Hex()) > strings.ToLower(token1.Hex()) {
+		token0, token1 = token1, token0
+		reserve0, reserve1 = reserve1, reserve0
+	}
+
+	// sqrtPriceX96 = sqrt(reserve1 / reserve0) * 2^96，L = sqrt(reserve0 * reserve1)
+	sqrtPriceX96 := new(big.Int).Lsh(reserve1, 192)
+	sqrtPriceX96.Quo(sqrtPriceX96, reserve0).Sqrt(sqrtPriceX96)
+	liquidity := new(big.Int).Mul(reserve0, reserve1)
+	liquidity.Sqrt(liquidity)
+
+	addr := common.BigToAddress(big.NewInt(int64(0x2000 + len(f.v3Pools))))
+	f.v3Pools[addr] = &fakeV3Pool{
+		factory:      common.HexToAddress(factory),
+		token0:       token0,
+		token1:       token1,
+		fee:          fee,
+		sqrtPriceX96: sqrtPriceX96,
+		liquidity:    liquidity,
+
+		oracleHistory: 24 * 3600,
+	}
+	f.tokens[token0].balances[addr] = reserve0
+	f.tokens[token1].balances[addr] = reserve1
+	return addr
+}
+
+// addFeed 在address注册一个8位精度的模拟价格源，价格以美分给出，更新时间为最新区块时间
+func (f *fakeChain) addFeed(address string, cents int64) common.Address {
+	addr := common.HexToAddress(address)
+	f.feeds[addr] = &fakeFeed{
+		decimals:        8,
+		answer:          big.NewInt(cents * 1_000_000),
+		roundID:         big.NewInt(100),
+		answeredInRound: big.NewInt(100),
+		updatedAt:       f.headTime,
+	}
+	return addr
+}
+
+// findV3Pool 查找指定工厂、代币和手续费等级的V3池
+func (f *fakeChain) findV3Pool(factory, tokenA, tokenB common.Address, fee uint32) (common.Address, *fakeV3Pool) {
+	for addr, pool := range f.v3Pools {
+		if pool.factory != factory || pool.fee != fee {
+			continue
+		}
+		if (pool.token0 == tokenA && pool.token1 == tokenB) || (pool.token0 == tokenB && pool.token1 == tokenA) {
+			return addr, pool
+		}
+	}
+	return common.Address{}, nil
+}
+
+// tick 当前价格所在的tick
+func (p *fakeV3Pool) tick() int64 {
+	sqrtPrice, _ := new(big.Rat).SetFrac(p.sqrtPriceX96, new(big.Int).Lsh(big.NewInt(1), 96)).Float64()
+	return int64(math.Floor(math.Log(sqrtPrice*sqrtPrice) / math.Log(1.0001)))
+}
+
+// quote 以池子当前价格附近的虚拟储备量近似计算兑换输出
+func (p *fakeV3Pool) quote(tokenIn common.Address, amountIn *big.Int) *big.Int {
+	q96 := new(big.Int).Lsh(big.NewInt(1), 96)
+	virtual0 := new(big.Int).Mul(p.liquidity, q96)
+	virtual0.Quo(virtual0, p.sqrtPriceX96)
+	virtual1 := new(big.Int).Mul(p.liquidity, p.sqrtPriceX96)
+	virtual1.Quo(virtual1, q96)
+
+	amountInAfterFee := new(big.Int).Mul(amountIn, big.NewInt(int64(1_000_000-p.fee)))
+	amountInAfterFee.Quo(amountInAfterFee, big.NewInt(1_000_000))
+
+	reserveIn, reserveOut := virtual0, virtual1
+	if tokenIn != p.token0 {
+		reserveIn, reserveOut = virtual1, virtual0
+	}
+	numerator := new(big.Int).Mul(amountInAfterFee, reserveOut)
+	return numerator.Quo(numerator, new(big.Int).Add(reserveIn, amountInAfterFee))
+}
+
+// findPair 查找两个代币之间的交易对
+func (f *fakeChain) findPair(tokenA, tokenB common.Address) (common.Address, *fakePair) {
+	for addr, pair := range f.pairs {
+		if (pair.token0 == tokenA && pair.token1 == tokenB) || (pair.token0 == tokenB && pair.token1 == tokenA) {
+			return addr, pair
+		}
+	}
+	return common.Address{}, nil
+}
+
+// callCount 返回指定合约方法被调用的次数
+func (f *fakeChain) callCount(address common.Address, method string) int {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.calls[address.Hex()+":"+method]
+}
+
+// v2AmountOut PancakeSwap V2 恒定乘积公式（0.25%手续费）
+func v2AmountOut(amountIn, reserveIn, reserveOut *big.Int) *big.Int {
+	amountInWithFee := new(big.Int).Mul(amountIn, big.NewInt(9975))
+	numerator := new(big.Int).Mul(amountInWithFee, reserveOut)
+	denominator := new(big.Int).Add(new(big.Int).Mul(reserveIn, big.NewInt(10000)), amountInWithFee)
+	return numerator.Quo(numerator, denominator)
+}
+
+// CallContract 实现 ethereum.ContractCaller
+func (f *fakeChain) CallContract(ctx context.Context, call ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.calls["CallContract"]++
+	if call.To == nil {
+		return nil, fmt.Errorf("invalid call")
+	}
+	if blockNumber != nil {
+		if snap := f.blocks[blockNumber.Uint64()]; snap != nil {
+			defer f.restore(f.snapshot())
+			f.restore(snap)
+		}
+	}
+	return f.call(*call.To, call.Data)
+}
+
+// call 执行只读调用，调用方需持有锁
+func (f *fakeChain) call(to common.Address, data []byte) ([]byte, error) {
+	if len(data) < 4 {
+		return nil, fmt.Errorf("invalid call")
+	}
+
+	var kind string
+	switch {
+	case to == common.HexToAddress(Multicall3Address) && f.multicall:
+		kind = "multicall"
+	case to == common.HexToAddress(PancakeSwapV2Router):
+		kind = "router"
+	case to == common.HexToAddress(PancakeSwapV2Factory):
+		kind = "factory"
+	case to == common.HexToAddress(PancakeSwapV3Factory) || to == common.HexToAddress(UniswapV3Factory):
+		kind = "v3factory"
+	case to == common.HexToAddress(PancakeSwapV3QuoterV2) || to == common.HexToAddress(UniswapV3QuoterV2):
+		kind = "v3quoter"
+	case f.pairs[to] != nil:
+		kind = "pair"
+	case f.v3Pools[to] != nil:
+		kind = "v3pool"
+	case f.tokens[to] != nil:
+		kind = "erc20"
+	case f.beacons[to] != (common.Address{}):
+		kind = "beacon"
+	case f.feeds[to] != nil:
+		kind = "feed"
+	default:
+		// 没有代码的地址返回空数据
+		return nil, nil
+	}
+
+	contractABI := f.abis[kind]
+	method, err := contractABI.MethodById(data[:4])
+	if err != nil && kind == "router" {
+		swapABI := f.abis["swap"]
+		method, err = swapABI.MethodById(data[:4])
+	}
+	if err != nil && kind == "erc20" {
+		riskABI := f.abis["risk"]
+		method, err = riskABI.MethodById(data[:4])
+	}
+	if err != nil && kind == "pair" {
+		// 交易对本身也是LP代币
+		lpABI := f.abis["erc20"]
+		method, err = lpABI.MethodById(data[:4])
+	}
+	if err != nil {
+		return nil, fmt.Errorf("execution reverted")
+	}
+	args, err := method.Inputs.Unpack(data[4:])
+	if err != nil {
+		return nil, err
+	}
+	f.calls[to.Hex()+":"+method.Name]++
+
+	outputs, err := f.dispatch(kind, to, method.Name, args)
+	if err != nil {
+		return nil, err
+	}
+	if kind == "erc20" && f.tokens[to].bytes32Metadata && (method.Name == "name" || method.Name == "symbol") {
+		return common.RightPadBytes([]byte(outputs[0].(string)), 32), nil
+	}
+	return method.Outputs.Pack(outputs...)
+}
+
+// dispatch 按合约类型和方法名计算返回值
+func (f *fakeChain) dispatch(kind string, to common.Address, method string, args []interface{}) ([]interface{}, error) {
+	switch kind + "." + method {
+	case "erc20.name":
+		return []interface{}{f.tokens[to].name}, nil
+	case "erc20.symbol":
+		return []interface{}{f.tokens[to].symbol}, nil
+	case "erc20.decimals":
+		if !f.tokens[to].noDecimals {
+			return []interface{}{f.tokens[to].decimals}, nil
+		}
+	case "erc20.totalSupply":
+		total := new(big.Int)
+		for _, balance := range f.tokens[to].balances {
+			total.Add(total, balance)
+		}
+		return []interface{}{total}, nil
+	case "erc20.balanceOf":
+		balance := f.tokens[to].balances[args[0].(common.Address)]
+		if balance == nil {
+			balance = big.NewInt(0)
+		}
+		return []interface{}{balance}, nil
+	case "erc20.allowance":
+		return []interface{}{f.tokens[to].allowance(args[0].(common.Address), args[1].(common.Address))}, nil
+	case "erc20.owner":
+		if owner := f.tokens[to].owner; owner != nil {
+			return []interface{}{*owner}, nil
+		}
+	case "erc20._maxTxAmount":
+		if amount := f.tokens[to].maxTxAmount; amount != nil {
+			return []interface{}{amount}, nil
+		}
+	case "erc20._maxWalletSize":
+		if amount := f.tokens[to].maxWalletAmount; amount != nil {
+			return []interface{}{amount}, nil
+		}
+	case "factory.getPair":
+		addr, _ := f.findPair(args[0].(common.Address), args[1].(common.Address))
+		return []interface{}{addr}, nil
+	case "pair.getReserves":
+		pair := f.pairs[to]
+		return []interface{}{pair.reserve0, pair.reserve1, pair.timestampLast}, nil
+	case "pair.token0":
+		return []interface{}{f.pairs[to].token0}, nil
+	case "pair.token1":
+		return []interface{}{f.pairs[to].token1}, nil
+	case "pair.totalSupply":
+		return []interface{}{f.pairs[to].totalSupply}, nil
+	case "pair.price0CumulativeLast":
+		return []interface{}{f.pairs[to].price0Cumulative}, nil
+	case "pair.price1CumulativeLast":
+		return []interface{}{f.pairs[to].price1Cumulative}, nil
+	case "router.getAmountsOut":
+		amounts, err := f.amountsOut(args[0].(*big.Int), args[1].([]common.Address))
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{amounts}, nil
+	case "router.getAmountsIn":
+		amounts, err := f.amountsIn(args[0].(*big.Int), args[1].([]common.Address))
+		if err != nil {
+			return nil, err
+		}
+		return []interface{}{amounts}, nil
+	case "v3factory.getPool":
+		addr, _ := f.findV3Pool(to, args[0].(common.Address), args[1].(common.Address), uint32(args[2].(*big.Int).Uint64()))
+		return []interface{}{addr}, nil
+	case "v3pool.slot0":
+		pool := f.v3Pools[to]
+		return []interface{}{pool.sqrtPriceX96, big.NewInt(0), uint16(0), uint16(1), uint16(1), uint32(0), true}, nil
+	case "v3pool.observe":
+		// 价格在预言机历史内不变，累计tick = tick * 时间
+		pool := f.v3Pools[to]
+		var tickCumulatives, secondsPerLiquidity []*big.Int
+		for _, secondsAgo := range args[0].([]uint32) {
+			if secondsAgo > pool.oracleHistory {
+				return nil, fmt.Errorf("execution reverted: OLD")
+			}
+			tickCumulatives = append(tickCumulatives, big.NewInt(pool.tick()*int64(f.headTime-uint64(secondsAgo))))
+			secondsPerLiquidity = append(secondsPerLiquidity, big.NewInt(0))
+		}
+		return []interface{}{tickCumulatives, secondsPerLiquidity}, nil
+	case "v3pool.liquidity":
+		return []interface{}{f.v3Pools[to].liquidity}, nil
+	case "v3pool.token0":
+		return []interface{}{f.v3Pools[to].token0}, nil
+	case "v3pool.token1":
+		return []interface{}{f.v3Pools[to].token1}, nil
+	case "v3quoter.quoteExactInputSingle":
+		params := *abi.ConvertType(args[0], new(quoteExactInputSingleParams)).(*quoteExactInputSingleParams)
+		factory := common.HexToAddress(PancakeSwapV3Factory)
+		if to == common.HexToAddress(UniswapV3QuoterV2) {
+			factory = common.HexToAddress(UniswapV3Factory)
+		}
+		_, pool := f.findV3Pool(factory, params.TokenIn, params.TokenOut, uint32(params.Fee.Uint64()))
+		if pool == nil {
+			return nil, fmt.Errorf("execution reverted")
+		}
+		amountOut := pool.quote(params.TokenIn, params.AmountIn)
+		return []interface{}{amountOut, pool.sqrtPriceX96, uint32(0), big.NewInt(100000)}, nil
+	case "multicall.aggregate3":
+		return []interface{}{f.aggregate3(args[0])}, nil
+	case "multicall.getEthBalance":
+		return []interface{}{f.nativeBalance(args[0].(common.Address))}, nil
+	case "feed.decimals":
+		return []interface{}{f.feeds[to].decimals}, nil
+	case "feed.latestRoundData":
+		feed := f.feeds[to]
+		updatedAt := new(big.Int).SetUint64(feed.updatedAt)
+		return []interface{}{feed.roundID, feed.answer, updatedAt, updatedAt, feed.answeredInRound}, nil
+	case "beacon.implementation":
+		return []interface{}{f.beacons[to]}, nil
+	case "router.WETH":
+		return []interface{}{common.HexToAddress(WBNBAddress)}, nil
+	}
+	return nil, fmt.Errorf("execution reverted")
+}
@@ -0,0 +1,460 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"strings"
+	"time"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+)
+
+// allowance 返回owner授权给spender的额度
+func (t *fakeToken) allowance(owner, spender common.Address) *big.Int {
+	if amount := t.allowances[owner][spender]; amount != nil {
+		return amount
+	}
+	return big.NewInt(0)
+}
+
+// balanceOf 返回地址的代币余额
+func (t *fakeToken) balanceOf(owner common.Address) *big.Int {
+	if balance := t.balances[owner]; balance != nil {
+		return balance
+	}
+	return big.NewInt(0)
+}
+
+// transfer 转账，接收方到账数量扣除转账费
+func (t *fakeToken) transfer(from, to common.Address, amount *big.Int) error {
+	if t.balanceOf(from).Cmp(amount) < 0 {
+		return fmt.Errorf("execution reverted: transfer amount exceeds balance")
+	}
+	t.balances[from] = new(big.Int).Sub(t.balanceOf(from), amount)
+	t.balances[to] = new(big.Int).Add(t.balanceOf(to), t.afterFee(amount))
+	return nil
+}
+
+// afterFee 返回扣除转账费后的数量
+func (t *fakeToken) afterFee(amount *big.Int) *big.Int {
+	fee := new(big.Int).Mul(amount, big.NewInt(t.transferFeeBps))
+	return fee.Sub(amount, fee.Quo(fee, big.NewInt(10000)))
+}
+
+// mint 为地址增发以完整代币数量给出的余额
+func (f *fakeChain) mint(token, to common.Address, amount int64) {
+	t := f.tokens[token]
+	minted := new(big.Int).Mul(big.NewInt(amount), pow10(t.decimals))
+	t.balances[to] = new(big.Int).Add(t.balanceOf(to), minted)
+}
+
+// pairReserves 返回交易对按兑换方向排列的储备量
+func (f *fakeChain) pairReserves(tokenIn, tokenOut common.Address) (common.Address, *fakePair, *big.Int, *big.Int, error) {
+	addr, pair := f.findPair(tokenIn, tokenOut)
+	if pair == nil {
+		return common.Address{}, nil, nil, nil, fmt.Errorf("execution reverted")
+	}
+	if pair.token0 == tokenIn {
+		return addr, pair, pair.reserve0, pair.reserve1, nil
+	}
+	return addr, pair, pair.reserve1, pair.reserve0, nil
+}
+
+// amountsOut 按V2公式计算路径上每一跳的输出数量
+func (f *fakeChain) amountsOut(amountIn *big.Int, path []common.Address) ([]*big.Int, error) {
+	amounts := []*big.Int{amountIn}
+	for i := 0; i+1 < len(path); i++ {
+		_, _, reserveIn, reserveOut, err := f.pairReserves(path[i], path[i+1])
+		if err != nil {
+			return nil, err
+		}
+		amounts = append(amounts, v2AmountOut(amounts[i], reserveIn, reserveOut))
+	}
+	return amounts, nil
+}
+
+// amountsIn 按V2公式反向计算路径上每一跳所需的输入数量
+func (f *fakeChain) amountsIn(amountOut *big.Int, path []common.Address) ([]*big.Int, error) {
+	amounts := make([]*big.Int, len(path))
+	amounts[len(path)-1] = amountOut
+	for i := len(path) - 1; i > 0; i-- {
+		_, _, reserveIn, reserveOut, err := f.pairReserves(path[i-1], path[i])
+		if err != nil {
+			return nil, err
+		}
+		if amounts[i].Cmp(reserveOut) >= 0 {
+			return nil, fmt.Errorf("execution reverted: INSUFFICIENT_LIQUIDITY")
+		}
+		amounts[i-1] = v2AmountIn(amounts[i], reserveIn, reserveOut)
+	}
+	return amounts, nil
+}
+
+// v2AmountIn PancakeSwap V2 获得指定输出所需的输入数量（0.25%手续费）
+func v2AmountIn(amountOut, reserveIn, reserveOut *big.Int) *big.Int {
+	numerator := new(big.Int).Mul(reserveIn, amountOut)
+	numerator.Mul(numerator, big.NewInt(10000))
+	denominator := new(big.Int).Sub(reserveOut, amountOut)
+	denominator.Mul(denominator, big.NewInt(9975))
+	return numerator.Quo(numerator, denominator).Add(numerator, big.NewInt(1))
+}
+
+// SuggestGasPrice 实现 ethereum.GasPricer
+func (f *fakeChain) SuggestGasPrice(ctx context.Context) (*big.Int, error) {
+	return big.NewInt(3_000_000_000), nil
+}
+
+// EstimateGas 实现 ethereum.GasEstimator
+func (f *fakeChain) EstimateGas(ctx context.Context, call ethereum.CallMsg) (uint64, error) {
+	return 150_000, nil
+}
+
+// PendingNonceAt 返回账户下一笔交易的nonce
+func (f *fakeChain) PendingNonceAt(ctx context.Context, account common.Address) (uint64, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return f.nonces[account], nil
+}
+
+// TransactionReceipt 返回已执行交易的收据
+func (f *fakeChain) TransactionReceipt(ctx context.Context, txHash common.Hash) (*types.Receipt, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	if receipt, ok := f.receipts[txHash]; ok && !f.pending {
+		return receipt, nil
+	}
+	return nil, ethereum.NotFound
+}
+
+// SendTransaction 立即执行交易并生成收据，执行失败的交易状态为回滚
+func (f *fakeChain) SendTransaction(ctx context.Context, tx *types.Transaction) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	from, err := types.Sender(types.NewEIP155Signer(tx.ChainId()), tx)
+	if err != nil {
+		return err
+	}
+	if tx.Nonce() != f.nonces[from] {
+		return fmt.Errorf("invalid nonce %d for %s", tx.Nonce(), from.Hex())
+	}
+	receipt := &types.Receipt{
+		Status:            types.ReceiptStatusSuccessful,
+		TxHash:            tx.Hash(),
+		GasUsed:           100_000,
+		EffectiveGasPrice: tx.GasPrice(),
+	}
+	fee := new(big.Int).Mul(tx.GasPrice(), new(big.Int).SetUint64(receipt.GasUsed))
+	if f.nativeBalance(from).Cmp(new(big.Int).Add(fee, tx.Value())) < 0 {
+		return fmt.Errorf("insufficient funds for gas * price + value")
+	}
+	f.nonces[from]++
+	f.sent = append(f.sent, tx)
+	receipt.BlockNumber = big.NewInt(int64(len(f.sent)))
+
+	// 无论执行是否成功都扣除gas费用，成功时转出交易携带的BNB
+	f.nativeBalances[from] = new(big.Int).Sub(f.nativeBalance(from), fee)
+	f.blocks[receipt.BlockNumber.Uint64()-1] = f.snapshot()
+	logs, err := f.execute(from, tx)
+	if err != nil {
+		receipt.Status = types.ReceiptStatusFailed
+	} else {
+		receipt.Logs = logs
+		f.nativeBalances[from] = new(big.Int).Sub(f.nativeBalance(from), tx.Value())
+	}
+	f.receipts[tx.Hash()] = receipt
+	f.blocks[receipt.BlockNumber.Uint64()] = f.snapshot()
+	if f.onSend != nil {
+		f.onSend()
+	}
+	return nil
+}
+
+// sentMethods 返回已发送交易调用的方法名
+func (f *fakeChain) sentMethods() []string {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	var methods []string
+	for _, tx := range f.sent {
+		for _, kind := range []string{"erc20", "swap"} {
+			contractABI := f.abis[kind]
+			if method, err := contractABI.MethodById(tx.Data()[:4]); err == nil {
+				methods = append(methods, method.Name)
+				break
+			}
+		}
+	}
+	return methods
+}
+
+// execute 执行交易，失败时不修改任何状态
+func (f *fakeChain) execute(from common.Address, tx *types.Transaction) ([]*types.Log, error) {
+	to := *tx.To()
+	if token := f.tokens[to]; token != nil {
+		erc20 := f.abis["erc20"]
+		method, err := erc20.MethodById(tx.Data()[:4])
+		if err != nil || (method.Name != "approve" && method.Name != "transfer") {
+			return nil, fmt.Errorf("execution reverted")
+		}
+		args, err := method.Inputs.Unpack(tx.Data()[4:])
+		if err != nil {
+			return nil, err
+		}
+		if method.Name == "transfer" {
+			return nil, token.transfer(from, args[0].(common.Address), args[1].(*big.Int))
+		}
+		if token.allowances[from] == nil {
+			token.allowances[from] = make(map[common.Address]*big.Int)
+		}
+		token.allowances[from][args[0].(common.Address)] = args[1].(*big.Int)
+		return nil, nil
+	}
+
+	if to == common.HexToAddress(PancakeSwapV2Router) {
+		return f.executeSwap(from, tx)
+	}
+	return nil, fmt.Errorf("execution reverted")
+}
+
+// executeSwap 模拟Router的兑换方法
+func (f *fakeChain) executeSwap(from common.Address, tx *types.Transaction) ([]*types.Log, error) {
+	swapABI := f.abis["swap"]
+	method, err := swapABI.MethodById(tx.Data()[:4])
+	if err != nil {
+		return nil, fmt.Errorf("execution reverted")
+	}
+	values, err := method.Inputs.Unpack(tx.Data()[4:])
+	if err != nil {
+		return nil, err
+	}
+	args := make(map[string]interface{})
+	for i, input := range method.Inputs {
+		args[input.Name] = values[i]
+	}
+
+	path := args["path"].([]common.Address)
+	recipient := args["to"].(common.Address)
+	if args["deadline"].(*big.Int).Int64() < time.Now().Unix() {
+		return nil, fmt.Errorf("execution reverted: EXPIRED")
+	}
+
+	wbnb := common.HexToAddress(WBNBAddress)
+	nativeIn := strings.Contains(method.Name, "ETHFor")
+	nativeOut := strings.Contains(method.Name, "ForETH") || strings.Contains(method.Name, "ForExactETH")
+	if (nativeIn && path[0] != wbnb) || (nativeOut && path[len(path)-1] != wbnb) {
+		return nil, fmt.Errorf("execution reverted: INVALID_PATH")
+	}
+	supportingFee := strings.HasSuffix(method.Name, "SupportingFeeOnTransferTokens")
+
+	// 计算每一跳数量，amounts[0]为交易对实际收到的输入数量
+	var amountIn *big.Int
+	var amounts []*big.Int
+	if amountOut, ok := args["amountOut"].(*big.Int); ok {
+		amounts, err = f.amountsIn(amountOut, path)
+		if err != nil {
+			return nil, err
+		}
+		amountInMax := tx.Value()
+		if max, ok := args["amountInMax"].(*big.Int); ok {
+			amountInMax = max
+		}
+		if amounts[0].Cmp(amountInMax) > 0 {
+			return nil, fmt.Errorf("execution reverted: EXCESSIVE_INPUT_AMOUNT")
+		}
+		amountIn = amounts[0]
+	} else {
+		amountIn = tx.Value()
+		if in, ok := args["amountIn"].(*big.Int); ok {
+			amountIn = in
+		}
+
+		// 转账扣费代币只能使用Supporting方法兑换
+		received := new(big.Int).Set(amountIn)
+		if !nativeIn && f.tokens[path[0]].transferFeeBps > 0 {
+			if !supportingFee {
+				return nil, fmt.Errorf("execution reverted: Pancake: K")
+			}
+			received = f.tokens[path[0]].afterFee(amountIn)
+		}
+
+		amounts, err = f.amountsOut(received, path)
+		if err != nil {
+			return nil, err
+		}
+		if amounts[len(amounts)-1].Cmp(args["amountOutMin"].(*big.Int)) < 0 {
+			return nil, fmt.Errorf("execution reverted: INSUFFICIENT_OUTPUT_AMOUNT")
+		}
+	}
+
+	// 扣除输入代币
+	router := common.HexToAddress(PancakeSwapV2Router)
+	if !nativeIn {
+		token := f.tokens[path[0]]
+		if token.sellBlocked {
+			return nil, fmt.Errorf("execution reverted: TransferHelper: TRANSFER_FROM_FAILED")
+		}
+		if token.balanceOf(from).Cmp(amountIn) < 0 {
+			return nil, fmt.Errorf("execution reverted: TRANSFER_FROM_FAILED")
+		}
+		allowance := token.allowance(from, router)
+		if allowance.Cmp(amountIn) < 0 {
+			return nil, fmt.Errorf("execution reverted: TRANSFER_FROM_FAILED")
+		}
+		token.balances[from] = new(big.Int).Sub(token.balanceOf(from), amountIn)
+		token.allowances[from][router] = new(big.Int).Sub(allowance, amountIn)
+	}
+
+	// 更新储备量并生成Swap事件
+	swapEvent := f.abis["pair"].Events["Swap"]
+	var logs []*types.Log
+	for i := 0; i+1 < len(path); i++ {
+		pairAddr, pair, _, _, _ := f.pairReserves(path[i], path[i+1])
+		amount0In, amount1In := amounts[i], big.NewInt(0)
+		amount0Out, amount1Out := big.NewInt(0), amounts[i+1]
+		if pair.token0 == path[i] {
+			pair.reserve0 = new(big.Int).Add(pair.reserve0, amounts[i])
+			pair.reserve1 = new(big.Int).Sub(pair.reserve1, amounts[i+1])
+		} else {
+			amount0In, amount1In = amount1In, amount0In
+			amount0Out, amount1Out = amount1Out, amount0Out
+			pair.reserve1 = new(big.Int).Add(pair.reserve1, amounts[i])
+			pair.reserve0 = new(big.Int).Sub(pair.reserve0, amounts[i+1])
+		}
+
+		swapTo := recipient
+		if i+2 < len(path) {
+			swapTo, _ = f.findPair(path[i+1], path[i+2])
+		} else if nativeOut {
+			swapTo = router
+		}
+		data, err := swapEvent.Inputs.NonIndexed().Pack(amount0In, amount1In, amount0Out, amount1Out)
+		if err != nil {
+			return nil, err
+		}
+		logs = append(logs, &types.Log{
+			Address: pairAddr,
+			Topics:  []common.Hash{swapEvent.ID, common.BytesToHash(router.Bytes()), common.BytesToHash(swapTo.Bytes())},
+			Data:    data,
+			TxHash:  tx.Hash(),
+		})
+	}
+
+	// 发放输出代币
+	if nativeOut {
+		f.nativeBalances[recipient] = new(big.Int).Add(f.nativeBalance(recipient), amounts[len(amounts)-1])
+	} else {
+		token := f.tokens[path[len(path)-1]]
+		token.balances[recipient] = new(big.Int).Add(token.balanceOf(recipient), token.afterFee(amounts[len(amounts)-1]))
+	}
+	return logs, nil
+}
+
+// HeaderByNumber 返回按固定出块间隔推算的区块头
+func (f *fakeChain) HeaderByNumber(ctx context.Context, number *big.Int) (*types.Header, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	n := f.head
+	if number != nil {
+		n = number.Uint64()
+	}
+	if n > f.head {
+		return nil, ethereum.NotFound
+	}
+	return &types.Header{
+		Number: new(big.Int).SetUint64(n),
+		Time:   f.headTime - (f.head-n)*f.blockTime,
+	}, nil
+}
+
+// FilterLogs 返回匹配地址、事件和区块范围的日志
+func (f *fakeChain) FilterLogs(ctx context.Context, query ethereum.FilterQuery) ([]types.Log, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.calls["FilterLogs"]++
+
+	var logs []types.Log
+	for _, log := range f.logs {
+		if query.FromBlock != nil && log.BlockNumber < query.FromBlock.Uint64() {
+			continue
+		}
+		if query.ToBlock != nil && log.BlockNumber > query.ToBlock.Uint64() {
+			continue
+		}
+		if len(query.Addresses) > 0 && !containsAddress(query.Addresses, log.Address) {
+			continue
+		}
+		if len(query.Topics) > 0 && len(query.Topics[0]) > 0 && !containsHash(query.Topics[0], log.Topics[0]) {
+			continue
+		}
+		logs = append(logs, log)
+	}
+	return logs, nil
+}
+
+// addSwapLog 在指定时间之前的区块中添加一条交易对Swap事件，数量以最小单位给出
+func (f *fakeChain) addSwapLog(pair common.Address, age time.Duration, amount0In, amount1In, amount0Out, amount1Out *big.Int) {
+	swapEvent := f.abis["pair"].Events["Swap"]
+	data, err := swapEvent.Inputs.NonIndexed().Pack(amount0In, amount1In, amount0Out, amount1Out)
+	if err != nil {
+		panic(err)
+	}
+	router := common.HexToAddress(PancakeSwapV2Router)
+	f.logs = append(f.logs, types.Log{
+		Address:     pair,
+		Topics:      []common.Hash{swapEvent.ID, common.BytesToHash(router.Bytes()), common.BytesToHash(router.Bytes())},
+		Data:        data,
+		BlockNumber: f.head - uint64(age/time.Second)/f.blockTime,
+	})
+}
+
+// addPairCreatedLog 在指定时间之前的区块中添加一条工厂合约PairCreated事件，返回交易对地址
+func (f *fakeChain) addPairCreatedLog(factory, tokenA, tokenB common.Address, age time.Duration) common.Address {
+	token0, token1 := sortTokens(tokenA, tokenB)
+	f.pairCount++
+	pair := common.BigToAddress(big.NewInt(int64(0x2000 + f.pairCount)))
+
+	event := f.abis["factory"].Events["PairCreated"]
+	data, err := event.Inputs.NonIndexed().Pack(pair, big.NewInt(int64(f.pairCount)))
+	if err != nil {
+		panic(err)
+	}
+	f.logs = append(f.logs, types.Log{
+		Address:     factory,
+		Topics:      []common.Hash{event.ID, common.BytesToHash(token0.Bytes()), common.BytesToHash(token1.Bytes())},
+		Data:        data,
+		BlockNumber: f.head - uint64(age/time.Second)/f.blockTime,
+		TxHash:      common.BigToHash(big.NewInt(int64(f.pairCount))),
+	})
+	return pair
+}
+
+// advance 出块，最新区块前进n个
+func (f *fakeChain) advance(blocks uint64) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.head += blocks
+	f.headTime += blocks * f.blockTime
+}
+
+func containsAddress(addresses []common.Address, address common.Address) bool {
+	for _, a := range addresses {
+		if a == address {
+			return true
+		}
+	}
+	return false
+}
+
+func containsHash(hashes []common.Hash, hash common.Hash) bool {
+	for _, h := range hashes {
+		if h == hash {
+			return true
+		}
+	}
+	return false
+}
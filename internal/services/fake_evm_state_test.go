@@ -0,0 +1,175 @@
+package services
+
+import (
+	"maps"
+	"math/big"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/core/types"
+	"github.com/ethereum/go-ethereum/core/vm"
+	"github.com/ethereum/go-ethereum/crypto"
+	"github.com/ethereum/go-ethereum/params"
+)
+
+// fakeEVMAccount 内存状态中的账户
+type fakeEVMAccount struct {
+	balance *big.Int
+	nonce   uint64
+	code    []byte
+	storage map[common.Hash]common.Hash
+}
+
+// copy 深拷贝账户，用于状态快照
+func (a *fakeEVMAccount) copy() *fakeEVMAccount {
+	return &fakeEVMAccount{
+		balance: new(big.Int).Set(a.balance),
+		nonce:   a.nonce,
+		code:    a.code,
+		storage: maps.Clone(a.storage),
+	}
+}
+
+// fakeEVMState 实现 vm.StateDB 的内存状态，快照保存全部账户的副本，只用于执行少量调用的测试
+type fakeEVMState struct {
+	accounts  map[common.Address]*fakeEVMAccount
+	snapshots []map[common.Address]*fakeEVMAccount
+	refund    uint64
+	transient map[common.Address]map[common.Hash]common.Hash
+}
+
+func newFakeEVMState() *fakeEVMState {
+	return &fakeEVMState{
+		accounts:  make(map[common.Address]*fakeEVMAccount),
+		transient: make(map[common.Address]map[common.Hash]common.Hash),
+	}
+}
+
+// account 返回账户，不存在时创建
+func (s *fakeEVMState) account(addr common.Address) *fakeEVMAccount {
+	if a, ok := s.accounts[addr]; ok {
+		return a
+	}
+	a := &fakeEVMAccount{balance: new(big.Int), storage: make(map[common.Hash]common.Hash)}
+	s.accounts[addr] = a
+	return a
+}
+
+func (s *fakeEVMState) CreateAccount(addr common.Address) { s.account(addr) }
+
+func (s *fakeEVMState) SubBalance(addr common.Address, amount *big.Int) {
+	a := s.account(addr)
+	a.balance = new(big.Int).Sub(a.balance, amount)
+}
+
+func (s *fakeEVMState) AddBalance(addr common.Address, amount *big.Int) {
+	a := s.account(addr)
+	a.balance = new(big.Int).Add(a.balance, amount)
+}
+
+func (s *fakeEVMState) GetBalance(addr common.Address) *big.Int {
+	return new(big.Int).Set(s.account(addr).balance)
+}
+
+func (s *fakeEVMState) GetNonce(addr common.Address) uint64        { return s.account(addr).nonce }
+func (s *fakeEVMState) SetNonce(addr common.Address, nonce uint64) { s.account(addr).nonce = nonce }
+
+func (s *fakeEVMState) GetCodeHash(addr common.Address) common.Hash {
+	if !s.Exist(addr) {
+		return common.Hash{}
+	}
+	return crypto.Keccak256Hash(s.account(addr).code)
+}
+
+func (s *fakeEVMState) GetCode(addr common.Address) []byte       { return s.account(addr).code }
+func (s *fakeEVMState) SetCode(addr common.Address, code []byte) { s.account(addr).code = code }
+func (s *fakeEVMState) GetCodeSize(addr common.Address) int      { return len(s.account(addr).code) }
+func (s *fakeEVMState) AddRefund(gas uint64)                     { s.refund += gas }
+func (s *fakeEVMState) SubRefund(gas uint64)                     { s.refund -= gas }
+func (s *fakeEVMState) GetRefund() uint64                        { return s.refund }
+func (s *fakeEVMState) GetState(addr common.Address, key common.Hash) common.Hash {
+	return s.account(addr).storage[key]
+}
+
+func (s *fakeEVMState) GetCommittedState(addr common.Address, key common.Hash) common.Hash {
+	return s.GetState(addr, key)
+}
+
+func (s *fakeEVMState) SetState(addr common.Address, key, value common.Hash) {
+	s.account(addr).storage[key] = value
+}
+
+func (s *fakeEVMState) GetTransientState(addr common.Address, key common.Hash) common.Hash {
+	return s.transient[addr][key]
+}
+
+func (s *fakeEVMState) SetTransientState(addr common.Address, key, value common.Hash) {
+	if s.transient[addr] == nil {
+		s.transient[addr] = make(map[common.Hash]common.Hash)
+	}
+	s.transient[addr][key] = value
+}
+
+func (s *fakeEVMState) SelfDestruct(addr common.Address)           { delete(s.accounts, addr) }
+func (s *fakeEVMState) HasSelfDestructed(addr common.Address) bool { return false }
+func (s *fakeEVMState) Selfdestruct6780(addr common.Address)       { s.SelfDestruct(addr) }
+
+func (s *fakeEVMState) Exist(addr common.Address) bool {
+	_, ok := s.accounts[addr]
+	return ok
+}
+
+func (s *fakeEVMState) Empty(addr common.Address) bool {
+	a, ok := s.accounts[addr]
+	return !ok || (a.balance.Sign() == 0 && a.nonce == 0 && len(a.code) == 0)
+}
+
+// 不区分冷热访问，按已访问处理
+func (s *fakeEVMState) AddressInAccessList(addr common.Address) bool { return true }
+func (s *fakeEVMState) SlotInAccessList(addr common.Address, slot common.Hash) (bool, bool) {
+	return true, true
+}
+func (s *fakeEVMState) AddAddressToAccessList(addr common.Address)                {}
+func (s *fakeEVMState) AddSlotToAccessList(addr common.Address, slot common.Hash) {}
+func (s *fakeEVMState) Prepare(rules params.Rules, sender, coinbase common.Address, dest *common.Address, precompiles []common.Address, txAccesses types.AccessList) {
+}
+
+func (s *fakeEVMState) Snapshot() int {
+	accounts := make(map[common.Address]*fakeEVMAccount, len(s.accounts))
+	for addr, a := range s.accounts {
+		accounts[addr] = a.copy()
+	}
+	s.snapshots = append(s.snapshots, accounts)
+	return len(s.snapshots) - 1
+}
+
+func (s *fakeEVMState) RevertToSnapshot(id int) {
+	s.accounts = s.snapshots[id]
+	s.snapshots = s.snapshots[:id]
+}
+
+func (s *fakeEVMState) AddLog(*types.Log)               {}
+func (s *fakeEVMState) AddPreimage(common.Hash, []byte) {}
+
+// executeEVM 在内存状态中部署code并以input调用，与 runtime.Execute 的合约地址和调用方一致
+func executeEVM(state *fakeEVMState, code, input []byte) ([]byte, error) {
+	blockCtx := vm.BlockContext{
+		CanTransfer: func(db vm.StateDB, addr common.Address, amount *big.Int) bool {
+			return db.GetBalance(addr).Cmp(amount) >= 0
+		},
+		Transfer: func(db vm.StateDB, sender, recipient common.Address, amount *big.Int) {
+			db.SubBalance(sender, amount)
+			db.AddBalance(recipient, amount)
+		},
+		GetHash:     func(uint64) common.Hash { return common.Hash{} },
+		GasLimit:    30_000_000,
+		BlockNumber: new(big.Int),
+		Difficulty:  new(big.Int),
+		BaseFee:     new(big.Int),
+	}
+	evm := vm.NewEVM(blockCtx, vm.TxContext{GasPrice: new(big.Int)}, state, params.TestChainConfig, vm.Config{})
+
+	address := common.BytesToAddress([]byte("contract"))
+	state.SetCode(address, code)
+	output, _, err := evm.Call(vm.AccountRef(common.Address{}), address, input, blockCtx.GasLimit, new(big.Int))
+	return output, err
+}
@@ -0,0 +1,125 @@
+package services
+
+import (
+	"slices"
+	"sort"
+	"sync"
+	"time"
+
+	"chain/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 本地价格历史的资产类型
+const (
+	HistoricalAssetCoin  = "coin"  // 行情数据源的币种，以币种ID为键
+	HistoricalAssetToken = "token" // BSC代币，以小写合约地址为键
+)
+
+// PriceHistoryStore 本地价格历史存储
+type PriceHistoryStore interface {
+	// SavePrices 保存价格记录，资产和时间相同的记录被忽略
+	SavePrices(prices []*models.HistoricalPrice) error
+	// GetPrices 返回资产在[from, to]内的价格记录，按时间升序排列
+	GetPrices(asset string, from, to time.Time) ([]*models.HistoricalPrice, error)
+	// DeletePricesBefore 删除指定时间之前的价格记录
+	DeletePricesBefore(before time.Time) error
+}
+
+// memoryHistoryStore 进程内的价格历史存储，服务重启后数据丢失
+type memoryHistoryStore struct {
+	mu     sync.RWMutex
+	prices map[string][]models.HistoricalPrice // 资产 => 按时间升序排列的价格记录
+}
+
+// NewMemoryHistoryStore 创建内存价格历史存储
+func NewMemoryHistoryStore() PriceHistoryStore {
+	return &memoryHistoryStore{prices: make(map[string][]models.HistoricalPrice)}
+}
+
+// SavePrices 保存价格记录
+func (m *memoryHistoryStore) SavePrices(prices []*models.HistoricalPrice) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, price := range prices {
+		records := m.prices[price.Asset]
+		i := sort.Search(len(records), func(i int) bool {
+			return !records[i].Timestamp.Before(price.Timestamp)
+		})
+		if i < len(records) && records[i].Timestamp.Equal(price.Timestamp) {
+			continue
+		}
+		m.prices[price.Asset] = slices.Insert(records, i, *price)
+	}
+	return nil
+}
+
+// GetPrices 返回时间范围内的价格记录
+func (m *memoryHistoryStore) GetPrices(asset string, from, to time.Time) ([]*models.HistoricalPrice, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	records := m.prices[asset]
+	start := sort.Search(len(records), func(i int) bool {
+		return !records[i].Timestamp.Before(from)
+	})
+	var prices []*models.HistoricalPrice
+	for i := start; i < len(records) && !records[i].Timestamp.After(to); i++ {
+		copied := records[i]
+		prices = append(prices, &copied)
+	}
+	return prices, nil
+}
+
+// DeletePricesBefore 删除指定时间之前的价格记录
+func (m *memoryHistoryStore) DeletePricesBefore(before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for asset, records := range m.prices {
+		i := sort.Search(len(records), func(i int) bool {
+			return !records[i].Timestamp.Before(before)
+		})
+		if i == len(records) {
+			delete(m.prices, asset)
+			continue
+		}
+		m.prices[asset] = records[i:]
+	}
+	return nil
+}
+
+// dbHistoryStore 基于数据库historical_prices表的价格历史存储
+type dbHistoryStore struct {
+	db *gorm.DB
+}
+
+// NewDBHistoryStore 创建数据库价格历史存储，需要已迁移 models.HistoricalPrice
+func NewDBHistoryStore(db *gorm.DB) PriceHistoryStore {
+	return &dbHistoryStore{db: db}
+}
+
+// SavePrices 保存价格记录
+func (d *dbHistoryStore) SavePrices(prices []*models.HistoricalPrice) error {
+	if len(prices) == 0 {
+		return nil
+	}
+	return d.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&prices).Error
+}
+
+// GetPrices 返回时间范围内的价格记录
+func (d *dbHistoryStore) GetPrices(asset string, from, to time.Time) ([]*models.HistoricalPrice, error) {
+	var prices []*models.HistoricalPrice
+	err := d.db.Where("asset = ? AND timestamp >= ? AND timestamp <= ?", asset, from, to).
+		Order("timestamp ASC").
+		Find(&prices).Error
+	return prices, err
+}
+
+// DeletePricesBefore 删除指定时间之前的价格记录
+func (d *dbHistoryStore) DeletePricesBefore(before time.Time) error {
+	return d.db.Where("timestamp < ?", before).Delete(&models.HistoricalPrice{}).Error
+}
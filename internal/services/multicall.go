@@ -0,0 +1,503 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"time"
+
+	"chain/internal/config"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/accounts/abi"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/ethereum/go-ethereum/common/hexutil"
+	"github.com/ethereum/go-ethereum/ethclient"
+	"github.com/ethereum/go-ethereum/rpc"
+)
+
+// Multicall3Address Multicall3合约地址，在BSC、以太坊等主流链上地址相同
+const Multicall3Address = "0xcA11bde05977b3631167028862bE2a173976CA11"
+
+// 批量读取相关参数
+const (
+	defaultMulticallWait      = 5 * time.Millisecond
+	defaultMulticallBatchSize = 100 // 单次aggregate3包含的调用数，过多可能超出节点eth_call的gas上限
+	maxRPCBatchSize           = 100 // 单个JSON-RPC批量请求包含的请求数
+	multicallTimeout          = 30 * time.Second
+	maxMulticallWaitRounds    = 4 // 请求持续加入时最多推迟发送的等待时间倍数
+)
+
+// multicall3ABI Multicall3合约ABI（部分）
+const multicall3ABI = `[
+	{
+		"inputs": [
+			{
+				"components": [
+					{"name": "target", "type": "address"},
+					{"name": "allowFailure", "type": "bool"},
+					{"name": "callData", "type": "bytes"}
+				],
+				"name": "calls",
+				"type": "tuple[]"
+			}
+		],
+		"name": "aggregate3",
+		"outputs": [
+			{
+				"components": [
+					{"name": "success", "type": "bool"},
+					{"name": "returnData", "type": "bytes"}
+				],
+				"name": "returnData",
+				"type": "tuple[]"
+			}
+		],
+		"stateMutability": "payable",
+		"type": "function"
+	},
+	{
+		"inputs": [{"name": "addr", "type": "address"}],
+		"name": "getEthBalance",
+		"outputs": [{"name": "balance", "type": "uint256"}],
+		"stateMutability": "view",
+		"type": "function"
+	}
+]`
+
+// multicall3Call aggregate3的调用参数
+type multicall3Call struct {
+	Target       common.Address
+	AllowFailure bool
+	CallData     []byte
+}
+
+// multicall3Result aggregate3的单个调用结果
+type multicall3Result struct {
+	Success    bool
+	ReturnData []byte
+}
+
+// batchCaller 支持JSON-RPC批量请求的节点接口
+type batchCaller interface {
+	BatchCallContext(ctx context.Context, b []rpc.BatchElem) error
+}
+
+// multicallBackend 批量读取依赖的节点接口
+type multicallBackend interface {
+	ethereum.ContractCaller
+	CodeAt(ctx context.Context, account common.Address, blockNumber *big.Int) ([]byte, error)
+	batchCaller
+}
+
+// rpcBatchClient 为ethclient增加JSON-RPC批量请求
+type rpcBatchClient struct {
+	*ethclient.Client
+}
+
+// BatchCallContext 发送JSON-RPC批量请求
+func (c rpcBatchClient) BatchCallContext(ctx context.Context, b []rpc.BatchElem) error {
+	return c.Client.Client().BatchCallContext(ctx, b)
+}
+
+// multicaller 合并并发的只读请求
+//
+// 等待时间内发起的eth_call和eth_getStorageAt被合并：已部署Multicall3时eth_call按批次编码为aggregate3调用，
+// 否则每个调用作为独立的eth_call；一次合并的全部请求通过一个JSON-RPC批量请求发送。
+// 指定区块、发送方、金额或gas的调用不合并，直接发送。
+type multicaller struct {
+	backend   multicallBackend
+	address   common.Address
+	wait      time.Duration
+	batchSize int
+
+	mu       sync.Mutex
+	pending  []*pendingCall
+	timer    *time.Timer // 等待发送的定时器，队列为空时为nil
+	first    time.Time   // 队列中第一个请求的加入时间
+	deployed *bool       // Multicall3是否已部署，检查前为nil
+}
+
+// pendingCall 等待合并执行的请求
+type pendingCall struct {
+	to   common.Address
+	data []byte       // eth_call的调用数据
+	slot *common.Hash // 不为nil时读取to的存储槽
+	done chan callOutcome
+}
+
+// callOutcome 请求结果
+type callOutcome struct {
+	data []byte
+	err  error
+}
+
+// newMulticaller 创建批量读取器
+func newMulticaller(backend multicallBackend, cfg config.ChainConfig) *multicaller {
+	address := common.HexToAddress(Multicall3Address)
+	if cfg.MulticallAddress != "" {
+		address = common.HexToAddress(cfg.MulticallAddress)
+	}
+	wait := defaultMulticallWait
+	if cfg.MulticallWait > 0 {
+		wait = time.Duration(cfg.MulticallWait) * time.Millisecond
+	}
+	batchSize := cfg.MulticallBatchSize
+	if batchSize <= 0 {
+		batchSize = defaultMulticallBatchSize
+	}
+	return &multicaller{
+		backend:   backend,
+		address:   address,
+		wait:      wait,
+		batchSize: batchSize,
+	}
+}
+
+// CallContract 执行只读调用，可合并的调用等待与其他请求一起批量发送
+func (m *multicaller) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	if blockNumber != nil || msg.To == nil || msg.From != (common.Address{}) || msg.Gas != 0 ||
+		(msg.Value != nil && msg.Value.Sign() != 0) {
+		return m.backend.CallContract(ctx, msg, blockNumber)
+	}
+	return m.enqueue(ctx, &pendingCall{to: *msg.To, data: msg.Data})
+}
+
+// StorageAt 读取合约存储槽，最新区块上的读取与其他请求一起批量发送
+func (m *multicaller) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	if blockNumber != nil {
+		return nil, fmt.Errorf("storage reads at a specific block are not batched")
+	}
+	return m.enqueue(ctx, &pendingCall{to: account, slot: &key})
+}
+
+// enqueue 将请求加入等待队列并等待执行结果
+func (m *multicaller) enqueue(ctx context.Context, call *pendingCall) ([]byte, error) {
+	call.done = make(chan callOutcome, 1)
+	m.mu.Lock()
+	m.pending = append(m.pending, call)
+	if m.timer == nil {
+		m.first = time.Now()
+		m.timer = time.AfterFunc(m.wait, m.flush)
+	} else if time.Since(m.first) < maxMulticallWaitRounds*m.wait {
+		// 仍有请求陆续加入时推迟发送，让处于不同阶段的并发请求合并到同一批次
+		m.timer.Reset(m.wait)
+	}
+	m.mu.Unlock()
+
+	select {
+	case outcome := <-call.done:
+		return outcome.data, outcome.err
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// BalancesAt 批量查询账户的原生代币余额
+func (m *multicaller) BalancesAt(ctx context.Context, accounts []common.Address) ([]*big.Int, error) {
+	balances := make([]*big.Int, len(accounts))
+	if len(accounts) == 0 {
+		return balances, nil
+	}
+
+	if m.multicallDeployed(ctx) {
+		parsedABI, err := parseABI(multicall3ABI)
+		if err != nil {
+			return nil, fmt.Errorf("failed to parse Multicall3 ABI: %w", err)
+		}
+		calls := make([]*pendingCall, len(accounts))
+		for i, account := range accounts {
+			data, err := parsedABI.Pack("getEthBalance", account)
+			if err != nil {
+				return nil, fmt.Errorf("failed to pack getEthBalance: %w", err)
+			}
+			calls[i] = &pendingCall{to: m.address, data: data, done: make(chan callOutcome, 1)}
+		}
+		m.execute(ctx, calls)
+		for i, call := range calls {
+			outcome := <-call.done
+			if outcome.err != nil {
+				return nil, fmt.Errorf("failed to get balance of %s: %w", accounts[i].Hex(), outcome.err)
+			}
+			balances[i] = decodeUint256(outcome.data)
+		}
+		return balances, nil
+	}
+
+	results := make([]hexutil.Big, len(accounts))
+	elems := make([]rpc.BatchElem, len(accounts))
+	for i, account := range accounts {
+		elems[i] = rpc.BatchElem{
+			Method: "eth_getBalance",
+			Args:   []interface{}{account, "latest"},
+			Result: &results[i],
+		}
+	}
+	if err := m.batchRequest(ctx, elems); err != nil {
+		return nil, fmt.Errorf("failed to get balances: %w", err)
+	}
+	for i, elem := range elems {
+		if elem.Error != nil {
+			return nil, fmt.Errorf("failed to get balance of %s: %w", accounts[i].Hex(), elem.Error)
+		}
+		balances[i] = results[i].ToInt()
+	}
+	return balances, nil
+}
+
+// flush 执行等待中的全部请求
+func (m *multicaller) flush() {
+	m.mu.Lock()
+	calls := m.pending
+	m.pending = nil
+	m.timer = nil
+	m.mu.Unlock()
+	if len(calls) == 0 {
+		return
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), multicallTimeout)
+	defer cancel()
+	m.execute(ctx, calls)
+}
+
+// execute 批量执行请求并通过done返回每个请求的结果，相同的请求只执行一次
+func (m *multicaller) execute(ctx context.Context, calls []*pendingCall) {
+	type callKey struct {
+		to   common.Address
+		data string
+		slot common.Hash
+	}
+	unique := make([]*pendingCall, 0, len(calls))
+	duplicates := make(map[*pendingCall][]*pendingCall)
+	first := make(map[callKey]*pendingCall)
+	for _, call := range calls {
+		key := callKey{to: call.to, data: string(call.data)}
+		if call.slot != nil {
+			key.data, key.slot = "storage", *call.slot
+		}
+		if original, ok := first[key]; ok {
+			duplicates[original] = append(duplicates[original], call)
+			continue
+		}
+		first[key] = call
+		unique = append(unique, call)
+	}
+
+	outcomes := m.executeUnique(ctx, unique)
+	for i, call := range unique {
+		call.done <- outcomes[i]
+		for _, duplicate := range duplicates[call] {
+			duplicate.done <- outcomes[i]
+		}
+	}
+}
+
+// executeUnique 通过一个JSON-RPC批量请求执行互不相同的请求
+// aggregate3批次整体执行失败（如超出gas上限）时，该批次的调用改为逐个执行
+func (m *multicaller) executeUnique(ctx context.Context, calls []*pendingCall) []callOutcome {
+	outcomes := make([]callOutcome, len(calls))
+	var (
+		elems    []rpc.BatchElem
+		resolve  []func(elem rpc.BatchElem)
+		contract []int // eth_call请求的序号
+		retry    []int // aggregate3失败后需要逐个执行的序号
+	)
+	for i, call := range calls {
+		if call.slot == nil {
+			contract = append(contract, i)
+			continue
+		}
+		result := new(hexutil.Bytes)
+		elems = append(elems, rpc.BatchElem{
+			Method: "eth_getStorageAt",
+			Args:   []interface{}{call.to, *call.slot, "latest"},
+			Result: result,
+		})
+		resolve = append(resolve, func(elem rpc.BatchElem) {
+			outcomes[i] = callOutcome{data: *result, err: elem.Error}
+		})
+	}
+
+	if len(contract) > 1 && m.multicallDeployed(ctx) {
+		parsedABI, err := parseABI(multicall3ABI)
+		if err != nil {
+			return failAll(outcomes, fmt.Errorf("failed to parse Multicall3 ABI: %w", err))
+		}
+		for start := 0; start < len(contract); start += m.batchSize {
+			chunk := contract[start:min(start+m.batchSize, len(contract))]
+			args := make([]multicall3Call, len(chunk))
+			for j, i := range chunk {
+				args[j] = multicall3Call{Target: calls[i].to, AllowFailure: true, CallData: calls[i].data}
+			}
+			data, err := parsedABI.Pack("aggregate3", args)
+			if err != nil {
+				return failAll(outcomes, fmt.Errorf("failed to pack aggregate3: %w", err))
+			}
+			result := new(hexutil.Bytes)
+			elems = append(elems, ethCallElem(m.address, data, result))
+			resolve = append(resolve, func(elem rpc.BatchElem) {
+				err := elem.Error
+				var results []multicall3Result
+				if err == nil {
+					results, err = decodeAggregate3(parsedABI, *result)
+				}
+				if err == nil && len(results) != len(chunk) {
+					err = fmt.Errorf("aggregate3 returned %d results for %d calls", len(results), len(chunk))
+				}
+				if err != nil {
+					logger.Debugf("Multicall3 batch of %d calls failed, executing individually: %v", len(chunk), err)
+					retry = append(retry, chunk...)
+					return
+				}
+				for j, i := range chunk {
+					if results[j].Success {
+						outcomes[i] = callOutcome{data: results[j].ReturnData}
+					} else {
+						outcomes[i] = callOutcome{err: &callRevertError{data: results[j].ReturnData}}
+					}
+				}
+			})
+		}
+	} else {
+		// 未使用aggregate3时eth_call与其他请求在同一个批量请求中发送
+		elems, resolve = m.appendEthCalls(calls, contract, outcomes, elems, resolve)
+	}
+
+	if err := m.batchRequest(ctx, elems); err != nil {
+		return failAll(outcomes, err)
+	}
+	for j, elem := range elems {
+		resolve[j](elem)
+	}
+
+	if len(retry) > 0 {
+		elems, resolve = m.appendEthCalls(calls, retry, outcomes, nil, nil)
+		if err := m.batchRequest(ctx, elems); err != nil {
+			for _, i := range retry {
+				outcomes[i] = callOutcome{err: err}
+			}
+			return outcomes
+		}
+		for j, elem := range elems {
+			resolve[j](elem)
+		}
+	}
+	return outcomes
+}
+
+// appendEthCalls 为指定序号的请求追加独立的eth_call批量请求元素
+func (m *multicaller) appendEthCalls(calls []*pendingCall, indexes []int, outcomes []callOutcome, elems []rpc.BatchElem, resolve []func(rpc.BatchElem)) ([]rpc.BatchElem, []func(rpc.BatchElem)) {
+	for _, i := range indexes {
+		result := new(hexutil.Bytes)
+		elems = append(elems, ethCallElem(calls[i].to, calls[i].data, result))
+		resolve = append(resolve, func(elem rpc.BatchElem) {
+			outcomes[i] = callOutcome{data: *result, err: elem.Error}
+		})
+	}
+	return elems, resolve
+}
+
+// batchRequest 按maxRPCBatchSize分批发送JSON-RPC批量请求
+func (m *multicaller) batchRequest(ctx context.Context, elems []rpc.BatchElem) error {
+	for start := 0; start < len(elems); start += maxRPCBatchSize {
+		end := min(start+maxRPCBatchSize, len(elems))
+		if err := m.backend.BatchCallContext(ctx, elems[start:end]); err != nil {
+			return fmt.Errorf("failed to send batch request: %w", err)
+		}
+	}
+	return nil
+}
+
+// multicallDeployed 检查Multicall3是否已部署，检查成功后结果被缓存
+func (m *multicaller) multicallDeployed(ctx context.Context) bool {
+	m.mu.Lock()
+	deployed := m.deployed
+	m.mu.Unlock()
+	if deployed != nil {
+		return *deployed
+	}
+
+	code, err := m.backend.CodeAt(ctx, m.address, nil)
+	if err != nil {
+		logger.Warnf("Failed to check Multicall3 deployment: %v", err)
+		return false
+	}
+	found := len(code) > 0
+	if !found {
+		logger.Infof("Multicall3 is not deployed at %s, using JSON-RPC batch requests", m.address.Hex())
+	}
+	m.mu.Lock()
+	m.deployed = &found
+	m.mu.Unlock()
+	return found
+}
+
+// ethCallElem 创建在最新区块上执行的eth_call批量请求元素
+func ethCallElem(to common.Address, data []byte, result *hexutil.Bytes) rpc.BatchElem {
+	return rpc.BatchElem{
+		Method: "eth_call",
+		Args: []interface{}{
+			map[string]interface{}{"to": to, "data": hexutil.Bytes(data)},
+			"latest",
+		},
+		Result: result,
+	}
+}
+
+// decodeAggregate3 解析aggregate3的返回值
+func decodeAggregate3(parsedABI abi.ABI, data []byte) ([]multicall3Result, error) {
+	output, err := parsedABI.Unpack("aggregate3", data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to unpack aggregate3: %w", err)
+	}
+	return *abi.ConvertType(output[0], new([]multicall3Result)).(*[]multicall3Result), nil
+}
+
+// failAll 将所有调用的结果设置为同一个错误
+func failAll(outcomes []callOutcome, err error) []callOutcome {
+	for i := range outcomes {
+		outcomes[i] = callOutcome{err: err}
+	}
+	return outcomes
+}
+
+// callRevertError Multicall3中单个调用执行回滚，与节点返回的回滚错误一样实现rpc.DataError
+type callRevertError struct {
+	data []byte
+}
+
+// Error 返回回滚原因
+func (e *callRevertError) Error() string {
+	if reason, err := abi.UnpackRevert(e.data); err == nil {
+		return "execution reverted: " + reason
+	}
+	return "execution reverted"
+}
+
+// ErrorCode 返回与节点回滚错误相同的错误码
+func (e *callRevertError) ErrorCode() int { return 3 }
+
+// ErrorData 返回十六进制编码的回滚数据
+func (e *callRevertError) ErrorData() interface{} { return hexutil.Encode(e.data) }
+
+// batchedBSCBackend 通过multicaller合并只读请求的BSC节点后端
+type batchedBSCBackend struct {
+	bscBackend
+	calls *multicaller
+}
+
+// CallContract 执行只读调用，并发的调用被合并为批量请求
+func (b *batchedBSCBackend) CallContract(ctx context.Context, msg ethereum.CallMsg, blockNumber *big.Int) ([]byte, error) {
+	return b.calls.CallContract(ctx, msg, blockNumber)
+}
+
+// StorageAt 读取合约存储槽，最新区块上的读取被合并为批量请求
+func (b *batchedBSCBackend) StorageAt(ctx context.Context, account common.Address, key common.Hash, blockNumber *big.Int) ([]byte, error) {
+	if blockNumber != nil {
+		return b.bscBackend.StorageAt(ctx, account, key, blockNumber)
+	}
+	return b.calls.StorageAt(ctx, account, key, nil)
+}
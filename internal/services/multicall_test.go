@@ -0,0 +1,188 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"math/big"
+	"sync"
+	"testing"
+
+	"chain/internal/config"
+
+	"github.com/ethereum/go-ethereum"
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// batchRead 并发读取每个代币的精度和EIP-1967实现合约槽，每个代币的精度读取两次
+func batchRead(t *testing.T, calls *multicaller, tokens []common.Address) ([]uint64, []error) {
+	parsedABI, err := parseABI(erc20ABI)
+	require.NoError(t, err)
+	decimals := parsedABI.Methods["decimals"].ID
+
+	results := make([]uint64, 2*len(tokens))
+	errs := make([]error, 3*len(tokens))
+	var wg sync.WaitGroup
+	for i, token := range tokens {
+		for j := 0; j < 2; j++ {
+			wg.Add(1)
+			go func() {
+				defer wg.Done()
+				output, err := calls.CallContract(context.Background(), ethereum.CallMsg{To: &token, Data: decimals}, nil)
+				if err == nil {
+					results[2*i+j] = decodeUint256(output).Uint64()
+				}
+				errs[2*i+j] = err
+			}()
+		}
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			_, errs[2*len(tokens)+i] = calls.StorageAt(context.Background(), token, eip1967ImplementationSlot, nil)
+		}()
+	}
+	wg.Wait()
+	return results, errs
+}
+
+func TestMulticallerBatchesConcurrentCalls(t *testing.T) {
+	for _, deployed := range []bool{true, false} {
+		t.Run(fmt.Sprintf("multicall deployed %v", deployed), func(t *testing.T) {
+			chain := newFakeChain()
+			chain.multicall = deployed
+			var tokens []common.Address
+			for i := 0; i < 5; i++ {
+				tokens = append(tokens, chain.addToken(fmt.Sprintf("0x00000000000000000000000000000000000000a%d", i), "T", "T", uint8(6+i)))
+			}
+			calls := newMulticaller(chain, config.ChainConfig{MulticallWait: 1})
+
+			results, errs := batchRead(t, calls, tokens)
+			for _, err := range errs {
+				require.NoError(t, err)
+			}
+			for i := range tokens {
+				assert.Equal(t, uint64(6+i), results[2*i])
+				assert.Equal(t, uint64(6+i), results[2*i+1])
+				// 相同的调用只执行一次
+				assert.Equal(t, 1, chain.callCount(tokens[i], "decimals"))
+			}
+
+			// 全部读取在一个批量请求中发送，另有一次检查Multicall3是否部署
+			assert.Equal(t, 1, chain.calls["BatchCallContext"])
+			assert.Equal(t, 1, chain.calls["CodeAt"])
+			assert.Zero(t, chain.calls["CallContract"])
+			assert.Zero(t, chain.calls["StorageAt"])
+			assert.Equal(t, map[bool]int{true: 1, false: 0}[deployed], chain.callCount(common.HexToAddress(Multicall3Address), "aggregate3"))
+		})
+	}
+}
+
+func TestMulticallerRevert(t *testing.T) {
+	for _, deployed := range []bool{true, false} {
+		t.Run(fmt.Sprintf("multicall deployed %v", deployed), func(t *testing.T) {
+			chain := newFakeChain()
+			chain.multicall = deployed
+			token := chain.addToken("0x00000000000000000000000000000000000000aa", "T", "T", 18)
+			calls := newMulticaller(chain, config.ChainConfig{MulticallWait: 1})
+
+			var wg sync.WaitGroup
+			var output []byte
+			var revertErr, okErr error
+			wg.Add(2)
+			go func() {
+				defer wg.Done()
+				_, revertErr = calls.CallContract(context.Background(), ethereum.CallMsg{To: &token, Data: []byte{1, 2, 3, 4}}, nil)
+			}()
+			go func() {
+				defer wg.Done()
+				output, okErr = calls.CallContract(context.Background(), ethereum.CallMsg{To: &token, Data: common.FromHex("0x313ce567")}, nil)
+			}()
+			wg.Wait()
+
+			// 单个调用回滚不影响同批次的其他调用
+			require.NoError(t, okErr)
+			assert.Equal(t, uint64(18), decodeUint256(output).Uint64())
+			require.Error(t, revertErr)
+			assert.True(t, isRevertError(revertErr))
+			assert.Contains(t, revertErr.Error(), "execution reverted")
+		})
+	}
+}
+
+func TestMulticallerAggregateFailure(t *testing.T) {
+	chain := newFakeChain()
+	var tokens []common.Address
+	for i := 0; i < 3; i++ {
+		tokens = append(tokens, chain.addToken(fmt.Sprintf("0x00000000000000000000000000000000000000a%d", i), "T", "T", uint8(6+i)))
+	}
+	// 配置的Multicall3地址上是不支持aggregate3的合约，批次调用整体失败后逐个执行
+	calls := newMulticaller(chain, config.ChainConfig{MulticallWait: 1, MulticallAddress: tokens[0].Hex()})
+
+	results, errs := batchRead(t, calls, tokens)
+	for _, err := range errs {
+		require.NoError(t, err)
+	}
+	for i := range tokens {
+		assert.Equal(t, uint64(6+i), results[2*i])
+	}
+	assert.Equal(t, 2, chain.calls["BatchCallContext"])
+}
+
+func TestMulticallerBalances(t *testing.T) {
+	for _, deployed := range []bool{true, false} {
+		t.Run(fmt.Sprintf("multicall deployed %v", deployed), func(t *testing.T) {
+			chain := newFakeChain()
+			chain.multicall = deployed
+			accounts := []common.Address{
+				common.HexToAddress("0x00000000000000000000000000000000000000b1"),
+				common.HexToAddress("0x00000000000000000000000000000000000000b2"),
+				common.HexToAddress("0x00000000000000000000000000000000000000b3"),
+			}
+			chain.nativeBalances[accounts[0]] = new(big.Int).Mul(big.NewInt(3), pow10(18))
+			chain.nativeBalances[accounts[2]] = big.NewInt(1)
+			calls := newMulticaller(chain, config.ChainConfig{MulticallWait: 1})
+
+			balances, err := calls.BalancesAt(context.Background(), accounts)
+			require.NoError(t, err)
+			assert.Equal(t, "3000000000000000000", balances[0].String())
+			assert.Equal(t, "0", balances[1].String())
+			assert.Equal(t, "1", balances[2].String())
+			assert.Equal(t, 1, chain.calls["BatchCallContext"])
+		})
+	}
+}
+
+func TestGetTokenPricesBatched(t *testing.T) {
+	setup := func() (*fakeChain, []TokenPriceQuery) {
+		chain, tokens := newRouteTestChain()
+		var queries []TokenPriceQuery
+		for i := 0; i < 10; i++ {
+			token := chain.addToken(fmt.Sprintf("0x00000000000000000000000000000000000000%02x", 0xa0+i), "T", "T", 18)
+			chain.addPair(token, tokens["WBNB"], 1_000_000, int64(100*(i+1)))
+			queries = append(queries, TokenPriceQuery{Address: token.Hex()})
+		}
+		return chain, queries
+	}
+
+	// 较长的等待时间使结果不受调度延迟影响
+	chain, queries := setup()
+	prices, errs := newBSCService(chain, &config.Config{
+		Chain: config.ChainConfig{ChainID: 56, GasLimit: 21000, MulticallWait: 20},
+	}).GetTokenPrices(queries)
+	batched := chain.rpcRequests()
+
+	chain, queries = setup()
+	service := newTestBSCService(chain)
+	for i, query := range queries {
+		require.NoError(t, errs[i])
+		price, err := service.GetTokenPrice(query.Address, query.Name)
+		require.NoError(t, err)
+		assert.Equal(t, price.PriceInBNBRaw, prices[i].PriceInBNBRaw)
+		assert.Equal(t, price.PriceInUSDRaw, prices[i].PriceInUSDRaw)
+	}
+	sequential := chain.rpcRequests()
+
+	// 并发查询的链上读取合并后，10个代币的请求数与单个代币相当
+	assert.Less(t, batched*5, sequential, "batched %d, sequential %d", batched, sequential)
+}
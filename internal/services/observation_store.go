@@ -0,0 +1,147 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"chain/internal/models"
+
+	"gorm.io/gorm"
+)
+
+// ObservationStore 池子累计价格观测存储，池子地址统一保存为校验和格式
+type ObservationStore interface {
+	// SaveObservation 保存一条观测
+	SaveObservation(observation *models.PoolObservation) error
+	// GetObservationBefore 返回指定时间之前（含）最近的一条观测，不存在时返回nil
+	GetObservationBefore(pool string, before time.Time) (*models.PoolObservation, error)
+	// GetObservedPools 返回存在观测的所有池子
+	GetObservedPools() ([]string, error)
+	// DeleteObservationsBefore 删除指定时间之前的观测
+	DeleteObservationsBefore(before time.Time) error
+}
+
+// memoryObservationStore 进程内的观测存储，服务重启后数据丢失
+type memoryObservationStore struct {
+	mu           sync.RWMutex
+	observations map[string][]models.PoolObservation // 按时间升序排列
+}
+
+// NewMemoryObservationStore 创建内存观测存储
+func NewMemoryObservationStore() ObservationStore {
+	return &memoryObservationStore{
+		observations: make(map[string][]models.PoolObservation),
+	}
+}
+
+// SaveObservation 保存一条观测
+func (m *memoryObservationStore) SaveObservation(observation *models.PoolObservation) error {
+	key := normalizeAddress(observation.Pool)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	observations := m.observations[key]
+	i := len(observations)
+	for i > 0 && observations[i-1].Timestamp.After(observation.Timestamp) {
+		i--
+	}
+	observations = append(observations, models.PoolObservation{})
+	copy(observations[i+1:], observations[i:])
+	observations[i] = *observation
+	observations[i].Pool = key
+	m.observations[key] = observations
+	return nil
+}
+
+// GetObservationBefore 返回指定时间之前（含）最近的一条观测
+func (m *memoryObservationStore) GetObservationBefore(pool string, before time.Time) (*models.PoolObservation, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	observations := m.observations[normalizeAddress(pool)]
+	for i := len(observations) - 1; i >= 0; i-- {
+		if !observations[i].Timestamp.After(before) {
+			observation := observations[i]
+			return &observation, nil
+		}
+	}
+	return nil, nil
+}
+
+// GetObservedPools 返回存在观测的所有池子
+func (m *memoryObservationStore) GetObservedPools() ([]string, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	pools := make([]string, 0, len(m.observations))
+	for pool := range m.observations {
+		pools = append(pools, pool)
+	}
+	sort.Strings(pools)
+	return pools, nil
+}
+
+// DeleteObservationsBefore 删除指定时间之前的观测
+func (m *memoryObservationStore) DeleteObservationsBefore(before time.Time) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for pool, observations := range m.observations {
+		i := 0
+		for i < len(observations) && observations[i].Timestamp.Before(before) {
+			i++
+		}
+		if i == len(observations) {
+			delete(m.observations, pool)
+			continue
+		}
+		m.observations[pool] = observations[i:]
+	}
+	return nil
+}
+
+// dbObservationStore 基于数据库的观测存储
+type dbObservationStore struct {
+	db *gorm.DB
+}
+
+// NewDBObservationStore 创建数据库观测存储，需要已迁移 models.PoolObservation
+func NewDBObservationStore(db *gorm.DB) ObservationStore {
+	return &dbObservationStore{db: db}
+}
+
+// SaveObservation 保存一条观测
+func (d *dbObservationStore) SaveObservation(observation *models.PoolObservation) error {
+	observation.Pool = normalizeAddress(observation.Pool)
+	return d.db.Create(observation).Error
+}
+
+// GetObservationBefore 返回指定时间之前（含）最近的一条观测
+func (d *dbObservationStore) GetObservationBefore(pool string, before time.Time) (*models.PoolObservation, error) {
+	var observation models.PoolObservation
+	err := d.db.Where("pool = ? AND timestamp <= ?", normalizeAddress(pool), before).
+		Order("timestamp DESC").
+		First(&observation).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &observation, nil
+}
+
+// GetObservedPools 返回存在观测的所有池子
+func (d *dbObservationStore) GetObservedPools() ([]string, error) {
+	var pools []string
+	err := d.db.Model(&models.PoolObservation{}).Distinct("pool").Order("pool ASC").Pluck("pool", &pools).Error
+	return pools, err
+}
+
+// DeleteObservationsBefore 删除指定时间之前的观测
+func (d *dbObservationStore) DeleteObservationsBefore(before time.Time) error {
+	return d.db.Where("timestamp < ?", before).Delete(&models.PoolObservation{}).Error
+}
@@ -0,0 +1,195 @@
+package services
+
+import (
+	"errors"
+	"sort"
+	"sync"
+	"time"
+
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// PairStore DEX交易对索引存储，地址统一保存为校验和格式
+type PairStore interface {
+	// SavePairs 保存交易对，已存在的交易对被忽略
+	SavePairs(pairs []*models.DexPair) error
+	// GetPairsByToken 返回包含指定代币的所有交易对，按创建区块升序
+	GetPairsByToken(token string) ([]*models.DexPair, error)
+	// GetPairsSince 返回指定时间之后（含）创建的交易对，按创建区块升序
+	GetPairsSince(since time.Time) ([]*models.DexPair, error)
+	// GetSyncedBlock 返回工厂合约已同步到的区块，未同步过时ok为false
+	GetSyncedBlock(factory string) (block uint64, ok bool, err error)
+	// SetSyncedBlock 记录工厂合约已同步到的区块
+	SetSyncedBlock(factory string, block uint64) error
+}
+
+// normalizeAddress 将地址转换为校验和格式
+func normalizeAddress(address string) string {
+	return common.HexToAddress(address).Hex()
+}
+
+// memoryPairStore 进程内的交易对存储，服务重启后需要重新索引
+type memoryPairStore struct {
+	mu      sync.RWMutex
+	pairs   map[string]*models.DexPair // 交易对地址 => 交易对
+	byToken map[string][]*models.DexPair
+	synced  map[string]uint64
+}
+
+// NewMemoryPairStore 创建内存交易对存储
+func NewMemoryPairStore() PairStore {
+	return &memoryPairStore{
+		pairs:   make(map[string]*models.DexPair),
+		byToken: make(map[string][]*models.DexPair),
+		synced:  make(map[string]uint64),
+	}
+}
+
+// SavePairs 保存交易对
+func (m *memoryPairStore) SavePairs(pairs []*models.DexPair) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, pair := range pairs {
+		key := normalizeAddress(pair.PairAddress)
+		if _, ok := m.pairs[key]; ok {
+			continue
+		}
+		stored := *pair
+		m.pairs[key] = &stored
+		for _, token := range []string{stored.Token0, stored.Token1} {
+			token = normalizeAddress(token)
+			m.byToken[token] = append(m.byToken[token], &stored)
+		}
+	}
+	return nil
+}
+
+// GetPairsByToken 返回包含指定代币的所有交易对
+func (m *memoryPairStore) GetPairsByToken(token string) ([]*models.DexPair, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	return sortPairs(copyPairs(m.byToken[normalizeAddress(token)])), nil
+}
+
+// GetPairsSince 返回指定时间之后创建的交易对
+func (m *memoryPairStore) GetPairsSince(since time.Time) ([]*models.DexPair, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var pairs []*models.DexPair
+	for _, pair := range m.pairs {
+		if !pair.BlockTime.Before(since) {
+			pairs = append(pairs, pair)
+		}
+	}
+	return sortPairs(copyPairs(pairs)), nil
+}
+
+// GetSyncedBlock 返回工厂合约已同步到的区块
+func (m *memoryPairStore) GetSyncedBlock(factory string) (uint64, bool, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	block, ok := m.synced[normalizeAddress(factory)]
+	return block, ok, nil
+}
+
+// SetSyncedBlock 记录工厂合约已同步到的区块
+func (m *memoryPairStore) SetSyncedBlock(factory string, block uint64) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	m.synced[normalizeAddress(factory)] = block
+	return nil
+}
+
+// copyPairs 复制交易对，避免调用方修改存储中的数据
+func copyPairs(pairs []*models.DexPair) []*models.DexPair {
+	result := make([]*models.DexPair, 0, len(pairs))
+	for _, pair := range pairs {
+		copied := *pair
+		result = append(result, &copied)
+	}
+	return result
+}
+
+// sortPairs 按创建区块和工厂序号升序排列
+func sortPairs(pairs []*models.DexPair) []*models.DexPair {
+	sort.Slice(pairs, func(i, j int) bool {
+		if pairs[i].BlockNumber != pairs[j].BlockNumber {
+			return pairs[i].BlockNumber < pairs[j].BlockNumber
+		}
+		return pairs[i].PairIndex < pairs[j].PairIndex
+	})
+	return pairs
+}
+
+// dbPairStore 基于数据库的交易对存储
+type dbPairStore struct {
+	db *gorm.DB
+}
+
+// NewDBPairStore 创建数据库交易对存储，需要已迁移 models.DexPair 和 models.PairSyncState
+func NewDBPairStore(db *gorm.DB) PairStore {
+	return &dbPairStore{db: db}
+}
+
+// SavePairs 保存交易对
+func (d *dbPairStore) SavePairs(pairs []*models.DexPair) error {
+	if len(pairs) == 0 {
+		return nil
+	}
+	for _, pair := range pairs {
+		pair.PairAddress = normalizeAddress(pair.PairAddress)
+		pair.Token0 = normalizeAddress(pair.Token0)
+		pair.Token1 = normalizeAddress(pair.Token1)
+	}
+	return d.db.Clauses(clause.OnConflict{DoNothing: true}).Create(&pairs).Error
+}
+
+// GetPairsByToken 返回包含指定代币的所有交易对
+func (d *dbPairStore) GetPairsByToken(token string) ([]*models.DexPair, error) {
+	token = normalizeAddress(token)
+	var pairs []*models.DexPair
+	err := d.db.Where("token0 = ? OR token1 = ?", token, token).
+		Order("block_number ASC, pair_index ASC").
+		Find(&pairs).Error
+	return pairs, err
+}
+
+// GetPairsSince 返回指定时间之后创建的交易对
+func (d *dbPairStore) GetPairsSince(since time.Time) ([]*models.DexPair, error) {
+	var pairs []*models.DexPair
+	err := d.db.Where("block_time >= ?", since).
+		Order("block_number ASC, pair_index ASC").
+		Find(&pairs).Error
+	return pairs, err
+}
+
+// GetSyncedBlock 返回工厂合约已同步到的区块
+func (d *dbPairStore) GetSyncedBlock(factory string) (uint64, bool, error) {
+	var state models.PairSyncState
+	err := d.db.Where("factory = ?", normalizeAddress(factory)).First(&state).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return 0, false, nil
+	}
+	if err != nil {
+		return 0, false, err
+	}
+	return state.LastBlock, true, nil
+}
+
+// SetSyncedBlock 记录工厂合约已同步到的区块
+func (d *dbPairStore) SetSyncedBlock(factory string, block uint64) error {
+	state := models.PairSyncState{Factory: normalizeAddress(factory), LastBlock: block}
+	return d.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "factory"}},
+		DoUpdates: clause.AssignmentColumns([]string{"last_block", "updated_at"}),
+	}).Create(&state).Error
+}
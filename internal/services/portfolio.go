@@ -0,0 +1,502 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math/big"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"chain/internal/config"
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 持仓余额来源
+const (
+	HoldingSourceChain  = "chain"  // 链上balanceOf或BNB余额查询
+	HoldingSourceStored = "stored" // 数据库 token_balances 表中保存的余额
+)
+
+const (
+	defaultPortfolioMaxAddresses = 20
+	maxPortfolioExtraTokens      = 100 // 单次请求额外指定的代币数上限
+	bnbCoinID                    = "binancecoin"
+)
+
+// ErrInvalidPortfolioQuery 估值请求的地址或代币无效
+var ErrInvalidPortfolioQuery = errors.New("invalid portfolio query")
+
+// PortfolioHolding 一项资产的持仓和USD估值
+type PortfolioHolding struct {
+	Token                 string   `json:"token"` // 代币合约地址，BNB为空
+	Symbol                string   `json:"symbol"`
+	Name                  string   `json:"name"`
+	Decimals              uint8    `json:"decimals"`
+	Native                bool     `json:"native"`
+	Balance               string   `json:"balance"`     // 按精度换算的数量
+	BalanceRaw            string   `json:"balance_raw"` // 最小单位的数量
+	PriceUSD              float64  `json:"price_usd"`
+	PriceChangePercent24h float64  `json:"price_change_percent_24h"`
+	ValueUSD              float64  `json:"value_usd"`
+	ValueChange24h        float64  `json:"value_change_24h"`       // 按当前余额计算的24小时USD价值变化
+	PriceSource           string   `json:"price_source,omitempty"` // 产生价格的数据源，未能估值时为空
+	Sources               []string `json:"sources"`                // 余额来源：chain 或 stored
+
+	balance *big.Int
+}
+
+// AccountPortfolio 单个地址的持仓
+type AccountPortfolio struct {
+	Address               string              `json:"address"`
+	Holdings              []*PortfolioHolding `json:"holdings"`
+	TotalValueUSD         float64             `json:"total_value_usd"`
+	ValueChange24h        float64             `json:"value_change_24h"`
+	ValueChangePercent24h float64             `json:"value_change_percent_24h"`
+}
+
+// Portfolio 一个或多个地址合计的持仓估值
+type Portfolio struct {
+	Addresses             []string            `json:"addresses"`
+	Holdings              []*PortfolioHolding `json:"holdings"` // 所有地址按资产合计
+	Accounts              []*AccountPortfolio `json:"accounts"` // 各地址的持仓
+	TotalValueUSD         float64             `json:"total_value_usd"`
+	ValueChange24h        float64             `json:"value_change_24h"`
+	ValueChangePercent24h float64             `json:"value_change_percent_24h"`
+	Warnings              []string            `json:"warnings,omitempty"` // 查询失败的余额、代币信息或价格
+	UpdatedAt             time.Time           `json:"updated_at"`
+}
+
+// PortfolioService 地址资产估值服务，余额来自BSC节点和已保存的代币余额，价格来自DEX和行情数据源
+type PortfolioService struct {
+	bsc          *BSCService
+	tokenPrices  tokenPriceSource  // 代币DEX价格，默认为BSC服务
+	prices       *PriceService     // 行情数据源，为nil时只使用DEX价格
+	balances     TokenBalanceStore // 已保存的代币余额，为nil时只查询链上余额
+	tokens       []common.Address  // 链上查询余额的代币
+	maxAddresses int
+}
+
+// assetPrice 资产的USD价格
+type assetPrice struct {
+	usd           float64
+	changePercent float64
+	source        string
+}
+
+// NewPortfolioService 创建资产估值服务
+func NewPortfolioService(cfg *config.Config, bscService *BSCService, priceService *PriceService) *PortfolioService {
+	tokens := make([]common.Address, 0, len(cfg.Portfolio.Tokens))
+	for _, token := range cfg.Portfolio.Tokens {
+		if token = strings.TrimSpace(token); token != "" {
+			tokens = append(tokens, common.HexToAddress(token))
+		}
+	}
+	if len(tokens) == 0 {
+		for _, token := range defaultTokens {
+			tokens = append(tokens, common.HexToAddress(token.Address))
+		}
+	}
+
+	maxAddresses := defaultPortfolioMaxAddresses
+	if cfg.Portfolio.MaxAddresses > 0 {
+		maxAddresses = cfg.Portfolio.MaxAddresses
+	}
+
+	return &PortfolioService{
+		bsc:          bscService,
+		tokenPrices:  bscService,
+		prices:       priceService,
+		tokens:       tokens,
+		maxAddresses: maxAddresses,
+	}
+}
+
+// SetBalanceStore 设置已保存的代币余额，其中链上查询列表以外的代币也计入持仓
+func (p *PortfolioService) SetBalanceStore(store TokenBalanceStore) {
+	p.balances = store
+}
+
+// GetPortfolio 查询地址的BNB和代币持仓并按USD估值，多个地址合计为一个持仓
+// extraTokens 为本次额外在链上查询余额的代币；部分余额或价格查询失败时照常返回并在Warnings中说明
+func (p *PortfolioService) GetPortfolio(ctx context.Context, addresses []string, extraTokens []string) (*Portfolio, error) {
+	accounts, tokens, err := p.normalizeQuery(addresses, extraTokens)
+	if err != nil {
+		return nil, err
+	}
+
+	natives, err := p.bsc.GetNativeBalances(ctx, accounts)
+	if err != nil {
+		return nil, err
+	}
+
+	var (
+		mu       sync.Mutex
+		warnings []string
+	)
+	warn := func(format string, args ...interface{}) {
+		mu.Lock()
+		warnings = append(warnings, fmt.Sprintf(format, args...))
+		mu.Unlock()
+	}
+
+	// 各地址的链上代币余额，并发查询以合并为批量请求
+	chainBalances := make([][]*big.Int, len(accounts))
+	var wg sync.WaitGroup
+	for i, account := range accounts {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			balances, errs := p.bsc.GetTokenBalances(account, tokens)
+			for _, err := range errs {
+				if err != nil {
+					warn("%s: %v", account.Hex(), err)
+				}
+			}
+			chainBalances[i] = balances
+		}()
+	}
+	wg.Wait()
+
+	holdings := make([]map[string]*PortfolioHolding, len(accounts))
+	queried := make([]map[common.Address]bool, len(accounts)) // 各地址链上查询成功的代币，包括余额为0的代币
+	held := make(map[common.Address]bool)
+	for i := range accounts {
+		holdings[i] = map[string]*PortfolioHolding{
+			"": {Symbol: "BNB", Name: "BNB", Decimals: 18, Native: true, balance: natives[i], Sources: []string{HoldingSourceChain}},
+		}
+		queried[i] = make(map[common.Address]bool, len(tokens))
+		for j, balance := range chainBalances[i] {
+			if balance == nil {
+				continue
+			}
+			queried[i][tokens[j]] = true
+			if balance.Sign() == 0 {
+				continue
+			}
+			holdings[i][tokens[j].Hex()] = &PortfolioHolding{Token: tokens[j].Hex(), balance: balance, Sources: []string{HoldingSourceChain}}
+			held[tokens[j]] = true
+		}
+	}
+	for _, warning := range p.addStoredHoldings(accounts, queried, holdings) {
+		warn("%s", warning)
+	}
+
+	// 链上查询的代币读取名称、符号和精度
+	metadata := make(map[common.Address]*TokenInfo, len(held))
+	for token := range held {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			info, err := p.bsc.GetTokenInfo(token.Hex())
+			if err != nil {
+				warn("%s: %v", token.Hex(), err)
+				return
+			}
+			mu.Lock()
+			metadata[token] = info
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	for i := range accounts {
+		for key, holding := range holdings[i] {
+			if holding.Native || !slices.Contains(holding.Sources, HoldingSourceChain) {
+				continue
+			}
+			info := metadata[common.HexToAddress(key)]
+			if info == nil {
+				delete(holdings[i], key)
+				continue
+			}
+			holding.Symbol, holding.Name, holding.Decimals = info.Symbol, info.Name, info.Decimals
+		}
+	}
+
+	prices := p.assetPrices(ctx, holdings, warn)
+
+	portfolio := &Portfolio{UpdatedAt: time.Now().UTC()}
+	total := make(map[string]*PortfolioHolding)
+	for i, account := range accounts {
+		accountPortfolio := &AccountPortfolio{Address: account.Hex()}
+		for key, holding := range holdings[i] {
+			holding.value(prices[key])
+			accountPortfolio.Holdings = append(accountPortfolio.Holdings, holding)
+
+			merged, ok := total[key]
+			if !ok {
+				copied := *holding
+				copied.balance = new(big.Int)
+				copied.Sources = nil
+				merged = &copied
+				total[key] = merged
+			}
+			merged.balance.Add(merged.balance, holding.balance)
+			merged.Sources = mergeSources(merged.Sources, holding.Sources)
+		}
+		accountPortfolio.TotalValueUSD, accountPortfolio.ValueChange24h, accountPortfolio.ValueChangePercent24h = sumHoldings(accountPortfolio.Holdings)
+		sortHoldings(accountPortfolio.Holdings)
+
+		portfolio.Addresses = append(portfolio.Addresses, account.Hex())
+		portfolio.Accounts = append(portfolio.Accounts, accountPortfolio)
+	}
+	for key, holding := range total {
+		holding.value(prices[key])
+		portfolio.Holdings = append(portfolio.Holdings, holding)
+	}
+	portfolio.TotalValueUSD, portfolio.ValueChange24h, portfolio.ValueChangePercent24h = sumHoldings(portfolio.Holdings)
+	sortHoldings(portfolio.Holdings)
+
+	sort.Strings(warnings)
+	portfolio.Warnings = warnings
+	return portfolio, nil
+}
+
+// normalizeQuery 校验并去重地址和额外代币，返回账户和链上查询余额的代币
+func (p *PortfolioService) normalizeQuery(addresses []string, extraTokens []string) ([]common.Address, []common.Address, error) {
+	var accounts []common.Address
+	seen := make(map[common.Address]bool)
+	for _, address := range addresses {
+		address = strings.TrimSpace(address)
+		if !common.IsHexAddress(address) {
+			return nil, nil, fmt.Errorf("%w: invalid address: %s", ErrInvalidPortfolioQuery, address)
+		}
+		account := common.HexToAddress(address)
+		if !seen[account] {
+			seen[account] = true
+			accounts = append(accounts, account)
+		}
+	}
+	if len(accounts) == 0 {
+		return nil, nil, fmt.Errorf("%w: at least one address is required", ErrInvalidPortfolioQuery)
+	}
+	if len(accounts) > p.maxAddresses {
+		return nil, nil, fmt.Errorf("%w: at most %d addresses are allowed", ErrInvalidPortfolioQuery, p.maxAddresses)
+	}
+	if len(extraTokens) > maxPortfolioExtraTokens {
+		return nil, nil, fmt.Errorf("%w: at most %d extra tokens are allowed", ErrInvalidPortfolioQuery, maxPortfolioExtraTokens)
+	}
+
+	tokens := append([]common.Address(nil), p.tokens...)
+	seen = make(map[common.Address]bool, len(tokens))
+	for _, token := range tokens {
+		seen[token] = true
+	}
+	for _, token := range extraTokens {
+		token = strings.TrimSpace(token)
+		if !common.IsHexAddress(token) {
+			return nil, nil, fmt.Errorf("%w: invalid token address: %s", ErrInvalidPortfolioQuery, token)
+		}
+		addr := common.HexToAddress(token)
+		if !seen[addr] {
+			seen[addr] = true
+			tokens = append(tokens, addr)
+		}
+	}
+	return accounts, tokens, nil
+}
+
+// addStoredHoldings 加入已保存的代币余额，链上查询成功的代币（包括余额为0的）以链上余额为准，返回无法读取的余额
+func (p *PortfolioService) addStoredHoldings(accounts []common.Address, queried []map[common.Address]bool, holdings []map[string]*PortfolioHolding) []string {
+	if p.balances == nil {
+		return nil
+	}
+	stored, err := p.balances.GetTokenBalances(p.bsc.chainID.Uint64(), accounts)
+	if err != nil {
+		return []string{fmt.Sprintf("failed to load stored token balances: %v", err)}
+	}
+
+	index := make(map[common.Address]int, len(accounts))
+	for i, account := range accounts {
+		index[account] = i
+	}
+	var warnings []string
+	for _, balance := range stored {
+		if !common.IsHexAddress(balance.Token.Address) {
+			continue
+		}
+		i, ok := index[common.HexToAddress(balance.Account.Address)]
+		if !ok {
+			continue
+		}
+		token := common.HexToAddress(balance.Token.Address)
+		amount, err := storedBalanceAmount(balance)
+		if err != nil {
+			warnings = append(warnings, fmt.Sprintf("%s: stored balance of %s: %v", accounts[i].Hex(), token.Hex(), err))
+			continue
+		}
+		if existing, ok := holdings[i][token.Hex()]; ok {
+			existing.Sources = mergeSources(existing.Sources, []string{HoldingSourceStored})
+			continue
+		}
+		if queried[i][token] || amount.Sign() == 0 {
+			continue
+		}
+		holdings[i][token.Hex()] = &PortfolioHolding{
+			Token:    token.Hex(),
+			Symbol:   balance.Token.Symbol,
+			Name:     balance.Token.Name,
+			Decimals: balance.Token.Decimals,
+			balance:  amount,
+			Sources:  []string{HoldingSourceStored},
+		}
+	}
+	return warnings
+}
+
+// storedBalanceAmount 解析保存的余额，整数为最小单位，带小数点时按代币精度换算
+func storedBalanceAmount(balance *models.TokenBalance) (*big.Int, error) {
+	value := strings.TrimSpace(balance.Balance)
+	if strings.Contains(value, ".") {
+		return parseUnits(value, balance.Token.Decimals)
+	}
+	amount, ok := new(big.Int).SetString(value, 10)
+	if !ok || amount.Sign() < 0 {
+		return nil, fmt.Errorf("invalid balance: %s", balance.Balance)
+	}
+	return amount, nil
+}
+
+// assetPrices 并发查询持仓涉及资产的USD价格，以持仓键为键，BNB的键为空
+func (p *PortfolioService) assetPrices(ctx context.Context, holdings []map[string]*PortfolioHolding, warn func(string, ...interface{})) map[string]*assetPrice {
+	keys := make(map[string]bool)
+	for _, accountHoldings := range holdings {
+		for key := range accountHoldings {
+			keys[key] = true
+		}
+	}
+
+	var (
+		mu     sync.Mutex
+		wg     sync.WaitGroup
+		prices = make(map[string]*assetPrice, len(keys))
+	)
+	for key := range keys {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			var (
+				price *assetPrice
+				err   error
+			)
+			if key == "" {
+				price, err = p.nativePrice(ctx)
+			} else {
+				price, err = p.tokenPrice(ctx, key)
+			}
+			if err != nil {
+				asset := key
+				if asset == "" {
+					asset = "BNB"
+				}
+				warn("failed to price %s: %v", asset, err)
+				return
+			}
+			mu.Lock()
+			prices[key] = price
+			mu.Unlock()
+		}()
+	}
+	wg.Wait()
+	return prices
+}
+
+// nativePrice 查询BNB价格，优先使用行情数据源，失败时使用WBNB的DEX价格
+func (p *PortfolioService) nativePrice(ctx context.Context) (*assetPrice, error) {
+	if p.prices != nil {
+		price, err := p.prices.GetCryptoPrice(ctx, bnbCoinID)
+		if err == nil && price.CurrentPrice > 0 {
+			return &assetPrice{usd: price.CurrentPrice, changePercent: price.PriceChangePercent24h, source: price.Source}, nil
+		}
+	}
+	return p.dexPrice(WBNBAddress)
+}
+
+// tokenPrice 查询代币价格，优先使用DEX价格，没有流动性时按合约地址查询行情数据源
+func (p *PortfolioService) tokenPrice(ctx context.Context, token string) (*assetPrice, error) {
+	price, err := p.dexPrice(token)
+	if err == nil {
+		return price, nil
+	}
+	if p.prices != nil {
+		if info, priceErr := p.prices.GetCryptoPrice(ctx, token); priceErr == nil && info.CurrentPrice > 0 {
+			return &assetPrice{usd: info.CurrentPrice, changePercent: info.PriceChangePercent24h, source: info.Source}, nil
+		}
+	}
+	return nil, err
+}
+
+// dexPrice 查询代币的DEX USD价格
+func (p *PortfolioService) dexPrice(token string) (*assetPrice, error) {
+	price, err := p.tokenPrices.GetTokenPrice(token, "")
+	if err != nil {
+		return nil, err
+	}
+	info := dexPriceInfo(price)
+	if info == nil {
+		return nil, fmt.Errorf("no USD price for %s", token)
+	}
+	return &assetPrice{usd: info.CurrentPrice, changePercent: info.PriceChangePercent24h, source: PriceProviderBSC}, nil
+}
+
+// value 按价格计算持仓的数量和USD价值，price为nil时只填充数量
+func (h *PortfolioHolding) value(price *assetPrice) {
+	h.Balance = formatUnits(h.balance, h.Decimals)
+	h.BalanceRaw = h.balance.String()
+	if price == nil {
+		return
+	}
+
+	amount, _ := new(big.Rat).SetFrac(h.balance, pow10(h.Decimals)).Float64()
+	h.PriceUSD = price.usd
+	h.PriceChangePercent24h = price.changePercent
+	h.PriceSource = price.source
+	h.ValueUSD = amount * price.usd
+	h.ValueChange24h = changeFromPercent(h.ValueUSD, price.changePercent)
+}
+
+// sumHoldings 合计持仓的USD价值和24小时变化，变化百分比相对24小时前的价值
+func sumHoldings(holdings []*PortfolioHolding) (float64, float64, float64) {
+	var total, change float64
+	for _, holding := range holdings {
+		total += holding.ValueUSD
+		change += holding.ValueChange24h
+	}
+	var percent float64
+	if previous := total - change; previous > 0 {
+		percent = change / previous * 100
+	}
+	return total, change, percent
+}
+
+// sortHoldings 按USD价值降序排列，价值相同时BNB在前，其余按符号排列
+func sortHoldings(holdings []*PortfolioHolding) {
+	sort.Slice(holdings, func(i, j int) bool {
+		a, b := holdings[i], holdings[j]
+		if a.ValueUSD != b.ValueUSD {
+			return a.ValueUSD > b.ValueUSD
+		}
+		if a.Native != b.Native {
+			return a.Native
+		}
+		if a.Symbol != b.Symbol {
+			return a.Symbol < b.Symbol
+		}
+		return a.Token < b.Token
+	})
+}
+
+// mergeSources 合并余额来源并去重
+func mergeSources(sources []string, added []string) []string {
+	for _, source := range added {
+		if !slices.Contains(sources, source) {
+			sources = append(sources, source)
+		}
+	}
+	return sources
+}
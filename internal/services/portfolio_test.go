@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"math/big"
+	"strings"
+	"testing"
+
+	"chain/internal/config"
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// wholeTokens 返回指定数量的完整代币（18位精度）
+func wholeTokens(amount int64) *big.Int {
+	return new(big.Int).Mul(big.NewInt(amount), pow10(18))
+}
+
+// newTestPortfolioService 创建连接到模拟链的估值服务，链上查询WBNB、USDT和CAKE的余额
+func newTestPortfolioService(chain *fakeChain, provider *stubPriceProvider) *PortfolioService {
+	cfg := &config.Config{Portfolio: config.PortfolioConfig{
+		Tokens:       []string{WBNBAddress, USDTAddress, CAKEAddress},
+		MaxAddresses: 2,
+	}}
+	service := NewPortfolioService(cfg, newTestBSCService(chain), newPriceServiceWithProviders("", provider))
+	service.tokenPrices = stubTokenPrices{
+		strings.ToLower(WBNBAddress): {TokenSymbol: "WBNB", PriceInUSD: "590", PriceChange24h: "0"},
+		strings.ToLower(USDTAddress): {TokenSymbol: "USDT", PriceInUSD: "1", PriceChange24h: "0"},
+		strings.ToLower(CAKEAddress): {TokenSymbol: "Cake", PriceInUSD: "2", PriceChange24h: "25"},
+	}
+	return service
+}
+
+func TestGetPortfolio(t *testing.T) {
+	chain := newFakeChain()
+	chain.addToken(WBNBAddress, "Wrapped BNB", "WBNB", 18)
+	usdt := chain.addToken(USDTAddress, "Tether USD", "USDT", 18)
+	cake := chain.addToken(CAKEAddress, "PancakeSwap Token", "Cake", 18)
+
+	alice := common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	bob := common.HexToAddress("0x0000000000000000000000000000000000000b0b")
+	chain.nativeBalances[alice] = wholeTokens(2)
+	chain.nativeBalances[bob] = wholeTokens(1)
+	chain.tokens[cake].balances[alice] = wholeTokens(100)
+	chain.tokens[usdt].balances[alice] = wholeTokens(50)
+	chain.tokens[cake].balances[bob] = wholeTokens(10)
+
+	service := newTestPortfolioService(chain, &stubPriceProvider{name: "primary", prices: map[string]float64{bnbCoinID: 600}})
+	// bob在数据库中还保存了代币列表以外的代币，以及链上已查询的CAKE和链上余额为0的USDT
+	unlisted := "0x00000000000000000000000000000000000000Ee"
+	service.SetBalanceStore(NewMemoryTokenBalanceStore([]*models.TokenBalance{
+		{Balance: "5.5", ChainID: 56, Account: models.Account{Address: strings.ToLower(bob.Hex())}, Token: models.Token{Address: unlisted, Symbol: "XT", Decimals: 18}},
+		{Balance: "1", ChainID: 56, Account: models.Account{Address: bob.Hex()}, Token: models.Token{Address: CAKEAddress, Symbol: "Cake", Decimals: 18}},
+		{Balance: "1", ChainID: 1, Account: models.Account{Address: bob.Hex()}, Token: models.Token{Address: USDTAddress, Decimals: 18}},
+		{Balance: "7", ChainID: 56, Account: models.Account{Address: bob.Hex()}, Token: models.Token{Address: USDTAddress, Symbol: "USDT", Decimals: 18}},
+	}))
+
+	portfolio, err := service.GetPortfolio(context.Background(), []string{alice.Hex(), strings.ToLower(bob.Hex()), alice.Hex()}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{alice.Hex(), bob.Hex()}, portfolio.Addresses)
+	require.Len(t, portfolio.Accounts, 2)
+
+	// alice: 2 BNB * 600 + 100 CAKE * 2 + 50 USDT
+	aliceHoldings := portfolio.Accounts[0].Holdings
+	require.Len(t, aliceHoldings, 3)
+	assert.True(t, aliceHoldings[0].Native)
+	assert.Equal(t, "2", aliceHoldings[0].Balance)
+	assert.Equal(t, 1200.0, aliceHoldings[0].ValueUSD)
+	assert.Equal(t, "primary", aliceHoldings[0].PriceSource)
+	assert.Equal(t, "Cake", aliceHoldings[1].Symbol)
+	assert.Equal(t, 200.0, aliceHoldings[1].ValueUSD)
+	assert.InDelta(t, 40.0, aliceHoldings[1].ValueChange24h, 1e-9)
+	assert.Equal(t, PriceProviderBSC, aliceHoldings[1].PriceSource)
+	assert.Equal(t, 1450.0, portfolio.Accounts[0].TotalValueUSD)
+
+	// bob: 链上USDT余额为0，不使用保存的余额；保存的未上市代币无法估值，价值计为0
+	bobHoldings := portfolio.Accounts[1].Holdings
+	require.Len(t, bobHoldings, 3)
+	assert.Equal(t, 620.0, portfolio.Accounts[1].TotalValueUSD)
+	assert.Equal(t, []string{HoldingSourceChain, HoldingSourceStored}, bobHoldings[1].Sources)
+	assert.Equal(t, "10", bobHoldings[1].Balance)
+	assert.Equal(t, "XT", bobHoldings[2].Symbol)
+	assert.Equal(t, "5.5", bobHoldings[2].Balance)
+	assert.Equal(t, []string{HoldingSourceStored}, bobHoldings[2].Sources)
+	assert.Zero(t, bobHoldings[2].ValueUSD)
+	assert.Empty(t, bobHoldings[2].PriceSource)
+
+	// 合计
+	require.Len(t, portfolio.Holdings, 4)
+	assert.Equal(t, "3", portfolio.Holdings[0].Balance)
+	assert.Equal(t, 1800.0, portfolio.Holdings[0].ValueUSD)
+	assert.Equal(t, "110", portfolio.Holdings[1].Balance)
+	assert.Equal(t, 2070.0, portfolio.TotalValueUSD)
+	assert.InDelta(t, 44.0, portfolio.ValueChange24h, 1e-9)
+	assert.InDelta(t, 44.0/(2070.0-44.0)*100, portfolio.ValueChangePercent24h, 1e-9)
+	require.Len(t, portfolio.Warnings, 1)
+	assert.Contains(t, portfolio.Warnings[0], "failed to price "+common.HexToAddress(unlisted).Hex())
+}
+
+func TestGetPortfolioFallbacks(t *testing.T) {
+	chain := newFakeChain()
+	chain.addToken(WBNBAddress, "Wrapped BNB", "WBNB", 18)
+	chain.addToken(USDTAddress, "Tether USD", "USDT", 18)
+	chain.addToken(CAKEAddress, "PancakeSwap Token", "Cake", 18)
+	alice := common.HexToAddress("0x00000000000000000000000000000000000a11ce")
+	chain.nativeBalances[alice] = wholeTokens(1)
+
+	// 行情数据源不可用时BNB使用WBNB的DEX价格，无合约的额外代币报告余额查询失败
+	service := newTestPortfolioService(chain, &stubPriceProvider{name: "primary", err: errors.New("connection refused")})
+	missing := common.HexToAddress("0x00000000000000000000000000000000000000dd")
+	portfolio, err := service.GetPortfolio(context.Background(), []string{alice.Hex()}, []string{missing.Hex()})
+	require.NoError(t, err)
+	require.Len(t, portfolio.Holdings, 1)
+	assert.Equal(t, 590.0, portfolio.Holdings[0].ValueUSD)
+	assert.Equal(t, PriceProviderBSC, portfolio.Holdings[0].PriceSource)
+	require.Len(t, portfolio.Warnings, 1)
+	assert.Contains(t, portfolio.Warnings[0], "failed to get balance of "+missing.Hex())
+}
+
+func TestGetPortfolioInvalidQuery(t *testing.T) {
+	service := newTestPortfolioService(newFakeChain(), &stubPriceProvider{name: "primary"})
+
+	tests := []struct {
+		addresses []string
+		tokens    []string
+		err       string
+	}{
+		{nil, nil, "at least one address is required"},
+		{[]string{"0x123"}, nil, "invalid address: 0x123"},
+		{[]string{"0x00000000000000000000000000000000000000a1", "0x00000000000000000000000000000000000000a2", "0x00000000000000000000000000000000000000a3"}, nil, "at most 2 addresses"},
+		{[]string{"0x00000000000000000000000000000000000000a1"}, []string{"cake"}, "invalid token address: cake"},
+	}
+	for _, tt := range tests {
+		_, err := service.GetPortfolio(context.Background(), tt.addresses, tt.tokens)
+		assert.ErrorIs(t, err, ErrInvalidPortfolioQuery)
+		assert.ErrorContains(t, err, tt.err)
+	}
+}
@@ -0,0 +1,225 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chain/internal/models"
+)
+
+const (
+	defaultBinanceURL = "https://api.binance.com"
+	binanceQuoteAsset = "USDT" // 以USDT交易对的价格作为USD价格
+	binanceMaxKlines  = 1000   // 单次K线请求的最大条数
+)
+
+// binanceAssetAliases 常见CoinGecko币种ID对应的Binance资产符号
+var binanceAssetAliases = map[string]string{
+	"bitcoin":           "BTC",
+	"ethereum":          "ETH",
+	"binancecoin":       "BNB",
+	"ripple":            "XRP",
+	"cardano":           "ADA",
+	"solana":            "SOL",
+	"dogecoin":          "DOGE",
+	"polkadot":          "DOT",
+	"tron":              "TRX",
+	"litecoin":          "LTC",
+	"chainlink":         "LINK",
+	"avalanche-2":       "AVAX",
+	"matic-network":     "MATIC",
+	"pancakeswap-token": "CAKE",
+}
+
+// binanceTicker Binance /api/v3/ticker/24hr 响应
+type binanceTicker struct {
+	Symbol             string `json:"symbol"`
+	LastPrice          string `json:"lastPrice"`
+	PriceChange        string `json:"priceChange"`
+	PriceChangePercent string `json:"priceChangePercent"`
+	QuoteVolume        string `json:"quoteVolume"`
+	CloseTime          int64  `json:"closeTime"`
+}
+
+// binanceProvider Binance公共行情数据源，ids为资产符号（如 BTC）或常见CoinGecko币种ID
+// 不提供市值，不支持市值排名和搜索
+type binanceProvider struct {
+	baseURL    string
+	httpClient *http.Client
+}
+
+// newBinanceProvider 创建Binance数据源，baseURL为空时使用官方API
+func newBinanceProvider(baseURL string, client *http.Client) *binanceProvider {
+	if baseURL == "" {
+		baseURL = defaultBinanceURL
+	}
+	return &binanceProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		httpClient: client,
+	}
+}
+
+func (b *binanceProvider) Name() string {
+	return PriceProviderBinance
+}
+
+// binanceAsset 将请求标识转换为Binance资产符号
+func binanceAsset(id string) string {
+	if asset, ok := binanceAssetAliases[strings.ToLower(id)]; ok {
+		return asset
+	}
+	return strings.ToUpper(id)
+}
+
+// coinKey 常见币种使用别名表中的资产符号，其余使用币种符号
+func (b *binanceProvider) coinKey(coin *models.Coin) string {
+	if asset, ok := binanceAssetAliases[coin.CoinID]; ok {
+		return asset
+	}
+	return strings.ToUpper(coin.Symbol)
+}
+
+// GetPrices 并发查询各币种USDT交易对的24小时行情，不存在的交易对不出现在结果中
+func (b *binanceProvider) GetPrices(ctx context.Context, ids []string) (map[string]*CryptoPriceInfo, error) {
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		result   = make(map[string]*CryptoPriceInfo, len(ids))
+		firstErr error
+	)
+	for _, id := range ids {
+		asset := binanceAsset(id)
+		if asset == binanceQuoteAsset {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			var ticker binanceTicker
+			err := fetchJSON(ctx, b.httpClient, PriceProviderBinance, b.baseURL+"/api/v3/ticker/24hr?symbol="+url.QueryEscape(asset+binanceQuoteAsset), nil, &ticker)
+
+			mu.Lock()
+			defer mu.Unlock()
+			var apiErr *priceAPIError
+			switch {
+			case errors.As(err, &apiErr) && apiErr.StatusCode == http.StatusBadRequest:
+				// 交易对不存在
+			case err != nil:
+				if firstErr == nil {
+					firstErr = err
+				}
+			default:
+				result[id] = ticker.toPriceInfo(asset)
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(result) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return result, nil
+}
+
+// GetTopPrices Binance不提供市值数据
+func (b *binanceProvider) GetTopPrices(ctx context.Context, limit int) ([]*CryptoPriceInfo, error) {
+	return nil, ErrPriceNotSupported
+}
+
+// Search Binance不提供币种搜索
+func (b *binanceProvider) Search(ctx context.Context, query string) ([]*CryptoPriceInfo, error) {
+	return nil, ErrPriceNotSupported
+}
+
+// GetPriceHistory 由USDT交易对的K线得到USD价格历史，K线周期与查询周期一致
+// 超过单次请求上限时按时间分页查询；只支持以USD计价
+func (b *binanceProvider) GetPriceHistory(ctx context.Context, id string, query HistoryQuery) (*HistoryData, error) {
+	if query.Currency != CurrencyUSD {
+		return nil, ErrPriceNotSupported
+	}
+	interval, _ := HistoryInterval(query.Interval)
+
+	data := &HistoryData{}
+	start := query.From.UnixMilli()
+	for start <= query.To.UnixMilli() {
+		var klines [][]interface{}
+		endpoint := fmt.Sprintf("%s/api/v3/klines?symbol=%s&interval=%s&startTime=%d&endTime=%d&limit=%d",
+			b.baseURL, url.QueryEscape(binanceAsset(id)+binanceQuoteAsset), query.Interval, start, query.To.UnixMilli(), binanceMaxKlines)
+		if err := fetchJSON(ctx, b.httpClient, PriceProviderBinance, endpoint, nil, &klines); err != nil {
+			return nil, err
+		}
+
+		for _, kline := range klines {
+			if candle, ok := parseBinanceKline(kline); ok {
+				data.Candles = append(data.Candles, candle)
+				// 价格点的时间为K线收盘时间，未收盘的K线取查询的结束时间
+				timestamp := candle.Timestamp.Add(interval)
+				if timestamp.After(query.To) {
+					timestamp = query.To
+				}
+				data.Points = append(data.Points, &PricePoint{Timestamp: timestamp, Price: candle.Close, Volume: candle.Volume})
+			}
+		}
+		if len(klines) < binanceMaxKlines || len(data.Candles) == 0 {
+			break
+		}
+		start = data.Candles[len(data.Candles)-1].Timestamp.Add(interval).UnixMilli()
+	}
+	return data, nil
+}
+
+// parseBinanceKline 解析K线 [开盘时间, 开盘价, 最高价, 最低价, 收盘价, 成交量, 收盘时间, 成交额, ...]
+func parseBinanceKline(kline []interface{}) (*HistoryCandle, bool) {
+	if len(kline) < 8 {
+		return nil, false
+	}
+	openTime, ok := kline[0].(float64)
+	if !ok {
+		return nil, false
+	}
+
+	var values [5]float64 // 开、高、低、收、成交额
+	for i, index := range []int{1, 2, 3, 4, 7} {
+		str, _ := kline[index].(string)
+		value, err := strconv.ParseFloat(str, 64)
+		if err != nil {
+			return nil, false
+		}
+		values[i] = value
+	}
+	return &HistoryCandle{
+		Timestamp: time.UnixMilli(int64(openTime)).UTC(),
+		Open:      values[0],
+		High:      values[1],
+		Low:       values[2],
+		Close:     values[3],
+		Volume:    values[4],
+	}, true
+}
+
+// toPriceInfo 转换为价格信息
+func (t *binanceTicker) toPriceInfo(asset string) *CryptoPriceInfo {
+	price, _ := strconv.ParseFloat(t.LastPrice, 64)
+	change, _ := strconv.ParseFloat(t.PriceChange, 64)
+	changePercent, _ := strconv.ParseFloat(t.PriceChangePercent, 64)
+	volume, _ := strconv.ParseFloat(t.QuoteVolume, 64)
+
+	return &CryptoPriceInfo{
+		Symbol:                strings.ToLower(asset),
+		Name:                  asset,
+		CurrentPrice:          price,
+		Volume24h:             volume,
+		PriceChange24h:        change,
+		PriceChangePercent24h: changePercent,
+		LastUpdated:           time.UnixMilli(t.CloseTime).UTC(),
+	}
+}
@@ -0,0 +1,72 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestBinanceProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		query := r.URL.Query()
+		switch r.URL.Path {
+		case "/api/v3/ticker/24hr":
+			if query.Get("symbol") != "BTCUSDT" {
+				w.WriteHeader(http.StatusBadRequest)
+				w.Write([]byte(`{"code":-1121,"msg":"Invalid symbol."}`))
+				return
+			}
+			json.NewEncoder(w).Encode(map[string]interface{}{
+				"symbol": "BTCUSDT", "lastPrice": "65000.50", "priceChange": "1300.00",
+				"priceChangePercent": "2.04", "quoteVolume": "1500000000.5", "closeTime": 1767225600000,
+			})
+		case "/api/v3/klines":
+			assert.Equal(t, "BTCUSDT", query.Get("symbol"))
+			assert.Equal(t, "1h", query.Get("interval"))
+			assert.Equal(t, "1767225600000", query.Get("startTime"))
+			assert.Equal(t, "1767232800000", query.Get("endTime"))
+			w.Write([]byte(`[[1767225600000,"64000","64100","63900","64050","10",1767229199999,"640500"],[1767229200000,"64050","65100","64000","65000","12",1767232799999,"780000"]]`))
+		}
+	}))
+	defer server.Close()
+
+	provider := newBinanceProvider(server.URL, server.Client())
+
+	// CoinGecko币种ID和资产符号都可以识别，不存在的交易对被忽略
+	prices, err := provider.GetPrices(context.Background(), []string{"bitcoin", "BTC", "NOPE", "usdt"})
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+
+	btc := prices["bitcoin"]
+	assert.Equal(t, "btc", btc.Symbol)
+	assert.Equal(t, 65000.5, btc.CurrentPrice)
+	assert.Equal(t, 1300.0, btc.PriceChange24h)
+	assert.Equal(t, 2.04, btc.PriceChangePercent24h)
+	assert.Equal(t, 1500000000.5, btc.Volume24h)
+	assert.Equal(t, int64(1767225600), btc.LastUpdated.Unix())
+	assert.Equal(t, btc, prices["BTC"])
+
+	from := time.Unix(1767225600, 0).UTC()
+	query := HistoryQuery{From: from, To: from.Add(2 * time.Hour), Interval: "1h", Currency: CurrencyUSD}
+	history, err := provider.GetPriceHistory(context.Background(), "bitcoin", query)
+	require.NoError(t, err)
+	require.Len(t, history.Candles, 2)
+	assert.Equal(t, &HistoryCandle{Timestamp: from, Open: 64000, High: 64100, Low: 63900, Close: 64050, Volume: 640500}, history.Candles[0])
+	require.Len(t, history.Points, 2)
+	assert.Equal(t, from.Add(time.Hour), history.Points[0].Timestamp)
+	assert.Equal(t, 65000.0, history.Points[1].Price)
+
+	// 只有USDT交易对，其他计价货币交由换算处理
+	query.Currency = CurrencyEUR
+	_, err = provider.GetPriceHistory(context.Background(), "bitcoin", query)
+	assert.ErrorIs(t, err, ErrPriceNotSupported)
+
+	_, err = provider.GetTopPrices(context.Background(), 10)
+	assert.ErrorIs(t, err, ErrPriceNotSupported)
+}
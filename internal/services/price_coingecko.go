@@ -0,0 +1,294 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+const (
+	defaultCoinGeckoURL      = "https://api.coingecko.com/api/v3"
+	coinGeckoBSCPlatform     = "binance-smart-chain" // CoinGecko中BSC链的平台ID
+	coinGeckoMarketsPageSize = 250                   // /coins/markets 每页最大数量
+)
+
+// CoinGeckoPriceResponse CoinGecko API响应
+type CoinGeckoPriceResponse struct {
+	ID                    string  `json:"id"`
+	Symbol                string  `json:"symbol"`
+	Name                  string  `json:"name"`
+	CurrentPrice          float64 `json:"current_price"`
+	MarketCap             float64 `json:"market_cap"`
+	MarketCapRank         int     `json:"market_cap_rank"`
+	TotalVolume           float64 `json:"total_volume"`
+	High24h               float64 `json:"high_24h"`
+	Low24h                float64 `json:"low_24h"`
+	PriceChange24h        float64 `json:"price_change_24h"`
+	PriceChangePercent24h float64 `json:"price_change_percentage_24h"`
+	LastUpdated           string  `json:"last_updated"`
+}
+
+// coinGeckoProvider CoinGecko行情数据源，ids为CoinGecko币种ID，如 bitcoin
+type coinGeckoProvider struct {
+	baseURL    string // 不含末尾的 /
+	apiKey     string // CoinGecko Pro API Key，可选
+	httpClient *http.Client
+}
+
+// newCoinGeckoProvider 创建CoinGecko数据源，baseURL为空时使用公共API
+func newCoinGeckoProvider(baseURL, apiKey string, client *http.Client) *coinGeckoProvider {
+	if baseURL == "" {
+		baseURL = defaultCoinGeckoURL
+	}
+	return &coinGeckoProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: client,
+	}
+}
+
+func (c *coinGeckoProvider) Name() string {
+	return PriceProviderCoinGecko
+}
+
+// get 请求CoinGecko API
+func (c *coinGeckoProvider) get(ctx context.Context, path string, out interface{}) error {
+	header := http.Header{}
+	if c.apiKey != "" {
+		header.Set("x-cg-pro-api-key", c.apiKey)
+	}
+	return fetchJSON(ctx, c.httpClient, PriceProviderCoinGecko, c.baseURL+path, header, out)
+}
+
+// GetPrices 通过 /coins/markets 批量查询价格
+func (c *coinGeckoProvider) GetPrices(ctx context.Context, ids []string) (map[string]*CryptoPriceInfo, error) {
+	requested := make(map[string]string, len(ids))
+	lowered := make([]string, 0, len(ids))
+	for _, id := range ids {
+		requested[strings.ToLower(id)] = id
+		lowered = append(lowered, strings.ToLower(id))
+	}
+
+	var prices []CoinGeckoPriceResponse
+	path := fmt.Sprintf("/coins/markets?vs_currency=usd&ids=%s&order=market_cap_desc&per_page=%d&page=1", url.QueryEscape(strings.Join(lowered, ",")), len(ids))
+	if err := c.get(ctx, path, &prices); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*CryptoPriceInfo, len(prices))
+	for i := range prices {
+		if id, ok := requested[prices[i].ID]; ok {
+			result[id] = prices[i].toPriceInfo()
+		}
+	}
+	return result, nil
+}
+
+// GetQuotes 通过 /simple/price 查询各币种以多种货币计价的价格
+func (c *coinGeckoProvider) GetQuotes(ctx context.Context, ids []string, currencies []string) (map[string]map[string]*PriceQuote, error) {
+	lowered := make([]string, 0, len(ids))
+	for _, id := range ids {
+		lowered = append(lowered, strings.ToLower(id))
+	}
+
+	// 币种ID => 字段（如 eur、eur_market_cap、eur_24h_vol、eur_24h_change）=> 数值
+	var prices map[string]map[string]float64
+	path := fmt.Sprintf("/simple/price?ids=%s&vs_currencies=%s&include_market_cap=true&include_24hr_vol=true&include_24hr_change=true",
+		url.QueryEscape(strings.Join(lowered, ",")), url.QueryEscape(strings.Join(currencies, ",")))
+	if err := c.get(ctx, path, &prices); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]map[string]*PriceQuote, len(prices))
+	for _, id := range ids {
+		fields, ok := prices[strings.ToLower(id)]
+		if !ok {
+			continue
+		}
+		quotes := make(map[string]*PriceQuote, len(currencies))
+		for _, currency := range currencies {
+			price, ok := fields[currency]
+			if !ok {
+				continue
+			}
+			changePercent := fields[currency+"_24h_change"]
+			quotes[currency] = &PriceQuote{
+				Price:                 price,
+				MarketCap:             fields[currency+"_market_cap"],
+				Volume24h:             fields[currency+"_24h_vol"],
+				PriceChange24h:        changeFromPercent(price, changePercent),
+				PriceChangePercent24h: changePercent,
+			}
+		}
+		result[id] = quotes
+	}
+	return result, nil
+}
+
+// GetTopPrices 查询市值排名前limit的币种
+func (c *coinGeckoProvider) GetTopPrices(ctx context.Context, limit int) ([]*CryptoPriceInfo, error) {
+	var prices []CoinGeckoPriceResponse
+	path := fmt.Sprintf("/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=%d&page=1", limit)
+	if err := c.get(ctx, path, &prices); err != nil {
+		return nil, err
+	}
+
+	result := make([]*CryptoPriceInfo, 0, len(prices))
+	for i := range prices {
+		result = append(result, prices[i].toPriceInfo())
+	}
+	return result, nil
+}
+
+// Search 通过 /search 查找币种，再查询前5个结果的价格
+func (c *coinGeckoProvider) Search(ctx context.Context, query string) ([]*CryptoPriceInfo, error) {
+	var searchResult struct {
+		Coins []struct {
+			ID     string `json:"id"`
+			Name   string `json:"name"`
+			Symbol string `json:"symbol"`
+		} `json:"coins"`
+	}
+	if err := c.get(ctx, "/search?query="+url.QueryEscape(query), &searchResult); err != nil {
+		return nil, err
+	}
+	if len(searchResult.Coins) == 0 {
+		return []*CryptoPriceInfo{}, nil
+	}
+
+	var ids []string
+	for i, coin := range searchResult.Coins {
+		if i >= 5 { // 限制结果数量
+			break
+		}
+		ids = append(ids, coin.ID)
+	}
+
+	prices, err := c.GetPrices(ctx, ids)
+	if err != nil {
+		return nil, err
+	}
+
+	// 按搜索结果的相关度排序
+	result := make([]*CryptoPriceInfo, 0, len(prices))
+	for _, id := range ids {
+		if price, ok := prices[id]; ok {
+			result = append(result, price)
+		}
+	}
+	return result, nil
+}
+
+// GetPriceHistory 通过 /market_chart/range 查询价格、市值和24小时成交额历史
+// 数据粒度由CoinGecko按时间范围决定：1天内5分钟、90天内1小时、更长为1天
+func (c *coinGeckoProvider) GetPriceHistory(ctx context.Context, id string, query HistoryQuery) (*HistoryData, error) {
+	var chart struct {
+		Prices       [][]float64 `json:"prices"`
+		MarketCaps   [][]float64 `json:"market_caps"`
+		TotalVolumes [][]float64 `json:"total_volumes"`
+	}
+	path := fmt.Sprintf("/coins/%s/market_chart/range?vs_currency=%s&from=%d&to=%d",
+		url.PathEscape(strings.ToLower(id)), url.QueryEscape(query.Currency), query.From.Unix(), query.To.Unix())
+	if err := c.get(ctx, path, &chart); err != nil {
+		return nil, err
+	}
+
+	// 每项为 [毫秒时间戳, 数值]，三个序列的时间戳一致
+	marketCaps := chartValues(chart.MarketCaps)
+	volumes := chartValues(chart.TotalVolumes)
+	data := &HistoryData{Points: make([]*PricePoint, 0, len(chart.Prices))}
+	for _, item := range chart.Prices {
+		if len(item) < 2 {
+			continue
+		}
+		timestamp := int64(item[0])
+		data.Points = append(data.Points, &PricePoint{
+			Timestamp: time.UnixMilli(timestamp).UTC(),
+			Price:     item[1],
+			MarketCap: marketCaps[timestamp],
+			Volume:    volumes[timestamp],
+		})
+	}
+	return data, nil
+}
+
+// chartValues 将 [毫秒时间戳, 数值] 序列转换为以时间戳为键的数值
+func chartValues(items [][]float64) map[int64]float64 {
+	values := make(map[int64]float64, len(items))
+	for _, item := range items {
+		if len(item) >= 2 {
+			values[int64(item[0])] = item[1]
+		}
+	}
+	return values
+}
+
+// CoinList 通过 /coins/list 读取全部币种及其BSC合约地址，
+// 再用 /coins/markets 前rankPages页的市值排名区分同符号的币种
+func (c *coinGeckoProvider) CoinList(ctx context.Context, rankPages int) ([]*models.Coin, error) {
+	var list []struct {
+		ID        string            `json:"id"`
+		Symbol    string            `json:"symbol"`
+		Name      string            `json:"name"`
+		Platforms map[string]string `json:"platforms"`
+	}
+	if err := c.get(ctx, "/coins/list?include_platform=true", &list); err != nil {
+		return nil, err
+	}
+
+	ranks := make(map[string]int)
+	for page := 1; page <= rankPages; page++ {
+		var markets []CoinGeckoPriceResponse
+		path := fmt.Sprintf("/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=%d&page=%d", coinGeckoMarketsPageSize, page)
+		if err := c.get(ctx, path, &markets); err != nil {
+			return nil, err
+		}
+		for i := range markets {
+			ranks[markets[i].ID] = markets[i].MarketCapRank
+		}
+		if len(markets) < coinGeckoMarketsPageSize {
+			break
+		}
+	}
+
+	coins := make([]*models.Coin, 0, len(list))
+	for _, item := range list {
+		if item.ID == "" {
+			continue
+		}
+		coin := &models.Coin{
+			CoinID:        item.ID,
+			Symbol:        strings.ToLower(item.Symbol),
+			Name:          item.Name,
+			MarketCapRank: ranks[item.ID],
+		}
+		if address := item.Platforms[coinGeckoBSCPlatform]; common.IsHexAddress(address) {
+			coin.BSCAddress = common.HexToAddress(address).Hex()
+		}
+		coins = append(coins, coin)
+	}
+	return coins, nil
+}
+
+// toPriceInfo 转换为价格信息
+func (r *CoinGeckoPriceResponse) toPriceInfo() *CryptoPriceInfo {
+	lastUpdated, _ := time.Parse(time.RFC3339, r.LastUpdated)
+	return &CryptoPriceInfo{
+		CoinID:                r.ID,
+		Symbol:                r.Symbol,
+		Name:                  r.Name,
+		CurrentPrice:          r.CurrentPrice,
+		MarketCap:             r.MarketCap,
+		Volume24h:             r.TotalVolume,
+		PriceChange24h:        r.PriceChange24h,
+		PriceChangePercent24h: r.PriceChangePercent24h,
+		LastUpdated:           lastUpdated,
+	}
+}
@@ -0,0 +1,130 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+const defaultCoinMarketCapURL = "https://pro-api.coinmarketcap.com"
+
+// coinMarketCapCoin CoinMarketCap币种行情
+type coinMarketCapCoin struct {
+	Name        string `json:"name"`
+	Symbol      string `json:"symbol"`
+	Slug        string `json:"slug"`
+	LastUpdated string `json:"last_updated"`
+	Quote       map[string]struct {
+		Price            float64 `json:"price"`
+		Volume24h        float64 `json:"volume_24h"`
+		PercentChange24h float64 `json:"percent_change_24h"`
+		MarketCap        float64 `json:"market_cap"`
+	} `json:"quote"`
+}
+
+// coinMarketCapProvider CoinMarketCap行情数据源，需要API Key
+// ids按CoinMarketCap的slug查询（如 bitcoin），主流币种的slug与CoinGecko币种ID一致
+type coinMarketCapProvider struct {
+	baseURL    string
+	apiKey     string
+	httpClient *http.Client
+}
+
+// newCoinMarketCapProvider 创建CoinMarketCap数据源，baseURL为空时使用官方Pro API
+func newCoinMarketCapProvider(baseURL, apiKey string, client *http.Client) *coinMarketCapProvider {
+	if baseURL == "" {
+		baseURL = defaultCoinMarketCapURL
+	}
+	return &coinMarketCapProvider{
+		baseURL:    strings.TrimRight(baseURL, "/"),
+		apiKey:     apiKey,
+		httpClient: client,
+	}
+}
+
+func (c *coinMarketCapProvider) Name() string {
+	return PriceProviderCoinMarketCap
+}
+
+// get 请求CoinMarketCap API
+func (c *coinMarketCapProvider) get(ctx context.Context, path string, out interface{}) error {
+	if c.apiKey == "" {
+		return errors.New("coinmarketcap API key not configured")
+	}
+	header := http.Header{}
+	header.Set("X-CMC_PRO_API_KEY", c.apiKey)
+	return fetchJSON(ctx, c.httpClient, PriceProviderCoinMarketCap, c.baseURL+path, header, out)
+}
+
+// GetPrices 通过 /v1/cryptocurrency/quotes/latest 按slug批量查询，无效的slug会被跳过
+func (c *coinMarketCapProvider) GetPrices(ctx context.Context, ids []string) (map[string]*CryptoPriceInfo, error) {
+	requested := make(map[string]string, len(ids))
+	slugs := make([]string, 0, len(ids))
+	for _, id := range ids {
+		requested[strings.ToLower(id)] = id
+		slugs = append(slugs, strings.ToLower(id))
+	}
+
+	var resp struct {
+		Data map[string]coinMarketCapCoin `json:"data"` // 以CoinMarketCap币种ID为键
+	}
+	path := fmt.Sprintf("/v1/cryptocurrency/quotes/latest?slug=%s&convert=USD&skip_invalid=true", url.QueryEscape(strings.Join(slugs, ",")))
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+
+	result := make(map[string]*CryptoPriceInfo, len(resp.Data))
+	for _, coin := range resp.Data {
+		if id, ok := requested[coin.Slug]; ok {
+			result[id] = coin.toPriceInfo()
+		}
+	}
+	return result, nil
+}
+
+// GetTopPrices 通过 /v1/cryptocurrency/listings/latest 查询市值排名
+func (c *coinMarketCapProvider) GetTopPrices(ctx context.Context, limit int) ([]*CryptoPriceInfo, error) {
+	var resp struct {
+		Data []coinMarketCapCoin `json:"data"`
+	}
+	path := fmt.Sprintf("/v1/cryptocurrency/listings/latest?start=1&limit=%d&convert=USD&sort=market_cap", limit)
+	if err := c.get(ctx, path, &resp); err != nil {
+		return nil, err
+	}
+
+	result := make([]*CryptoPriceInfo, 0, len(resp.Data))
+	for i := range resp.Data {
+		result = append(result, resp.Data[i].toPriceInfo())
+	}
+	return result, nil
+}
+
+// Search CoinMarketCap不提供模糊搜索
+func (c *coinMarketCapProvider) Search(ctx context.Context, query string) ([]*CryptoPriceInfo, error) {
+	return nil, ErrPriceNotSupported
+}
+
+// GetPriceHistory CoinMarketCap历史行情需要付费套餐，不使用
+func (c *coinMarketCapProvider) GetPriceHistory(ctx context.Context, id string, query HistoryQuery) (*HistoryData, error) {
+	return nil, ErrPriceNotSupported
+}
+
+// toPriceInfo 转换为价格信息
+func (c *coinMarketCapCoin) toPriceInfo() *CryptoPriceInfo {
+	quote := c.Quote["USD"]
+	lastUpdated, _ := time.Parse(time.RFC3339, c.LastUpdated)
+	return &CryptoPriceInfo{
+		Symbol:                strings.ToLower(c.Symbol),
+		Name:                  c.Name,
+		CurrentPrice:          quote.Price,
+		MarketCap:             quote.MarketCap,
+		Volume24h:             quote.Volume24h,
+		PriceChange24h:        changeFromPercent(quote.Price, quote.PercentChange24h),
+		PriceChangePercent24h: quote.PercentChange24h,
+		LastUpdated:           lastUpdated,
+	}
+}
@@ -0,0 +1,54 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const testCMCBitcoin = `{"id":1,"name":"Bitcoin","symbol":"BTC","slug":"bitcoin","last_updated":"2026-01-01T00:00:00.000Z",
+	"quote":{"USD":{"price":66000,"volume_24h":31000000000,"percent_change_24h":10,"market_cap":1300000000000}}}`
+
+func TestCoinMarketCapProvider(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "cmc-key", r.Header.Get("X-CMC_PRO_API_KEY"))
+		switch r.URL.Path {
+		case "/v1/cryptocurrency/quotes/latest":
+			assert.Equal(t, "bitcoin,unknown-coin", r.URL.Query().Get("slug"))
+			assert.Equal(t, "true", r.URL.Query().Get("skip_invalid"))
+			w.Write([]byte(`{"status":{"error_code":0},"data":{"1":` + testCMCBitcoin + `}}`))
+		case "/v1/cryptocurrency/listings/latest":
+			assert.Equal(t, "1", r.URL.Query().Get("limit"))
+			w.Write([]byte(`{"status":{"error_code":0},"data":[` + testCMCBitcoin + `]}`))
+		}
+	}))
+	defer server.Close()
+
+	provider := newCoinMarketCapProvider(server.URL, "cmc-key", server.Client())
+
+	prices, err := provider.GetPrices(context.Background(), []string{"Bitcoin", "unknown-coin"})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+
+	btc := prices["Bitcoin"]
+	assert.Equal(t, "btc", btc.Symbol)
+	assert.Equal(t, 66000.0, btc.CurrentPrice)
+	assert.Equal(t, 1.3e12, btc.MarketCap)
+	assert.Equal(t, 3.1e10, btc.Volume24h)
+	assert.Equal(t, 10.0, btc.PriceChangePercent24h)
+	assert.InDelta(t, 6000, btc.PriceChange24h, 1e-6)
+	assert.Equal(t, int64(1767225600), btc.LastUpdated.Unix())
+
+	top, err := provider.GetTopPrices(context.Background(), 1)
+	require.NoError(t, err)
+	require.Len(t, top, 1)
+	assert.Equal(t, "Bitcoin", top[0].Name)
+
+	// 缺少API Key时不发送请求
+	_, err = newCoinMarketCapProvider(server.URL, "", server.Client()).GetPrices(context.Background(), []string{"bitcoin"})
+	assert.EqualError(t, err, "coinmarketcap API key not configured")
+}
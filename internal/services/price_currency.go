@@ -0,0 +1,270 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"net/http"
+	"sort"
+	"strings"
+)
+
+// 支持的计价货币
+const (
+	CurrencyUSD = "usd"
+	CurrencyEUR = "eur"
+	CurrencyCNY = "cny"
+	CurrencyJPY = "jpy"
+	CurrencyBTC = "btc"
+	CurrencyBNB = "bnb"
+)
+
+// SupportedQuoteCurrencies 支持的计价货币，USD价格始终在 CryptoPriceInfo 的顶层字段中返回
+var SupportedQuoteCurrencies = []string{CurrencyUSD, CurrencyEUR, CurrencyCNY, CurrencyJPY, CurrencyBTC, CurrencyBNB}
+
+// cryptoQuoteCoins 以加密货币计价时，用于换算的币种ID
+var cryptoQuoteCoins = map[string]string{
+	CurrencyBTC: "bitcoin",
+	CurrencyBNB: "binancecoin",
+}
+
+const defaultFXRateURL = "https://open.er-api.com/v6/latest/USD"
+
+// PriceQuote 以某种计价货币表示的价格
+type PriceQuote struct {
+	Price                 float64 `json:"price"`
+	MarketCap             float64 `json:"market_cap"`
+	Volume24h             float64 `json:"volume_24h"`
+	PriceChange24h        float64 `json:"price_change_24h"`
+	PriceChangePercent24h float64 `json:"price_change_percent_24h"`
+	Converted             bool    `json:"converted"` // 由USD价格按当前汇率换算，涨跌幅沿用USD的涨跌幅
+}
+
+// quoteProvider 由支持多种计价货币的数据源实现，ids为CoinGecko币种ID
+type quoteProvider interface {
+	// GetQuotes 查询各币种以currencies计价的价格，结果为 币种ID => 货币 => 报价，缺少的交易对不出现在结果中
+	GetQuotes(ctx context.Context, ids []string, currencies []string) (map[string]map[string]*PriceQuote, error)
+}
+
+// fxRateSource 法币汇率来源
+type fxRateSource interface {
+	// USDRates 返回1美元可兑换的各货币数量，键为小写货币代码
+	USDRates(ctx context.Context) (map[string]float64, error)
+}
+
+// ParseQuoteCurrencies 解析逗号分隔的计价货币，去重并按字母排序，不支持的货币返回错误
+func ParseQuoteCurrencies(value string) ([]string, error) {
+	if strings.TrimSpace(value) == "" {
+		return nil, nil
+	}
+	return normalizeCurrencies(strings.Split(value, ","))
+}
+
+// normalizeCurrencies 转换为小写、去重并按字母排序，不支持的货币返回错误
+func normalizeCurrencies(currencies []string) ([]string, error) {
+	seen := make(map[string]bool, len(currencies))
+	var result []string
+	for _, currency := range currencies {
+		currency = strings.ToLower(strings.TrimSpace(currency))
+		if currency == "" || seen[currency] {
+			continue
+		}
+		if !isQuoteCurrency(currency) {
+			return nil, fmt.Errorf("unsupported quote currency: %s", currency)
+		}
+		seen[currency] = true
+		result = append(result, currency)
+	}
+	sort.Strings(result)
+	return result, nil
+}
+
+// isQuoteCurrency 判断是否为支持的计价货币
+func isQuoteCurrency(currency string) bool {
+	for _, supported := range SupportedQuoteCurrencies {
+		if currency == supported {
+			return true
+		}
+	}
+	return false
+}
+
+// currencyCacheKey 在缓存键后追加计价货币
+func currencyCacheKey(key string, currencies []string) string {
+	if len(currencies) == 0 {
+		return key
+	}
+	return key + "|" + strings.Join(currencies, ",")
+}
+
+// usdQuote 由USD字段构造USD报价
+func usdQuote(price *CryptoPriceInfo) *PriceQuote {
+	return &PriceQuote{
+		Price:                 price.CurrentPrice,
+		MarketCap:             price.MarketCap,
+		Volume24h:             price.Volume24h,
+		PriceChange24h:        price.PriceChange24h,
+		PriceChangePercent24h: price.PriceChangePercent24h,
+	}
+}
+
+// convertQuote 按汇率（1美元可兑换的数量）换算USD报价
+func convertQuote(price *CryptoPriceInfo, rate float64) *PriceQuote {
+	return &PriceQuote{
+		Price:                 price.CurrentPrice * rate,
+		MarketCap:             price.MarketCap * rate,
+		Volume24h:             price.Volume24h * rate,
+		PriceChange24h:        price.PriceChange24h * rate,
+		PriceChangePercent24h: price.PriceChangePercent24h,
+		Converted:             true,
+	}
+}
+
+// addQuotes 为每个价格填充各计价货币的报价
+// 优先使用产生该价格的数据源直接提供的交易对，缺少的交易对由USD价格换算：
+// 法币使用汇率来源（经过缓存），BTC和BNB使用其USD价格
+func (p *PriceService) addQuotes(ctx context.Context, prices []*CryptoPriceInfo, currencies []string) error {
+	if len(currencies) == 0 || len(prices) == 0 {
+		return nil
+	}
+
+	var others []string
+	for _, currency := range currencies {
+		if currency != CurrencyUSD {
+			others = append(others, currency)
+		}
+	}
+	for _, price := range prices {
+		price.Quotes = make(map[string]*PriceQuote, len(currencies))
+		if len(others) < len(currencies) {
+			price.Quotes[CurrencyUSD] = usdQuote(price)
+		}
+	}
+	if len(others) == 0 {
+		return nil
+	}
+
+	p.addNativeQuotes(ctx, prices, others)
+
+	rates := make(map[string]float64)
+	for _, price := range prices {
+		for _, currency := range others {
+			if _, ok := price.Quotes[currency]; ok {
+				continue
+			}
+			rate, ok := rates[currency]
+			if !ok {
+				var err error
+				if rate, err = p.usdRate(ctx, currency); err != nil {
+					return fmt.Errorf("failed to convert price to %s: %w", currency, err)
+				}
+				rates[currency] = rate
+			}
+			price.Quotes[currency] = convertQuote(price, rate)
+		}
+	}
+	return nil
+}
+
+// addNativeQuotes 由产生价格的数据源直接查询各计价货币的报价，失败时交由换算处理
+func (p *PriceService) addNativeQuotes(ctx context.Context, prices []*CryptoPriceInfo, currencies []string) {
+	for _, state := range p.available() {
+		provider, ok := state.provider.(quoteProvider)
+		if !ok {
+			continue
+		}
+
+		var ids []string
+		for _, price := range prices {
+			if price.Source == state.provider.Name() && price.CoinID != "" {
+				ids = append(ids, price.CoinID)
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		quotes, err := provider.GetQuotes(ctx, ids, currencies)
+		if err != nil {
+			p.recordFailure(state, "get quotes", err)
+			continue
+		}
+		for _, price := range prices {
+			if price.Source != state.provider.Name() {
+				continue
+			}
+			for currency, quote := range quotes[price.CoinID] {
+				price.Quotes[currency] = quote
+			}
+		}
+	}
+}
+
+// usdRate 返回1美元可兑换的计价货币数量
+func (p *PriceService) usdRate(ctx context.Context, currency string) (float64, error) {
+	if coinID, ok := cryptoQuoteCoins[currency]; ok {
+		price, err := p.GetCryptoPrice(ctx, coinID)
+		if err != nil {
+			return 0, err
+		}
+		if price.CurrentPrice <= 0 {
+			return 0, fmt.Errorf("invalid %s price", coinID)
+		}
+		return 1 / price.CurrentPrice, nil
+	}
+
+	if p.fx == nil {
+		return 0, errors.New("no FX rate source configured")
+	}
+	var rates map[string]float64
+	err := p.cache.Fetch(ctx, CacheFXRates, CurrencyUSD, &rates, func(ctx context.Context) (interface{}, error) {
+		return p.fx.USDRates(ctx)
+	})
+	if err != nil {
+		return 0, err
+	}
+	rate, ok := rates[currency]
+	if !ok || rate <= 0 {
+		return 0, fmt.Errorf("no exchange rate for %s", currency)
+	}
+	return rate, nil
+}
+
+// erAPIRates ExchangeRate-API格式的汇率来源，默认使用 open.er-api.com 的免费接口
+type erAPIRates struct {
+	url        string
+	httpClient *http.Client
+}
+
+// newERAPIRates 创建汇率来源，url为空时使用默认地址
+func newERAPIRates(url string, client *http.Client) *erAPIRates {
+	if url == "" {
+		url = defaultFXRateURL
+	}
+	return &erAPIRates{url: url, httpClient: client}
+}
+
+// USDRates 返回以USD为基准的汇率
+func (e *erAPIRates) USDRates(ctx context.Context) (map[string]float64, error) {
+	var response struct {
+		Result    string             `json:"result"`
+		ErrorType string             `json:"error-type"`
+		BaseCode  string             `json:"base_code"`
+		Rates     map[string]float64 `json:"rates"`
+	}
+	if err := fetchJSON(ctx, e.httpClient, "fx", e.url, nil, &response); err != nil {
+		return nil, err
+	}
+	if response.Result != "success" {
+		return nil, fmt.Errorf("FX rate API error: %s", response.ErrorType)
+	}
+	if !strings.EqualFold(response.BaseCode, "USD") {
+		return nil, fmt.Errorf("unexpected FX base currency: %s", response.BaseCode)
+	}
+
+	rates := make(map[string]float64, len(response.Rates))
+	for code, rate := range response.Rates {
+		rates[strings.ToLower(code)] = rate
+	}
+	return rates, nil
+}
@@ -0,0 +1,126 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync/atomic"
+	"testing"
+
+	"chain/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubFXRates 返回固定汇率
+type stubFXRates struct {
+	rates map[string]float64
+	calls atomic.Int32
+}
+
+func (s *stubFXRates) USDRates(ctx context.Context) (map[string]float64, error) {
+	s.calls.Add(1)
+	return s.rates, nil
+}
+
+func TestParseQuoteCurrencies(t *testing.T) {
+	currencies, err := ParseQuoteCurrencies(" EUR,btc,,eur,USD ")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"btc", "eur", "usd"}, currencies)
+
+	currencies, err = ParseQuoteCurrencies("")
+	require.NoError(t, err)
+	assert.Empty(t, currencies)
+
+	_, err = ParseQuoteCurrencies("eur,doge")
+	assert.EqualError(t, err, "unsupported quote currency: doge")
+}
+
+func TestPriceServiceNativeQuotes(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/coins/markets":
+			w.Write([]byte(`[{"id":"bitcoin","symbol":"btc","name":"Bitcoin","current_price":65000,"market_cap":1.3e12,"total_volume":3e10,"price_change_percentage_24h":4}]`))
+		case "/simple/price":
+			assert.Equal(t, "bitcoin", r.URL.Query().Get("ids"))
+			assert.Equal(t, "cny,eur", r.URL.Query().Get("vs_currencies"))
+			assert.Equal(t, "true", r.URL.Query().Get("include_market_cap"))
+			// CoinGecko缺少CNY交易对
+			w.Write([]byte(`{"bitcoin":{"eur":60000,"eur_market_cap":1.2e12,"eur_24h_vol":2.8e10,"eur_24h_change":3}}`))
+		}
+	}))
+	defer server.Close()
+
+	fx := &stubFXRates{rates: map[string]float64{"usd": 1, "cny": 7.2, "eur": 0.92}}
+	service := newPriceServiceWithProviders("", newCoinGeckoProvider(server.URL, "", server.Client()))
+	service.fx = fx
+
+	price, err := service.GetCryptoPrice(context.Background(), "bitcoin", "eur", "CNY", "usd")
+	require.NoError(t, err)
+	require.Len(t, price.Quotes, 3)
+
+	eur := price.Quotes[CurrencyEUR]
+	assert.False(t, eur.Converted)
+	assert.Equal(t, 60000.0, eur.Price)
+	assert.Equal(t, 1.2e12, eur.MarketCap)
+	assert.Equal(t, 2.8e10, eur.Volume24h)
+	assert.Equal(t, 3.0, eur.PriceChangePercent24h)
+
+	cny := price.Quotes[CurrencyCNY]
+	assert.True(t, cny.Converted)
+	assert.Equal(t, 65000*7.2, cny.Price)
+	assert.Equal(t, 1.3e12*7.2, cny.MarketCap)
+	assert.Equal(t, 4.0, cny.PriceChangePercent24h)
+
+	assert.Equal(t, 65000.0, price.Quotes[CurrencyUSD].Price)
+	assert.Equal(t, 65000.0, price.CurrentPrice)
+}
+
+func TestPriceServiceConvertedQuotes(t *testing.T) {
+	provider := &stubPriceProvider{name: "stub", prices: map[string]float64{"bitcoin": 50000, "binancecoin": 500, "cake": 2.5}}
+	fx := &stubFXRates{rates: map[string]float64{"jpy": 150}}
+	service := newPriceServiceWithProviders("", provider)
+	service.fx = fx
+	service.SetCache(newCache(NewMemoryCacheBackend(), config.CacheConfig{}))
+
+	prices, err := service.GetMultipleCryptoPrices(context.Background(), []string{"cake"}, "bnb", "btc", "jpy")
+	require.NoError(t, err)
+	cake := prices["cake"]
+	require.Len(t, cake.Quotes, 3)
+	assert.InDelta(t, 0.005, cake.Quotes[CurrencyBNB].Price, 1e-12)
+	assert.InDelta(t, 0.00005, cake.Quotes[CurrencyBTC].Price, 1e-12)
+	assert.Equal(t, 375.0, cake.Quotes[CurrencyJPY].Price)
+	assert.True(t, cake.Quotes[CurrencyJPY].Converted)
+
+	// 汇率经过缓存
+	_, err = service.GetCryptoPrice(context.Background(), "bitcoin", "jpy")
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), fx.calls.Load())
+
+	// 缺少汇率时返回错误
+	_, err = service.GetCryptoPrice(context.Background(), "bitcoin", "eur")
+	assert.EqualError(t, err, "failed to convert price to eur: no exchange rate for eur")
+
+	_, err = service.GetTopCryptoPrices(context.Background(), 10, "gbp")
+	assert.EqualError(t, err, "unsupported quote currency: gbp")
+}
+
+func TestERAPIRates(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":"success","base_code":"USD","rates":{"USD":1,"EUR":0.92,"JPY":150.5}}`))
+	}))
+	defer server.Close()
+
+	rates, err := newERAPIRates(server.URL, server.Client()).USDRates(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]float64{"usd": 1, "eur": 0.92, "jpy": 150.5}, rates)
+
+	failing := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`{"result":"error","error-type":"unsupported-code"}`))
+	}))
+	defer failing.Close()
+
+	_, err = newERAPIRates(failing.URL, failing.Client()).USDRates(context.Background())
+	assert.EqualError(t, err, "FX rate API error: unsupported-code")
+}
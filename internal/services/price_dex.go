@@ -0,0 +1,125 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// tokenPriceSource 按代币地址查询链上价格，由BSCService实现
+type tokenPriceSource interface {
+	GetTokenPrice(tokenAddress, tokenName string) (*PriceInfo, error)
+	// RefreshTokenPrice 跳过缓存查询代币最新价格
+	RefreshTokenPrice(tokenAddress string) (*PriceInfo, error)
+}
+
+// dexPriceProvider 以BSCService的DEX价格作为行情数据源，ids为BSC代币合约地址
+// 非地址的标识会被忽略，不支持市值排名、搜索和历史价格
+type dexPriceProvider struct {
+	mu     sync.RWMutex
+	source tokenPriceSource
+}
+
+func (d *dexPriceProvider) Name() string {
+	return PriceProviderBSC
+}
+
+// setSource 设置链上价格来源
+func (d *dexPriceProvider) setSource(source tokenPriceSource) {
+	d.mu.Lock()
+	defer d.mu.Unlock()
+	d.source = source
+}
+
+// coinKey 使用币种的BSC合约地址，没有BSC合约的币种不支持
+func (d *dexPriceProvider) coinKey(coin *models.Coin) string {
+	return coin.BSCAddress
+}
+
+// GetPrices 并发查询各代币的DEX价格，查询失败的代币不出现在结果中
+func (d *dexPriceProvider) GetPrices(ctx context.Context, ids []string) (map[string]*CryptoPriceInfo, error) {
+	d.mu.RLock()
+	source := d.source
+	d.mu.RUnlock()
+	if source == nil {
+		return nil, errors.New("BSC service not configured for price provider")
+	}
+
+	var (
+		mu       sync.Mutex
+		wg       sync.WaitGroup
+		result   = make(map[string]*CryptoPriceInfo, len(ids))
+		firstErr error
+	)
+	for _, id := range ids {
+		if !common.IsHexAddress(id) {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+
+			price, err := source.GetTokenPrice(id, "")
+			mu.Lock()
+			defer mu.Unlock()
+			if err != nil {
+				if firstErr == nil {
+					firstErr = err
+				}
+				return
+			}
+			if info := dexPriceInfo(price); info != nil {
+				result[id] = info
+			}
+		}()
+	}
+	wg.Wait()
+
+	if len(result) == 0 && firstErr != nil {
+		return nil, firstErr
+	}
+	return result, nil
+}
+
+// GetTopPrices 链上价格没有市值排名
+func (d *dexPriceProvider) GetTopPrices(ctx context.Context, limit int) ([]*CryptoPriceInfo, error) {
+	return nil, ErrPriceNotSupported
+}
+
+// Search 链上价格不支持按名称搜索
+func (d *dexPriceProvider) Search(ctx context.Context, query string) ([]*CryptoPriceInfo, error) {
+	return nil, ErrPriceNotSupported
+}
+
+// GetPriceHistory 链上价格不提供历史数据
+func (d *dexPriceProvider) GetPriceHistory(ctx context.Context, id string, query HistoryQuery) (*HistoryData, error) {
+	return nil, ErrPriceNotSupported
+}
+
+// dexPriceInfo 将BSCService的价格转换为行情信息，没有USD价格时返回nil
+func dexPriceInfo(price *PriceInfo) *CryptoPriceInfo {
+	usd, err := strconv.ParseFloat(price.PriceInUSD, 64)
+	if err != nil || usd <= 0 {
+		return nil
+	}
+	volume, _ := strconv.ParseFloat(price.Volume24h, 64)
+	changePercent, _ := strconv.ParseFloat(price.PriceChange24h, 64)
+
+	return &CryptoPriceInfo{
+		Symbol:                strings.ToLower(price.TokenSymbol),
+		Name:                  price.TokenName,
+		CurrentPrice:          usd,
+		Volume24h:             volume,
+		PriceChange24h:        changeFromPercent(usd, changePercent),
+		PriceChangePercent24h: changePercent,
+		LastUpdated:           time.Now().UTC(),
+	}
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubTokenPrices 按地址返回固定的链上价格
+type stubTokenPrices map[string]*PriceInfo
+
+func (s stubTokenPrices) GetTokenPrice(tokenAddress, tokenName string) (*PriceInfo, error) {
+	if price, ok := s[strings.ToLower(tokenAddress)]; ok {
+		return price, nil
+	}
+	return nil, errors.New("no liquidity pool found")
+}
+
+func (s stubTokenPrices) RefreshTokenPrice(tokenAddress string) (*PriceInfo, error) {
+	return s.GetTokenPrice(tokenAddress, "")
+}
+
+func TestDexPriceProvider(t *testing.T) {
+	cake := strings.ToLower(CAKEAddress)
+	provider := &dexPriceProvider{}
+
+	_, err := provider.GetPrices(context.Background(), []string{CAKEAddress})
+	assert.EqualError(t, err, "BSC service not configured for price provider")
+
+	provider.setSource(stubTokenPrices{
+		cake:                         {TokenName: "PancakeSwap Token", TokenSymbol: "Cake", PriceInUSD: "2.5", Volume24h: "1000000", PriceChange24h: "25"},
+		strings.ToLower(USDCAddress): {TokenSymbol: "USDC", PriceInUSD: "0"},
+	})
+
+	// 非地址标识被忽略，没有USD价格的代币不返回
+	prices, err := provider.GetPrices(context.Background(), []string{CAKEAddress, "bitcoin", USDCAddress})
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+
+	info := prices[CAKEAddress]
+	assert.Equal(t, "cake", info.Symbol)
+	assert.Equal(t, "PancakeSwap Token", info.Name)
+	assert.Equal(t, 2.5, info.CurrentPrice)
+	assert.Equal(t, 1000000.0, info.Volume24h)
+	assert.Equal(t, 25.0, info.PriceChangePercent24h)
+	assert.InDelta(t, 0.5, info.PriceChange24h, 1e-9)
+
+	// 所有代币都查询失败时返回错误，交由下一个数据源处理
+	_, err = provider.GetPrices(context.Background(), []string{BUSDAddress})
+	assert.EqualError(t, err, "no liquidity pool found")
+
+	_, err = provider.GetPriceHistory(context.Background(), CAKEAddress, HistoryQuery{Days: 7, Currency: CurrencyUSD})
+	assert.ErrorIs(t, err, ErrPriceNotSupported)
+}
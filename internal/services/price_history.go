@@ -0,0 +1,301 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+	"time"
+)
+
+// 价格历史查询参数
+const (
+	defaultHistoryDays = 7
+	maxHistoryCandles  = 5000 // 单次查询覆盖的最大K线数量
+)
+
+// ErrInvalidHistoryQuery 价格历史查询条件无效
+var ErrInvalidHistoryQuery = errors.New("invalid price history query")
+
+// historyIntervals 支持的价格历史周期，按从小到大排列
+var historyIntervals = []struct {
+	name     string
+	duration time.Duration
+}{
+	{"5m", 5 * time.Minute},
+	{"1h", time.Hour},
+	{"1d", 24 * time.Hour},
+	{"1w", 7 * 24 * time.Hour},
+}
+
+// HistoryInterval 返回价格历史周期对应的时长
+func HistoryInterval(interval string) (time.Duration, bool) {
+	for _, i := range historyIntervals {
+		if i.name == interval {
+			return i.duration, true
+		}
+	}
+	return 0, false
+}
+
+// autoHistoryInterval 按时间范围选择周期，与CoinGecko自动返回的数据粒度一致
+func autoHistoryInterval(span time.Duration) string {
+	switch {
+	case span <= 24*time.Hour:
+		return "5m"
+	case span <= 90*24*time.Hour:
+		return "1h"
+	}
+	return "1d"
+}
+
+// HistoryQuery 价格历史查询条件
+type HistoryQuery struct {
+	Days     int       // From为零时查询To之前days天，默认7
+	From     time.Time // 起始时间（含）
+	To       time.Time // 结束时间（含），为零时表示当前时间
+	Interval string    // 5m、1h、1d 或 1w，为空时按时间范围自动选择
+	Currency string    // 计价货币，默认usd
+}
+
+// normalize 填充默认值并校验，返回的查询From、To、Interval和Currency均已确定
+func (q HistoryQuery) normalize(now time.Time) (HistoryQuery, error) {
+	if q.To.IsZero() {
+		q.To = now
+	}
+	if q.From.IsZero() {
+		days := q.Days
+		if days <= 0 {
+			days = defaultHistoryDays
+		}
+		q.From = q.To.AddDate(0, 0, -days)
+	}
+	if !q.From.Before(q.To) {
+		return q, fmt.Errorf("%w: from must be before to", ErrInvalidHistoryQuery)
+	}
+
+	if q.Interval == "" {
+		q.Interval = autoHistoryInterval(q.To.Sub(q.From))
+	}
+	duration, ok := HistoryInterval(q.Interval)
+	if !ok {
+		return q, fmt.Errorf("%w: unsupported interval %s, expected 5m, 1h, 1d or 1w", ErrInvalidHistoryQuery, q.Interval)
+	}
+	if count := q.To.Sub(q.From)/duration + 1; count > maxHistoryCandles {
+		return q, fmt.Errorf("%w: time range covers %d candles, at most %d allowed", ErrInvalidHistoryQuery, count, maxHistoryCandles)
+	}
+
+	q.Currency = strings.ToLower(q.Currency)
+	if q.Currency == "" {
+		q.Currency = CurrencyUSD
+	}
+	if !isQuoteCurrency(q.Currency) {
+		return q, fmt.Errorf("%w: unsupported quote currency: %s", ErrInvalidHistoryQuery, q.Currency)
+	}
+	return q, nil
+}
+
+// cacheKey 返回缓存键，未指定时间范围的查询以天数为键，使相对时间的查询可以命中缓存
+func (q HistoryQuery) cacheKey(symbol string) string {
+	days := q.Days
+	if days <= 0 {
+		days = defaultHistoryDays
+	}
+	span := fmt.Sprintf("%dd", days)
+	switch {
+	case !q.From.IsZero() && !q.To.IsZero():
+		span = fmt.Sprintf("%d-%d", q.From.Unix(), q.To.Unix())
+	case !q.From.IsZero():
+		span = fmt.Sprintf("%d-now", q.From.Unix())
+	case !q.To.IsZero():
+		span = fmt.Sprintf("%dd-%d", days, q.To.Unix())
+	}
+	return fmt.Sprintf("%s:%s:%s:%s", strings.ToLower(symbol), span, q.Interval, strings.ToLower(q.Currency))
+}
+
+// PricePoint 带时间戳的价格
+type PricePoint struct {
+	Timestamp time.Time `json:"timestamp"`
+	Price     float64   `json:"price"`
+	MarketCap float64   `json:"market_cap,omitempty"`
+	Volume    float64   `json:"volume,omitempty"`
+}
+
+// HistoryCandle 价格历史K线，Timestamp为周期开始时间
+type HistoryCandle struct {
+	Timestamp time.Time `json:"timestamp"`
+	Open      float64   `json:"open"`
+	High      float64   `json:"high"`
+	Low       float64   `json:"low"`
+	Close     float64   `json:"close"`
+	Volume    float64   `json:"volume"`               // 数据源K线的成交额；由价格点聚合时为周期内最后的24小时成交额
+	MarketCap float64   `json:"market_cap,omitempty"` // 周期内最后的市值
+}
+
+// HistoryData 数据源返回的价格历史
+type HistoryData struct {
+	Points  []*PricePoint    // 按时间升序
+	Candles []*HistoryCandle // 数据源直接提供查询周期的K线时非空
+}
+
+// PriceHistory 价格历史
+type PriceHistory struct {
+	Symbol    string           `json:"symbol"`
+	CoinID    string           `json:"coin_id"`
+	Source    string           `json:"source"`            // 有本地记录时为 local
+	Sources   []string         `json:"sources,omitempty"` // 使用本地记录时，local和补充未覆盖时间范围的数据源
+	Currency  string           `json:"currency"`
+	Interval  string           `json:"interval"`
+	From      time.Time        `json:"from"`
+	To        time.Time        `json:"to"`
+	Converted bool             `json:"converted,omitempty"` // 由USD历史按当前汇率换算
+	Points    []*PricePoint    `json:"points"`
+	Candles   []*HistoryCandle `json:"candles"`
+	Prices    []float64        `json:"prices"` // 各价格点的价格，兼容只需要价格序列的调用方
+}
+
+// aggregateCandles 将价格点按周期聚合为K线，没有价格点的周期不返回K线
+func aggregateCandles(points []*PricePoint, interval time.Duration) []*HistoryCandle {
+	var candles []*HistoryCandle
+	var current *HistoryCandle
+	for _, point := range points {
+		start := point.Timestamp.Truncate(interval)
+		if current == nil || !current.Timestamp.Equal(start) {
+			current = &HistoryCandle{Timestamp: start, Open: point.Price, High: point.Price, Low: point.Price}
+			candles = append(candles, current)
+		}
+		current.High = max(current.High, point.Price)
+		current.Low = min(current.Low, point.Price)
+		current.Close = point.Price
+		current.Volume = point.Volume
+		current.MarketCap = point.MarketCap
+	}
+	return candles
+}
+
+// inRange 只保留[from, to]内的价格点和与其重叠的K线
+func (d *HistoryData) inRange(from, to time.Time, interval time.Duration) {
+	points := d.Points[:0]
+	for _, point := range d.Points {
+		if !point.Timestamp.Before(from) && !point.Timestamp.After(to) {
+			points = append(points, point)
+		}
+	}
+	d.Points = points
+
+	candles := d.Candles[:0]
+	for _, candle := range d.Candles {
+		if !candle.Timestamp.After(to) && candle.Timestamp.Add(interval).After(from) {
+			candles = append(candles, candle)
+		}
+	}
+	d.Candles = candles
+}
+
+// convert 按汇率换算价格、市值和成交额
+func (h *PriceHistory) convert(rate float64) {
+	for _, point := range h.Points {
+		point.Price *= rate
+		point.MarketCap *= rate
+		point.Volume *= rate
+	}
+	for _, candle := range h.Candles {
+		candle.Open *= rate
+		candle.High *= rate
+		candle.Low *= rate
+		candle.Close *= rate
+		candle.Volume *= rate
+		candle.MarketCap *= rate
+	}
+	for i := range h.Prices {
+		h.Prices[i] *= rate
+	}
+	h.Converted = true
+}
+
+// GetPriceHistory 获取价格历史，包含带时间戳的价格点和按周期聚合的K线
+func (p *PriceService) GetPriceHistory(ctx context.Context, symbol string, query HistoryQuery) (*PriceHistory, error) {
+	normalized, err := query.normalize(time.Now().UTC())
+	if err != nil {
+		return nil, err
+	}
+	query.Interval = normalized.Interval
+
+	var history *PriceHistory
+	err = p.cache.Fetch(ctx, CachePriceHistory, query.cacheKey(symbol), &history, func(ctx context.Context) (interface{}, error) {
+		return p.getPriceHistory(ctx, symbol, normalized)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return history, nil
+}
+
+// getPriceHistory 优先使用本地记录的价格历史，没有本地记录时从第一个支持的数据源查询
+// 没有数据源能直接提供该计价货币的历史时，查询USD历史并按当前汇率换算
+func (p *PriceService) getPriceHistory(ctx context.Context, symbol string, query HistoryQuery) (*PriceHistory, error) {
+	coin := p.resolver.Resolve(symbol)
+	if records := p.storedHistory(coin, query.From, query.To); len(records) > 0 {
+		return p.localHistory(ctx, coin, query, records)
+	}
+
+	history, err := p.fetchHistory(ctx, coin, query)
+	if err == nil || query.Currency == CurrencyUSD {
+		return history, err
+	}
+
+	usd := query
+	usd.Currency = CurrencyUSD
+	history, err = p.fetchHistory(ctx, coin, usd)
+	if err != nil {
+		return nil, err
+	}
+	rate, err := p.usdRate(ctx, query.Currency)
+	if err != nil {
+		return nil, fmt.Errorf("failed to convert price history to %s: %w", query.Currency, err)
+	}
+	history.convert(rate)
+	history.Currency = query.Currency
+	return history, nil
+}
+
+// fetchHistory 按优先级查询价格历史
+func (p *PriceService) fetchHistory(ctx context.Context, coin *ResolvedCoin, query HistoryQuery) (*PriceHistory, error) {
+	duration, _ := HistoryInterval(query.Interval)
+
+	var errs providerErrors
+	for _, state := range p.available() {
+		id := coinKey(state.provider, &coin.Coin)
+		if id == "" {
+			continue
+		}
+		data, err := state.provider.GetPriceHistory(ctx, id, query)
+		if err != nil {
+			p.recordFailure(state, "get price history", err)
+			errs.add(state.provider.Name(), err)
+			continue
+		}
+
+		data.inRange(query.From, query.To, duration)
+		if len(data.Candles) == 0 {
+			data.Candles = aggregateCandles(data.Points, duration)
+		}
+		prices := make([]float64, 0, len(data.Points))
+		for _, point := range data.Points {
+			prices = append(prices, point.Price)
+		}
+		return &PriceHistory{
+			Symbol:   coin.Query,
+			CoinID:   coin.CoinID,
+			Source:   state.provider.Name(),
+			Currency: query.Currency,
+			Interval: query.Interval,
+			From:     query.From,
+			To:       query.To,
+			Points:   data.Points,
+			Candles:  data.Candles,
+			Prices:   prices,
+		}, nil
+	}
+	return nil, errs.err("get price history")
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHistoryQueryNormalize(t *testing.T) {
+	now := time.Date(2026, 1, 10, 12, 0, 0, 0, time.UTC)
+
+	// 默认查询最近7天，按时间范围选择小时周期
+	query, err := HistoryQuery{}.normalize(now)
+	require.NoError(t, err)
+	assert.Equal(t, now.AddDate(0, 0, -7), query.From)
+	assert.Equal(t, now, query.To)
+	assert.Equal(t, "1h", query.Interval)
+	assert.Equal(t, CurrencyUSD, query.Currency)
+
+	query, err = HistoryQuery{Days: 1, Currency: "EUR"}.normalize(now)
+	require.NoError(t, err)
+	assert.Equal(t, "5m", query.Interval)
+	assert.Equal(t, CurrencyEUR, query.Currency)
+
+	query, err = HistoryQuery{Days: 365}.normalize(now)
+	require.NoError(t, err)
+	assert.Equal(t, "1d", query.Interval)
+
+	tests := []struct {
+		query HistoryQuery
+		err   string
+	}{
+		{HistoryQuery{From: now, To: now.Add(-time.Hour)}, "from must be before to"},
+		{HistoryQuery{Interval: "2h"}, "unsupported interval 2h"},
+		{HistoryQuery{Days: 30, Interval: "5m"}, "at most 5000 allowed"},
+		{HistoryQuery{Currency: "gbp"}, "unsupported quote currency: gbp"},
+	}
+	for _, tt := range tests {
+		_, err := tt.query.normalize(now)
+		assert.ErrorIs(t, err, ErrInvalidHistoryQuery)
+		assert.ErrorContains(t, err, tt.err)
+	}
+}
+
+func TestAggregateCandles(t *testing.T) {
+	start := time.Date(2026, 1, 1, 0, 0, 0, 0, time.UTC)
+	points := []*PricePoint{
+		{Timestamp: start.Add(5 * time.Minute), Price: 100, Volume: 10},
+		{Timestamp: start.Add(20 * time.Minute), Price: 120, Volume: 11},
+		{Timestamp: start.Add(40 * time.Minute), Price: 90, Volume: 12},
+		{Timestamp: start.Add(50 * time.Minute), Price: 110, Volume: 13, MarketCap: 1e9},
+		// 没有价格点的周期不返回K线
+		{Timestamp: start.Add(3*time.Hour + 10*time.Minute), Price: 130, Volume: 14},
+	}
+
+	candles := aggregateCandles(points, time.Hour)
+	require.Len(t, candles, 2)
+	assert.Equal(t, &HistoryCandle{Timestamp: start, Open: 100, High: 120, Low: 90, Close: 110, Volume: 13, MarketCap: 1e9}, candles[0])
+	assert.Equal(t, &HistoryCandle{Timestamp: start.Add(3 * time.Hour), Open: 130, High: 130, Low: 130, Close: 130, Volume: 14}, candles[1])
+}
+
+func TestPriceHistoryConvertsUSD(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Write([]byte(`[[1767225600000,"100","110","90","105","10",1767229199999,"1000"],[1767229200000,"105","120","100","115","12",1767232799999,"1200"]]`))
+	}))
+	defer server.Close()
+
+	service := newPriceServiceWithProviders("", newBinanceProvider(server.URL, server.Client()))
+	service.fx = &stubFXRates{rates: map[string]float64{"eur": 0.5}}
+
+	// Binance只有USDT交易对，EUR历史由USD历史按当前汇率换算
+	from := time.Unix(1767225600, 0).UTC()
+	history, err := service.GetPriceHistory(context.Background(), "bitcoin", HistoryQuery{From: from, To: from.Add(2 * time.Hour), Currency: "eur"})
+	require.NoError(t, err)
+	assert.Equal(t, PriceProviderBinance, history.Source)
+	assert.Equal(t, CurrencyEUR, history.Currency)
+	assert.Equal(t, "5m", history.Interval)
+	assert.True(t, history.Converted)
+	assert.Equal(t, []float64{52.5, 57.5}, history.Prices)
+	require.Len(t, history.Candles, 2)
+	assert.Equal(t, 55.0, history.Candles[0].High)
+	assert.Equal(t, 500.0, history.Candles[0].Volume)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+)
+
+// 行情数据源类型
+const (
+	PriceProviderCoinGecko     = "coingecko"
+	PriceProviderBinance       = "binance"
+	PriceProviderCoinMarketCap = "coinmarketcap"
+	PriceProviderBSC           = "bsc"
+)
+
+// PriceAggregateMedian 同时查询所有数据源并取价格中位数
+const PriceAggregateMedian = "median"
+
+// ErrPriceNotSupported 数据源不支持该类查询，PriceService会直接尝试下一个数据源
+var ErrPriceNotSupported = errors.New("not supported by price provider")
+
+// PriceProvider 加密货币行情数据源
+//
+// ids 为调用方传入的币种标识（CoinGecko币种ID、交易符号或BSC代币地址），
+// 各数据源自行解释，无法识别的标识不出现在结果中。
+type PriceProvider interface {
+	// Name 数据源名称，写入结果的 Source 字段
+	Name() string
+	// GetPrices 批量查询价格，结果以请求的标识为键
+	GetPrices(ctx context.Context, ids []string) (map[string]*CryptoPriceInfo, error)
+	// GetTopPrices 查询市值排名前limit的币种
+	GetTopPrices(ctx context.Context, limit int) ([]*CryptoPriceInfo, error)
+	// Search 按名称或符号搜索币种并返回价格
+	Search(ctx context.Context, query string) ([]*CryptoPriceInfo, error)
+	// GetPriceHistory 查询[query.From, query.To]内以query.Currency计价的价格历史，
+	// query已填充默认值；不支持该计价货币时返回 ErrPriceNotSupported
+	GetPriceHistory(ctx context.Context, id string, query HistoryQuery) (*HistoryData, error)
+}
+
+// RateLimitError 数据源返回限流响应
+type RateLimitError struct {
+	Provider   string
+	RetryAfter time.Duration // 响应中的Retry-After，0表示未指定
+}
+
+func (e *RateLimitError) Error() string {
+	if e.RetryAfter > 0 {
+		return fmt.Sprintf("%s rate limited, retry after %s", e.Provider, e.RetryAfter)
+	}
+	return fmt.Sprintf("%s rate limited", e.Provider)
+}
+
+// priceAPIError 数据源返回非200状态码
+type priceAPIError struct {
+	StatusCode int
+}
+
+func (e *priceAPIError) Error() string {
+	return fmt.Sprintf("API request failed with status: %d", e.StatusCode)
+}
+
+// fetchJSON 发送GET请求并解析JSON响应，429和418（Binance封禁）返回RateLimitError
+func fetchJSON(ctx context.Context, client *http.Client, provider, endpoint string, header http.Header, out interface{}) error {
+	req, err := http.NewRequestWithContext(ctx, "GET", endpoint, nil)
+	if err != nil {
+		return fmt.Errorf("failed to create request: %w", err)
+	}
+	for key, values := range header {
+		req.Header[key] = values
+	}
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to fetch price data: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode == http.StatusTooManyRequests || resp.StatusCode == http.StatusTeapot {
+		limited := &RateLimitError{Provider: provider}
+		if seconds, err := strconv.Atoi(resp.Header.Get("Retry-After")); err == nil && seconds > 0 {
+			limited.RetryAfter = time.Duration(seconds) * time.Second
+		}
+		return limited
+	}
+	if resp.StatusCode != http.StatusOK {
+		return &priceAPIError{StatusCode: resp.StatusCode}
+	}
+
+	body, err := io.ReadAll(resp.Body)
+	if err != nil {
+		return fmt.Errorf("failed to read response body: %w", err)
+	}
+	if err := json.Unmarshal(body, out); err != nil {
+		return fmt.Errorf("failed to parse response: %w", err)
+	}
+	return nil
+}
+
+// changeFromPercent 由当前价格和24小时涨跌幅（百分比）反推涨跌额
+func changeFromPercent(price, percent float64) float64 {
+	if percent <= -100 {
+		return 0
+	}
+	return price - price/(1+percent/100)
+}
@@ -0,0 +1,228 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"chain/internal/models"
+	"chain/pkg/logger"
+)
+
+// PriceSourceLocal 价格历史来自本地记录
+const PriceSourceLocal = "local"
+
+// maxHistoryGapFetches 本地记录的空缺超过该数量时合并为一次数据源查询
+const maxHistoryGapFetches = 3
+
+// defaultHistoryRetention 本地价格历史的默认保留时间
+const defaultHistoryRetention = 30 * 24 * time.Hour
+
+// timeRange 闭区间[from, to]
+type timeRange struct{ from, to time.Time }
+
+// contains 判断时间是否在区间内
+func (r timeRange) contains(t time.Time) bool {
+	return !t.Before(r.from) && !t.After(r.to)
+}
+
+// RunPriceHistoryRecorder 按记录间隔持续记录观察列表中币种和BSC代币的价格并清理过期记录，直到ctx取消
+// 记录间隔为0或没有配置币种和代币时不启动
+func (p *PriceService) RunPriceHistoryRecorder(ctx context.Context) {
+	if p.historyInterval <= 0 || len(p.historyCoins)+len(p.historyTokens) == 0 {
+		logger.Info("Price history recorder disabled")
+		return
+	}
+
+	ticker := time.NewTicker(p.historyInterval)
+	defer ticker.Stop()
+
+	for {
+		if count, err := p.RecordPrices(ctx); err != nil {
+			logger.Warnf("Failed to record price history: %v", err)
+		} else {
+			logger.Debugf("Recorded %d prices", count)
+		}
+		if err := p.history.DeletePricesBefore(time.Now().Add(-p.historyRetention)); err != nil {
+			logger.Warnf("Failed to delete expired price history: %v", err)
+		}
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// RecordPrices 记录观察列表中币种（经行情数据源）和BSC代币（经BSCService）的当前USD价格，返回保存的记录数
+// 价格不经缓存查询；记录时间按记录间隔对齐，同一周期内重复记录被忽略；单个资产失败不影响其他资产
+func (p *PriceService) RecordPrices(ctx context.Context) (int, error) {
+	timestamp := time.Now().UTC()
+	if p.historyInterval > 0 {
+		timestamp = timestamp.Truncate(p.historyInterval)
+	}
+
+	var (
+		records []*models.HistoricalPrice
+		errs    []error
+	)
+	if len(p.historyCoins) > 0 {
+		prices, err := p.getMultipleCryptoPrices(ctx, p.historyCoins)
+		if err != nil {
+			errs = append(errs, err)
+		}
+		for _, price := range prices {
+			records = append(records, &models.HistoricalPrice{
+				Asset:     price.CoinID,
+				Kind:      HistoricalAssetCoin,
+				Price:     price.CurrentPrice,
+				MarketCap: price.MarketCap,
+				Volume24h: price.Volume24h,
+				Source:    price.Source,
+				Timestamp: timestamp,
+			})
+		}
+	}
+
+	if len(p.historyTokens) > 0 && p.tokens == nil {
+		errs = append(errs, errors.New("BSC service not configured for price history"))
+	} else {
+		for _, token := range p.historyTokens {
+			price, err := p.tokens.RefreshTokenPrice(token.Hex())
+			if err != nil {
+				errs = append(errs, fmt.Errorf("%s: %w", token.Hex(), err))
+				continue
+			}
+			info := dexPriceInfo(price)
+			if info == nil {
+				errs = append(errs, fmt.Errorf("%s: no USD price", token.Hex()))
+				continue
+			}
+			records = append(records, &models.HistoricalPrice{
+				Asset:     strings.ToLower(token.Hex()),
+				Kind:      HistoricalAssetToken,
+				Price:     info.CurrentPrice,
+				Volume24h: info.Volume24h,
+				Source:    PriceProviderBSC,
+				Timestamp: timestamp,
+			})
+		}
+	}
+
+	if err := p.history.SavePrices(records); err != nil {
+		return 0, fmt.Errorf("failed to save price history: %w", err)
+	}
+	return len(records), errors.Join(errs...)
+}
+
+// storedHistory 返回币种在[from, to]内的本地价格记录，依次按币种ID和BSC合约地址查找
+func (p *PriceService) storedHistory(coin *ResolvedCoin, from, to time.Time) []*models.HistoricalPrice {
+	assets := []string{coin.CoinID}
+	if address := strings.ToLower(coin.BSCAddress); address != "" && address != coin.CoinID {
+		assets = append(assets, address)
+	}
+	for _, asset := range assets {
+		records, err := p.history.GetPrices(asset, from, to)
+		if err != nil {
+			logger.Warnf("Failed to get stored price history of %s: %v", asset, err)
+			continue
+		}
+		if len(records) > 0 {
+			return records
+		}
+	}
+	return nil
+}
+
+// localHistory 由本地价格记录构造价格历史，记录未覆盖的时间由数据源补充
+// 开头、结尾或相邻两条记录之间的空缺超过两个记录间隔（且超过查询周期）时视为未覆盖，
+// 空缺较多时合并为一次查询；数据源补充失败时只返回本地记录
+func (p *PriceService) localHistory(ctx context.Context, coin *ResolvedCoin, query HistoryQuery, records []*models.HistoricalPrice) (*PriceHistory, error) {
+	duration, _ := HistoryInterval(query.Interval)
+	tolerance := max(duration, 2*p.historyInterval)
+
+	points := make([]*PricePoint, 0, len(records))
+	for _, record := range records {
+		points = append(points, &PricePoint{
+			Timestamp: record.Timestamp.UTC(),
+			Price:     record.Price,
+			MarketCap: record.MarketCap,
+			Volume:    record.Volume24h,
+		})
+	}
+
+	var gaps []timeRange
+	if first := points[0].Timestamp; first.Sub(query.From) > tolerance {
+		gaps = append(gaps, timeRange{query.From, first.Add(-time.Second)})
+	}
+	for i := 1; i < len(points); i++ {
+		if prev, next := points[i-1].Timestamp, points[i].Timestamp; next.Sub(prev) > tolerance {
+			gaps = append(gaps, timeRange{prev.Add(time.Second), next.Add(-time.Second)})
+		}
+	}
+	if last := points[len(points)-1].Timestamp; query.To.Sub(last) > tolerance {
+		gaps = append(gaps, timeRange{last.Add(time.Second), query.To})
+	}
+
+	// 空缺过多时查询覆盖所有空缺的一个范围，只保留落在空缺内的价格点
+	fetches := gaps
+	if len(gaps) > maxHistoryGapFetches {
+		fetches = []timeRange{{gaps[0].from, gaps[len(gaps)-1].to}}
+	}
+
+	sources := []string{PriceSourceLocal}
+	for _, fetch := range fetches {
+		gapQuery := query
+		gapQuery.From, gapQuery.To, gapQuery.Currency = fetch.from, fetch.to, CurrencyUSD
+		fetched, err := p.fetchHistory(ctx, coin, gapQuery)
+		if err != nil {
+			logger.Warnf("Failed to fetch price history of %s from %s to %s: %v", coin.CoinID, fetch.from, fetch.to, err)
+			continue
+		}
+		added := false
+		for _, point := range fetched.Points {
+			if slices.ContainsFunc(gaps, func(gap timeRange) bool { return gap.contains(point.Timestamp) }) {
+				points = append(points, point)
+				added = true
+			}
+		}
+		if added && !slices.Contains(sources, fetched.Source) {
+			sources = append(sources, fetched.Source)
+		}
+	}
+	sort.SliceStable(points, func(i, j int) bool {
+		return points[i].Timestamp.Before(points[j].Timestamp)
+	})
+
+	prices := make([]float64, 0, len(points))
+	for _, point := range points {
+		prices = append(prices, point.Price)
+	}
+	history := &PriceHistory{
+		Symbol:   coin.Query,
+		CoinID:   coin.CoinID,
+		Source:   PriceSourceLocal,
+		Sources:  sources,
+		Currency: CurrencyUSD,
+		Interval: query.Interval,
+		From:     query.From,
+		To:       query.To,
+		Points:   points,
+		Candles:  aggregateCandles(points, duration),
+		Prices:   prices,
+	}
+	if query.Currency != CurrencyUSD {
+		rate, err := p.usdRate(ctx, query.Currency)
+		if err != nil {
+			return nil, fmt.Errorf("failed to convert price history to %s: %w", query.Currency, err)
+		}
+		history.convert(rate)
+		history.Currency = query.Currency
+	}
+	return history, nil
+}
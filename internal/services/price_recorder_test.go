@@ -0,0 +1,193 @@
+package services
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"chain/internal/config"
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRecordPrices(t *testing.T) {
+	provider := &stubPriceProvider{name: "primary", prices: map[string]float64{"bitcoin": 65000}}
+	service := newPriceServiceWithProviders("", provider)
+	service.historyInterval = 5 * time.Minute
+	service.historyCoins = []string{"bitcoin"}
+	service.historyTokens = []common.Address{common.HexToAddress(CAKEAddress), common.HexToAddress(BUSDAddress)}
+	service.tokens = stubTokenPrices{
+		strings.ToLower(CAKEAddress): {TokenSymbol: "Cake", PriceInUSD: "2.5", Volume24h: "1000000"},
+	}
+
+	// 记录不使用缓存中的旧价格
+	service.cache = newCache(NewMemoryCacheBackend(), config.CacheConfig{})
+	_, err := service.GetMultipleCryptoPrices(context.Background(), service.historyCoins)
+	require.NoError(t, err)
+	provider.prices["bitcoin"] = 66000
+
+	// 没有流动性的代币报告错误，其他资产照常记录
+	count, err := service.RecordPrices(context.Background())
+	assert.Equal(t, 2, count)
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "no liquidity pool found")
+
+	// 同一周期内重复记录被忽略
+	_, _ = service.RecordPrices(context.Background())
+
+	now := time.Now()
+	coins, err := service.history.GetPrices("bitcoin", now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	require.Len(t, coins, 1)
+	assert.Equal(t, 66000.0, coins[0].Price)
+	assert.Equal(t, HistoricalAssetCoin, coins[0].Kind)
+	assert.Equal(t, "primary", coins[0].Source)
+	assert.Zero(t, coins[0].Timestamp.UnixNano()%int64(5*time.Minute))
+
+	tokens, err := service.history.GetPrices(strings.ToLower(CAKEAddress), now.Add(-time.Hour), now)
+	require.NoError(t, err)
+	require.Len(t, tokens, 1)
+	assert.Equal(t, 2.5, tokens[0].Price)
+	assert.Equal(t, 1000000.0, tokens[0].Volume24h)
+	assert.Equal(t, PriceProviderBSC, tokens[0].Source)
+
+	// 没有设置BSC服务时代币无法记录
+	service.tokens = nil
+	_, err = service.RecordPrices(context.Background())
+	assert.EqualError(t, err, "BSC service not configured for price history")
+
+	// 记录间隔为0时不启动
+	service.historyInterval = 0
+	service.RunPriceHistoryRecorder(context.Background())
+}
+
+func TestPriceHistoryServedFromLocalRecords(t *testing.T) {
+	provider := &stubPriceProvider{name: "primary", prices: map[string]float64{"bitcoin": 60000}}
+	service := newPriceServiceWithProviders("", provider)
+	service.historyInterval = time.Hour
+
+	// 本地记录覆盖最近两天
+	to := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	var records []*models.HistoricalPrice
+	for i := 48; i >= 1; i-- {
+		records = append(records, &models.HistoricalPrice{
+			Asset: "bitcoin", Kind: HistoricalAssetCoin, Price: 65000 + float64(i), Timestamp: to.Add(-time.Duration(i) * time.Hour),
+		})
+	}
+	require.NoError(t, service.history.SavePrices(records))
+
+	// 记录覆盖的范围不查询数据源
+	history, err := service.GetPriceHistory(context.Background(), "bitcoin", HistoryQuery{From: to.Add(-24 * time.Hour), To: to, Interval: "1h"})
+	require.NoError(t, err)
+	assert.Equal(t, PriceSourceLocal, history.Source)
+	assert.Equal(t, []string{PriceSourceLocal}, history.Sources)
+	assert.Len(t, history.Points, 24)
+	assert.Len(t, history.Candles, 24)
+	assert.Empty(t, provider.histories)
+
+	// 记录之前的范围由数据源补充
+	history, err = service.GetPriceHistory(context.Background(), "bitcoin", HistoryQuery{From: to.Add(-72 * time.Hour), To: to, Interval: "1h"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{PriceSourceLocal, "primary"}, history.Sources)
+	require.Len(t, provider.histories, 1)
+	assert.Equal(t, to.Add(-72*time.Hour), provider.histories[0].From)
+	assert.Equal(t, to.Add(-48*time.Hour-time.Second), provider.histories[0].To)
+	assert.Equal(t, CurrencyUSD, provider.histories[0].Currency)
+	require.Len(t, history.Points, 49)
+	assert.Equal(t, 60000.0, history.Points[0].Price)
+	assert.Equal(t, 65048.0, history.Points[1].Price)
+
+	// 数据源失败时只返回本地记录，非USD计价按汇率换算
+	provider.err = assert.AnError
+	service.fx = &stubFXRates{rates: map[string]float64{"eur": 0.5}}
+	history, err = service.GetPriceHistory(context.Background(), "bitcoin", HistoryQuery{From: to.Add(-96 * time.Hour), To: to, Interval: "1d", Currency: "eur"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{PriceSourceLocal}, history.Sources)
+	assert.Len(t, history.Points, 48)
+	assert.True(t, history.Converted)
+	assert.Equal(t, CurrencyEUR, history.Currency)
+	assert.Equal(t, 32524.0, history.Points[0].Price)
+}
+
+func TestPriceHistoryFillsInteriorGaps(t *testing.T) {
+	provider := &stubPriceProvider{name: "primary", prices: map[string]float64{"bitcoin": 60000}}
+	service := newPriceServiceWithProviders("", provider)
+	service.historyInterval = time.Hour
+
+	// 本地记录覆盖最近一天，缺少第10到第14小时
+	to := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+	var records []*models.HistoricalPrice
+	for i := 24; i >= 0; i-- {
+		if i >= 10 && i <= 14 {
+			continue
+		}
+		records = append(records, &models.HistoricalPrice{
+			Asset: "bitcoin", Kind: HistoricalAssetCoin, Price: 65000, Timestamp: to.Add(-time.Duration(i) * time.Hour),
+		})
+	}
+	require.NoError(t, service.history.SavePrices(records))
+
+	history, err := service.GetPriceHistory(context.Background(), "bitcoin", HistoryQuery{From: to.Add(-24 * time.Hour), To: to, Interval: "1h"})
+	require.NoError(t, err)
+	assert.Equal(t, []string{PriceSourceLocal, "primary"}, history.Sources)
+	require.Len(t, provider.histories, 1)
+	assert.Equal(t, to.Add(-15*time.Hour+time.Second), provider.histories[0].From)
+	assert.Equal(t, to.Add(-9*time.Hour-time.Second), provider.histories[0].To)
+	assert.Len(t, history.Points, 21)
+
+	// 空缺过多时合并为一次查询，只保留空缺内的价格点
+	provider.histories = nil
+	var sparse []*models.HistoricalPrice
+	for i := 0; i <= 24; i += 4 {
+		sparse = append(sparse, &models.HistoricalPrice{
+			Asset: "ethereum", Kind: HistoricalAssetCoin, Price: 3000, Timestamp: to.Add(-time.Duration(i) * time.Hour),
+		})
+	}
+	require.NoError(t, service.history.SavePrices(sparse))
+	history, err = service.GetPriceHistory(context.Background(), "ethereum", HistoryQuery{From: to.Add(-24 * time.Hour), To: to, Interval: "1h"})
+	require.NoError(t, err)
+	require.Len(t, provider.histories, 1)
+	assert.Equal(t, to.Add(-24*time.Hour+time.Second), provider.histories[0].From)
+	assert.Equal(t, to.Add(-time.Second), provider.histories[0].To)
+	assert.Len(t, history.Points, 8)
+}
+
+func TestMemoryHistoryStore(t *testing.T) {
+	store := NewMemoryHistoryStore()
+	base := time.Date(2026, 1, 10, 0, 0, 0, 0, time.UTC)
+
+	// 乱序保存，资产和时间相同的记录被忽略
+	require.NoError(t, store.SavePrices([]*models.HistoricalPrice{
+		{Asset: "bitcoin", Price: 3, Timestamp: base.Add(2 * time.Hour)},
+		{Asset: "bitcoin", Price: 1, Timestamp: base},
+		{Asset: "ethereum", Price: 10, Timestamp: base.Add(time.Hour)},
+		{Asset: "bitcoin", Price: 2, Timestamp: base.Add(time.Hour)},
+	}))
+	require.NoError(t, store.SavePrices([]*models.HistoricalPrice{
+		{Asset: "bitcoin", Price: 99, Timestamp: base.Add(time.Hour)},
+	}))
+
+	prices, err := store.GetPrices("bitcoin", base, base.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, prices, 3)
+	for i, price := range prices {
+		assert.Equal(t, float64(i+1), price.Price)
+	}
+
+	prices, err = store.GetPrices("bitcoin", base.Add(30*time.Minute), base.Add(90*time.Minute))
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, 2.0, prices[0].Price)
+
+	// 清理过期记录，没有剩余记录的资产被移除
+	require.NoError(t, store.DeletePricesBefore(base.Add(90*time.Minute)))
+	prices, err = store.GetPrices("bitcoin", base, base.Add(2*time.Hour))
+	require.NoError(t, err)
+	require.Len(t, prices, 1)
+	assert.Equal(t, 3.0, prices[0].Price)
+	assert.NotContains(t, store.(*memoryHistoryStore).prices, "ethereum")
+}
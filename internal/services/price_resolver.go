@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sort"
+	"strings"
+	"sync"
+	"time"
+
+	"chain/internal/config"
+	"chain/internal/models"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 币种解析方式
+const (
+	CoinMatchOverride = "override" // 配置的固定映射
+	CoinMatchID       = "id"       // 币种ID
+	CoinMatchAddress  = "address"  // BSC合约地址
+	CoinMatchSymbol   = "symbol"   // 符号，同符号时取市值排名最高的
+	CoinMatchName     = "name"     // 名称
+	CoinMatchNone     = "none"     // 未找到，原样作为币种ID使用
+)
+
+// coinListRetryDelay 刷新币种列表失败后的重试间隔
+const coinListRetryDelay = 5 * time.Minute
+
+// coinListSource 提供完整币种列表的数据源，由coinGeckoProvider实现
+type coinListSource interface {
+	CoinList(ctx context.Context, rankPages int) ([]*models.Coin, error)
+}
+
+// coinKeyer 由数据源实现，将解析后的币种转换为该数据源的查询标识，返回空表示不支持该币种
+// 未实现的数据源使用币种ID
+type coinKeyer interface {
+	coinKey(coin *models.Coin) string
+}
+
+// ResolvedCoin 符号解析结果
+type ResolvedCoin struct {
+	models.Coin
+	Query     string `json:"query"`
+	MatchedBy string `json:"matched_by"`
+}
+
+// CoinResolver 维护符号、名称和BSC合约地址到币种ID的映射
+// 币种列表定期从CoinGecko刷新并持久化，同符号的币种取市值排名最高的，配置的固定映射优先
+type CoinResolver struct {
+	source    coinListSource // nil时不刷新
+	rankPages int
+	interval  time.Duration
+	overrides map[string]string // 小写的查询 => 币种ID
+
+	mu          sync.RWMutex
+	store       CoinStore
+	byID        map[string]*models.Coin
+	bySymbol    map[string][]*models.Coin // 按市值排名排列
+	byName      map[string][]*models.Coin
+	byAddress   map[string][]*models.Coin // 小写地址
+	refreshedAt time.Time
+}
+
+// newCoinResolver 创建符号解析器，使用内存存储
+func newCoinResolver(source coinListSource, cfg config.PriceConfig) *CoinResolver {
+	r := &CoinResolver{
+		source:    source,
+		rankPages: cfg.CoinRankPages,
+		interval:  time.Duration(cfg.CoinListRefresh) * time.Second,
+		overrides: make(map[string]string, len(cfg.SymbolOverrides)),
+	}
+	for query, id := range cfg.SymbolOverrides {
+		r.overrides[strings.ToLower(strings.TrimSpace(query))] = strings.ToLower(strings.TrimSpace(id))
+	}
+	r.SetStore(NewMemoryCoinStore())
+	return r
+}
+
+// SetStore 设置币种存储并加载已保存的币种列表
+func (r *CoinResolver) SetStore(store CoinStore) {
+	coins, err := store.ListCoins()
+	if err != nil {
+		logger.Warnf("Failed to load coin list: %v", err)
+	}
+
+	var refreshedAt time.Time
+	for _, coin := range coins {
+		if coin.UpdatedAt.After(refreshedAt) {
+			refreshedAt = coin.UpdatedAt
+		}
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.store = store
+	r.index(coins)
+	r.refreshedAt = refreshedAt
+}
+
+// index 重建查询索引，调用方持有写锁
+func (r *CoinResolver) index(coins []*models.Coin) {
+	r.byID = make(map[string]*models.Coin, len(coins))
+	r.bySymbol = make(map[string][]*models.Coin)
+	r.byName = make(map[string][]*models.Coin)
+	r.byAddress = make(map[string][]*models.Coin)
+	for _, coin := range coins {
+		r.byID[coin.CoinID] = coin
+		r.bySymbol[strings.ToLower(coin.Symbol)] = append(r.bySymbol[strings.ToLower(coin.Symbol)], coin)
+		r.byName[strings.ToLower(coin.Name)] = append(r.byName[strings.ToLower(coin.Name)], coin)
+		if coin.BSCAddress != "" {
+			r.byAddress[strings.ToLower(coin.BSCAddress)] = append(r.byAddress[strings.ToLower(coin.BSCAddress)], coin)
+		}
+	}
+	for _, index := range []map[string][]*models.Coin{r.bySymbol, r.byName, r.byAddress} {
+		for _, candidates := range index {
+			sort.SliceStable(candidates, func(i, j int) bool {
+				return betterCoin(candidates[i], candidates[j])
+			})
+		}
+	}
+}
+
+// betterCoin 判断a是否排在b之前，市值排名相同时按币种ID
+func betterCoin(a, b *models.Coin) bool {
+	if a.MarketCapRank != b.MarketCapRank {
+		return higherRank(a, b)
+	}
+	return a.CoinID < b.CoinID
+}
+
+// higherRank 判断a的市值排名是否高于b，没有排名的最低
+func higherRank(a, b *models.Coin) bool {
+	if a.MarketCapRank == 0 || b.MarketCapRank == 0 {
+		return a.MarketCapRank != 0 && b.MarketCapRank == 0
+	}
+	return a.MarketCapRank < b.MarketCapRank
+}
+
+// Refresh 从数据源重新读取币种列表并保存，返回币种数量
+func (r *CoinResolver) Refresh(ctx context.Context) (int, error) {
+	if r.source == nil {
+		return 0, errors.New("no coin list source configured")
+	}
+	coins, err := r.source.CoinList(ctx, r.rankPages)
+	if err != nil {
+		return 0, err
+	}
+	if len(coins) == 0 {
+		return 0, errors.New("coin list is empty")
+	}
+
+	r.mu.RLock()
+	store := r.store
+	r.mu.RUnlock()
+	if err := store.SaveCoins(coins); err != nil {
+		logger.Warnf("Failed to save coin list: %v", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	r.index(coins)
+	r.refreshedAt = time.Now()
+	return len(coins), nil
+}
+
+// Run 按刷新间隔持续刷新币种列表，直到ctx取消；已保存的列表未过期时等到过期再刷新
+// 刷新间隔为0或没有数据源时不启动
+func (r *CoinResolver) Run(ctx context.Context) {
+	if r.interval <= 0 || r.source == nil {
+		logger.Info("Coin list refresher disabled")
+		return
+	}
+
+	r.mu.RLock()
+	delay := time.Until(r.refreshedAt.Add(r.interval))
+	r.mu.RUnlock()
+
+	for {
+		if delay > 0 {
+			timer := time.NewTimer(delay)
+			select {
+			case <-ctx.Done():
+				timer.Stop()
+				return
+			case <-timer.C:
+			}
+		}
+
+		count, err := r.Refresh(ctx)
+		if err != nil {
+			logger.Warnf("Failed to refresh coin list: %v", err)
+			delay = coinListRetryDelay
+			continue
+		}
+		logger.Infof("Refreshed %d coins", count)
+		delay = r.interval
+	}
+}
+
+// Resolve 将用户输入的符号、名称、币种ID或BSC合约地址解析为币种
+// 依次匹配固定映射、合约地址、币种ID和符号（取市值排名最高的，同排名时币种ID优先）、名称，
+// 都未找到时原样作为币种ID
+func (r *CoinResolver) Resolve(query string) *ResolvedCoin {
+	q := strings.ToLower(strings.TrimSpace(query))
+
+	r.mu.RLock()
+	defer r.mu.RUnlock()
+
+	if id, ok := r.overrides[q]; ok {
+		if coin, ok := r.byID[id]; ok {
+			return resolvedCoin(query, CoinMatchOverride, coin)
+		}
+		return resolvedCoin(query, CoinMatchOverride, &models.Coin{CoinID: id, Symbol: q})
+	}
+
+	if common.IsHexAddress(q) {
+		if candidates := r.byAddress[q]; len(candidates) > 0 {
+			return resolvedCoin(query, CoinMatchAddress, candidates[0])
+		}
+		return resolvedCoin(query, CoinMatchNone, &models.Coin{CoinID: q, Symbol: q, BSCAddress: common.HexToAddress(q).Hex()})
+	}
+
+	if coin, ok := r.byID[q]; ok {
+		if candidates := r.bySymbol[q]; len(candidates) > 0 && higherRank(candidates[0], coin) {
+			return resolvedCoin(query, CoinMatchSymbol, candidates[0])
+		}
+		return resolvedCoin(query, CoinMatchID, coin)
+	}
+	if candidates := r.bySymbol[q]; len(candidates) > 0 {
+		return resolvedCoin(query, CoinMatchSymbol, candidates[0])
+	}
+	if candidates := r.byName[q]; len(candidates) > 0 {
+		return resolvedCoin(query, CoinMatchName, candidates[0])
+	}
+	return resolvedCoin(query, CoinMatchNone, &models.Coin{CoinID: q, Symbol: q})
+}
+
+// resolvedCoin 复制币种作为解析结果
+func resolvedCoin(query, matchedBy string, coin *models.Coin) *ResolvedCoin {
+	return &ResolvedCoin{Coin: *coin, Query: query, MatchedBy: matchedBy}
+}
+
+// coinKey 返回数据源查询该币种使用的标识
+func coinKey(provider PriceProvider, coin *models.Coin) string {
+	if keyer, ok := provider.(coinKeyer); ok {
+		return keyer.coinKey(coin)
+	}
+	return coin.CoinID
+}
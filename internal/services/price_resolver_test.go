@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+	"time"
+
+	"chain/internal/config"
+	"chain/internal/models"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubCoinList 模拟CoinGecko的币种列表和市值排名
+func stubCoinList(t *testing.T) *httptest.Server {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		switch r.URL.Path {
+		case "/coins/list":
+			assert.Equal(t, "true", r.URL.Query().Get("include_platform"))
+			w.Write([]byte(`[
+				{"id":"uniswap","symbol":"UNI","name":"Uniswap","platforms":{"ethereum":"0x1f9840a85d5af5bf1d1762f925bdaddc4201f984","binance-smart-chain":"0xbf5140a22578168fd562dccf235e5d43a02ce9b1"}},
+				{"id":"universe-token","symbol":"uni","name":"Universe","platforms":{}},
+				{"id":"pancakeswap-token","symbol":"cake","name":"PancakeSwap","platforms":{"binance-smart-chain":"0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82"}},
+				{"id":"bitcoin","symbol":"btc","name":"Bitcoin","platforms":{"":""}}
+			]`))
+		case "/coins/markets":
+			assert.Equal(t, "250", r.URL.Query().Get("per_page"))
+			assert.Equal(t, "1", r.URL.Query().Get("page"))
+			w.Write([]byte(`[{"id":"bitcoin","market_cap_rank":1},{"id":"uniswap","market_cap_rank":20},{"id":"pancakeswap-token","market_cap_rank":90}]`))
+		default:
+			w.WriteHeader(http.StatusNotFound)
+		}
+	}))
+	t.Cleanup(server.Close)
+	return server
+}
+
+// newTestCoinResolver 创建使用模拟币种列表的解析器并刷新一次
+func newTestCoinResolver(t *testing.T, overrides map[string]string) *CoinResolver {
+	server := stubCoinList(t)
+	resolver := newCoinResolver(newCoinGeckoProvider(server.URL, "", server.Client()), config.PriceConfig{
+		CoinRankPages:   2,
+		SymbolOverrides: overrides,
+	})
+	count, err := resolver.Refresh(context.Background())
+	require.NoError(t, err)
+	require.Equal(t, 4, count)
+	return resolver
+}
+
+func TestCoinResolver(t *testing.T) {
+	resolver := newTestCoinResolver(t, map[string]string{"Cake": "pancakeswap-token", "WBTC": "wrapped-bitcoin"})
+
+	tests := []struct {
+		query     string
+		coinID    string
+		matchedBy string
+	}{
+		{"UNI", "uniswap", CoinMatchSymbol}, // 同符号取市值排名最高的
+		{"bitcoin", "bitcoin", CoinMatchID},
+		{"Universe", "universe-token", CoinMatchName},
+		{"0xBf5140A22578168FD562DCcF235E5D43A02ce9B1", "uniswap", CoinMatchAddress},
+		{"cake", "pancakeswap-token", CoinMatchOverride},
+		{"wbtc", "wrapped-bitcoin", CoinMatchOverride}, // 列表中没有的币种也可以固定映射
+		{"Dogecoin", "dogecoin", CoinMatchNone},
+	}
+	for _, tt := range tests {
+		coin := resolver.Resolve(tt.query)
+		assert.Equal(t, tt.coinID, coin.CoinID, tt.query)
+		assert.Equal(t, tt.matchedBy, coin.MatchedBy, tt.query)
+		assert.Equal(t, tt.query, coin.Query)
+	}
+
+	// 解析结果带有BSC合约地址，未收录的地址原样保留
+	assert.Equal(t, "0x0E09FaBB73Bd3Ade0a17ECC321fD13a19e81cE82", resolver.Resolve("cake").BSCAddress)
+	assert.Equal(t, BUSDAddress, resolver.Resolve(strings.ToLower(BUSDAddress)).BSCAddress)
+	assert.Empty(t, resolver.Resolve("btc").BSCAddress)
+}
+
+func TestCoinResolverLoadsStoredList(t *testing.T) {
+	store := NewMemoryCoinStore()
+	resolver := newTestCoinResolver(t, nil)
+	resolver.SetStore(store)
+	_, err := resolver.Refresh(context.Background())
+	require.NoError(t, err)
+
+	coins, err := store.ListCoins()
+	require.NoError(t, err)
+	assert.Len(t, coins, 4)
+
+	// 新的解析器从存储加载列表，不需要刷新
+	loaded := newCoinResolver(nil, config.PriceConfig{})
+	loaded.SetStore(store)
+	assert.Equal(t, "uniswap", loaded.Resolve("uni").CoinID)
+	assert.Equal(t, CoinMatchSymbol, loaded.Resolve("uni").MatchedBy)
+}
+
+func TestCoinResolverRunWaitsForStaleList(t *testing.T) {
+	var calls int
+	source := coinListFunc(func(ctx context.Context, rankPages int) ([]*models.Coin, error) {
+		calls++
+		return []*models.Coin{{CoinID: "bitcoin", Symbol: "btc", Name: "Bitcoin"}}, nil
+	})
+
+	store := NewMemoryCoinStore()
+	require.NoError(t, store.SaveCoins([]*models.Coin{{CoinID: "ethereum", Symbol: "eth", UpdatedAt: time.Now()}}))
+	resolver := newCoinResolver(source, config.PriceConfig{CoinListRefresh: 3600})
+	resolver.SetStore(store)
+
+	// 已保存的列表未过期，不立即刷新
+	ctx, cancel := context.WithTimeout(context.Background(), 50*time.Millisecond)
+	defer cancel()
+	resolver.Run(ctx)
+	assert.Equal(t, 0, calls)
+	assert.Equal(t, "ethereum", resolver.Resolve("eth").CoinID)
+
+	// 刷新间隔为0时不启动
+	newCoinResolver(source, config.PriceConfig{}).Run(context.Background())
+	assert.Equal(t, 0, calls)
+}
+
+// coinListFunc 以函数实现币种列表数据源
+type coinListFunc func(ctx context.Context, rankPages int) ([]*models.Coin, error)
+
+func (f coinListFunc) CoinList(ctx context.Context, rankPages int) ([]*models.Coin, error) {
+	return f(ctx, rankPages)
+}
+
+func TestPriceServiceResolvesProviderKeys(t *testing.T) {
+	var tickers []string
+	binance := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		tickers = append(tickers, r.URL.Query().Get("symbol"))
+		json.NewEncoder(w).Encode(map[string]interface{}{"symbol": "UNIUSDT", "lastPrice": "7.5", "closeTime": 1767225600000})
+	}))
+	defer binance.Close()
+
+	dex := &dexPriceProvider{}
+	dex.setSource(stubTokenPrices{
+		strings.ToLower(CAKEAddress): {TokenName: "PancakeSwap Token", TokenSymbol: "Cake", PriceInUSD: "2.5"},
+	})
+	service := newPriceServiceWithProviders("", dex, newBinanceProvider(binance.URL, binance.Client()))
+	service.resolver = newTestCoinResolver(t, nil)
+
+	// CAKE由bsc数据源按合约地址查询；UNI在链上没有流动性，由Binance按符号补充
+	prices, err := service.GetMultipleCryptoPrices(context.Background(), []string{"cake", "Uniswap"})
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	assert.Equal(t, PriceProviderBSC, prices["cake"].Source)
+	assert.Equal(t, "pancakeswap-token", prices["cake"].CoinID)
+	assert.Equal(t, PriceProviderBinance, prices["uni"].Source)
+	assert.Equal(t, "uniswap", prices["uni"].CoinID)
+	assert.Equal(t, []string{"UNIUSDT"}, tickers)
+
+	price, err := service.GetCryptoPrice(context.Background(), "0x0e09fabb73bd3ade0a17ecc321fd13a19e81ce82")
+	require.NoError(t, err)
+	assert.Equal(t, 2.5, price.CurrentPrice)
+	assert.Equal(t, "pancakeswap-token", price.CoinID)
+}
@@ -2,322 +2,651 @@ package services
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
-	"io"
 	"net/http"
+	"sort"
+	"strconv"
 	"strings"
+	"sync"
 	"time"
 
 	"chain/internal/config"
+	"chain/pkg/logger"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 行情服务默认参数
+const (
+	defaultPriceTimeout      = 30 * time.Second
+	defaultRateLimitCooldown = time.Minute
 )
 
-// PriceService 价格服务
+// ErrPriceNotFound 数据源没有该币种的价格
+var ErrPriceNotFound = errors.New("no price data found")
+
+// PriceService 价格服务，按优先级依次使用各行情数据源
 type PriceService struct {
-	config     *config.Config
-	httpClient *http.Client
+	config    *config.Config
+	providers []*priceProviderState // 按优先级排列
+	aggregate string                // 为 median 时同时查询所有数据源取中位数
+	cooldown  time.Duration         // 数据源被限流后的默认暂停时间
+	dex       *dexPriceProvider     // 配置了bsc数据源时非nil
+	cache     *Cache                // nil表示不缓存
+	resolver  *CoinResolver         // 将用户输入解析为各数据源的查询标识
+	fx        fxRateSource          // 法币汇率，用于换算数据源缺少的计价货币
+
+	history          PriceHistoryStore // 本地价格历史，查询历史时优先使用
+	historyInterval  time.Duration     // 记录价格历史的间隔，0表示不记录
+	historyRetention time.Duration     // 本地价格历史的保留时间
+	historyCoins     []string          // 定期记录价格的币种
+	historyTokens    []common.Address  // 定期记录DEX价格的BSC代币
+	tokens           tokenPriceSource  // 查询BSC代币价格，未设置BSC服务时为nil
 }
 
-// CoinGeckoPriceResponse CoinGecko API响应
-type CoinGeckoPriceResponse struct {
-	ID                string             `json:"id"`
-	Symbol            string             `json:"symbol"`
-	Name              string             `json:"name"`
-	CurrentPrice      float64            `json:"current_price"`
-	MarketCap         float64            `json:"market_cap"`
-	MarketCapRank     int                `json:"market_cap_rank"`
-	TotalVolume       float64            `json:"total_volume"`
-	High24h           float64            `json:"high_24h"`
-	Low24h            float64            `json:"low_24h"`
-	PriceChange24h    float64            `json:"price_change_24h"`
-	PriceChangePercent24h float64        `json:"price_change_percentage_24h"`
-	LastUpdated       string             `json:"last_updated"`
+// priceProviderState 数据源及其限流状态
+type priceProviderState struct {
+	provider PriceProvider
+
+	mu           sync.Mutex
+	limitedUntil time.Time
 }
 
 // CryptoPriceInfo 加密货币价格信息
 type CryptoPriceInfo struct {
-	Symbol            string    `json:"symbol"`
-	Name              string    `json:"name"`
-	CurrentPrice      float64   `json:"current_price"`
-	MarketCap         float64   `json:"market_cap"`
-	Volume24h         float64   `json:"volume_24h"`
-	PriceChange24h    float64   `json:"price_change_24h"`
-	PriceChangePercent24h float64 `json:"price_change_percent_24h"`
-	LastUpdated       time.Time `json:"last_updated"`
+	CoinID                string    `json:"coin_id"` // 解析得到的CoinGecko币种ID
+	Symbol                string    `json:"symbol"`
+	Name                  string    `json:"name"`
+	CurrentPrice          float64   `json:"current_price"`
+	MarketCap             float64   `json:"market_cap"`
+	Volume24h             float64   `json:"volume_24h"`
+	PriceChange24h        float64   `json:"price_change_24h"`
+	PriceChangePercent24h float64   `json:"price_change_percent_24h"`
+	LastUpdated           time.Time `json:"last_updated"`
+	Source                string    `json:"source"`            // 产生该结果的数据源，中位数聚合时为 median
+	Sources               []string  `json:"sources,omitempty"` // 中位数聚合时参与计算的数据源
+
+	// Quotes 请求的计价货币（小写）=> 报价，未指定计价货币时为空；顶层价格字段始终为USD
+	Quotes map[string]*PriceQuote `json:"quotes,omitempty"`
 }
 
-// NewPriceService 创建价格服务
+// NewPriceService 创建价格服务，未配置数据源时只使用CoinGecko
 func NewPriceService(cfg *config.Config) *PriceService {
-	return &PriceService{
-		config: cfg,
-		httpClient: &http.Client{
-			Timeout: 30 * time.Second,
-		},
+	timeout := defaultPriceTimeout
+	if cfg.Price.Timeout > 0 {
+		timeout = time.Duration(cfg.Price.Timeout) * time.Second
+	}
+	httpClient := &http.Client{Timeout: timeout}
+
+	cooldown := defaultRateLimitCooldown
+	if cfg.Price.RateLimitCooldown > 0 {
+		cooldown = time.Duration(cfg.Price.RateLimitCooldown) * time.Second
+	}
+
+	historyRetention := defaultHistoryRetention
+	if cfg.Price.HistoryRetention > 0 {
+		historyRetention = time.Duration(cfg.Price.HistoryRetention) * time.Second
+	}
+
+	historyTokens := make([]common.Address, 0, len(cfg.Price.HistoryTokens))
+	for _, token := range cfg.Price.HistoryTokens {
+		historyTokens = append(historyTokens, common.HexToAddress(token))
+	}
+
+	p := &PriceService{
+		config:           cfg,
+		aggregate:        strings.ToLower(cfg.Price.Aggregate),
+		cooldown:         cooldown,
+		cache:            defaultCache(cfg),
+		history:          NewMemoryHistoryStore(),
+		historyInterval:  time.Duration(cfg.Price.HistoryInterval) * time.Second,
+		historyRetention: historyRetention,
+		historyCoins:     cfg.Price.HistoryCoins,
+		historyTokens:    historyTokens,
+	}
+
+	providerConfigs := make([]config.PriceProviderConfig, len(cfg.Price.Providers))
+	copy(providerConfigs, cfg.Price.Providers)
+	sort.SliceStable(providerConfigs, func(i, j int) bool {
+		return providerConfigs[i].Priority < providerConfigs[j].Priority
+	})
+
+	// 币种列表从第一个CoinGecko数据源刷新
+	var coinList *coinGeckoProvider
+	for _, providerCfg := range providerConfigs {
+		var provider PriceProvider
+		switch strings.ToLower(providerCfg.Type) {
+		case PriceProviderCoinGecko:
+			baseURL := providerCfg.APIURL
+			if baseURL == "" {
+				baseURL = cfg.Price.APIURL
+			}
+			coinGecko := newCoinGeckoProvider(baseURL, providerCfg.APIKey, httpClient)
+			if coinList == nil {
+				coinList = coinGecko
+			}
+			provider = coinGecko
+		case PriceProviderBinance:
+			provider = newBinanceProvider(providerCfg.APIURL, httpClient)
+		case PriceProviderCoinMarketCap:
+			provider = newCoinMarketCapProvider(providerCfg.APIURL, providerCfg.APIKey, httpClient)
+		case PriceProviderBSC:
+			if p.dex == nil {
+				p.dex = &dexPriceProvider{}
+			}
+			provider = p.dex
+		default:
+			logger.Warnf("Unknown price provider type: %s", providerCfg.Type)
+			continue
+		}
+		p.providers = append(p.providers, &priceProviderState{provider: provider})
 	}
-}
 
-// GetCryptoPrice 获取加密货币价格
-func (p *PriceService) GetCryptoPrice(ctx context.Context, symbol string) (*CryptoPriceInfo, error) {
-	// 使用CoinGecko免费API
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&ids=%s&order=market_cap_desc&per_page=1&page=1", strings.ToLower(symbol))
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	if len(p.providers) == 0 {
+		coinList = newCoinGeckoProvider(cfg.Price.APIURL, "", httpClient)
+		p.providers = append(p.providers, &priceProviderState{provider: coinList})
 	}
-
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch price data: %w", err)
+	if coinList == nil {
+		coinList = newCoinGeckoProvider(cfg.Price.APIURL, "", httpClient)
 	}
-	defer resp.Body.Close()
+	p.resolver = newCoinResolver(coinList, cfg.Price)
+	p.fx = newERAPIRates(cfg.Price.FXAPIURL, httpClient)
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
-	}
+	return p
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+// newPriceServiceWithProviders 使用指定的数据源创建价格服务
+func newPriceServiceWithProviders(aggregate string, providers ...PriceProvider) *PriceService {
+	p := &PriceService{
+		config:           &config.Config{},
+		aggregate:        aggregate,
+		cooldown:         defaultRateLimitCooldown,
+		resolver:         newCoinResolver(nil, config.PriceConfig{}),
+		history:          NewMemoryHistoryStore(),
+		historyRetention: defaultHistoryRetention,
 	}
-
-	var prices []CoinGeckoPriceResponse
-	if err := json.Unmarshal(body, &prices); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	for _, provider := range providers {
+		p.providers = append(p.providers, &priceProviderState{provider: provider})
 	}
+	return p
+}
 
-	if len(prices) == 0 {
-		return nil, fmt.Errorf("no price data found for symbol: %s", symbol)
+// SetBSCService 设置bsc数据源和价格历史记录使用的BSC服务，未设置时bsc数据源查询失败并转移到下一个数据源
+func (p *PriceService) SetBSCService(bscService *BSCService) {
+	p.tokens = bscService
+	if p.dex != nil {
+		p.dex.setSource(bscService)
 	}
+}
 
-	price := prices[0]
-	lastUpdated, _ := time.Parse(time.RFC3339, price.LastUpdated)
+// SetCache 设置行情缓存，默认使用内存缓存，nil表示不缓存
+func (p *PriceService) SetCache(cache *Cache) {
+	p.cache = cache
+}
 
-	return &CryptoPriceInfo{
-		Symbol:                price.Symbol,
-		Name:                  price.Name,
-		CurrentPrice:          price.CurrentPrice,
-		MarketCap:             price.MarketCap,
-		Volume24h:             price.TotalVolume,
-		PriceChange24h:        price.PriceChange24h,
-		PriceChangePercent24h: price.PriceChangePercent24h,
-		LastUpdated:           lastUpdated,
-	}, nil
+// SetCoinStore 设置币种列表存储并加载已保存的列表，默认使用内存存储
+func (p *PriceService) SetCoinStore(store CoinStore) {
+	p.resolver.SetStore(store)
 }
 
-// GetMultipleCryptoPrices 批量获取加密货币价格
-func (p *PriceService) GetMultipleCryptoPrices(ctx context.Context, symbols []string) (map[string]*CryptoPriceInfo, error) {
-	if len(symbols) == 0 {
-		return nil, fmt.Errorf("no symbols provided")
-	}
+// SetHistoryStore 设置本地价格历史存储，默认使用内存存储
+func (p *PriceService) SetHistoryStore(store PriceHistoryStore) {
+	p.history = store
+}
 
-	// 将符号转换为小写并用逗号连接
-	idsParam := strings.ToLower(strings.Join(symbols, ","))
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&ids=%s&order=market_cap_desc&per_page=%d&page=1", idsParam, len(symbols))
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+// RunCoinListRefresher 按配置的间隔从CoinGecko刷新币种列表，直到ctx取消
+func (p *PriceService) RunCoinListRefresher(ctx context.Context) {
+	p.resolver.Run(ctx)
+}
+
+// ResolveCoin 将符号、名称、币种ID或BSC合约地址解析为币种
+func (p *PriceService) ResolveCoin(query string) *ResolvedCoin {
+	return p.resolver.Resolve(query)
+}
+
+// Providers 按优先级返回数据源名称
+func (p *PriceService) Providers() []string {
+	names := make([]string, 0, len(p.providers))
+	for _, state := range p.providers {
+		names = append(names, state.provider.Name())
 	}
+	return names
+}
 
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch price data: %w", err)
+// available 返回未处于限流暂停期的数据源
+func (p *PriceService) available() []*priceProviderState {
+	now := time.Now()
+	states := make([]*priceProviderState, 0, len(p.providers))
+	for _, state := range p.providers {
+		state.mu.Lock()
+		limited := now.Before(state.limitedUntil)
+		state.mu.Unlock()
+		if !limited {
+			states = append(states, state)
+		}
 	}
-	defer resp.Body.Close()
+	return states
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
+// recordFailure 记录数据源错误，限流时暂停使用该数据源
+func (p *PriceService) recordFailure(state *priceProviderState, op string, err error) {
+	var limited *RateLimitError
+	if errors.As(err, &limited) {
+		pause := p.cooldown
+		if limited.RetryAfter > 0 {
+			pause = limited.RetryAfter
+		}
+		state.mu.Lock()
+		state.limitedUntil = time.Now().Add(pause)
+		state.mu.Unlock()
+		logger.Warnf("Price provider %s rate limited, paused for %s", state.provider.Name(), pause)
+		return
 	}
+	if !errors.Is(err, ErrPriceNotSupported) {
+		logger.Warnf("Price provider %s failed to %s: %v", state.provider.Name(), op, err)
+	}
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+// providerErrors 汇总各数据源的错误
+type providerErrors []string
+
+func (e *providerErrors) add(provider string, err error) {
+	if !errors.Is(err, ErrPriceNotSupported) {
+		*e = append(*e, fmt.Sprintf("%s: %v", provider, err))
 	}
+}
 
-	var prices []CoinGeckoPriceResponse
-	if err := json.Unmarshal(body, &prices); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+func (e providerErrors) err(op string) error {
+	if len(e) == 0 {
+		return fmt.Errorf("no available price provider supports %s", op)
 	}
+	return fmt.Errorf("all price providers failed to %s: %s", op, strings.Join(e, "; "))
+}
 
-	result := make(map[string]*CryptoPriceInfo)
-	for _, price := range prices {
-		lastUpdated, _ := time.Parse(time.RFC3339, price.LastUpdated)
-		result[price.Symbol] = &CryptoPriceInfo{
-			Symbol:                price.Symbol,
-			Name:                  price.Name,
-			CurrentPrice:          price.CurrentPrice,
-			MarketCap:             price.MarketCap,
-			Volume24h:             price.TotalVolume,
-			PriceChange24h:        price.PriceChange24h,
-			PriceChangePercent24h: price.PriceChangePercent24h,
-			LastUpdated:           lastUpdated,
+// providerQueries 将请求转换为数据源的查询标识，返回去重后的标识及每个标识对应的请求
+func providerQueries(provider PriceProvider, queries []string, coins map[string]*ResolvedCoin) ([]string, map[string][]string) {
+	var ids []string
+	byID := make(map[string][]string)
+	for _, query := range queries {
+		id := coinKey(provider, &coins[query].Coin)
+		if id == "" {
+			continue
+		}
+		if _, ok := byID[id]; !ok {
+			ids = append(ids, id)
 		}
+		byID[id] = append(byID[id], query)
 	}
+	return ids, byID
+}
 
-	return result, nil
+// resolveQueries 解析每个请求对应的币种
+func (p *PriceService) resolveQueries(queries []string) map[string]*ResolvedCoin {
+	coins := make(map[string]*ResolvedCoin, len(queries))
+	for _, query := range queries {
+		coins[query] = p.resolver.Resolve(query)
+	}
+	return coins
 }
 
-// GetTopCryptoPrices 获取市值排名前N的加密货币价格
-func (p *PriceService) GetTopCryptoPrices(ctx context.Context, limit int) ([]*CryptoPriceInfo, error) {
-	if limit <= 0 || limit > 250 {
-		limit = 10 // 默认获取前10名
+// fetchPrices 查询一组币种的价格，结果以请求的标识为键
+// 请求先解析为币种，再按各数据源的查询标识查询
+// 默认按优先级依次查询，前一个数据源出错或缺少的币种由下一个数据源补充
+func (p *PriceService) fetchPrices(ctx context.Context, queries []string) (map[string]*CryptoPriceInfo, error) {
+	coins := p.resolveQueries(queries)
+	if p.aggregate == PriceAggregateMedian {
+		return p.fetchMedianPrices(ctx, queries, coins)
 	}
 
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/markets?vs_currency=usd&order=market_cap_desc&per_page=%d&page=1", limit)
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
-	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+	states := p.available()
+	if len(states) == 0 {
+		return nil, errors.New("all price providers are rate limited")
 	}
 
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch price data: %w", err)
+	result := make(map[string]*CryptoPriceInfo, len(queries))
+	pending := queries
+	var errs providerErrors
+	for _, state := range states {
+		ids, byID := providerQueries(state.provider, pending, coins)
+		if len(ids) == 0 {
+			continue
+		}
+		prices, err := state.provider.GetPrices(ctx, ids)
+		if err != nil {
+			p.recordFailure(state, "get prices", err)
+			errs.add(state.provider.Name(), err)
+			continue
+		}
+
+		for id, info := range prices {
+			for _, query := range byID[id] {
+				found := *info
+				found.CoinID = coins[query].CoinID
+				found.Source = state.provider.Name()
+				result[query] = &found
+			}
+		}
+
+		var missing []string
+		for _, query := range pending {
+			if _, ok := result[query]; !ok {
+				missing = append(missing, query)
+			}
+		}
+		if pending = missing; len(pending) == 0 {
+			break
+		}
 	}
-	defer resp.Body.Close()
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("API request failed with status: %d", resp.StatusCode)
+	if len(result) == 0 && len(errs) > 0 {
+		return nil, errs.err("get prices")
 	}
+	return result, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
-	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+// fetchMedianPrices 同时查询所有可用数据源，每个币种取各数据源价格的中位数
+// 其余字段使用优先级最高的有效结果
+func (p *PriceService) fetchMedianPrices(ctx context.Context, queries []string, coins map[string]*ResolvedCoin) (map[string]*CryptoPriceInfo, error) {
+	states := p.available()
+	if len(states) == 0 {
+		return nil, errors.New("all price providers are rate limited")
+	}
+	results := make([]map[string]*CryptoPriceInfo, len(states))
+	failures := make([]error, len(states))
+	keys := make([]map[string]string, len(states)) // 请求 => 该数据源的查询标识
+
+	var wg sync.WaitGroup
+	for i, state := range states {
+		ids, byID := providerQueries(state.provider, queries, coins)
+		keys[i] = make(map[string]string, len(queries))
+		for id, requested := range byID {
+			for _, query := range requested {
+				keys[i][query] = id
+			}
+		}
+		if len(ids) == 0 {
+			continue
+		}
+
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			results[i], failures[i] = state.provider.GetPrices(ctx, ids)
+		}()
 	}
+	wg.Wait()
 
-	var prices []CoinGeckoPriceResponse
-	if err := json.Unmarshal(body, &prices); err != nil {
-		return nil, fmt.Errorf("failed to parse response: %w", err)
+	var errs providerErrors
+	for i, err := range failures {
+		if err != nil {
+			p.recordFailure(states[i], "get prices", err)
+			errs.add(states[i].provider.Name(), err)
+		}
 	}
 
-	var result []*CryptoPriceInfo
-	for _, price := range prices {
-		lastUpdated, _ := time.Parse(time.RFC3339, price.LastUpdated)
-		result = append(result, &CryptoPriceInfo{
-			Symbol:                price.Symbol,
-			Name:                  price.Name,
-			CurrentPrice:          price.CurrentPrice,
-			MarketCap:             price.MarketCap,
-			Volume24h:             price.TotalVolume,
-			PriceChange24h:        price.PriceChange24h,
-			PriceChangePercent24h: price.PriceChangePercent24h,
-			LastUpdated:           lastUpdated,
-		})
+	result := make(map[string]*CryptoPriceInfo, len(queries))
+	for _, query := range queries {
+		var (
+			base    *CryptoPriceInfo
+			quotes  []float64
+			sources []string
+		)
+		for i, prices := range results {
+			id, ok := keys[i][query]
+			if !ok {
+				continue
+			}
+			info, ok := prices[id]
+			if !ok || info.CurrentPrice <= 0 {
+				continue
+			}
+			if base == nil {
+				copied := *info
+				base = &copied
+			}
+			quotes = append(quotes, info.CurrentPrice)
+			sources = append(sources, states[i].provider.Name())
+		}
+		if base == nil {
+			continue
+		}
+
+		base.CoinID = coins[query].CoinID
+		if len(quotes) == 1 {
+			base.Source = sources[0]
+		} else {
+			base.CurrentPrice = median(quotes)
+			base.Source = PriceAggregateMedian
+			base.Sources = sources
+		}
+		result[query] = base
 	}
 
+	if len(result) == 0 && len(errs) > 0 {
+		return nil, errs.err("get prices")
+	}
 	return result, nil
 }
 
-// SearchCrypto 搜索加密货币
-func (p *PriceService) SearchCrypto(ctx context.Context, query string) ([]*CryptoPriceInfo, error) {
-	if query == "" {
-		return nil, fmt.Errorf("search query cannot be empty")
+// median 计算中位数，偶数个时取中间两个的平均值
+func median(values []float64) float64 {
+	sorted := append([]float64(nil), values...)
+	sort.Float64s(sorted)
+	mid := len(sorted) / 2
+	if len(sorted)%2 == 0 {
+		return (sorted[mid-1] + sorted[mid]) / 2
 	}
+	return sorted[mid]
+}
 
-	// 使用CoinGecko搜索API
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/search?query=%s", query)
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// GetCryptoPrice 获取加密货币价格，指定currencies时在 Quotes 中返回以这些货币计价的报价
+func (p *PriceService) GetCryptoPrice(ctx context.Context, symbol string, currencies ...string) (*CryptoPriceInfo, error) {
+	currencies, err := normalizeCurrencies(currencies)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	resp, err := p.httpClient.Do(req)
+	var price *CryptoPriceInfo
+	err = p.cache.Fetch(ctx, CacheCryptoPrice, currencyCacheKey(strings.ToLower(symbol), currencies), &price, func(ctx context.Context) (interface{}, error) {
+		price, err := p.getCryptoPrice(ctx, symbol)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.addQuotes(ctx, []*CryptoPriceInfo{price}, currencies); err != nil {
+			return nil, err
+		}
+		return price, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to search crypto: %w", err)
-	}
-	defer resp.Body.Close()
-
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("search request failed with status: %d", resp.StatusCode)
+		return nil, err
 	}
+	return price, nil
+}
 
-	body, err := io.ReadAll(resp.Body)
+// getCryptoPrice 从数据源查询加密货币价格
+func (p *PriceService) getCryptoPrice(ctx context.Context, symbol string) (*CryptoPriceInfo, error) {
+	prices, err := p.fetchPrices(ctx, []string{symbol})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
 
-	var searchResult struct {
-		Coins []struct {
-			ID     string `json:"id"`
-			Name   string `json:"name"`
-			Symbol string `json:"symbol"`
-		} `json:"coins"`
+	price, ok := prices[symbol]
+	if !ok {
+		return nil, fmt.Errorf("%w for symbol: %s", ErrPriceNotFound, symbol)
 	}
+	return price, nil
+}
 
-	if err := json.Unmarshal(body, &searchResult); err != nil {
-		return nil, fmt.Errorf("failed to parse search response: %w", err)
+// RefreshCryptoPrices 跳过缓存用一次上游查询获取多个币种的最新价格，结果以查询的符号为键，没有价格的符号不在结果中
+// 每个结果同时写入 GetCryptoPrice 的缓存
+func (p *PriceService) RefreshCryptoPrices(ctx context.Context, symbols []string, currencies ...string) (map[string]*CryptoPriceInfo, error) {
+	currencies, err := normalizeCurrencies(currencies)
+	if err != nil {
+		return nil, err
 	}
 
-	if len(searchResult.Coins) == 0 {
-		return []*CryptoPriceInfo{}, nil
+	result, err := p.fetchPrices(ctx, symbols)
+	if err != nil {
+		return nil, err
+	}
+	prices := make([]*CryptoPriceInfo, 0, len(result))
+	for _, price := range result {
+		prices = append(prices, price)
+	}
+	if err := p.addQuotes(ctx, prices, currencies); err != nil {
+		return nil, err
 	}
 
-	// 获取搜索结果的价格信息（最多前5个）
-	var ids []string
-	for i, coin := range searchResult.Coins {
-		if i >= 5 { // 限制结果数量
-			break
+	for symbol, price := range result {
+		var cached *CryptoPriceInfo
+		key := currencyCacheKey(strings.ToLower(symbol), currencies)
+		if err := p.cache.Refresh(ctx, CacheCryptoPrice, key, &cached, func(ctx context.Context) (interface{}, error) {
+			return price, nil
+		}); err != nil {
+			logger.Warnf("Failed to cache price of %s: %v", symbol, err)
 		}
-		ids = append(ids, coin.ID)
 	}
+	return result, nil
+}
 
-	pricesMap, err := p.GetMultipleCryptoPrices(ctx, ids)
+// GetMultipleCryptoPrices 批量获取加密货币价格，结果以币种符号为键
+func (p *PriceService) GetMultipleCryptoPrices(ctx context.Context, symbols []string, currencies ...string) (map[string]*CryptoPriceInfo, error) {
+	if len(symbols) == 0 {
+		return nil, fmt.Errorf("no symbols provided")
+	}
+	currencies, err := normalizeCurrencies(currencies)
 	if err != nil {
 		return nil, err
 	}
 
-	var result []*CryptoPriceInfo
-	for _, price := range pricesMap {
-		result = append(result, price)
+	keys := make([]string, 0, len(symbols))
+	for _, symbol := range symbols {
+		keys = append(keys, strings.ToLower(symbol))
 	}
+	sort.Strings(keys)
 
+	var result map[string]*CryptoPriceInfo
+	err = p.cache.Fetch(ctx, CacheCryptoPrices, currencyCacheKey(strings.Join(keys, ","), currencies), &result, func(ctx context.Context) (interface{}, error) {
+		result, err := p.getMultipleCryptoPrices(ctx, symbols)
+		if err != nil {
+			return nil, err
+		}
+		prices := make([]*CryptoPriceInfo, 0, len(result))
+		for _, price := range result {
+			prices = append(prices, price)
+		}
+		if err := p.addQuotes(ctx, prices, currencies); err != nil {
+			return nil, err
+		}
+		return result, nil
+	})
+	if err != nil {
+		return nil, err
+	}
 	return result, nil
 }
 
-// GetPriceHistory 获取价格历史（简化版本）
-func (p *PriceService) GetPriceHistory(ctx context.Context, symbol string, days int) ([]float64, error) {
-	if days <= 0 || days > 365 {
-		days = 7 // 默认7天
-	}
-
-	url := fmt.Sprintf("https://api.coingecko.com/api/v3/coins/%s/market_chart?vs_currency=usd&days=%d", strings.ToLower(symbol), days)
-	
-	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+// getMultipleCryptoPrices 从数据源批量查询加密货币价格
+func (p *PriceService) getMultipleCryptoPrices(ctx context.Context, symbols []string) (map[string]*CryptoPriceInfo, error) {
+	prices, err := p.fetchPrices(ctx, symbols)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create request: %w", err)
+		return nil, err
 	}
 
-	resp, err := p.httpClient.Do(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch price history: %w", err)
+	result := make(map[string]*CryptoPriceInfo, len(prices))
+	for _, price := range prices {
+		result[price.Symbol] = price
 	}
-	defer resp.Body.Close()
+	return result, nil
+}
 
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("price history request failed with status: %d", resp.StatusCode)
+// GetTopCryptoPrices 获取市值排名前N的加密货币价格
+func (p *PriceService) GetTopCryptoPrices(ctx context.Context, limit int, currencies ...string) ([]*CryptoPriceInfo, error) {
+	if limit <= 0 || limit > 250 {
+		limit = 10 // 默认获取前10名
+	}
+	currencies, err := normalizeCurrencies(currencies)
+	if err != nil {
+		return nil, err
 	}
 
-	body, err := io.ReadAll(resp.Body)
+	var prices []*CryptoPriceInfo
+	err = p.cache.Fetch(ctx, CacheTopPrices, currencyCacheKey(strconv.Itoa(limit), currencies), &prices, func(ctx context.Context) (interface{}, error) {
+		prices, err := p.getTopCryptoPrices(ctx, limit)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.addQuotes(ctx, prices, currencies); err != nil {
+			return nil, err
+		}
+		return prices, nil
+	})
 	if err != nil {
-		return nil, fmt.Errorf("failed to read response body: %w", err)
+		return nil, err
 	}
+	return prices, nil
+}
 
-	var historyData struct {
-		Prices [][]float64 `json:"prices"`
+// getTopCryptoPrices 从第一个支持的数据源查询市值排名
+func (p *PriceService) getTopCryptoPrices(ctx context.Context, limit int) ([]*CryptoPriceInfo, error) {
+	var errs providerErrors
+	for _, state := range p.available() {
+		prices, err := state.provider.GetTopPrices(ctx, limit)
+		if err != nil {
+			p.recordFailure(state, "get top prices", err)
+			errs.add(state.provider.Name(), err)
+			continue
+		}
+		for _, price := range prices {
+			price.Source = state.provider.Name()
+		}
+		return prices, nil
 	}
+	return nil, errs.err("get top prices")
+}
 
-	if err := json.Unmarshal(body, &historyData); err != nil {
-		return nil, fmt.Errorf("failed to parse history response: %w", err)
+// SearchCrypto 搜索加密货币
+func (p *PriceService) SearchCrypto(ctx context.Context, query string, currencies ...string) ([]*CryptoPriceInfo, error) {
+	if query == "" {
+		return nil, fmt.Errorf("search query cannot be empty")
+	}
+	currencies, err := normalizeCurrencies(currencies)
+	if err != nil {
+		return nil, err
 	}
 
-	var prices []float64
-	for _, priceData := range historyData.Prices {
-		if len(priceData) >= 2 {
-			prices = append(prices, priceData[1]) // priceData[0]是时间戳，priceData[1]是价格
+	var results []*CryptoPriceInfo
+	err = p.cache.Fetch(ctx, CacheSearch, currencyCacheKey(strings.ToLower(query), currencies), &results, func(ctx context.Context) (interface{}, error) {
+		results, err := p.searchCrypto(ctx, query)
+		if err != nil {
+			return nil, err
+		}
+		if err := p.addQuotes(ctx, results, currencies); err != nil {
+			return nil, err
 		}
+		return results, nil
+	})
+	if err != nil {
+		return nil, err
 	}
+	return results, nil
+}
 
-	return prices, nil
-}
\ No newline at end of file
+// searchCrypto 从第一个支持的数据源搜索
+func (p *PriceService) searchCrypto(ctx context.Context, query string) ([]*CryptoPriceInfo, error) {
+	var errs providerErrors
+	for _, state := range p.available() {
+		results, err := state.provider.Search(ctx, query)
+		if err != nil {
+			p.recordFailure(state, "search", err)
+			errs.add(state.provider.Name(), err)
+			continue
+		}
+		for _, result := range results {
+			result.Source = state.provider.Name()
+		}
+		return results, nil
+	}
+	return nil, errs.err("search")
+}
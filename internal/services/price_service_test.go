@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chain/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// stubPriceProvider 返回固定价格的数据源，err非nil时所有查询都返回该错误
+type stubPriceProvider struct {
+	name   string
+	prices map[string]float64
+	err    error
+	calls  atomic.Int32
+
+	mu        sync.Mutex
+	histories []HistoryQuery // 收到的价格历史查询
+	requested [][]string     // 每次价格查询的币种ID
+}
+
+func (s *stubPriceProvider) Name() string {
+	return s.name
+}
+
+func (s *stubPriceProvider) GetPrices(ctx context.Context, ids []string) (map[string]*CryptoPriceInfo, error) {
+	s.calls.Add(1)
+	s.mu.Lock()
+	s.requested = append(s.requested, ids)
+	s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	result := make(map[string]*CryptoPriceInfo)
+	for _, id := range ids {
+		if price, ok := s.prices[id]; ok {
+			result[id] = &CryptoPriceInfo{Symbol: id, Name: s.name + " " + id, CurrentPrice: price}
+		}
+	}
+	return result, nil
+}
+
+func (s *stubPriceProvider) GetTopPrices(ctx context.Context, limit int) ([]*CryptoPriceInfo, error) {
+	return nil, ErrPriceNotSupported
+}
+
+func (s *stubPriceProvider) Search(ctx context.Context, query string) ([]*CryptoPriceInfo, error) {
+	return nil, ErrPriceNotSupported
+}
+
+func (s *stubPriceProvider) GetPriceHistory(ctx context.Context, id string, query HistoryQuery) (*HistoryData, error) {
+	s.mu.Lock()
+	s.histories = append(s.histories, query)
+	s.mu.Unlock()
+	if s.err != nil {
+		return nil, s.err
+	}
+	return &HistoryData{Points: []*PricePoint{{Timestamp: query.To, Price: s.prices[id]}}}, nil
+}
+
+func TestPriceServiceFailover(t *testing.T) {
+	primary := &stubPriceProvider{name: "primary", err: errors.New("connection refused")}
+	secondary := &stubPriceProvider{name: "secondary", prices: map[string]float64{"btc": 65000}}
+	service := newPriceServiceWithProviders("", primary, secondary)
+
+	price, err := service.GetCryptoPrice(context.Background(), "btc")
+	require.NoError(t, err)
+	assert.Equal(t, 65000.0, price.CurrentPrice)
+	assert.Equal(t, "secondary", price.Source)
+
+	history, err := service.GetPriceHistory(context.Background(), "btc", HistoryQuery{Days: 7})
+	require.NoError(t, err)
+	assert.Equal(t, "secondary", history.Source)
+
+	// 所有数据源都失败时返回各自的错误
+	secondary.err = errors.New("bad gateway")
+	_, err = service.GetCryptoPrice(context.Background(), "btc")
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "primary: connection refused")
+	assert.Contains(t, err.Error(), "secondary: bad gateway")
+
+	// 没有数据源支持时报告不支持
+	_, err = service.GetTopCryptoPrices(context.Background(), 10)
+	assert.EqualError(t, err, "no available price provider supports get top prices")
+}
+
+func TestPriceServiceFillsMissingFromNextProvider(t *testing.T) {
+	primary := &stubPriceProvider{name: "primary", prices: map[string]float64{"btc": 65000}}
+	secondary := &stubPriceProvider{name: "secondary", prices: map[string]float64{"btc": 64000, "eth": 3200}}
+	service := newPriceServiceWithProviders("", primary, secondary)
+
+	prices, err := service.GetMultipleCryptoPrices(context.Background(), []string{"btc", "eth", "doge"})
+	require.NoError(t, err)
+	require.Len(t, prices, 2)
+	assert.Equal(t, "primary", prices["btc"].Source)
+	assert.Equal(t, 65000.0, prices["btc"].CurrentPrice)
+	assert.Equal(t, "secondary", prices["eth"].Source)
+
+	_, err = service.GetCryptoPrice(context.Background(), "doge")
+	assert.EqualError(t, err, "no price data found for symbol: doge")
+}
+
+func TestPriceServiceRateLimitCooldown(t *testing.T) {
+	primary := &stubPriceProvider{name: "primary", err: &RateLimitError{Provider: "primary", RetryAfter: time.Hour}}
+	secondary := &stubPriceProvider{name: "secondary", prices: map[string]float64{"btc": 65000}}
+	service := newPriceServiceWithProviders("", primary, secondary)
+
+	for i := 0; i < 3; i++ {
+		price, err := service.GetCryptoPrice(context.Background(), "btc")
+		require.NoError(t, err)
+		assert.Equal(t, "secondary", price.Source)
+	}
+	// 限流后的暂停期内不再请求该数据源
+	assert.Equal(t, int32(1), primary.calls.Load())
+
+	service.providers[1].limitedUntil = time.Now().Add(time.Hour)
+	_, err := service.GetCryptoPrice(context.Background(), "btc")
+	assert.EqualError(t, err, "all price providers are rate limited")
+}
+
+func TestPriceServiceMedianAggregation(t *testing.T) {
+	providers := []PriceProvider{
+		&stubPriceProvider{name: "a", prices: map[string]float64{"btc": 100, "eth": 10}},
+		&stubPriceProvider{name: "b", prices: map[string]float64{"btc": 103}},
+		&stubPriceProvider{name: "c", prices: map[string]float64{"btc": 90}},
+		&stubPriceProvider{name: "d", err: errors.New("timeout")},
+	}
+	service := newPriceServiceWithProviders(PriceAggregateMedian, providers...)
+
+	prices, err := service.GetMultipleCryptoPrices(context.Background(), []string{"btc", "eth"})
+	require.NoError(t, err)
+
+	btc := prices["btc"]
+	assert.Equal(t, 100.0, btc.CurrentPrice)
+	assert.Equal(t, PriceAggregateMedian, btc.Source)
+	assert.Equal(t, []string{"a", "b", "c"}, btc.Sources)
+	assert.Equal(t, "a btc", btc.Name)
+
+	// 只有一个数据源有报价时直接使用该数据源
+	assert.Equal(t, 10.0, prices["eth"].CurrentPrice)
+	assert.Equal(t, "a", prices["eth"].Source)
+	assert.Nil(t, prices["eth"].Sources)
+
+	assert.Equal(t, 2.5, median([]float64{4, 1, 3, 2}))
+}
+
+func TestNewPriceServiceProviderPriority(t *testing.T) {
+	service := NewPriceService(&config.Config{Price: config.PriceConfig{
+		Providers: []config.PriceProviderConfig{
+			{Type: "bsc", Priority: 3},
+			{Type: "binance", Priority: 1},
+			{Type: "unknown", Priority: 0},
+			{Type: "coinmarketcap", APIKey: "key", Priority: 2},
+			{Type: "CoinGecko", Priority: 1},
+		},
+	}})
+	assert.Equal(t, []string{"binance", "coingecko", "coinmarketcap", "bsc"}, service.Providers())
+
+	// 未配置数据源时只使用CoinGecko
+	assert.Equal(t, []string{"coingecko"}, NewPriceService(&config.Config{}).Providers())
+}
+
+func TestCoinGeckoProviderRateLimited(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		assert.Equal(t, "secret", r.Header.Get("x-cg-pro-api-key"))
+		w.Header().Set("Retry-After", "30")
+		w.WriteHeader(http.StatusTooManyRequests)
+	}))
+	defer server.Close()
+
+	provider := newCoinGeckoProvider(server.URL, "secret", server.Client())
+	_, err := provider.GetPrices(context.Background(), []string{"bitcoin"})
+
+	var limited *RateLimitError
+	require.ErrorAs(t, err, &limited)
+	assert.Equal(t, PriceProviderCoinGecko, limited.Provider)
+	assert.Equal(t, 30*time.Second, limited.RetryAfter)
+}
@@ -0,0 +1,139 @@
+package services
+
+import (
+	"errors"
+	"strings"
+	"sync"
+	"time"
+
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+// PriceSnapshotStore 代币价格快照存储
+type PriceSnapshotStore interface {
+	// SaveSnapshot 保存一条价格快照
+	SaveSnapshot(snapshot *models.PriceSnapshot) error
+	// GetSnapshotBefore 返回指定时间之前（含）最近的一条快照，不存在时返回nil
+	GetSnapshotBefore(tokenAddress string, before time.Time) (*models.PriceSnapshot, error)
+}
+
+// 内存快照存储的容量限制
+const (
+	memorySnapshotRetention = snapshotMaxAge // 超过该时长的快照不再作为涨跌幅基准，保存时清理
+	memorySnapshotMaxTokens = 10000          // 最多保存快照的代币数量
+)
+
+// memorySnapshotStore 进程内的价格快照存储，服务重启后数据丢失
+// 每个代币只保留memorySnapshotRetention内的快照，代币数量超过上限时淘汰最久未更新的代币
+type memorySnapshotStore struct {
+	mu        sync.RWMutex
+	snapshots map[common.Address][]models.PriceSnapshot // 按时间升序排列
+	maxTokens int
+}
+
+// NewMemorySnapshotStore 创建内存价格快照存储
+func NewMemorySnapshotStore() PriceSnapshotStore {
+	return &memorySnapshotStore{
+		snapshots: make(map[common.Address][]models.PriceSnapshot),
+		maxTokens: memorySnapshotMaxTokens,
+	}
+}
+
+// SaveSnapshot 保存一条价格快照，并清理该代币过期的快照
+func (m *memorySnapshotStore) SaveSnapshot(snapshot *models.PriceSnapshot) error {
+	key := common.HexToAddress(snapshot.TokenAddress)
+
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	snapshots, ok := m.snapshots[key]
+	if !ok && len(m.snapshots) >= m.maxTokens {
+		m.evict(snapshot.Timestamp)
+	}
+
+	i := len(snapshots)
+	for i > 0 && snapshots[i-1].Timestamp.After(snapshot.Timestamp) {
+		i--
+	}
+	snapshots = append(snapshots, models.PriceSnapshot{})
+	copy(snapshots[i+1:], snapshots[i:])
+	snapshots[i] = *snapshot
+
+	// 清理比最新快照早memorySnapshotRetention以上的快照
+	cutoff := snapshots[len(snapshots)-1].Timestamp.Add(-memorySnapshotRetention)
+	expired := 0
+	for snapshots[expired].Timestamp.Before(cutoff) {
+		expired++
+	}
+	m.snapshots[key] = append([]models.PriceSnapshot(nil), snapshots[expired:]...)
+	return nil
+}
+
+// evict 删除最新快照已过期的代币，没有过期代币时删除最新快照最早的代币，调用方需持有锁
+func (m *memorySnapshotStore) evict(now time.Time) {
+	cutoff := now.Add(-memorySnapshotRetention)
+	var oldest common.Address
+	var oldestAt time.Time
+	for token, snapshots := range m.snapshots {
+		latest := snapshots[len(snapshots)-1].Timestamp
+		if latest.Before(cutoff) {
+			delete(m.snapshots, token)
+			continue
+		}
+		if oldestAt.IsZero() || latest.Before(oldestAt) {
+			oldest, oldestAt = token, latest
+		}
+	}
+	if len(m.snapshots) >= m.maxTokens {
+		delete(m.snapshots, oldest)
+	}
+}
+
+// GetSnapshotBefore 返回指定时间之前（含）最近的一条快照
+func (m *memorySnapshotStore) GetSnapshotBefore(tokenAddress string, before time.Time) (*models.PriceSnapshot, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	snapshots := m.snapshots[common.HexToAddress(tokenAddress)]
+	for i := len(snapshots) - 1; i >= 0; i-- {
+		if !snapshots[i].Timestamp.After(before) {
+			snapshot := snapshots[i]
+			return &snapshot, nil
+		}
+	}
+	return nil, nil
+}
+
+// dbSnapshotStore 基于数据库的价格快照存储
+type dbSnapshotStore struct {
+	db *gorm.DB
+}
+
+// NewDBSnapshotStore 创建数据库价格快照存储，需要已迁移 models.PriceSnapshot
+func NewDBSnapshotStore(db *gorm.DB) PriceSnapshotStore {
+	return &dbSnapshotStore{db: db}
+}
+
+// SaveSnapshot 保存一条价格快照
+func (d *dbSnapshotStore) SaveSnapshot(snapshot *models.PriceSnapshot) error {
+	snapshot.TokenAddress = strings.ToLower(snapshot.TokenAddress)
+	return d.db.Create(snapshot).Error
+}
+
+// GetSnapshotBefore 返回指定时间之前（含）最近的一条快照
+func (d *dbSnapshotStore) GetSnapshotBefore(tokenAddress string, before time.Time) (*models.PriceSnapshot, error) {
+	var snapshot models.PriceSnapshot
+	err := d.db.Where("token_address = ? AND timestamp <= ?", strings.ToLower(tokenAddress), before).
+		Order("timestamp DESC").
+		First(&snapshot).Error
+	if errors.Is(err, gorm.ErrRecordNotFound) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	return &snapshot, nil
+}
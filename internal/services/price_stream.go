@@ -0,0 +1,485 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+	"sync"
+	"time"
+
+	"chain/internal/config"
+
+	"github.com/ethereum/go-ethereum/common"
+)
+
+// 价格订阅默认参数
+const (
+	defaultPriceStreamPollInterval = 2 * time.Second
+	defaultPriceStreamMaxAssets    = 50
+	defaultPriceStreamMaxTopics    = 500
+	defaultPriceStreamMaxSubs      = 1000
+	priceUpdateBuffer              = 64 // 每个订阅者缓冲的价格更新数量，缓冲满时丢弃
+)
+
+// ErrInvalidPriceSubscription 订阅的资产或推送条件无效
+var ErrInvalidPriceSubscription = errors.New("invalid price subscription")
+
+// ErrPriceStreamLimit 订阅数或轮询的资产数已达到上限
+var ErrPriceStreamLimit = errors.New("price stream limit reached")
+
+// PriceUpdate 推送给订阅者的价格更新
+type PriceUpdate struct {
+	Asset  string           // 订阅时的资产：代币为校验和地址，行情为请求的符号
+	Token  *PriceInfo       // 代币的DEX价格，行情订阅时为nil
+	Crypto *CryptoPriceInfo // 行情价格，代币订阅时为nil
+	Err    error            // 查询失败的原因，失败时价格为nil
+	At     time.Time        // 查询价格的时间
+
+	usd float64 // 用于判断价格变化的USD价格，无法解析时为0
+}
+
+// PriceSubscribeOptions 订阅的推送条件，零值使用配置的默认值
+type PriceSubscribeOptions struct {
+	Epsilon  float64       // 价格相对上次推送变化达到该百分比时推送
+	Interval time.Duration // 价格未变化时也推送的间隔
+}
+
+// PriceStreamService 价格订阅的分发中心
+// 同一资产的所有订阅者共享一次查询，每个轮询间隔跳过缓存查询上游价格并更新缓存，再按各订阅者的推送条件分发；
+// 代币各自轮询，计价货币相同的行情资产合并为一次上游查询
+type PriceStreamService struct {
+	tokens         tokenPriceSource // 查询BSC代币价格，未设置BSC服务时为nil
+	prices         *PriceService
+	pollInterval   time.Duration
+	epsilon        float64
+	interval       time.Duration
+	maxAssets      int
+	maxTopics      int // 同时轮询的最大资产数
+	maxSubscribers int // 同时存在的最大订阅数
+
+	mu          sync.Mutex
+	topics      map[string]*priceTopic // 资产键 => 正在轮询的资产
+	batches     map[string]*priceBatch // 分组键 => 合并查询的资产组
+	subscribers int                    // 当前订阅数
+}
+
+// priceTopic 被订阅的资产，最后一个订阅者取消时停止轮询
+type priceTopic struct {
+	subscribers map[*priceSubscriber]string // 订阅者 => 订阅者请求的资产名
+	last        *PriceUpdate                // 最近一次查询结果，新订阅者立即收到
+	query       string                      // 合并查询时资产的查询参数
+	cancel      context.CancelFunc
+}
+
+// priceBatch 合并为一次上游查询的资产组，最后一个资产取消时停止轮询
+type priceBatch struct {
+	topics  map[string]*priceTopic // 资产键 => 资产
+	fetch   func(ctx context.Context, queries []string) map[string]*PriceUpdate
+	started bool // 已开始第一次查询，之后加入的资产需要单独查询首次推送的价格
+	cancel  context.CancelFunc
+}
+
+// streamAsset 订阅中的一个资产
+type streamAsset struct {
+	key   string // 资产键，相同资产的订阅共享轮询
+	name  string
+	fetch func(ctx context.Context) *PriceUpdate // 单独查询资产价格；合并查询的资产只用于加入已有分组时的首次推送
+
+	batch      string // 分组键，非空时与同组资产合并查询
+	query      string
+	fetchBatch func(ctx context.Context, queries []string) map[string]*PriceUpdate // 按查询参数返回同组资产的价格
+}
+
+// priceSubscriber 一个订阅，记录每个资产上次推送的更新
+type priceSubscriber struct {
+	epsilon  float64
+	interval time.Duration
+
+	mu     sync.Mutex
+	ch     chan *PriceUpdate
+	sent   map[string]*PriceUpdate // 资产键 => 上次推送的更新
+	closed bool
+}
+
+// NewPriceStreamService 创建价格订阅服务
+func NewPriceStreamService(cfg *config.Config, bscService *BSCService, priceService *PriceService) *PriceStreamService {
+	pollInterval := defaultPriceStreamPollInterval
+	if cfg.PriceStream.PollInterval > 0 {
+		pollInterval = time.Duration(cfg.PriceStream.PollInterval) * time.Second
+	}
+	maxAssets := defaultPriceStreamMaxAssets
+	if cfg.PriceStream.MaxAssets > 0 {
+		maxAssets = cfg.PriceStream.MaxAssets
+	}
+	maxTopics := defaultPriceStreamMaxTopics
+	if cfg.PriceStream.MaxTopics > 0 {
+		maxTopics = cfg.PriceStream.MaxTopics
+	}
+	maxSubscribers := defaultPriceStreamMaxSubs
+	if cfg.PriceStream.MaxSubscribers > 0 {
+		maxSubscribers = cfg.PriceStream.MaxSubscribers
+	}
+
+	s := &PriceStreamService{
+		prices:         priceService,
+		pollInterval:   pollInterval,
+		epsilon:        max(cfg.PriceStream.Epsilon, 0),
+		interval:       max(time.Duration(cfg.PriceStream.Interval)*time.Second, 0),
+		maxAssets:      maxAssets,
+		maxTopics:      maxTopics,
+		maxSubscribers: maxSubscribers,
+		topics:         make(map[string]*priceTopic),
+		batches:        make(map[string]*priceBatch),
+	}
+	if bscService != nil {
+		s.tokens = bscService
+	}
+	return s
+}
+
+// SubscribeTokenPrices 订阅BSC代币的DEX价格，返回更新通道和取消函数
+// 每个代币先推送一次当前价格，之后在价格变化达到epsilon、查询结果在成功和失败之间切换或超过推送间隔时推送
+func (s *PriceStreamService) SubscribeTokenPrices(tokens []string, opts PriceSubscribeOptions) (<-chan *PriceUpdate, func(), error) {
+	if s.tokens == nil {
+		return nil, nil, errors.New("BSC service not configured for token price subscriptions")
+	}
+
+	assets := make([]streamAsset, 0, len(tokens))
+	for _, token := range tokens {
+		token = strings.TrimSpace(token)
+		if !common.IsHexAddress(token) {
+			return nil, nil, fmt.Errorf("%w: invalid token address: %s", ErrInvalidPriceSubscription, token)
+		}
+		address := common.HexToAddress(token).Hex()
+		assets = append(assets, streamAsset{
+			key:  AlertSourceToken + ":" + strings.ToLower(address),
+			name: address,
+			fetch: func(ctx context.Context) *PriceUpdate {
+				price, err := s.tokens.RefreshTokenPrice(address)
+				if err != nil {
+					return &PriceUpdate{Err: err}
+				}
+				update := &PriceUpdate{Token: price}
+				if info := dexPriceInfo(price); info != nil {
+					update.usd = info.CurrentPrice
+				}
+				return update
+			},
+		})
+	}
+	return s.subscribe(assets, opts)
+}
+
+// SubscribeCryptoPrices 订阅加密货币行情价格，指定currencies时在报价中包含这些计价货币
+// 符号、名称或币种ID解析为同一币种的订阅共享轮询，计价货币相同的所有币种每个轮询间隔合并为一次上游查询，
+// 推送条件与 SubscribeTokenPrices 相同
+func (s *PriceStreamService) SubscribeCryptoPrices(symbols []string, currencies []string, opts PriceSubscribeOptions) (<-chan *PriceUpdate, func(), error) {
+	currencies, err := normalizeCurrencies(currencies)
+	if err != nil {
+		return nil, nil, fmt.Errorf("%w: %v", ErrInvalidPriceSubscription, err)
+	}
+
+	fetchBatch := func(ctx context.Context, queries []string) map[string]*PriceUpdate {
+		updates := make(map[string]*PriceUpdate, len(queries))
+		prices, err := s.prices.RefreshCryptoPrices(ctx, queries, currencies...)
+		for _, query := range queries {
+			switch price, ok := prices[query]; {
+			case err != nil:
+				updates[query] = &PriceUpdate{Err: err}
+			case !ok:
+				updates[query] = &PriceUpdate{Err: fmt.Errorf("%w for symbol: %s", ErrPriceNotFound, query)}
+			default:
+				updates[query] = &PriceUpdate{Crypto: price, usd: price.CurrentPrice}
+			}
+		}
+		return updates
+	}
+
+	assets := make([]streamAsset, 0, len(symbols))
+	for _, symbol := range symbols {
+		symbol = strings.TrimSpace(symbol)
+		if symbol == "" {
+			return nil, nil, fmt.Errorf("%w: empty symbol", ErrInvalidPriceSubscription)
+		}
+		assets = append(assets, streamAsset{
+			key:  currencyCacheKey(AlertSourceCrypto+":"+s.prices.ResolveCoin(symbol).CoinID, currencies),
+			name: symbol,
+			fetch: func(ctx context.Context) *PriceUpdate {
+				price, err := s.prices.GetCryptoPrice(ctx, symbol, currencies...)
+				if err != nil {
+					return &PriceUpdate{Err: err}
+				}
+				return &PriceUpdate{Crypto: price, usd: price.CurrentPrice}
+			},
+			batch:      currencyCacheKey(AlertSourceCrypto, currencies),
+			query:      symbol,
+			fetchBatch: fetchBatch,
+		})
+	}
+	return s.subscribe(assets, opts)
+}
+
+// subscribe 校验推送条件并将订阅者加入各资产，资产没有轮询协程时启动
+func (s *PriceStreamService) subscribe(assets []streamAsset, opts PriceSubscribeOptions) (<-chan *PriceUpdate, func(), error) {
+	if math.IsNaN(opts.Epsilon) || opts.Epsilon < 0 {
+		return nil, nil, fmt.Errorf("%w: epsilon must not be negative", ErrInvalidPriceSubscription)
+	}
+	if opts.Interval < 0 {
+		return nil, nil, fmt.Errorf("%w: interval must not be negative", ErrInvalidPriceSubscription)
+	}
+
+	// 同一订阅中的重复资产只保留第一个
+	unique := assets[:0]
+	seen := make(map[string]bool, len(assets))
+	for _, asset := range assets {
+		if !seen[asset.key] {
+			seen[asset.key] = true
+			unique = append(unique, asset)
+		}
+	}
+	if len(unique) == 0 {
+		return nil, nil, fmt.Errorf("%w: at least one asset is required", ErrInvalidPriceSubscription)
+	}
+	if len(unique) > s.maxAssets {
+		return nil, nil, fmt.Errorf("%w: at most %d assets per subscription", ErrInvalidPriceSubscription, s.maxAssets)
+	}
+
+	sub := &priceSubscriber{
+		epsilon:  s.epsilon,
+		interval: s.interval,
+		ch:       make(chan *PriceUpdate, priceUpdateBuffer),
+		sent:     make(map[string]*PriceUpdate, len(unique)),
+	}
+	if opts.Epsilon > 0 {
+		sub.epsilon = opts.Epsilon
+	}
+	if opts.Interval > 0 {
+		sub.interval = opts.Interval
+	}
+
+	s.mu.Lock()
+	if s.subscribers >= s.maxSubscribers {
+		s.mu.Unlock()
+		return nil, nil, fmt.Errorf("%w: at most %d subscriptions", ErrPriceStreamLimit, s.maxSubscribers)
+	}
+	added := 0
+	for _, asset := range unique {
+		if _, ok := s.topics[asset.key]; !ok {
+			added++
+		}
+	}
+	if len(s.topics)+added > s.maxTopics {
+		s.mu.Unlock()
+		return nil, nil, fmt.Errorf("%w: at most %d assets can be streamed at once", ErrPriceStreamLimit, s.maxTopics)
+	}
+	s.subscribers++
+	for _, asset := range unique {
+		topic, ok := s.topics[asset.key]
+		if !ok {
+			topic = s.startTopic(asset)
+		}
+		topic.subscribers[sub] = asset.name
+		if topic.last != nil {
+			sub.offer(asset.key, asset.name, topic.last)
+		}
+	}
+	s.mu.Unlock()
+
+	var once sync.Once
+	return sub.ch, func() {
+		once.Do(func() {
+			s.mu.Lock()
+			s.subscribers--
+			for _, asset := range unique {
+				topic := s.topics[asset.key]
+				delete(topic.subscribers, sub)
+				if len(topic.subscribers) == 0 {
+					s.stopTopic(asset, topic)
+				}
+			}
+			s.mu.Unlock()
+			sub.close()
+		})
+	}, nil
+}
+
+// startTopic 开始轮询资产，调用方持有mu
+// 单独查询的资产启动自己的轮询协程；合并查询的资产加入分组，分组不存在时创建并立即查询，
+// 分组已开始查询时先推送经缓存查询的价格，之后随分组的下一次查询更新
+func (s *PriceStreamService) startTopic(asset streamAsset) *priceTopic {
+	ctx, cancel := context.WithCancel(context.Background())
+	topic := &priceTopic{subscribers: make(map[*priceSubscriber]string), query: asset.query, cancel: cancel}
+	s.topics[asset.key] = topic
+	if asset.batch == "" {
+		go s.poll(ctx, asset.key, topic, asset.fetch)
+		return topic
+	}
+
+	batch, ok := s.batches[asset.batch]
+	if !ok {
+		batchCtx, batchCancel := context.WithCancel(context.Background())
+		batch = &priceBatch{topics: make(map[string]*priceTopic), fetch: asset.fetchBatch, cancel: batchCancel}
+		s.batches[asset.batch] = batch
+		go s.pollBatch(batchCtx, batch)
+	} else if batch.started {
+		go s.fetchInitial(ctx, asset.key, topic, asset.fetch)
+	}
+	batch.topics[asset.key] = topic
+	return topic
+}
+
+// stopTopic 停止轮询资产，分组中没有资产时停止分组的轮询，调用方持有mu
+func (s *PriceStreamService) stopTopic(asset streamAsset, topic *priceTopic) {
+	topic.cancel()
+	delete(s.topics, asset.key)
+	if batch, ok := s.batches[asset.batch]; ok {
+		delete(batch.topics, asset.key)
+		if len(batch.topics) == 0 {
+			batch.cancel()
+			delete(s.batches, asset.batch)
+		}
+	}
+}
+
+// poll 按轮询间隔查询资产价格并分发给订阅者，直到ctx取消
+func (s *PriceStreamService) poll(ctx context.Context, key string, topic *priceTopic, fetch func(ctx context.Context) *PriceUpdate) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		update := fetch(ctx)
+		if ctx.Err() != nil {
+			return
+		}
+		update.At = time.Now()
+
+		s.mu.Lock()
+		s.publish(key, topic, update)
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// pollBatch 按轮询间隔用一次查询获取分组内所有资产的价格并分发给订阅者，直到ctx取消
+func (s *PriceStreamService) pollBatch(ctx context.Context, batch *priceBatch) {
+	ticker := time.NewTicker(s.pollInterval)
+	defer ticker.Stop()
+
+	for {
+		s.mu.Lock()
+		batch.started = true
+		queries := make([]string, 0, len(batch.topics))
+		for _, topic := range batch.topics {
+			queries = append(queries, topic.query)
+		}
+		s.mu.Unlock()
+
+		updates := batch.fetch(ctx, queries)
+		if ctx.Err() != nil {
+			return
+		}
+		now := time.Now()
+
+		s.mu.Lock()
+		for key, topic := range batch.topics {
+			update, ok := updates[topic.query]
+			if !ok {
+				// 本次查询之后加入分组的资产，等待下一次查询
+				continue
+			}
+			update.At = now
+			s.publish(key, topic, update)
+		}
+		s.mu.Unlock()
+
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// fetchInitial 为加入已有分组的资产查询一次价格，分组已查询过该资产时忽略结果
+func (s *PriceStreamService) fetchInitial(ctx context.Context, key string, topic *priceTopic, fetch func(ctx context.Context) *PriceUpdate) {
+	update := fetch(ctx)
+	if ctx.Err() != nil {
+		return
+	}
+	update.At = time.Now()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if topic.last == nil {
+		s.publish(key, topic, update)
+	}
+}
+
+// publish 记录资产的最新查询结果并分发给订阅者，调用方持有mu
+func (s *PriceStreamService) publish(key string, topic *priceTopic, update *PriceUpdate) {
+	topic.last = update
+	for sub, name := range topic.subscribers {
+		sub.offer(key, name, update)
+	}
+}
+
+// offer 满足推送条件时将更新发送给订阅者，缓冲满时丢弃并在下次查询时重新判断
+func (sub *priceSubscriber) offer(key, name string, update *PriceUpdate) {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+
+	if sub.closed {
+		return
+	}
+	if last, ok := sub.sent[key]; ok && !sub.due(last, update) {
+		return
+	}
+
+	copied := *update
+	copied.Asset = name
+	select {
+	case sub.ch <- &copied:
+		sub.sent[key] = update
+	default:
+	}
+}
+
+// due 判断相对上次推送的更新是否需要推送
+func (sub *priceSubscriber) due(last, update *PriceUpdate) bool {
+	if update == last {
+		return false
+	}
+	if sub.interval > 0 && update.At.Sub(last.At) >= sub.interval {
+		return true
+	}
+	if (last.Err == nil) != (update.Err == nil) {
+		return true
+	}
+	if update.Err != nil {
+		return update.Err.Error() != last.Err.Error()
+	}
+	if last.usd == 0 {
+		return update.usd != 0
+	}
+
+	change := math.Abs(update.usd-last.usd) / last.usd * 100
+	if sub.epsilon == 0 {
+		return change > 0
+	}
+	return change >= sub.epsilon
+}
+
+// close 关闭更新通道，之后的更新被忽略
+func (sub *priceSubscriber) close() {
+	sub.mu.Lock()
+	defer sub.mu.Unlock()
+	sub.closed = true
+	close(sub.ch)
+}
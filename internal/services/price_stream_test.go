@@ -0,0 +1,280 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"chain/internal/config"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// countingTokenPrices 可修改价格并统计查询次数的代币价格来源
+type countingTokenPrices struct {
+	calls atomic.Int32
+
+	mu     sync.Mutex
+	prices map[string]string // 小写地址 => USD价格
+}
+
+func (c *countingTokenPrices) GetTokenPrice(tokenAddress, tokenName string) (*PriceInfo, error) {
+	c.calls.Add(1)
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	price, ok := c.prices[strings.ToLower(tokenAddress)]
+	if !ok {
+		return nil, errors.New("no liquidity pool found")
+	}
+	return &PriceInfo{TokenAddress: tokenAddress, TokenSymbol: "Cake", PriceInUSD: price}, nil
+}
+
+func (c *countingTokenPrices) RefreshTokenPrice(tokenAddress string) (*PriceInfo, error) {
+	return c.GetTokenPrice(tokenAddress, "")
+}
+
+// set 修改代币价格，价格为空时代币查询失败
+func (c *countingTokenPrices) set(token, price string) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if price == "" {
+		delete(c.prices, strings.ToLower(token))
+		return
+	}
+	c.prices[strings.ToLower(token)] = price
+}
+
+// newTestPriceStreamService 创建使用模拟价格来源的订阅服务
+func newTestPriceStreamService(tokens tokenPriceSource, provider *stubPriceProvider, pollInterval time.Duration) *PriceStreamService {
+	service := NewPriceStreamService(&config.Config{PriceStream: config.PriceStreamConfig{Epsilon: 1, MaxAssets: 2}}, nil, newPriceServiceWithProviders("", provider))
+	service.tokens = tokens
+	service.pollInterval = pollInterval
+	return service
+}
+
+// receive 等待下一个更新
+func receive(t *testing.T, updates <-chan *PriceUpdate) *PriceUpdate {
+	t.Helper()
+	select {
+	case update := <-updates:
+		require.NotNil(t, update)
+		return update
+	case <-time.After(2 * time.Second):
+		t.Fatal("no price update received")
+		return nil
+	}
+}
+
+func TestSubscribeTokenPricesSharesUpstreamFetch(t *testing.T) {
+	tokens := &countingTokenPrices{prices: map[string]string{strings.ToLower(CAKEAddress): "2"}}
+	service := newTestPriceStreamService(tokens, &stubPriceProvider{name: "primary"}, time.Hour)
+
+	// 同一代币的多个订阅只查询一次，后加入的订阅立即收到最近的价格
+	var cancels []func()
+	for _, token := range []string{CAKEAddress, strings.ToLower(CAKEAddress), CAKEAddress} {
+		updates, cancel, err := service.SubscribeTokenPrices([]string{token}, PriceSubscribeOptions{})
+		require.NoError(t, err)
+		cancels = append(cancels, cancel)
+
+		update := receive(t, updates)
+		assert.Equal(t, CAKEAddress, update.Asset)
+		assert.Equal(t, "2", update.Token.PriceInUSD)
+		assert.NoError(t, update.Err)
+		assert.False(t, update.At.IsZero())
+	}
+	assert.Equal(t, int32(1), tokens.calls.Load())
+
+	// 最后一个订阅者取消后停止轮询
+	for _, cancel := range cancels {
+		cancel()
+	}
+	service.mu.Lock()
+	assert.Empty(t, service.topics)
+	service.mu.Unlock()
+}
+
+func TestSubscribeTokenPricesEpsilon(t *testing.T) {
+	tokens := &countingTokenPrices{prices: map[string]string{strings.ToLower(CAKEAddress): "2"}}
+	service := newTestPriceStreamService(tokens, &stubPriceProvider{name: "primary"}, 5*time.Millisecond)
+
+	updates, cancel, err := service.SubscribeTokenPrices([]string{CAKEAddress}, PriceSubscribeOptions{})
+	require.NoError(t, err)
+	defer cancel()
+	assert.Equal(t, "2", receive(t, updates).Token.PriceInUSD)
+
+	// 变化0.5%低于1%的epsilon，不推送
+	tokens.set(CAKEAddress, "2.01")
+	calls := tokens.calls.Load()
+	require.Eventually(t, func() bool { return tokens.calls.Load() >= calls+3 }, 2*time.Second, time.Millisecond)
+	assert.Empty(t, updates)
+
+	// 相对上次推送的价格累计变化达到1%时推送
+	tokens.set(CAKEAddress, "2.02")
+	assert.Equal(t, "2.02", receive(t, updates).Token.PriceInUSD)
+
+	// 查询失败时推送错误，恢复后推送价格
+	tokens.set(CAKEAddress, "")
+	assert.Error(t, receive(t, updates).Err)
+	tokens.set(CAKEAddress, "2.02")
+	assert.Equal(t, "2.02", receive(t, updates).Token.PriceInUSD)
+}
+
+func TestPriceSubscriberDue(t *testing.T) {
+	now := time.Now()
+	last := &PriceUpdate{usd: 100, At: now}
+	failed := errors.New("rate limited")
+
+	tests := []struct {
+		name     string
+		epsilon  float64
+		interval time.Duration
+		update   *PriceUpdate
+		due      bool
+	}{
+		{"below epsilon", 1, 0, &PriceUpdate{usd: 100.5, At: now.Add(time.Minute)}, false},
+		{"reaches epsilon", 1, 0, &PriceUpdate{usd: 99, At: now}, true},
+		{"any change without epsilon", 0, 0, &PriceUpdate{usd: 100.001, At: now}, true},
+		{"unchanged without epsilon", 0, 0, &PriceUpdate{usd: 100, At: now}, false},
+		{"interval elapsed", 1, 30 * time.Second, &PriceUpdate{usd: 100, At: now.Add(30 * time.Second)}, true},
+		{"interval not elapsed", 1, 30 * time.Second, &PriceUpdate{usd: 100, At: now.Add(29 * time.Second)}, false},
+		{"error", 1, 0, &PriceUpdate{Err: failed, At: now}, true},
+	}
+	for _, tt := range tests {
+		sub := &priceSubscriber{epsilon: tt.epsilon, interval: tt.interval}
+		assert.Equal(t, tt.due, sub.due(last, tt.update), tt.name)
+	}
+
+	// 相同的错误不重复推送
+	sub := &priceSubscriber{epsilon: 1}
+	assert.False(t, sub.due(&PriceUpdate{Err: failed}, &PriceUpdate{Err: errors.New("rate limited")}))
+	assert.True(t, sub.due(&PriceUpdate{Err: failed}, &PriceUpdate{Err: errors.New("timeout")}))
+}
+
+func TestSubscribeCryptoPrices(t *testing.T) {
+	provider := &stubPriceProvider{name: "primary", prices: map[string]float64{"bitcoin": 65000}}
+	service := newTestPriceStreamService(nil, provider, time.Hour)
+	service.prices.resolver = newCoinResolver(nil, config.PriceConfig{SymbolOverrides: map[string]string{"btc": "bitcoin"}})
+
+	// 符号和币种ID解析为同一币种，共享一次查询
+	btc, cancelBTC, err := service.SubscribeCryptoPrices([]string{"BTC"}, nil, PriceSubscribeOptions{})
+	require.NoError(t, err)
+	defer cancelBTC()
+	update := receive(t, btc)
+	assert.Equal(t, "BTC", update.Asset)
+	assert.Equal(t, 65000.0, update.Crypto.CurrentPrice)
+
+	bitcoin, cancelBitcoin, err := service.SubscribeCryptoPrices([]string{"bitcoin"}, nil, PriceSubscribeOptions{})
+	require.NoError(t, err)
+	defer cancelBitcoin()
+	update = receive(t, bitcoin)
+	assert.Equal(t, "bitcoin", update.Asset)
+	assert.Equal(t, int32(1), provider.calls.Load())
+
+	// 未配置BSC服务时不能订阅代币
+	_, _, err = service.SubscribeTokenPrices([]string{CAKEAddress}, PriceSubscribeOptions{})
+	assert.EqualError(t, err, "BSC service not configured for token price subscriptions")
+}
+
+func TestSubscribeCryptoPricesBatchesUpstreamFetch(t *testing.T) {
+	provider := &stubPriceProvider{name: "primary", prices: map[string]float64{"bitcoin": 65000, "ethereum": 3000, "binancecoin": 600}}
+	service := newTestPriceStreamService(nil, provider, 20*time.Millisecond)
+	service.maxAssets = 5
+
+	// 计价货币相同的币种每个轮询间隔只向上游查询一次
+	updates, cancel, err := service.SubscribeCryptoPrices([]string{"bitcoin", "ethereum", "binancecoin"}, nil, PriceSubscribeOptions{Interval: time.Millisecond})
+	require.NoError(t, err)
+	received := make(map[string]bool)
+	for len(received) < 3 {
+		received[receive(t, updates).Asset] = true
+	}
+	require.Eventually(t, func() bool { return provider.calls.Load() >= 5 }, 2*time.Second, time.Millisecond)
+	cancel()
+
+	provider.mu.Lock()
+	defer provider.mu.Unlock()
+	require.NotEmpty(t, provider.requested)
+	for _, ids := range provider.requested {
+		assert.ElementsMatch(t, []string{"bitcoin", "ethereum", "binancecoin"}, ids)
+	}
+	assert.Empty(t, service.batches)
+}
+
+func TestSubscribeInvalid(t *testing.T) {
+	tokens := &countingTokenPrices{prices: map[string]string{}}
+	service := newTestPriceStreamService(tokens, &stubPriceProvider{name: "primary"}, time.Hour)
+
+	_, _, err := service.SubscribeTokenPrices([]string{"cake"}, PriceSubscribeOptions{})
+	assert.ErrorIs(t, err, ErrInvalidPriceSubscription)
+	assert.ErrorContains(t, err, "invalid token address: cake")
+
+	_, _, err = service.SubscribeTokenPrices(nil, PriceSubscribeOptions{})
+	assert.ErrorContains(t, err, "at least one asset is required")
+
+	_, _, err = service.SubscribeTokenPrices([]string{WBNBAddress, USDTAddress, CAKEAddress}, PriceSubscribeOptions{})
+	assert.ErrorContains(t, err, "at most 2 assets per subscription")
+
+	_, _, err = service.SubscribeTokenPrices([]string{CAKEAddress}, PriceSubscribeOptions{Epsilon: -1})
+	assert.ErrorContains(t, err, "epsilon must not be negative")
+
+	_, _, err = service.SubscribeCryptoPrices([]string{"btc"}, []string{"xyz"}, PriceSubscribeOptions{})
+	assert.ErrorIs(t, err, ErrInvalidPriceSubscription)
+	assert.ErrorContains(t, err, "unsupported quote currency: xyz")
+
+	_, _, err = service.SubscribeCryptoPrices([]string{" "}, nil, PriceSubscribeOptions{})
+	assert.ErrorContains(t, err, "empty symbol")
+
+	// 校验失败时不启动轮询
+	assert.Zero(t, tokens.calls.Load())
+	assert.Empty(t, service.topics)
+}
+
+func TestSubscribeCryptoPricesBypassesCache(t *testing.T) {
+	provider := &stubPriceProvider{name: "primary", prices: map[string]float64{"bitcoin": 65000}}
+	service := newTestPriceStreamService(nil, provider, time.Hour)
+	service.prices.cache = newCache(NewMemoryCacheBackend(), config.CacheConfig{})
+
+	_, err := service.prices.GetCryptoPrice(context.Background(), "bitcoin")
+	require.NoError(t, err)
+	provider.prices["bitcoin"] = 66000
+
+	// 订阅跳过缓存查询最新价格，并用结果更新缓存
+	updates, cancel, err := service.SubscribeCryptoPrices([]string{"bitcoin"}, nil, PriceSubscribeOptions{})
+	require.NoError(t, err)
+	defer cancel()
+	assert.Equal(t, 66000.0, receive(t, updates).Crypto.CurrentPrice)
+
+	price, err := service.prices.GetCryptoPrice(context.Background(), "bitcoin")
+	require.NoError(t, err)
+	assert.Equal(t, 66000.0, price.CurrentPrice)
+}
+
+func TestPriceStreamLimits(t *testing.T) {
+	tokens := &countingTokenPrices{prices: map[string]string{}}
+	service := newTestPriceStreamService(tokens, &stubPriceProvider{name: "primary"}, time.Hour)
+	service.maxTopics, service.maxSubscribers = 2, 2
+
+	_, cancelFirst, err := service.SubscribeTokenPrices([]string{WBNBAddress, USDTAddress}, PriceSubscribeOptions{})
+	require.NoError(t, err)
+
+	// 新资产超过轮询上限，已轮询的资产不受限制
+	_, _, err = service.SubscribeTokenPrices([]string{CAKEAddress}, PriceSubscribeOptions{})
+	assert.ErrorIs(t, err, ErrPriceStreamLimit)
+	_, cancelSecond, err := service.SubscribeTokenPrices([]string{WBNBAddress}, PriceSubscribeOptions{})
+	require.NoError(t, err)
+	defer cancelSecond()
+
+	_, _, err = service.SubscribeTokenPrices([]string{USDTAddress}, PriceSubscribeOptions{})
+	assert.ErrorIs(t, err, ErrPriceStreamLimit)
+	assert.ErrorContains(t, err, "at most 2 subscriptions")
+
+	// 取消订阅后释放名额
+	cancelFirst()
+	_, cancelThird, err := service.SubscribeTokenPrices([]string{CAKEAddress}, PriceSubscribeOptions{})
+	require.NoError(t, err)
+	cancelThird()
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"net/netip"
+	"syscall"
+	"time"
+)
+
+// 访问用户提供地址的HTTP客户端参数
+const (
+	publicDialTimeout  = 10 * time.Second
+	publicMaxRedirects = 5
+)
+
+// 访问用户提供地址的错误
+var (
+	ErrNonPublicAddress   = errors.New("address is not publicly routable") // 地址解析到内网、回环、链路本地等非公网地址
+	ErrRedirectNotAllowed = errors.New("redirect not allowed")             // 重定向到允许域名以外的地址
+)
+
+// reservedPrefixes netip不视为私有或本地的保留地址段：本网络（RFC 791）和运营商级NAT（RFC 6598）
+var reservedPrefixes = []netip.Prefix{
+	netip.MustParsePrefix("0.0.0.0/8"),
+	netip.MustParsePrefix("100.64.0.0/10"),
+}
+
+// newPublicHTTPClient 创建只连接公网地址的HTTP客户端，用于访问用户提供的URL
+// 在建立连接时检查实际连接的IP，重定向和DNS重绑定都无法访问内网服务；不使用代理，避免检查的是代理地址
+// allowedHosts不为空时只跟随重定向到这些域名（含子域名）的请求
+func newPublicHTTPClient(timeout time.Duration, allowedHosts []string) *http.Client {
+	dialer := &net.Dialer{
+		Timeout: publicDialTimeout,
+		Control: func(network, address string, _ syscall.RawConn) error {
+			host, _, err := net.SplitHostPort(address)
+			if err != nil {
+				return err
+			}
+			ip, err := netip.ParseAddr(host)
+			if err != nil {
+				return err
+			}
+			if !isPublicIP(ip) {
+				return fmt.Errorf("%w: %s", ErrNonPublicAddress, ip)
+			}
+			return nil
+		},
+	}
+
+	transport := http.DefaultTransport.(*http.Transport).Clone()
+	transport.Proxy = nil
+	transport.DialContext = dialer.DialContext
+	return &http.Client{
+		Transport:     transport,
+		Timeout:       timeout,
+		CheckRedirect: redirectPolicy(allowedHosts),
+	}
+}
+
+// redirectPolicy 限制重定向次数，allowedHosts不为空时拒绝重定向到其他域名
+func redirectPolicy(allowedHosts []string) func(req *http.Request, via []*http.Request) error {
+	return func(req *http.Request, via []*http.Request) error {
+		if len(via) >= publicMaxRedirects {
+			return fmt.Errorf("stopped after %d redirects", publicMaxRedirects)
+		}
+		if len(allowedHosts) > 0 && !hostAllowed(req.URL.Hostname(), allowedHosts) {
+			return fmt.Errorf("%w: host %s", ErrRedirectNotAllowed, req.URL.Hostname())
+		}
+		return nil
+	}
+}
+
+// isPublicIP 判断IP是否为可公网路由的单播地址
+func isPublicIP(ip netip.Addr) bool {
+	ip = ip.Unmap()
+	if !ip.IsGlobalUnicast() || ip.IsPrivate() {
+		return false
+	}
+	for _, prefix := range reservedPrefixes {
+		if prefix.Contains(ip) {
+			return false
+		}
+	}
+	return true
+}
@@ -0,0 +1,73 @@
+package services
+
+import (
+	"strings"
+
+	"chain/internal/models"
+
+	"github.com/ethereum/go-ethereum/common"
+	"gorm.io/gorm"
+)
+
+// TokenBalanceStore 已保存的账户代币余额
+type TokenBalanceStore interface {
+	// GetTokenBalances 返回账户在指定链上保存的代币余额，包含账户和代币信息，账户地址不区分大小写
+	GetTokenBalances(chainID uint64, accounts []common.Address) ([]*models.TokenBalance, error)
+}
+
+// memoryTokenBalanceStore 固定的代币余额，用于未连接数据库时提供已知余额
+type memoryTokenBalanceStore struct {
+	balances []*models.TokenBalance
+}
+
+// NewMemoryTokenBalanceStore 创建包含指定余额的内存代币余额存储，余额需包含账户和代币信息
+func NewMemoryTokenBalanceStore(balances []*models.TokenBalance) TokenBalanceStore {
+	return &memoryTokenBalanceStore{balances: balances}
+}
+
+// GetTokenBalances 返回账户的代币余额
+func (m *memoryTokenBalanceStore) GetTokenBalances(chainID uint64, accounts []common.Address) ([]*models.TokenBalance, error) {
+	wanted := make(map[common.Address]bool, len(accounts))
+	for _, account := range accounts {
+		wanted[account] = true
+	}
+
+	var balances []*models.TokenBalance
+	for _, balance := range m.balances {
+		if balance.ChainID == chainID && common.IsHexAddress(balance.Account.Address) &&
+			wanted[common.HexToAddress(balance.Account.Address)] {
+			copied := *balance
+			balances = append(balances, &copied)
+		}
+	}
+	return balances, nil
+}
+
+// dbTokenBalanceStore 读取数据库 token_balances 表的代币余额
+type dbTokenBalanceStore struct {
+	db *gorm.DB
+}
+
+// NewDBTokenBalanceStore 创建数据库代币余额存储，需要已迁移 models.TokenBalance、models.Account 和 models.Token
+func NewDBTokenBalanceStore(db *gorm.DB) TokenBalanceStore {
+	return &dbTokenBalanceStore{db: db}
+}
+
+// GetTokenBalances 返回账户的代币余额，账户地址按校验和与小写两种格式匹配
+func (d *dbTokenBalanceStore) GetTokenBalances(chainID uint64, accounts []common.Address) ([]*models.TokenBalance, error) {
+	if len(accounts) == 0 {
+		return nil, nil
+	}
+	addresses := make([]string, 0, len(accounts)*2)
+	for _, account := range accounts {
+		addresses = append(addresses, account.Hex(), strings.ToLower(account.Hex()))
+	}
+
+	var balances []*models.TokenBalance
+	err := d.db.Joins("JOIN accounts ON accounts.id = token_balances.account_id").
+		Where("accounts.address IN ? AND token_balances.chain_id = ?", addresses, chainID).
+		Preload("Account").
+		Preload("Token").
+		Find(&balances).Error
+	return balances, err
+}
@@ -0,0 +1,409 @@
+package services
+
+import (
+	"sort"
+	"strings"
+	"sync"
+	"unicode/utf8"
+
+	"chain/internal/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// 代币搜索返回数量
+const (
+	defaultTokenSearchLimit = 20
+	maxTokenSearchLimit     = 100
+)
+
+// TokenStore 代币信息存储，地址统一保存为校验和格式
+type TokenStore interface {
+	// UpsertTokens 保存代币，已存在的代币更新名称、符号、精度、图标、验证状态和来源
+	UpsertTokens(tokens []*models.Token) error
+	// AddTokens 保存代币，已存在的代币被忽略
+	AddTokens(tokens []*models.Token) error
+	// SaveTokenMetadata 保存从链上读取的元数据，已存在的代币保留图标、验证状态和来源
+	SaveTokenMetadata(token *models.Token) error
+	// GetTokens 返回指定链上已保存的代币，不存在的地址被忽略
+	GetTokens(chainID uint64, addresses []string) ([]*models.Token, error)
+	// SearchTokens 按名称或符号搜索代币，按匹配程度排序，同等匹配时已验证的代币在前
+	SearchTokens(query TokenQuery) ([]*models.Token, error)
+}
+
+// TokenQuery 代币搜索条件
+type TokenQuery struct {
+	ChainID      uint64
+	Text         string // 名称或符号，不区分大小写
+	Prefix       bool   // 只匹配名称或符号的前缀，否则匹配任意位置并模糊匹配拼写相近的名称或符号
+	VerifiedOnly bool   // 只返回已验证的代币
+	Limit        int    // 返回数量，默认20，最大100
+}
+
+// limit 返回有效的搜索数量
+func (q TokenQuery) limit() int {
+	if q.Limit <= 0 {
+		return defaultTokenSearchLimit
+	}
+	if q.Limit > maxTokenSearchLimit {
+		return maxTokenSearchLimit
+	}
+	return q.Limit
+}
+
+// 搜索匹配程度，数值越小匹配越好
+const (
+	matchSymbolExact = iota
+	matchNameExact
+	matchSymbolPrefix
+	matchNamePrefix
+	matchSymbolContains
+	matchNameContains
+	matchSymbolFuzzy
+	matchNameFuzzy
+	matchNone
+)
+
+// 模糊匹配参数
+const (
+	fuzzyMinQueryLength = 3    // 搜索文本少于该长度时不做模糊匹配
+	fuzzyCandidateLimit = 1000 // 数据库存储每次模糊匹配读取的候选代币数量上限
+)
+
+// fuzzyMaxDistance 返回搜索文本允许的最大编辑距离，0表示不做模糊匹配
+func fuzzyMaxDistance(text string) int {
+	switch n := utf8.RuneCountInString(text); {
+	case n < fuzzyMinQueryLength:
+		return 0
+	case n < 6:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// tokenMatchRank 计算代币与搜索文本（小写）的匹配程度
+func tokenMatchRank(token *models.Token, text string, prefixOnly bool) int {
+	symbol := strings.ToLower(token.Symbol)
+	name := strings.ToLower(token.Name)
+	switch {
+	case symbol == text:
+		return matchSymbolExact
+	case name == text:
+		return matchNameExact
+	case strings.HasPrefix(symbol, text):
+		return matchSymbolPrefix
+	case strings.HasPrefix(name, text):
+		return matchNamePrefix
+	case prefixOnly:
+		return matchNone
+	case strings.Contains(symbol, text):
+		return matchSymbolContains
+	case strings.Contains(name, text):
+		return matchNameContains
+	}
+
+	// 符号或名称（整体或其中一个单词）与搜索文本的编辑距离在允许范围内
+	if maxDistance := fuzzyMaxDistance(text); maxDistance > 0 {
+		if editDistance(symbol, text, maxDistance) <= maxDistance {
+			return matchSymbolFuzzy
+		}
+		if editDistance(name, text, maxDistance) <= maxDistance {
+			return matchNameFuzzy
+		}
+		for _, word := range strings.Fields(name) {
+			if editDistance(word, text, maxDistance) <= maxDistance {
+				return matchNameFuzzy
+			}
+		}
+	}
+	return matchNone
+}
+
+// editDistance 计算两个字符串的编辑距离，相邻字符交换计为一次编辑
+// 长度差超过maxDistance时直接返回maxDistance+1
+func editDistance(a, b string, maxDistance int) int {
+	ra, rb := []rune(a), []rune(b)
+	if diff := len(ra) - len(rb); diff > maxDistance || -diff > maxDistance {
+		return maxDistance + 1
+	}
+
+	// 只保留最近三行
+	prev2 := make([]int, len(rb)+1)
+	prev := make([]int, len(rb)+1)
+	curr := make([]int, len(rb)+1)
+	for j := range prev {
+		prev[j] = j
+	}
+	for i := 1; i <= len(ra); i++ {
+		curr[0] = i
+		for j := 1; j <= len(rb); j++ {
+			cost := 1
+			if ra[i-1] == rb[j-1] {
+				cost = 0
+			}
+			curr[j] = min(prev[j]+1, curr[j-1]+1, prev[j-1]+cost)
+			if i > 1 && j > 1 && ra[i-1] == rb[j-2] && ra[i-2] == rb[j-1] {
+				curr[j] = min(curr[j], prev2[j-2]+1)
+			}
+		}
+		prev2, prev, curr = prev, curr, prev2
+	}
+	return prev[len(rb)]
+}
+
+// tokenMatch 搜索命中的代币及其匹配程度
+type tokenMatch struct {
+	token *models.Token
+	rank  int
+}
+
+// sortTokenMatches 按匹配程度排序，同等匹配时已验证的代币在前，再按符号和地址排序
+func sortTokenMatches(matches []tokenMatch) {
+	sort.Slice(matches, func(i, j int) bool {
+		a, b := matches[i], matches[j]
+		if a.rank != b.rank {
+			return a.rank < b.rank
+		}
+		if a.token.Verified != b.token.Verified {
+			return a.token.Verified
+		}
+		if a.token.Symbol != b.token.Symbol {
+			return a.token.Symbol < b.token.Symbol
+		}
+		return a.token.Address < b.token.Address
+	})
+}
+
+// memoryTokenStore 进程内的代币存储
+type memoryTokenStore struct {
+	mu     sync.RWMutex
+	tokens map[string]*models.Token // 地址 => 代币
+}
+
+// NewMemoryTokenStore 创建内存代币存储
+func NewMemoryTokenStore() TokenStore {
+	return &memoryTokenStore{tokens: make(map[string]*models.Token)}
+}
+
+// UpsertTokens 保存代币，已存在的代币被更新
+func (m *memoryTokenStore) UpsertTokens(tokens []*models.Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, token := range tokens {
+		stored := *token
+		stored.Address = normalizeAddress(token.Address)
+		m.tokens[stored.Address] = &stored
+	}
+	return nil
+}
+
+// AddTokens 保存代币，已存在的代币被忽略
+func (m *memoryTokenStore) AddTokens(tokens []*models.Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	for _, token := range tokens {
+		address := normalizeAddress(token.Address)
+		if _, ok := m.tokens[address]; ok {
+			continue
+		}
+		stored := *token
+		stored.Address = address
+		m.tokens[address] = &stored
+	}
+	return nil
+}
+
+// SaveTokenMetadata 保存从链上读取的元数据
+func (m *memoryTokenStore) SaveTokenMetadata(token *models.Token) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	address := normalizeAddress(token.Address)
+	stored := *token
+	stored.Address = address
+	if existing, ok := m.tokens[address]; ok {
+		stored.LogoURI = existing.LogoURI
+		stored.Verified = existing.Verified
+		stored.Source = existing.Source
+	}
+	m.tokens[address] = &stored
+	return nil
+}
+
+// GetTokens 返回已保存的代币
+func (m *memoryTokenStore) GetTokens(chainID uint64, addresses []string) ([]*models.Token, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	var tokens []*models.Token
+	for _, address := range addresses {
+		if token, ok := m.tokens[normalizeAddress(address)]; ok && token.ChainID == chainID {
+			copied := *token
+			tokens = append(tokens, &copied)
+		}
+	}
+	return tokens, nil
+}
+
+// SearchTokens 按名称或符号搜索代币
+func (m *memoryTokenStore) SearchTokens(query TokenQuery) ([]*models.Token, error) {
+	m.mu.RLock()
+	defer m.mu.RUnlock()
+
+	text := strings.ToLower(strings.TrimSpace(query.Text))
+	var matches []tokenMatch
+	for _, token := range m.tokens {
+		if token.ChainID != query.ChainID || (query.VerifiedOnly && !token.Verified) {
+			continue
+		}
+		if rank := tokenMatchRank(token, text, query.Prefix); rank != matchNone {
+			copied := *token
+			matches = append(matches, tokenMatch{token: &copied, rank: rank})
+		}
+	}
+	sortTokenMatches(matches)
+
+	tokens := make([]*models.Token, 0, query.limit())
+	for _, m := range matches {
+		if len(tokens) == query.limit() {
+			break
+		}
+		tokens = append(tokens, m.token)
+	}
+	return tokens, nil
+}
+
+// dbTokenStore 基于数据库tokens表的代币存储
+type dbTokenStore struct {
+	db *gorm.DB
+}
+
+// NewDBTokenStore 创建数据库代币存储，需要已迁移 models.Token
+func NewDBTokenStore(db *gorm.DB) TokenStore {
+	return &dbTokenStore{db: db}
+}
+
+// tokenBatchSize 批量写入代币的每批数量
+const tokenBatchSize = 500
+
+// UpsertTokens 保存代币，已存在的代币被更新
+func (d *dbTokenStore) UpsertTokens(tokens []*models.Token) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	for _, token := range tokens {
+		token.Address = normalizeAddress(token.Address)
+	}
+	return d.db.Clauses(clause.OnConflict{
+		Columns:   []clause.Column{{Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{"name", "symbol", "decimals", "logo_uri", "verified", "source", "updated_at"}),
+	}).CreateInBatches(&tokens, tokenBatchSize).Error
+}
+
+// AddTokens 保存代币，已存在的代币被忽略
+func (d *dbTokenStore) AddTokens(tokens []*models.Token) error {
+	if len(tokens) == 0 {
+		return nil
+	}
+	for _, token := range tokens {
+		token.Address = normalizeAddress(token.Address)
+	}
+	return d.db.Clauses(clause.OnConflict{DoNothing: true}).CreateInBatches(&tokens, tokenBatchSize).Error
+}
+
+// SaveTokenMetadata 保存从链上读取的元数据
+func (d *dbTokenStore) SaveTokenMetadata(token *models.Token) error {
+	token.Address = normalizeAddress(token.Address)
+	return d.db.Clauses(clause.OnConflict{
+		Columns: []clause.Column{{Name: "address"}},
+		DoUpdates: clause.AssignmentColumns([]string{
+			"name", "symbol", "decimals", "total_supply", "is_proxy", "implementation", "metadata_updated_at", "updated_at",
+		}),
+	}).Create(token).Error
+}
+
+// GetTokens 返回已保存的代币
+func (d *dbTokenStore) GetTokens(chainID uint64, addresses []string) ([]*models.Token, error) {
+	if len(addresses) == 0 {
+		return nil, nil
+	}
+	normalized := make([]string, 0, len(addresses))
+	for _, address := range addresses {
+		normalized = append(normalized, normalizeAddress(address))
+	}
+
+	var tokens []*models.Token
+	err := d.db.Where("chain_id = ? AND address IN ?", chainID, normalized).Find(&tokens).Error
+	return tokens, err
+}
+
+// SearchTokens 按名称或符号搜索代币，匹配程度的排序与内存存储一致
+// 包含匹配不足limit个时，从符号或名称中某个单词首字母与搜索文本相同的代币中模糊匹配补足
+func (d *dbTokenStore) SearchTokens(query TokenQuery) ([]*models.Token, error) {
+	text := strings.ToLower(strings.TrimSpace(query.Text))
+	prefix := escapeLike(text) + "%"
+	pattern := prefix
+	if !query.Prefix {
+		pattern = "%" + prefix
+	}
+
+	var tokens []*models.Token
+	err := d.scope(query).
+		Where("(LOWER(symbol) LIKE ? OR LOWER(name) LIKE ?)", pattern, pattern).
+		Clauses(clause.OrderBy{
+			Expression: clause.Expr{
+				SQL: "CASE WHEN LOWER(symbol) = ? THEN 0 WHEN LOWER(name) = ? THEN 1 " +
+					"WHEN LOWER(symbol) LIKE ? THEN 2 WHEN LOWER(name) LIKE ? THEN 3 " +
+					"WHEN LOWER(symbol) LIKE ? THEN 4 ELSE 5 END, verified DESC, symbol ASC, address ASC",
+				Vars: []interface{}{text, text, prefix, prefix, "%" + prefix},
+			},
+		}).Limit(query.limit()).Find(&tokens).Error
+	if err != nil || query.Prefix || len(tokens) == query.limit() || fuzzyMaxDistance(text) == 0 {
+		return tokens, err
+	}
+
+	// 候选代币排除已包含匹配的代币，按首字母粗筛后在内存中计算编辑距离
+	initial := escapeLike(string([]rune(text)[:1]))
+	var candidates []*models.Token
+	err = d.scope(query).
+		Where("LOWER(symbol) NOT LIKE ? AND LOWER(name) NOT LIKE ?", pattern, pattern).
+		Where("(LOWER(symbol) LIKE ? OR LOWER(name) LIKE ? OR LOWER(name) LIKE ?)", initial+"%", initial+"%", "% "+initial+"%").
+		Order("verified DESC, symbol ASC, address ASC").
+		Limit(fuzzyCandidateLimit).Find(&candidates).Error
+	if err != nil {
+		return nil, err
+	}
+
+	var matches []tokenMatch
+	for _, token := range candidates {
+		if rank := tokenMatchRank(token, text, false); rank != matchNone {
+			matches = append(matches, tokenMatch{token: token, rank: rank})
+		}
+	}
+	sortTokenMatches(matches)
+	for _, m := range matches {
+		if len(tokens) == query.limit() {
+			break
+		}
+		tokens = append(tokens, m.token)
+	}
+	return tokens, nil
+}
+
+// scope 返回按链和验证状态过滤的查询
+func (d *dbTokenStore) scope(query TokenQuery) *gorm.DB {
+	db := d.db.Where("chain_id = ?", query.ChainID)
+	if query.VerifiedOnly {
+		db = db.Where("verified = ?", true)
+	}
+	return db
+}
+
+// escapeLike 转义LIKE模式中的通配符
+func escapeLike(text string) string {
+	return strings.NewReplacer(`\`, `\\`, `%`, `\%`, `_`, `\_`).Replace(text)
+}
@@ -0,0 +1,76 @@
+package services
+
+import (
+	"fmt"
+	"math/big"
+	"strings"
+)
+
+// pow10 返回10的n次方
+func pow10(n uint8) *big.Int {
+	return new(big.Int).Exp(big.NewInt(10), big.NewInt(int64(n)), nil)
+}
+
+// formatUnits 将最小单位数量按精度格式化为精确的十进制字符串
+func formatUnits(amount *big.Int, decimals uint8) string {
+	if amount == nil {
+		return "0"
+	}
+
+	negative := amount.Sign() < 0
+	digits := new(big.Int).Abs(amount).String()
+	if decimals > 0 {
+		if len(digits) <= int(decimals) {
+			digits = strings.Repeat("0", int(decimals)-len(digits)+1) + digits
+		}
+		point := len(digits) - int(decimals)
+		fraction := strings.TrimRight(digits[point:], "0")
+		digits = digits[:point]
+		if fraction != "" {
+			digits += "." + fraction
+		}
+	}
+
+	if negative {
+		return "-" + digits
+	}
+	return digits
+}
+
+// parseUnits 将十进制字符串按精度转换为最小单位数量
+func parseUnits(value string, decimals uint8) (*big.Int, error) {
+	value = strings.TrimSpace(value)
+	if value == "" {
+		return nil, fmt.Errorf("empty amount")
+	}
+
+	negative := strings.HasPrefix(value, "-")
+	value = strings.TrimPrefix(value, "-")
+
+	whole, fraction, _ := strings.Cut(value, ".")
+	if len(fraction) > int(decimals) {
+		return nil, fmt.Errorf("amount %s has more than %d decimal places", value, decimals)
+	}
+	fraction += strings.Repeat("0", int(decimals)-len(fraction))
+	if whole == "" {
+		whole = "0"
+	}
+
+	amount, ok := new(big.Int).SetString(whole+fraction, 10)
+	if !ok {
+		return nil, fmt.Errorf("invalid amount: %s", value)
+	}
+	if negative {
+		amount.Neg(amount)
+	}
+	return amount, nil
+}
+
+// formatRat 将有理数格式化为最多precision位小数的十进制字符串
+func formatRat(value *big.Rat, precision int) string {
+	formatted := value.FloatString(precision)
+	if strings.Contains(formatted, ".") {
+		formatted = strings.TrimRight(strings.TrimRight(formatted, "0"), ".")
+	}
+	return formatted
+}
@@ -0,0 +1,49 @@
+package services
+
+import (
+	"math/big"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestFormatUnits(t *testing.T) {
+	tests := []struct {
+		amount   string
+		decimals uint8
+		expected string
+	}{
+		{"0", 18, "0"},
+		{"1000000000000000000", 18, "1"},
+		{"1500000", 6, "1.5"},
+		{"1", 9, "0.000000001"},
+		{"123456789", 0, "123456789"},
+		{"-2500000000", 9, "-2.5"},
+	}
+
+	for _, tt := range tests {
+		amount, _ := new(big.Int).SetString(tt.amount, 10)
+		assert.Equal(t, tt.expected, formatUnits(amount, tt.decimals))
+	}
+}
+
+func TestParseUnits(t *testing.T) {
+	amount, err := parseUnits("1.5", 6)
+	require.NoError(t, err)
+	assert.Equal(t, "1500000", amount.String())
+
+	amount, err = parseUnits(".000000001", 9)
+	require.NoError(t, err)
+	assert.Equal(t, "1", amount.String())
+
+	amount, err = parseUnits("42", 18)
+	require.NoError(t, err)
+	assert.Equal(t, "42000000000000000000", amount.String())
+
+	_, err = parseUnits("0.0000001", 6)
+	assert.Error(t, err)
+
+	_, err = parseUnits("abc", 18)
+	assert.Error(t, err)
+}